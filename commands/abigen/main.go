@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -83,6 +84,10 @@ var (
 		Name:  "alias",
 		Usage: "Comma separated aliases for function and event renaming, e.g. original1=alias1, original2=alias2",
 	}
+	wasmFlag = cli.BoolFlag{
+		Name:  "wasm",
+		Usage: "Bind a WASM contract: --abi is the WASM contract ABI json, --bin the compiled WASM module",
+	}
 )
 
 func init() {
@@ -101,6 +106,7 @@ func init() {
 		outFlag,
 		langFlag,
 		aliasFlag,
+		wasmFlag,
 	}
 	app.Action = utils.MigrateFlags(abigen)
 	cli.CommandHelpTemplate = flags.OriginCommandHelpTemplate
@@ -111,6 +117,9 @@ func abigen(c *cli.Context) error {
 	if c.GlobalString(pkgFlag.Name) == "" {
 		utils.Fatalf("No destination package specified (--pkg)")
 	}
+	if c.GlobalBool(wasmFlag.Name) {
+		return abigenWasm(c)
+	}
 	var lang bind.Lang
 	switch c.GlobalString(langFlag.Name) {
 	case "go":
@@ -254,6 +263,62 @@ func abigen(c *cli.Context) error {
 	return nil
 }
 
+// abigenWasm generates the Go binding of a WASM contract.
+func abigenWasm(c *cli.Context) error {
+	if c.GlobalString(abiFlag.Name) == "" {
+		utils.Fatalf("WASM bindings require a contract ABI (--abi)")
+	}
+	if c.GlobalString(langFlag.Name) != "go" {
+		utils.Fatalf("WASM bindings are only supported for Go (--lang)")
+	}
+	var (
+		abi []byte
+		err error
+	)
+	input := c.GlobalString(abiFlag.Name)
+	if input == "-" {
+		abi, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		abi, err = ioutil.ReadFile(input)
+	}
+	if err != nil {
+		utils.Fatalf("Failed to read input ABI: %v", err)
+	}
+	// The WASM module is binary, embed it hex encoded
+	var bin string
+	if binFile := c.GlobalString(binFlag.Name); binFile != "" {
+		code, err := ioutil.ReadFile(binFile)
+		if err != nil {
+			utils.Fatalf("Failed to read input WASM module: %v", err)
+		}
+		bin = hex.EncodeToString(code)
+	}
+	kind := c.GlobalString(typeFlag.Name)
+	if kind == "" {
+		kind = c.GlobalString(pkgFlag.Name)
+	}
+	aliases := make(map[string]string)
+	if c.GlobalIsSet(aliasFlag.Name) {
+		re := regexp.MustCompile(`(?:(\w+)[:=](\w+))`)
+		submatches := re.FindAllStringSubmatch(c.GlobalString(aliasFlag.Name), -1)
+		for _, match := range submatches {
+			aliases[match[1]] = match[2]
+		}
+	}
+	code, err := bind.BindWasm([]string{kind}, []string{string(abi)}, []string{bin}, c.GlobalString(pkgFlag.Name), aliases)
+	if err != nil {
+		utils.Fatalf("Failed to generate ABI binding: %v", err)
+	}
+	if !c.GlobalIsSet(outFlag.Name) {
+		fmt.Printf("%s\n", code)
+		return nil
+	}
+	if err := ioutil.WriteFile(c.GlobalString(outFlag.Name), []byte(code), 0600); err != nil {
+		utils.Fatalf("Failed to write ABI binding: %v", err)
+	}
+	return nil
+}
+
 func main() {
 	log.Root().SetHandler(log.LvlFilterHandler(log.LvlInfo, log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
 
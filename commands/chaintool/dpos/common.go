@@ -3,8 +3,13 @@ package dpos
 import (
 	phoenixchain "github.com/PhoenixGlobal/Phoenix-Chain-SDK"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/bind"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/dposclient"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/ethclient"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/node"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
@@ -18,11 +23,14 @@ import (
 	"errors"
 	"fmt"
 	"gopkg.in/urfave/cli.v1"
-	"io"
 	"io/ioutil"
-	"os"
+	"math/big"
+	"time"
 )
 
+// txWaitTimeout is how long the write commands wait for their transaction to be mined.
+const txWaitTimeout = 2 * time.Minute
+
 func CallDPosContract(client *ethclient.Client, funcType uint16, params ...interface{}) ([]byte, error) {
 	send, to := EncodeDPOS(funcType, params...)
 	var msg phoenixchain.CallMsg
@@ -205,9 +213,9 @@ func query(c *cli.Context, funcType uint16, params ...interface{}) error {
 
 func getBlsProof(keyfilepath string) (bls.SchnorrProofHex,error) {
 	var proofHex bls.SchnorrProofHex
-	blsKey,err:=bls.LoadBLS(keyfilepath)
+	blsKey,err:=keystore.LoadBLSKey(keyfilepath, getKeyPassphrase(keyfilepath))
 	if err != nil {
-		return proofHex,fmt.Errorf("bls.LoadBLS error,%s", err.Error())
+		return proofHex,fmt.Errorf("load bls key error,%s", err.Error())
 	}
 	proof, _ := blsKey.MakeSchnorrNIZKP()
 	proofByte, _ := proof.MarshalText()
@@ -215,17 +223,20 @@ func getBlsProof(keyfilepath string) (bls.SchnorrProofHex,error) {
 	return proofHex,nil
 }
 
-func GetNodeKey(file string) (string, error)  {
-	buf := make([]byte, 64)
-	fd, err := os.Open(file)
-	if err != nil {
-		return "", err
-	}
-	defer fd.Close()
-	if _, err := io.ReadFull(fd, buf); err != nil {
-		return "", err
+// GetNodeKey loads the node key of a file, either a plain hex one or one
+// encrypted with a passphrase, which is prompted for.
+func GetNodeKey(file string) (*ecdsa.PrivateKey, error) {
+	return keystore.LoadNodeKey(file, getKeyPassphrase(file))
+}
+
+// getKeyPassphrase prompts for the passphrase of a node or BLS key file if it's
+// encrypted, plain key files need none.
+func getKeyPassphrase(file string) string {
+	content, err := ioutil.ReadFile(file)
+	if err != nil || !keystore.IsEncryptedNodeKey(content) {
+		return ""
 	}
-	return string(buf),nil
+	return getPassphrase(false)
 }
 
 // promptPassphrase prompts the user for a passphrase.  Set confirmation to true
@@ -270,4 +281,130 @@ func getPrivateKey(keystorePath string) (*ecdsa.PrivateKey,string){
 		utils.Fatalf("Error decrypting key: %v", err)
 	}
 	return key.PrivateKey,key.Address.String()
-}
\ No newline at end of file
+}
+
+// sendDPosTx signs a PoS contract transaction with the account of the keystore file,
+// sends it to the rpc node and prints the contract result recorded in the receipt.
+func sendDPosTx(c *cli.Context, send func(*dposclient.Client, *bind.TransactOpts) (*types.Transaction, error)) (*dposclient.TxResult, error) {
+	url := c.String(rpcUrlFlag.Name)
+	if url == "" {
+		return nil, errors.New("rpc url not set")
+	}
+	keystorePath := c.String(keystoreFlag.Name)
+	if keystorePath == "" {
+		return nil, errors.New("keystore file not set")
+	}
+	priKey, from := getPrivateKey(keystorePath)
+
+	client, err := dposclient.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	tx, err := send(client, bind.NewKeyedTransactor(priKey))
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("send transaction success,from:", from, "txHash:", tx.Hash().Hex())
+
+	ctx, cancel := context.WithTimeout(context.Background(), txWaitTimeout)
+	defer cancel()
+	res, err := client.WaitTxResult(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the transaction result: %v", err)
+	}
+	out, err := formatTxResult(res)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println(string(out))
+	if err := res.Err(); err != nil {
+		return res, fmt.Errorf("transaction failed,code:%d,msg:%s", res.Code, err.Error())
+	}
+	return res, nil
+}
+
+// formatTxResult renders the contract result of a transaction like the results
+// of the queries, with the message of its code and the data it returned.
+func formatTxResult(res *dposclient.TxResult) ([]byte, error) {
+	data, err := decodeTxData(res)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		TxHash   common.Hash
+		Contract common.Address
+		Code     uint32
+		Ret      string
+		Data     interface{} `json:",omitempty"`
+	}{
+		TxHash:   res.TxHash,
+		Contract: res.Contract,
+		Code:     res.Code,
+		Ret:      dposclient.LookupError(res.Code).Msg,
+		Data:     data,
+	})
+}
+
+// decodeTxData decodes the value returned along with the result code by the
+// contract, falling back to the raw bytes for the contracts returning none.
+func decodeTxData(res *dposclient.TxResult) (interface{}, error) {
+	if len(res.Data) == 0 || res.Err() != nil {
+		return nil, nil
+	}
+	switch res.Contract {
+	case vm.DelegateRewardPoolAddr:
+		rewards, err := dposclient.DecodeWithdrawDelegateReward(res)
+		if err != nil {
+			return nil, fmt.Errorf("invalid withdrawDelegateReward result: %v", err)
+		}
+		return rewards, nil
+	}
+	return hexutil.Bytes(res.Data), nil
+}
+
+// signProgramVersion signs the program version with the node key, proving that
+// the node itself runs this version.
+func signProgramVersion(nodeKeyPath string, programVersion uint32) (common.VersionSign, error) {
+	var versionSign common.VersionSign
+	privateKey, err := GetNodeKey(nodeKeyPath)
+	if err != nil {
+		return versionSign, fmt.Errorf("getNodeKey error: %v", err)
+	}
+	node.GetCryptoHandler().SetPrivateKey(privateKey)
+	sign, err := node.GetCryptoHandler().Sign(programVersion)
+	if err != nil {
+		return versionSign, err
+	}
+	versionSign.SetBytes(sign)
+	return versionSign, nil
+}
+
+func getNodeID(c *cli.Context, flag cli.StringFlag) (discover.NodeID, error) {
+	nodeIDstring := c.String(flag.Name)
+	if nodeIDstring == "" {
+		return discover.NodeID{}, fmt.Errorf("param %s not set", flag.Name)
+	}
+	return discover.HexID(nodeIDstring)
+}
+
+func getAmount(c *cli.Context) (*big.Int, error) {
+	amountString := c.String(amountFlag.Name)
+	if amountString == "" {
+		return nil, errors.New("param amount not set")
+	}
+	amount, ok := new(big.Int).SetString(amountString, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount: %s", amountString)
+	}
+	return amount, nil
+}
+
+func getAmountType(c *cli.Context) (dposclient.AmountType, error) {
+	typ := dposclient.AmountType(c.Uint64(amountTypeFlag.Name))
+	if typ != dposclient.FreeAmount && typ != dposclient.RestrictingAmount {
+		return 0, fmt.Errorf("invalid amount type: %d", typ)
+	}
+	return typ, nil
+}
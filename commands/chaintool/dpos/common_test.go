@@ -0,0 +1,57 @@
+package dpos
+
+import (
+	"flag"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/dposclient"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/reward"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/staking"
+)
+
+func TestFormatTxResult(t *testing.T) {
+	data, err := rlp.EncodeToBytes([]reward.NodeDelegateReward{{StakingNum: 10, Reward: big.NewInt(100)}})
+	assert.Nil(t, err)
+	res := &dposclient.TxResult{
+		TxHash:   common.HexToHash("0x01"),
+		Contract: vm.DelegateRewardPoolAddr,
+		Data:     data,
+	}
+	out, err := formatTxResult(res)
+	assert.Nil(t, err)
+	assert.Contains(t, string(out), `"Code":0,"Ret":"ok","Data":[{"nodeID":`)
+	assert.Contains(t, string(out), `"stakingNum":10,"reward":100}]}`)
+
+	res.Data = []byte{0xc1, 0x80}
+	_, err = formatTxResult(res)
+	assert.NotNil(t, err)
+
+	res.Contract = vm.StakingContractAddr
+	out, err = formatTxResult(res)
+	assert.Nil(t, err)
+	assert.Contains(t, string(out), `"Code":0,"Ret":"ok","Data":"0xc180"`)
+
+	res.Code, res.Data = staking.ErrCanAlreadyExist.Code, nil
+	out, err = formatTxResult(res)
+	assert.Nil(t, err)
+	assert.Contains(t, string(out), `"Code":301101,"Ret":"`+staking.ErrCanAlreadyExist.Msg+`"}`)
+}
+
+func TestEditCandidateRewardPerRange(t *testing.T) {
+	set := flag.NewFlagSet("editCandidate", flag.ContinueOnError)
+	set.String(nodeIdFlag.Name, "", "")
+	set.Uint64(rewardPerFlag.Name, 0, "")
+	nodeID := "e0fbf44d916ee125098327e378a3b5f23b421c9636b7662e8fec1d0c8ab4c7addcbeae865c005734f8451873eef11f64f361bdac3c57ad2143e892af24127769"
+	assert.Nil(t, set.Parse([]string{"--" + nodeIdFlag.Name, nodeID, "--" + rewardPerFlag.Name, "70000"}))
+
+	// The value would wrap to 4464 as an uint16, it must not reach the contract.
+	err := editCandidate(cli.NewContext(nil, set, nil))
+	assert.EqualError(t, err, "invalid rewardPer 70000, must be at most 10000 basis points")
+}
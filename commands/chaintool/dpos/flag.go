@@ -30,12 +30,12 @@ var (
 
 	nodeKeyFlag = cli.StringFlag{
 		Name:  "nodeKey",
-		Usage: "nodeKey file path",
+		Usage: "nodeKey file path, plain or encrypted",
 	}
 
 	blsKeyfileFlag = cli.StringFlag{
 		Name:  "blsKey",
-		Usage: "file containing the blsKey",
+		Usage: "file containing the blsKey, plain or encrypted",
 	}
 
 	stakingParamsFlag = cli.StringFlag{
@@ -47,4 +47,19 @@ var (
 		Name:  "govParams",
 		Usage: "gov file path",
 	}
+
+	amountFlag = cli.StringFlag{
+		Name:  "amount",
+		Usage: "amount in von",
+	}
+
+	amountTypeFlag = cli.Uint64Flag{
+		Name:  "amountType",
+		Usage: "the balance the amount is paid from,0: free balance,1: restricting balance",
+	}
+
+	programVersionFlag = cli.Uint64Flag{
+		Name:  "programVersion",
+		Usage: "the program version of the node, signed with the nodeKey",
+	}
 )
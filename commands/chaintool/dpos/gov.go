@@ -2,6 +2,9 @@ package dpos
 
 import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/chaintool/core"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/bind"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/dposclient"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
 	"encoding/json"
 	"errors"
@@ -11,6 +14,7 @@ import (
 	"os"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/gov"
 )
 
 // submitText
@@ -25,6 +29,12 @@ var (
 		Usage: "use for gov func",
 		Subcommands: []cli.Command{
 			SubmitTextCmd,
+			submitVersionCmd,
+			submitParamCmd,
+			voteCmd,
+			declareVersionCmd,
+			submitCancelCmd,
+			submitTreasuryCmd,
 			getProposalCmd,
 			getTallyResultCmd,
 			listProposalCmd,
@@ -32,6 +42,7 @@ var (
 			getGovernParamValueCmd,
 			getAccuVerifiersCountCmd,
 			listGovernParamCmd,
+			getTreasuryBalanceCmd,
 		},
 	}
 	SubmitTextCmd = cli.Command{
@@ -41,6 +52,48 @@ var (
 		Action: submitText,
 		Flags:  []cli.Flag{configPathFlag,keystoreFlag,govParamsFlag},
 	}
+	submitVersionCmd = cli.Command{
+		Name:   "submitVersion",
+		Usage:  "2001,submit version proposal,parameter:verifier,pipID,newVersion,endVotingRounds",
+		Before: netCheck,
+		Action: submitVersion,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag, verifierFlag, pipIDFlag, newVersionFlag, endVotingRoundsFlag},
+	}
+	submitParamCmd = cli.Command{
+		Name:   "submitParam",
+		Usage:  "2002,submit param proposal,parameter:verifier,pipID,module,name,newValue",
+		Before: netCheck,
+		Action: submitParam,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag, verifierFlag, pipIDFlag, moduleFlag, nameFlag, newValueFlag},
+	}
+	voteCmd = cli.Command{
+		Name:   "vote",
+		Usage:  "2003,vote for proposal,parameter:verifier,proposalID,option,programVersion,nodeKey",
+		Before: netCheck,
+		Action: vote,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag, nodeKeyFlag, verifierFlag, proposalIDFlag, voteOptionFlag, programVersionFlag},
+	}
+	declareVersionCmd = cli.Command{
+		Name:   "declareVersion",
+		Usage:  "2004,declare the program version of the node,parameter:verifier,programVersion,nodeKey",
+		Before: netCheck,
+		Action: declareVersion,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag, nodeKeyFlag, verifierFlag, programVersionFlag},
+	}
+	submitCancelCmd = cli.Command{
+		Name:   "submitCancel",
+		Usage:  "2005,submit cancel proposal,parameter:verifier,pipID,endVotingRounds,tobeCanceled",
+		Before: netCheck,
+		Action: submitCancel,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag, verifierFlag, pipIDFlag, endVotingRoundsFlag, tobeCanceledFlag},
+	}
+	submitTreasuryCmd = cli.Command{
+		Name:   "submitTreasury",
+		Usage:  "2006,submit treasury proposal,parameter:verifier,pipID,recipient,tranches",
+		Before: netCheck,
+		Action: submitTreasury,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag, verifierFlag, pipIDFlag, recipientFlag, tranchesFlag},
+	}
 	getProposalCmd = cli.Command{
 		Name:   "getProposal",
 		Usage:  "2100,get proposal,parameter:proposalID",
@@ -90,6 +143,13 @@ var (
 		Action: listGovernParam,
 		Flags:  []cli.Flag{rpcUrlFlag, addressHRPFlag, moduleFlag, jsonFlag},
 	}
+	getTreasuryBalanceCmd = cli.Command{
+		Name:   "getTreasuryBalance",
+		Usage:  "2107,query the balance of the community treasury",
+		Before: netCheck,
+		Action: getTreasuryBalance,
+		Flags:  []cli.Flag{rpcUrlFlag, addressHRPFlag, jsonFlag},
+	}
 	proposalIDFlag = cli.StringFlag{
 		Name:  "proposalID",
 		Usage: "proposalID",
@@ -106,6 +166,42 @@ var (
 		Name:  "blockHash",
 		Usage: "blockHash",
 	}
+	verifierFlag = cli.StringFlag{
+		Name:  "verifier",
+		Usage: "node id of the verifier submitting or voting",
+	}
+	pipIDFlag = cli.StringFlag{
+		Name:  "pipID",
+		Usage: "pipID",
+	}
+	newVersionFlag = cli.Uint64Flag{
+		Name:  "newVersion",
+		Usage: "the version to upgrade to",
+	}
+	endVotingRoundsFlag = cli.Uint64Flag{
+		Name:  "endVotingRounds",
+		Usage: "the number of consensus rounds the voting lasts",
+	}
+	newValueFlag = cli.StringFlag{
+		Name:  "newValue",
+		Usage: "the new value of the governance parameter",
+	}
+	tobeCanceledFlag = cli.StringFlag{
+		Name:  "tobeCanceled",
+		Usage: "the id of the proposal to cancel",
+	}
+	recipientFlag = cli.StringFlag{
+		Name:  "recipient",
+		Usage: "the account paid by the treasury proposal",
+	}
+	tranchesFlag = cli.StringFlag{
+		Name:  "tranches",
+		Usage: "tranches file path, a json list of {\"epoch\":..,\"amount\":..} released after the proposal passes",
+	}
+	voteOptionFlag = cli.Uint64Flag{
+		Name:  "option",
+		Usage: "vote option,1: yes,2: no,3: abstention",
+	}
 )
 
 func getProposal(c *cli.Context) error {
@@ -163,6 +259,150 @@ func submitText(c *cli.Context) error {
 	return nil
 }
 
+func submitVersion(c *cli.Context) error {
+	verifier, err := getNodeID(c, verifierFlag)
+	if err != nil {
+		return err
+	}
+	pipID := c.String(pipIDFlag.Name)
+	if pipID == "" {
+		return errors.New("param pipID not set")
+	}
+	newVersion := uint32(c.Uint64(newVersionFlag.Name))
+	if newVersion == 0 {
+		return errors.New("param newVersion not set")
+	}
+	endVotingRounds := c.Uint64(endVotingRoundsFlag.Name)
+	if endVotingRounds == 0 {
+		return errors.New("param endVotingRounds not set")
+	}
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.SubmitVersion(opts, verifier, pipID, newVersion, endVotingRounds)
+	})
+	return err
+}
+
+func submitParam(c *cli.Context) error {
+	verifier, err := getNodeID(c, verifierFlag)
+	if err != nil {
+		return err
+	}
+	pipID := c.String(pipIDFlag.Name)
+	if pipID == "" {
+		return errors.New("param pipID not set")
+	}
+	module := c.String(moduleFlag.Name)
+	if module == "" {
+		return errors.New("param module not set")
+	}
+	name := c.String(nameFlag.Name)
+	if name == "" {
+		return errors.New("param name not set")
+	}
+	if !c.IsSet(newValueFlag.Name) {
+		return errors.New("param newValue not set")
+	}
+	newValue := c.String(newValueFlag.Name)
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.SubmitParam(opts, verifier, pipID, module, name, newValue)
+	})
+	return err
+}
+
+func submitCancel(c *cli.Context) error {
+	verifier, err := getNodeID(c, verifierFlag)
+	if err != nil {
+		return err
+	}
+	pipID := c.String(pipIDFlag.Name)
+	if pipID == "" {
+		return errors.New("param pipID not set")
+	}
+	endVotingRounds := c.Uint64(endVotingRoundsFlag.Name)
+	if endVotingRounds == 0 {
+		return errors.New("param endVotingRounds not set")
+	}
+	tobeCanceled := c.String(tobeCanceledFlag.Name)
+	if tobeCanceled == "" {
+		return errors.New("param tobeCanceled not set")
+	}
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.SubmitCancel(opts, verifier, pipID, endVotingRounds, common.HexToHash(tobeCanceled))
+	})
+	return err
+}
+
+func submitTreasury(c *cli.Context) error {
+	verifier, err := getNodeID(c, verifierFlag)
+	if err != nil {
+		return err
+	}
+	pipID := c.String(pipIDFlag.Name)
+	if pipID == "" {
+		return errors.New("param pipID not set")
+	}
+	recipientString := c.String(recipientFlag.Name)
+	if recipientString == "" {
+		return errors.New("param recipient not set")
+	}
+	recipient, err := common.StringToAddress(recipientString)
+	if err != nil {
+		return err
+	}
+	tranchesPath := c.String(tranchesFlag.Name)
+	if tranchesPath == "" {
+		return errors.New("param tranches not set")
+	}
+	tranches, err := loadRestrictingPlans(tranchesPath)
+	if err != nil {
+		return err
+	}
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.SubmitTreasury(opts, verifier, pipID, recipient, tranches)
+	})
+	return err
+}
+
+func vote(c *cli.Context) error {
+	verifier, err := getNodeID(c, verifierFlag)
+	if err != nil {
+		return err
+	}
+	proposalID := c.String(proposalIDFlag.Name)
+	if proposalID == "" {
+		return errors.New("param proposalID not set")
+	}
+	option := gov.VoteOption(c.Uint64(voteOptionFlag.Name))
+	if option != gov.Yes && option != gov.No && option != gov.Abstention {
+		return fmt.Errorf("invalid vote option: %d", option)
+	}
+	programVersion := uint32(c.Uint64(programVersionFlag.Name))
+	versionSign, err := signProgramVersion(c.String(nodeKeyFlag.Name), programVersion)
+	if err != nil {
+		return err
+	}
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.Vote(opts, verifier, common.HexToHash(proposalID), option, programVersion, versionSign)
+	})
+	return err
+}
+
+func declareVersion(c *cli.Context) error {
+	verifier, err := getNodeID(c, verifierFlag)
+	if err != nil {
+		return err
+	}
+	programVersion := uint32(c.Uint64(programVersionFlag.Name))
+	versionSign, err := signProgramVersion(c.String(nodeKeyFlag.Name), programVersion)
+	if err != nil {
+		return err
+	}
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.DeclareVersion(opts, verifier, programVersion, versionSign)
+	})
+	return err
+}
+
 func getActiveVersion(c *cli.Context) error {
 	return query(c, 2103)
 }
@@ -195,3 +435,7 @@ func listGovernParam(c *cli.Context) error {
 	module := c.String(moduleFlag.Name)
 	return query(c, 2106, module)
 }
+
+func getTreasuryBalance(c *cli.Context) error {
+	return query(c, 2107)
+}
@@ -1,11 +1,18 @@
 package dpos
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io/ioutil"
 
 	"gopkg.in/urfave/cli.v1"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/bind"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/dposclient"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/restricting"
 )
 
 var (
@@ -13,9 +20,17 @@ var (
 		Name:  "restricting",
 		Usage: "use for restricting",
 		Subcommands: []cli.Command{
+			createRestrictingPlanCmd,
 			getRestrictingInfoCmd,
 		},
 	}
+	createRestrictingPlanCmd = cli.Command{
+		Name:   "createRestrictingPlan",
+		Usage:  "4000,create restricting plan,parameter:address,plans",
+		Before: netCheck,
+		Action: createRestrictingPlan,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag, addFlag, plansFlag},
+	}
 	getRestrictingInfoCmd = cli.Command{
 		Name:   "getRestrictingInfo",
 		Usage:  "4100,get restricting info,parameter:address",
@@ -23,8 +38,51 @@ var (
 		Action: getRestrictingInfo,
 		Flags:  []cli.Flag{rpcUrlFlag, addressHRPFlag, addFlag, jsonFlag},
 	}
+	plansFlag = cli.StringFlag{
+		Name:  "plans",
+		Usage: "restricting plans file path, a json list of {\"epoch\":..,\"amount\":..}",
+	}
 )
 
+func createRestrictingPlan(c *cli.Context) error {
+	addstring := c.String(addFlag.Name)
+	if addstring == "" {
+		return errors.New("The locked position release to the account account is not set")
+	}
+	account, err := common.StringToAddress(addstring)
+	if err != nil {
+		return err
+	}
+	plansPath := c.String(plansFlag.Name)
+	if plansPath == "" {
+		return errors.New("param plans not set")
+	}
+	plans, err := loadRestrictingPlans(plansPath)
+	if err != nil {
+		return err
+	}
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.CreateRestrictingPlan(opts, account, plans)
+	})
+	return err
+}
+
+// loadRestrictingPlans reads a json list of restricting plans from a file.
+func loadRestrictingPlans(path string) ([]restricting.RestrictingPlan, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read plans file: %v", err)
+	}
+	var plans []restricting.RestrictingPlan
+	if err := json.Unmarshal(data, &plans); err != nil {
+		return nil, fmt.Errorf("parse plans to json error,%s", err.Error())
+	}
+	if len(plans) == 0 {
+		return nil, errors.New("no restricting plan in plans file")
+	}
+	return plans, nil
+}
+
 func getRestrictingInfo(c *cli.Context) error {
 	addstring := c.String(addFlag.Name)
 	if addstring == "" {
@@ -3,6 +3,9 @@ package dpos
 import (
 	"gopkg.in/urfave/cli.v1"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/bind"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/dposclient"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
 )
 
@@ -11,9 +14,17 @@ var (
 		Name:  "reward",
 		Usage: "use for reward",
 		Subcommands: []cli.Command{
+			withdrawDelegateRewardCmd,
 			getDelegateRewardCmd,
 		},
 	}
+	withdrawDelegateRewardCmd = cli.Command{
+		Name:   "withdrawDelegateReward",
+		Usage:  "5000,withdraw the commission rewards of the account from all the nodes it delegated to",
+		Before: netCheck,
+		Action: withdrawDelegateReward,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag},
+	}
 	getDelegateRewardCmd = cli.Command{
 		Name:   "getDelegateReward",
 		Usage:  "5100,query account not withdrawn commission rewards at each node,parameter:nodeList(can empty)",
@@ -27,6 +38,13 @@ var (
 	}
 )
 
+func withdrawDelegateReward(c *cli.Context) error {
+	_, err := sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.WithdrawDelegateReward(opts)
+	})
+	return err
+}
+
 func getDelegateReward(c *cli.Context) error {
 	nodeIDlist := c.StringSlice(nodeList.Name)
 	idlist := make([]discover.NodeID, 0)
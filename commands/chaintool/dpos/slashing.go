@@ -2,10 +2,17 @@ package dpos
 
 import (
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
 
 	"gopkg.in/urfave/cli.v1"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/bind"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/dposclient"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/consensus"
 )
 
 var (
@@ -13,20 +20,25 @@ var (
 		Name:  "slashing",
 		Usage: "use for slashing",
 		Subcommands: []cli.Command{
+			reportDuplicateSignCmd,
 			checkDuplicateSignCmd,
 			zeroProduceNodeListCmd,
 		},
 	}
+	reportDuplicateSignCmd = cli.Command{
+		Name:   "reportDuplicateSign",
+		Usage:  "3000,report the node for duplicate signing,parameter:duplicateSignType,evidence",
+		Before: netCheck,
+		Action: reportDuplicateSign,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag, duplicateSignTypeFlag, evidenceFlag},
+	}
 	checkDuplicateSignCmd = cli.Command{
 		Name:   "checkDuplicateSign",
 		Usage:  "3001,query whether the node has been reported for too many signatures,parameter:duplicateSignType,nodeid,blockNum",
 		Before: netCheck,
 		Action: checkDuplicateSign,
 		Flags: []cli.Flag{rpcUrlFlag, addressHRPFlag,
-			cli.Uint64Flag{
-				Name:  "duplicateSignType",
-				Usage: "duplicateSign type,1：prepareBlock，2：prepareVote，3：viewChange",
-			},
+			duplicateSignTypeFlag,
 			nodeIdFlag,
 			blockNumFlag, jsonFlag,
 		},
@@ -42,10 +54,37 @@ var (
 		Name:  "blockNum",
 		Usage: "blockNum",
 	}
+	duplicateSignTypeFlag = cli.Uint64Flag{
+		Name:  "duplicateSignType",
+		Usage: "duplicateSign type,1：prepareBlock，2：prepareVote，3：viewChange",
+	}
+	evidenceFlag = cli.StringFlag{
+		Name:  "evidence",
+		Usage: "evidence file path, the json evidence of the duplicate signing",
+	}
 )
 
+func reportDuplicateSign(c *cli.Context) error {
+	duplicateSignType := consensus.EvidenceType(c.Uint64(duplicateSignTypeFlag.Name))
+	if duplicateSignType == 0 {
+		return errors.New("param duplicateSignType not set")
+	}
+	evidencePath := c.String(evidenceFlag.Name)
+	if evidencePath == "" {
+		return errors.New("param evidence not set")
+	}
+	evidence, err := ioutil.ReadFile(evidencePath)
+	if err != nil {
+		return fmt.Errorf("Failed to read evidence file: %v", err)
+	}
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.ReportDuplicateSign(opts, duplicateSignType, strings.TrimSpace(string(evidence)))
+	})
+	return err
+}
+
 func checkDuplicateSign(c *cli.Context) error {
-	duplicateSignType := c.Uint64("duplicateSignType")
+	duplicateSignType := c.Uint64(duplicateSignTypeFlag.Name)
 
 	nodeIDstring := c.String(nodeIdFlag.Name)
 	if nodeIDstring == "" {
@@ -2,6 +2,9 @@ package dpos
 
 import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/chaintool/dpos/lib"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/bind"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/dposclient"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/node"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
@@ -35,12 +38,21 @@ type Dpos_1000 struct {
 	BlsProof           bls.SchnorrProofHex
 }
 
+// maxRewardPer is the largest share of the rewards paid to delegators, in
+// basis points, which the staking contract accepts.
+const maxRewardPer = 10000
+
 var (
 	StakingCmd = cli.Command{
 		Name:  "staking",
 		Usage: "use for staking",
 		Subcommands: []cli.Command{
 			CreateStakingCmd,
+			editCandidateCmd,
+			increaseStakingCmd,
+			withdrewStakingCmd,
+			delegateCmd,
+			withdrewDelegationCmd,
 			GetVerifierListCmd,
 			getValidatorListCmd,
 			getCandidateListCmd,
@@ -59,6 +71,42 @@ var (
 		Action: createStaking,
 		Flags:  []cli.Flag{configPathFlag,keystoreFlag,blsKeyfileFlag,nodeKeyFlag,stakingParamsFlag},
 	}
+	editCandidateCmd = cli.Command{
+		Name:   "editCandidate",
+		Usage:  "1001,edit the candidate info,parameter:nodeid,benefitAddress,rewardPer,externalId,nodeName,website,details",
+		Before: netCheck,
+		Action: editCandidate,
+		Flags: []cli.Flag{rpcUrlFlag, keystoreFlag, nodeIdFlag, benefitAddressFlag, rewardPerFlag,
+			externalIdFlag, nodeNameFlag, websiteFlag, detailsFlag},
+	}
+	increaseStakingCmd = cli.Command{
+		Name:   "increaseStaking",
+		Usage:  "1002,increase the staking of the candidate,parameter:nodeid,amountType,amount",
+		Before: netCheck,
+		Action: increaseStaking,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag, nodeIdFlag, amountTypeFlag, amountFlag},
+	}
+	withdrewStakingCmd = cli.Command{
+		Name:   "withdrewStaking",
+		Usage:  "1003,withdraw the staking of the candidate,parameter:nodeid",
+		Before: netCheck,
+		Action: withdrewStaking,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag, nodeIdFlag},
+	}
+	delegateCmd = cli.Command{
+		Name:   "delegate",
+		Usage:  "1004,delegate to the candidate,parameter:nodeid,amountType,amount",
+		Before: netCheck,
+		Action: delegate,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag, nodeIdFlag, amountTypeFlag, amountFlag},
+	}
+	withdrewDelegationCmd = cli.Command{
+		Name:   "withdrewDelegation",
+		Usage:  "1005,withdraw the delegation,parameter:stakingBlock,nodeid,amount",
+		Before: netCheck,
+		Action: withdrewDelegation,
+		Flags:  []cli.Flag{rpcUrlFlag, keystoreFlag, stakingBlockNumFlag, nodeIdFlag, amountFlag},
+	}
 	GetVerifierListCmd = cli.Command{
 		Name:   "getVerifierList",
 		Usage:  "1100,query the validator queue of the current settlement epoch",
@@ -134,6 +182,30 @@ var (
 		Name:  "nodeid",
 		Usage: "node id",
 	}
+	benefitAddressFlag = cli.StringFlag{
+		Name:  "benefitAddress",
+		Usage: "the account receiving the block and staking rewards",
+	}
+	rewardPerFlag = cli.Uint64Flag{
+		Name:  "rewardPer",
+		Usage: "the share of the rewards paid to delegators, in basis points",
+	}
+	externalIdFlag = cli.StringFlag{
+		Name:  "externalId",
+		Usage: "external id of the node",
+	}
+	nodeNameFlag = cli.StringFlag{
+		Name:  "nodeName",
+		Usage: "name of the node",
+	}
+	websiteFlag = cli.StringFlag{
+		Name:  "website",
+		Usage: "website of the node",
+	}
+	detailsFlag = cli.StringFlag{
+		Name:  "details",
+		Usage: "description of the node",
+	}
 )
 
 func createStaking(c *cli.Context) error {
@@ -172,7 +244,7 @@ func createStaking(c *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("getNodeKey error: %v", err)
 	}
-	node.GetCryptoHandler().SetPrivateKey(crypto.HexMustToECDSA(nodeKey))
+	node.GetCryptoHandler().SetPrivateKey(nodeKey)
 	versionSign := common.VersionSign{}
 	versionSign.SetBytes(node.GetCryptoHandler().MustSign(dpos_1000.ProgramVersion))
 	fmt.Println("versionSign is ",versionSign.String())
@@ -229,6 +301,115 @@ func createStaking(c *cli.Context) error {
 
 }
 
+func editCandidate(c *cli.Context) error {
+	nodeId, err := getNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	params := &dposclient.EditCandidateParams{NodeId: nodeId}
+	if c.IsSet(benefitAddressFlag.Name) {
+		benefitAddress, err := common.StringToAddress(c.String(benefitAddressFlag.Name))
+		if err != nil {
+			return err
+		}
+		params.BenefitAddress = &benefitAddress
+	}
+	if c.IsSet(rewardPerFlag.Name) {
+		value := c.Uint64(rewardPerFlag.Name)
+		if value > maxRewardPer {
+			return fmt.Errorf("invalid rewardPer %d, must be at most %d basis points", value, maxRewardPer)
+		}
+		rewardPer := uint16(value)
+		params.RewardPer = &rewardPer
+	}
+	for _, field := range []struct {
+		flag  cli.StringFlag
+		value **string
+	}{
+		{externalIdFlag, &params.ExternalId},
+		{nodeNameFlag, &params.NodeName},
+		{websiteFlag, &params.Website},
+		{detailsFlag, &params.Details},
+	} {
+		if c.IsSet(field.flag.Name) {
+			value := c.String(field.flag.Name)
+			*field.value = &value
+		}
+	}
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.EditCandidate(opts, params)
+	})
+	return err
+}
+
+func increaseStaking(c *cli.Context) error {
+	nodeId, err := getNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	typ, err := getAmountType(c)
+	if err != nil {
+		return err
+	}
+	amount, err := getAmount(c)
+	if err != nil {
+		return err
+	}
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.IncreaseStaking(opts, nodeId, typ, amount)
+	})
+	return err
+}
+
+func withdrewStaking(c *cli.Context) error {
+	nodeId, err := getNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.WithdrewStaking(opts, nodeId)
+	})
+	return err
+}
+
+func delegate(c *cli.Context) error {
+	nodeId, err := getNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	typ, err := getAmountType(c)
+	if err != nil {
+		return err
+	}
+	amount, err := getAmount(c)
+	if err != nil {
+		return err
+	}
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.Delegate(opts, typ, nodeId, amount)
+	})
+	return err
+}
+
+func withdrewDelegation(c *cli.Context) error {
+	if !c.IsSet(stakingBlockNumFlag.Name) {
+		return errors.New("param stakingBlock not set")
+	}
+	stakingBlockNum := c.Uint64(stakingBlockNumFlag.Name)
+	nodeId, err := getNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	amount, err := getAmount(c)
+	if err != nil {
+		return err
+	}
+	_, err = sendDPosTx(c, func(client *dposclient.Client, opts *bind.TransactOpts) (*types.Transaction, error) {
+		return client.WithdrewDelegation(opts, stakingBlockNum, nodeId, amount)
+	})
+	return err
+}
+
 func getVerifierList(c *cli.Context) error {
 	return query(c, 1100)
 }
@@ -4,23 +4,29 @@ import (
 	"encoding/hex"
 	"fmt"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
 	"gopkg.in/urfave/cli.v1"
 )
 
 type outputGenblskeypair struct {
-	PrivateKey string
+	PrivateKey string `json:",omitempty"`
 	PublicKey  string
 }
 
 var commandGenblskeypair = cli.Command{
 	Name:      "genblskeypair",
 	Usage:     "generate new bls private key pair",
-	ArgsUsage: "[  ]",
+	ArgsUsage: "[ <keyfile> ]",
 	Description: `
 Generate a new bls private key pair.
+
+If a key file is given, the private key is stored in it encrypted with a
+passphrase instead of being printed.
 `,
 	Flags: []cli.Flag{
+		passphraseFlag,
 		jsonFlag,
 	},
 	Action: func(ctx *cli.Context) error {
@@ -32,13 +38,23 @@ Generate a new bls private key pair.
 		privateKey.SetByCSPRNG()
 		pubKey := privateKey.GetPublicKey()
 		out := outputGenblskeypair{
-			PrivateKey: hex.EncodeToString(privateKey.GetLittleEndian()),
-			PublicKey:  hex.EncodeToString(pubKey.Serialize()),
+			PublicKey: hex.EncodeToString(pubKey.Serialize()),
+		}
+		if keyfilepath := ctx.Args().First(); keyfilepath != "" {
+			mustNewKeyFile(keyfilepath)
+			passphrase := getKeyPassphrase(ctx)
+			if err := keystore.StoreBLSKey(keyfilepath, &privateKey, passphrase, keystore.StandardScryptN, keystore.StandardScryptP); err != nil {
+				utils.Fatalf("Failed to write keyfile to %s: %v", keyfilepath, err)
+			}
+		} else {
+			out.PrivateKey = hex.EncodeToString(privateKey.GetLittleEndian())
 		}
 		if ctx.Bool(jsonFlag.Name) {
 			mustPrintJSON(out)
 		} else {
-			fmt.Println("PrivateKey: ", out.PrivateKey)
+			if out.PrivateKey != "" {
+				fmt.Println("PrivateKey: ", out.PrivateKey)
+			}
 			fmt.Println("PublicKey : ", out.PublicKey)
 		}
 		return nil
@@ -8,23 +8,27 @@ import (
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
 )
 
 type outputGenkeypair struct {
-	PrivateKey string
+	PrivateKey string `json:",omitempty"`
 	PublicKey  string
 }
 
 var commandGenkeypair = cli.Command{
 	Name:      "genkeypair",
 	Usage:     "generate new private key pair",
-	ArgsUsage: "[ ]",
+	ArgsUsage: "[ <keyfile> ]",
 	Description: `
 Generate a new private key pair.
-.
+
+If a key file is given, the private key is stored in it encrypted with a
+passphrase instead of being printed, ready to be used as a p2p node key.
 `,
 	Flags: []cli.Flag{
+		passphraseFlag,
 		jsonFlag,
 	},
 	Action: func(ctx *cli.Context) error {
@@ -39,13 +43,23 @@ Generate a new private key pair.
 
 		// Output some information.
 		out := outputGenkeypair{
-			PublicKey:  hex.EncodeToString(crypto.FromECDSAPub(&privateKey.PublicKey)[1:]),
-			PrivateKey: hex.EncodeToString(crypto.FromECDSA(privateKey)),
+			PublicKey: hex.EncodeToString(crypto.FromECDSAPub(&privateKey.PublicKey)[1:]),
+		}
+		if keyfilepath := ctx.Args().First(); keyfilepath != "" {
+			mustNewKeyFile(keyfilepath)
+			passphrase := getKeyPassphrase(ctx)
+			if err := keystore.StoreNodeKey(keyfilepath, privateKey, passphrase, keystore.StandardScryptN, keystore.StandardScryptP); err != nil {
+				utils.Fatalf("Failed to write keyfile to %s: %v", keyfilepath, err)
+			}
+		} else {
+			out.PrivateKey = hex.EncodeToString(crypto.FromECDSA(privateKey))
 		}
 		if ctx.Bool(jsonFlag.Name) {
 			mustPrintJSON(out)
 		} else {
-			fmt.Println("PrivateKey: ", out.PrivateKey)
+			if out.PrivateKey != "" {
+				fmt.Println("PrivateKey: ", out.PrivateKey)
+			}
 			fmt.Println("PublicKey : ", out.PublicKey)
 		}
 		return nil
@@ -26,8 +26,15 @@ func init() {
 		commandSignMessage,
 		commandBlsProof,
 		commandVerifyMessage,
+		commandSignTypedData,
+		commandVerifyTypedData,
 		commandGenkeypair,
 		commandGenblskeypair,
+		commandGenMnemonic,
+		commandRestoreMnemonic,
+		commandDeriveMnemonic,
+		commandMigrateNodeKey,
+		commandMigrateBlsKey,
 		//commandAddressHexToBech32,
 	}
 }
@@ -86,15 +86,23 @@ Generate a bls proof with a keyfile.
 `,
 	Flags: []cli.Flag{
 		blsKeyfileFlag,
+		passphraseFlag,
 		jsonFlag,
 	},
 	Action: func(ctx *cli.Context) error {
-		// Load the keyfile.
+		// Load the keyfile, decrypting it if needed.
 		keyfilepath := ctx.String(blsKeyfileFlag.Name)
-
-		blsKey,err:=bls.LoadBLS(keyfilepath)
+		keyjson, err := ioutil.ReadFile(keyfilepath)
+		if err != nil {
+			utils.Fatalf("Failed to read the keyfile at '%s': %v", keyfilepath, err)
+		}
+		var passphrase string
+		if keystore.IsEncryptedNodeKey(keyjson) {
+			passphrase = getPassphrase(ctx, false)
+		}
+		blsKey,err:=keystore.LoadBLSKey(keyfilepath, passphrase)
 		if err != nil {
-			return fmt.Errorf("bls.LoadBLS error,%s", err.Error())
+			return fmt.Errorf("load bls key error,%s", err.Error())
 		}
 
 		proof, _ := blsKey.MakeSchnorrNIZKP()
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/hdwallet"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/web"
+)
+
+type outputMnemonic struct {
+	Mnemonic   string `json:",omitempty"`
+	WalletFile string
+}
+
+type outputDerive struct {
+	Path    string
+	Address string
+}
+
+var (
+	bitsFlag = cli.IntFlag{
+		Name:  "bits",
+		Usage: "entropy size of the mnemonic, a multiple of 32 in [128, 256]",
+		Value: 256,
+	}
+	mnemonicFileFlag = cli.StringFlag{
+		Name:  "mnemonicfile",
+		Usage: "the file that contains the mnemonic to restore",
+	}
+	derivationPathFlag = cli.StringFlag{
+		Name:  "path",
+		Usage: "derivation path of the first account",
+		Value: accounts.DefaultBaseDerivationPath.String(),
+	}
+	countFlag = cli.IntFlag{
+		Name:  "count",
+		Usage: "number of consecutive accounts to derive",
+		Value: 1,
+	}
+)
+
+var commandGenMnemonic = cli.Command{
+	Name:      "genmnemonic",
+	Usage:     "generate new mnemonic backed HD wallet",
+	ArgsUsage: "[ <walletfile> ]",
+	Description: `
+Generate a new BIP-39 mnemonic and store its seed in an encrypted wallet file.
+
+The mnemonic is printed once and never stored, write it down to be able to
+restore the wallet. If the given path is a directory, such as the keystore of
+a node, the wallet file is created in it under its canonical name.
+`,
+	Flags: []cli.Flag{
+		passphraseFlag,
+		jsonFlag,
+		bitsFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		entropy, err := hdwallet.NewEntropy(ctx.Int(bitsFlag.Name))
+		if err != nil {
+			utils.Fatalf("Failed to generate entropy: %v", err)
+		}
+		mnemonic, err := hdwallet.NewMnemonic(entropy)
+		if err != nil {
+			utils.Fatalf("Failed to create mnemonic: %v", err)
+		}
+		walletfile := writeMnemonicWallet(ctx, mnemonic)
+
+		// Output some information.
+		out := outputMnemonic{
+			Mnemonic:   mnemonic,
+			WalletFile: walletfile,
+		}
+		if ctx.Bool(jsonFlag.Name) {
+			mustPrintJSON(out)
+		} else {
+			fmt.Println("Mnemonic:   ", out.Mnemonic)
+			fmt.Println("Wallet file:", out.WalletFile)
+		}
+		return nil
+	},
+}
+
+var commandRestoreMnemonic = cli.Command{
+	Name:      "restoremnemonic",
+	Usage:     "restore HD wallet from an existing mnemonic",
+	ArgsUsage: "[ <walletfile> ]",
+	Description: `
+Restore a HD wallet from an existing BIP-39 mnemonic, storing its seed in an
+encrypted wallet file.
+
+The mnemonic is prompted for, unless --mnemonicfile points to a file holding
+it. If the given path is a directory, the wallet file is created in it under
+its canonical name.
+`,
+	Flags: []cli.Flag{
+		passphraseFlag,
+		jsonFlag,
+		mnemonicFileFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		var mnemonic string
+		if file := ctx.String(mnemonicFileFlag.Name); file != "" {
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				utils.Fatalf("Failed to read mnemonic file '%s': %v", file, err)
+			}
+			mnemonic = string(content)
+		} else {
+			input, err := web.Stdin.PromptPassword("Mnemonic: ")
+			if err != nil {
+				utils.Fatalf("Failed to read mnemonic: %v", err)
+			}
+			mnemonic = input
+		}
+		mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+		if err := hdwallet.ValidateMnemonic(mnemonic); err != nil {
+			utils.Fatalf("Invalid mnemonic: %v", err)
+		}
+		walletfile := writeMnemonicWallet(ctx, mnemonic)
+
+		// Output some information.
+		out := outputMnemonic{
+			WalletFile: walletfile,
+		}
+		if ctx.Bool(jsonFlag.Name) {
+			mustPrintJSON(out)
+		} else {
+			fmt.Println("Wallet file:", out.WalletFile)
+		}
+		return nil
+	},
+}
+
+var commandDeriveMnemonic = cli.Command{
+	Name:      "derivemnemonic",
+	Usage:     "derive accounts from a HD wallet",
+	ArgsUsage: "<walletfile>",
+	Description: `
+Derive the addresses of a HD wallet.
+
+The first account is derived at --path, any further ones requested by --count
+being derived by incrementing the last component of the path.
+`,
+	Flags: []cli.Flag{
+		passphraseFlag,
+		jsonFlag,
+		derivationPathFlag,
+		countFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		walletfile := ctx.Args().First()
+		if walletfile == "" {
+			utils.Fatalf("No wallet file specified")
+		}
+		path, err := accounts.ParseDerivationPath(ctx.String(derivationPathFlag.Name))
+		if err != nil {
+			utils.Fatalf("Invalid derivation path: %v", err)
+		}
+		count := ctx.Int(countFlag.Name)
+		if count < 1 {
+			utils.Fatalf("Invalid account count: %d", count)
+		}
+		// Read and decrypt the wallet seed.
+		walletjson, err := ioutil.ReadFile(walletfile)
+		if err != nil {
+			utils.Fatalf("Failed to read the wallet file at '%s': %v", walletfile, err)
+		}
+		passphrase := getPassphrase(ctx, false)
+		seed, err := hdwallet.DecryptSeed(walletjson, passphrase)
+		if err != nil {
+			utils.Fatalf("Error decrypting wallet: %v", err)
+		}
+		master, err := hdwallet.NewMasterKey(seed)
+		if err != nil {
+			utils.Fatalf("Failed to create master key: %v", err)
+		}
+		// Derive the common parent once, only the last level differs.
+		parent, err := master.Derive(path[:len(path)-1])
+		if err != nil {
+			utils.Fatalf("Failed to derive parent key: %v", err)
+		}
+		out := make([]outputDerive, 0, count)
+		for i := 0; i < count; i++ {
+			child := make(accounts.DerivationPath, len(path))
+			copy(child, path)
+			child[len(child)-1] += uint32(i)
+
+			key, err := parent.Child(child[len(child)-1])
+			if err != nil {
+				utils.Fatalf("Failed to derive %s: %v", child, err)
+			}
+			privateKey, err := key.PrivateKey()
+			if err != nil {
+				utils.Fatalf("Failed to derive %s: %v", child, err)
+			}
+			out = append(out, outputDerive{
+				Path:    child.String(),
+				Address: crypto.PubkeyToAddress(privateKey.PublicKey).String(),
+			})
+		}
+		if ctx.Bool(jsonFlag.Name) {
+			mustPrintJSON(out)
+		} else {
+			for _, acc := range out {
+				fmt.Printf("%s %s\n", acc.Path, acc.Address)
+			}
+		}
+		return nil
+	},
+}
+
+// writeMnemonicWallet encrypts the seed of a mnemonic with a passphrase given by
+// the user and stores it in the wallet file given as argument, returning its
+// path.
+func writeMnemonicWallet(ctx *cli.Context, mnemonic string) string {
+	walletfile := ctx.Args().First()
+	if walletfile == "" {
+		walletfile = hdwallet.WalletFileName()
+	} else if info, err := os.Stat(walletfile); err == nil && info.IsDir() {
+		walletfile = filepath.Join(walletfile, hdwallet.WalletFileName())
+	}
+	if _, err := os.Stat(walletfile); err == nil {
+		utils.Fatalf("Wallet file already exists at %s.", walletfile)
+	} else if !os.IsNotExist(err) {
+		utils.Fatalf("Error checking if wallet file exists: %v", err)
+	}
+
+	// Encrypt seed with passphrase.
+	passphrase := getPassphrase(ctx, true)
+	walletjson, err := hdwallet.EncryptSeed(hdwallet.NewSeed(mnemonic, ""), passphrase, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		utils.Fatalf("Error encrypting wallet: %v", err)
+	}
+
+	// Store the file to disk.
+	if err := os.MkdirAll(filepath.Dir(walletfile), 0700); err != nil {
+		utils.Fatalf("Could not create directory %s", filepath.Dir(walletfile))
+	}
+	if err := ioutil.WriteFile(walletfile, walletjson, 0600); err != nil {
+		utils.Fatalf("Failed to write wallet file to %s: %v", walletfile, err)
+	}
+	return walletfile
+}
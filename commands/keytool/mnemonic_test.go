@@ -0,0 +1,38 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMnemonicRestoreDerive(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "phoenixkey-test")
+	if err != nil {
+		t.Fatal("Can't create temporary directory:", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	var (
+		walletfile   = filepath.Join(tmpdir, "the-wallet")
+		mnemonicfile = filepath.Join(tmpdir, "the-mnemonic")
+		passfile     = filepath.Join(tmpdir, "the-password")
+	)
+	ioutil.WriteFile(mnemonicfile, []byte(strings.Repeat("abandon ", 11)+"about\n"), 0600)
+	ioutil.WriteFile(passfile, []byte("foobar\n"), 0600)
+
+	// Restore the wallet.
+	restore := runKeytool(t, "restoremnemonic", "--mnemonicfile", mnemonicfile, "--passwordfile", passfile, walletfile)
+	restore.Expect("Wallet file: " + walletfile + "\n")
+	restore.ExpectExit()
+
+	// Derive the first accounts of it.
+	derive := runKeytool(t, "derivemnemonic", "--count", "2", "--passwordfile", passfile, walletfile)
+	derive.Expect(`
+m/44'/60'/0'/0/0 0x9858EfFD232B4033E47d90003D41EC34EcaEda94
+m/44'/60'/0'/0/1 0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0
+`)
+	derive.ExpectExit()
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+)
+
+type outputMigrate struct {
+	KeyFile   string
+	PublicKey string
+}
+
+var commandMigrateNodeKey = cli.Command{
+	Name:      "migratenodekey",
+	Usage:     "encrypt a plain p2p node key file",
+	ArgsUsage: "<keyfile>",
+	Description: `
+Encrypt a plain hex p2p node key file with a passphrase, replacing it in place.
+
+The node decrypts it with the passphrase file given by --keypassword.
+`,
+	Flags: []cli.Flag{
+		passphraseFlag,
+		jsonFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		keyfilepath := mustPlainKeyFile(ctx)
+
+		key, err := crypto.LoadECDSA(keyfilepath)
+		if err != nil {
+			utils.Fatalf("Failed to load the node key at '%s': %v", keyfilepath, err)
+		}
+		passphrase := getKeyPassphrase(ctx)
+		if err := keystore.StoreNodeKey(keyfilepath, key, passphrase, keystore.StandardScryptN, keystore.StandardScryptP); err != nil {
+			utils.Fatalf("Failed to write the encrypted node key: %v", err)
+		}
+		printMigrated(ctx, outputMigrate{
+			KeyFile:   keyfilepath,
+			PublicKey: hex.EncodeToString(crypto.FromECDSAPub(&key.PublicKey)[1:]),
+		})
+		return nil
+	},
+}
+
+var commandMigrateBlsKey = cli.Command{
+	Name:      "migrateblskey",
+	Usage:     "encrypt a plain bls key file",
+	ArgsUsage: "<keyfile>",
+	Description: `
+Encrypt a plain hex BLS key file with a passphrase, replacing it in place.
+
+The node decrypts it with the passphrase file given by --keypassword.
+`,
+	Flags: []cli.Flag{
+		passphraseFlag,
+		jsonFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		keyfilepath := mustPlainKeyFile(ctx)
+
+		if err := bls.Init(int(bls.BLS12_381)); err != nil {
+			return err
+		}
+		key, err := bls.LoadBLS(keyfilepath)
+		if err != nil {
+			utils.Fatalf("Failed to load the bls key at '%s': %v", keyfilepath, err)
+		}
+		passphrase := getKeyPassphrase(ctx)
+		if err := keystore.StoreBLSKey(keyfilepath, key, passphrase, keystore.StandardScryptN, keystore.StandardScryptP); err != nil {
+			utils.Fatalf("Failed to write the encrypted bls key: %v", err)
+		}
+		printMigrated(ctx, outputMigrate{
+			KeyFile:   keyfilepath,
+			PublicKey: hex.EncodeToString(key.GetPublicKey().Serialize()),
+		})
+		return nil
+	},
+}
+
+// mustPlainKeyFile returns the key file given as argument, making sure it's not
+// encrypted yet.
+func mustPlainKeyFile(ctx *cli.Context) string {
+	keyfilepath := ctx.Args().First()
+	if keyfilepath == "" {
+		utils.Fatalf("No key file specified")
+	}
+	content, err := ioutil.ReadFile(keyfilepath)
+	if err != nil {
+		utils.Fatalf("Failed to read the key file at '%s': %v", keyfilepath, err)
+	}
+	if keystore.IsEncryptedNodeKey(content) {
+		utils.Fatalf("Key file at '%s' is already encrypted", keyfilepath)
+	}
+	return keyfilepath
+}
+
+// mustNewKeyFile makes sure a key file doesn't exist yet, so generating a key
+// never overwrites an existing one.
+func mustNewKeyFile(keyfilepath string) {
+	if _, err := os.Stat(keyfilepath); err == nil {
+		utils.Fatalf("Keyfile already exists at %s.", keyfilepath)
+	} else if !os.IsNotExist(err) {
+		utils.Fatalf("Error checking if keyfile exists: %v", err)
+	}
+}
+
+// getKeyPassphrase obtains the passphrase to encrypt a node or BLS key with,
+// which must not be empty as that would store the key in plain hex.
+func getKeyPassphrase(ctx *cli.Context) string {
+	passphrase := getPassphrase(ctx, true)
+	if passphrase == "" {
+		utils.Fatalf("Refusing to store the key without a passphrase")
+	}
+	return passphrase
+}
+
+// printMigrated prints the public key of a newly encrypted key file.
+func printMigrated(ctx *cli.Context, out outputMigrate) {
+	if ctx.Bool(jsonFlag.Name) {
+		mustPrintJSON(out)
+	} else {
+		fmt.Println("Encrypted key file:", out.KeyFile)
+		fmt.Println("PublicKey:         ", out.PublicKey)
+	}
+}
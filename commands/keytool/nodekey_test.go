@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+func TestMigrateNodeKey(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "phoenixkey-test")
+	if err != nil {
+		t.Fatal("Can't create temporary directory:", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	var (
+		keyfile  = filepath.Join(tmpdir, "nodekey")
+		passfile = filepath.Join(tmpdir, "the-password")
+	)
+	key, _ := crypto.GenerateKey()
+	if err := crypto.SaveECDSA(keyfile, key); err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(passfile, []byte("foobar\n"), 0600)
+
+	// Encrypt the plain key in place.
+	migrate := runKeytool(t, "migratenodekey", "--passwordfile", passfile, keyfile)
+	migrate.ExpectRegexp(`Encrypted key file: .*nodekey\nPublicKey: +[0-9a-f]{128}\n`)
+	migrate.ExpectExit()
+
+	content, _ := ioutil.ReadFile(keyfile)
+	if !keystore.IsEncryptedNodeKey(content) {
+		t.Fatalf("key file not encrypted: %s", content)
+	}
+	loaded, err := keystore.LoadNodeKey(keyfile, "foobar")
+	if err != nil {
+		t.Fatalf("failed to load migrated key: %v", err)
+	}
+	if loaded.D.Cmp(key.D) != 0 {
+		t.Fatalf("migrated key mismatch")
+	}
+	// Migrating again must be refused.
+	again := runKeytool(t, "migratenodekey", "--passwordfile", passfile, keyfile)
+	again.WaitExit()
+	if again.ExitStatus() == 0 {
+		t.Errorf("migrating an encrypted key succeeded")
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/signer/eip712"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+)
+
+type outputSignTypedData struct {
+	Hash      string
+	Signature string
+}
+
+var commandSignTypedData = cli.Command{
+	Name:      "signtypeddata",
+	Usage:     "sign EIP-712 typed data",
+	ArgsUsage: "<keyfile> <jsonfile>",
+	Description: `
+Sign the EIP-712 typed data contained in a JSON file with a keyfile.
+
+The file holds the types, primaryType, domain and message of the typed data,
+as passed to phoenixchain_signTypedData. The recovery id of the signature is 27 or 28.
+`,
+	Flags: []cli.Flag{
+		passphraseFlag,
+		jsonFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		if len(ctx.Args()) != 2 {
+			utils.Fatalf("Invalid number of arguments: want 2, got %d", len(ctx.Args()))
+		}
+		typedData := getTypedData(ctx.Args().Get(1))
+		hash, _, err := eip712.TypedDataAndHash(typedData)
+		if err != nil {
+			utils.Fatalf("Invalid typed data: %v", err)
+		}
+
+		// Load the keyfile.
+		keyfilepath := ctx.Args().First()
+		keyjson, err := ioutil.ReadFile(keyfilepath)
+		if err != nil {
+			utils.Fatalf("Failed to read the keyfile at '%s': %v", keyfilepath, err)
+		}
+
+		// Decrypt key with passphrase.
+		passphrase := getPassphrase(ctx, false)
+		key, err := keystore.DecryptKey(keyjson, passphrase)
+		if err != nil {
+			utils.Fatalf("Error decrypting key: %v", err)
+		}
+
+		signature, err := eip712.SignTypedData(key.PrivateKey, typedData)
+		if err != nil {
+			utils.Fatalf("Failed to sign typed data: %v", err)
+		}
+		out := outputSignTypedData{
+			Hash:      hex.EncodeToString(hash),
+			Signature: hex.EncodeToString(signature),
+		}
+		if ctx.Bool(jsonFlag.Name) {
+			mustPrintJSON(out)
+		} else {
+			fmt.Println("Hash:     ", out.Hash)
+			fmt.Println("Signature:", out.Signature)
+		}
+		return nil
+	},
+}
+
+var commandVerifyTypedData = cli.Command{
+	Name:      "verifytypeddata",
+	Usage:     "verify the signature of EIP-712 typed data",
+	ArgsUsage: "<address> <signature> <jsonfile>",
+	Description: `
+Verify the signature of the EIP-712 typed data contained in a JSON file.
+
+Signatures with a recovery id of either 0/1 or 27/28 are accepted.
+`,
+	Flags: []cli.Flag{
+		jsonFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		if len(ctx.Args()) != 3 {
+			utils.Fatalf("Invalid number of arguments: want 3, got %d", len(ctx.Args()))
+		}
+		addressStr := ctx.Args().First()
+		signatureHex := ctx.Args().Get(1)
+		typedData := getTypedData(ctx.Args().Get(2))
+
+		address, err := common.StringToAddress(addressStr)
+		if err != nil {
+			utils.Fatalf("Invalid address: %s", addressStr)
+		}
+		signature, err := hex.DecodeString(signatureHex)
+		if err != nil {
+			utils.Fatalf("Signature encoding is not hexadecimal: %v", err)
+		}
+		recoveredAddress, err := eip712.RecoverTypedData(typedData, signature)
+		if err != nil {
+			utils.Fatalf("Signature verification failed: %v", err)
+		}
+
+		out := outputVerify{
+			Success:          bytes.Equal(address.Bytes(), recoveredAddress.Bytes()),
+			RecoveredAddress: recoveredAddress.String(),
+		}
+		if ctx.Bool(jsonFlag.Name) {
+			mustPrintJSON(out)
+		} else {
+			if out.Success {
+				fmt.Println("Signature verification successful!")
+			} else {
+				fmt.Println("Signature verification failed!")
+			}
+			fmt.Println("Recovered address:", out.RecoveredAddress)
+		}
+		return nil
+	},
+}
+
+// getTypedData reads EIP-712 typed data from a JSON file. Numbers are decoded
+// exactly, so integers beyond the precision of a float64 are hashed correctly.
+func getTypedData(file string) eip712.TypedData {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		utils.Fatalf("Can't read typed data file: %v", err)
+	}
+	var typedData eip712.TypedData
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&typedData); err != nil {
+		utils.Fatalf("Invalid typed data file: %v", err)
+	}
+	return typedData
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// typedDataJSON is the example message of the EIP-712 specification.
+const typedDataJSON = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestTypedDataSignVerify(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "phoenixkey-test")
+	if err != nil {
+		t.Fatal("Can't create temporary directory:", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	var (
+		keyfile  = filepath.Join(tmpdir, "the-keyfile")
+		privfile = filepath.Join(tmpdir, "the-privatekey")
+		passfile = filepath.Join(tmpdir, "the-password")
+		datafile = filepath.Join(tmpdir, "the-typeddata")
+	)
+	// The key of the "Cow" account of the specification, keccak256("cow").
+	ioutil.WriteFile(privfile, []byte("c85ef7d79691fe79573b1a7064c19c1a9819ebdbd1faaab1a8ec92344438aaf4"), 0600)
+	ioutil.WriteFile(passfile, []byte("foobar\n"), 0600)
+	ioutil.WriteFile(datafile, []byte(typedDataJSON), 0600)
+
+	// Import the key.
+	generate := runKeytool(t, "generate", "--privatekey", privfile, "--passwordfile", passfile, keyfile)
+	generate.ExpectRegexp(`Address: .*\n`)
+	generate.ExpectExit()
+
+	// Sign the typed data.
+	sign := runKeytool(t, "signtypeddata", "--passwordfile", passfile, keyfile, datafile)
+	sign.Expect("Hash:      be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2\n")
+	_, matches := sign.ExpectRegexp(`Signature: ([0-9a-f]{130})\n`)
+	signature := matches[1]
+	sign.ExpectExit()
+
+	// Verify the typed data.
+	verify := runKeytool(t, "verifytypeddata", "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826", signature, datafile)
+	verify.Expect(`
+Signature verification successful!
+Recovered address: 0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826
+`)
+	verify.ExpectExit()
+}
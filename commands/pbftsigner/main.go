@@ -0,0 +1,160 @@
+// pbftsigner is a signer daemon holding the node key and BLS key of a validator,
+// which signs blocks and consensus messages for the pbft engine of a node
+// started with --pbft.signer.
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/signer"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/node"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rpc"
+)
+
+// protectionFile is the file in the data directory holding the watermarks of
+// the signed consensus messages.
+const protectionFile = "protection.json"
+
+var (
+	logLevelFlag = cli.IntFlag{
+		Name:  "loglevel",
+		Value: 3,
+		Usage: "log level to emit to the screen",
+	}
+	dataDirFlag = cli.StringFlag{
+		Name:  "datadir",
+		Usage: "Directory for the double signing protection data",
+		Value: filepath.Join(node.DefaultDataDir(), "pbftsigner"),
+	}
+	blsKeyFlag = cli.StringFlag{
+		Name:  "blskey",
+		Usage: "BLS key file",
+	}
+	rpcPortFlag = cli.IntFlag{
+		Name:  "rpcport",
+		Usage: "HTTP-RPC server listening port",
+		Value: node.DefaultHTTPPort + 6,
+	}
+	app = cli.NewApp()
+)
+
+func init() {
+	app.Name = "pbftsigner"
+	app.Usage = "Sign blocks and consensus messages for a PhoenixChain validator"
+	app.Flags = []cli.Flag{
+		logLevelFlag,
+		dataDirFlag,
+		utils.NodeKeyFileFlag,
+		blsKeyFlag,
+		utils.KeyPasswordFileFlag,
+		utils.IPCDisabledFlag,
+		utils.IPCPathFlag,
+		utils.RPCEnabledFlag,
+		utils.RPCListenAddrFlag,
+		rpcPortFlag,
+		utils.RPCVirtualHostsFlag,
+	}
+	app.Action = run
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(c *cli.Context) error {
+	log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(c.Int(logLevelFlag.Name)), log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
+
+	// Load the keys of the validator.
+	if !c.IsSet(utils.NodeKeyFileFlag.Name) || !c.IsSet(blsKeyFlag.Name) {
+		utils.Fatalf("Both --%s and --%s are required", utils.NodeKeyFileFlag.Name, blsKeyFlag.Name)
+	}
+	passphrase := utils.MakeKeyPassphrase(c)
+	nodeKey, err := keystore.LoadNodeKey(c.String(utils.NodeKeyFileFlag.Name), passphrase)
+	if err != nil {
+		utils.Fatalf("Failed to load node key: %v", err)
+	}
+	blsKey, err := keystore.LoadBLSKey(c.String(blsKeyFlag.Name), passphrase)
+	if err != nil {
+		utils.Fatalf("Failed to load BLS key: %v", err)
+	}
+
+	dataDir := c.String(dataDirFlag.Name)
+	protection, err := signer.NewProtection(filepath.Join(dataDir, protectionFile))
+	if err != nil {
+		utils.Fatalf("Failed to load double signing protection: %v", err)
+	}
+	local := signer.NewLocalSigner(nodeKey, blsKey, protection)
+	log.Info("Loaded validator keys", "nodeID", local.NodeID().TerminalString())
+
+	rpcAPI := []rpc.API{
+		{
+			Namespace: signer.Namespace,
+			Version:   "1.0",
+			Service:   signer.NewAPI(local),
+			Public:    true,
+		},
+	}
+	if c.Bool(utils.RPCEnabledFlag.Name) {
+		srv := rpc.NewServer()
+		if err := node.RegisterApisFromWhitelist(rpcAPI, []string{signer.Namespace}, srv, false); err != nil {
+			utils.Fatalf("Could not register API: %v", err)
+		}
+		vhosts := splitAndTrim(c.String(utils.RPCVirtualHostsFlag.Name))
+		handler := node.NewHTTPHandlerStack(srv, nil, vhosts)
+
+		httpEndpoint := fmt.Sprintf("%s:%d", c.String(utils.RPCListenAddrFlag.Name), c.Int(rpcPortFlag.Name))
+		listener, err := node.StartHTTPEndpoint(httpEndpoint, rpc.DefaultHTTPTimeouts, handler)
+		if err != nil {
+			utils.Fatalf("Could not start RPC api: %v", err)
+		}
+		log.Info("HTTP endpoint opened", "url", fmt.Sprintf("http://%s", httpEndpoint))
+		defer func() {
+			listener.Close()
+			log.Info("HTTP endpoint closed", "url", httpEndpoint)
+		}()
+	}
+	if !c.Bool(utils.IPCDisabledFlag.Name) {
+		ipcapiURL := filepath.Join(dataDir, "pbftsigner.ipc")
+		if c.IsSet(utils.IPCPathFlag.Name) {
+			ipcapiURL = c.String(utils.IPCPathFlag.Name)
+		}
+		listener, _, err := rpc.StartIPCEndpoint(ipcapiURL, rpcAPI)
+		if err != nil {
+			utils.Fatalf("Could not start IPC api: %v", err)
+		}
+		log.Info("IPC endpoint opened", "url", ipcapiURL)
+		defer func() {
+			listener.Close()
+			log.Info("IPC endpoint closed", "url", ipcapiURL)
+		}()
+	}
+
+	abortChan := make(chan os.Signal, 1)
+	signal.Notify(abortChan, os.Interrupt)
+
+	sig := <-abortChan
+	log.Info("Exiting...", "signal", sig)
+	return nil
+}
+
+// splitAndTrim splits input separated by a comma
+// and trims excessive white space from the substrings.
+func splitAndTrim(input string) []string {
+	result := strings.Split(input, ",")
+	for i, r := range result {
+		result[i] = strings.TrimSpace(r)
+	}
+	return result
+}
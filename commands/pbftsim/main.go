@@ -0,0 +1,101 @@
+// pbftsim runs pbft validators in-process on a simulated clock and network,
+// following scenario files which inject network faults, equivocating and
+// crashing validators, and check the safety and liveness of the consensus.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/simulation"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/snapshotdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+)
+
+// Git SHA1 commit hash of the release (set via linker flags)
+var gitCommit = ""
+var gitDate = ""
+var app *cli.App
+
+func init() {
+	app = utils.NewApp(gitCommit, gitDate, "a PhoenixChain consensus simulator")
+	app.ArgsUsage = "<scenario.json> [<scenario.json>...]"
+	app.Flags = []cli.Flag{
+		logLevelFlag,
+		seedFlag,
+		dataDirFlag,
+	}
+	app.Before = func(ctx *cli.Context) error {
+		log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(ctx.GlobalInt(logLevelFlag.Name)), log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
+		return nil
+	}
+	app.Action = run
+}
+
+// Commonly used command line flags.
+var (
+	logLevelFlag = cli.IntFlag{
+		Name:  "loglevel",
+		Value: 1,
+		Usage: "log level to emit to the screen",
+	}
+	seedFlag = cli.Int64Flag{
+		Name:  "seed",
+		Usage: "seed of the random faults, overrides those of the scenarios",
+	}
+	dataDirFlag = cli.StringFlag{
+		Name:  "datadir",
+		Usage: "directory to keep the chains and wals of the validators in, removed after the run if not set",
+	}
+)
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run runs the scenarios one after the other, it fails on the first safety or
+// liveness violation.
+func run(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		utils.Fatalf("At least one scenario file is required")
+	}
+	// The snapshot db is shared by the validators of the process, keep it
+	// with their data instead of the working directory.
+	dir := ctx.GlobalString(dataDirFlag.Name)
+	if dir == "" {
+		tmp, err := ioutil.TempDir("", "pbftsim")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		dir = tmp
+	}
+	snapshotdb.SetDBPathWithNode(filepath.Join(dir, snapshotdb.DBPath))
+	for _, path := range ctx.Args() {
+		sc, err := simulation.LoadScenario(path)
+		if err != nil {
+			return err
+		}
+		if ctx.GlobalIsSet(seedFlag.Name) {
+			sc.Seed = ctx.GlobalInt64(seedFlag.Name)
+		}
+		var dataDir string
+		if ctx.GlobalIsSet(dataDirFlag.Name) {
+			dataDir = filepath.Join(dir, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+		}
+		fmt.Printf("Running %s with seed %d\n", path, sc.Seed)
+		if err := sc.Run(dataDir, os.Stdout); err != nil {
+			return fmt.Errorf("scenario %s failed: %v", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/wal"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+)
+
+var commandList = cli.Command{
+	Name:      "list",
+	Usage:     "list the content of the wal database and the journal files",
+	ArgsUsage: "<waldir>",
+	Description: `
+Print the committed, locked and QC blocks, the position of the last confirmed
+view in the journal, the stored view change QCs and the journal files.`,
+	Flags: []cli.Flag{
+		jsonFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		dir := walDir(ctx)
+		meta, err := wal.ReadMeta(dir)
+		if err != nil {
+			utils.Fatalf("Failed to read the wal database: %v", err)
+		}
+		files, err := wal.ListJournals(dir)
+		if err != nil {
+			utils.Fatalf("Failed to list the journal files: %v", err)
+		}
+
+		if ctx.Bool(jsonFlag.Name) {
+			out := &listInfo{ViewChange: meta.ViewChange, ViewChangeQCs: meta.ViewChangeQCs, Journals: files}
+			if cs := meta.ChainState; cs != nil {
+				out.Commit, out.Lock = newStateInfo(cs.Commit), newStateInfo(cs.Lock)
+				for _, s := range cs.QC {
+					out.QC = append(out.QC, newStateInfo(s))
+				}
+			}
+			mustPrintJSON(out)
+			return nil
+		}
+		if cs := meta.ChainState; cs != nil {
+			fmt.Println("Commit:", formatState(cs.Commit))
+			fmt.Println("Lock:  ", formatState(cs.Lock))
+			for _, s := range cs.QC {
+				fmt.Println("QC:    ", formatState(s))
+			}
+		} else {
+			fmt.Println("No chain state")
+		}
+		if vc := meta.ViewChange; vc != nil {
+			fmt.Printf("Confirmed view: epoch %d, view %d, block %d, journal position wal.%d:%d\n", vc.Epoch, vc.ViewNumber, vc.BlockNumber, vc.FileID, vc.Seq)
+		} else {
+			fmt.Println("No confirmed view")
+		}
+		for _, entry := range meta.ViewChangeQCs {
+			_, _, _, _, hash, number := entry.ViewChangeQC.MaxBlock()
+			fmt.Printf("View change QC: epoch %d, view %d, block %d, %d QCs, highest block %d %s\n",
+				entry.Epoch, entry.ViewNumber, entry.BlockNumber, len(entry.ViewChangeQC.QCs), number, hash.TerminalString())
+		}
+		for _, file := range files {
+			fmt.Printf("Journal: wal.%d, %d bytes\n", file.ID, file.Size)
+		}
+		return nil
+	},
+}
+
+var commandDump = cli.Command{
+	Name:      "dump",
+	Usage:     "decode the journalled messages",
+	ArgsUsage: "<waldir>",
+	Description: `
+Print the journalled messages with their position, epoch, view, block and
+signer. By default the messages loaded by the node on restart are printed,
+those from the last confirmed view on; use --all to print every message.`,
+	Flags: []cli.Flag{
+		jsonFlag,
+		cli.BoolFlag{
+			Name:  "all",
+			Usage: "print the messages before the last confirmed view too",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		dir := walDir(ctx)
+		fileID, offset := startPosition(ctx, dir)
+
+		var infos []*entryInfo
+		err := wal.ReadJournal(dir, fileID, offset, func(entry *wal.JournalEntry) error {
+			info := describeEntry(entry)
+			if ctx.Bool(jsonFlag.Name) {
+				infos = append(infos, info)
+			} else {
+				fmt.Println(info)
+			}
+			return nil
+		})
+		if ctx.Bool(jsonFlag.Name) {
+			mustPrintJSON(infos)
+		}
+		return err
+	},
+}
+
+// startPosition returns the position of the last confirmed view in the
+// journal, or the start of the journal if --all is set.
+func startPosition(ctx *cli.Context, dir string) (uint32, uint64) {
+	if ctx.Bool("all") {
+		return 0, 0
+	}
+	meta, err := wal.ReadMeta(dir)
+	if err != nil {
+		utils.Fatalf("Failed to read the wal database: %v", err)
+	}
+	if meta.ViewChange == nil {
+		return 0, 0
+	}
+	return meta.ViewChange.FileID, meta.ViewChange.Seq
+}
+
+// entryInfo describes a journalled message.
+type entryInfo struct {
+	File        uint32      `json:"file"`
+	Offset      uint64      `json:"offset"`
+	Time        time.Time   `json:"time"`
+	Type        string      `json:"type"`
+	Epoch       uint64      `json:"epoch"`
+	ViewNumber  uint64      `json:"viewNumber"`
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockIndex  uint32      `json:"blockIndex"`
+	BlockHash   common.Hash `json:"blockHash"`
+	Signer      *uint32     `json:"signer,omitempty"` // Validator index, nil for the confirmed views
+}
+
+func (info *entryInfo) String() string {
+	signer := "-"
+	if info.Signer != nil {
+		signer = fmt.Sprint(*info.Signer)
+	}
+	return fmt.Sprintf("wal.%d:%-8d %s %-19s epoch=%d view=%d number=%d index=%d hash=%s signer=%s",
+		info.File, info.Offset, info.Time.UTC().Format("2006-01-02T15:04:05.000"), info.Type,
+		info.Epoch, info.ViewNumber, info.BlockNumber, info.BlockIndex, info.BlockHash.TerminalString(), signer)
+}
+
+func describeEntry(entry *wal.JournalEntry) *entryInfo {
+	info := &entryInfo{
+		File:   entry.FileID,
+		Offset: entry.Offset,
+		Time:   time.Unix(0, int64(entry.Timestamp)),
+	}
+	switch m := entry.Msg.(type) {
+	case *protocols.ConfirmedViewChange:
+		info.Type = "ConfirmedViewChange"
+		info.Epoch, info.ViewNumber = m.Epoch, m.ViewNumber
+		info.BlockNumber, info.BlockHash = m.Block.NumberU64(), m.Block.Hash()
+		if m.QC != nil {
+			info.BlockIndex = m.QC.BlockIndex
+		}
+	case *protocols.SendViewChange:
+		info.Type = "SendViewChange"
+		info.Epoch, info.ViewNumber = m.ViewChange.Epoch, m.ViewChange.ViewNumber
+		info.BlockNumber, info.BlockHash = m.ViewChange.BlockNumber, m.ViewChange.BlockHash
+		if m.ViewChange.PrepareQC != nil {
+			info.BlockIndex = m.ViewChange.PrepareQC.BlockIndex
+		}
+		info.Signer = &m.ViewChange.ValidatorIndex
+	case *protocols.SendPrepareBlock:
+		info.Type = "SendPrepareBlock"
+		info.Epoch, info.ViewNumber = m.Prepare.Epoch, m.Prepare.ViewNumber
+		info.BlockNumber, info.BlockHash = m.Prepare.Block.NumberU64(), m.Prepare.Block.Hash()
+		info.BlockIndex = m.Prepare.BlockIndex
+		info.Signer = &m.Prepare.ProposalIndex
+	case *protocols.SendPrepareVote:
+		info.Type = "SendPrepareVote"
+		info.Epoch, info.ViewNumber = m.Vote.Epoch, m.Vote.ViewNumber
+		info.BlockNumber, info.BlockHash = m.Vote.BlockNumber, m.Vote.BlockHash
+		info.BlockIndex = m.Vote.BlockIndex
+		info.Signer = &m.Vote.ValidatorIndex
+	case *protocols.SendPreCommit:
+		info.Type = "SendPreCommit"
+		info.Epoch, info.ViewNumber = m.Vote.Epoch, m.Vote.ViewNumber
+		info.BlockNumber, info.BlockHash = m.Vote.BlockNumber, m.Vote.BlockHash
+		info.BlockIndex = m.Vote.BlockIndex
+		info.Signer = &m.Vote.ValidatorIndex
+	}
+	return info
+}
+
+// listInfo is the JSON output of the list command.
+type listInfo struct {
+	Commit        *stateInfo               `json:"commit,omitempty"`
+	Lock          *stateInfo               `json:"lock,omitempty"`
+	QC            []*stateInfo             `json:"qc,omitempty"`
+	ViewChange    *wal.ViewChangeMessage   `json:"viewChange,omitempty"`
+	ViewChangeQCs []*wal.ViewChangeQCEntry `json:"viewChangeQCs,omitempty"`
+	Journals      []wal.JournalFile        `json:"journals"`
+}
+
+// stateInfo describes a block of the chain state.
+type stateInfo struct {
+	BlockNumber uint64             `json:"blockNumber"`
+	BlockHash   common.Hash        `json:"blockHash"`
+	QuorumCert  *ctypes.QuorumCert `json:"qc"`
+}
+
+func newStateInfo(s *protocols.State) *stateInfo {
+	return &stateInfo{BlockNumber: s.Block.NumberU64(), BlockHash: s.Block.Hash(), QuorumCert: s.QuorumCert}
+}
+
+func formatState(s *protocols.State) string {
+	return fmt.Sprintf("number=%d hash=%s %s", s.Block.NumberU64(), s.Block.Hash().TerminalString(), formatQC(s.QuorumCert))
+}
+
+func formatQC(qc *ctypes.QuorumCert) string {
+	if qc == nil {
+		return "qc=nil"
+	}
+	return fmt.Sprintf("epoch=%d view=%d index=%d signers=%s", qc.Epoch, qc.ViewNumber, qc.BlockIndex, qc.ValidatorSet.String())
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/wal"
+)
+
+var commandPrune = cli.Command{
+	Name:      "prune",
+	Usage:     "remove the journal files before the last confirmed view",
+	ArgsUsage: "<waldir>",
+	Description: `
+Remove the journal files the node doesn't load anymore, which a running node
+removes itself when it confirms a view.`,
+	Action: func(ctx *cli.Context) error {
+		removed, err := wal.PruneJournals(walDir(ctx))
+		for _, id := range removed {
+			fmt.Printf("Removed wal.%d\n", id)
+		}
+		if err != nil {
+			utils.Fatalf("Failed to prune the journal: %v", err)
+		}
+		if len(removed) == 0 {
+			fmt.Println("Nothing to prune")
+		}
+		return nil
+	},
+}
+
+var commandTruncate = cli.Command{
+	Name:      "truncate",
+	Usage:     "drop the journalled messages from a position on",
+	ArgsUsage: "<waldir>",
+	Description: `
+Drop the journalled messages from the given offset of a journal file on,
+removing the later journal files. The offset has to be the position of a
+message as printed by the dump command, or the position of a corrupted tail
+reported by the dump command. Keep a copy of the wal directory, the messages
+dropped are lost.`,
+	Flags: []cli.Flag{
+		cli.UintFlag{
+			Name:  "file",
+			Usage: "number of the journal file, N of wal.N",
+		},
+		cli.Uint64Flag{
+			Name:  "offset",
+			Usage: "offset of the first message to drop in the journal file",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		dir := walDir(ctx)
+		if !ctx.IsSet("file") || !ctx.IsSet("offset") {
+			utils.Fatalf("Both --file and --offset are required")
+		}
+		fileID, offset := uint32(ctx.Uint("file")), ctx.Uint64("offset")
+		if err := wal.TruncateJournal(dir, fileID, offset); err != nil {
+			utils.Fatalf("Failed to truncate the journal: %v", err)
+		}
+		fmt.Printf("Truncated wal.%d at offset %d\n", fileID, offset)
+		return nil
+	},
+}
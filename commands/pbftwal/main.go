@@ -0,0 +1,120 @@
+// pbftwal inspects the consensus WAL of a stopped node: it decodes the
+// journalled messages, verifies their signatures, prunes or truncates the
+// journal and replays it into an engine to reproduce the recovery of the node.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+)
+
+// Git SHA1 commit hash of the release (set via linker flags)
+var gitCommit = ""
+var gitDate = ""
+var app *cli.App
+
+func init() {
+	app = utils.NewApp(gitCommit, gitDate, "a PhoenixChain consensus WAL inspector")
+	app.Flags = []cli.Flag{
+		logLevelFlag,
+	}
+	app.Before = func(ctx *cli.Context) error {
+		log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(ctx.GlobalInt(logLevelFlag.Name)), log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
+		return nil
+	}
+	app.Commands = []cli.Command{
+		commandList,
+		commandDump,
+		commandVerify,
+		commandPrune,
+		commandTruncate,
+		commandReplay,
+	}
+}
+
+// Commonly used command line flags.
+var (
+	logLevelFlag = cli.IntFlag{
+		Name:  "loglevel",
+		Value: 1,
+		Usage: "log level to emit to the screen",
+	}
+	jsonFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "output JSON instead of human-readable format",
+	}
+	genesisFlag = cli.StringFlag{
+		Name:  "genesis",
+		Usage: "genesis file of the network, the mainnet configuration is used if not set",
+	}
+	validatorsFlag = cli.StringFlag{
+		Name:  "validators",
+		Usage: "JSON file with the validators in the format of the initialNodes of the genesis, overrides those of the genesis",
+	}
+)
+
+func main() {
+	cli.CommandHelpTemplate = utils.OriginCommandHelpTemplate
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// walDir returns the wal directory given as the first argument.
+func walDir(ctx *cli.Context) string {
+	if ctx.NArg() != 1 {
+		utils.Fatalf("The wal directory is required, it is <datadir>/phoenixchain/wal for a node")
+	}
+	return ctx.Args().First()
+}
+
+// chainConfig returns the chain configuration and the validators given by
+// the --genesis and --validators flags.
+func chainConfig(ctx *cli.Context) (*configs.ChainConfig, []configs.PbftNode) {
+	config := configs.MainnetChainConfig
+	if file := ctx.String(genesisFlag.Name); file != "" {
+		genesis := new(core.Genesis)
+		mustLoadJSON(file, genesis)
+		if genesis.Config == nil || genesis.Config.Pbft == nil {
+			utils.Fatalf("No pbft configuration in the genesis file %s", file)
+		}
+		config = genesis.Config
+	}
+	validators := config.Pbft.InitialNodes
+	if file := ctx.String(validatorsFlag.Name); file != "" {
+		validators = nil
+		mustLoadJSON(file, &validators)
+	}
+	if len(validators) == 0 {
+		utils.Fatalf("No validators given")
+	}
+	return config, validators
+}
+
+func mustLoadJSON(file string, val interface{}) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		utils.Fatalf("Failed to read %s: %v", file, err)
+	}
+	if err := json.Unmarshal(content, val); err != nil {
+		utils.Fatalf("Failed to decode %s: %v", file, err)
+	}
+}
+
+func mustPrintJSON(jsonObject interface{}) {
+	str, err := json.MarshalIndent(jsonObject, "", "  ")
+	if err != nil {
+		utils.Fatalf("Failed to marshal JSON object: %v", err)
+	}
+	fmt.Println(string(str))
+}
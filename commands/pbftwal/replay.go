@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+)
+
+var commandReplay = cli.Command{
+	Name:      "replay",
+	Usage:     "replay the wal into a consensus engine",
+	ArgsUsage: "<waldir>",
+	Description: `
+Load the wal into a consensus engine the way the node does on restart, and
+print the view of the engine after each journalled message and the state of
+the engine at the end. The engine starts on the committed block of the wal with
+the validators of the genesis or of the --validators file; it neither executes
+blocks nor signs or sends messages. The replay stops at the first message the
+node would fail to recover.`,
+	Flags: []cli.Flag{
+		genesisFlag,
+		validatorsFlag,
+		cli.StringFlag{
+			Name:  "nodeid",
+			Usage: "node ID of the validator the wal belongs to",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		dir := walDir(ctx)
+		config, validators := chainConfig(ctx)
+		nodeID, err := discover.HexID(ctx.String("nodeid"))
+		if err != nil {
+			utils.Fatalf("Invalid --nodeid: %v", err)
+		}
+
+		status, err := pbft.ReplayWal(dir, config, validators, nodeID, func(step *pbft.ReplayStep) {
+			result := "ok"
+			if step.Err != nil {
+				result = step.Err.Error()
+			}
+			fmt.Printf("%s -> %s %s\n", describeEntry(step.Entry), step.View, result)
+		})
+		if status != nil {
+			var out bytes.Buffer
+			json.Indent(&out, status, "", "  ")
+			fmt.Println(out.String())
+		}
+		return err
+	},
+}
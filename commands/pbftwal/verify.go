@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	pbftutils "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/validator"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/wal"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+)
+
+var commandVerify = cli.Command{
+	Name:      "verify",
+	Usage:     "verify the signatures of the wal",
+	ArgsUsage: "<waldir>",
+	Description: `
+Verify the signatures of the journalled messages and of the quorum
+certificates they carry, the QCs of the chain state and the stored view change
+QCs against the validators of the genesis or of the --validators file. The
+validators are used for every epoch, so the wal of a node which switched
+validators has to be verified with the validators of its epoch.`,
+	Flags: []cli.Flag{
+		genesisFlag,
+		validatorsFlag,
+		cli.BoolFlag{
+			Name:  "all",
+			Usage: "verify the messages before the last confirmed view too",
+		},
+	},
+	Action: func(ctx *cli.Context) error {
+		dir := walDir(ctx)
+		_, nodes := chainConfig(ctx)
+		validators, _ := validator.NewStaticAgency(nodes).GetValidator(0)
+
+		meta, err := wal.ReadMeta(dir)
+		if err != nil {
+			utils.Fatalf("Failed to read the wal database: %v", err)
+		}
+		checked, failed := 0, 0
+		check := func(what string, err error) {
+			checked++
+			if err != nil {
+				failed++
+				fmt.Printf("%s: %v\n", what, err)
+			}
+		}
+		if cs := meta.ChainState; cs != nil {
+			check("commit qc", verifyQC(validators, cs.Commit.QuorumCert))
+			check("lock qc", verifyQC(validators, cs.Lock.QuorumCert))
+			for _, s := range cs.QC {
+				check("qc", verifyQC(validators, s.QuorumCert))
+			}
+		}
+		for _, entry := range meta.ViewChangeQCs {
+			what := fmt.Sprintf("view change qc of epoch %d view %d block %d", entry.Epoch, entry.ViewNumber, entry.BlockNumber)
+			check(what, verifyViewChangeQC(validators, entry.ViewChangeQC))
+		}
+
+		fileID, offset := startPosition(ctx, dir)
+		err = wal.ReadJournal(dir, fileID, offset, func(entry *wal.JournalEntry) error {
+			what := describeEntry(entry).String()
+			switch m := entry.Msg.(type) {
+			case *protocols.ConfirmedViewChange:
+				check(what+" qc", verifyQC(validators, m.QC))
+				check(what+" view change qc", verifyViewChangeQC(validators, m.ViewChangeQC))
+			case *protocols.SendViewChange:
+				check(what, verifyMsg(validators, m.ViewChange))
+				check(what+" prepare qc", verifyQC(validators, m.ViewChange.PrepareQC))
+			case *protocols.SendPrepareBlock:
+				check(what, verifyMsg(validators, m.Prepare))
+				check(what+" prepare qc", verifyQC(validators, m.Prepare.PrepareQC))
+				check(what+" view change qc", verifyViewChangeQC(validators, m.Prepare.ViewChangeQC))
+			case *protocols.SendPrepareVote:
+				check(what, verifyMsg(validators, m.Vote))
+				check(what+" parent qc", verifyQC(validators, m.Vote.ParentQC))
+			case *protocols.SendPreCommit:
+				check(what, verifyMsg(validators, m.Vote))
+				check(what+" parent qc", verifyQC(validators, m.Vote.ParentQC))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d signatures checked, %d invalid\n", checked, failed)
+		if failed > 0 {
+			return errors.New("invalid signatures found")
+		}
+		return nil
+	},
+}
+
+// verifyMsg verifies the signature of a consensus message.
+func verifyMsg(validators *pbfttypes.Validators, msg ctypes.ConsensusMsg) error {
+	node, err := validators.FindNodeByIndex(int(msg.NodeIndex()))
+	if err != nil {
+		return err
+	}
+	data, err := msg.CannibalizeBytes()
+	if err != nil {
+		return err
+	}
+	return node.Verify(data, msg.Sign())
+}
+
+// verifyQC verifies the aggregated signature of a quorum certificate, which
+// may be absent.
+func verifyQC(validators *pbfttypes.Validators, qc *ctypes.QuorumCert) error {
+	if qc == nil {
+		return nil
+	}
+	data, err := qc.CannibalizeBytes()
+	if err != nil {
+		return err
+	}
+	return verifyAggSig(validators, qc.ValidatorSet, data, qc.Signature.Bytes())
+}
+
+// verifyViewChangeQC verifies the quorum certificates of a view change QC,
+// which may be absent.
+func verifyViewChangeQC(validators *pbfttypes.Validators, viewChangeQC *ctypes.ViewChangeQC) error {
+	if viewChangeQC == nil {
+		return nil
+	}
+	for _, qc := range viewChangeQC.QCs {
+		data, err := qc.CannibalizeBytes()
+		if err != nil {
+			return err
+		}
+		if err := verifyAggSig(validators, qc.ValidatorSet, data, qc.Signature.Bytes()); err != nil {
+			return fmt.Errorf("qc of block %d: %v", qc.BlockNumber, err)
+		}
+	}
+	return nil
+}
+
+// verifyAggSig verifies a signature aggregated by the validators of the set,
+// the way the validator pool of the engine does.
+func verifyAggSig(validators *pbfttypes.Validators, set *pbftutils.BitArray, msg, signature []byte) error {
+	if set == nil {
+		return errors.New("no validator set")
+	}
+	nodes, err := validators.NodeListByBitArray(set)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return errors.New("empty validator set")
+	}
+	pub := *nodes[0].BlsPubKey
+	for _, node := range nodes[1:] {
+		pub.Add(node.BlsPubKey)
+	}
+	var sig bls.Sign
+	if err := sig.Deserialize(signature); err != nil {
+		return err
+	}
+	if !sig.Verify(&pub, string(msg)) {
+		return errors.New("bls verifies signature fail")
+	}
+	return nil
+}
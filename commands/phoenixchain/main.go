@@ -18,6 +18,7 @@ import (
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/hdwallet"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/node"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/internal/debug"
@@ -72,6 +73,7 @@ var (
 		utils.CacheDatabaseFlag,
 		utils.CacheGCFlag,
 		utils.CacheTrieDBFlag,
+		utils.CacheSnapshotFlag,
 		utils.ListenPortFlag,
 		utils.MaxPeersFlag,
 		utils.MaxConsensusPeersFlag,
@@ -87,6 +89,7 @@ var (
 		utils.NetrestrictFlag,
 		utils.NodeKeyFileFlag,
 		utils.NodeKeyHexFlag,
+		utils.KeyPasswordFileFlag,
 		utils.DeveloperPeriodFlag,
 		utils.MainFlag,
 		utils.TestnetFlag,
@@ -104,12 +107,23 @@ var (
 		utils.RPCPortFlag,
 		utils.RPCCORSDomainFlag,
 		utils.RPCVirtualHostsFlag,
+		utils.RPCJWTSecretFlag,
+		utils.RPCJWTPublicKeyFlag,
+		utils.RPCRateLimitFlag,
+		utils.RPCRateBurstFlag,
+		utils.RPCMethodWeightsFlag,
+		utils.RPCMaxConcurrentFlag,
+		utils.RPCBatchLimitFlag,
+		utils.RPCResponseLimitFlag,
 		utils.RPCApiFlag,
 		utils.WSEnabledFlag,
 		utils.WSListenAddrFlag,
 		utils.WSPortFlag,
 		utils.WSApiFlag,
 		utils.WSAllowedOriginsFlag,
+		utils.GraphQLEnabledFlag,
+		utils.GraphQLCORSDomainFlag,
+		utils.GraphQLVirtualHostsFlag,
 		utils.IPCDisabledFlag,
 		utils.IPCPathFlag,
 		utils.InsecureUnlockAllowedFlag,
@@ -149,7 +163,9 @@ var (
 		utils.PbftWalDisabledFlag,
 		utils.PbftMaxPingLatency,
 		utils.PbftBlsPriKeyFileFlag,
+		utils.PbftSignerFlag,
 		utils.PbftBlacklistDeadlineFlag,
+		utils.PbftEvidenceReporterFlag,
 	}
 
 	dbFlags = []cli.Flag{
@@ -309,8 +325,12 @@ func startNode(ctx *cli.Context, stack *node.Node) {
 		}
 		stateReader := ethclient.NewClient(rpcClient)
 
-		// Open any wallets already attached
+		// Open any wallets already attached, HD wallets need a passphrase to be
+		// opened explicitly via personal_openWallet
 		for _, wallet := range stack.AccountManager().Wallets() {
+			if wallet.URL().Scheme == hdwallet.Scheme {
+				continue
+			}
 			if err := wallet.Open(""); err != nil {
 				log.Warn("Failed to open wallet", "url", wallet.URL(), "err", err)
 			}
@@ -319,6 +339,10 @@ func startNode(ctx *cli.Context, stack *node.Node) {
 		for event := range events {
 			switch event.Kind {
 			case accounts.WalletArrived:
+				if event.Wallet.URL().Scheme == hdwallet.Scheme {
+					log.Info("New HD wallet appeared", "url", event.Wallet.URL())
+					continue
+				}
 				if err := event.Wallet.Open(""); err != nil {
 					log.Warn("New wallet appeared, failed to open", "url", event.Wallet.URL(), "err", err)
 				}
@@ -106,6 +106,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.CacheDatabaseFlag,
 			utils.CacheGCFlag,
 			utils.CacheTrieDBFlag,
+			utils.CacheSnapshotFlag,
 		},
 	},
 	{
@@ -133,6 +134,17 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.IPCPathFlag,
 			utils.RPCCORSDomainFlag,
 			utils.RPCVirtualHostsFlag,
+			utils.RPCJWTSecretFlag,
+			utils.RPCJWTPublicKeyFlag,
+			utils.RPCRateLimitFlag,
+			utils.RPCRateBurstFlag,
+			utils.RPCMethodWeightsFlag,
+			utils.RPCMaxConcurrentFlag,
+			utils.RPCBatchLimitFlag,
+			utils.RPCResponseLimitFlag,
+			utils.GraphQLEnabledFlag,
+			utils.GraphQLCORSDomainFlag,
+			utils.GraphQLVirtualHostsFlag,
 			utils.JSpathFlag,
 			utils.ExecFlag,
 			utils.PreloadJSFlag,
@@ -154,6 +166,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.NetrestrictFlag,
 			utils.NodeKeyFileFlag,
 			utils.NodeKeyHexFlag,
+			utils.KeyPasswordFileFlag,
 		},
 	},
 	{
@@ -209,7 +222,9 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.PbftWalDisabledFlag,
 			utils.PbftMaxPingLatency,
 			utils.PbftBlsPriKeyFileFlag,
+			utils.PbftSignerFlag,
 			utils.PbftBlacklistDeadlineFlag,
+			utils.PbftEvidenceReporterFlag,
 		},
 	},
 	{
@@ -7,6 +7,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/eth/gasprice"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/ethstats"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -24,11 +25,13 @@ import (
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/signer"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/graphql"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/les"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/node"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p"
@@ -38,7 +41,6 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/fdlimit"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
-	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/metrics"
@@ -152,7 +154,7 @@ var (
 	defaultSyncMode = eth2.DefaultConfig.SyncMode
 	SyncModeFlag    = TextMarshalerFlag{
 		Name:  "syncmode",
-		Usage: `Blockchain sync mode ("fast", "full", or "light")`,
+		Usage: `Blockchain sync mode ("fast", "full", "light" or "snap")`,
 		Value: &defaultSyncMode,
 	}
 	LightServFlag = cli.IntFlag{
@@ -249,6 +251,11 @@ var (
 		Usage: "Megabytes of memory allocated to triedb internal caching",
 		Value: eth2.DefaultConfig.TrieDBCache,
 	}
+	CacheSnapshotFlag = cli.IntFlag{
+		Name:  "cache.snapshot",
+		Usage: "Megabytes of memory allocated to state snapshot caching (0 disables the snapshot)",
+		Value: eth2.DefaultConfig.SnapshotCache,
+	}
 	MinerGasTargetFlag = cli.Uint64Flag{
 		Name:  "miner.gastarget",
 		Usage: "Target gas floor for mined blocks",
@@ -317,6 +324,41 @@ var (
 		Usage: "API's offered over the HTTP-RPC interface",
 		Value: "",
 	}
+	RPCJWTSecretFlag = cli.StringFlag{
+		Name:  "rpc.jwtsecret",
+		Usage: "Path to a hex encoded secret verifying the HS256 bearer tokens required on the HTTP and WS-RPC servers",
+		Value: "",
+	}
+	RPCJWTPublicKeyFlag = cli.StringFlag{
+		Name:  "rpc.jwtpublickey",
+		Usage: "Path to a PEM encoded RSA or ECDSA public key verifying the RS256/ES256 bearer tokens required on the HTTP and WS-RPC servers",
+		Value: "",
+	}
+	RPCRateLimitFlag = cli.Float64Flag{
+		Name:  "rpc.ratelimit",
+		Usage: "Request cost per second granted to each HTTP and WS-RPC client (0 = unlimited)",
+	}
+	RPCRateBurstFlag = cli.IntFlag{
+		Name:  "rpc.rateburst",
+		Usage: "Request cost an HTTP or WS-RPC client may spend at once (0 = rate limit)",
+	}
+	RPCMethodWeightsFlag = cli.StringFlag{
+		Name:  "rpc.methodweights",
+		Usage: "Comma separated request costs of expensive methods (e.g. debug_traceBlock=50,phoenixchain_getLogs=10)",
+		Value: "",
+	}
+	RPCMaxConcurrentFlag = cli.IntFlag{
+		Name:  "rpc.maxconcurrent",
+		Usage: "Maximum number of requests an HTTP or WS-RPC client may have in flight (0 = unlimited)",
+	}
+	RPCBatchLimitFlag = cli.IntFlag{
+		Name:  "rpc.batchlimit",
+		Usage: "Maximum number of calls in an HTTP or WS-RPC batch request (0 = unlimited)",
+	}
+	RPCResponseLimitFlag = cli.IntFlag{
+		Name:  "rpc.responselimit",
+		Usage: "Maximum size in bytes of an HTTP or WS-RPC call result (0 = unlimited)",
+	}
 	IPCDisabledFlag = cli.BoolFlag{
 		Name:  "ipcdisable",
 		Usage: "Disable the IPC-RPC server",
@@ -349,6 +391,20 @@ var (
 		Usage: "Origins from which to accept websockets requests",
 		Value: "",
 	}
+	GraphQLEnabledFlag = cli.BoolFlag{
+		Name:  "graphql",
+		Usage: "Enable GraphQL on the HTTP-RPC server at /graphql",
+	}
+	GraphQLCORSDomainFlag = cli.StringFlag{
+		Name:  "graphql.corsdomain",
+		Usage: "Comma separated list of domains from which to accept cross origin requests (browser enforced)",
+		Value: "",
+	}
+	GraphQLVirtualHostsFlag = cli.StringFlag{
+		Name:  "graphql.vhosts",
+		Usage: "Comma separated list of virtual hostnames from which to accept requests (server enforced). Accepts '*' wildcard.",
+		Value: strings.Join(node.DefaultConfig.GraphQLVirtualHosts, ","),
+	}
 	ExecFlag = cli.StringFlag{
 		Name:  "exec",
 		Usage: "Execute JavaScript statement",
@@ -403,6 +459,10 @@ var (
 		Name:  "nodekeyhex",
 		Usage: "P2P node key as hex (for testing)",
 	}
+	KeyPasswordFileFlag = cli.StringFlag{
+		Name:  "keypassword",
+		Usage: "Password file to decrypt encrypted node and BLS key files",
+	}
 	NATFlag = cli.StringFlag{
 		Name:  "nat",
 		Usage: "NAT port mapping mechanism (any|none|upnp|pmp|extip:<IP>)",
@@ -550,12 +610,22 @@ var (
 		Usage: "BLS key file",
 	}
 
+	PbftSignerFlag = cli.StringFlag{
+		Name:  "pbft.signer",
+		Usage: "IPC path or HTTP URL of a remote signer holding the node and BLS keys of the validator",
+	}
+
 	PbftBlacklistDeadlineFlag = cli.StringFlag{
 		Name:  "pbft.blacklist_deadline",
 		Usage: "Blacklist effective time. uint:minute",
 		Value: "60",
 	}
 
+	PbftEvidenceReporterFlag = cli.StringFlag{
+		Name:  "pbft.evidence_reporter",
+		Usage: "Unlocked account reporting the duplicate signatures detected by this node to the slashing contract",
+	}
+
 	DBNoGCFlag = cli.BoolFlag{
 		Name:  "db.nogc",
 		Usage: "Disables database garbage collection",
@@ -624,7 +694,7 @@ func setNodeKey(ctx *cli.Context, cfg *p2p.Config) {
 	case file != "" && hex != "":
 		Fatalf("Options %q and %q are mutually exclusive", NodeKeyFileFlag.Name, NodeKeyHexFlag.Name)
 	case file != "":
-		if key, err = crypto.LoadECDSA(file); err != nil {
+		if key, err = keystore.LoadNodeKey(file, MakeKeyPassphrase(ctx)); err != nil {
 			Fatalf("Option %q: %v", NodeKeyFileFlag.Name, err)
 		}
 		cfg.PrivateKey = key
@@ -774,6 +844,63 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setRPCAuth configures the token authentication of the HTTP and WebSocket
+// RPC endpoints from the set command line flags.
+func setRPCAuth(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalIsSet(RPCJWTSecretFlag.Name) {
+		cfg.JWTSecret = ctx.GlobalString(RPCJWTSecretFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCJWTPublicKeyFlag.Name) {
+		cfg.JWTPublicKey = ctx.GlobalString(RPCJWTPublicKeyFlag.Name)
+	}
+}
+
+// setRPCLimits configures the request quotas of the HTTP and WebSocket RPC
+// clients from the set command line flags.
+func setRPCLimits(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalIsSet(RPCRateLimitFlag.Name) {
+		cfg.RPCLimits.RequestRate = ctx.GlobalFloat64(RPCRateLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCRateBurstFlag.Name) {
+		cfg.RPCLimits.RequestBurst = ctx.GlobalInt(RPCRateBurstFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCMethodWeightsFlag.Name) {
+		weights := make(map[string]int)
+		for _, entry := range splitAndTrim(ctx.GlobalString(RPCMethodWeightsFlag.Name)) {
+			parts := strings.SplitN(entry, "=", 2)
+			if len(parts) != 2 {
+				Fatalf("Invalid method weight %q, want method=weight", entry)
+			}
+			weight, err := strconv.Atoi(parts[1])
+			if err != nil || weight <= 0 {
+				Fatalf("Invalid weight of method %s: %q", parts[0], parts[1])
+			}
+			weights[parts[0]] = weight
+		}
+		cfg.RPCLimits.MethodWeights = weights
+	}
+	if ctx.GlobalIsSet(RPCMaxConcurrentFlag.Name) {
+		cfg.RPCLimits.MaxConcurrent = ctx.GlobalInt(RPCMaxConcurrentFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCBatchLimitFlag.Name) {
+		cfg.RPCLimits.MaxBatchSize = ctx.GlobalInt(RPCBatchLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(RPCResponseLimitFlag.Name) {
+		cfg.RPCLimits.MaxResponseSize = ctx.GlobalInt(RPCResponseLimitFlag.Name)
+	}
+}
+
+// setGraphQL creates the GraphQL CORS and virtual hosts configuration from
+// the set command line flags.
+func setGraphQL(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalIsSet(GraphQLCORSDomainFlag.Name) {
+		cfg.GraphQLCors = splitAndTrim(ctx.GlobalString(GraphQLCORSDomainFlag.Name))
+	}
+	if ctx.GlobalIsSet(GraphQLVirtualHostsFlag.Name) {
+		cfg.GraphQLVirtualHosts = splitAndTrim(ctx.GlobalString(GraphQLVirtualHostsFlag.Name))
+	}
+}
+
 // setIPC creates an IPC path configuration from the set command line flags,
 // returning an empty string if IPC was explicitly disabled, or the set path.
 func setIPC(ctx *cli.Context, cfg *node.Config) {
@@ -846,6 +973,20 @@ func MakePasswordList(ctx *cli.Context) []string {
 	return lines
 }
 
+// MakeKeyPassphrase reads the passphrase of encrypted node and BLS key files
+// from the file given by the --keypassword flag, if any.
+func MakeKeyPassphrase(ctx *cli.Context) string {
+	path := ctx.GlobalString(KeyPasswordFileFlag.Name)
+	if path == "" {
+		return ""
+	}
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		Fatalf("Failed to read key password file: %v", err)
+	}
+	return strings.TrimRight(string(text), "\r\n")
+}
+
 func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	setNodeKey(ctx, cfg)
 	setNAT(ctx, cfg)
@@ -920,10 +1061,14 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 
 // SetNodeConfig applies node-related command line flags to the config.
 func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
+	cfg.KeyPassphrase = MakeKeyPassphrase(ctx)
 	SetP2PConfig(ctx, &cfg.P2P)
 	setIPC(ctx, cfg)
 	setHTTP(ctx, cfg)
 	setWS(ctx, cfg)
+	setRPCAuth(ctx, cfg)
+	setRPCLimits(ctx, cfg)
+	setGraphQL(ctx, cfg)
 	setNodeUserIdent(ctx, cfg)
 
 	switch {
@@ -1095,6 +1240,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth2.Config) {
 	if ctx.GlobalIsSet(CacheTrieDBFlag.Name) {
 		cfg.TrieDBCache = ctx.GlobalInt(CacheTrieDBFlag.Name)
 	}
+	if ctx.GlobalIsSet(CacheSnapshotFlag.Name) {
+		cfg.SnapshotCache = ctx.GlobalInt(CacheSnapshotFlag.Name)
+	}
 	if ctx.GlobalIsSet(DocRootFlag.Name) {
 		cfg.DocRoot = ctx.GlobalString(DocRootFlag.Name)
 	}
@@ -1144,21 +1292,25 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth2.Config) {
 }
 
 func SetPbft(ctx *cli.Context, cfg *types.OptionsConfig, nodeCfg *node.Config) {
-	if nodeCfg.P2P.PrivateKey != nil {
-		cfg.NodePriKey = nodeCfg.P2P.PrivateKey
-		cfg.NodeID = discover.PubkeyID(&cfg.NodePriKey.PublicKey)
-	}
+	if ctx.GlobalIsSet(PbftSignerFlag.Name) {
+		setPbftSigner(ctx, cfg, nodeCfg)
+	} else {
+		if nodeCfg.P2P.PrivateKey != nil {
+			cfg.NodePriKey = nodeCfg.P2P.PrivateKey
+			cfg.NodeID = discover.PubkeyID(&cfg.NodePriKey.PublicKey)
+		}
 
-	if ctx.GlobalIsSet(PbftBlsPriKeyFileFlag.Name) {
-		priKey, err := bls.LoadBLS(ctx.GlobalString(PbftBlsPriKeyFileFlag.Name))
-		if err != nil {
-			Fatalf("Failed to load bls key from file: %v", err)
+		if ctx.GlobalIsSet(PbftBlsPriKeyFileFlag.Name) {
+			priKey, err := keystore.LoadBLSKey(ctx.GlobalString(PbftBlsPriKeyFileFlag.Name), nodeCfg.KeyPassphrase)
+			if err != nil {
+				Fatalf("Failed to load bls key from file: %v", err)
+			}
+			cfg.BlsPriKey = priKey
+		} else {
+			cfg.BlsPriKey = nodeCfg.BlsKey()
 		}
-		cfg.BlsPriKey = priKey
-	} else {
-		cfg.BlsPriKey = nodeCfg.BlsKey()
+		nodeCfg.P2P.BlsPublicKey = *(cfg.BlsPriKey.GetPublicKey())
 	}
-	nodeCfg.P2P.BlsPublicKey = *(cfg.BlsPriKey.GetPublicKey())
 
 	if ctx.GlobalIsSet(PbftWalDisabledFlag.Name) {
 		cfg.WalMode = !ctx.GlobalBool(PbftWalDisabledFlag.Name)
@@ -1174,9 +1326,41 @@ func SetPbft(ctx *cli.Context, cfg *types.OptionsConfig, nodeCfg *node.Config) {
 	if ctx.GlobalIsSet(PbftBlacklistDeadlineFlag.Name) {
 		cfg.BlacklistDeadline = ctx.GlobalInt64(PbftBlacklistDeadlineFlag.Name)
 	}
+	if ctx.GlobalIsSet(PbftEvidenceReporterFlag.Name) {
+		reporter := ctx.GlobalString(PbftEvidenceReporterFlag.Name)
+		if !common.IsHexAddress(reporter) {
+			Fatalf("Invalid evidence reporter address %q", reporter)
+		}
+		cfg.EvidenceReporter = common.HexToAddress(reporter)
+	}
 
 }
 
+// setPbftSigner connects the consensus engine to a remote signer, which holds the
+// BLS key instead of the node. The node key identifies the node on the p2p
+// network as well, so the node has to be started with the same key.
+func setPbftSigner(ctx *cli.Context, cfg *types.OptionsConfig, nodeCfg *node.Config) {
+	if ctx.GlobalIsSet(PbftBlsPriKeyFileFlag.Name) {
+		Fatalf("Flags --%s and --%s are mutually exclusive", PbftSignerFlag.Name, PbftBlsPriKeyFileFlag.Name)
+	}
+	endpoint := ctx.GlobalString(PbftSignerFlag.Name)
+	remote, err := signer.NewRemoteSigner(endpoint)
+	if err != nil {
+		Fatalf("Failed to connect to the pbft signer at %s: %v", endpoint, err)
+	}
+	if nodeID := discover.PubkeyID(&nodeCfg.NodeKey().PublicKey); nodeID != remote.NodeID() {
+		Fatalf("Node key of the pbft signer %s doesn't match the node key %s", remote.NodeID().TerminalString(), nodeID.TerminalString())
+	}
+	blsPubKey, err := remote.BlsPublicKey()
+	if err != nil {
+		Fatalf("Failed to get the BLS public key from the pbft signer: %v", err)
+	}
+	cfg.Signer = remote
+	cfg.NodeID = remote.NodeID()
+	nodeCfg.P2P.BlsPublicKey = *blsPubKey
+	log.Info("Using remote pbft signer", "endpoint", endpoint, "nodeID", cfg.NodeID.TerminalString())
+}
+
 // RegisterEthService adds an Ethereum client to the stack.
 func RegisterEthService(stack *node.Node, cfg *eth2.Config) {
 	var err error
@@ -1207,6 +1391,30 @@ func RegisterEthService(stack *node.Node, cfg *eth2.Config) {
 	}
 }
 
+// RegisterGraphQLService is a utility function to construct a new service and register it against a node.
+// The queries are served on the HTTP-RPC endpoint of the node, which must be enabled.
+func RegisterGraphQLService(stack *node.Node, cors, vhosts []string) {
+	if stack.Config().HTTPHost == "" {
+		Fatalf("GraphQL requires the HTTP-RPC server to be enabled with --%s", RPCEnabledFlag.Name)
+	}
+	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		// Try to construct the GraphQL service backed by a full node
+		var ethServ *eth2.Ethereum
+		if err := ctx.Service(&ethServ); err == nil {
+			return graphql.New(stack, ethServ.APIBackend, cors, vhosts)
+		}
+		// Try to construct the GraphQL service backed by a light node
+		var lesServ *les.LightEthereum
+		if err := ctx.Service(&lesServ); err == nil {
+			return graphql.New(stack, lesServ.ApiBackend, cors, vhosts)
+		}
+		// Well, this should not have happened, bail out
+		return nil, errors.New("no Phoenix-Chain-Core service")
+	}); err != nil {
+		Fatalf("Failed to register the GraphQL service: %v", err)
+	}
+}
+
 // RegisterShhService configures Whisper and adds it to the given node.
 //func RegisterShhService(stack *node.Node, cfg *whisper.Config) {
 //	if err := stack.Register(func(n *node.ServiceContext) (node.Service, error) {
@@ -1324,6 +1532,7 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 		MaxFutureBlocks: eth2.DefaultConfig.MaxFutureBlocks,
 		BadBlockLimit:   eth2.DefaultConfig.BadBlockLimit,
 		TriesInMemory:   eth2.DefaultConfig.TriesInMemory,
+		SnapshotLimit:   eth2.DefaultConfig.SnapshotCache,
 	}
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
 		cache.TrieDirtyLimit = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
@@ -1367,6 +1576,7 @@ func MakeChainForPBFT(ctx *cli.Context, stack *node.Node, cfg *eth2.Config, node
 		MaxFutureBlocks: eth2.DefaultConfig.MaxFutureBlocks,
 		BadBlockLimit:   eth2.DefaultConfig.BadBlockLimit,
 		TriesInMemory:   eth2.DefaultConfig.TriesInMemory,
+		SnapshotLimit:   eth2.DefaultConfig.SnapshotCache,
 	}
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
 		cache.TrieDirtyLimit = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
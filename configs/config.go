@@ -112,9 +112,9 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), "", big.NewInt(0), big.NewInt(0), nil, nil, GenesisVersion}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), "", big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, nil, GenesisVersion}
 
-	TestChainConfig = &ChainConfig{big.NewInt(1),  "", big.NewInt(0), big.NewInt(0), nil, new(PbftConfig), GenesisVersion}
+	TestChainConfig = &ChainConfig{big.NewInt(1), "", big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, new(PbftConfig), GenesisVersion}
 )
 
 // TrustedCheckpoint represents a set of post-processed trie roots (CHT and
@@ -140,6 +140,10 @@ type ChainConfig struct {
 	EmptyBlock  string   `json:"emptyBlock"`
 	EIP155Block *big.Int `json:"eip155Block,omitempty"` // EIP155 HF block
 	EWASMBlock  *big.Int `json:"ewasmBlock,omitempty"`  // EWASM switch block (nil = no fork, 0 = already activated)
+
+	DynamicFeeBlock *big.Int `json:"dynamicFeeBlock,omitempty"` // Dynamic fee market switch block (nil = no fork, 0 = already activated)
+	AccessListBlock *big.Int `json:"accessListBlock,omitempty"` // Access list switch block (nil = no fork, 0 = already activated)
+	TreasuryBlock   *big.Int `json:"treasuryBlock,omitempty"`   // Treasury proposals switch block (nil = no fork, 0 = already activated)
 	// Various consensus engines
 	Clique *CliqueConfig `json:"clique,omitempty"`
 	Pbft   *PbftConfig   `json:"pbft,omitempty"`
@@ -167,6 +171,23 @@ type PbftConfig struct {
 	Amount        uint32     `json:"amount,omitempty"`        //The maximum number of blocks generated per cycle
 	InitialNodes  []PbftNode `json:"initialNodes,omitempty"`  //Genesis consensus node
 	ValidatorMode string     `json:"validatorMode,omitempty"` //Validator mode for easy testing
+
+	ProposerRotationBlock *big.Int                  `json:"proposerRotationBlock,omitempty"` // Stake-weighted proposer rotation switch block (nil = no fork, 0 = already activated)
+	ProposerReputation    *ProposerReputationConfig `json:"proposerReputation,omitempty"`    // Skips proposers missing their turns after the rotation fork if set
+}
+
+// ProposerReputationConfig configures skipping the proposers which missed
+// their turns recently. It takes effect with the proposer rotation fork and
+// must not be changed afterwards.
+type ProposerReputationConfig struct {
+	Period    uint64 `json:"period"`    // Number of blocks the missed turns are counted over and the proposers are skipped for
+	MaxMisses uint64 `json:"maxMisses"` // Proposers missing more turns in the previous period are skipped
+}
+
+// IsProposerRotation returns whether num represents a block number after the
+// fork switching the proposers by stake-weighted rotation.
+func (c *PbftConfig) IsProposerRotation(num *big.Int) bool {
+	return isForked(c.ProposerRotationBlock, num)
 }
 
 // CliqueConfig is the consensus engine configs for proof-of-authority based sealing.
@@ -191,9 +212,12 @@ func (c *ChainConfig) String() string {
 	default:
 		engine = "unknown"
 	}
-	return fmt.Sprintf("{ChainID: %v EIP155: %v Engine: %v}",
+	return fmt.Sprintf("{ChainID: %v EIP155: %v DynamicFee: %v AccessList: %v Treasury: %v Engine: %v}",
 		c.ChainID,
 		c.EIP155Block,
+		c.DynamicFeeBlock,
+		c.AccessListBlock,
+		c.TreasuryBlock,
 		engine,
 	)
 }
@@ -209,6 +233,24 @@ func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return isForked(c.EWASMBlock, num)
 }
 
+// IsDynamicFee returns whether num represents a block number after the fork
+// introducing the base fee and the dynamic fee transactions.
+func (c *ChainConfig) IsDynamicFee(num *big.Int) bool {
+	return isForked(c.DynamicFeeBlock, num)
+}
+
+// IsAccessList returns whether num represents a block number after the fork
+// introducing the access list transactions and the warm state access prices.
+func (c *ChainConfig) IsAccessList(num *big.Int) bool {
+	return isForked(c.AccessListBlock, num)
+}
+
+// IsTreasury returns whether num represents a block number after the fork
+// introducing the treasury proposals.
+func (c *ChainConfig) IsTreasury(num *big.Int) bool {
+	return isForked(c.TreasuryBlock, num)
+}
+
 // GasTable returns the gas table corresponding to the current phase (homestead or homestead reprice).
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
@@ -241,6 +283,21 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if isForkIncompatible(c.DynamicFeeBlock, newcfg.DynamicFeeBlock, head) {
+		return newCompatError("dynamic fee fork block", c.DynamicFeeBlock, newcfg.DynamicFeeBlock)
+	}
+	if isForkIncompatible(c.AccessListBlock, newcfg.AccessListBlock, head) {
+		return newCompatError("access list fork block", c.AccessListBlock, newcfg.AccessListBlock)
+	}
+	if isForkIncompatible(c.TreasuryBlock, newcfg.TreasuryBlock, head) {
+		return newCompatError("treasury fork block", c.TreasuryBlock, newcfg.TreasuryBlock)
+	}
+	if c.Pbft != nil && newcfg.Pbft != nil && isForkIncompatible(c.Pbft.ProposerRotationBlock, newcfg.Pbft.ProposerRotationBlock, head) {
+		return newCompatError("proposer rotation fork block", c.Pbft.ProposerRotationBlock, newcfg.Pbft.ProposerRotationBlock)
+	}
+	if c.Pbft != nil && newcfg.Pbft != nil && isForked(c.Pbft.ProposerRotationBlock, head) && !proposerReputationEqual(c.Pbft.ProposerReputation, newcfg.Pbft.ProposerReputation) {
+		return newCompatError("proposer reputation", c.Pbft.ProposerRotationBlock, newcfg.Pbft.ProposerRotationBlock)
+	}
 	return nil
 }
 
@@ -268,6 +325,13 @@ func configNumEqual(x, y *big.Int) bool {
 	return x.Cmp(y) == 0
 }
 
+func proposerReputationEqual(x, y *ProposerReputationConfig) bool {
+	if x == nil || y == nil {
+		return x == y
+	}
+	return *x == *y
+}
+
 // ConfigCompatError is raised if the locally-stored blockchain is initialised with a
 // ChainConfig that would alter the past.
 type ConfigCompatError struct {
@@ -46,6 +46,51 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     0,
 			},
 		},
+		{
+			stored:  &ChainConfig{Pbft: &PbftConfig{ProposerRotationBlock: big.NewInt(10)}},
+			new:     &ChainConfig{Pbft: &PbftConfig{ProposerRotationBlock: big.NewInt(20)}},
+			head:    9,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{Pbft: &PbftConfig{ProposerRotationBlock: big.NewInt(10)}},
+			new:    &ChainConfig{Pbft: &PbftConfig{}},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "proposer rotation fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    nil,
+				RewindTo:     9,
+			},
+		},
+		{
+			stored:  &ChainConfig{Pbft: &PbftConfig{ProposerRotationBlock: big.NewInt(10), ProposerReputation: &ProposerReputationConfig{Period: 100, MaxMisses: 2}}},
+			new:     &ChainConfig{Pbft: &PbftConfig{ProposerRotationBlock: big.NewInt(10), ProposerReputation: &ProposerReputationConfig{Period: 200, MaxMisses: 2}}},
+			head:    9,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{Pbft: &PbftConfig{ProposerRotationBlock: big.NewInt(10), ProposerReputation: &ProposerReputationConfig{Period: 100, MaxMisses: 2}}},
+			new:    &ChainConfig{Pbft: &PbftConfig{ProposerRotationBlock: big.NewInt(10), ProposerReputation: &ProposerReputationConfig{Period: 100, MaxMisses: 3}}},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "proposer reputation",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{TreasuryBlock: big.NewInt(30)},
+			new:    &ChainConfig{TreasuryBlock: big.NewInt(40)},
+			head:   35,
+			wantErr: &ConfigCompatError{
+				What:         "treasury fork block",
+				StoredConfig: big.NewInt(30),
+				NewConfig:    big.NewInt(40),
+				RewindTo:     29,
+			},
+		},
 	}
 
 	for _, test := range tests {
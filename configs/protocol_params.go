@@ -9,6 +9,10 @@ const (
 	DefaultMinerGasCeil  uint64 = 21000 * 8000 * 1.2 // 201600000
 	MaxGasCeil           uint64 = 300000000
 
+	BaseFeeChangeDenominator = 8          // Bounds the amount the base fee can change between blocks.
+	ElasticityMultiplier     = 2          // Bounds the maximum gas limit a block may have relative to its gas target.
+	InitialBaseFee           = 1000000000 // Initial base fee for the first block of the dynamic fee fork.
+
 	MaximumExtraDataSize uint64 = 32    // Maximum size extra data may be after Genesis.
 	ExpByteGas           uint64 = 10    // Times ceil(log256(exponent)) for the EXP instruction.
 	SloadGas             uint64 = 50    // Multiplied by the number of 32-byte words that are copied (round up) for any *COPY operation and added.
@@ -84,6 +88,15 @@ const (
 	ExtcodeHashGasEIP1884        uint64 = 700  // Cost of EXTCODEHASH after EIP 1884 (part in Istanbul)
 	SelfdestructGasEIP150        uint64 = 5000 // Cost of SELFDESTRUCT post EIP 150 (Tangerine)
 
+	// Prices of the state accesses after the access list fork. The first access
+	// to an account or a storage slot keeps the price above, later accesses and
+	// the ones declared by the access list of the transaction are warm.
+	WarmStorageReadCost       uint64 = 100 // Cost of reading a warm account or storage slot
+	ColdSloadCost             uint64 = 200 // Cost of the first SLOAD of a storage slot
+	ColdAccountAccessCost     uint64 = 700 // Cost of the first access to an account
+	TxAccessListAddressGas    uint64 = 500 // Per address specified in the access list of a transaction
+	TxAccessListStorageKeyGas uint64 = 80  // Per storage key specified in the access list of a transaction
+
 	// EXP has a dynamic portion depending on the size of the exponent
 	ExpByteFrontier uint64 = 10 // was set to 10 in Frontier
 	ExpByteEIP158   uint64 = 50 // was raised to 50 during Eip158 (Spurious Dragon)
@@ -125,13 +138,14 @@ const (
 	DelegateGas           uint64 = 16000 // Gas needed for delegate
 	WithdrewDelegationGas uint64 = 8000  // Gas needed for withdrewDelegate
 
-	GovGas                   uint64 = 9000   // Gas needed for precompiled contract: govContract
-	SubmitTextProposalGas    uint64 = 320000 // Gas needed for submitText
-	SubmitVersionProposalGas uint64 = 450000 // Gas needed for submitVersion
-	SubmitCancelProposalGas  uint64 = 500000 // Gas needed for submitCancel
-	SubmitParamProposalGas   uint64 = 500000 // Gas needed for submitParam
-	VoteGas                  uint64 = 2000   // Gas needed for vote
-	DeclareVersionGas        uint64 = 3000   // Gas needed for declareVersion
+	GovGas                    uint64 = 9000   // Gas needed for precompiled contract: govContract
+	SubmitTextProposalGas     uint64 = 320000 // Gas needed for submitText
+	SubmitVersionProposalGas  uint64 = 450000 // Gas needed for submitVersion
+	SubmitCancelProposalGas   uint64 = 500000 // Gas needed for submitCancel
+	SubmitParamProposalGas    uint64 = 500000 // Gas needed for submitParam
+	SubmitTreasuryProposalGas uint64 = 500000 // Gas needed for submitTreasury
+	VoteGas                   uint64 = 2000   // Gas needed for vote
+	DeclareVersionGas         uint64 = 3000   // Gas needed for declareVersion
 
 	SlashingGas              uint64 = 21000 // Gas needed for precompiled contract: slashingContract
 	ReportDuplicateSignGas   uint64 = 21000 // Gas needed for reportDuplicateSign
@@ -152,8 +166,9 @@ var (
 	MinimumDifficulty      = big.NewInt(131072) // The minimum that the difficulty may ever be.
 	DurationLimit          = big.NewInt(13)     // The decision boundary on the blocktime duration used to determine whether difficulty should go up or not.
 
-	SubmitTextProposalGasPrice    = big.NewInt(1500000 * 1000000000) // Min gas price for submit a text proposal in Von
-	SubmitVersionProposalGasPrice = big.NewInt(2100000 * 1000000000) // Min gas price for submit a version proposal in Von
-	SubmitCancelProposalGasPrice  = big.NewInt(3000000 * 1000000000) // Min gas price for submit a cancel proposal in Von
-	SubmitParamProposalGasPrice   = big.NewInt(2000000 * 1000000000) // Min gas price for submit a cancel proposal in Von
+	SubmitTextProposalGasPrice     = big.NewInt(1500000 * 1000000000) // Min gas price for submit a text proposal in Von
+	SubmitVersionProposalGasPrice  = big.NewInt(2100000 * 1000000000) // Min gas price for submit a version proposal in Von
+	SubmitCancelProposalGasPrice   = big.NewInt(3000000 * 1000000000) // Min gas price for submit a cancel proposal in Von
+	SubmitParamProposalGasPrice    = big.NewInt(2000000 * 1000000000) // Min gas price for submit a cancel proposal in Von
+	SubmitTreasuryProposalGasPrice = big.NewInt(2000000 * 1000000000) // Min gas price for submit a treasury proposal in Von
 )
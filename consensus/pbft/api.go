@@ -1,11 +1,16 @@
 package pbft
 
 import (
+	"context"
 	"encoding/json"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/evidence"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/state"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/consensus"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rpc"
 )
 
 type Status struct {
@@ -18,6 +23,8 @@ type Status struct {
 type API interface {
 	Status() []byte
 	Evidences() string
+	EvidencePool() evidence.EvidencePool
+	Health() *consensus.HealthReport
 	GetPrepareQC(number uint64) *types.QuorumCert
 	GetSchnorrNIZKProve() (*bls.SchnorrProof, error)
 }
@@ -67,6 +74,67 @@ func (s *PublicPhoenixchainConsensusAPI) Evidences() string {
 	return s.engine.Evidences()
 }
 
+// GetEvidences returns the duplicate evidences matching the filter, all of
+// them if the filter is omitted.
+func (s *PublicPhoenixchainConsensusAPI) GetEvidences(filter *consensus.EvidenceFilter) ([]*consensus.EvidenceInfo, error) {
+	evs := s.engine.EvidencePool().Query(filter)
+	infos := make([]*consensus.EvidenceInfo, 0, len(evs))
+	for _, e := range evs {
+		info, err := evidence.NewEvidenceInfo(e)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// ConsensusHealth returns the prepare votes, pre-commits, proposals and view
+// changes of the validators in the last committed blocks.
+func (s *PublicPhoenixchainConsensusAPI) ConsensusHealth() *consensus.HealthReport {
+	return s.engine.Health()
+}
+
+// NewEvidences creates a subscription that fires for each duplicate evidence
+// matching the filter as soon as it is recorded.
+func (s *PublicPhoenixchainConsensusAPI) NewEvidences(ctx context.Context, filter *consensus.EvidenceFilter) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		evs := make(chan evidence.NewEvidenceEvent, 16)
+		evSub := s.engine.EvidencePool().SubscribeNewEvidence(evs)
+		defer evSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-evs:
+				if !filter.Match(ev.Evidence) {
+					continue
+				}
+				info, err := evidence.NewEvidenceInfo(ev.Evidence)
+				if err != nil {
+					log.Error("Failed to encode new evidence", "err", err)
+					continue
+				}
+				notifier.Notify(rpcSub.ID, info)
+			case <-evSub.Err():
+				return
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // PublicAdminConsensusAPI provides an API to access the PhoenixChain blockchain.
 // It offers only methods that operate on public data that
 // is freely available to anyone.
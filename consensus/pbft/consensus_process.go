@@ -67,13 +67,15 @@ func (pbft *Pbft) OnPrepareBlock(id string, msg *protocols.PrepareBlock) error {
 		}
 	}
 
-	if _, err := pbft.verifyConsensusMsg(msg); err != nil {
+	node, err := pbft.verifyConsensusMsg(msg)
+	if err != nil {
 		pbft.log.Error("Failed to verify prepareBlock", "prepare", msg.String(), "error", err.Error())
 		signatureCheckFailureMeter.Mark(1)
 		return err
 	}
 	// The new block is notified by the PrepareBlockHash to the nodes in the network.
 	pbft.state.AddPrepareBlock(msg)
+	pbft.health.AddPrepareBlock(msg, node)
 	pbft.log.Debug("Receive new prepareBlock", "msgHash", msg.MsgHash(), "prepare", msg.String())
 	pbft.state.UpdateStep(ctypes.RoundStepPrepareBlock)
 	pbft.exePrepareBlock(msg)
@@ -116,6 +118,7 @@ func (pbft *Pbft) OnPrepareVote(id string, msg *protocols.PrepareVote) error {
 	}
 
 	pbft.state.AddPrepareVote(uint32(node.Index), msg)
+	pbft.health.AddPrepareVote(msg, node)
 	pbft.log.Debug("Receive new prepareVote", "msgHash", msg.MsgHash(), "vote", msg.String(), "votes", pbft.state.PrepareVoteLenByNumber(msg.BlockNumber))
 
 	pbft.state.UpdateStep(ctypes.RoundStepPrepareVote)
@@ -160,6 +163,7 @@ func (pbft *Pbft) OnPreCommit(id string, msg *protocols.PreCommit) error {
 	}
 
 	pbft.state.AddPreCommit(uint32(node.Index), msg)
+	pbft.health.AddPreCommit(msg, node)
 	pbft.log.Debug("Receive new PreCommit", "msgHash", msg.MsgHash(), "vote", msg.String(), "votes", pbft.state.PreCommitLenByNumber(msg.BlockNumber))
 
 	pbft.state.UpdateStep(ctypes.RoundStepPreCommit)
@@ -190,6 +194,7 @@ func (pbft *Pbft) OnViewChange(id string, msg *protocols.ViewChange) error {
 	}
 
 	pbft.state.AddViewChange(uint32(node.Index), msg)
+	pbft.health.AddViewChange(msg, node)
 	pbft.log.Debug("Receive new viewChange", "msgHash", msg.MsgHash(), "viewChange", msg.String(), "total", pbft.state.ViewChangeLen())
 	//pbft.state.UpdateStep(ctypes.RoundStepNewRound)
 	// It is possible to achieve viewchangeQC every time you add viewchange
@@ -234,6 +239,7 @@ func (pbft *Pbft) OnViewTimeout() {
 	}
 
 	pbft.state.AddViewChange(uint32(node.Index), viewChange)
+	pbft.health.AddViewChange(viewChange, node)
 	pbft.network.Broadcast(viewChange)
 	pbft.log.Info("Local add viewChange", "index", node.Index, "viewChange", viewChange.String(), "total", pbft.state.ViewChangeLen())
 
@@ -278,6 +284,7 @@ func (pbft *Pbft) insertQCBlock(block *types.Block, qc *ctypes.QuorumCert) {
 			"CommitHash", pbft.state.HighestCommitBlock().Hash())
 		return
 	}
+	pbft.health.AddPreCommitQC(qc)
 	if pbft.state.Epoch() == qc.Epoch{
 		if pbft.state.ViewBlockByIndex(qc.BlockNumber) == nil {
 			pbft.state.AddQCBlock(block, qc)
@@ -298,7 +305,10 @@ func (pbft *Pbft) insertQCBlock(block *types.Block, qc *ctypes.QuorumCert) {
 		if err != nil {
 			return false
 		}
-		proposer := pbft.currentProposer()
+		proposer, err := pbft.currentProposer()
+		if err != nil {
+			return false
+		}
 		// The current node is the proposer and the block is generated by itself
 		if node.Index == proposer.Index && pbft.state.Epoch() == qc.Epoch {
 			return true
@@ -531,6 +541,7 @@ func (pbft *Pbft) trySendPrepareVote(msg *protocols.PrepareVote) {
 		node, _ := pbft.validatorPool.GetValidatorByNodeID(pbft.state.Epoch(), pbft.config.Option.NodeID)
 		pbft.log.Info("Add local prepareVote", "vote", p.String())
 		pbft.state.AddPrepareVote(uint32(node.Index), p)
+		pbft.health.AddPrepareVote(p, node)
 		//pending.Pop()
 
 		// write sendPrepareVote info to wal
@@ -582,6 +593,7 @@ func (pbft *Pbft) trySendPreCommit(msg *protocols.PreCommit) {
 		node, _ := pbft.validatorPool.GetValidatorByNodeID(pbft.state.Epoch(), pbft.config.Option.NodeID)
 		pbft.log.Info("Add local preCommit", "vote", p.String())
 		pbft.state.AddPreCommit(uint32(node.Index), p)
+		pbft.health.AddPreCommit(p, node)
 		//pending.Pop()
 
 		// write sendPrepareVote info to wal
@@ -640,6 +652,7 @@ func (pbft *Pbft) findPreVoteQC(blockNumber uint64) {
 			//pbft.trySendPrepareVote()
 			pbft.state.SetPrepareVoteQC(qc)
 			pbft.state.AddQC(qc)
+			pbft.health.AddPrepareQC(qc)
 			pbft.genPreCommit(qc)
 		}
 	}
@@ -744,6 +757,7 @@ func (pbft *Pbft) tryCommitNewBlock(lock *types.Block, commit *types.Block, qc *
 		pbft.state.SetHighestCommitBlock(commit)
 		pbft.blockTree.PruneBlock(commit.Hash(), commit.NumberU64(), nil)
 		pbft.blockTree.NewRoot(commit)
+		pbft.health.Commit(commit.NumberU64(), commit.Hash())
 		// metrics
 		blockNumberGauage.Update(int64(commit.NumberU64()))
 		highestQCNumberGauage.Update(int64(highestqc.NumberU64()))
@@ -883,6 +897,13 @@ func (pbft *Pbft) tryChangeViewByViewChange(viewChangeQC *ctypes.ViewChangeQC) {
 	}
 	_, _, blockEpoch, _, _, number := viewChangeQC.MaxBlock()
 
+	// The proposer didn't get its block to the node before the view changed.
+	if pbft.state.ViewBlockByIndex(pbft.state.BlockNumber()) == nil {
+		if proposer, err := pbft.currentProposer(); err == nil {
+			pbft.health.AddMissedProposal(pbft.state.Epoch(), pbft.state.ViewNumber(), pbft.state.BlockNumber(), proposer.NodeID)
+		}
+	}
+
 	if pbft.validatorPool.EqualSwitchPoint(number) && blockEpoch == pbft.state.Epoch() {
 		// Validator already switch, new epoch
 		pbft.log.Info("BlockNumber is equal to switchPoint, change epoch", "blockNumber", number, "view", pbft.state.ViewString())
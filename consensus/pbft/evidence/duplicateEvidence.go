@@ -400,16 +400,18 @@ func (d DuplicateViewChangeEvidence) ValidateMsg() bool {
 
 // EvidenceData encapsulate externally visible duplicate data
 type EvidenceData struct {
-	DP []*DuplicatePrepareBlockEvidence `json:"duplicatePrepare"`
-	DV []*DuplicatePrepareVoteEvidence  `json:"duplicateVote"`
-	DC []*DuplicateViewChangeEvidence   `json:"duplicateViewchange"`
+	DP  []*DuplicatePrepareBlockEvidence `json:"duplicatePrepare"`
+	DV  []*DuplicatePrepareVoteEvidence  `json:"duplicateVote"`
+	DC  []*DuplicateViewChangeEvidence   `json:"duplicateViewchange"`
+	DPC []*DuplicatePrecommitEvidence    `json:"duplicatePreCommit"`
 }
 
 func NewEvidenceData() *EvidenceData {
 	return &EvidenceData{
-		DP: make([]*DuplicatePrepareBlockEvidence, 0),
-		DV: make([]*DuplicatePrepareVoteEvidence, 0),
-		DC: make([]*DuplicateViewChangeEvidence, 0),
+		DP:  make([]*DuplicatePrepareBlockEvidence, 0),
+		DV:  make([]*DuplicatePrepareVoteEvidence, 0),
+		DC:  make([]*DuplicateViewChangeEvidence, 0),
+		DPC: make([]*DuplicatePrecommitEvidence, 0),
 	}
 }
 
@@ -424,6 +426,8 @@ func ClassifyEvidence(evds consensus.Evidences) *EvidenceData {
 			ed.DV = append(ed.DV, e.(*DuplicatePrepareVoteEvidence))
 		case *DuplicateViewChangeEvidence:
 			ed.DC = append(ed.DC, e.(*DuplicateViewChangeEvidence))
+		case *DuplicatePrecommitEvidence:
+			ed.DPC = append(ed.DPC, e.(*DuplicatePrecommitEvidence))
 		}
 	}
 	return ed
@@ -12,9 +12,11 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/node"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/consensus"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/event"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 var (
@@ -24,6 +26,8 @@ var (
 	voteDualPrefix = byte(0x2)
 	// Duplicate viewChange prefix
 	viewDualPrefix = byte(0x3)
+	// Duplicate preCommit prefix
+	precommitDualPrefix = byte(0x4)
 )
 
 // EvidencePool encapsulates functions required to record duplicate blocks and votes.
@@ -33,10 +37,18 @@ type EvidencePool interface {
 	AddPrepareVote(pv *protocols.PrepareVote, node *pbfttypes.ValidateNode) error
 	AddPreCommit(pv *protocols.PreCommit, node *pbfttypes.ValidateNode) error
 	AddViewChange(vc *protocols.ViewChange, node *pbfttypes.ValidateNode) error
+	// Query retrieves the recorded evidences matching the filter.
+	Query(filter *consensus.EvidenceFilter) consensus.Evidences
+	// SubscribeNewEvidence registers a subscription for the evidences recorded from now on.
+	SubscribeNewEvidence(ch chan<- NewEvidenceEvent) event.Subscription
 }
 
+// NewEvidenceEvent is posted when a duplicate evidence is recorded to the database.
+type NewEvidenceEvent struct{ Evidence consensus.Evidence }
+
 // emptyEvidencePool is a empty implementation for EvidencePool
 type emptyEvidencePool struct {
+	feed event.Feed
 }
 
 func (pool *emptyEvidencePool) AddPrepareBlock(pb *protocols.PrepareBlock, node *pbfttypes.ValidateNode) error {
@@ -59,6 +71,14 @@ func (pool *emptyEvidencePool) Evidences() consensus.Evidences {
 	return nil
 }
 
+func (pool *emptyEvidencePool) Query(filter *consensus.EvidenceFilter) consensus.Evidences {
+	return nil
+}
+
+func (pool *emptyEvidencePool) SubscribeNewEvidence(ch chan<- NewEvidenceEvent) event.Subscription {
+	return pool.feed.Subscribe(ch)
+}
+
 func (pool *emptyEvidencePool) Clear(epoch uint64, viewNumber uint64) {
 }
 
@@ -72,6 +92,9 @@ type baseEvidencePool struct {
 	pc PrecommitEvidence
 	vc ViewChangeEvidence
 	db *leveldb.DB
+
+	feed  event.Feed
+	scope event.SubscriptionScope
 }
 
 // NewEvidencePool creates a new baseEvidencePool to record duplicate blocks and votes.
@@ -180,26 +203,28 @@ func (pool *baseEvidencePool) AddViewChange(vc *protocols.ViewChange, node *pbft
 
 // Evidences retrieves the duplicate evidence by querying the database
 func (pool *baseEvidencePool) Evidences() consensus.Evidences {
+	return pool.Query(nil)
+}
+
+// Query retrieves the duplicate evidence matching the filter by querying the database.
+// The key layout allows to only iterate over the entries of the filtered type and epoch.
+func (pool *baseEvidencePool) Query(filter *consensus.EvidenceFilter) consensus.Evidences {
 	var evds consensus.Evidences
-	it := pool.db.NewIterator(nil, nil)
+	var rng *util.Range
+	if filter != nil && filter.Type != 0 {
+		prefix := []byte{evidenceTypePrefix(filter.Type)}
+		if filter.Epoch != nil {
+			epoch := [8]byte{}
+			binary.BigEndian.PutUint64(epoch[:], *filter.Epoch)
+			prefix = append(prefix, epoch[:]...)
+		}
+		rng = util.BytesPrefix(prefix)
+	}
+	it := pool.db.NewIterator(rng, nil)
 	for it.Next() {
-		flag := it.Key()[0]
-		switch flag {
-		case prepareDualPrefix:
-			var e DuplicatePrepareBlockEvidence
-			if err := rlp.DecodeBytes(it.Value(), &e); err == nil {
-				evds = append(evds, &e)
-			}
-		case voteDualPrefix:
-			var e DuplicatePrepareVoteEvidence
-			if err := rlp.DecodeBytes(it.Value(), &e); err == nil {
-				evds = append(evds, &e)
-			}
-		case viewDualPrefix:
-			var e DuplicateViewChangeEvidence
-			if err := rlp.DecodeBytes(it.Value(), &e); err == nil {
-				evds = append(evds, &e)
-			}
+		e := decodeEvidence(it.Key()[0], it.Value())
+		if e != nil && filter.Match(e) {
+			evds = append(evds, e)
 		}
 	}
 
@@ -207,6 +232,48 @@ func (pool *baseEvidencePool) Evidences() consensus.Evidences {
 	return evds
 }
 
+// SubscribeNewEvidence registers a subscription for the duplicate evidence
+// recorded to the database from now on.
+func (pool *baseEvidencePool) SubscribeNewEvidence(ch chan<- NewEvidenceEvent) event.Subscription {
+	return pool.scope.Track(pool.feed.Subscribe(ch))
+}
+
+// decodeEvidence decodes an evidence stored under a key with the given prefix.
+func decodeEvidence(flag byte, data []byte) consensus.Evidence {
+	var e consensus.Evidence
+	switch flag {
+	case prepareDualPrefix:
+		e = new(DuplicatePrepareBlockEvidence)
+	case voteDualPrefix:
+		e = new(DuplicatePrepareVoteEvidence)
+	case viewDualPrefix:
+		e = new(DuplicateViewChangeEvidence)
+	case precommitDualPrefix:
+		e = new(DuplicatePrecommitEvidence)
+	default:
+		return nil
+	}
+	if err := rlp.DecodeBytes(data, e); err != nil {
+		return nil
+	}
+	return e
+}
+
+// evidenceTypePrefix returns the key prefix of the evidences of the given type.
+func evidenceTypePrefix(dupType consensus.EvidenceType) byte {
+	switch dupType {
+	case DuplicatePrepareBlockType:
+		return prepareDualPrefix
+	case DuplicatePrepareVoteType:
+		return voteDualPrefix
+	case DuplicateViewChangeType:
+		return viewDualPrefix
+	case DuplicatePreCommitType:
+		return precommitDualPrefix
+	}
+	return 0
+}
+
 // NewEvidences retrieves the duplicate evidence by parsing string
 func NewEvidences(data string) (consensus.Evidences, error) {
 	var eds EvidenceData
@@ -233,25 +300,25 @@ func NewEvidences(data string) (consensus.Evidences, error) {
 		}
 		res = append(res, e)
 	}
+	for _, e := range eds.DPC {
+		if !e.ValidateMsg() {
+			return nil, fmt.Errorf("invalid evidence data")
+		}
+		res = append(res, e)
+	}
 	return res, nil
 }
 
 // NewEvidences retrieves the duplicate evidence by parsing string
 func NewEvidence(dupType consensus.EvidenceType, data string) (consensus.Evidence, error) {
-	var d consensus.Evidence
-	switch dupType {
-	case DuplicatePrepareBlockType:
-		d = new(DuplicatePrepareBlockEvidence)
-
-	case DuplicatePrepareVoteType:
-		d = new(DuplicatePrepareVoteEvidence)
-
-	case DuplicateViewChangeType:
-		d = new(DuplicateViewChangeEvidence)
-
-	default:
+	// Duplicate PreCommits can be queried but are not slashable evidence.
+	if dupType == DuplicatePreCommitType {
 		return nil, fmt.Errorf("invalid param dupType:%d", dupType)
 	}
+	d, err := newEvidenceOfType(dupType)
+	if err != nil {
+		return nil, err
+	}
 	// unmarshal evidence data
 	if err := json.Unmarshal([]byte(data), &d); err != nil {
 		return nil, err
@@ -271,6 +338,7 @@ func (pool *baseEvidencePool) Clear(epoch uint64, blockNumber uint64) {
 }
 
 func (pool *baseEvidencePool) Close() {
+	pool.scope.Close()
 	pool.db.Close()
 }
 
@@ -299,6 +367,8 @@ func encodeKey(e consensus.Evidence, id Identity) []byte {
 		buf.WriteByte(voteDualPrefix)
 	case *DuplicateViewChangeEvidence:
 		buf.WriteByte(viewDualPrefix)
+	case *DuplicatePrecommitEvidence:
+		buf.WriteByte(precommitDualPrefix)
 	}
 
 	// epoch
@@ -328,7 +398,9 @@ func (ev *baseEvidencePool) commit(e consensus.Evidence, id Identity) error {
 	ok := false
 	if ok, err = ev.db.Has(key, nil); !ok {
 		if buf, err = rlp.EncodeToBytes(e); err == nil {
-			err = ev.db.Put(key, buf, &opt.WriteOptions{Sync: true})
+			if err = ev.db.Put(key, buf, &opt.WriteOptions{Sync: true}); err == nil {
+				ev.feed.Send(NewEvidenceEvent{Evidence: e})
+			}
 		}
 	}
 	return err
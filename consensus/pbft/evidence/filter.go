@@ -0,0 +1,55 @@
+package evidence
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/consensus"
+)
+
+// NewEvidenceInfo creates the RPC representation of e.
+func NewEvidenceInfo(e consensus.Evidence) (*consensus.EvidenceInfo, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return &consensus.EvidenceInfo{
+		Type:        e.Type(),
+		Hash:        e.Hash(),
+		Epoch:       e.Epoch(),
+		ViewNumber:  e.ViewNumber(),
+		BlockNumber: e.BlockNumber(),
+		NodeID:      e.NodeID(),
+		Evidence:    data,
+	}, nil
+}
+
+// DecodeEvidenceInfo decodes the evidence carried by info into the concrete
+// type given by its type field, one of DuplicatePrepareBlockEvidence,
+// DuplicatePrepareVoteEvidence, DuplicateViewChangeEvidence or
+// DuplicatePrecommitEvidence.
+func DecodeEvidenceInfo(info *consensus.EvidenceInfo) (consensus.Evidence, error) {
+	e, err := newEvidenceOfType(info.Type)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(info.Evidence, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// newEvidenceOfType returns an empty evidence of the given type.
+func newEvidenceOfType(dupType consensus.EvidenceType) (consensus.Evidence, error) {
+	switch dupType {
+	case DuplicatePrepareBlockType:
+		return new(DuplicatePrepareBlockEvidence), nil
+	case DuplicatePrepareVoteType:
+		return new(DuplicatePrepareVoteEvidence), nil
+	case DuplicateViewChangeType:
+		return new(DuplicateViewChangeEvidence), nil
+	case DuplicatePreCommitType:
+		return new(DuplicatePrecommitEvidence), nil
+	}
+	return nil, fmt.Errorf("invalid param dupType:%d", dupType)
+}
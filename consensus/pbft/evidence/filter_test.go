@@ -0,0 +1,124 @@
+package evidence
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/consensus"
+)
+
+func TestQueryAndSubscribe(t *testing.T) {
+	p := path()
+	defer os.RemoveAll(p)
+	pool, err := NewBaseEvidencePool(p)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer pool.Close()
+
+	evCh := make(chan NewEvidenceEvent, 4)
+	sub := pool.SubscribeNewEvidence(evCh)
+	defer sub.Unsubscribe()
+
+	validateNodes, secretKeys := createValidateNode(2)
+	for i, node := range validateNodes {
+		epoch := uint64(i + 1)
+		block := newBlock(int64(i + 1))
+		pv := makePrepareVote(epoch, 1, block.Hash(), block.NumberU64(), 1, node.Index, t, secretKeys[i])
+		assert.Nil(t, pool.AddPrepareVote(pv, node))
+		block = newBlock(int64(i + 1))
+		pv = makePrepareVote(epoch, 1, block.Hash(), block.NumberU64(), 1, node.Index, t, secretKeys[i])
+		assert.IsType(t, &DuplicatePrepareVoteEvidence{}, pool.AddPrepareVote(pv, node))
+
+		select {
+		case ev := <-evCh:
+			assert.Equal(t, node.NodeID, ev.Evidence.NodeID())
+		case <-time.After(time.Second):
+			t.Fatal("new evidence not notified")
+		}
+	}
+	block := newBlock(2)
+	pc := makePreCommit(2, 1, block.Hash(), block.NumberU64(), 1, validateNodes[1].Index, t, secretKeys[1])
+	assert.Nil(t, pool.AddPreCommit(pc, validateNodes[1]))
+	block = newBlock(2)
+	pc = makePreCommit(2, 1, block.Hash(), block.NumberU64(), 1, validateNodes[1].Index, t, secretKeys[1])
+	assert.IsType(t, &DuplicatePrecommitEvidence{}, pool.AddPreCommit(pc, validateNodes[1]))
+	select {
+	case ev := <-evCh:
+		assert.Equal(t, DuplicatePreCommitType, ev.Evidence.Type())
+	case <-time.After(time.Second):
+		t.Fatal("new evidence not notified")
+	}
+
+	block = newBlock(1)
+	pb := makePrepareBlock(1, 1, block, 1, validateNodes[0].Index, t, secretKeys[0])
+	assert.Nil(t, pool.AddPrepareBlock(pb, validateNodes[0]))
+	pb = makePrepareBlock(1, 1, newBlock(1), 1, validateNodes[0].Index, t, secretKeys[0])
+	assert.IsType(t, &DuplicatePrepareBlockEvidence{}, pool.AddPrepareBlock(pb, validateNodes[0]))
+
+	assert.Len(t, pool.Query(nil), 4)
+	assert.Len(t, pool.Query(&consensus.EvidenceFilter{Type: DuplicatePrepareVoteType}), 2)
+	assert.Len(t, pool.Query(&consensus.EvidenceFilter{Type: DuplicateViewChangeType}), 0)
+	evs := pool.Query(&consensus.EvidenceFilter{Type: DuplicatePreCommitType})
+	if assert.Len(t, evs, 1) {
+		assert.Equal(t, validateNodes[1].NodeID, evs[0].NodeID())
+		info, err := NewEvidenceInfo(evs[0])
+		assert.Nil(t, err)
+		decoded, err := DecodeEvidenceInfo(info)
+		if assert.Nil(t, err) {
+			assert.Equal(t, evs[0].Hash(), decoded.Hash())
+		}
+		// The slashing contract doesn't decode duplicate PreCommits.
+		data, err := json.Marshal(evs[0])
+		assert.Nil(t, err)
+		_, err = NewEvidence(DuplicatePreCommitType, string(data))
+		assert.NotNil(t, err)
+	}
+
+	epoch := uint64(2)
+	evs = pool.Query(&consensus.EvidenceFilter{Type: DuplicatePrepareVoteType, Epoch: &epoch})
+	if assert.Len(t, evs, 1) {
+		assert.Equal(t, validateNodes[1].NodeID, evs[0].NodeID())
+	}
+	evs = pool.Query(&consensus.EvidenceFilter{NodeID: &validateNodes[0].NodeID})
+	assert.Len(t, evs, 2)
+
+	from, to := uint64(2), uint64(5)
+	evs = pool.Query(&consensus.EvidenceFilter{FromBlock: &from, ToBlock: &to})
+	if assert.Len(t, evs, 2) {
+		assert.Equal(t, uint64(2), evs[0].BlockNumber())
+		assert.Equal(t, uint64(2), evs[1].BlockNumber())
+	}
+}
+
+func TestEvidenceInfoJSON(t *testing.T) {
+	validateNodes, secretKeys := createValidateNode(1)
+	block := newBlock(1)
+	vc := makeViewChange(1, 1, block.Hash(), block.NumberU64(), validateNodes[0].Index, t, secretKeys[0])
+	viewA, _ := NewEvidenceView(vc, validateNodes[0])
+	block = newBlock(1)
+	vc = makeViewChange(1, 1, block.Hash(), block.NumberU64(), validateNodes[0].Index, t, secretKeys[0])
+	viewB, _ := NewEvidenceView(vc, validateNodes[0])
+
+	ev := &DuplicateViewChangeEvidence{ViewA: viewA, ViewB: viewB}
+	info, err := NewEvidenceInfo(ev)
+	assert.Nil(t, err)
+	b, err := json.Marshal(info)
+	assert.Nil(t, err)
+
+	var decoded consensus.EvidenceInfo
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, DuplicateViewChangeType, decoded.Type)
+	assert.Equal(t, validateNodes[0].NodeID, decoded.NodeID)
+	e, err := DecodeEvidenceInfo(&decoded)
+	assert.Nil(t, err)
+	if assert.IsType(t, &DuplicateViewChangeEvidence{}, e) {
+		assert.Equal(t, ev.Hash(), e.Hash())
+		assert.Nil(t, e.Validate())
+	}
+}
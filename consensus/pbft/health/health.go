@@ -0,0 +1,496 @@
+// Package health implements tracking the participation of the validators in
+// the pbft consensus over a rolling window of committed blocks.
+package health
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/consensus"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/metrics"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+var (
+	// Committed block record prefix
+	blockPrefix = []byte("b")
+	// Finished view record prefix
+	viewPrefix = []byte("v")
+)
+
+var (
+	blocksGauge      = metrics.NewRegisteredGauge("pbft/health/blocks", nil)
+	viewChangesGauge = metrics.NewRegisteredGauge("pbft/health/view_changes", nil)
+	timeToQCGauge    = metrics.NewRegisteredGauge("pbft/health/time_to_qc", nil)
+)
+
+// validatorGauges are the names of the gauges updated for every validator.
+var validatorGauges = []string{"prepare_votes", "precommits", "proposals", "missed_proposals", "view_changes", "time_to_qc"}
+
+// BlockRecord records the participation in a block.
+type BlockRecord struct {
+	Number        uint64            `json:"number"`
+	Hash          common.Hash       `json:"hash"`
+	Epoch         uint64            `json:"epoch"`
+	ViewNumber    uint64            `json:"viewNumber"`
+	Proposer      discover.NodeID   `json:"proposer"`
+	PrepareVotes  []discover.NodeID `json:"prepareVotes"`
+	PreCommits    []discover.NodeID `json:"preCommits"`
+	ProposedAt    uint64            `json:"proposedAt"`    // Unix time in milliseconds
+	PrepareQCAt   uint64            `json:"prepareQCAt"`   // Unix time in milliseconds
+	PreCommitQCAt uint64            `json:"preCommitQCAt"` // Unix time in milliseconds
+}
+
+// TimeToQC returns the milliseconds from the proposal of the block to its
+// pre-commit quorum certificate, zero if either wasn't seen by the node.
+func (r *BlockRecord) TimeToQC() uint64 {
+	if r.ProposedAt == 0 || r.PreCommitQCAt < r.ProposedAt {
+		return 0
+	}
+	return r.PreCommitQCAt - r.ProposedAt
+}
+
+// ViewRecord records the view changes of a view.
+type ViewRecord struct {
+	Epoch       uint64            `json:"epoch"`
+	ViewNumber  uint64            `json:"viewNumber"`
+	BlockNumber uint64            `json:"blockNumber"` // Block the view was producing
+	MissedBy    *discover.NodeID  `json:"missedBy" rlp:"nil"`
+	ViewChanges []discover.NodeID `json:"viewChanges"`
+}
+
+// Tracker records the consensus messages of the pending blocks and keeps the
+// records of the last window committed blocks, in a database if a path is
+// given. All methods are safe to call on a nil tracker.
+type Tracker struct {
+	window uint64
+	db     *leveldb.DB
+	now    func() time.Time
+
+	lock         sync.Mutex
+	pending      map[common.Hash]*BlockRecord
+	pendingViews map[viewKey]*ViewRecord
+	blocks       map[uint64]*BlockRecord
+	views        map[viewKey]*ViewRecord
+	head         uint64
+	gauges       map[discover.NodeID]bool
+}
+
+type viewKey struct {
+	epoch, view uint64
+}
+
+// NewTracker creates a tracker keeping the records of window blocks, and opens
+// the database at path to persist them unless path is empty.
+func NewTracker(path string, window uint64) (*Tracker, error) {
+	t := &Tracker{
+		window:       window,
+		now:          time.Now,
+		pending:      make(map[common.Hash]*BlockRecord),
+		pendingViews: make(map[viewKey]*ViewRecord),
+		blocks:       make(map[uint64]*BlockRecord),
+		views:        make(map[viewKey]*ViewRecord),
+		gauges:       make(map[discover.NodeID]bool),
+	}
+	if path == "" {
+		return t, nil
+	}
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	t.db = db
+	t.load()
+	return t, nil
+}
+
+// load reads the records of the window from the database.
+func (t *Tracker) load() {
+	it := t.db.NewIterator(util.BytesPrefix(blockPrefix), nil)
+	for it.Next() {
+		var record BlockRecord
+		if err := rlp.DecodeBytes(it.Value(), &record); err != nil {
+			log.Warn("Failed to decode consensus health record", "key", common.Bytes2Hex(it.Key()), "err", err)
+			continue
+		}
+		t.blocks[record.Number] = &record
+		if record.Number > t.head {
+			t.head = record.Number
+		}
+	}
+	it.Release()
+
+	it = t.db.NewIterator(util.BytesPrefix(viewPrefix), nil)
+	for it.Next() {
+		var record ViewRecord
+		if err := rlp.DecodeBytes(it.Value(), &record); err != nil {
+			log.Warn("Failed to decode consensus health record", "key", common.Bytes2Hex(it.Key()), "err", err)
+			continue
+		}
+		t.views[viewKey{record.Epoch, record.ViewNumber}] = &record
+	}
+	it.Release()
+	t.trim()
+}
+
+func (t *Tracker) pendingBlock(hash common.Hash) *BlockRecord {
+	record, ok := t.pending[hash]
+	if !ok {
+		record = &BlockRecord{Hash: hash}
+		t.pending[hash] = record
+	}
+	return record
+}
+
+func (t *Tracker) pendingView(epoch, view, blockNumber uint64) *ViewRecord {
+	key := viewKey{epoch, view}
+	record, ok := t.pendingViews[key]
+	if !ok {
+		record = &ViewRecord{Epoch: epoch, ViewNumber: view, BlockNumber: blockNumber}
+		t.pendingViews[key] = record
+	}
+	return record
+}
+
+func (t *Tracker) millis() uint64 {
+	return uint64(common.Millis(t.now()))
+}
+
+// AddPrepareBlock records the proposal of a block.
+func (t *Tracker) AddPrepareBlock(pb *protocols.PrepareBlock, node *pbfttypes.ValidateNode) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	record := t.pendingBlock(pb.Block.Hash())
+	if record.ProposedAt == 0 {
+		record.Number = pb.Block.NumberU64()
+		record.Epoch = pb.Epoch
+		record.ViewNumber = pb.ViewNumber
+		record.Proposer = node.NodeID
+		record.ProposedAt = t.millis()
+	}
+}
+
+// AddPrepareVote records a prepare vote for a block.
+func (t *Tracker) AddPrepareVote(pv *protocols.PrepareVote, node *pbfttypes.ValidateNode) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	record := t.pendingBlock(pv.BlockHash)
+	record.Number = pv.BlockNumber
+	record.PrepareVotes = addNode(record.PrepareVotes, node.NodeID)
+}
+
+// AddPreCommit records a pre-commit for a block.
+func (t *Tracker) AddPreCommit(pc *protocols.PreCommit, node *pbfttypes.ValidateNode) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	record := t.pendingBlock(pc.BlockHash)
+	record.Number = pc.BlockNumber
+	record.PreCommits = addNode(record.PreCommits, node.NodeID)
+}
+
+// AddViewChange records a view change sent by a validator.
+func (t *Tracker) AddViewChange(vc *protocols.ViewChange, node *pbfttypes.ValidateNode) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	record := t.pendingView(vc.Epoch, vc.ViewNumber, vc.BlockNumber+1)
+	record.ViewChanges = addNode(record.ViewChanges, node.NodeID)
+}
+
+// AddMissedProposal records that the proposer of the view didn't propose the
+// block before the view changed.
+func (t *Tracker) AddMissedProposal(epoch, view, blockNumber uint64, proposer discover.NodeID) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	record := t.pendingView(epoch, view, blockNumber)
+	record.MissedBy = &proposer
+}
+
+// AddPrepareQC records the prepare quorum certificate of a block.
+func (t *Tracker) AddPrepareQC(qc *ctypes.QuorumCert) {
+	if t == nil || qc == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if record := t.pendingBlock(qc.BlockHash); record.PrepareQCAt == 0 {
+		record.Number = qc.BlockNumber
+		record.PrepareQCAt = t.millis()
+	}
+}
+
+// AddPreCommitQC records the pre-commit quorum certificate of a block.
+func (t *Tracker) AddPreCommitQC(qc *ctypes.QuorumCert) {
+	if t == nil || qc == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if record := t.pendingBlock(qc.BlockHash); record.PreCommitQCAt == 0 {
+		record.Number = qc.BlockNumber
+		record.PreCommitQCAt = t.millis()
+	}
+}
+
+// Commit moves the records of the committed block and the views finished up
+// to it into the window, and drops the records of the blocks it replaced.
+func (t *Tracker) Commit(number uint64, hash common.Hash) {
+	if t == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	record, ok := t.pending[hash]
+	if !ok {
+		record = &BlockRecord{Number: number, Hash: hash}
+	}
+	record.Number = number
+	t.blocks[number] = record
+	t.put(blockKey(number), record)
+	for h, r := range t.pending {
+		if r.Number <= number {
+			delete(t.pending, h)
+		}
+	}
+	for key, r := range t.pendingViews {
+		if r.BlockNumber <= number {
+			t.views[key] = r
+			t.put(viewDBKey(key), r)
+			delete(t.pendingViews, key)
+		}
+	}
+	if number > t.head {
+		t.head = number
+	}
+	t.trim()
+	t.updateMetrics()
+}
+
+func (t *Tracker) put(key []byte, record interface{}) {
+	if t.db == nil {
+		return
+	}
+	buf, err := rlp.EncodeToBytes(record)
+	if err == nil {
+		err = t.db.Put(key, buf, nil)
+	}
+	if err != nil {
+		log.Warn("Failed to store consensus health record", "key", common.Bytes2Hex(key), "err", err)
+	}
+}
+
+func (t *Tracker) delete(key []byte) {
+	if t.db == nil {
+		return
+	}
+	if err := t.db.Delete(key, nil); err != nil {
+		log.Warn("Failed to delete consensus health record", "key", common.Bytes2Hex(key), "err", err)
+	}
+}
+
+// trim drops the records older than the window.
+func (t *Tracker) trim() {
+	if t.head < t.window {
+		return
+	}
+	oldest := t.head - t.window + 1
+	for number := range t.blocks {
+		if number < oldest {
+			delete(t.blocks, number)
+			t.delete(blockKey(number))
+		}
+	}
+	for key, record := range t.views {
+		if record.BlockNumber < oldest {
+			delete(t.views, key)
+			t.delete(viewDBKey(key))
+		}
+	}
+}
+
+// Report summarizes the window, the validators given are listed first in the
+// given order followed by the others seen in the window.
+func (t *Tracker) Report(validators []discover.NodeID) *consensus.HealthReport {
+	if t == nil {
+		return &consensus.HealthReport{Validators: []*consensus.ValidatorHealth{}}
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.report(validators)
+}
+
+func (t *Tracker) report(validators []discover.NodeID) *consensus.HealthReport {
+	report := &consensus.HealthReport{Validators: make([]*consensus.ValidatorHealth, 0, len(validators))}
+	reports := make(map[discover.NodeID]*consensus.ValidatorHealth)
+	get := func(id discover.NodeID) *consensus.ValidatorHealth {
+		r, ok := reports[id]
+		if !ok {
+			r = &consensus.ValidatorHealth{NodeID: id}
+			reports[id] = r
+		}
+		return r
+	}
+	for _, id := range validators {
+		get(id).Validator = true
+	}
+
+	var (
+		totalTime, timed uint64
+		times            = make(map[discover.NodeID][2]uint64)
+	)
+	for _, record := range t.blocks {
+		if report.From == 0 || record.Number < report.From {
+			report.From = record.Number
+		}
+		if record.Number > report.To {
+			report.To = record.Number
+		}
+		report.Blocks++
+		for _, id := range record.PrepareVotes {
+			get(id).PrepareVotes++
+		}
+		for _, id := range record.PreCommits {
+			get(id).PreCommits++
+		}
+		if record.ProposedAt == 0 {
+			continue
+		}
+		get(record.Proposer).Proposals++
+		if ttq := record.TimeToQC(); ttq > 0 {
+			totalTime += ttq
+			timed++
+			sum := times[record.Proposer]
+			times[record.Proposer] = [2]uint64{sum[0] + ttq, sum[1] + 1}
+		}
+	}
+	if timed > 0 {
+		report.AvgTimeToQC = totalTime / timed
+	}
+	for id, sum := range times {
+		get(id).AvgTimeToQC = sum[0] / sum[1]
+	}
+	for _, record := range t.views {
+		report.ViewChanges++
+		if record.MissedBy != nil {
+			get(*record.MissedBy).MissedProposals++
+		}
+		for _, id := range record.ViewChanges {
+			get(id).ViewChanges++
+		}
+	}
+
+	for _, id := range validators {
+		if r, ok := reports[id]; ok {
+			report.Validators = append(report.Validators, r)
+			delete(reports, id)
+		}
+	}
+	others := make([]*consensus.ValidatorHealth, 0, len(reports))
+	for _, r := range reports {
+		others = append(others, r)
+	}
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].NodeID.String() < others[j].NodeID.String()
+	})
+	report.Validators = append(report.Validators, others...)
+	return report
+}
+
+// updateMetrics updates the gauges of the window.
+func (t *Tracker) updateMetrics() {
+	if !metrics.Enabled {
+		return
+	}
+	report := t.report(nil)
+	blocksGauge.Update(int64(report.Blocks))
+	viewChangesGauge.Update(int64(report.ViewChanges))
+	timeToQCGauge.Update(int64(report.AvgTimeToQC))
+
+	seen := make(map[discover.NodeID]bool, len(report.Validators))
+	for _, r := range report.Validators {
+		seen[r.NodeID] = true
+		values := []uint64{r.PrepareVotes, r.PreCommits, r.Proposals, r.MissedProposals, r.ViewChanges, r.AvgTimeToQC}
+		for i, name := range validatorGauges {
+			metrics.GetOrRegisterGauge(gaugeName(r.NodeID, name), nil).Update(int64(values[i]))
+		}
+	}
+	// Validators which left the window aren't reported anymore.
+	for id := range t.gauges {
+		if !seen[id] {
+			for _, name := range validatorGauges {
+				metrics.Unregister(gaugeName(id, name))
+			}
+		}
+	}
+	t.gauges = seen
+}
+
+func gaugeName(id discover.NodeID, name string) string {
+	return "pbft/health/validator/" + id.TerminalString() + "/" + name
+}
+
+// Close closes the database.
+func (t *Tracker) Close() error {
+	if t == nil || t.db == nil {
+		return nil
+	}
+	return t.db.Close()
+}
+
+func addNode(nodes []discover.NodeID, id discover.NodeID) []discover.NodeID {
+	for _, n := range nodes {
+		if n == id {
+			return nodes
+		}
+	}
+	return append(nodes, id)
+}
+
+func blockKey(number uint64) []byte {
+	key := make([]byte, len(blockPrefix)+8)
+	copy(key, blockPrefix)
+	binary.BigEndian.PutUint64(key[len(blockPrefix):], number)
+	return key
+}
+
+func viewDBKey(key viewKey) []byte {
+	buf := make([]byte, len(viewPrefix)+16)
+	copy(buf, viewPrefix)
+	binary.BigEndian.PutUint64(buf[len(viewPrefix):], key.epoch)
+	binary.BigEndian.PutUint64(buf[len(viewPrefix)+8:], key.view)
+	return buf
+}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/consensus"
+)
+
+func newTestNodes(n int) []*pbfttypes.ValidateNode {
+	nodes := make([]*pbfttypes.ValidateNode, n)
+	for i := range nodes {
+		nodes[i] = &pbfttypes.ValidateNode{Index: uint32(i), NodeID: discover.NodeID{byte(i + 1)}}
+	}
+	return nodes
+}
+
+// produce runs the consensus of a block proposed by the given validator, which
+// reaches its quorum certificates after the given milliseconds.
+func produce(t *Tracker, clock *time.Time, number uint64, proposer *pbfttypes.ValidateNode, voters []*pbfttypes.ValidateNode, ttq int64) {
+	block := types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(number)})
+	t.AddPrepareBlock(&protocols.PrepareBlock{Epoch: 1, ViewNumber: number, Block: block}, proposer)
+	for _, node := range voters {
+		t.AddPrepareVote(&protocols.PrepareVote{BlockHash: block.Hash(), BlockNumber: number}, node)
+		// Votes received again are counted once.
+		t.AddPrepareVote(&protocols.PrepareVote{BlockHash: block.Hash(), BlockNumber: number}, node)
+		t.AddPreCommit(&protocols.PreCommit{BlockHash: block.Hash(), BlockNumber: number}, node)
+	}
+	*clock = clock.Add(time.Duration(ttq) * time.Millisecond)
+	qc := &ctypes.QuorumCert{BlockHash: block.Hash(), BlockNumber: number}
+	t.AddPrepareQC(qc)
+	t.AddPreCommitQC(qc)
+	t.Commit(number, block.Hash())
+}
+
+func TestTracker(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tracker, err := NewTracker(dir, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := time.Unix(1000, 0)
+	tracker.now = func() time.Time { return clock }
+
+	nodes := newTestNodes(4)
+	ids := []discover.NodeID{nodes[0].NodeID, nodes[1].NodeID, nodes[2].NodeID, nodes[3].NodeID}
+	for number := uint64(1); number <= 3; number++ {
+		produce(tracker, &clock, number, nodes[0], nodes[:3], 100)
+	}
+
+	// The second validator misses its turn, the view changes without it.
+	for _, node := range nodes[:3] {
+		tracker.AddViewChange(&protocols.ViewChange{Epoch: 1, ViewNumber: 4, BlockNumber: 3}, node)
+	}
+	tracker.AddMissedProposal(1, 4, 4, nodes[1].NodeID)
+	produce(tracker, &clock, 4, nodes[2], nodes[:3], 300)
+	// A block replaced by the committed one is dropped.
+	tracker.AddPrepareVote(&protocols.PrepareVote{BlockNumber: 4}, nodes[3])
+	produce(tracker, &clock, 5, nodes[0], nodes[:3], 200)
+
+	report := tracker.Report(ids)
+	assert.Equal(t, uint64(2), report.From)
+	assert.Equal(t, uint64(5), report.To)
+	assert.Equal(t, uint64(4), report.Blocks)
+	assert.Equal(t, uint64(1), report.ViewChanges)
+	assert.Equal(t, uint64(175), report.AvgTimeToQC)
+	assert.Empty(t, tracker.pending)
+
+	assert.Equal(t, 4, len(report.Validators))
+	assert.Equal(t, &consensus.ValidatorHealth{NodeID: ids[0], Validator: true, PrepareVotes: 4, PreCommits: 4, Proposals: 3, ViewChanges: 1, AvgTimeToQC: 133}, report.Validators[0])
+	assert.Equal(t, &consensus.ValidatorHealth{NodeID: ids[1], Validator: true, PrepareVotes: 4, PreCommits: 4, MissedProposals: 1, ViewChanges: 1}, report.Validators[1])
+	assert.Equal(t, &consensus.ValidatorHealth{NodeID: ids[2], Validator: true, PrepareVotes: 4, PreCommits: 4, Proposals: 1, ViewChanges: 1, AvgTimeToQC: 300}, report.Validators[2])
+	assert.Equal(t, &consensus.ValidatorHealth{NodeID: ids[3], Validator: true}, report.Validators[3])
+
+	// Validators which are not in the epoch anymore are listed last.
+	report = tracker.Report(ids[1:2])
+	assert.Equal(t, ids[1], report.Validators[0].NodeID)
+	assert.Equal(t, 3, len(report.Validators))
+	assert.False(t, report.Validators[1].Validator)
+
+	// The window survives a restart.
+	expect := tracker.Report(ids)
+	assert.Nil(t, tracker.Close())
+	tracker, err = NewTracker(dir, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expect, tracker.Report(ids))
+
+	// The view change leaves the window with its block.
+	tracker.now = func() time.Time { return clock }
+	produce(tracker, &clock, 6, nodes[0], nodes[:3], 100)
+	produce(tracker, &clock, 7, nodes[0], nodes[:3], 100)
+	report = tracker.Report(ids)
+	assert.Equal(t, uint64(4), report.From)
+	assert.Equal(t, uint64(1), report.ViewChanges)
+	produce(tracker, &clock, 8, nodes[0], nodes[:3], 100)
+	report = tracker.Report(ids)
+	assert.Equal(t, uint64(0), report.ViewChanges)
+	assert.Equal(t, uint64(0), report.Validators[1].MissedProposals)
+	assert.Nil(t, tracker.Close())
+}
+
+func TestNilTracker(t *testing.T) {
+	var tracker *Tracker
+	tracker.AddPreCommitQC(&ctypes.QuorumCert{})
+	tracker.Commit(1, [32]byte{})
+	assert.Empty(t, tracker.Report(nil).Validators)
+	assert.Nil(t, tracker.Close())
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/golang-lru"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/mclock"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
@@ -712,6 +713,14 @@ func (h *EngineManager) RemovePeer(id string) {
 	}
 }
 
+// clock returns the clock of the synchronization timers.
+func (h *EngineManager) clock() mclock.Clock {
+	if clock := h.engine.Config().Option.Clock; clock != nil {
+		return clock
+	}
+	return mclock.System{}
+}
+
 // Select a node with a height higher than the local node block from
 // the neighbor node list, and then synchronize the block data of
 // the height difference to the node.
@@ -722,11 +731,12 @@ func (h *EngineManager) RemovePeer(id string) {
 // 3. Synchronous blocks with inconsistent commit block height.
 func (h *EngineManager) synchronize() {
 	log.Debug("~ Start synchronize in the handler")
-	blockNumberTimer := time.NewTimer(QCBnMonitorInterval * time.Second)
-	viewTicker := time.NewTicker(SyncViewChangeInterval * time.Second)
-	pureBlacklistTicker := time.NewTicker(removeBlacklistInterval * time.Second)
-	voteTicker := time.NewTicker(SyncPrepareVoteInterval * time.Second)
-	preCommitTicker := time.NewTicker(SyncPreCommitInterval * time.Millisecond)
+	clock := h.clock()
+	blockNumberTimer := clock.NewTimer(QCBnMonitorInterval * time.Second)
+	viewTicker := clock.NewTimer(SyncViewChangeInterval * time.Second)
+	pureBlacklistTicker := clock.NewTimer(removeBlacklistInterval * time.Second)
+	voteTicker := clock.NewTimer(SyncPrepareVoteInterval * time.Second)
+	preCommitTicker := clock.NewTimer(SyncPreCommitInterval * time.Millisecond)
 
 	// Logic used to synchronize QC.
 	syncQCBnFunc := func() {
@@ -738,7 +748,8 @@ func (h *EngineManager) synchronize() {
 
 	for {
 		select {
-		case <-voteTicker.C:
+		case <-voteTicker.C():
+			voteTicker.Reset(SyncPrepareVoteInterval * time.Second)
 			msg, err := h.engine.MissingPrepareVote()
 			if err != nil {
 				log.Debug("Request missing prepareVote failed", "err", err)
@@ -748,7 +759,8 @@ func (h *EngineManager) synchronize() {
 			// Only broadcasts without forwarding.
 			h.PartBroadcast(msg)
 
-		case <-preCommitTicker.C:
+		case <-preCommitTicker.C():
+			preCommitTicker.Reset(SyncPreCommitInterval * time.Millisecond)
 			msg, err := h.engine.MissingPreCommit()
 			if err != nil {
 				log.Debug("Request missing preCommit failed", "err", err)
@@ -758,7 +770,7 @@ func (h *EngineManager) synchronize() {
 			// Only broadcasts without forwarding.
 			h.PartBroadcast(msg)
 
-		case <-blockNumberTimer.C:
+		case <-blockNumberTimer.C():
 			// Sent at random.
 			syncQCBnFunc()
 			rd := rand.Intn(5)
@@ -768,7 +780,8 @@ func (h *EngineManager) synchronize() {
 			resetTime := time.Duration(rd) * time.Second
 			blockNumberTimer.Reset(resetTime)
 
-		case <-viewTicker.C:
+		case <-viewTicker.C():
+			viewTicker.Reset(SyncViewChangeInterval * time.Second)
 			// If the local viewChange has insufficient votes,
 			// the GetViewChange message is sent from the missing node.
 			msg, err := h.engine.MissingViewChangeNodes()
@@ -780,7 +793,8 @@ func (h *EngineManager) synchronize() {
 			// Only broadcasts without forwarding.
 			h.PartBroadcast(msg)
 
-		case <-pureBlacklistTicker.C:
+		case <-pureBlacklistTicker.C():
+			pureBlacklistTicker.Reset(removeBlacklistInterval * time.Second)
 			// Iterate over the blacklist and remove
 			// the nodes that have expired.
 			keys := h.blacklist.Keys()
@@ -800,6 +814,11 @@ func (h *EngineManager) synchronize() {
 
 		case <-h.quitSend:
 			log.Warn("Synchronize quit")
+			blockNumberTimer.Stop()
+			viewTicker.Stop()
+			pureBlacklistTicker.Stop()
+			voteTicker.Stop()
+			preCommitTicker.Stop()
 			return
 		}
 	}
@@ -4,9 +4,9 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types/pbfttypes"
 	"bytes"
 	"container/list"
-	"crypto/elliptic"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"strings"
 	"sync/atomic"
 
@@ -27,9 +27,11 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/evidence"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/executor"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/fetcher"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/health"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/network"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/rules"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/signer"
 	cstate "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/state"
 	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/utils"
@@ -41,6 +43,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	commonconsensus "github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/consensus"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/event"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
@@ -99,12 +102,14 @@ type Pbft struct {
 	eventMux         *event.TypeMux
 	closeOnce        sync.Once
 	exitCh           chan struct{}
+	loopWG           sync.WaitGroup // Waits for the receive loop to exit on Close
 	txPool           consensus.TxPoolReset
 	blockChain       consensus.ChainReader
 	blockCacheWriter consensus.BlockCacheWriter
 	peerMsgCh        chan *ctypes.MsgInfo
 	syncMsgCh        chan *ctypes.MsgInfo
 	evPool           evidence.EvidencePool
+	health           *health.Tracker
 	log              log.Logger
 	network          *network.EngineManager
 
@@ -137,6 +142,10 @@ type Pbft struct {
 	// Validator pool
 	validatorPool *validator.ValidatorPool
 
+	// Proposer selection after the proposer rotation fork
+	weightedSelector   *weightedSelector
+	reputationSelector *reputationSelector
+
 	// Store blocks that are not committed
 	blockTree *ctypes.BlockTree
 
@@ -150,6 +159,7 @@ type Pbft struct {
 	loading                   int32
 	updateChainStateHook      pbfttypes.UpdateChainStateFn
 	updateChainStateDelayHook func(qcState, lockState, commitState *protocols.State)
+	sendQueueHook             func(*ctypes.MsgPackage)
 
 	// Record the number of peer requests for obtaining pbft information.
 	queues     map[string]int // Per peer message counts to prevent memory exhaustion.
@@ -192,6 +202,7 @@ func New(sysConfig *configs.PbftConfig, optConfig *ctypes.OptionsConfig, eventMu
 		statQueues:         make(map[common.Hash]map[string]int),
 		messageHashCache:   mapset.NewSet(),
 		netLatencyMap:      make(map[string]*list.List),
+		weightedSelector:   newWeightedSelector(),
 	}
 
 	if evPool, err := evidence.NewEvidencePool(ctx, optConfig.EvidenceDir); err == nil {
@@ -200,6 +211,43 @@ func New(sysConfig *configs.PbftConfig, optConfig *ctypes.OptionsConfig, eventMu
 		return nil
 	}
 
+	healthPath := ""
+	if ctx != nil && optConfig.HealthDir != "" {
+		healthPath = ctx.ResolvePath(optConfig.HealthDir)
+	}
+	if tracker, err := health.NewTracker(healthPath, optConfig.HealthWindow); err == nil {
+		pbft.health = tracker
+	} else {
+		pbft.log.Error("Failed to open consensus health database, keeping it in memory", "path", healthPath, "err", err)
+		pbft.health, _ = health.NewTracker("", optConfig.HealthWindow)
+	}
+
+	if optConfig.Signer == nil {
+		if optConfig.NodePriKey == nil {
+			if ctx == nil {
+				pbft.log.Error("No signer or node key configured for the local signer")
+				return nil
+			}
+			optConfig.NodePriKey = ctx.NodePriKey()
+			optConfig.NodeID = discover.PubkeyID(&optConfig.NodePriKey.PublicKey)
+		}
+		// The watermarks are kept in memory on nodes without a data directory.
+		var protection *signer.Protection
+		if optConfig.ProtectionFile != "" {
+			path := ""
+			if ctx != nil {
+				path = ctx.ResolvePath(optConfig.ProtectionFile)
+			}
+			p, err := signer.NewProtection(path)
+			if err != nil {
+				pbft.log.Error("Failed to load double signing protection", "path", path, "err", err)
+				return nil
+			}
+			protection = p
+		}
+		optConfig.Signer = signer.NewLocalSigner(optConfig.NodePriKey, optConfig.BlsPriKey, protection)
+	}
+
 	return pbft
 }
 
@@ -235,7 +283,7 @@ func (pbft *Pbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 	utils.SetTrue(&pbft.loading)
 
 	//Initialize view state
-	pbft.state = cstate.NewViewState(pbft.config.Sys.Period, pbft.blockTree)
+	pbft.state = cstate.NewViewState(pbft.config.Sys.Period, pbft.blockTree, pbft.config.Option.Clock)
 	pbft.state.SetHighestQCBlock(block)
 	pbft.state.SetHighestLockBlock(block)
 	pbft.state.SetHighestPreCommitQCBlock(block)
@@ -244,12 +292,15 @@ func (pbft *Pbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 	// init handler and router to process message.
 	// pbft -> handler -> router.
 	pbft.network = network.NewEngineManger(pbft) // init engineManager as handler.
+	pbft.network.SetSendQueueHook(pbft.sendQueueHook)
 	// Start the handler to process the message.
 	go pbft.network.Start()
 
-	if pbft.config.Option.NodePriKey == nil {
-		pbft.config.Option.NodePriKey = pbft.nodeServiceContext.NodePriKey()
-		pbft.config.Option.NodeID = discover.PubkeyID(&pbft.config.Option.NodePriKey.PublicKey)
+	pbft.config.Option.NodeID = pbft.config.Option.Signer.NodeID()
+
+	if pbft.config.Sys.ProposerReputation != nil {
+		pbft.reputationSelector = newReputationSelector(pbft.config.Sys.ProposerReputation, pbft.config.Sys.Amount,
+			pbft.weightedSelector, pbft.committedQC, pbft.committedValidators)
 	}
 
 	if isGenesis() {
@@ -289,6 +340,7 @@ func (pbft *Pbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 	}
 	utils.SetFalse(&pbft.loading)
 
+	pbft.loopWG.Add(1)
 	go pbft.receiveLoop()
 
 	pbft.fetcher.Start()
@@ -298,6 +350,13 @@ func (pbft *Pbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 	return nil
 }
 
+// SetSendQueueHook sets a function called with every message the engine sends
+// before it's passed to the peers, which carries the messages of engines running
+// without a p2p server. It has to be set before Start.
+func (pbft *Pbft) SetSendQueueHook(hook func(*ctypes.MsgPackage)) {
+	pbft.sendQueueHook = hook
+}
+
 // ReceiveMessage Entrance: The messages related to the consensus are entered from here.
 // The message sent from the peer node is sent to the PBFT message queue and
 // there is a loop that will distribute the incoming message.
@@ -481,6 +540,7 @@ func (pbft *Pbft) LoadWal() (err error) {
 
 // receiveLoop receives all consensus related messages, all processing logic in the same goroutine
 func (pbft *Pbft) receiveLoop() {
+	defer pbft.loopWG.Done()
 
 	// Responsible for handling consensus message logic.
 	consensusMessageHandler := func(msg *ctypes.MsgInfo) {
@@ -542,6 +602,9 @@ func (pbft *Pbft) receiveLoop() {
 			pbft.OnViewTimeout()
 		case err := <-pbft.commitErrCh:
 			pbft.OnCommitError(err)
+		case <-pbft.exitCh:
+			pbft.log.Debug("Pbft receive loop exit")
+			return
 		}
 	}
 }
@@ -741,7 +804,7 @@ func (pbft *Pbft) Seal(chain consensus.ChainReader, block *types.Block, results
 		return ErrorUnKnowBlock
 	}
 
-	sign, err := pbft.signFn(header.SealHash().Bytes())
+	sign, err := pbft.signFn(header)
 	if err != nil {
 		pbft.log.Error("Seal block sign fail", "number", block.Number(), "parentHash", block.ParentHash(), "err", err)
 		return err
@@ -764,7 +827,7 @@ func (pbft *Pbft) DealBlock(block *types.Block)(b *types.Block,e error) {
 		return
 	}
 
-	sign, err := pbft.signFn(header.SealHash().Bytes())
+	sign, err := pbft.signFn(header)
 	if err != nil {
 		pbft.log.Error("Seal block sign fail", "number", block.Number(), "parentHash", block.ParentHash(), "err", err)
 		e=err
@@ -793,10 +856,13 @@ func (pbft *Pbft) OnSeal(block *types.Block, results chan<- *types.Block, stop <
 		pbft.log.Warn("Can not got the validator, seal fail", "epoch", pbft.state.Epoch(), "nodeID", pbft.NodeID())
 		return
 	}
-	numValidators := pbft.validatorPool.Len(pbft.state.Epoch())
 	//currentProposer := pbft.state.BlockNumber() % uint64(numValidators)
 	//currentProposer := (pbft.state.BlockNumber()-1)% uint64(numValidators)
-	currentProposer := pbft.CalCurrentProposer(numValidators)
+	currentProposer, err := pbft.CalCurrentProposer(pbft.state.Epoch())
+	if err != nil {
+		pbft.log.Warn("Can not calculate the current proposer, seal fail", "number", pbft.state.BlockNumber(), "err", err)
+		return
+	}
 	if currentProposer != uint64(me.Index) {
 		pbft.log.Warn("You are not the current proposer", "index", me.Index, "currentProposer", currentProposer)
 		return
@@ -1009,6 +1075,22 @@ func (pbft *Pbft) Status() []byte {
 	return <-status
 }
 
+// Health returns the participation of the validators in the last committed
+// blocks, the validators of the current epoch are listed first.
+func (pbft *Pbft) Health() *commonconsensus.HealthReport {
+	result := make(chan *commonconsensus.HealthReport, 1)
+	pbft.asyncCallCh <- func() {
+		var ids []discover.NodeID
+		if validators := pbft.validatorPool.EpochValidators(pbft.state.Epoch()); validators != nil {
+			for _, node := range sortedValidators(validators) {
+				ids = append(ids, node.NodeID)
+			}
+		}
+		result <- pbft.health.Report(ids)
+	}
+	return <-result
+}
+
 // GetPrepareQC returns the QC data of the specified block height.
 func (pbft *Pbft) GetPrepareQC(number uint64) *ctypes.QuorumCert {
 	pbft.log.Debug("get prepare QC")
@@ -1120,11 +1202,19 @@ func (pbft *Pbft) Close() error {
 			return
 		}
 		close(pbft.exitCh)
+		if pbft.network != nil {
+			pbft.network.Close()
+		}
+		pbft.fetcher.Stop()
+		pbft.evPool.Close()
 	})
+	// The loop writes to the wal until it exits.
+	pbft.loopWG.Wait()
 	if pbft.asyncExecutor != nil {
 		pbft.asyncExecutor.Stop()
 	}
 	pbft.bridge.Close()
+	pbft.health.Close()
 	return nil
 }
 
@@ -1161,30 +1251,57 @@ func (pbft *Pbft) ShouldSeal(curTime time.Time) (bool, error) {
 	}
 }
 
-func (pbft *Pbft) CalCurrentProposer(numValidators int) uint64 {
-	var currentProposer uint64
-	//if pbft.IsAddBlockTimeOver(){
-	//	timeOverInterval:=time.Now().Unix()-pbft.state.LastAddBlockNumberTime()-cstate.AddBlockNumberTimeInterval
-	//	modInt:=(timeOverInterval/cstate.AddBlockNumberTimeInterval)% int64(numValidators)
-	//	currentProposer = (pbft.state.BlockNumber()+uint64(modInt)) % uint64(numValidators)
-	//	pbft.log.Debug("CalCurrentProposer,IsAddBlockTimeOver", "old currentProposer",  (pbft.state.BlockNumber()-1) % uint64(numValidators), "new currentProposer", currentProposer)
-	//}else {
-	//	currentProposer = (pbft.state.BlockNumber()-1) % uint64(numValidators)
-	//}
-	currentProposer = (pbft.state.BlockNumber()-1+pbft.state.ViewNumber()/10) % uint64(numValidators)
-	return currentProposer
+// CalCurrentProposer returns the index of the proposer of the current block
+// and view among the validators of the epoch.
+func (pbft *Pbft) CalCurrentProposer(epoch uint64) (uint64, error) {
+	return pbft.CalCurrentProposerWithBlockNumber(epoch, pbft.state.BlockNumber())
 }
 
-func (pbft *Pbft) CalCurrentProposerWithBlockNumber(blockNumber uint64,numValidators int) uint64 {
-	var currentProposer uint64
-	//if pbft.IsAddBlockTimeOver(){
-	//	currentProposer = blockNumber % uint64(numValidators)
-	//	pbft.log.Debug("CalCurrentProposerWithBlockNumber,IsAddBlockTimeOver", "old currentProposer",  (blockNumber-1) % uint64(numValidators), "new currentProposer", currentProposer)
-	//}else {
-	//	currentProposer = (blockNumber-1) % uint64(numValidators)
-	//}
-	currentProposer = (blockNumber-1+pbft.state.ViewNumber()/10) % uint64(numValidators)
-	return currentProposer
+// CalCurrentProposerWithBlockNumber returns the index of the proposer of the
+// block in the current view among the validators of the epoch.
+func (pbft *Pbft) CalCurrentProposerWithBlockNumber(epoch, blockNumber uint64) (uint64, error) {
+	validators := pbft.validatorPool.EpochValidators(epoch)
+	return pbft.proposerSelector(blockNumber).Proposer(validators, blockNumber, pbft.state.ViewNumber())
+}
+
+// proposerSelector returns the selector of the proposer of the block, which
+// switches to stake-weighted rotation with the proposer rotation fork.
+func (pbft *Pbft) proposerSelector(blockNumber uint64) ProposerSelector {
+	if !pbft.config.Sys.IsProposerRotation(new(big.Int).SetUint64(blockNumber)) {
+		return roundRobinSelector{}
+	}
+	if pbft.reputationSelector != nil {
+		return pbft.reputationSelector
+	}
+	return pbft.weightedSelector
+}
+
+// committedQC returns the quorum certificate of a committed block, or nil if
+// the block isn't committed.
+func (pbft *Pbft) committedQC(number uint64) *ctypes.QuorumCert {
+	header := pbft.blockChain.GetHeaderByNumber(number)
+	if header == nil {
+		return nil
+	}
+	block := pbft.blockChain.GetBlock(header.Hash(), number)
+	if block == nil {
+		return nil
+	}
+	_, qc, err := ctypes.DecodeExtra(block.ExtraData())
+	if err != nil {
+		return nil
+	}
+	return qc
+}
+
+// committedValidators returns the validators of a committed block, or nil if
+// they are not found.
+func (pbft *Pbft) committedValidators(number uint64) *pbfttypes.Validators {
+	validators, err := pbft.validatorPool.ValidatorsAt(number)
+	if err != nil {
+		return nil
+	}
+	return validators
 }
 
 // OnShouldSeal determines whether the current condition
@@ -1214,7 +1331,11 @@ func (pbft *Pbft) OnShouldSeal(result chan error) {
 
 	numValidators := pbft.validatorPool.Len(pbft.state.Epoch())
 	//currentProposer := (pbft.state.BlockNumber()-1) % uint64(numValidators)
-	currentProposer:=pbft.CalCurrentProposer(numValidators)
+	currentProposer, err := pbft.CalCurrentProposer(pbft.state.Epoch())
+	if err != nil {
+		result <- err
+		return
+	}
 	validator, err := pbft.validatorPool.GetValidatorByNodeID(pbft.state.Epoch(), pbft.config.Option.NodeID)
 	if err != nil {
 		pbft.log.Error("Should seal fail", "err", err)
@@ -1401,35 +1522,29 @@ func (pbft *Pbft) Evidences() string {
 	return string(js)
 }
 
+// EvidencePool returns the pool recording the duplicate signatures detected by the engine.
+func (pbft *Pbft) EvidencePool() evidence.EvidencePool {
+	return pbft.evPool
+}
+
 func (pbft *Pbft) verifySelfSigned(m []byte, sig []byte) bool {
 	recPubKey, err := crypto.Ecrecover(m, sig)
 	if err != nil {
 		return false
 	}
 
-	pubKey := pbft.config.Option.NodePriKey.PublicKey
-	pbytes := elliptic.Marshal(pubKey.Curve, pubKey.X, pubKey.Y)
-	return bytes.Equal(pbytes, recPubKey)
+	nodeID := pbft.config.Option.Signer.NodeID()
+	return bytes.Equal(nodeID[:], recPubKey[1:])
 }
 
-// signFn use private key to sign byte slice.
-func (pbft *Pbft) signFn(m []byte) ([]byte, error) {
-	return crypto.Sign(m, pbft.config.Option.NodePriKey)
-}
-
-// signFn use bls private key to sign byte slice.
-func (pbft *Pbft) signFnByBls(m []byte) ([]byte, error) {
-	sign := pbft.config.Option.BlsPriKey.Sign(string(m))
-	return sign.Serialize(), nil
+// signFn use the node key to sign the seal hash of the header.
+func (pbft *Pbft) signFn(header *types.Header) ([]byte, error) {
+	return pbft.config.Option.Signer.SignSeal(header)
 }
 
 // signMsg use bls private key to sign msg.
 func (pbft *Pbft) signMsgByBls(msg ctypes.ConsensusMsg) error {
-	buf, err := msg.CannibalizeBytes()
-	if err != nil {
-		return err
-	}
-	sign, err := pbft.signFnByBls(buf)
+	sign, err := pbft.config.Option.Signer.SignMsg(msg)
 	if err != nil {
 		return err
 	}
@@ -1449,24 +1564,28 @@ func (pbft *Pbft) isCurrentValidator() (*pbfttypes.ValidateNode, error) {
 	return pbft.validatorPool.GetValidatorByNodeID(pbft.state.Epoch(), pbft.config.Option.NodeID)
 }
 
-func (pbft *Pbft) currentProposer() *pbfttypes.ValidateNode {
-	length := pbft.validatorPool.Len(pbft.state.Epoch())
+func (pbft *Pbft) currentProposer() (*pbfttypes.ValidateNode, error) {
 	//currentProposer := pbft.state.BlockNumber() % uint64(length)
 	//currentProposer := (pbft.state.BlockNumber()-1) % uint64(length)
-	currentProposer:=pbft.CalCurrentProposer(length)
-	validator, _ := pbft.validatorPool.GetValidatorByIndex(pbft.state.Epoch(), uint32(currentProposer))
-	return validator
+	currentProposer, err := pbft.CalCurrentProposer(pbft.state.Epoch())
+	if err != nil {
+		return nil, err
+	}
+	return pbft.validatorPool.GetValidatorByIndex(pbft.state.Epoch(), uint32(currentProposer))
 }
 
 func (pbft *Pbft) isProposer(epoch, blockNumber uint64, nodeIndex uint32) bool {
 	if err := pbft.validatorPool.EnableVerifyEpoch(epoch); err != nil {
 		return false
 	}
-	length := pbft.validatorPool.Len(epoch)
 	var index uint64
 	if blockNumber>0{
 		//index = (blockNumber-1) % uint64(length)
-		index = pbft.CalCurrentProposerWithBlockNumber(blockNumber,length)
+		var err error
+		if index, err = pbft.CalCurrentProposerWithBlockNumber(epoch, blockNumber); err != nil {
+			pbft.log.Debug("Can not calculate the proposer", "epoch", epoch, "number", blockNumber, "err", err)
+			return false
+		}
 	}else {
 		index = 0
 	}
@@ -1628,7 +1747,10 @@ func (pbft *Pbft) verifyConsensusMsg(msg ctypes.ConsensusMsg) (*pbfttypes.Valida
 
 	switch cm := msg.(type) {
 	case *protocols.PrepareBlock:
-		proposer := pbft.currentProposer()
+		proposer, err := pbft.currentProposer()
+		if err != nil {
+			return nil, fmt.Errorf("can not calculate the current proposer: %v", err)
+		}
 		if uint32(proposer.Index) != msg.NodeIndex() {
 			return nil, fmt.Errorf("current proposer index:%d, prepare block author index:%d", proposer.Index, msg.NodeIndex())
 		}
@@ -1997,7 +2119,7 @@ func (pbft *Pbft) avgRTT() time.Duration {
 }
 
 func (pbft *Pbft) GetSchnorrNIZKProve() (*bls.SchnorrProof, error) {
-	return pbft.config.Option.BlsPriKey.MakeSchnorrNIZKP()
+	return pbft.config.Option.Signer.SchnorrNIZKProve()
 }
 
 func (pbft *Pbft) DecodeExtra(extra []byte) (common.Hash, uint64, error) {
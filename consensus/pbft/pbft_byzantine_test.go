@@ -145,7 +145,7 @@ func NewBadBlock(parent common.Hash, number uint64, node *TestPBFT) *types.Block
 		GasLimit:    10000000000,
 	}
 
-	sign, _ := node.engine.signFn(header.SealHash().Bytes())
+	sign, _ := node.engine.signFn(header)
 	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sign[:])
 
 	block := types.NewBlockWithHeader(header)
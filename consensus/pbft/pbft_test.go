@@ -17,6 +17,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/signer"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/state"
 	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/validator"
@@ -27,6 +28,8 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/node"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/event"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
 )
 
@@ -67,6 +70,7 @@ func TestBls(t *testing.T) {
 		config: ctypes.Config{
 			Option: &ctypes.OptionsConfig{
 				BlsPriKey: owner,
+				Signer:    signer.NewLocalSigner(pk[0], owner, nil),
 			},
 		},
 	}
@@ -98,6 +102,7 @@ func TestPrepareBlockBls(t *testing.T) {
 		config: ctypes.Config{
 			Option: &ctypes.OptionsConfig{
 				BlsPriKey: owner,
+				Signer:    signer.NewLocalSigner(pk[0], owner, nil),
 			},
 		},
 	}
@@ -147,6 +152,37 @@ func TestPrepareBlockBls(t *testing.T) {
 	assert.Nil(t, pbft.validatorPool.Verify(0, 0, msg, pb.Sign()))
 }
 
+func TestLocalSignerProtection(t *testing.T) {
+	pk, sk := GenerateKeys(1)
+	ctx := node.NewServiceContext(&node.Config{DataDir: ""}, nil, new(event.TypeMux), nil)
+	pbft := New(&configs.PbftConfig{Period: 10000, Amount: 10}, &ctypes.OptionsConfig{
+		NodePriKey:     pk[0],
+		NodeID:         discover.PubkeyID(&pk[0].PublicKey),
+		BlsPriKey:      sk[0],
+		ProtectionFile: "signerprotection.json",
+	}, ctx.EventMux, ctx)
+
+	// The engine signs before it is started, with a signer created once.
+	local := pbft.config.Option.Signer
+	vote := &protocols.PrepareVote{Epoch: 1, ViewNumber: 1, BlockHash: common.HexToHash("0xa"), BlockNumber: 10}
+	assert.Nil(t, pbft.signMsgByBls(vote))
+	assert.True(t, local == pbft.config.Option.Signer)
+
+	conflict := &protocols.PrepareVote{Epoch: 1, ViewNumber: 1, BlockHash: common.HexToHash("0xb"), BlockNumber: 10}
+	assert.NotNil(t, pbft.signMsgByBls(conflict))
+
+	header := &types.Header{Number: big.NewInt(10), Extra: make([]byte, 97)}
+	sign, err := pbft.signFn(header)
+	assert.Nil(t, err)
+	assert.True(t, pbft.verifySelfSigned(header.SealHash().Bytes(), sign))
+}
+
+func TestNewWithoutNodeKey(t *testing.T) {
+	_, sk := GenerateKeys(1)
+	pbft := New(&configs.PbftConfig{Period: 10000, Amount: 10}, &ctypes.OptionsConfig{BlsPriKey: sk[0]}, new(event.TypeMux), nil)
+	assert.Nil(t, pbft)
+}
+
 func TestAgg(t *testing.T) {
 	num := 4
 	pk, sk := GenerateKeys(num)
@@ -167,9 +203,10 @@ func TestAgg(t *testing.T) {
 			config: ctypes.Config{
 				Option: &ctypes.OptionsConfig{
 					BlsPriKey: sk[i],
+					Signer:    signer.NewLocalSigner(pk[i], sk[i], nil),
 				},
 			},
-			state: state.NewViewState(BaseMs, nil),
+			state: state.NewViewState(BaseMs, nil, nil),
 		}
 
 		cnode[i].state.SetHighestQCBlock(NewBlock(common.Hash{}, 1))
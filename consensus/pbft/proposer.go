@@ -0,0 +1,363 @@
+package pbft
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+const (
+	// viewsPerTurn is the number of views a proposer keeps its turn for
+	// before the next proposer takes over the block.
+	viewsPerTurn = 10
+
+	// scheduleSlots is the average number of turns of a validator in the
+	// stake-weighted schedule.
+	scheduleSlots = 16
+
+	// maxCachedSchedules bounds the schedules kept by the weighted selector,
+	// the validators of the previous and the current epoch are in use.
+	maxCachedSchedules = 4
+
+	// reputationLag is the distance of the missed turns counted from the
+	// start of a period, so they are committed on every validator when the
+	// period starts.
+	reputationLag = 10
+)
+
+// ProposerSelector selects the proposers of the blocks among the validators
+// of an epoch. Every validator has to select the same proposer for a block.
+type ProposerSelector interface {
+	// Proposer returns the index of the validator proposing the block of the
+	// given number in the given view, or an error if the data it is derived
+	// from is not available yet.
+	Proposer(validators *pbfttypes.Validators, blockNumber, viewNumber uint64) (uint64, error)
+}
+
+// proposerTurn returns the turn of the proposer of a block, which moves on
+// with every block and every viewsPerTurn views.
+func proposerTurn(blockNumber, viewNumber uint64) uint64 {
+	return blockNumber - 1 + viewNumber/viewsPerTurn
+}
+
+// roundRobinSelector gives every validator a turn after the other.
+type roundRobinSelector struct{}
+
+func (roundRobinSelector) Proposer(validators *pbfttypes.Validators, blockNumber, viewNumber uint64) (uint64, error) {
+	return proposerTurn(blockNumber, viewNumber) % uint64(validators.Len()), nil
+}
+
+// weightedSelector gives the validators turns in proportion to their weights,
+// spread evenly over a schedule. Validators weighted equally take their turns
+// in the same order as the round robin selector.
+type weightedSelector struct {
+	lock      sync.Mutex
+	schedules map[common.Hash][]uint32
+}
+
+func newWeightedSelector() *weightedSelector {
+	return &weightedSelector{schedules: make(map[common.Hash][]uint32)}
+}
+
+func (s *weightedSelector) Proposer(validators *pbfttypes.Validators, blockNumber, viewNumber uint64) (uint64, error) {
+	schedule := s.schedule(validators)
+	return uint64(schedule[proposerTurn(blockNumber, viewNumber)%uint64(len(schedule))]), nil
+}
+
+// schedule returns the indexes of the validators in the order of their turns.
+func (s *weightedSelector) schedule(validators *pbfttypes.Validators) []uint32 {
+	nodes := sortedValidators(validators)
+	key := scheduleKey(nodes)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if schedule, ok := s.schedules[key]; ok {
+		return schedule
+	}
+	if len(s.schedules) >= maxCachedSchedules {
+		s.schedules = make(map[common.Hash][]uint32)
+	}
+	schedule := buildSchedule(nodes)
+	s.schedules[key] = schedule
+	return schedule
+}
+
+// sortedValidators returns the validators ordered by their indexes.
+func sortedValidators(validators *pbfttypes.Validators) []*pbfttypes.ValidateNode {
+	nodes := make([]*pbfttypes.ValidateNode, 0, validators.Len())
+	for i := 0; i < validators.Len(); i++ {
+		node, err := validators.FindNodeByIndex(i)
+		if err != nil {
+			break
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// scheduleKey identifies the schedule of the validators by their ids and
+// weights, as the validators of an epoch are loaded again by the pool.
+func scheduleKey(nodes []*pbfttypes.ValidateNode) common.Hash {
+	type keyNode struct {
+		NodeID discover.NodeID
+		Weight *big.Int
+	}
+	key := make([]keyNode, len(nodes))
+	for i, node := range nodes {
+		key[i] = keyNode{node.NodeID, new(big.Int)}
+		if node.Weight != nil {
+			key[i].Weight = node.Weight
+		}
+	}
+	data, _ := rlp.EncodeToBytes(key)
+	return crypto.Keccak256Hash(data)
+}
+
+// buildSchedule apportions scheduleSlots turns per validator by the largest
+// remainder method, giving each validator at least one turn, and interleaves
+// them by smooth weighted round robin. The validators are weighted equally if
+// any of them has no positive weight.
+func buildSchedule(nodes []*pbfttypes.ValidateNode) []uint32 {
+	n := len(nodes)
+	weights := make([]*big.Int, n)
+	total := new(big.Int)
+	for i, node := range nodes {
+		if node.Weight == nil || node.Weight.Sign() <= 0 {
+			total.SetInt64(0)
+			break
+		}
+		weights[i] = node.Weight
+		total.Add(total, node.Weight)
+	}
+	if total.Sign() == 0 {
+		for i := range weights {
+			weights[i] = big.NewInt(1)
+		}
+		total.SetInt64(int64(n))
+	}
+
+	// Every validator has a turn, the others are apportioned by weight.
+	length := n * scheduleSlots
+	spare := big.NewInt(int64(length - n))
+	slots := make([]int, n)
+	remainders := make([]*big.Int, n)
+	assigned := n
+	for i, weight := range weights {
+		quo, rem := new(big.Int).QuoRem(new(big.Int).Mul(spare, weight), total, new(big.Int))
+		slots[i] = 1 + int(quo.Int64())
+		remainders[i] = rem
+		assigned += int(quo.Int64())
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+	for i := 0; assigned < length; i++ {
+		slots[order[i]]++
+		assigned++
+	}
+
+	schedule := make([]uint32, 0, length)
+	current := make([]int, n)
+	for len(schedule) < length {
+		best := 0
+		for i := range current {
+			current[i] += slots[i]
+			if current[i] > current[best] {
+				best = i
+			}
+		}
+		current[best] -= length
+		schedule = append(schedule, nodes[best].Index)
+	}
+	return schedule
+}
+
+// reputationSelector takes the turns of the weighted schedule, but skips the
+// validators which missed more turns than allowed in the previous period.
+//
+// The missed turns are derived from the quorum certificates of the committed
+// blocks: the views a block took in addition to the view of its parent failed,
+// and the turns of these views are counted against the validators scheduled
+// for them by weight. The turns of a skipped validator are counted for it, so
+// it gets its turns back in the next period unless the validators taking them
+// missed them. At most a third of the validators are skipped.
+//
+// The counted blocks end reputationLag blocks before the period starts, so
+// every validator following the chain has committed them. A node which hasn't
+// can't tell the proposers of the period, it fails instead of guessing.
+type reputationSelector struct {
+	weighted  *weightedSelector
+	period    uint64
+	maxMisses uint64
+	amount    uint32
+
+	// Committed quorum certificates and validators by block number.
+	qcAt         func(number uint64) *ctypes.QuorumCert
+	validatorsAt func(number uint64) *pbfttypes.Validators
+
+	lock   sync.Mutex
+	misses map[uint64]map[discover.NodeID]uint64 // Missed turns counted for the period
+}
+
+func newReputationSelector(config *configs.ProposerReputationConfig, amount uint32, weighted *weightedSelector,
+	qcAt func(uint64) *ctypes.QuorumCert, validatorsAt func(uint64) *pbfttypes.Validators) *reputationSelector {
+	return &reputationSelector{
+		weighted:     weighted,
+		period:       config.Period,
+		maxMisses:    config.MaxMisses,
+		amount:       amount,
+		qcAt:         qcAt,
+		validatorsAt: validatorsAt,
+		misses:       make(map[uint64]map[discover.NodeID]uint64),
+	}
+}
+
+func (s *reputationSelector) Proposer(validators *pbfttypes.Validators, blockNumber, viewNumber uint64) (uint64, error) {
+	schedule := s.weighted.schedule(validators)
+	turn := proposerTurn(blockNumber, viewNumber) % uint64(len(schedule))
+	skipped, err := s.skipped(validators, blockNumber)
+	if err != nil {
+		return 0, err
+	}
+	for i := uint64(0); i < uint64(len(schedule)); i++ {
+		index := schedule[(turn+i)%uint64(len(schedule))]
+		if !skipped[index] {
+			return uint64(index), nil
+		}
+	}
+	return uint64(schedule[turn]), nil
+}
+
+// skipped returns the indexes of the validators skipped in the period of the
+// block.
+func (s *reputationSelector) skipped(validators *pbfttypes.Validators, blockNumber uint64) (map[uint32]bool, error) {
+	if s.period == 0 || blockNumber == 0 {
+		return nil, nil
+	}
+	misses, err := s.periodMisses((blockNumber - 1) / s.period)
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		index  uint32
+		misses uint64
+	}
+	var candidates []candidate
+	for id, count := range misses {
+		if count <= s.maxMisses {
+			continue
+		}
+		if node, err := validators.FindNodeByID(id); err == nil {
+			candidates = append(candidates, candidate{node.Index, count})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].misses != candidates[j].misses {
+			return candidates[i].misses > candidates[j].misses
+		}
+		return candidates[i].index < candidates[j].index
+	})
+	if limit := (validators.Len() - 1) / 3; len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	skipped := make(map[uint32]bool, len(candidates))
+	for _, c := range candidates {
+		skipped[c.index] = true
+	}
+	return skipped, nil
+}
+
+// periodMisses returns the turns missed by the validators in the blocks the
+// period is based on, or an error if they are not all committed locally.
+func (s *reputationSelector) periodMisses(period uint64) (map[discover.NodeID]uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if misses, ok := s.misses[period]; ok {
+		return misses, nil
+	}
+	if period*s.period <= reputationLag {
+		return nil, nil
+	}
+	last := period*s.period - reputationLag
+	first := uint64(2)
+	if last > s.period+1 {
+		first = last - s.period + 1
+	}
+	misses, complete := s.countMisses(first, last)
+	if !complete {
+		// The blocks are committed later, don't remember the partial count.
+		log.Debug("Missed proposer turns are not committed", "period", period, "first", first, "last", last)
+		return nil, fmt.Errorf("missed proposer turns of blocks %d-%d are not committed", first, last)
+	}
+	if len(s.misses) >= maxCachedSchedules {
+		s.misses = make(map[uint64]map[discover.NodeID]uint64)
+	}
+	s.misses[period] = misses
+	return misses, nil
+}
+
+// countMisses counts the turns missed in the committed blocks from first to
+// last, and reports whether all of them are committed.
+func (s *reputationSelector) countMisses(first, last uint64) (map[discover.NodeID]uint64, bool) {
+	misses := make(map[discover.NodeID]uint64)
+	parent := s.qcAt(first - 1)
+	if parent == nil {
+		return misses, false
+	}
+	var (
+		epoch      uint64
+		validators *pbfttypes.Validators
+	)
+	for number := first; number <= last; number++ {
+		qc := s.qcAt(number)
+		if qc == nil {
+			return misses, false
+		}
+		// The views start over with the epoch.
+		if qc.Epoch != parent.Epoch || qc.ViewNumber <= parent.ViewNumber {
+			parent = qc
+			continue
+		}
+		if validators == nil || epoch != qc.Epoch {
+			if validators = s.validatorsAt(number); validators == nil || validators.Len() == 0 {
+				return misses, false
+			}
+			epoch = qc.Epoch
+		}
+		// The view of the parent failed unless it produced all of its blocks.
+		view := parent.ViewNumber
+		if parent.BlockIndex+1 >= s.amount {
+			view++
+		}
+		// The turns before the one producing the block were missed, the
+		// schedule repeats itself for long outages.
+		schedule := s.weighted.schedule(validators)
+		from, to := proposerTurn(number, view), proposerTurn(number, qc.ViewNumber)
+		if to-from > uint64(len(schedule)) {
+			from = to - uint64(len(schedule))
+		}
+		for turn := from; turn < to; turn++ {
+			index := schedule[turn%uint64(len(schedule))]
+			misses[validators.NodeID(int(index))]++
+		}
+		parent = qc
+	}
+	return misses, true
+}
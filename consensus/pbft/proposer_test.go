@@ -0,0 +1,166 @@
+package pbft
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+)
+
+func newWeightedValidators(weights ...int64) *pbfttypes.Validators {
+	vs := &pbfttypes.Validators{Nodes: make(pbfttypes.ValidateNodeMap, len(weights))}
+	for i, weight := range weights {
+		id := discover.NodeID{byte(i + 1)}
+		vs.Nodes[id] = &pbfttypes.ValidateNode{Index: uint32(i), NodeID: id}
+		if weight > 0 {
+			vs.Nodes[id].Weight = big.NewInt(weight)
+		}
+	}
+	return vs
+}
+
+// mustProposer returns the proposer of the block chosen by the selector.
+func mustProposer(t *testing.T, s ProposerSelector, vs *pbfttypes.Validators, number, view uint64) uint64 {
+	index, err := s.Proposer(vs, number, view)
+	if err != nil {
+		t.Fatalf("number %d view %d: %v", number, view, err)
+	}
+	return index
+}
+
+func TestWeightedSelectorEqualWeights(t *testing.T) {
+	weighted := newWeightedSelector()
+	for _, vs := range []*pbfttypes.Validators{
+		newWeightedValidators(5, 5, 5, 5),
+		newWeightedValidators(0, 0, 0, 0, 0, 0, 0),
+		// Validators without weights are weighted equally.
+		newWeightedValidators(1, 0, 100),
+	} {
+		for number := uint64(1); number < 100; number++ {
+			for view := uint64(0); view < 30; view++ {
+				assert.Equal(t, mustProposer(t, roundRobinSelector{}, vs, number, view), mustProposer(t, weighted, vs, number, view))
+			}
+		}
+	}
+}
+
+func TestWeightedSelector(t *testing.T) {
+	weighted := newWeightedSelector()
+	tests := []struct {
+		weights []int64
+		turns   []int
+	}{
+		{[]int64{1, 1, 2, 4}, []int{9, 8, 16, 31}},
+		// Every validator has a turn.
+		{[]int64{1, 1e18}, []int{1, 31}},
+	}
+	for _, test := range tests {
+		vs := newWeightedValidators(test.weights...)
+		length := len(test.weights) * scheduleSlots
+		turns := make([]int, len(test.weights))
+		for number := uint64(1); number <= uint64(length); number++ {
+			turns[mustProposer(t, weighted, vs, number, 0)]++
+		}
+		assert.Equal(t, test.turns, turns)
+
+		// The schedule repeats itself and moves on with the views.
+		assert.Equal(t, mustProposer(t, weighted, vs, 3, 0), mustProposer(t, weighted, vs, 3+uint64(length), 0))
+		assert.Equal(t, mustProposer(t, weighted, vs, 4, 0), mustProposer(t, weighted, vs, 3, viewsPerTurn))
+	}
+
+	// The heaviest validator doesn't take all of its turns in a row.
+	vs := newWeightedValidators(1, 1, 2, 4)
+	for number := uint64(1); number < 64; number += 2 {
+		assert.True(t, mustProposer(t, weighted, vs, number, 0) != 3 || mustProposer(t, weighted, vs, number+1, 0) != 3 || mustProposer(t, weighted, vs, number+2, 0) != 3)
+	}
+}
+
+// newTestChain returns the quorum certificates of a chain where the offline
+// validators never produce their blocks.
+func newTestChain(vs *pbfttypes.Validators, amount uint32, blocks uint64, offline ...uint64) map[uint64]*ctypes.QuorumCert {
+	isOffline := func(index uint64) bool {
+		for _, o := range offline {
+			if o == index {
+				return true
+			}
+		}
+		return false
+	}
+	qcs := make(map[uint64]*ctypes.QuorumCert)
+	view, index := uint64(0), uint32(0)
+	for number := uint64(1); number <= blocks; number++ {
+		if number > 1 {
+			if index+1 >= amount {
+				view, index = view+1, 0
+			} else {
+				index++
+			}
+		}
+		for isOffline(proposerTurn(number, view) % uint64(vs.Len())) {
+			view, index = view+1, 0
+		}
+		qcs[number] = &ctypes.QuorumCert{Epoch: 1, ViewNumber: view, BlockNumber: number, BlockIndex: index}
+	}
+	return qcs
+}
+
+func newTestReputationSelector(vs *pbfttypes.Validators, qcs map[uint64]*ctypes.QuorumCert, amount uint32) *reputationSelector {
+	config := &configs.ProposerReputationConfig{Period: 20, MaxMisses: 2}
+	return newReputationSelector(config, amount, newWeightedSelector(),
+		func(number uint64) *ctypes.QuorumCert { return qcs[number] },
+		func(uint64) *pbfttypes.Validators { return vs })
+}
+
+func TestReputationSelector(t *testing.T) {
+	vs := newWeightedValidators(5, 5, 5, 5)
+	qcs := newTestChain(vs, 10, 100, 2)
+	selector := newTestReputationSelector(vs, qcs, 10)
+
+	for period := uint64(1); period <= 2; period++ {
+		misses, err := selector.periodMisses(period)
+		assert.Nil(t, err)
+		assert.True(t, misses[vs.NodeID(2)] > 2)
+		assert.Equal(t, 1, len(misses))
+	}
+
+	for number := uint64(1); number <= 60; number++ {
+		for view := uint64(0); view < 30; view++ {
+			expect := mustProposer(t, selector.weighted, vs, number, view)
+			// The offline validator is skipped from the second period on.
+			if number > 20 && expect == 2 {
+				expect = 3
+			}
+			assert.Equal(t, expect, mustProposer(t, selector, vs, number, view), "number %d view %d", number, view)
+		}
+	}
+
+	// The proposers are unknown until the counted blocks are committed.
+	for number := uint64(25); number <= 100; number++ {
+		delete(qcs, number)
+	}
+	selector = newTestReputationSelector(vs, qcs, 10)
+	assert.Equal(t, mustProposer(t, selector.weighted, vs, 21, 0), mustProposer(t, selector, vs, 21, 0))
+	_, err := selector.Proposer(vs, 43, 0)
+	assert.NotNil(t, err)
+	assert.NotContains(t, selector.misses, uint64(2))
+}
+
+func TestReputationSelectorLimit(t *testing.T) {
+	// No more than a third of the validators are skipped.
+	vs := newWeightedValidators(5, 5, 5, 5)
+	qcs := newTestChain(vs, 10, 100, 1, 2)
+	selector := newTestReputationSelector(vs, qcs, 10)
+
+	misses, err := selector.periodMisses(2)
+	assert.Nil(t, err)
+	assert.True(t, misses[vs.NodeID(1)] > 2)
+	assert.True(t, misses[vs.NodeID(2)] > 2)
+	skipped, err := selector.skipped(vs, 41)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(skipped))
+}
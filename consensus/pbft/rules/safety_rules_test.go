@@ -73,7 +73,7 @@ func prepareQC(epoch, viewNumber uint64, hash common.Hash, number uint64, index
 }
 
 func newEpochViewNumberState(epoch, viewNumber uint64, amount uint32) (*state.ViewState, *ctypes.BlockTree) {
-	viewState := state.NewViewState(Period, nil)
+	viewState := state.NewViewState(Period, nil, nil)
 	viewState.ResetView(epoch, viewNumber,viewState.BlockNumber())
 	viewState.SetViewTimer(2)
 
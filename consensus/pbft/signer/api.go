@@ -0,0 +1,100 @@
+package signer
+
+import (
+	"fmt"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// Namespace is the RPC namespace of the signer API.
+const Namespace = "pbftsigner"
+
+// API is the remote signing service of a validator. Messages are sent RLP
+// encoded, so the signer derives what it signs from the message itself.
+type API struct {
+	signer *LocalSigner
+}
+
+// NewAPI creates the signing service for a signer, which should be protected
+// against double signing.
+func NewAPI(signer *LocalSigner) *API {
+	return &API{signer: signer}
+}
+
+// NodeID returns the node ID of the validator.
+func (api *API) NodeID() discover.NodeID {
+	return api.signer.NodeID()
+}
+
+// BlsPublicKey returns the BLS public key of the validator.
+func (api *API) BlsPublicKey() (*bls.PublicKey, error) {
+	return api.signer.BlsPublicKey()
+}
+
+// SignSeal signs the seal hash of an RLP encoded block header.
+//
+// Seals are not checked against the watermarks: after a view change the next
+// proposer legitimately seals a different block at the same height, and a seal
+// alone is no evidence of double signing, which is only proven with the BLS
+// signatures of consensus messages. A sealed block is proposed through a
+// PrepareBlock, which is signed with SignMsg and protected like the others.
+func (api *API) SignSeal(data hexutil.Bytes) (hexutil.Bytes, error) {
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(data, header); err != nil {
+		return nil, fmt.Errorf("invalid header: %v", err)
+	}
+	sign, err := api.signer.SignSeal(header)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Signed block seal", "number", header.Number, "sealHash", header.SealHash())
+	return sign, nil
+}
+
+// SignMsg signs an RLP encoded consensus message of the given pbft message code.
+func (api *API) SignMsg(code uint64, data hexutil.Bytes) (hexutil.Bytes, error) {
+	msg, err := decodeMsg(code, data)
+	if err != nil {
+		return nil, err
+	}
+	sign, err := api.signer.SignMsg(msg)
+	if err != nil {
+		log.Warn("Refused to sign consensus message", "type", fmt.Sprintf("%T", msg), "msg", msg, "err", err)
+		return nil, err
+	}
+	log.Info("Signed consensus message", "type", fmt.Sprintf("%T", msg), "msg", msg)
+	return sign, nil
+}
+
+// SchnorrNIZKProve proves the possession of the BLS key.
+func (api *API) SchnorrNIZKProve() (*bls.SchnorrProof, error) {
+	return api.signer.SchnorrNIZKProve()
+}
+
+// decodeMsg decodes a consensus message which is signed with the BLS key.
+func decodeMsg(code uint64, data []byte) (ctypes.ConsensusMsg, error) {
+	var msg ctypes.ConsensusMsg
+	switch code {
+	case protocols.PrepareBlockMsg:
+		msg = new(protocols.PrepareBlock)
+	case protocols.PrepareVoteMsg:
+		msg = new(protocols.PrepareVote)
+	case protocols.PreCommitMsg:
+		msg = new(protocols.PreCommit)
+	case protocols.ViewChangeMsg:
+		msg = new(protocols.ViewChange)
+	default:
+		return nil, fmt.Errorf("%v: code %d", errUnknownMessage, code)
+	}
+	if err := rlp.DecodeBytes(data, msg); err != nil {
+		return nil, fmt.Errorf("invalid consensus message: %v", err)
+	}
+	return msg, nil
+}
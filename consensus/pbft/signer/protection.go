@@ -0,0 +1,170 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+)
+
+// Kinds of consensus messages tracked by the double signing protection.
+const (
+	KindPrepareBlock = "prepareBlock"
+	KindPrepareVote  = "prepareVote"
+	KindPreCommit    = "preCommit"
+	KindViewChange   = "viewChange"
+)
+
+// Watermark is the highest position in the consensus signed for a kind of message.
+type Watermark struct {
+	Epoch       uint64      `json:"epoch"`
+	ViewNumber  uint64      `json:"viewNumber"`
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+}
+
+func (w *Watermark) String() string {
+	return fmt.Sprintf("{Epoch:%d,ViewNumber:%d,BlockNumber:%d,BlockHash:%s}", w.Epoch, w.ViewNumber, w.BlockNumber, w.BlockHash.TerminalString())
+}
+
+// compare orders two watermarks of the given kind by their position in the
+// consensus. A validator sends a single view change per view, so the block of
+// view changes is not part of their position.
+func (w *Watermark) compare(kind string, other *Watermark) int {
+	a := []uint64{w.Epoch, w.ViewNumber, w.BlockNumber}
+	b := []uint64{other.Epoch, other.ViewNumber, other.BlockNumber}
+	if kind == KindViewChange {
+		a, b = a[:2], b[:2]
+	}
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// ConflictError is returned when signing a message would contradict one that
+// was signed before.
+type ConflictError struct {
+	Kind   string
+	Signed *Watermark
+	Wanted *Watermark
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("refusing to sign %s %s, already signed %s", e.Kind, e.Wanted, e.Signed)
+}
+
+// Protection keeps the watermark of every kind of message in a file, so that
+// the validator never signs two different blocks at the same position, or goes
+// back to a position it already left, even after a restart.
+type Protection struct {
+	path  string
+	lock  sync.Mutex
+	marks map[string]*Watermark
+}
+
+// NewProtection loads the watermarks from the given file, which is created
+// when the first message is signed. An empty path keeps them in memory only.
+func NewProtection(path string) (*Protection, error) {
+	p := &Protection{
+		path:  path,
+		marks: make(map[string]*Watermark),
+	}
+	if path == "" {
+		return p, nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &p.marks); err != nil {
+		return nil, fmt.Errorf("invalid protection file %s: %v", path, err)
+	}
+	return p, nil
+}
+
+// Watermark returns the watermark of the given kind of message, or nil if no
+// such message has been signed yet.
+func (p *Protection) Watermark(kind string) *Watermark {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if mark := p.marks[kind]; mark != nil {
+		cpy := *mark
+		return &cpy
+	}
+	return nil
+}
+
+// Advance checks that a message at the given position may be signed and raises
+// the watermark to it. Signing the same block at the current watermark again is
+// allowed. The watermark is written to disk before Advance returns, so the
+// signature must only be made if it succeeds.
+func (p *Protection) Advance(kind string, mark *Watermark) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if last := p.marks[kind]; last != nil {
+		switch cmp := mark.compare(kind, last); {
+		case cmp < 0:
+			return &ConflictError{Kind: kind, Signed: last, Wanted: mark}
+		case cmp == 0 && mark.BlockHash != last.BlockHash:
+			return &ConflictError{Kind: kind, Signed: last, Wanted: mark}
+		case cmp == 0:
+			return nil
+		}
+	}
+	marks := make(map[string]*Watermark, len(p.marks)+1)
+	for k, v := range p.marks {
+		marks[k] = v
+	}
+	cpy := *mark
+	marks[kind] = &cpy
+	if err := p.write(marks); err != nil {
+		return err
+	}
+	p.marks = marks
+	return nil
+}
+
+// write atomically replaces the protection file with the given watermarks.
+func (p *Protection) write(marks map[string]*Watermark) error {
+	if p.path == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(marks, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(p.path), "."+filepath.Base(p.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	f.Close()
+	return os.Rename(f.Name(), p.path)
+}
@@ -0,0 +1,105 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rpc"
+)
+
+// callTimeout bounds every request to the remote signer, a view lasts only a
+// few seconds.
+const callTimeout = 3 * time.Second
+
+// RemoteSigner signs through the API of a signer daemon.
+type RemoteSigner struct {
+	client    *rpc.Client
+	nodeID    discover.NodeID
+	blsPubKey *bls.PublicKey
+}
+
+// NewRemoteSigner connects to the signer daemon at the given IPC path or HTTP
+// URL and retrieves the public keys of the validator.
+func NewRemoteSigner(endpoint string) (*RemoteSigner, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewRemoteSignerWithClient(client)
+}
+
+// NewRemoteSignerWithClient creates a remote signer using the given RPC client.
+func NewRemoteSignerWithClient(client *rpc.Client) (*RemoteSigner, error) {
+	s := &RemoteSigner{client: client, blsPubKey: new(bls.PublicKey)}
+	if err := s.call(&s.nodeID, "nodeID"); err != nil {
+		return nil, fmt.Errorf("failed to get node ID from signer: %v", err)
+	}
+	if err := s.call(s.blsPubKey, "blsPublicKey"); err != nil {
+		return nil, fmt.Errorf("failed to get BLS public key from signer: %v", err)
+	}
+	return s, nil
+}
+
+// Close closes the connection to the signer daemon.
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
+
+// NodeID returns the node ID of the validator.
+func (s *RemoteSigner) NodeID() discover.NodeID {
+	return s.nodeID
+}
+
+// BlsPublicKey returns the BLS public key of the validator.
+func (s *RemoteSigner) BlsPublicKey() (*bls.PublicKey, error) {
+	return s.blsPubKey, nil
+}
+
+// SignSeal requests the signature of the seal hash of a block header.
+func (s *RemoteSigner) SignSeal(header *types.Header) ([]byte, error) {
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	var sign hexutil.Bytes
+	if err := s.call(&sign, "signSeal", hexutil.Bytes(data)); err != nil {
+		return nil, err
+	}
+	return sign, nil
+}
+
+// SignMsg requests the BLS signature of a consensus message.
+func (s *RemoteSigner) SignMsg(msg ctypes.ConsensusMsg) ([]byte, error) {
+	data, err := rlp.EncodeToBytes(msg)
+	if err != nil {
+		return nil, err
+	}
+	var sign hexutil.Bytes
+	if err := s.call(&sign, "signMsg", protocols.MessageType(msg), hexutil.Bytes(data)); err != nil {
+		return nil, err
+	}
+	return sign, nil
+}
+
+// SchnorrNIZKProve requests the proof of possession of the BLS key.
+func (s *RemoteSigner) SchnorrNIZKProve() (*bls.SchnorrProof, error) {
+	proof := new(bls.SchnorrProof)
+	if err := s.call(proof, "schnorrNIZKProve"); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+func (s *RemoteSigner) call(result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+	return s.client.CallContext(ctx, result, Namespace+"_"+method, args...)
+}
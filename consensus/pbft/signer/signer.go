@@ -0,0 +1,101 @@
+// Package signer implements the signers used by the pbft engine, holding the
+// node key and BLS key of a validator either in the node itself or in a
+// separate signer daemon reached over IPC or HTTP.
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+)
+
+var errUnknownMessage = errors.New("unknown consensus message")
+
+// LocalSigner signs with keys held in memory. If it has a protection, it refuses
+// to sign consensus messages conflicting with those it signed before.
+type LocalSigner struct {
+	nodeKey    *ecdsa.PrivateKey
+	blsKey     *bls.SecretKey
+	protection *Protection
+}
+
+// NewLocalSigner creates a signer for the given keys. The protection may be nil,
+// which disables the double signing checks.
+func NewLocalSigner(nodeKey *ecdsa.PrivateKey, blsKey *bls.SecretKey, protection *Protection) *LocalSigner {
+	return &LocalSigner{
+		nodeKey:    nodeKey,
+		blsKey:     blsKey,
+		protection: protection,
+	}
+}
+
+// NodeID returns the node ID derived from the node key.
+func (s *LocalSigner) NodeID() discover.NodeID {
+	return discover.PubkeyID(&s.nodeKey.PublicKey)
+}
+
+// BlsPublicKey returns the public part of the BLS key.
+func (s *LocalSigner) BlsPublicKey() (*bls.PublicKey, error) {
+	return s.blsKey.GetPublicKey(), nil
+}
+
+// SignSeal signs the seal hash of a block header with the node key. Seals are
+// not protected, see API.SignSeal.
+func (s *LocalSigner) SignSeal(header *types.Header) ([]byte, error) {
+	return crypto.Sign(header.SealHash().Bytes(), s.nodeKey)
+}
+
+// SignMsg signs a consensus message with the BLS key, after raising the
+// watermark of its kind if the signer is protected.
+func (s *LocalSigner) SignMsg(msg ctypes.ConsensusMsg) ([]byte, error) {
+	if s.protection != nil {
+		kind, mark, err := watermarkOf(msg)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.protection.Advance(kind, mark); err != nil {
+			return nil, err
+		}
+	}
+	buf, err := msg.CannibalizeBytes()
+	if err != nil {
+		return nil, err
+	}
+	sign := s.blsKey.Sign(string(buf))
+	return sign.Serialize(), nil
+}
+
+// SchnorrNIZKProve proves the possession of the BLS key.
+func (s *LocalSigner) SchnorrNIZKProve() (*bls.SchnorrProof, error) {
+	return s.blsKey.MakeSchnorrNIZKP()
+}
+
+// watermarkOf returns the kind and the position in the consensus of a message.
+func watermarkOf(msg ctypes.ConsensusMsg) (string, *Watermark, error) {
+	mark := &Watermark{
+		Epoch:       msg.EpochNum(),
+		ViewNumber:  msg.ViewNum(),
+		BlockNumber: msg.BlockNum(),
+	}
+	switch m := msg.(type) {
+	case *protocols.PrepareBlock:
+		mark.BlockHash = m.Block.Hash()
+		return KindPrepareBlock, mark, nil
+	case *protocols.PrepareVote:
+		mark.BlockHash = m.BlockHash
+		return KindPrepareVote, mark, nil
+	case *protocols.PreCommit:
+		mark.BlockHash = m.BlockHash
+		return KindPreCommit, mark, nil
+	case *protocols.ViewChange:
+		mark.BlockHash = m.BlockHash
+		return KindViewChange, mark, nil
+	}
+	return "", nil, errUnknownMessage
+}
@@ -0,0 +1,121 @@
+package signer
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rpc"
+)
+
+func tempProtection(t *testing.T) (*Protection, string) {
+	dir, err := ioutil.TempDir("", "pbftsigner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewProtection(filepath.Join(dir, "protection.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, dir
+}
+
+func TestProtectionAdvance(t *testing.T) {
+	p, dir := tempProtection(t)
+	defer os.RemoveAll(dir)
+
+	hashA, hashB := common.HexToHash("0xa"), common.HexToHash("0xb")
+	tests := []struct {
+		kind string
+		mark Watermark
+		ok   bool
+	}{
+		{KindPrepareVote, Watermark{1, 1, 10, hashA}, true},
+		// Signing the same block again is allowed.
+		{KindPrepareVote, Watermark{1, 1, 10, hashA}, true},
+		// A different block at the same position is a double sign.
+		{KindPrepareVote, Watermark{1, 1, 10, hashB}, false},
+		// Going back is refused.
+		{KindPrepareVote, Watermark{1, 1, 9, hashB}, false},
+		{KindPrepareVote, Watermark{1, 0, 11, hashB}, false},
+		{KindPrepareVote, Watermark{1, 1, 11, hashB}, true},
+		{KindPrepareVote, Watermark{1, 2, 11, hashA}, true},
+		// Kinds of messages are tracked separately.
+		{KindPrepareBlock, Watermark{1, 1, 10, hashB}, true},
+		// A single view change is sent per view.
+		{KindViewChange, Watermark{1, 1, 10, hashA}, true},
+		{KindViewChange, Watermark{1, 1, 11, hashA}, true},
+		{KindViewChange, Watermark{1, 1, 12, hashB}, false},
+		{KindViewChange, Watermark{1, 2, 9, hashB}, true},
+	}
+	for i, test := range tests {
+		mark := test.mark
+		err := p.Advance(test.kind, &mark)
+		if test.ok {
+			assert.Nil(t, err, "test %d", i)
+		} else {
+			assert.IsType(t, &ConflictError{}, err, "test %d", i)
+		}
+	}
+
+	// The watermarks survive a restart.
+	loaded, err := NewProtection(p.path)
+	assert.Nil(t, err)
+	assert.Equal(t, &Watermark{1, 2, 11, hashA}, loaded.Watermark(KindPrepareVote))
+	assert.Equal(t, &Watermark{1, 1, 10, hashB}, loaded.Watermark(KindPrepareBlock))
+	assert.Equal(t, &Watermark{1, 2, 9, hashB}, loaded.Watermark(KindViewChange))
+	assert.Nil(t, loaded.Watermark(KindPreCommit))
+	assert.IsType(t, &ConflictError{}, loaded.Advance(KindPrepareVote, &Watermark{1, 2, 11, hashB}))
+}
+
+func TestAPI(t *testing.T) {
+	p, dir := tempProtection(t)
+	defer os.RemoveAll(dir)
+
+	key, _ := crypto.GenerateKey()
+	server := rpc.NewServer()
+	if err := server.RegisterName(Namespace, NewAPI(NewLocalSigner(key, new(bls.SecretKey), p))); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	var nodeID discover.NodeID
+	assert.Nil(t, client.Call(&nodeID, "pbftsigner_nodeID"))
+	assert.Equal(t, discover.PubkeyID(&key.PublicKey), nodeID)
+
+	// Block seals are signed with the node key.
+	header := &types.Header{Number: big.NewInt(1), Extra: make([]byte, 97)}
+	data, _ := rlp.EncodeToBytes(header)
+	var seal hexutil.Bytes
+	assert.Nil(t, client.Call(&seal, "pbftsigner_signSeal", hexutil.Bytes(data)))
+	pub, err := crypto.SigToPub(header.SealHash().Bytes(), seal)
+	assert.Nil(t, err)
+	assert.Equal(t, nodeID, discover.PubkeyID(pub))
+
+	// Votes conflicting with the signed ones are refused before using the BLS key.
+	assert.Nil(t, p.Advance(KindPrepareVote, &Watermark{1, 1, 10, common.HexToHash("0xa")}))
+	vote := &protocols.PrepareVote{Epoch: 1, ViewNumber: 1, BlockHash: common.HexToHash("0xb"), BlockNumber: 10}
+	data, _ = rlp.EncodeToBytes(vote)
+	var sign hexutil.Bytes
+	err = client.Call(&sign, "pbftsigner_signMsg", protocols.PrepareVoteMsg, hexutil.Bytes(data))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "refusing to sign prepareVote")
+
+	// Only consensus messages are signed.
+	err = client.Call(&sign, "pbftsigner_signMsg", protocols.GetPrepareVoteMsg, hexutil.Bytes(data))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), errUnknownMessage.Error())
+}
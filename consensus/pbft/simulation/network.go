@@ -0,0 +1,331 @@
+package simulation
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/mclock"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// AnyNode matches every node in the rules of the network.
+const AnyNode = -1
+
+// Filter decides whether a message is delivered, it's called for every copy
+// of a message sent to a node.
+type Filter func(from, to int, msg ctypes.Message) bool
+
+// Stats counts the messages passed through the network.
+type Stats struct {
+	Sent         uint64 // Messages sent by the engines
+	Delivered    uint64 // Copies of the messages delivered to the nodes
+	Dropped      uint64 // Copies dropped by the faults or lost to the crashed nodes
+	Equivocated  uint64 // Conflicting messages made up for the equivocating nodes
+	Synchronized uint64 // Blocks inserted into lagging nodes from their peers
+}
+
+// envelope is a message sent by an engine, encoded the way it goes on the wire.
+type envelope struct {
+	from        int
+	incarnation int
+	seq         uint64
+	typ         reflect.Type
+	data        []byte
+	peerID      string // Receiver of a directed message, empty for a broadcast
+}
+
+// delivery is a copy of a message scheduled for a node.
+type delivery struct {
+	at          mclock.AbsTime
+	seq         uint64
+	from, to    int
+	incarnation int
+	typ         reflect.Type
+	data        []byte
+}
+
+// rule drops or delays the messages of a link.
+type rule struct {
+	from, to int
+	rate     float64
+	delay    time.Duration
+}
+
+func (r *rule) matches(from, to int) bool {
+	return (r.from == AnyNode || r.from == from) && (r.to == AnyNode || r.to == to)
+}
+
+// twin is the conflicting block made up for an equivocating proposer, it's
+// sent to the peers of the group instead of the original.
+type twin struct {
+	hash   common.Hash
+	number uint64
+	group  map[int]bool
+}
+
+// network carries the messages between the nodes. The engines queue their
+// messages from their own goroutines, the faults are decided and the copies
+// are delivered by the goroutine stepping the simulation, in the order of
+// the senders and their messages, with a random source seeded by the
+// configuration.
+type network struct {
+	sim *Simulation
+	rng *rand.Rand
+
+	lock   sync.Mutex
+	outbox []*envelope
+	seqs   []uint64
+
+	queue      []*delivery
+	deliverSeq uint64
+
+	groups     map[int]int // Partition group of the nodes, nil if healed
+	rules      []*rule
+	reorder    float64
+	window     time.Duration
+	filter     Filter
+	equivocate map[int]bool
+	twins      map[common.Hash]*twin
+
+	stats Stats
+}
+
+func newNetwork(sim *Simulation, seed int64, nodes int) *network {
+	return &network{
+		sim:        sim,
+		rng:        rand.New(rand.NewSource(seed)),
+		seqs:       make([]uint64, nodes),
+		equivocate: make(map[int]bool),
+		twins:      make(map[common.Hash]*twin),
+	}
+}
+
+// send is the send queue hook of the engines, it queues an encoded copy of
+// the message.
+func (net *network) send(from, incarnation int, m *ctypes.MsgPackage) {
+	data, err := rlp.EncodeToBytes(m.Message())
+	if err != nil {
+		log.Error("Failed to encode the simulated message", "node", from, "type", reflect.TypeOf(m.Message()), "err", err)
+		return
+	}
+	net.lock.Lock()
+	defer net.lock.Unlock()
+	net.seqs[from]++
+	net.outbox = append(net.outbox, &envelope{
+		from:        from,
+		incarnation: incarnation,
+		seq:         net.seqs[from],
+		typ:         reflect.TypeOf(m.Message()).Elem(),
+		data:        data,
+		peerID:      m.PeerID(),
+	})
+	atomic.AddUint64(&net.stats.Sent, 1)
+}
+
+// pending returns the number of messages sent so far.
+func (net *network) pending() uint64 {
+	return atomic.LoadUint64(&net.stats.Sent)
+}
+
+// linked returns whether the partition lets the nodes talk.
+func (net *network) linked(from, to int) bool {
+	if net.groups == nil {
+		return true
+	}
+	return net.groups[from] == net.groups[to]
+}
+
+// schedule takes the messages sent by the engines and schedules their copies
+// for the receivers, deciding the faults. It returns the number of messages
+// taken.
+func (net *network) schedule(now mclock.AbsTime) int {
+	net.lock.Lock()
+	outbox := net.outbox
+	net.outbox = nil
+	net.lock.Unlock()
+
+	sort.Slice(outbox, func(i, j int) bool {
+		if outbox[i].from != outbox[j].from {
+			return outbox[i].from < outbox[j].from
+		}
+		return outbox[i].seq < outbox[j].seq
+	})
+	for _, env := range outbox {
+		sender := net.sim.nodes[env.from]
+		if sender.down || sender.incarnation != env.incarnation {
+			continue
+		}
+		var receivers []*Node
+		for _, node := range net.sim.nodes {
+			if node == sender {
+				continue
+			}
+			if env.peerID == "" || env.peerID == node.peerID {
+				receivers = append(receivers, node)
+			}
+		}
+		copies := net.equivocation(sender, env, receivers)
+		for _, node := range receivers {
+			data := env.data
+			if d, ok := copies[node.index]; ok {
+				data = d
+			}
+			net.route(now, env, node, data)
+		}
+	}
+	return len(outbox)
+}
+
+// route schedules the copy of a message for a receiver.
+func (net *network) route(now mclock.AbsTime, env *envelope, to *Node, data []byte) {
+	from := env.from
+	if to.down || !net.linked(from, to.index) {
+		atomic.AddUint64(&net.stats.Dropped, 1)
+		return
+	}
+	if net.filter != nil && !net.filter(from, to.index, decode(env.typ, data)) {
+		atomic.AddUint64(&net.stats.Dropped, 1)
+		return
+	}
+	delay := net.sim.config.Latency
+	if jitter := net.sim.config.Jitter; jitter > 0 {
+		delay += time.Duration(net.rng.Int63n(int64(jitter)))
+	}
+	for _, r := range net.rules {
+		if !r.matches(from, to.index) {
+			continue
+		}
+		if r.rate > 0 && net.rng.Float64() < r.rate {
+			atomic.AddUint64(&net.stats.Dropped, 1)
+			return
+		}
+		delay += r.delay
+	}
+	if net.reorder > 0 && net.window > 0 && net.rng.Float64() < net.reorder {
+		delay += time.Duration(net.rng.Int63n(int64(net.window)))
+	}
+	net.deliverSeq++
+	net.queue = append(net.queue, &delivery{
+		at:          now.Add(delay),
+		seq:         net.deliverSeq,
+		from:        from,
+		to:          to.index,
+		incarnation: to.incarnation,
+		typ:         env.typ,
+		data:        data,
+	})
+}
+
+// deliver delivers the copies due by now, in the order of their delivery time
+// and scheduling. It returns the number of copies delivered.
+func (net *network) deliver(now mclock.AbsTime) int {
+	sort.Slice(net.queue, func(i, j int) bool {
+		if net.queue[i].at != net.queue[j].at {
+			return net.queue[i].at < net.queue[j].at
+		}
+		return net.queue[i].seq < net.queue[j].seq
+	})
+	due := 0
+	for due < len(net.queue) && net.queue[due].at <= now {
+		due++
+	}
+	deliveries := net.queue[:due]
+	net.queue = append([]*delivery(nil), net.queue[due:]...)
+
+	delivered := 0
+	for _, d := range deliveries {
+		from, to := net.sim.nodes[d.from], net.sim.nodes[d.to]
+		if to.down || to.incarnation != d.incarnation || !net.linked(d.from, d.to) {
+			atomic.AddUint64(&net.stats.Dropped, 1)
+			continue
+		}
+		to.receive(from, decode(d.typ, d.data))
+		atomic.AddUint64(&net.stats.Delivered, 1)
+		delivered++
+	}
+	return delivered
+}
+
+// equivocation makes up the conflicting copies of the messages of an
+// equivocating node. The block proposed by the node is replaced by a twin
+// block of the same height for every other receiver, and its votes for the
+// block are doubled with votes for the twin. It returns the copies by
+// receiver.
+func (net *network) equivocation(sender *Node, env *envelope, receivers []*Node) map[int][]byte {
+	if !net.equivocate[sender.index] {
+		return nil
+	}
+	switch msg := decode(env.typ, env.data).(type) {
+	case *protocols.PrepareBlock:
+		if len(receivers) < 2 {
+			return nil
+		}
+		header := msg.Block.Header()
+		header.Time++
+		sign, err := sender.signer.SignSeal(header)
+		if err != nil {
+			return nil
+		}
+		copy(header.Extra[len(header.Extra)-len(sign):], sign)
+		original := msg.Block.Hash()
+		msg.Block = msg.Block.WithSeal(header)
+		t := &twin{hash: msg.Block.Hash(), number: msg.Block.NumberU64(), group: make(map[int]bool)}
+		for i, node := range receivers {
+			if i%2 == 1 {
+				t.group[node.index] = true
+			}
+		}
+		net.twins[original] = t
+		return net.resign(sender, msg, t.group)
+
+	case *protocols.PrepareVote:
+		if t := net.twins[msg.BlockHash]; t != nil {
+			msg.BlockHash = t.hash
+			return net.resign(sender, msg, t.group)
+		}
+
+	case *protocols.PreCommit:
+		if t := net.twins[msg.BlockHash]; t != nil {
+			msg.BlockHash = t.hash
+			return net.resign(sender, msg, t.group)
+		}
+	}
+	return nil
+}
+
+// resign signs the conflicting message and returns its copies for the group.
+func (net *network) resign(sender *Node, msg ctypes.ConsensusMsg, group map[int]bool) map[int][]byte {
+	sign, err := sender.signer.SignMsg(msg)
+	if err != nil {
+		log.Error("Failed to sign the equivocating message", "node", sender.index, "err", err)
+		return nil
+	}
+	msg.SetSign(sign)
+	data, err := rlp.EncodeToBytes(msg)
+	if err != nil {
+		return nil
+	}
+	copies := make(map[int][]byte, len(group))
+	for index := range group {
+		copies[index] = data
+	}
+	atomic.AddUint64(&net.stats.Equivocated, 1)
+	return copies
+}
+
+// decode decodes a copy of a message.
+func decode(typ reflect.Type, data []byte) ctypes.Message {
+	msg := reflect.New(typ).Interface()
+	if err := rlp.DecodeBytes(data, msg); err != nil {
+		panic("simulation: failed to decode a message encoded by the network: " + err.Error())
+	}
+	return msg.(ctypes.Message)
+}
@@ -0,0 +1,272 @@
+package simulation
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/signer"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/validator"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/node"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/mclock"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/event"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+)
+
+// Node is a validator of the simulation. It runs a consensus engine on its own
+// chain and plays the part of the miner worker: it seals the blocks the engine
+// asks for and writes the blocks the engine commits.
+type Node struct {
+	sim     *Simulation
+	index   int
+	id      discover.NodeID
+	peerID  string
+	nodeKey *ecdsa.PrivateKey
+	blsKey  *bls.SecretKey
+	signer  ctypes.Signer // Signs the equivocating messages
+	db      ethdb.Database
+	dataDir string
+
+	engine *pbft.Pbft
+	chain  *core.BlockChain
+	cache  *core.BlockChainCache
+	txpool *core.TxPool
+	sub    *event.TypeMuxSubscription
+	wg     sync.WaitGroup
+
+	incarnation  int  // Number of starts, tags the messages of each run
+	down         bool // Crashed and not restarted
+	sealedParent common.Hash
+	sealedAt     mclock.AbsTime
+}
+
+func newNode(sim *Simulation, index int, nodeKey *ecdsa.PrivateKey, blsKey *bls.SecretKey, db ethdb.Database) *Node {
+	id := discover.PubkeyID(&nodeKey.PublicKey)
+	return &Node{
+		sim:     sim,
+		index:   index,
+		id:      id,
+		peerID:  id.TerminalString(),
+		nodeKey: nodeKey,
+		blsKey:  blsKey,
+		signer:  signer.NewLocalSigner(nodeKey, blsKey, nil),
+		db:      db,
+		dataDir: filepath.Join(sim.dataDir, fmt.Sprintf("node%d", index)),
+		down:    true,
+	}
+}
+
+// Index returns the index of the node among the validators.
+func (n *Node) Index() int { return n.index }
+
+// ID returns the node ID of the validator.
+func (n *Node) ID() discover.NodeID { return n.id }
+
+// DataDir returns the data directory of the node, the wal is in its
+// phoenixchain/wal subdirectory.
+func (n *Node) DataDir() string { return n.dataDir }
+
+// Engine returns the consensus engine of the node, nil while it's down.
+func (n *Node) Engine() *pbft.Pbft {
+	if n.down {
+		return nil
+	}
+	return n.engine
+}
+
+// Chain returns the chain of the node.
+func (n *Node) Chain() *core.BlockChain { return n.chain }
+
+// Height returns the number of the head block of the chain of the node.
+func (n *Node) Height() uint64 {
+	if n.chain == nil {
+		return 0
+	}
+	return n.chain.CurrentBlock().NumberU64()
+}
+
+// Down returns whether the node is crashed.
+func (n *Node) Down() bool { return n.down }
+
+// start starts the engine of the node on its chain, loading the consensus
+// state from the wal on a restart.
+func (n *Node) start(validators []configs.PbftNode) error {
+	sys := &configs.PbftConfig{
+		Period:       n.sim.config.Period,
+		Amount:       n.sim.config.Amount,
+		InitialNodes: validators,
+	}
+	opt := &ctypes.OptionsConfig{
+		NodePriKey:        n.nodeKey,
+		NodeID:            n.id,
+		BlsPriKey:         n.blsKey,
+		Clock:             n.sim.clock,
+		WalMode:           true,
+		PeerMsgQueueSize:  1024,
+		EvidenceDir:       "evidence",
+		MaxPingLatency:    5000,
+		MaxQueuesLimit:    4096,
+		BlacklistDeadline: 5,
+		Period:            n.sim.config.Period,
+		Amount:            n.sim.config.Amount,
+	}
+	ctx := node.NewServiceContext(&node.Config{DataDir: n.dataDir, Name: "phoenixchain"}, nil, new(event.TypeMux), nil)
+	engine := pbft.New(sys, opt, ctx.EventMux, ctx)
+	if engine == nil {
+		return errors.New("failed to create the engine")
+	}
+	chain, err := core.NewBlockChain(n.db, nil, n.sim.genesis.Config, engine, vm.Config{}, nil)
+	if err != nil {
+		return err
+	}
+	cache := core.NewBlockChainCache(chain)
+	txconfig := core.DefaultTxPoolConfig
+	txconfig.Journal = ""
+	txpool := core.NewTxPool(txconfig, n.sim.genesis.Config, core.NewTxPoolBlockChain(cache))
+
+	incarnation := n.incarnation + 1
+	engine.SetSendQueueHook(func(m *ctypes.MsgPackage) {
+		n.sim.net.send(n.index, incarnation, m)
+	})
+	n.engine, n.chain, n.cache, n.txpool = engine, chain, cache, txpool
+	n.sub = ctx.EventMux.Subscribe(pbfttypes.PbftResult{})
+	n.wg.Add(1)
+	go n.resultLoop(n.sub, chain, cache)
+
+	if err := engine.Start(chain, cache, txpool, validator.NewStaticAgency(validators)); err != nil {
+		n.stop()
+		return err
+	}
+	n.incarnation, n.down = incarnation, false
+	n.sealedParent, n.sealedAt = common.Hash{}, 0
+	return nil
+}
+
+// stop stops the engine and the chain of the node as if it crashed, the
+// database and the wal are kept.
+func (n *Node) stop() {
+	n.down = true
+	n.engine.Close()
+	n.sub.Unsubscribe()
+	n.wg.Wait()
+	n.txpool.Stop()
+	n.chain.Stop()
+}
+
+// resultLoop writes the blocks committed by the engine to the chain, the way
+// the miner worker does.
+func (n *Node) resultLoop(sub *event.TypeMuxSubscription, chain *core.BlockChain, cache *core.BlockChainCache) {
+	defer n.wg.Done()
+	for obj := range sub.Chan() {
+		result, ok := obj.Data.(pbfttypes.PbftResult)
+		if !ok || result.Block == nil {
+			continue
+		}
+		block := result.Block
+		if chain.HasBlock(block.Hash(), block.NumberU64()) {
+			continue
+		}
+		sealHash := block.Header().SealHash()
+		state, receipts := cache.ReadStateDB(sealHash), cache.ReadReceipts(sealHash)
+		if state == nil {
+			log.Warn("Simulated node misses the state of the committed block", "node", n.index, "number", block.NumberU64(), "hash", block.Hash())
+			continue
+		}
+		block.SetExtraData(result.ExtraData)
+		result.ChainStateUpdateCB()
+		if _, err := chain.WriteBlockWithState(block, receipts, state); err != nil {
+			log.Error("Simulated node failed to write the committed block", "node", n.index, "number", block.NumberU64(), "hash", block.Hash(), "err", err)
+			if result.SyncState != nil {
+				select {
+				case result.SyncState <- err:
+				default:
+				}
+			}
+			continue
+		}
+		n.sim.recordCommit(n.index, block)
+	}
+}
+
+// seal produces a block if the engine asks for one, the interval between the
+// blocks of a view is kept on the simulated clock.
+func (n *Node) seal(now mclock.AbsTime, interval time.Duration) {
+	if ok, _ := n.engine.ShouldSeal(time.Now()); !ok {
+		return
+	}
+	parent := n.engine.NextBaseBlock()
+	if parent.Hash() == n.sealedParent && now.Sub(n.sealedAt) < interval {
+		return
+	}
+	n.sealedParent, n.sealedAt = parent.Hash(), now
+
+	timestamp := uint64(common.Millis(time.Now()))
+	if timestamp <= parent.Time() {
+		timestamp = parent.Time() + 1
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   core.CalcGasLimit(parent, parent.GasLimit()),
+		Time:       timestamp,
+	}
+	if config := n.sim.genesis.Config; config.IsDynamicFee(header.Number) {
+		header.BaseFee = core.CalcBaseFee(config, parent.Header())
+	}
+	if err := n.engine.Prepare(n.chain, header); err != nil {
+		log.Error("Simulated node failed to prepare the header", "node", n.index, "number", header.Number, "err", err)
+		return
+	}
+	state, err := n.cache.MakeStateDB(parent)
+	if err != nil {
+		log.Error("Simulated node failed to create the state", "node", n.index, "number", header.Number, "err", err)
+		return
+	}
+	block, err := n.engine.Finalize(n.chain, header, state, nil, nil)
+	if err != nil {
+		log.Error("Simulated node failed to finalize the block", "node", n.index, "number", header.Number, "err", err)
+		return
+	}
+	sealHash := block.Header().SealHash()
+	n.cache.WriteStateDB(sealHash, state, block.NumberU64())
+	n.cache.WriteReceipts(sealHash, nil, block.NumberU64())
+	n.cache.AddSealBlock(sealHash, block.NumberU64())
+
+	results, complete := make(chan *types.Block, 1), make(chan struct{}, 1)
+	if err := n.engine.Seal(n.chain, block, results, nil, complete); err != nil {
+		log.Error("Simulated node failed to seal the block", "node", n.index, "number", header.Number, "err", err)
+	}
+}
+
+// receive passes a message from a peer to the engine the way the network
+// handler of the engine does.
+func (n *Node) receive(from *Node, msg ctypes.Message) {
+	info := ctypes.NewMsgInfo(msg, from.peerID)
+	switch m := msg.(type) {
+	case *protocols.PrepareBlock:
+		m.Block.ReceivedAt = time.Now()
+		n.engine.ReceiveMessage(info)
+	case *protocols.PrepareVote, *protocols.PreCommit, *protocols.ViewChange:
+		n.engine.ReceiveMessage(info)
+	case *protocols.PbftStatusData, *protocols.Ping, *protocols.Pong:
+		// Handled by the peers of the network handler.
+	default:
+		n.engine.ReceiveSyncMsg(info)
+	}
+}
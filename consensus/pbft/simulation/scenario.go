@@ -0,0 +1,238 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+)
+
+// Scenario is a simulation run described in JSON: the validators, the network
+// and the faults injected over time, with the heights expected on the way.
+// The durations are in milliseconds of simulated time.
+type Scenario struct {
+	Validators int     `json:"validators"` // Number of validators, 4 if zero
+	Period     uint64  `json:"period"`     // Duration of a view, 2000 if zero
+	Amount     uint32  `json:"amount"`     // Maximum number of blocks of a view, 1 if zero
+	Seed       int64   `json:"seed"`       // Seed of the random faults
+	Step       uint64  `json:"step"`       // Simulated time of a step, 50 if zero
+	Latency    uint64  `json:"latency"`    // Delay of every message
+	Jitter     uint64  `json:"jitter"`     // Maximum random delay added to every message
+	Duration   uint64  `json:"duration"`   // Length of the run, at least the time of the last event
+	Events     []Event `json:"events"`
+}
+
+// Event is an action of a scenario taken at a point of simulated time.
+//
+// The actions are:
+//
+//	partition   split the nodes into the groups, the others form a group of their own
+//	heal        remove the partition
+//	drop        drop the messages from a node to another at the rate
+//	delay       delay the messages from a node to another
+//	reorder     delay the messages at the rate by up to the window
+//	equivocate  make the node send conflicting blocks and votes
+//	honest      stop the node from equivocating
+//	clear       remove all the faults of the network
+//	crash       stop the node, keeping its chain and wal
+//	restart     restart the crashed node from its chain and wal
+//	expect      require every running node to reach the height within the timeout
+type Event struct {
+	At      uint64  `json:"at"`
+	Action  string  `json:"action"`
+	Node    int     `json:"node"`
+	Groups  [][]int `json:"groups,omitempty"`
+	From    *int    `json:"from,omitempty"` // Any node if not set
+	To      *int    `json:"to,omitempty"`   // Any node if not set
+	Rate    float64 `json:"rate,omitempty"`
+	Delay   uint64  `json:"delay,omitempty"`
+	Window  uint64  `json:"window,omitempty"`
+	Height  uint64  `json:"height,omitempty"`
+	Timeout uint64  `json:"timeout,omitempty"`
+}
+
+func (e *Event) String() string {
+	switch e.Action {
+	case "partition":
+		return fmt.Sprintf("partition %v", e.Groups)
+	case "drop":
+		return fmt.Sprintf("drop %s->%s rate=%v", formatNode(e.From), formatNode(e.To), e.Rate)
+	case "delay":
+		return fmt.Sprintf("delay %s->%s %v", formatNode(e.From), formatNode(e.To), millis(e.Delay))
+	case "reorder":
+		return fmt.Sprintf("reorder rate=%v window=%v", e.Rate, millis(e.Window))
+	case "equivocate", "honest", "crash", "restart":
+		return fmt.Sprintf("%s node %d", e.Action, e.Node)
+	case "expect":
+		return fmt.Sprintf("expect height %d within %v", e.Height, millis(e.Timeout))
+	}
+	return e.Action
+}
+
+func formatNode(index *int) string {
+	if index == nil || *index == AnyNode {
+		return "*"
+	}
+	return fmt.Sprint(*index)
+}
+
+func millis(ms uint64) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
+
+// LoadScenario reads a scenario from a JSON file.
+func LoadScenario(path string) (*Scenario, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var sc Scenario
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&sc); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %v", path, err)
+	}
+	if err := sc.validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %v", path, err)
+	}
+	return &sc, nil
+}
+
+// validate checks the actions and the nodes of the events.
+func (sc *Scenario) validate() error {
+	validators := sc.Validators
+	if validators == 0 {
+		validators = 4
+	}
+	checkNode := func(index int) error {
+		if index < 0 || index >= validators {
+			return fmt.Errorf("node %d out of range", index)
+		}
+		return nil
+	}
+	checkLink := func(index *int) error {
+		if index == nil || *index == AnyNode {
+			return nil
+		}
+		return checkNode(*index)
+	}
+	for i, e := range sc.Events {
+		var err error
+		switch e.Action {
+		case "heal", "clear", "reorder", "expect":
+		case "partition":
+			for _, group := range e.Groups {
+				for _, index := range group {
+					if err == nil {
+						err = checkNode(index)
+					}
+				}
+			}
+		case "drop", "delay":
+			if err = checkLink(e.From); err == nil {
+				err = checkLink(e.To)
+			}
+		case "equivocate", "honest", "crash", "restart":
+			err = checkNode(e.Node)
+		default:
+			err = fmt.Errorf("unknown action %q", e.Action)
+		}
+		if err != nil {
+			return fmt.Errorf("event %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// config returns the configuration of the simulation of the scenario.
+func (sc *Scenario) config(dataDir string) Config {
+	return Config{
+		Validators: sc.Validators,
+		Period:     sc.Period,
+		Amount:     sc.Amount,
+		Seed:       sc.Seed,
+		Step:       millis(sc.Step),
+		Latency:    millis(sc.Latency),
+		Jitter:     millis(sc.Jitter),
+		DataDir:    dataDir,
+	}
+}
+
+// Run runs the scenario, writing the events and the heights of the nodes to
+// out. The data of the nodes is kept in dataDir if it's set. It returns the
+// first expectation or safety violated.
+func (sc *Scenario) Run(dataDir string, out io.Writer) error {
+	if err := sc.validate(); err != nil {
+		return err
+	}
+	if out == nil {
+		out = ioutil.Discard
+	}
+	s, err := New(sc.config(dataDir))
+	if err != nil {
+		return err
+	}
+	defer s.Stop()
+
+	events := append([]Event(nil), sc.Events...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At < events[j].At })
+	for i := range events {
+		e := &events[i]
+		s.RunUntil(func() bool { return s.Elapsed() >= millis(e.At) }, millis(e.At)-s.Elapsed())
+		if err := s.CheckSafety(); err != nil {
+			return err
+		}
+		if err := sc.apply(s, e); err != nil {
+			fmt.Fprintf(out, "[%v] %s: %v\n", s.Elapsed(), e, err)
+			return err
+		}
+		fmt.Fprintf(out, "[%v] %s, heights %v\n", s.Elapsed(), e, s.Heights())
+	}
+	if end := millis(sc.Duration); s.Elapsed() < end {
+		s.Run(end - s.Elapsed())
+	}
+	stats := s.Stats()
+	fmt.Fprintf(out, "[%v] end, heights %v, %d messages sent, %d delivered, %d dropped, %d equivocated, %d blocks synchronized\n",
+		s.Elapsed(), s.Heights(), stats.Sent, stats.Delivered, stats.Dropped, stats.Equivocated, stats.Synchronized)
+	return s.CheckSafety()
+}
+
+// apply takes the action of the event.
+func (sc *Scenario) apply(s *Simulation, e *Event) error {
+	link := func(index *int) int {
+		if index == nil {
+			return AnyNode
+		}
+		return *index
+	}
+	switch e.Action {
+	case "partition":
+		s.Partition(e.Groups...)
+	case "heal":
+		s.Heal()
+	case "drop":
+		s.Drop(link(e.From), link(e.To), e.Rate)
+	case "delay":
+		s.Delay(link(e.From), link(e.To), millis(e.Delay))
+	case "reorder":
+		s.Reorder(e.Rate, millis(e.Window))
+	case "equivocate":
+		s.Equivocate(e.Node, true)
+	case "honest":
+		s.Equivocate(e.Node, false)
+	case "clear":
+		s.ClearFaults()
+	case "crash":
+		return s.Crash(e.Node)
+	case "restart":
+		return s.Restart(e.Node)
+	case "expect":
+		return s.WaitHeight(e.Height, millis(e.Timeout))
+	}
+	return nil
+}
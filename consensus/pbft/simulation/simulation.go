@@ -0,0 +1,399 @@
+// Package simulation runs pbft validators in-process on a simulated clock and
+// network to regression-test the consensus without real networks.
+//
+// Every validator runs a full consensus engine with its own chain and wal. The
+// view and synchronization timers of the engines run on a shared simulated
+// clock which only advances when the simulation is stepped, and the messages
+// of the engines are carried by a simulated network instead of p2p peers. The
+// network can partition the validators, drop, delay and reorder messages, and
+// make validators equivocate; validators can be crashed and restarted from
+// their wal. The committed blocks of all validators are checked against each
+// other for safety, and the heights they reach for liveness.
+//
+// The faults are decided with a random source seeded by the configuration in
+// the order of the senders and their messages, so a run with the same seed
+// injects the same faults for the same traffic. Runs aren't reproducible bit
+// for bit though: the engines process the messages on their own goroutines,
+// sign with fresh keys and stamp the blocks with the wall clock.
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/mclock"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+)
+
+const (
+	maxRounds     = 64  // Maximum rounds of message exchange of a step
+	maxSyncBlocks = 128 // Maximum blocks inserted into a lagging node per step
+)
+
+// Config configures a simulation.
+type Config struct {
+	Validators int           // Number of validators, 4 if zero
+	Period     uint64        // Duration of a view in milliseconds, 2000 if zero
+	Amount     uint32        // Maximum number of blocks of a view, 1 if zero
+	Seed       int64         // Seed of the random faults of the network
+	Step       time.Duration // Simulated time a step advances the clock by, 50ms if zero
+	Latency    time.Duration // Delay of every message
+	Jitter     time.Duration // Maximum random delay added to every message
+	Settle     time.Duration // Real time the engines have to stay quiet to end a round of a step, 5ms if zero
+	DataDir    string        // Directory of the data of the nodes, a temporary directory removed on Stop if empty
+}
+
+// commit is the first block committed at a height.
+type commit struct {
+	node int
+	hash common.Hash
+}
+
+// Simulation is a network of validators running on a simulated clock.
+type Simulation struct {
+	config     Config
+	clock      *mclock.Simulated
+	net        *network
+	nodes      []*Node
+	validators []configs.PbftNode
+	genesis    core.Genesis
+	dataDir    string
+	removeDir  bool
+	started    mclock.AbsTime
+
+	lock       sync.Mutex
+	commits    map[uint64]commit
+	violations []error
+}
+
+// New creates the validators of a simulation and starts their engines on the
+// genesis block.
+func New(config Config) (*Simulation, error) {
+	if config.Validators == 0 {
+		config.Validators = 4
+	}
+	if config.Period == 0 {
+		config.Period = 2000
+	}
+	if config.Amount == 0 {
+		config.Amount = 1
+	}
+	if config.Step == 0 {
+		config.Step = 50 * time.Millisecond
+	}
+	if config.Settle == 0 {
+		config.Settle = 5 * time.Millisecond
+	}
+	if config.Validators < 1 {
+		return nil, errors.New("no validators")
+	}
+	s := &Simulation{
+		config:  config,
+		clock:   new(mclock.Simulated),
+		dataDir: config.DataDir,
+		commits: make(map[uint64]commit),
+	}
+	if s.dataDir == "" {
+		dir, err := ioutil.TempDir("", "pbftsim")
+		if err != nil {
+			return nil, err
+		}
+		s.dataDir, s.removeDir = dir, true
+	}
+	s.net = newNetwork(s, config.Seed, config.Validators)
+	s.started = s.clock.Now()
+
+	nodeKeys, blsKeys, validators := pbft.GeneratePbftNode(config.Validators)
+	s.validators = validators
+	for i := 0; i < config.Validators; i++ {
+		db := rawdb.NewMemoryDatabase()
+		s.genesis, _ = pbft.CreateGenesis(db)
+		s.nodes = append(s.nodes, newNode(s, i, nodeKeys[i], blsKeys[i], db))
+	}
+	for _, node := range s.nodes {
+		if err := node.start(s.validators); err != nil {
+			s.Stop()
+			return nil, fmt.Errorf("failed to start node %d: %v", node.index, err)
+		}
+	}
+	return s, nil
+}
+
+// Stop stops the nodes and removes the temporary data directory.
+func (s *Simulation) Stop() {
+	for _, node := range s.nodes {
+		if !node.down {
+			node.stop()
+		}
+	}
+	if s.removeDir {
+		os.RemoveAll(s.dataDir)
+	}
+}
+
+// Nodes returns the validators of the simulation.
+func (s *Simulation) Nodes() []*Node {
+	return s.nodes
+}
+
+// Node returns the validator of the index.
+func (s *Simulation) Node(index int) *Node {
+	return s.nodes[index]
+}
+
+// Elapsed returns the simulated time since the start of the simulation.
+func (s *Simulation) Elapsed() time.Duration {
+	return s.clock.Now().Sub(s.started)
+}
+
+// Stats returns the counters of the network.
+func (s *Simulation) Stats() Stats {
+	stats := &s.net.stats
+	return Stats{
+		Sent:         atomic.LoadUint64(&stats.Sent),
+		Delivered:    atomic.LoadUint64(&stats.Delivered),
+		Dropped:      atomic.LoadUint64(&stats.Dropped),
+		Equivocated:  atomic.LoadUint64(&stats.Equivocated),
+		Synchronized: atomic.LoadUint64(&stats.Synchronized),
+	}
+}
+
+// Step advances the clock by a step. The nodes asked to propose seal their
+// blocks, then the messages due are exchanged in rounds until the engines
+// are quiet, and the nodes lagging behind a reachable peer insert the
+// committed blocks of the peer, the way the downloader does.
+func (s *Simulation) Step() {
+	s.clock.Run(s.config.Step)
+	now := s.clock.Now()
+
+	interval := time.Duration(s.config.Period/uint64(s.config.Amount)) * time.Millisecond
+	for _, node := range s.nodes {
+		if !node.down {
+			node.seal(now, interval)
+		}
+	}
+	for round := 0; round < maxRounds; round++ {
+		s.settle()
+		if s.net.schedule(now) == 0 && s.net.deliver(now) == 0 {
+			break
+		}
+	}
+	s.synchronize()
+}
+
+// Run steps the simulation for the simulated duration.
+func (s *Simulation) Run(d time.Duration) {
+	for end := s.clock.Now().Add(d); s.clock.Now() < end; {
+		s.Step()
+	}
+}
+
+// RunUntil steps the simulation until the condition holds, for at most the
+// simulated timeout. It returns whether the condition holds.
+func (s *Simulation) RunUntil(cond func() bool, timeout time.Duration) bool {
+	for end := s.clock.Now().Add(timeout); !cond(); s.Step() {
+		if s.clock.Now() >= end {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitHeight steps the simulation until every running node has committed the
+// block of the height, failing if it takes longer than the simulated timeout.
+func (s *Simulation) WaitHeight(height uint64, timeout time.Duration) error {
+	if s.RunUntil(func() bool { return s.MinHeight() >= height }, timeout) {
+		return nil
+	}
+	return fmt.Errorf("liveness violated: heights %v after %v, expected %d", s.Heights(), timeout, height)
+}
+
+// Heights returns the heights of the chains of the nodes.
+func (s *Simulation) Heights() []uint64 {
+	heights := make([]uint64, len(s.nodes))
+	for i, node := range s.nodes {
+		heights[i] = node.Height()
+	}
+	return heights
+}
+
+// MinHeight returns the lowest height of the running nodes.
+func (s *Simulation) MinHeight() uint64 {
+	var min uint64
+	first := true
+	for _, node := range s.nodes {
+		if node.down {
+			continue
+		}
+		if height := node.Height(); first || height < min {
+			min, first = height, false
+		}
+	}
+	return min
+}
+
+// CheckSafety returns an error if two nodes committed different blocks at the
+// same height.
+func (s *Simulation) CheckSafety() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.violations) > 0 {
+		return s.violations[0]
+	}
+	return nil
+}
+
+// recordCommit records a block committed by a node, and a safety violation
+// if another block was committed at its height.
+func (s *Simulation) recordCommit(index int, block *types.Block) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	number, hash := block.NumberU64(), block.Hash()
+	first, ok := s.commits[number]
+	if !ok {
+		s.commits[number] = commit{node: index, hash: hash}
+		return
+	}
+	if first.hash != hash {
+		s.violations = append(s.violations, fmt.Errorf("safety violated: node %d committed %s and node %d committed %s at height %d",
+			first.node, first.hash.TerminalString(), index, hash.TerminalString(), number))
+	}
+}
+
+// Crash stops a node as if its process was killed, the messages sent to the
+// node until it's restarted are lost.
+func (s *Simulation) Crash(index int) error {
+	node := s.nodes[index]
+	if node.down {
+		return fmt.Errorf("node %d is down", index)
+	}
+	node.stop()
+	return nil
+}
+
+// Restart restarts a crashed node from its chain and wal.
+func (s *Simulation) Restart(index int) error {
+	node := s.nodes[index]
+	if !node.down {
+		return fmt.Errorf("node %d is running", index)
+	}
+	return node.start(s.validators)
+}
+
+// Partition splits the nodes into groups which can't reach each other, the
+// nodes not in any group form a group of their own.
+func (s *Simulation) Partition(groups ...[]int) {
+	s.net.groups = make(map[int]int)
+	for i, group := range groups {
+		for _, index := range group {
+			s.net.groups[index] = i + 1
+		}
+	}
+}
+
+// Heal removes the partition.
+func (s *Simulation) Heal() {
+	s.net.groups = nil
+}
+
+// Drop drops the messages from a node to another at the rate, AnyNode
+// matches every node.
+func (s *Simulation) Drop(from, to int, rate float64) {
+	s.net.rules = append(s.net.rules, &rule{from: from, to: to, rate: rate})
+}
+
+// Delay delays the messages from a node to another, AnyNode matches every
+// node.
+func (s *Simulation) Delay(from, to int, delay time.Duration) {
+	s.net.rules = append(s.net.rules, &rule{from: from, to: to, delay: delay})
+}
+
+// Reorder delays the messages at the rate by a random duration within the
+// window, so they overtake each other.
+func (s *Simulation) Reorder(rate float64, window time.Duration) {
+	s.net.reorder, s.net.window = rate, window
+}
+
+// SetFilter sets a function deciding whether a message is delivered, nil
+// delivers every message.
+func (s *Simulation) SetFilter(filter Filter) {
+	s.net.filter = filter
+}
+
+// Equivocate makes a node send conflicting blocks and votes to its peers, or
+// stops it from doing so.
+func (s *Simulation) Equivocate(index int, on bool) {
+	if on {
+		s.net.equivocate[index] = true
+	} else {
+		delete(s.net.equivocate, index)
+	}
+}
+
+// ClearFaults heals the partition and removes the drops, delays, reordering,
+// filter and equivocation.
+func (s *Simulation) ClearFaults() {
+	s.Heal()
+	s.net.rules = nil
+	s.net.reorder, s.net.window = 0, 0
+	s.net.filter = nil
+	s.net.equivocate = make(map[int]bool)
+}
+
+// settle waits until the engines have been quiet for the settle duration.
+func (s *Simulation) settle() {
+	for sent := s.net.pending(); ; {
+		time.Sleep(s.config.Settle)
+		now := s.net.pending()
+		if now == sent {
+			return
+		}
+		sent = now
+	}
+}
+
+// synchronize inserts the committed blocks of the highest reachable peer
+// into the nodes lagging behind it by more than a block.
+func (s *Simulation) synchronize() {
+	for _, node := range s.nodes {
+		if node.down {
+			continue
+		}
+		height := node.Height()
+		var best *Node
+		for _, peer := range s.nodes {
+			if peer == node || peer.down || !s.net.linked(peer.index, node.index) {
+				continue
+			}
+			if peer.Height() > height+1 && (best == nil || peer.Height() > best.Height()) {
+				best = peer
+			}
+		}
+		if best == nil {
+			continue
+		}
+		var blocks types.Blocks
+		for number := height + 1; number <= best.Height() && len(blocks) < maxSyncBlocks; number++ {
+			block := best.chain.GetBlockByNumber(number)
+			if block == nil {
+				break
+			}
+			blocks = append(blocks, block)
+		}
+		if n, err := node.chain.InsertChain(blocks); err != nil {
+			log.Debug("Simulated node failed to insert the blocks of its peer", "node", node.index, "peer", best.index, "inserted", n, "err", err)
+		}
+		atomic.AddUint64(&s.net.stats.Synchronized, uint64(len(blocks)))
+	}
+}
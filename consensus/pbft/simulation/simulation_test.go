@@ -0,0 +1,203 @@
+package simulation
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+)
+
+func newTestSimulation(t *testing.T, config Config) *Simulation {
+	s, err := New(config)
+	if err != nil {
+		t.Fatalf("Failed to create the simulation: %v", err)
+	}
+	return s
+}
+
+// assertConsistent checks that the nodes didn't commit conflicting blocks and
+// share the blocks up to the lowest height.
+func assertConsistent(t *testing.T, s *Simulation) {
+	assert.NoError(t, s.CheckSafety())
+	height := s.MinHeight()
+	for number := uint64(1); number <= height; number++ {
+		hash := s.Node(0).Chain().GetBlockByNumber(number).Hash()
+		for _, node := range s.Nodes()[1:] {
+			assert.Equal(t, hash, node.Chain().GetBlockByNumber(number).Hash(), "block %d of node %d", number, node.Index())
+		}
+	}
+}
+
+func TestSimulation(t *testing.T) {
+	s := newTestSimulation(t, Config{})
+	defer s.Stop()
+
+	assert.NoError(t, s.WaitHeight(10, 20*time.Second))
+	assertConsistent(t, s)
+	stats := s.Stats()
+	assert.NotZero(t, stats.Sent)
+	assert.Zero(t, stats.Dropped)
+}
+
+func TestSimulation_Partition(t *testing.T) {
+	s := newTestSimulation(t, Config{Latency: 10 * time.Millisecond, Seed: 1})
+	defer s.Stop()
+	assert.NoError(t, s.WaitHeight(3, 20*time.Second))
+
+	// Neither half has a quorum.
+	s.Partition([]int{0, 1}, []int{2, 3})
+	s.Run(5 * time.Second)
+	height := s.MinHeight()
+	s.Run(10 * time.Second)
+	for _, h := range s.Heights() {
+		assert.Equal(t, height, h)
+	}
+
+	s.Heal()
+	assert.NoError(t, s.WaitHeight(height+5, 30*time.Second))
+	assertConsistent(t, s)
+}
+
+func TestSimulation_MinorityPartition(t *testing.T) {
+	s := newTestSimulation(t, Config{Latency: 10 * time.Millisecond, Seed: 2})
+	defer s.Stop()
+
+	s.Partition([]int{0, 1, 2})
+	assert.True(t, s.RunUntil(func() bool { return s.Node(0).Height() >= 8 }, 60*time.Second))
+	assert.True(t, s.Node(3).Height() < 8)
+
+	s.Heal()
+	assert.NoError(t, s.WaitHeight(10, 30*time.Second))
+	assertConsistent(t, s)
+	assert.NotZero(t, s.Stats().Synchronized)
+}
+
+func TestSimulation_CrashRestart(t *testing.T) {
+	s := newTestSimulation(t, Config{Latency: 10 * time.Millisecond})
+	defer s.Stop()
+	assert.NoError(t, s.WaitHeight(3, 20*time.Second))
+
+	assert.NoError(t, s.Crash(2))
+	assert.Error(t, s.Crash(2))
+	assert.Nil(t, s.Node(2).Engine())
+	crashed := s.Node(2).Height()
+	assert.NoError(t, s.WaitHeight(8, 60*time.Second))
+	assert.Equal(t, crashed, s.Node(2).Height())
+
+	_, err := os.Stat(filepath.Join(s.Node(2).DataDir(), "phoenixchain", "wal"))
+	assert.NoError(t, err)
+	assert.NoError(t, s.Restart(2))
+	assert.Error(t, s.Restart(2))
+	assert.NoError(t, s.WaitHeight(12, 60*time.Second))
+	assertConsistent(t, s)
+}
+
+func TestSimulation_Equivocation(t *testing.T) {
+	s := newTestSimulation(t, Config{Latency: 10 * time.Millisecond, Seed: 3})
+	defer s.Stop()
+
+	s.Equivocate(1, true)
+	assert.NoError(t, s.WaitHeight(10, 60*time.Second))
+	assertConsistent(t, s)
+	assert.NotZero(t, s.Stats().Equivocated)
+}
+
+func TestSimulation_LossyNetwork(t *testing.T) {
+	s := newTestSimulation(t, Config{Latency: 20 * time.Millisecond, Jitter: 30 * time.Millisecond, Seed: 4})
+	defer s.Stop()
+
+	s.Drop(AnyNode, AnyNode, 0.1)
+	s.Delay(0, AnyNode, 100*time.Millisecond)
+	s.Reorder(0.3, 200*time.Millisecond)
+	assert.NoError(t, s.WaitHeight(10, 60*time.Second))
+	assertConsistent(t, s)
+	assert.NotZero(t, s.Stats().Dropped)
+}
+
+func TestSimulation_Filter(t *testing.T) {
+	s := newTestSimulation(t, Config{})
+	defer s.Stop()
+
+	// Without the votes no block gets a quorum certificate.
+	s.SetFilter(func(from, to int, msg ctypes.Message) bool {
+		_, ok := msg.(*protocols.PrepareVote)
+		return !ok
+	})
+	err := s.WaitHeight(1, 10*time.Second)
+	if assert.Error(t, err) {
+		assert.True(t, strings.HasPrefix(err.Error(), "liveness violated"))
+	}
+	s.ClearFaults()
+	assert.NoError(t, s.WaitHeight(5, 30*time.Second))
+	assertConsistent(t, s)
+}
+
+func TestSimulation_CheckSafety(t *testing.T) {
+	s := &Simulation{commits: make(map[uint64]commit)}
+	block := pbft.NewBlock(common.Hash{}, 1)
+	s.recordCommit(0, block)
+	s.recordCommit(1, block)
+	assert.NoError(t, s.CheckSafety())
+
+	s.recordCommit(2, pbft.NewBlock(common.Hash{1}, 1))
+	assert.Error(t, s.CheckSafety())
+}
+
+func TestScenarios(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("No scenarios: %v", err)
+	}
+	for _, file := range files {
+		sc, err := LoadScenario(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			var out strings.Builder
+			if err := sc.Run("", &out); err != nil {
+				t.Errorf("Scenario failed: %v\n%s", err, out.String())
+			}
+		})
+	}
+}
+
+func TestLoadScenario(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pbftsim")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, test := range []struct {
+		json string
+		err  string
+	}{
+		{`{"events": [{"at": 10, "action": "crash", "node": 3}, {"action": "drop", "to": 1, "rate": 0.5}]}`, ""},
+		{`{"events": [{"action": "explode"}]}`, `unknown action "explode"`},
+		{`{"events": [{"action": "crash", "node": 4}]}`, "node 4 out of range"},
+		{`{"validators": 7, "events": [{"action": "partition", "groups": [[0, 1], [6, 7]]}]}`, "node 7 out of range"},
+		{`{"events": [{"action": "delay", "from": -1, "to": 9}]}`, "node 9 out of range"},
+		{`{"nodes": 4}`, "unknown field"},
+	} {
+		path := filepath.Join(dir, "scenario.json")
+		if err := ioutil.WriteFile(path, []byte(test.json), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadScenario(path)
+		if test.err == "" {
+			assert.NoError(t, err, test.json)
+		} else if assert.Error(t, err, test.json) {
+			assert.Contains(t, err.Error(), test.err)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/math"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/mclock"
 
 	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
@@ -868,10 +869,12 @@ type ViewState struct {
 	blockTree *ctypes.BlockTree
 }
 
-func NewViewState(period uint64, blockTree *ctypes.BlockTree) *ViewState {
+// NewViewState creates the view state, whose view timer runs on the clock,
+// which is the system clock if nil.
+func NewViewState(period uint64, blockTree *ctypes.BlockTree, clock mclock.Clock) *ViewState {
 	return &ViewState{
 		view:      newView(),
-		viewTimer: newViewTimer(period),
+		viewTimer: newViewTimer(period, clock),
 		blockTree: blockTree,
 	}
 }
@@ -1380,7 +1383,7 @@ func (vs *ViewState) IsDeadline() bool {
 	return vs.viewTimer.isDeadline()
 }
 
-func (vs *ViewState) ViewTimeout() <-chan time.Time {
+func (vs *ViewState) ViewTimeout() <-chan mclock.AbsTime {
 	return vs.viewTimer.timerChan()
 }
 
@@ -14,7 +14,7 @@ import (
 )
 
 func TestNewViewState(t *testing.T) {
-	viewState := NewViewState(BaseMs, nil)
+	viewState := NewViewState(BaseMs, nil, nil)
 	viewState.ResetView(1, 1,viewState.BlockNumber())
 
 	assert.Equal(t, uint64(1), viewState.Epoch())
@@ -81,7 +81,7 @@ func TestPrepareVoteQueue(t *testing.T) {
 }
 
 func TestPrepareVotes(t *testing.T) {
-	viewState := NewViewState(BaseMs, nil)
+	viewState := NewViewState(BaseMs, nil, nil)
 
 	var b *protocols.PrepareVote
 	for i := 0; i < 10; i++ {
@@ -97,7 +97,7 @@ func TestPrepareVotes(t *testing.T) {
 }
 
 func TestViewBlocks(t *testing.T) {
-	viewState := NewViewState(BaseMs, nil)
+	viewState := NewViewState(BaseMs, nil, nil)
 
 	var viewBlock *prepareViewBlock
 	for i := 0; i < 10; i++ {
@@ -121,7 +121,7 @@ var (
 )
 
 func TestViewVotes(t *testing.T) {
-	viewState := NewViewState(BaseMs, nil)
+	viewState := NewViewState(BaseMs, nil, nil)
 	votes := viewState.viewVotes
 	prepareVotes := []*protocols.PrepareVote{
 		{BlockIndex: uint32(5)},
@@ -144,7 +144,7 @@ func TestViewVotes(t *testing.T) {
 }
 
 func TestNewViewQC(t *testing.T) {
-	viewState := NewViewState(BaseMs, nil)
+	viewState := NewViewState(BaseMs, nil, nil)
 	viewQCs := viewState.viewQCs
 
 	for i := uint32(0); i < 10; i++ {
@@ -176,7 +176,7 @@ func newBlock(number uint64) *types.Block {
 }
 
 func TestNewViewBlock(t *testing.T) {
-	viewState := NewViewState(BaseMs, nil)
+	viewState := NewViewState(BaseMs, nil, nil)
 	for i := uint64(0); i < 10; i++ {
 		viewState.AddQCBlock(newBlock(i), &ctypes.QuorumCert{BlockNumber: i, BlockIndex: uint32(i)})
 	}
@@ -192,7 +192,7 @@ func TestNewViewBlock(t *testing.T) {
 }
 
 func TestNewViewChanges(t *testing.T) {
-	viewState := NewViewState(BaseMs, nil)
+	viewState := NewViewState(BaseMs, nil, nil)
 
 	var v *protocols.ViewChange
 	for i := uint32(0); i < 10; i++ {
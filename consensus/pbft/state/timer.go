@@ -3,6 +3,8 @@ package state
 import (
 	"math"
 	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/mclock"
 )
 
 const (
@@ -11,19 +13,25 @@ const (
 )
 
 type viewTimer struct {
-	//Timer last timeout
+	clock mclock.Clock
+
+	//Timer last timeout, on the wall clock and on the clock of the timer
 	deadline time.Time
-	timer    *time.Timer
+	expiry   mclock.AbsTime
+	timer    mclock.ChanTimer
 
 	//Time window length calculation module
 	timeInterval    viewTimeInterval
 	preViewInterval uint64
 }
 
-func newViewTimer(period uint64) *viewTimer {
-	timer := time.NewTimer(0)
+func newViewTimer(period uint64, clock mclock.Clock) *viewTimer {
+	if clock == nil {
+		clock = mclock.System{}
+	}
+	timer := clock.NewTimer(time.Duration(math.MaxInt64))
 	timer.Stop()
-	return &viewTimer{timer: timer,
+	return &viewTimer{clock: clock, timer: timer,
 		timeInterval:    viewTimeInterval{baseMs: period * uint64(time.Millisecond), exponentBase: exponentBase, maxExponent: maxExponent},
 		preViewInterval: 1,
 	}
@@ -45,6 +53,7 @@ func (t *viewTimer) setupTimer(viewInterval uint64) {
 	viewInterval = t.calViewInterval(viewInterval)
 	duration := t.timeInterval.getViewTimeInterval(viewInterval)
 	t.deadline = time.Now().Add(duration)
+	t.expiry = t.clock.Now().Add(duration)
 	t.stopTimer()
 	t.timer.Reset(duration)
 }
@@ -52,17 +61,17 @@ func (t *viewTimer) setupTimer(viewInterval uint64) {
 func (t *viewTimer) stopTimer() {
 	if !t.timer.Stop() {
 		select {
-		case <-t.timer.C:
+		case <-t.timer.C():
 		default:
 		}
 	}
 }
-func (t *viewTimer) timerChan() <-chan time.Time {
-	return t.timer.C
+func (t *viewTimer) timerChan() <-chan mclock.AbsTime {
+	return t.timer.C()
 }
 
 func (t viewTimer) isDeadline() bool {
-	return t.deadline.Before(time.Now())
+	return t.expiry < t.clock.Now()
 }
 
 // Calculate the time window of each view，time=b*e^m
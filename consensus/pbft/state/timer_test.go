@@ -1,16 +1,14 @@
 package state
 
 import (
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/mclock"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
 )
 
 func TestTimer(t *testing.T) {
-	timer := time.NewTimer(0)
-	timer.Stop()
-
-	viewTimer := &viewTimer{timer: timer, timeInterval: viewTimeInterval{baseMs: uint64(1 * time.Second), exponentBase: exponentBase, maxExponent: maxExponent}}
+	viewTimer := newViewTimer(1000, nil)
 	viewTimer.setupTimer(1)
 	assert.False(t, viewTimer.isDeadline())
 	select {
@@ -19,6 +17,35 @@ func TestTimer(t *testing.T) {
 	}
 }
 
+func TestTimerSimulatedClock(t *testing.T) {
+	clock := new(mclock.Simulated)
+	viewTimer := newViewTimer(1000, clock)
+	viewTimer.setupTimer(1)
+
+	clock.Run(999 * time.Millisecond)
+	assert.False(t, viewTimer.isDeadline())
+	select {
+	case <-viewTimer.timerChan():
+		t.Fatal("view timeout before the deadline")
+	default:
+	}
+
+	clock.Run(2 * time.Millisecond)
+	assert.True(t, viewTimer.isDeadline())
+	select {
+	case <-viewTimer.timerChan():
+	default:
+		t.Fatal("no view timeout after the deadline")
+	}
+
+	// A new view restarts the timer from the current time of the clock.
+	viewTimer.setupTimer(1)
+	assert.False(t, viewTimer.isDeadline())
+	clock.Run(time.Second + time.Millisecond)
+	assert.True(t, viewTimer.isDeadline())
+	assert.Len(t, viewTimer.timerChan(), 1)
+}
+
 func TestCalViewInterval(t *testing.T) {
 	type views struct {
 		in  uint64
@@ -33,7 +60,7 @@ func TestCalViewInterval(t *testing.T) {
 		{{2, 2}, {2, 3}, {2, 2}, {2, 3}},
 	}
 	for row, test := range testcases {
-		timer := newViewTimer(10)
+		timer := newViewTimer(10, nil)
 		timer.calViewInterval(1)
 		for cul, c := range test {
 			//fmt.Printf("row:%d, cul:%d, pre:%d in:%d, out:%d\n", row, cul, timer.preViewInterval, c.in, c.out)
@@ -302,6 +302,7 @@ func (pbft *Pbft) OnBlockQuorumCert(id string, msg *protocols.BlockQuorumCert) e
 	//pbft.trySendPrepareVote()
 	pbft.state.SetPrepareVoteQC(msg.BlockQC)
 	pbft.state.AddQC(msg.BlockQC)
+	pbft.health.AddPrepareQC(msg.BlockQC)
 
 	pbft.genPreCommit(msg.BlockQC)
 
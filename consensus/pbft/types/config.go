@@ -5,6 +5,8 @@ import (
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/mclock"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
 )
 
@@ -12,6 +14,8 @@ type OptionsConfig struct {
 	NodePriKey *ecdsa.PrivateKey `json:"-"`
 	NodeID     discover.NodeID   `json:"nodeID"`
 	BlsPriKey  *bls.SecretKey    `json:"-"`
+	Signer     Signer            `json:"-"` // Signs with the keys above if nil
+	Clock      mclock.Clock      `json:"-"` // Clock of the view and synchronization timers, the system clock if nil
 	WalMode    bool              `json:"walMode"`
 
 	PeerMsgQueueSize  uint64 `json:"peerMsgQueueSize"`
@@ -20,6 +24,13 @@ type OptionsConfig struct {
 	MaxQueuesLimit    int64  `json:"maxQueuesLimit"`    // The maximum value that a single node can send a message.
 	BlacklistDeadline int64  `json:"blacklistDeadline"` // Blacklist expiration time. unit: minute.
 
+	ProtectionFile string `json:"protectionFile"` // Watermarks of the local signer against double signing, unprotected if empty.
+
+	EvidenceReporter common.Address `json:"evidenceReporter"` // Account reporting the detected duplicate signatures, disabled if zero.
+
+	HealthDir    string `json:"healthDir"`
+	HealthWindow uint64 `json:"healthWindow"` // Number of committed blocks the consensus health is tracked for.
+
 	Period uint64 `json:"period"`
 	Amount uint32 `json:"amount"`
 }
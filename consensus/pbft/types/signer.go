@@ -0,0 +1,27 @@
+package types
+
+import (
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+)
+
+// Signer holds the keys of a validator and signs on its behalf. The keys may be
+// kept in the node itself or in a remote signer, in which case the signer refuses
+// to sign messages conflicting with the ones it signed before.
+type Signer interface {
+	// NodeID returns the node ID derived from the node key.
+	NodeID() discover.NodeID
+
+	// BlsPublicKey returns the public part of the BLS key.
+	BlsPublicKey() (*bls.PublicKey, error)
+
+	// SignSeal signs the seal hash of a block header with the node key.
+	SignSeal(header *types.Header) ([]byte, error)
+
+	// SignMsg signs a consensus message with the BLS key.
+	SignMsg(msg ConsensusMsg) ([]byte, error)
+
+	// SchnorrNIZKProve proves the possession of the BLS key.
+	SchnorrNIZKProve() (*bls.SchnorrProof, error)
+}
@@ -551,6 +551,12 @@ func (vp *ValidatorPool) Validators(epoch uint64) *pbfttypes.Validators {
 	return vp.currentValidators
 }
 
+// ValidatorsAt returns the validators of a committed block, which may be
+// older than the previous validators kept by the pool.
+func (vp *ValidatorPool) ValidatorsAt(blockNumber uint64) (*pbfttypes.Validators, error) {
+	return vp.agency.GetValidator(blockNumber)
+}
+
 // VerifyHeader verify block's header.
 func (vp *ValidatorPool) VerifyHeader(header *types.Header) error {
 	_, err := crypto.Ecrecover(header.SealHash().Bytes(), header.Signature())
@@ -579,6 +585,18 @@ func (vp *ValidatorPool) IsCandidateNode(nodeID discover.NodeID) bool {
 	return vp.agency.IsCandidateNode(nodeID)
 }
 
+// EpochValidators returns the validators of the epoch, which Len and
+// GetValidatorByIndex look up.
+func (vp *ValidatorPool) EpochValidators(epoch uint64) *pbfttypes.Validators {
+	vp.lock.RLock()
+	defer vp.lock.RUnlock()
+
+	if vp.epochToBlockNumber(epoch) <= vp.switchPoint {
+		return vp.prevValidators
+	}
+	return vp.currentValidators
+}
+
 // Len return number of validators.
 func (vp *ValidatorPool) Len(epoch uint64) int {
 	vp.lock.RLock()
@@ -0,0 +1,295 @@
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// The functions below work on the wal directory of a stopped node, the
+// directory is locked by the engine of a running node.
+
+var errNotEntryBoundary = errors.New("offset is not at the start of a journal message")
+
+// JournalFile is a journal file in the wal directory.
+type JournalFile struct {
+	ID   uint32
+	Size uint64
+}
+
+// JournalEntry is a message read from a journal file.
+type JournalEntry struct {
+	FileID    uint32
+	Offset    uint64 // Offset of the message in the file
+	Timestamp uint64 // Unix time in nanoseconds
+	MsgType   uint16
+	Msg       interface{}
+}
+
+// CorruptionError is returned when a journal file can't be read further.
+type CorruptionError struct {
+	FileID uint32
+	Offset uint64
+	Err    error
+}
+
+func (e *CorruptionError) Error() string {
+	return fmt.Sprintf("corrupt journal wal.%d at offset %d: %v", e.FileID, e.Offset, e.Err)
+}
+
+// ViewChangeQCEntry is a view change quorum certificate stored in the wal
+// database.
+type ViewChangeQCEntry struct {
+	Epoch        uint64
+	BlockNumber  uint64
+	ViewNumber   uint64
+	ViewChangeQC *ctypes.ViewChangeQC
+}
+
+// Meta is the content of the wal database.
+type Meta struct {
+	ChainState    *protocols.ChainState // Nil if no block was committed
+	ViewChange    *ViewChangeMessage    // Position of the last confirmed view in the journal, nil if none
+	ViewChangeQCs []*ViewChangeQCEntry
+}
+
+// ReadMeta reads the wal database of the wal directory at path.
+func ReadMeta(path string) (*Meta, error) {
+	db, err := leveldb.OpenFile(filepath.Join(path, metaDBName), &opt.Options{ReadOnly: true, ErrorIfMissing: true})
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	meta := new(Meta)
+	if data, err := db.Get(chainStateKey, nil); err == nil {
+		meta.ChainState = new(protocols.ChainState)
+		if err := rlp.DecodeBytes(data, meta.ChainState); err != nil {
+			return nil, errGetChainState
+		}
+	}
+	if data, err := db.Get(viewChangeKey, nil); err == nil {
+		meta.ViewChange = new(ViewChangeMessage)
+		if err := rlp.DecodeBytes(data, meta.ViewChange); err != nil {
+			return nil, errGetViewChangeMeta
+		}
+	}
+	it := db.NewIterator(util.BytesPrefix(viewChangeQCPrefix), nil)
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()[len(viewChangeQCPrefix):]
+		if len(key) != 8+len(viewChangeQCSplit)+8+len(viewChangeQCSplit)+8 {
+			continue
+		}
+		entry := &ViewChangeQCEntry{
+			Epoch:       binary.BigEndian.Uint64(key[0:8]),
+			BlockNumber: binary.BigEndian.Uint64(key[8+len(viewChangeQCSplit):]),
+			ViewNumber:  binary.BigEndian.Uint64(key[len(key)-8:]),
+		}
+		entry.ViewChangeQC = new(ctypes.ViewChangeQC)
+		if err := rlp.DecodeBytes(it.Value(), entry.ViewChangeQC); err != nil {
+			return nil, errGetViewChangeQC
+		}
+		meta.ViewChangeQCs = append(meta.ViewChangeQCs, entry)
+	}
+	return meta, it.Error()
+}
+
+// ListJournals returns the journal files of the wal directory at path in
+// ascending order.
+func ListJournals(path string) ([]JournalFile, error) {
+	var files []JournalFile
+	for _, f := range listJournalFiles(path) {
+		info, err := os.Stat(filepath.Join(path, f.name))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, JournalFile{ID: f.num, Size: uint64(info.Size())})
+	}
+	return files, nil
+}
+
+// ReadJournal reads the messages of the journal files of the wal directory at
+// path, starting at the offset of the file given. The engine loads the
+// messages from the position of the last confirmed view only.
+func ReadJournal(path string, fromFileID uint32, fromOffset uint64, fn func(entry *JournalEntry) error) error {
+	files, err := ListJournals(path)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.ID < fromFileID {
+			continue
+		}
+		offset := uint64(0)
+		if file.ID == fromFileID {
+			offset = fromOffset
+		}
+		if err := readJournalFile(path, file.ID, offset, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readJournalFile reads the messages of a journal file from the offset.
+func readJournalFile(path string, fileID uint32, offset uint64, fn func(entry *JournalEntry) error) error {
+	file, err := os.Open(filepath.Join(path, fmt.Sprintf("wal.%d", fileID)))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(int64(offset), io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReaderSize(file, readBufferLimitSize)
+	for {
+		entry, size, err := readJournalEntry(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return &CorruptionError{FileID: fileID, Offset: offset, Err: err}
+		}
+		entry.FileID, entry.Offset = fileID, offset
+		if err := fn(entry); err != nil {
+			return err
+		}
+		offset += size
+	}
+}
+
+// readJournalEntry reads the next message and returns its size in the file,
+// or io.EOF at the end of the file.
+func readJournalEntry(reader *bufio.Reader) (*JournalEntry, uint64, error) {
+	var index [10]byte
+	if n, err := io.ReadFull(reader, index[:]); err != nil {
+		if n == 0 && err == io.EOF {
+			return nil, 0, io.EOF
+		}
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	crc := binary.BigEndian.Uint32(index[0:4])
+	length := binary.BigEndian.Uint32(index[4:8])
+	msgType := binary.BigEndian.Uint16(index[8:10])
+
+	pack := make([]byte, length)
+	if _, err := io.ReadFull(reader, pack); err != nil {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	if crc32.Checksum(pack, crc32c) != crc {
+		return nil, 0, errors.New("crc mismatch")
+	}
+	if msgType < protocols.ConfirmedViewChangeMsg || msgType > protocols.SendPreCommitMsg {
+		return nil, 0, fmt.Errorf("unknown message type %d", msgType)
+	}
+	msg, err := WALDecode(pack, msgType)
+	if err != nil {
+		return nil, 0, err
+	}
+	var head struct {
+		Timestamp uint64
+		Data      rlp.RawValue
+	}
+	if err := rlp.DecodeBytes(pack, &head); err != nil {
+		return nil, 0, err
+	}
+	return &JournalEntry{Timestamp: head.Timestamp, MsgType: msgType, Msg: msg}, uint64(len(index)) + uint64(length), nil
+}
+
+// PruneJournals removes the journal files of the wal directory at path that
+// are before the position of the last confirmed view, which the engine
+// doesn't load anymore. It returns the removed files.
+func PruneJournals(path string) ([]uint32, error) {
+	meta, err := ReadMeta(path)
+	if err != nil {
+		return nil, err
+	}
+	if meta.ViewChange == nil {
+		return nil, nil
+	}
+	files, err := ListJournals(path)
+	if err != nil {
+		return nil, err
+	}
+	var removed []uint32
+	for _, file := range files {
+		if file.ID >= meta.ViewChange.FileID {
+			break
+		}
+		if err := os.Remove(filepath.Join(path, fmt.Sprintf("wal.%d", file.ID))); err != nil {
+			return removed, err
+		}
+		removed = append(removed, file.ID)
+	}
+	return removed, nil
+}
+
+// TruncateJournal drops the messages of the wal directory at path from the
+// offset of the journal file given, removing the later files. The offset has
+// to be at the start of a message, or at the start of a corrupted tail.
+func TruncateJournal(path string, fileID uint32, offset uint64) error {
+	files, err := ListJournals(path)
+	if err != nil {
+		return err
+	}
+	var size *uint64
+	for i, file := range files {
+		if file.ID == fileID {
+			size = &files[i].Size
+		}
+	}
+	if size == nil {
+		return fmt.Errorf("journal wal.%d not found", fileID)
+	}
+	if offset > *size {
+		return fmt.Errorf("offset %d is beyond the end of wal.%d", offset, fileID)
+	}
+
+	// Make sure the offset is at a message boundary.
+	boundary := offset == 0 || offset == *size
+	err = readJournalFile(path, fileID, 0, func(entry *JournalEntry) error {
+		if entry.Offset == offset {
+			boundary = true
+		}
+		if entry.Offset >= offset {
+			return io.EOF
+		}
+		return nil
+	})
+	if corrupt, ok := err.(*CorruptionError); ok && corrupt.Offset <= offset {
+		boundary, err = boundary || corrupt.Offset == offset, nil
+	}
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if !boundary {
+		return errNotEntryBoundary
+	}
+
+	if err := os.Truncate(filepath.Join(path, fmt.Sprintf("wal.%d", fileID)), int64(offset)); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.ID > fileID {
+			if err := os.Remove(filepath.Join(path, fmt.Sprintf("wal.%d", file.ID))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package wal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/syndtr/goleveldb/leveldb"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+func TestInspectWal(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "wal")
+	defer os.RemoveAll(tempDir)
+
+	wal, _ := NewWal(nil, tempDir)
+	wal.SetMockJournalLimitSize(2 * 1024)
+
+	chainState, err := testWalUpdateChainState(wal)
+	assert.Nil(t, err)
+	assert.Nil(t, wal.UpdateViewChangeQC(epoch, blockNumber, viewNumber, buildViewChangeQC()))
+
+	// Fill a few journal files.
+	for i := 0; i < 20; i++ {
+		assert.Nil(t, wal.WriteSync(buildSendPrepareVote()))
+		assert.Nil(t, wal.WriteSync(buildSendViewChange()))
+	}
+	wal.Close()
+
+	files, err := ListJournals(tempDir)
+	assert.Nil(t, err)
+	assert.True(t, len(files) > 2)
+
+	// All messages are read with their position.
+	var entries []*JournalEntry
+	assert.Nil(t, ReadJournal(tempDir, 0, 0, func(entry *JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	assert.Equal(t, 40, len(entries))
+	assert.IsType(t, &protocols.SendPrepareVote{}, entries[0].Msg)
+	assert.Equal(t, uint16(protocols.SendViewChangeMsg), entries[1].MsgType)
+	assert.NotZero(t, entries[1].Timestamp)
+
+	// Confirm a view in the middle of the journal, the engine would expire
+	// the earlier files itself.
+	confirmed := entries[20]
+	db, err := leveldb.OpenFile(filepath.Join(tempDir, metaDBName), nil)
+	assert.Nil(t, err)
+	data, _ := rlp.EncodeToBytes(&ViewChangeMessage{Epoch: epoch, ViewNumber: viewNumber + 1, FileID: confirmed.FileID, Seq: confirmed.Offset})
+	assert.Nil(t, db.Put(viewChangeKey, data, nil))
+	db.Close()
+
+	meta, err := ReadMeta(tempDir)
+	assert.Nil(t, err)
+	assert.True(t, meta.ChainState.Commit.EqualState(chainState.Commit))
+	assert.Equal(t, viewNumber+1, meta.ViewChange.ViewNumber)
+	assert.Equal(t, 1, len(meta.ViewChangeQCs))
+	assert.Equal(t, blockNumber, meta.ViewChangeQCs[0].BlockNumber)
+	assert.Equal(t, 3, len(meta.ViewChangeQCs[0].ViewChangeQC.QCs))
+
+	count := 0
+	assert.Nil(t, ReadJournal(tempDir, meta.ViewChange.FileID, meta.ViewChange.Seq, func(entry *JournalEntry) error {
+		count++
+		return nil
+	}))
+	assert.Equal(t, 20, count)
+
+	// A corrupted tail is reported, and can be truncated.
+	last := entries[len(entries)-1]
+	path := filepath.Join(tempDir, fmt.Sprintf("wal.%d", last.FileID))
+	data, _ = ioutil.ReadFile(path)
+	assert.Nil(t, ioutil.WriteFile(path, data[:len(data)-3], 0644))
+	err = ReadJournal(tempDir, 0, 0, func(*JournalEntry) error { return nil })
+	assert.Equal(t, &CorruptionError{FileID: last.FileID, Offset: last.Offset, Err: err.(*CorruptionError).Err}, err)
+	assert.Equal(t, errNotEntryBoundary, TruncateJournal(tempDir, last.FileID, last.Offset+1))
+	assert.Nil(t, TruncateJournal(tempDir, last.FileID, last.Offset))
+
+	// Truncating drops the later files.
+	third := entries[30]
+	assert.Nil(t, TruncateJournal(tempDir, third.FileID, third.Offset))
+	count = 0
+	assert.Nil(t, ReadJournal(tempDir, 0, 0, func(entry *JournalEntry) error {
+		count++
+		return nil
+	}))
+	assert.Equal(t, 30, count)
+
+	// Pruning removes the files before the confirmed view.
+	removed, err := PruneJournals(tempDir)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, removed)
+	files, _ = ListJournals(tempDir)
+	assert.Equal(t, confirmed.FileID, files[0].ID)
+}
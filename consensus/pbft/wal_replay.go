@@ -0,0 +1,188 @@
+package pbft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/validator"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/wal"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/event"
+)
+
+var errReplaySign = errors.New("signing is disabled while replaying a wal")
+
+// ReplayStep is the result of replaying a journal message.
+type ReplayStep struct {
+	Entry *wal.JournalEntry
+	View  string // View of the engine after the message
+	Err   error
+}
+
+// ReplayWal loads the wal directory at path into an engine of the validator
+// given, the way the engine of the node recovers on restart. The engine
+// starts on the committed block of the wal and neither executes blocks nor
+// signs or sends messages. It calls fn after each journal message, stops at
+// the first error like the recovery does and returns the engine status.
+func ReplayWal(path string, chainConfig *configs.ChainConfig, validators []configs.PbftNode, nodeID discover.NodeID, fn func(step *ReplayStep)) ([]byte, error) {
+	// The recovery of the messages sent expects the node to be a validator.
+	isValidator := false
+	for _, node := range validators {
+		if node.Node.ID == nodeID {
+			isValidator = true
+		}
+	}
+	if !isValidator {
+		return nil, fmt.Errorf("node %s is not a validator", nodeID.TerminalString())
+	}
+
+	meta, err := wal.ReadMeta(path)
+	if err != nil {
+		return nil, err
+	}
+	if meta.ChainState == nil {
+		return nil, errors.New("no committed chain state in the wal")
+	}
+
+	backend, err := newReplayBackend(chainConfig, meta.ChainState.Commit.Block, meta.ChainState.Commit.QuorumCert)
+	if err != nil {
+		return nil, err
+	}
+	optConfig := &ctypes.OptionsConfig{
+		NodeID:           nodeID,
+		Signer:           &replaySigner{nodeID: nodeID},
+		PeerMsgQueueSize: 1024,
+		Period:           chainConfig.Pbft.Period,
+		Amount:           chainConfig.Pbft.Amount,
+	}
+	engine := New(chainConfig.Pbft, optConfig, new(event.TypeMux), nil)
+	if engine == nil {
+		return nil, errors.New("failed to create the engine")
+	}
+	if err := engine.Start(backend, backend, backend, validator.NewStaticAgency(validators)); err != nil {
+		return nil, err
+	}
+	defer engine.Close()
+
+	result := make(chan error, 1)
+	var status []byte
+	engine.asyncCallCh <- func() {
+		var err error
+		utils.SetTrue(&engine.loading)
+		defer utils.SetFalse(&engine.loading)
+
+		if err := engine.recoveryChainState(meta.ChainState); err != nil {
+			result <- fmt.Errorf("recover chain state: %v", err)
+			return
+		}
+		if meta.ViewChange != nil {
+			err = wal.ReadJournal(path, meta.ViewChange.FileID, meta.ViewChange.Seq, func(entry *wal.JournalEntry) error {
+				err := engine.recoveryMsg(entry.Msg)
+				fn(&ReplayStep{Entry: entry, View: engine.state.ViewString(), Err: err})
+				return err
+			})
+		}
+		status, _ = json.Marshal(&Status{
+			Tree:      engine.blockTree,
+			State:     engine.state,
+			Validator: engine.IsConsensusNode(),
+		})
+		result <- err
+	}
+	err = <-result
+	return status, err
+}
+
+// replayBackend is the chain of a replayed engine, which holds the committed
+// block only and accepts any block.
+type replayBackend struct {
+	config  *configs.ChainConfig
+	current *types.Block
+	blocks  map[common.Hash]*types.Block
+}
+
+func newReplayBackend(config *configs.ChainConfig, block *types.Block, qc *ctypes.QuorumCert) (*replayBackend, error) {
+	// The engine starts on the quorum certificate kept in the block.
+	extra, err := ctypes.EncodeExtra(byte(pbftVersion), qc)
+	if err != nil {
+		return nil, err
+	}
+	block.SetExtraData(extra)
+	return &replayBackend{
+		config:  config,
+		current: block,
+		blocks:  map[common.Hash]*types.Block{block.Hash(): block},
+	}, nil
+}
+
+func (b *replayBackend) Config() *configs.ChainConfig { return b.config }
+
+func (b *replayBackend) CurrentHeader() *types.Header { return b.current.Header() }
+
+func (b *replayBackend) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if block := b.GetBlock(hash, number); block != nil {
+		return block.Header()
+	}
+	return nil
+}
+
+func (b *replayBackend) GetHeaderByNumber(number uint64) *types.Header {
+	for _, block := range b.blocks {
+		if block.NumberU64() == number {
+			return block.Header()
+		}
+	}
+	return nil
+}
+
+func (b *replayBackend) GetHeaderByHash(hash common.Hash) *types.Header {
+	if block, ok := b.blocks[hash]; ok {
+		return block.Header()
+	}
+	return nil
+}
+
+func (b *replayBackend) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if block, ok := b.blocks[hash]; ok && block.NumberU64() == number {
+		return block
+	}
+	return nil
+}
+
+func (b *replayBackend) CurrentBlock() *types.Block { return b.current }
+
+func (b *replayBackend) Execute(block *types.Block, parent *types.Block) error { return nil }
+
+func (b *replayBackend) ClearCache(block *types.Block) {}
+
+func (b *replayBackend) WriteBlock(block *types.Block) error {
+	b.blocks[block.Hash()] = block
+	b.current = block
+	return nil
+}
+
+func (b *replayBackend) ForkedReset(newHeader *types.Header, rollback []*types.Block) {}
+
+func (b *replayBackend) Reset(newBlock *types.Block) {}
+
+// replaySigner is the signer of a replayed engine, which refuses to sign.
+type replaySigner struct {
+	nodeID discover.NodeID
+}
+
+func (s *replaySigner) NodeID() discover.NodeID { return s.nodeID }
+
+func (s *replaySigner) BlsPublicKey() (*bls.PublicKey, error) { return nil, errReplaySign }
+
+func (s *replaySigner) SignSeal(header *types.Header) ([]byte, error) { return nil, errReplaySign }
+
+func (s *replaySigner) SignMsg(msg ctypes.ConsensusMsg) ([]byte, error) { return nil, errReplaySign }
+
+func (s *replaySigner) SchnorrNIZKProve() (*bls.SchnorrProof, error) { return nil, errReplaySign }
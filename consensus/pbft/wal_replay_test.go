@@ -0,0 +1,85 @@
+package pbft
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/wal"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+func TestWalReplay(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "wal")
+	defer os.RemoveAll(tempDir)
+
+	var nodes []configs.PbftNode
+	for i := 0; i < 4; i++ {
+		key, _ := crypto.GenerateKey()
+		nodes = append(nodes, configs.PbftNode{Node: *discover.NewNode(discover.PubkeyID(&key.PublicKey), nil, 0, 0)})
+	}
+	chainConfig := &configs.ChainConfig{Pbft: &configs.PbftConfig{Period: 10000, Amount: 10}}
+	qc := func(block *types.Block, index uint32) *ctypes.QuorumCert {
+		return &ctypes.QuorumCert{Epoch: 1, BlockHash: block.Hash(), BlockNumber: block.NumberU64(), BlockIndex: index, ValidatorSet: utils.NewBitArray(4)}
+	}
+
+	// The node committed the first block of the view, and voted for the fourth.
+	commit := NewBlock(common.Hash{}, 1)
+	lock := NewBlock(commit.Hash(), 2)
+	qcBlock := NewBlock(lock.Hash(), 3)
+	next := NewBlock(qcBlock.Hash(), 4)
+	nextVote := &protocols.PrepareVote{Epoch: 1, BlockHash: next.Hash(), BlockNumber: 4, BlockIndex: 3}
+
+	w, _ := wal.NewWal(nil, tempDir)
+	assert.Nil(t, w.UpdateChainState(&protocols.ChainState{
+		Commit: &protocols.State{Block: commit, QuorumCert: qc(commit, 0)},
+		Lock:   &protocols.State{Block: lock, QuorumCert: qc(lock, 1)},
+		QC:     []*protocols.State{{Block: qcBlock, QuorumCert: qc(qcBlock, 2)}},
+	}))
+	assert.Nil(t, w.UpdateViewChange(&wal.ViewChangeMessage{Epoch: 1}))
+	assert.Nil(t, w.WriteSync(&protocols.SendPrepareVote{Block: next, Vote: nextVote}))
+	w.Close()
+
+	// Only the wal of a validator is replayed.
+	_, err := ReplayWal(tempDir, chainConfig, nodes, discover.NodeID{1}, func(*ReplayStep) {})
+	assert.NotNil(t, err)
+
+	// The replay stops at a corrupted message.
+	journal, _ := wal.ListJournals(tempDir)
+	file, _ := os.OpenFile(filepath.Join(tempDir, fmt.Sprintf("wal.%d", journal[0].ID)), os.O_APPEND|os.O_WRONLY, 0644)
+	file.Write(make([]byte, 12))
+	file.Close()
+
+	var steps []*ReplayStep
+	status, err := ReplayWal(tempDir, chainConfig, nodes, nodes[1].Node.ID, func(step *ReplayStep) {
+		steps = append(steps, step)
+	})
+	assert.IsType(t, &wal.CorruptionError{}, err)
+	assert.Equal(t, 1, len(steps))
+	assert.Nil(t, steps[0].Err)
+	assert.Equal(t, nextVote.BlockHash, steps[0].Entry.Msg.(*protocols.SendPrepareVote).Vote.BlockHash)
+	assert.Equal(t, "{Epoch:1,ViewNumber:0,BlockNumber:4}", steps[0].View)
+
+	var s struct {
+		State struct {
+			View struct {
+				Epoch      uint64 `json:"epoch"`
+				ViewNumber uint64 `json:"viewNumber"`
+			} `json:"view"`
+		} `json:"state"`
+	}
+	assert.Nil(t, json.Unmarshal(status, &s))
+	assert.Equal(t, uint64(1), s.State.View.Epoch)
+}
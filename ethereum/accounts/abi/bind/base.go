@@ -113,24 +113,34 @@ func (c *BoundContract) Call(opts *CallOpts, result interface{}, method string,
 	if err != nil {
 		return err
 	}
+	output, err := c.call(opts, input)
+	if err != nil {
+		return err
+	}
+	return c.abi.Unpack(result, method, output)
+}
+
+// call executes a call with the given input, returning the output.
+func (c *BoundContract) call(opts *CallOpts, input []byte) ([]byte, error) {
 	var (
 		msg    = phoenixchain.CallMsg{From: opts.From, To: &c.address, Data: input}
 		ctx    = ensureContext(opts.Context)
 		code   []byte
 		output []byte
+		err    error
 	)
 	if opts.Pending {
 		pb, ok := c.caller.(PendingContractCaller)
 		if !ok {
-			return ErrNoPendingState
+			return nil, ErrNoPendingState
 		}
 		output, err = pb.PendingCallContract(ctx, msg)
 		if err == nil && len(output) == 0 {
 			// Make sure we have a contract to operate on, and bail out otherwise.
 			if code, err = pb.PendingCodeAt(ctx, c.address); err != nil {
-				return err
+				return nil, err
 			} else if len(code) == 0 {
-				return ErrNoCode
+				return nil, ErrNoCode
 			}
 		}
 	} else {
@@ -138,16 +148,13 @@ func (c *BoundContract) Call(opts *CallOpts, result interface{}, method string,
 		if err == nil && len(output) == 0 {
 			// Make sure we have a contract to operate on, and bail out otherwise.
 			if code, err = c.caller.CodeAt(ctx, c.address, opts.BlockNumber); err != nil {
-				return err
+				return nil, err
 			} else if len(code) == 0 {
-				return ErrNoCode
+				return nil, ErrNoCode
 			}
 		}
 	}
-	if err != nil {
-		return err
-	}
-	return c.abi.Unpack(result, method, output)
+	return output, err
 }
 
 // Transact invokes the (paid) contract method with params as input values.
@@ -256,6 +263,11 @@ func (c *BoundContract) FilterLogs(opts *FilterOpts, name string, query ...[]int
 	if err != nil {
 		return nil, nil, err
 	}
+	return c.filterLogs(opts, topics)
+}
+
+// filterLogs filters the contract logs matching the given topics for past blocks.
+func (c *BoundContract) filterLogs(opts *FilterOpts, topics [][]common.Hash) (chan types.Log, event.Subscription, error) {
 	// Start the background filtering
 	logs := make(chan types.Log, 128)
 
@@ -305,6 +317,11 @@ func (c *BoundContract) WatchLogs(opts *WatchOpts, name string, query ...[]inter
 	if err != nil {
 		return nil, nil, err
 	}
+	return c.watchLogs(opts, topics)
+}
+
+// watchLogs subscribes to the contract logs matching the given topics for future blocks.
+func (c *BoundContract) watchLogs(opts *WatchOpts, topics [][]common.Hash) (chan types.Log, event.Subscription, error) {
 	// Start the background filtering
 	logs := make(chan types.Log, 128)
 
@@ -0,0 +1,118 @@
+package bind
+
+import (
+	"fmt"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/wasmabi"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/event"
+)
+
+// WasmBoundContract is the base wrapper object that reflects a WASM contract
+// on the phoenixchain network. It is the counterpart of BoundContract using
+// the RLP based encoding of the WASM interpreter.
+type WasmBoundContract struct {
+	contract *BoundContract // Generic wrapper for the low level calls and transactions
+	abi      wasmabi.ABI    // Reflect based ABI to access the correct contract methods
+}
+
+// NewWasmBoundContract creates a low level WASM contract interface through
+// which calls and transactions may be made through.
+func NewWasmBoundContract(address common.Address, abi wasmabi.ABI, caller ContractCaller, transactor ContractTransactor, filterer ContractFilterer) *WasmBoundContract {
+	return &WasmBoundContract{
+		contract: NewBoundContract(address, emptyABI, caller, transactor, filterer),
+		abi:      abi,
+	}
+}
+
+// emptyABI stands for the Solidity ABI the underlying BoundContract never uses.
+var emptyABI abi.ABI
+
+// DeployWasmContract deploys a WASM contract onto the phoenixchain blockchain,
+// running its init function with params, and binds the deployment address
+// with a Go wrapper.
+func DeployWasmContract(opts *TransactOpts, abi wasmabi.ABI, code []byte, backend ContractBackend, params ...interface{}) (common.Address, *types.Transaction, *WasmBoundContract, error) {
+	c := NewWasmBoundContract(common.Address{}, abi, backend, backend, backend)
+
+	input, err := c.abi.PackDeploy(code, params...)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	tx, err := c.contract.transact(opts, nil, input)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	c.contract.address = crypto.CreateAddress(opts.From, tx.Nonce())
+	return c.contract.address, tx, c, nil
+}
+
+// Call invokes the contract method with params as input values and sets the
+// output to result, which must be a pointer to a value of the return type.
+func (c *WasmBoundContract) Call(opts *CallOpts, result interface{}, method string, params ...interface{}) error {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(CallOpts)
+	}
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return err
+	}
+	output, err := c.contract.call(opts, input)
+	if err != nil {
+		return err
+	}
+	return c.abi.Unpack(result, method, output)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (c *WasmBoundContract) Transact(opts *TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return nil, err
+	}
+	return c.contract.transact(opts, &c.contract.address, input)
+}
+
+// FilterLogs filters contract logs for past blocks, returning the necessary
+// channels to construct a strongly typed bound iterator on top of them.
+func (c *WasmBoundContract) FilterLogs(opts *FilterOpts, name string, query ...[]interface{}) (chan types.Log, event.Subscription, error) {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(FilterOpts)
+	}
+	topics, err := c.makeTopics(name, query...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.contract.filterLogs(opts, topics)
+}
+
+// WatchLogs filters subscribes to contract logs for future blocks, returning a
+// subscription object that can be used to tear down the watcher.
+func (c *WasmBoundContract) WatchLogs(opts *WatchOpts, name string, query ...[]interface{}) (chan types.Log, event.Subscription, error) {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(WatchOpts)
+	}
+	topics, err := c.makeTopics(name, query...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.contract.watchLogs(opts, topics)
+}
+
+func (c *WasmBoundContract) makeTopics(name string, query ...[]interface{}) ([][]common.Hash, error) {
+	e, ok := c.abi.Events[name]
+	if !ok {
+		return nil, fmt.Errorf("event '%s' not found", name)
+	}
+	return e.MakeTopics(query...)
+}
+
+// UnpackLog unpacks a retrieved log into the provided output structure.
+func (c *WasmBoundContract) UnpackLog(out interface{}, event string, log types.Log) error {
+	return c.abi.UnpackLog(out, event, log.Topics, log.Data)
+}
@@ -0,0 +1,191 @@
+package bind
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/wasmabi"
+)
+
+// BindWasm generates a Go wrapper around a WASM contract ABI, the counterpart
+// of Bind for contracts run by the WASM interpreter. The bytecodes are the hex
+// encoded WASM modules to generate deploy methods for.
+func BindWasm(types []string, abis []string, bytecodes []string, pkg string, aliases map[string]string) (string, error) {
+	var (
+		// contracts is the map of each individual contract requested binding
+		contracts = make(map[string]*tmplWasmContract)
+
+		// structs is the map of all struct definitions shared by passed contracts.
+		structs = make(map[string]*tmplWasmStruct)
+	)
+	for i := 0; i < len(types); i++ {
+		// Parse the actual ABI to generate the binding for
+		wasmABI, err := wasmabi.JSON(strings.NewReader(abis[i]))
+		if err != nil {
+			return "", err
+		}
+		// Strip any whitespace from the JSON ABI
+		strippedABI := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, abis[i])
+
+		for name, kind := range wasmABI.Structs {
+			fields := make([]*tmplWasmField, len(kind.StructElems))
+			for j, elem := range kind.StructElems {
+				fields[j] = &tmplWasmField{Type: bindTypeWasm(elem), Name: capitalise(kind.StructNames[j])}
+			}
+			structs[name] = &tmplWasmStruct{Name: capitalise(name), Fields: fields}
+		}
+		var (
+			calls     = make(map[string]*tmplWasmMethod)
+			transacts = make(map[string]*tmplWasmMethod)
+			events    = make(map[string]*tmplWasmEvent)
+
+			// identifiers are used to detect duplicated identifiers of functions
+			// and events once normalized.
+			callIdentifiers     = make(map[string]bool)
+			transactIdentifiers = make(map[string]bool)
+			eventIdentifiers    = make(map[string]bool)
+		)
+		for _, original := range wasmABI.Methods {
+			// Normalize the method for capital cases and non-anonymous inputs
+			normalized := original
+			normalizedName := capitalise(alias(aliases, original.Name))
+			// Ensure there is no duplicated identifier
+			var identifiers = callIdentifiers
+			if !original.Constant {
+				identifiers = transactIdentifiers
+			}
+			if identifiers[normalizedName] {
+				return "", fmt.Errorf("duplicated identifier \"%s\"(normalized \"%s\"), use --alias for renaming", original.Name, normalizedName)
+			}
+			identifiers[normalizedName] = true
+			normalized.Name = normalizedName
+			normalized.Inputs = normalizeWasmArgs(original.Inputs)
+
+			// Append the methods to the call or transact lists
+			if original.Constant {
+				calls[original.Name] = &tmplWasmMethod{Original: original, Normalized: normalized}
+			} else {
+				transacts[original.Name] = &tmplWasmMethod{Original: original, Normalized: normalized}
+			}
+		}
+		for _, original := range wasmABI.Events {
+			// Skip anonymous events as they don't support explicit filtering
+			if original.Anonymous {
+				continue
+			}
+			// Normalize the event for capital cases and non-anonymous inputs
+			normalized := original
+
+			// Ensure there is no duplicated identifier
+			normalizedName := capitalise(alias(aliases, original.Name))
+			if eventIdentifiers[normalizedName] {
+				return "", fmt.Errorf("duplicated identifier \"%s\"(normalized \"%s\"), use --alias for renaming", original.Name, normalizedName)
+			}
+			eventIdentifiers[normalizedName] = true
+			normalized.Name = normalizedName
+			normalized.Inputs = normalizeWasmArgs(original.Inputs)
+
+			// Append the event to the accumulator list
+			events[original.Name] = &tmplWasmEvent{Original: original, Normalized: normalized}
+		}
+		constructor := wasmABI.Constructor
+		constructor.Inputs = normalizeWasmArgs(constructor.Inputs)
+
+		contracts[types[i]] = &tmplWasmContract{
+			Type:        capitalise(types[i]),
+			InputABI:    strings.Replace(strippedABI, "\"", "\\\"", -1),
+			InputBin:    strings.TrimPrefix(strings.TrimSpace(bytecodes[i]), "0x"),
+			Constructor: constructor,
+			Calls:       calls,
+			Transacts:   transacts,
+			Events:      events,
+		}
+	}
+	// Generate the contract template data content and render it
+	data := &tmplWasmData{
+		Package:   pkg,
+		Contracts: contracts,
+		Structs:   structs,
+	}
+	buffer := new(bytes.Buffer)
+
+	funcs := map[string]interface{}{
+		"bindtype":      bindTypeWasm,
+		"bindtopictype": bindTopicTypeWasm,
+		"capitalise":    capitalise,
+		"decapitalise":  decapitalise,
+	}
+	tmpl := template.Must(template.New("").Funcs(funcs).Parse(tmplSourceWasmGo))
+	if err := tmpl.Execute(buffer, data); err != nil {
+		return "", err
+	}
+	// Pass the code through gofmt to clean it up
+	code, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("%v\n%s", err, buffer)
+	}
+	return string(code), nil
+}
+
+// normalizeWasmArgs names the anonymous arguments.
+func normalizeWasmArgs(args wasmabi.Arguments) wasmabi.Arguments {
+	normalized := make(wasmabi.Arguments, len(args))
+	copy(normalized, args)
+	for j, arg := range normalized {
+		if arg.Name == "" {
+			normalized[j].Name = fmt.Sprintf("arg%d", j)
+		}
+	}
+	return normalized
+}
+
+// bindTypeWasm converts WASM contract types to Go ones.
+func bindTypeWasm(kind *wasmabi.Type) string {
+	switch kind.T {
+	case wasmabi.BoolTy:
+		return "bool"
+	case wasmabi.UintTy:
+		return fmt.Sprintf("uint%d", kind.Size)
+	case wasmabi.IntTy:
+		return fmt.Sprintf("int%d", kind.Size)
+	case wasmabi.BigUintTy:
+		return "*big.Int"
+	case wasmabi.StringTy:
+		return "string"
+	case wasmabi.BytesTy:
+		return "[]byte"
+	case wasmabi.FixedBytesTy:
+		return fmt.Sprintf("[%d]byte", kind.Size)
+	case wasmabi.AddressTy:
+		return "common.Address"
+	case wasmabi.SliceTy:
+		return "[]" + bindTypeWasm(kind.Elem)
+	case wasmabi.ArrayTy:
+		return fmt.Sprintf("[%d]", kind.Size) + bindTypeWasm(kind.Elem)
+	case wasmabi.PairTy:
+		return fmt.Sprintf("struct{ First %s; Second %s }", bindTypeWasm(kind.Key), bindTypeWasm(kind.Elem))
+	case wasmabi.MapTy:
+		return fmt.Sprintf("[]struct{ Key %s; Value %s }", bindTypeWasm(kind.Key), bindTypeWasm(kind.Elem))
+	case wasmabi.StructTy:
+		return capitalise(kind.StructName)
+	}
+	return "interface{}"
+}
+
+// bindTopicTypeWasm converts WASM contract types to Go ones, used for indexed
+// event inputs which only keep a hash of the values that are not decodable.
+func bindTopicTypeWasm(kind *wasmabi.Type) string {
+	if kind.DecodableTopic() {
+		return bindTypeWasm(kind)
+	}
+	return "common.Hash"
+}
@@ -0,0 +1,38 @@
+package bind
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const wasmTestABI = `[
+	{"name":"Head","type":"struct","baseclass":[],"fields":[{"name":"title","type":"string"}]},
+	{"name":"Message","type":"struct","baseclass":["Head"],"fields":[{"name":"body","type":"string"}]},
+	{"name":"init","type":"Action","constant":false,"input":[{"name":"owner","type":"Address"}],"output":"void"},
+	{"name":"get_message","type":"Action","constant":true,"input":[{"name":"id","type":"uint64"}],"output":"Message"},
+	{"name":"Posted","type":"Event","anonymous":false,"topic":2,"input":[{"name":"from","type":"Address"},{"name":"title","type":"string"},{"name":"id","type":"uint64"}]}
+]`
+
+func TestBindWasm(t *testing.T) {
+	contractABI, err := ioutil.ReadFile("../../../../commands/chaintool/test/contracta.cpp.abi.json")
+	assert.Nil(t, err)
+
+	code, err := BindWasm([]string{"contractA", "board"}, []string{string(contractABI), wasmTestABI}, []string{"", "0x0061736d"}, "bindtest", nil)
+	assert.Nil(t, err)
+	for _, want := range []string{
+		"func (_ContractA *ContractATransactor) Atransfer1(opts *bind.TransactOpts, from string, to string, asset int32) (*types.Transaction, error)",
+		"func DeployBoard(auth *bind.TransactOpts, backend bind.ContractBackend, owner common.Address) (common.Address, *types.Transaction, *Board, error)",
+		"func (_Board *BoardCaller) GetMessage(opts *bind.CallOpts, id uint64) (Message, error)",
+		"func (_Board *BoardFilterer) FilterPosted(opts *bind.FilterOpts, from []common.Address, title []string) (*BoardPostedIterator, error)",
+		"Title common.Hash",
+	} {
+		assert.True(t, strings.Contains(code, want), "missing %q", want)
+	}
+	assert.False(t, strings.Contains(code, "func DeployContractA"))
+
+	_, err = BindWasm([]string{"board"}, []string{wasmTestABI}, []string{""}, "bindtest", map[string]string{"get_message": "Posted"})
+	assert.Nil(t, err)
+}
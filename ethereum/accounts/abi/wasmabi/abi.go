@@ -0,0 +1,335 @@
+// Package wasmabi implements the ABI of the WASM contracts executed by the
+// wagon interpreter.
+//
+// Calls are the RLP list of the FNV-64 hash of the function name followed by
+// the arguments, return values are RLP encoded. Structs are encoded as lists
+// holding their base classes first, and signed integers are zigzag encoded as
+// done by the contract development toolkit.
+package wasmabi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// initName is the function run when a contract is deployed.
+const initName = "init"
+
+// wasmMagic is the prefix of WASM contract deployments.
+var wasmMagic = []byte{0x00, 0x61, 0x73, 0x6d}
+
+// The ABI holds information about a WASM contract's context and available
+// invokable methods.
+type ABI struct {
+	Constructor Method
+	Methods     map[string]Method
+	Events      map[string]Event
+	Structs     map[string]*Type
+}
+
+// Argument holds the name and type of an argument.
+type Argument struct {
+	Name    string
+	Type    *Type
+	Indexed bool // indexed is only used by events
+}
+
+// Arguments holds the arguments of a method or event.
+type Arguments []Argument
+
+// Method represents a callable of the contract. ID is the hash of the name
+// that selects the method in a call.
+type Method struct {
+	Name     string
+	Constant bool
+	Inputs   Arguments
+	Outputs  Arguments
+	ID       uint64
+}
+
+// FuncID returns the hash selecting the function with the given name.
+func FuncID(name string) uint64 {
+	hash := fnv.New64()
+	hash.Write([]byte(name))
+	return hash.Sum64()
+}
+
+// String returns the C++ like signature of the method.
+func (method Method) String() string {
+	ret := "void"
+	if len(method.Outputs) > 0 {
+		ret = method.Outputs[0].Type.String()
+	}
+	return fmt.Sprintf("%s %s(%s)", ret, method.Name, method.Inputs.signature())
+}
+
+func (arguments Arguments) signature() string {
+	args := make([]string, len(arguments))
+	for i, arg := range arguments {
+		args[i] = arg.Type.String()
+		if arg.Indexed {
+			args[i] += " indexed"
+		}
+		if arg.Name != "" {
+			args[i] += " " + arg.Name
+		}
+	}
+	return strings.Join(args, ", ")
+}
+
+// pack encodes the arguments into a list of values for the rlp package.
+func (arguments Arguments) pack(args ...interface{}) ([]interface{}, error) {
+	if len(args) != len(arguments) {
+		return nil, fmt.Errorf("argument count mismatch: got %d for %d", len(args), len(arguments))
+	}
+	list := make([]interface{}, len(args))
+	for i, arg := range arguments {
+		packed, err := arg.Type.pack(reflect.ValueOf(args[i]))
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %v", arg.Name, err)
+		}
+		list[i] = packed
+	}
+	return list, nil
+}
+
+// JSON returns a parsed ABI interface and error if it failed.
+func JSON(reader io.Reader) (ABI, error) {
+	dec := json.NewDecoder(reader)
+
+	var abi ABI
+	if err := dec.Decode(&abi); err != nil {
+		return ABI{}, err
+	}
+	return abi, nil
+}
+
+// Pack the given method name to conform the ABI. The empty name packs the
+// arguments of the init function.
+func (abi ABI) Pack(name string, args ...interface{}) ([]byte, error) {
+	method := abi.Constructor
+	if name != "" {
+		var exist bool
+		if method, exist = abi.Methods[name]; !exist {
+			return nil, fmt.Errorf("method '%s' not found", name)
+		}
+	}
+	arguments, err := method.Inputs.pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(append([]interface{}{method.ID}, arguments...))
+}
+
+// PackDeploy packs the contract code and the arguments of its init function
+// into the input of a contract creation.
+func (abi ABI) PackDeploy(code []byte, args ...interface{}) ([]byte, error) {
+	input, err := abi.Pack("", args...)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := rlp.EncodeToBytes([][]byte{code, input})
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, wasmMagic...), enc...), nil
+}
+
+// Unpack the return value of the named method into v, which must be a
+// pointer.
+func (abi ABI) Unpack(v interface{}, name string, data []byte) error {
+	method, ok := abi.Methods[name]
+	if !ok {
+		return fmt.Errorf("wasmabi: could not locate named method")
+	}
+	if len(method.Outputs) == 0 {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("wasmabi: Unpack(non-pointer)")
+	}
+	rest, err := method.Outputs[0].Type.unpack(data, rv.Elem())
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return fmt.Errorf("wasmabi: %d trailing bytes after return value", len(rest))
+	}
+	return nil
+}
+
+// argumentMarshaling is an Argument as found in the JSON ABI.
+type argumentMarshaling struct {
+	Name    string
+	Type    string
+	Indexed bool
+}
+
+// fieldMarshaling is an entry of the JSON ABI. Besides the format with
+// "function" entries it accepts the one of the contract development toolkit,
+// where functions are "Action" entries with an "input" list and an "output"
+// type, events list their indexed inputs first and structs are declared in
+// "struct" entries.
+type fieldMarshaling struct {
+	Type      string
+	Name      string
+	Inputs    []argumentMarshaling
+	Input     []argumentMarshaling
+	Outputs   json.RawMessage
+	Output    json.RawMessage
+	Constant  json.RawMessage
+	Anonymous bool
+	Topic     int
+	BaseClass []string
+	Fields    []argumentMarshaling
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (abi *ABI) UnmarshalJSON(data []byte) error {
+	var fields []fieldMarshaling
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	abi.Methods = make(map[string]Method)
+	abi.Events = make(map[string]Event)
+	abi.Structs = make(map[string]*Type)
+
+	// Declare the structs first as they may be used before their definition.
+	for _, field := range fields {
+		if strings.ToLower(field.Type) == "struct" {
+			if _, exist := abi.Structs[field.Name]; exist {
+				return fmt.Errorf("duplicated struct %s", field.Name)
+			}
+			abi.Structs[field.Name] = newStructType(field.Name)
+		}
+	}
+	abi.Constructor = Method{Name: initName, ID: FuncID(initName)}
+	for _, field := range fields {
+		inputs, err := abi.arguments(append(field.Inputs, field.Input...))
+		if err != nil {
+			return fmt.Errorf("%s: %v", field.Name, err)
+		}
+		switch strings.ToLower(field.Type) {
+		case "function", "action":
+			constant, err := parseConstant(field.Constant)
+			if err != nil {
+				return fmt.Errorf("%s: %v", field.Name, err)
+			}
+			outputs, err := abi.outputs(field.Outputs, field.Output)
+			if err != nil {
+				return fmt.Errorf("%s: %v", field.Name, err)
+			}
+			method := Method{Name: field.Name, Constant: constant, Inputs: inputs, Outputs: outputs, ID: FuncID(field.Name)}
+			if field.Name == initName {
+				abi.Constructor = method
+				continue
+			}
+			if _, exist := abi.Methods[field.Name]; exist {
+				return fmt.Errorf("duplicated method %s", field.Name)
+			}
+			abi.Methods[field.Name] = method
+		case "event":
+			for i := range inputs {
+				inputs[i].Indexed = inputs[i].Indexed || i < field.Topic
+			}
+			if _, exist := abi.Events[field.Name]; exist {
+				return fmt.Errorf("duplicated event %s", field.Name)
+			}
+			abi.Events[field.Name] = NewEvent(field.Name, field.Anonymous, inputs)
+		case "struct":
+			st := abi.Structs[field.Name]
+			for _, base := range field.BaseClass {
+				typ, ok := abi.Structs[base]
+				if !ok {
+					return fmt.Errorf("%s: unknown base class %s", field.Name, base)
+				}
+				st.StructElems = append(st.StructElems, typ)
+				st.StructNames = append(st.StructNames, base)
+			}
+			members, err := abi.arguments(field.Fields)
+			if err != nil {
+				return fmt.Errorf("%s: %v", field.Name, err)
+			}
+			for _, member := range members {
+				st.StructElems = append(st.StructElems, member.Type)
+				st.StructNames = append(st.StructNames, member.Name)
+			}
+		default:
+			return fmt.Errorf("unknown abi entry type %q", field.Type)
+		}
+	}
+	return nil
+}
+
+func (abi *ABI) arguments(args []argumentMarshaling) (Arguments, error) {
+	arguments := make(Arguments, len(args))
+	for i, arg := range args {
+		typ, err := NewType(arg.Type, abi.Structs)
+		if err != nil {
+			return nil, err
+		}
+		arguments[i] = Argument{Name: arg.Name, Type: typ, Indexed: arg.Indexed}
+	}
+	return arguments, nil
+}
+
+// outputs parses the return value, given either as a list of arguments or as
+// a single type name.
+func (abi *ABI) outputs(raws ...json.RawMessage) (Arguments, error) {
+	var outputs Arguments
+	for _, raw := range raws {
+		if len(raw) == 0 {
+			continue
+		}
+		var (
+			name string
+			args []argumentMarshaling
+		)
+		if err := json.Unmarshal(raw, &name); err == nil {
+			if name != "" && name != "void" {
+				args = []argumentMarshaling{{Type: name}}
+			}
+		} else if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, err
+		}
+		parsed, err := abi.arguments(args)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, parsed...)
+	}
+	if len(outputs) > 1 {
+		return nil, errors.New("multiple return values are not supported")
+	}
+	return outputs, nil
+}
+
+// parseConstant accepts both boolean and string flags.
+func parseConstant(raw json.RawMessage) (bool, error) {
+	if len(raw) == 0 {
+		return false, nil
+	}
+	var constant bool
+	if err := json.Unmarshal(raw, &constant); err == nil {
+		return constant, nil
+	}
+	var flag string
+	if err := json.Unmarshal(raw, &flag); err != nil {
+		return false, err
+	}
+	switch flag {
+	case "true":
+		return true, nil
+	case "false", "":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid constant flag %q", flag)
+}
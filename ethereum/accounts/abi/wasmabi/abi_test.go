@@ -0,0 +1,130 @@
+package wasmabi
+
+import (
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// cdtABI uses the format of the contract development toolkit.
+const cdtABI = `[
+	{"name":"Head","type":"struct","baseclass":[],"fields":[{"name":"title","type":"string"}]},
+	{"name":"Message","type":"struct","baseclass":["Head"],"fields":[{"name":"body","type":"string"},{"name":"tags","type":"list<uint16>"}]},
+	{"name":"init","type":"Action","constant":false,"input":[{"name":"owner","type":"FixedHash<20>"}],"output":"void"},
+	{"name":"put","type":"Action","constant":false,"input":[{"name":"msg","type":"Message"},{"name":"scores","type":"map<string,int64>"}],"output":"void"},
+	{"name":"get","type":"Action","constant":true,"input":[{"name":"id","type":"uint64"}],"output":"Message"},
+	{"name":"Transfer","type":"Event","anonymous":false,"topic":2,"input":[{"name":"from","type":"Address"},{"name":"memo","type":"string"},{"name":"amount","type":"uint128"},{"name":"delta","type":"int32"}]}
+]`
+
+func TestParseContractABI(t *testing.T) {
+	f, err := os.Open("../../../../commands/chaintool/test/contracta.cpp.abi.json")
+	assert.Nil(t, err)
+	defer f.Close()
+	parsed, err := JSON(f)
+	assert.Nil(t, err)
+	assert.Len(t, parsed.Methods, 6)
+
+	method := parsed.Methods["atransfer1"]
+	assert.False(t, method.Constant)
+	assert.Equal(t, "int32 atransfer1(string from, string to, int32 asset)", method.String())
+
+	input, err := parsed.Pack("atransfer1", "alice", "bob", int32(-3))
+	assert.Nil(t, err)
+	want, _ := rlp.EncodeToBytes([]interface{}{FuncID("atransfer1"), "alice", "bob", uint64(5)})
+	assert.Equal(t, want, input)
+
+	_, err = parsed.Pack("atransfer1", "alice", "bob", uint32(3))
+	assert.NotNil(t, err)
+	_, err = parsed.Pack("atransfer1", "alice", "bob")
+	assert.NotNil(t, err)
+
+	var ret int32
+	out, _ := rlp.EncodeToBytes(uint64(7)) // zigzag encoding of -4
+	assert.Nil(t, parsed.Unpack(&ret, "atransfer1", out))
+	assert.Equal(t, int32(-4), ret)
+}
+
+func TestStructsAndDeploy(t *testing.T) {
+	parsed, err := JSON(strings.NewReader(cdtABI))
+	assert.Nil(t, err)
+	assert.Len(t, parsed.Methods, 2)
+	assert.Equal(t, FuncID("init"), parsed.Constructor.ID)
+	assert.True(t, parsed.Methods["get"].Constant)
+
+	type Head struct{ Title string }
+	type Message struct {
+		Head Head
+		Body string
+		Tags []uint16
+	}
+	type Score struct {
+		Key   string
+		Value int64
+	}
+	msg := Message{Head: Head{"hi"}, Body: "hello", Tags: []uint16{1, 300}}
+	input, err := parsed.Pack("put", msg, []Score{{"a", 1}, {"b", -1}})
+	assert.Nil(t, err)
+	want, _ := rlp.EncodeToBytes([]interface{}{
+		FuncID("put"),
+		[]interface{}{[]interface{}{"hi"}, "hello", []uint64{1, 300}},
+		[]interface{}{[]interface{}{"a", uint64(2)}, []interface{}{"b", uint64(1)}},
+	})
+	assert.Equal(t, want, input)
+
+	// Return values decode into typed structs as well as generic values.
+	out, _ := rlp.EncodeToBytes([]interface{}{[]interface{}{"hi"}, "hello", []uint64{1, 300}})
+	var typed Message
+	assert.Nil(t, parsed.Unpack(&typed, "get", out))
+	assert.Equal(t, msg, typed)
+	var generic interface{}
+	assert.Nil(t, parsed.Unpack(&generic, "get", out))
+	assert.Equal(t, "hello", reflect.ValueOf(generic).FieldByName("Body").String())
+
+	owner := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	code := []byte{0x00, 0x61, 0x73, 0x6d, 0x01}
+	deploy, err := parsed.PackDeploy(code, owner)
+	assert.Nil(t, err)
+	initInput, _ := rlp.EncodeToBytes([]interface{}{FuncID("init"), owner})
+	enc, _ := rlp.EncodeToBytes([][]byte{code, initInput})
+	assert.Equal(t, append(wasmMagic, enc...), deploy)
+}
+
+func TestEvents(t *testing.T) {
+	parsed, err := JSON(strings.NewReader(cdtABI))
+	assert.Nil(t, err)
+	event := parsed.Events["Transfer"]
+	assert.Equal(t, "event Transfer(Address indexed from, string indexed memo, uint128 amount, int32 delta)", event.String())
+	assert.Equal(t, common.BytesToHash([]byte("Transfer")), event.ID)
+
+	from := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	memo := "a memo that is longer than a single topic"
+	fromEnc, _ := rlp.EncodeToBytes(from)
+	topics := []common.Hash{event.ID, common.BytesToHash(fromEnc), crypto.Keccak256Hash([]byte(memo))}
+	data, _ := rlp.EncodeToBytes([]interface{}{big.NewInt(1000), uint64(3)})
+
+	query, err := event.MakeTopics([]interface{}{from}, []interface{}{memo})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]common.Hash{{topics[0]}, {topics[1]}, {topics[2]}}, query)
+
+	var transfer struct {
+		From   common.Address
+		Memo   common.Hash
+		Amount *big.Int
+		Delta  int32
+	}
+	assert.Nil(t, parsed.UnpackLog(&transfer, "Transfer", topics, data))
+	assert.Equal(t, from, transfer.From)
+	assert.Equal(t, topics[2], transfer.Memo)
+	assert.Equal(t, int64(1000), transfer.Amount.Int64())
+	assert.Equal(t, int32(-2), transfer.Delta)
+
+	assert.NotNil(t, parsed.UnpackLog(&transfer, "Transfer", topics[1:], data))
+}
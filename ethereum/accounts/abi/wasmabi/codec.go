@@ -0,0 +1,266 @@
+package wasmabi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// pack converts v into a value the rlp package encodes the way the contract
+// expects it. Signed integers are zigzag encoded like the CDT does.
+func (t *Type) pack(v reflect.Value) (interface{}, error) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return nil, fmt.Errorf("wasmabi: cannot use nil as type %v", t)
+	}
+	switch t.T {
+	case BoolTy:
+		if v.Kind() == reflect.Bool {
+			return v.Bool(), nil
+		}
+	case UintTy:
+		switch v.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if u := v.Uint(); t.Size < 64 && u>>uint(t.Size) != 0 {
+				return nil, fmt.Errorf("wasmabi: %d overflows %v", u, t)
+			}
+			return v.Uint(), nil
+		}
+	case IntTy:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i := v.Int()
+			if t.Size < 64 && (i < -1<<uint(t.Size-1) || i >= 1<<uint(t.Size-1)) {
+				return nil, fmt.Errorf("wasmabi: %d overflows %v", i, t)
+			}
+			return uint64(i<<1) ^ uint64(i>>63), nil
+		}
+	case BigUintTy:
+		if v.Type() == bigT {
+			b := v.Interface().(*big.Int)
+			if b == nil || b.Sign() < 0 || b.BitLen() > t.Size {
+				return nil, fmt.Errorf("wasmabi: %v out of range of %v", b, t)
+			}
+			return b, nil
+		}
+	case StringTy:
+		if v.Kind() == reflect.String {
+			return v.String(), nil
+		}
+	case BytesTy:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), nil
+		}
+	case FixedBytesTy, AddressTy:
+		if v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8 && v.Len() == t.Size {
+			b := make([]byte, t.Size)
+			reflect.Copy(reflect.ValueOf(b), v)
+			return b, nil
+		}
+	case SliceTy, ArrayTy:
+		if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && (t.T == SliceTy || v.Len() == t.Size) {
+			list := make([]interface{}, v.Len())
+			for i := range list {
+				elem, err := t.Elem.pack(v.Index(i))
+				if err != nil {
+					return nil, err
+				}
+				list[i] = elem
+			}
+			return list, nil
+		}
+	case PairTy:
+		if v.Kind() == reflect.Struct && v.NumField() == 2 {
+			return packFields([]*Type{t.Key, t.Elem}, v)
+		}
+	case MapTy:
+		if v.Kind() == reflect.Slice && indirectType(v.Type().Elem()).Kind() == reflect.Struct {
+			list := make([]interface{}, v.Len())
+			for i := range list {
+				entry := indirect(v.Index(i))
+				if !entry.IsValid() || entry.NumField() != 2 {
+					return nil, fmt.Errorf("wasmabi: cannot use %v as type %v", v.Type(), t)
+				}
+				pair, err := packFields([]*Type{t.Key, t.Elem}, entry)
+				if err != nil {
+					return nil, err
+				}
+				list[i] = pair
+			}
+			return list, nil
+		}
+	case StructTy:
+		if v.Kind() == reflect.Struct && v.NumField() == len(t.StructElems) {
+			return packFields(t.StructElems, v)
+		}
+	}
+	return nil, fmt.Errorf("wasmabi: cannot use %v as type %v", v.Type(), t)
+}
+
+func packFields(types []*Type, v reflect.Value) ([]interface{}, error) {
+	list := make([]interface{}, len(types))
+	for i, typ := range types {
+		elem, err := typ.pack(v.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		list[i] = elem
+	}
+	return list, nil
+}
+
+// encode returns the RLP encoding of v.
+func (t *Type) encode(v interface{}) ([]byte, error) {
+	packed, err := t.pack(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(packed)
+}
+
+// unpack decodes the first RLP value of data into v, which must be settable,
+// and returns the remaining data. An interface v receives a value of the
+// reflection type of t.
+func (t *Type) unpack(data []byte, v reflect.Value) ([]byte, error) {
+	switch {
+	case v.Kind() == reflect.Interface:
+		value := reflect.New(t.GetType()).Elem()
+		rest, err := t.unpack(data, value)
+		if err != nil {
+			return nil, err
+		}
+		v.Set(value)
+		return rest, nil
+	case v.Kind() == reflect.Ptr && v.Type() != bigT:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return t.unpack(data, v.Elem())
+	}
+	kind, content, rest, err := rlp.Split(data)
+	if err != nil {
+		return nil, err
+	}
+	if list := t.T >= SliceTy; list != (kind == rlp.List) {
+		return nil, fmt.Errorf("wasmabi: invalid encoding of %v", t)
+	}
+	switch t.T {
+	case BoolTy:
+		if v.Kind() == reflect.Bool {
+			if len(content) > 1 || (len(content) == 1 && content[0] != 1) {
+				return nil, fmt.Errorf("wasmabi: invalid bool %x", content)
+			}
+			v.SetBool(len(content) == 1)
+			return rest, nil
+		}
+	case UintTy, IntTy:
+		if len(content) > 8 {
+			return nil, fmt.Errorf("wasmabi: %x overflows %v", content, t)
+		}
+		var u uint64
+		for _, b := range content {
+			u = u<<8 | uint64(b)
+		}
+		switch v.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if t.T == UintTy && !v.OverflowUint(u) {
+				v.SetUint(u)
+				return rest, nil
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if i := int64(u>>1) ^ -int64(u&1); t.T == IntTy && !v.OverflowInt(i) {
+				v.SetInt(i)
+				return rest, nil
+			}
+		}
+	case BigUintTy:
+		if v.Type() == bigT {
+			v.Set(reflect.ValueOf(new(big.Int).SetBytes(content)))
+			return rest, nil
+		}
+	case StringTy:
+		if v.Kind() == reflect.String {
+			v.SetString(string(content))
+			return rest, nil
+		}
+	case BytesTy:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(append([]byte{}, content...))
+			return rest, nil
+		}
+	case FixedBytesTy, AddressTy:
+		if v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8 && v.Len() == t.Size {
+			if len(content) != t.Size {
+				return nil, fmt.Errorf("wasmabi: invalid length %d of %v", len(content), t)
+			}
+			reflect.Copy(v, reflect.ValueOf(content))
+			return rest, nil
+		}
+	case SliceTy, ArrayTy, MapTy:
+		elem := t.Elem
+		if t.T == MapTy {
+			elem = &Type{T: PairTy, Key: t.Key, Elem: t.Elem, stringKind: t.stringKind}
+		}
+		switch {
+		case v.Kind() == reflect.Slice && t.T != ArrayTy:
+			v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+			for len(content) > 0 {
+				item := reflect.New(v.Type().Elem()).Elem()
+				if content, err = elem.unpack(content, item); err != nil {
+					return nil, err
+				}
+				v.Set(reflect.Append(v, item))
+			}
+			return rest, nil
+		case v.Kind() == reflect.Array && t.T == ArrayTy && v.Len() == t.Size:
+			for i := 0; i < t.Size; i++ {
+				if content, err = elem.unpack(content, v.Index(i)); err != nil {
+					return nil, err
+				}
+			}
+			if len(content) > 0 {
+				return nil, fmt.Errorf("wasmabi: too many elements for %v", t)
+			}
+			return rest, nil
+		}
+	case PairTy:
+		if v.Kind() == reflect.Struct && v.NumField() == 2 {
+			return rest, unpackFields([]*Type{t.Key, t.Elem}, content, v)
+		}
+	case StructTy:
+		if v.Kind() == reflect.Struct && v.NumField() == len(t.StructElems) {
+			return rest, unpackFields(t.StructElems, content, v)
+		}
+	}
+	return nil, fmt.Errorf("wasmabi: cannot unmarshal %v into Go value of type %v", t, v.Type())
+}
+
+func unpackFields(types []*Type, content []byte, v reflect.Value) error {
+	var err error
+	for i, typ := range types {
+		if content, err = typ.unpack(content, v.Field(i)); err != nil {
+			return err
+		}
+	}
+	if len(content) > 0 {
+		return fmt.Errorf("wasmabi: too many fields for %v", v.Type())
+	}
+	return nil
+}
+
+// indirect dereferences pointers and interfaces, except for big integers.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Interface || (v.Kind() == reflect.Ptr && v.Type() != bigT)) {
+		v = v.Elem()
+	}
+	return v
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,183 @@
+package wasmabi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// Event is an event potentially triggered by the contract through the
+// phoenixchain_event host function. The first topic of a log is the event
+// name unless the event is anonymous, the next ones are the indexed inputs
+// and the data is the RLP list of the other inputs.
+type Event struct {
+	Name      string
+	Anonymous bool
+	Inputs    Arguments
+	ID        common.Hash
+}
+
+// NewEvent creates a new Event.
+func NewEvent(name string, anonymous bool, inputs Arguments) Event {
+	return Event{
+		Name:      name,
+		Anonymous: anonymous,
+		Inputs:    inputs,
+		ID:        topicHash([]byte(name)),
+	}
+}
+
+// String returns the C++ like signature of the event.
+func (e Event) String() string {
+	return fmt.Sprintf("event %s(%s)", e.Name, e.Inputs.signature())
+}
+
+// topicHash turns encoded data into a topic the way the contract does: data
+// longer than a topic is hashed, shorter data is left padded.
+func topicHash(data []byte) common.Hash {
+	if len(data) > common.HashLength {
+		return crypto.Keccak256Hash(data)
+	}
+	return common.BytesToHash(data)
+}
+
+// Topic returns the topic of a value of an indexed argument. Strings and
+// bytes are used as is, other values are RLP encoded first.
+func (arg Argument) Topic(v interface{}) (common.Hash, error) {
+	if arg.Type.T == StringTy || arg.Type.T == BytesTy {
+		packed, err := arg.Type.pack(reflect.ValueOf(v))
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if s, ok := packed.(string); ok {
+			return topicHash([]byte(s)), nil
+		}
+		return topicHash(packed.([]byte)), nil
+	}
+	enc, err := arg.Type.encode(v)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return topicHash(enc), nil
+}
+
+// MakeTopics converts the filter query of the indexed inputs into the topics
+// of a log filter, the event name included. A nil rule matches any value, a
+// common.Hash is taken as the topic of an argument that is not decodable.
+func (e Event) MakeTopics(query ...[]interface{}) ([][]common.Hash, error) {
+	var indexed Arguments
+	for _, arg := range e.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(query) > len(indexed) {
+		return nil, fmt.Errorf("too many topic rules for event %s", e.Name)
+	}
+	var topics [][]common.Hash
+	if !e.Anonymous {
+		topics = append(topics, []common.Hash{e.ID})
+	}
+	for i, rules := range query {
+		var topic []common.Hash
+		for _, rule := range rules {
+			if hash, ok := rule.(common.Hash); ok && !indexed[i].Type.DecodableTopic() {
+				topic = append(topic, hash)
+				continue
+			}
+			hash, err := indexed[i].Topic(rule)
+			if err != nil {
+				return nil, err
+			}
+			topic = append(topic, hash)
+		}
+		topics = append(topics, topic)
+	}
+	return topics, nil
+}
+
+// UnpackLog unpacks the topics and data of a log of the named event into out,
+// a pointer to a struct with a field for every input. The fields of indexed
+// inputs that are not decodable receive the topic as a common.Hash.
+func (abi ABI) UnpackLog(out interface{}, name string, topics []common.Hash, data []byte) error {
+	e, ok := abi.Events[name]
+	if !ok {
+		return fmt.Errorf("wasmabi: could not locate named event")
+	}
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("wasmabi: UnpackLog(non-pointer to struct)")
+	}
+	rv = rv.Elem()
+	if !e.Anonymous {
+		if len(topics) == 0 || topics[0] != e.ID {
+			return fmt.Errorf("wasmabi: log is not a %s event", e.Name)
+		}
+		topics = topics[1:]
+	}
+	field := func(i int, arg Argument) (reflect.Value, error) {
+		name := arg.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		f := rv.FieldByName(fieldName(name))
+		if !f.IsValid() || !f.CanSet() {
+			return reflect.Value{}, fmt.Errorf("wasmabi: field %s can't be found in the given value", fieldName(name))
+		}
+		return f, nil
+	}
+	var content []byte
+	if len(data) > 0 {
+		var err error
+		if content, _, err = rlp.SplitList(data); err != nil {
+			return fmt.Errorf("wasmabi: invalid event data: %v", err)
+		}
+	}
+	for i, arg := range e.Inputs {
+		f, err := field(i, arg)
+		if err != nil {
+			return err
+		}
+		if !arg.Indexed {
+			if content, err = arg.Type.unpack(content, f); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(topics) == 0 {
+			return fmt.Errorf("wasmabi: missing topic of %s", arg.Name)
+		}
+		topic := topics[0]
+		topics = topics[1:]
+		if !arg.Type.DecodableTopic() {
+			if f.Type() != reflect.TypeOf(common.Hash{}) {
+				return fmt.Errorf("wasmabi: topic of %s must be unpacked into a common.Hash", arg.Name)
+			}
+			f.Set(reflect.ValueOf(topic))
+			continue
+		}
+		// The encoding of decodable types only starts with a zero byte when
+		// it is that single byte, so the padding can be told apart.
+		enc := topic.Bytes()
+		for len(enc) > 1 && enc[0] == 0 {
+			enc = enc[1:]
+		}
+		if _, err := arg.Type.unpack(enc, f); err != nil {
+			return fmt.Errorf("wasmabi: invalid topic of %s: %v", arg.Name, err)
+		}
+	}
+	if len(content) > 0 {
+		return errors.New("wasmabi: too many values in event data")
+	}
+	return nil
+}
+
+// fieldName returns the Go struct field of an argument.
+func fieldName(name string) string {
+	return abi.ToCamelCase(name)
+}
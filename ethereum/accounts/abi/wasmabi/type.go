@@ -0,0 +1,246 @@
+package wasmabi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+)
+
+// Type enumerator
+const (
+	BoolTy byte = iota
+	UintTy
+	IntTy
+	BigUintTy
+	StringTy
+	BytesTy
+	FixedBytesTy
+	AddressTy
+	SliceTy
+	ArrayTy
+	PairTy
+	MapTy
+	StructTy
+)
+
+// Type is the reflection of the supported argument type.
+type Type struct {
+	T    byte
+	Size int   // Bit size of integers, length of arrays and fixed bytes
+	Elem *Type // Element of lists and arrays, value of maps, second of pairs
+	Key  *Type // Key of maps, first of pairs
+
+	stringKind string // holds the unparsed string for printing
+
+	// Struct relative fields, the base classes come first and are named after
+	// their struct.
+	StructName  string
+	StructElems []*Type
+	StructNames []string
+}
+
+var (
+	bigT     = reflect.TypeOf((*big.Int)(nil))
+	addressT = reflect.TypeOf(common.Address{})
+)
+
+// NewType creates a new reflection type of the abi type given in t. Struct
+// types are looked up in structs.
+func NewType(t string, structs map[string]*Type) (*Type, error) {
+	t = strings.TrimSpace(t)
+	name, params, err := splitGeneric(t)
+	if err != nil {
+		return nil, err
+	}
+	typ := &Type{stringKind: t}
+	switch name {
+	case "bool":
+		typ.T = BoolTy
+	case "uint8", "uint16", "uint32", "uint64":
+		typ.T, typ.Size = UintTy, bitSize(name[len("uint"):])
+	case "int8", "int16", "int32", "int64":
+		typ.T, typ.Size = IntTy, bitSize(name[len("int"):])
+	case "uint128", "uint256":
+		typ.T, typ.Size = BigUintTy, bitSize(name[len("uint"):])
+	case "string":
+		typ.T = StringTy
+	case "bytes":
+		typ.T = BytesTy
+	case "Address":
+		typ.T, typ.Size = AddressTy, common.AddressLength
+	case "FixedHash":
+		if len(params) != 1 {
+			return nil, fmt.Errorf("invalid type %s", t)
+		}
+		if typ.Size, err = strconv.Atoi(params[0]); err != nil || typ.Size <= 0 {
+			return nil, fmt.Errorf("invalid size of %s", t)
+		}
+		typ.T = FixedBytesTy
+	case "list", "vector", "set", "array":
+		if (name == "array" && len(params) != 2) || (name != "array" && len(params) != 1) {
+			return nil, fmt.Errorf("invalid type %s", t)
+		}
+		if typ.Elem, err = NewType(params[0], structs); err != nil {
+			return nil, err
+		}
+		typ.T = SliceTy
+		if name == "array" {
+			if typ.Size, err = strconv.Atoi(params[1]); err != nil || typ.Size < 0 {
+				return nil, fmt.Errorf("invalid size of %s", t)
+			}
+			typ.T = ArrayTy
+		} else if typ.Elem.T == UintTy && typ.Elem.Size == 8 {
+			typ.T, typ.Elem = BytesTy, nil
+		}
+	case "map", "pair":
+		if len(params) != 2 {
+			return nil, fmt.Errorf("invalid type %s", t)
+		}
+		if typ.Key, err = NewType(params[0], structs); err != nil {
+			return nil, err
+		}
+		if typ.Elem, err = NewType(params[1], structs); err != nil {
+			return nil, err
+		}
+		typ.T = PairTy
+		if name == "map" {
+			typ.T = MapTy
+		}
+	default:
+		st, ok := structs[name]
+		if !ok || len(params) != 0 {
+			return nil, fmt.Errorf("unsupported arg type: %s", t)
+		}
+		return st, nil
+	}
+	return typ, nil
+}
+
+// splitGeneric splits a type like map<string,list<uint8>> into its name and
+// top level parameters.
+func splitGeneric(t string) (string, []string, error) {
+	open := strings.IndexByte(t, '<')
+	if open < 0 {
+		return t, nil, nil
+	}
+	if !strings.HasSuffix(t, ">") {
+		return "", nil, fmt.Errorf("invalid type %s", t)
+	}
+	var (
+		params []string
+		depth  int
+		start  = open + 1
+	)
+	for i := start; i < len(t)-1; i++ {
+		switch t[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				params = append(params, strings.TrimSpace(t[start:i]))
+				start = i + 1
+			}
+		}
+		if depth < 0 {
+			return "", nil, fmt.Errorf("invalid type %s", t)
+		}
+	}
+	if depth != 0 {
+		return "", nil, fmt.Errorf("invalid type %s", t)
+	}
+	params = append(params, strings.TrimSpace(t[start:len(t)-1]))
+	return strings.TrimSpace(t[:open]), params, nil
+}
+
+func bitSize(s string) int {
+	size, _ := strconv.Atoi(s)
+	return size
+}
+
+// newStructType creates the type of a struct definition, the fields of which
+// are filled in once all structs are known.
+func newStructType(name string) *Type {
+	return &Type{T: StructTy, StructName: name, stringKind: name}
+}
+
+// String implements Stringer.
+func (t Type) String() string {
+	return t.stringKind
+}
+
+// GetType returns the reflection type of the ABI type.
+func (t Type) GetType() reflect.Type {
+	switch t.T {
+	case BoolTy:
+		return reflect.TypeOf(false)
+	case UintTy:
+		return [...]reflect.Type{reflect.TypeOf(uint8(0)), reflect.TypeOf(uint16(0)), reflect.TypeOf(uint32(0)), reflect.TypeOf(uint64(0))}[sizeIndex(t.Size)]
+	case IntTy:
+		return [...]reflect.Type{reflect.TypeOf(int8(0)), reflect.TypeOf(int16(0)), reflect.TypeOf(int32(0)), reflect.TypeOf(int64(0))}[sizeIndex(t.Size)]
+	case BigUintTy:
+		return bigT
+	case StringTy:
+		return reflect.TypeOf("")
+	case BytesTy:
+		return reflect.TypeOf([]byte{})
+	case FixedBytesTy:
+		return reflect.ArrayOf(t.Size, reflect.TypeOf(byte(0)))
+	case AddressTy:
+		return addressT
+	case SliceTy:
+		return reflect.SliceOf(t.Elem.GetType())
+	case ArrayTy:
+		return reflect.ArrayOf(t.Size, t.Elem.GetType())
+	case PairTy:
+		return reflect.StructOf([]reflect.StructField{
+			{Name: "First", Type: t.Key.GetType()},
+			{Name: "Second", Type: t.Elem.GetType()},
+		})
+	case MapTy:
+		return reflect.SliceOf(reflect.StructOf([]reflect.StructField{
+			{Name: "Key", Type: t.Key.GetType()},
+			{Name: "Value", Type: t.Elem.GetType()},
+		}))
+	case StructTy:
+		fields := make([]reflect.StructField, len(t.StructElems))
+		for i, elem := range t.StructElems {
+			fields[i] = reflect.StructField{Name: abi.ToCamelCase(t.StructNames[i]), Type: elem.GetType()}
+		}
+		return reflect.StructOf(fields)
+	}
+	panic(fmt.Errorf("invalid type %d", t.T))
+}
+
+func sizeIndex(size int) int {
+	switch size {
+	case 8:
+		return 0
+	case 16:
+		return 1
+	case 32:
+		return 2
+	}
+	return 3
+}
+
+// DecodableTopic reports whether an indexed argument of the type can be
+// recovered from its topic, which is the case when its encoding fits in a topic
+// and is stored as is. Topics of other types only hold a hash.
+func (t Type) DecodableTopic() bool {
+	switch t.T {
+	case BoolTy, UintTy, IntTy, AddressTy:
+		return true
+	case BigUintTy:
+		return t.Size < 256
+	case FixedBytesTy:
+		return t.Size < common.HashLength
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package hdwallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/math"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+// hardenedOffset is the first child index of hardened derivations.
+const hardenedOffset = 0x80000000
+
+// masterKeySalt is the HMAC key deriving the master key from a seed.
+var masterKeySalt = []byte("Bitcoin seed")
+
+// errInvalidKey is returned if a derived key falls outside of the curve order,
+// which happens with a probability lower than 1 in 2^127.
+var errInvalidKey = errors.New("invalid derived key")
+
+// ExtendedKey is a BIP-32 extended private key, the private key of a node in
+// the derivation tree along with the chain code deriving its children.
+type ExtendedKey struct {
+	key       []byte // 32 byte big endian private key
+	chainCode []byte // 32 byte chain code
+}
+
+// NewMasterKey creates the root node of the derivation tree of a seed.
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, errors.New("seed size must be in [16, 64] bytes")
+	}
+	mac := hmac.New(sha512.New, masterKeySalt)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	if !validKey(new(big.Int).SetBytes(sum[:32])) {
+		return nil, errInvalidKey
+	}
+	return &ExtendedKey{key: sum[:32], chainCode: sum[32:]}, nil
+}
+
+// validKey reports whether a scalar can be used as a private key.
+func validKey(k *big.Int) bool {
+	return k.Sign() > 0 && k.Cmp(crypto.S256().Params().N) < 0
+}
+
+// Child derives the child key of the given index, indices from 2^31 onwards
+// being hardened ones.
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	data := make([]byte, 0, 37)
+	if index >= hardenedOffset {
+		data = append(append(data, 0x00), k.key...)
+	} else {
+		priv, err := crypto.ToECDSA(k.key)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, crypto.CompressPubkey(&priv.PublicKey)...)
+	}
+	data = data[:len(data)+4]
+	binary.BigEndian.PutUint32(data[len(data)-4:], index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, errInvalidKey
+	}
+	child := tweak.Add(tweak, new(big.Int).SetBytes(k.key))
+	child.Mod(child, crypto.S256().Params().N)
+	if child.Sign() == 0 {
+		return nil, errInvalidKey
+	}
+	return &ExtendedKey{key: math.PaddedBigBytes(child, 32), chainCode: sum[32:]}, nil
+}
+
+// Derive derives the descendant key at the given path, relative to this key.
+func (k *ExtendedKey) Derive(path accounts.DerivationPath) (*ExtendedKey, error) {
+	key := k
+	for _, index := range path {
+		child, err := key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+		key = child
+	}
+	return key, nil
+}
+
+// PrivateKey returns the secp256k1 private key of the node.
+func (k *ExtendedKey) PrivateKey() (*ecdsa.PrivateKey, error) {
+	return crypto.ToECDSA(k.key)
+}
+
+// zero wipes the key material from memory.
+func (k *ExtendedKey) zero() {
+	for i := range k.key {
+		k.key[i] = 0
+	}
+	for i := range k.chainCode {
+		k.chainCode[i] = 0
+	}
+}
@@ -0,0 +1,65 @@
+package hdwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+// Tests the key derivation against the first BIP-32 test vector, covering both
+// hardened and normal children.
+func TestDerivationVector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		t.Fatalf("failed to create master key: %v", err)
+	}
+	tests := []struct {
+		path      accounts.DerivationPath
+		key       string
+		chainCode string
+	}{
+		{accounts.DerivationPath{}, "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508"},
+		{accounts.DerivationPath{hardenedOffset}, "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea", "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"},
+		{accounts.DerivationPath{hardenedOffset, 1}, "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368", "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19"},
+	}
+	for i, tt := range tests {
+		key, err := master.Derive(tt.path)
+		if err != nil {
+			t.Fatalf("test %d: failed to derive key: %v", i, err)
+		}
+		if have := hex.EncodeToString(key.key); have != tt.key {
+			t.Errorf("test %d: key mismatch: have %s, want %s", i, have, tt.key)
+		}
+		if have := hex.EncodeToString(key.chainCode); have != tt.chainCode {
+			t.Errorf("test %d: chain code mismatch: have %s, want %s", i, have, tt.chainCode)
+		}
+	}
+}
+
+// Tests that the default account of a well known mnemonic matches the address
+// derived by other BIP-44 wallets.
+func TestDerivationDefaultAccount(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	master, err := NewMasterKey(NewSeed(mnemonic, ""))
+	if err != nil {
+		t.Fatalf("failed to create master key: %v", err)
+	}
+	child, err := master.Derive(accounts.DefaultBaseDerivationPath)
+	if err != nil {
+		t.Fatalf("failed to derive key: %v", err)
+	}
+	key, err := child.PrivateKey()
+	if err != nil {
+		t.Fatalf("failed to convert key: %v", err)
+	}
+	want := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	if have := crypto.PubkeyToAddress(key.PublicKey); !bytes.Equal(have[:], want[:]) {
+		t.Errorf("address mismatch: have %x, want %x", have, want)
+	}
+}
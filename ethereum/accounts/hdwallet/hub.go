@@ -0,0 +1,234 @@
+package hdwallet
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/event"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+)
+
+// Scheme is the protocol scheme prefixing account and wallet URLs.
+const Scheme = "hd"
+
+// HubType is the reflect type of a HD wallet backend.
+var HubType = reflect.TypeOf(&Hub{})
+
+// mnemonicBits is the entropy size of the mnemonics of new wallets, which
+// results in 24 words.
+const mnemonicBits = 256
+
+// refreshCycle is the maximum time between wallet refreshes, picking up the
+// wallet files added to the key directory by other means.
+const refreshCycle = 3 * time.Second
+
+// refreshThrottling is the minimum time between wallet refreshes to avoid
+// scanning the key directory in a loop.
+const refreshThrottling = 500 * time.Millisecond
+
+// Hub is an accounts.Backend managing the mnemonic backed HD wallets stored in
+// a key directory, alongside the keys of the keystore.
+type Hub struct {
+	keydir  string // Directory holding the encrypted wallet files
+	scryptN int    // Scrypt N parameter of new wallet files
+	scryptP int    // Scrypt P parameter of new wallet files
+
+	refreshed   time.Time               // Time instance when the list of wallets was last refreshed
+	wallets     []accounts.Wallet       // List of wallets currently tracking
+	updateFeed  event.Feed              // Event feed to notify wallet additions/removals
+	updateScope event.SubscriptionScope // Subscription scope tracking current live listeners
+	updating    bool                    // Whether the event notification loop is running
+
+	stateLock sync.RWMutex // Protects the internals of the hub from racey access
+}
+
+// NewHub creates a HD wallet manager for the wallets stored in the given key
+// directory, new ones being encrypted with the given scrypt parameters.
+func NewHub(keydir string, scryptN, scryptP int) *Hub {
+	keydir, _ = filepath.Abs(keydir)
+	hub := &Hub{
+		keydir:  keydir,
+		scryptN: scryptN,
+		scryptP: scryptP,
+	}
+	hub.refreshWallets()
+	return hub
+}
+
+// Wallets implements accounts.Backend, returning all the wallets found in the
+// key directory.
+func (hub *Hub) Wallets() []accounts.Wallet {
+	// Make sure the list of wallets is up to date
+	hub.refreshWallets()
+
+	hub.stateLock.RLock()
+	defer hub.stateLock.RUnlock()
+
+	cpy := make([]accounts.Wallet, len(hub.wallets))
+	copy(cpy, hub.wallets)
+	return cpy
+}
+
+// refreshWallets scans the key directory for wallet files and updates the list
+// of wallets based on the found ones.
+func (hub *Hub) refreshWallets() {
+	// Don't scan the directory like crazy it the user fetches wallets in a loop
+	hub.stateLock.RLock()
+	elapsed := time.Since(hub.refreshed)
+	hub.stateLock.RUnlock()
+
+	if elapsed < refreshThrottling {
+		return
+	}
+	hub.scanWallets()
+}
+
+// scanWallets lists the wallet files of the key directory, sorted by file name,
+// and transforms the current list of wallets into the new one.
+func (hub *Hub) scanWallets() {
+	files, err := ioutil.ReadDir(hub.keydir)
+	if err != nil {
+		log.Trace("Failed to list HD wallet files", "dir", hub.keydir, "err", err)
+	}
+	hub.stateLock.Lock()
+
+	var (
+		wallets = make([]accounts.Wallet, 0, len(hub.wallets))
+		events  []accounts.WalletEvent
+	)
+	for _, file := range files {
+		if file.IsDir() || !isWalletFile(file.Name()) {
+			continue
+		}
+		url := accounts.URL{Scheme: Scheme, Path: filepath.Join(hub.keydir, file.Name())}
+
+		// Drop wallets in front of the next file, they were deleted
+		for len(hub.wallets) > 0 && hub.wallets[0].URL().Cmp(url) < 0 {
+			events = append(events, accounts.WalletEvent{Wallet: hub.wallets[0], Kind: accounts.WalletDropped})
+			hub.wallets = hub.wallets[1:]
+		}
+		// If there are no more wallets or the file is before the next, wrap new wallet
+		if len(hub.wallets) == 0 || hub.wallets[0].URL().Cmp(url) > 0 {
+			wallet := &wallet{hub: hub, url: url, log: log.New("url", url)}
+
+			events = append(events, accounts.WalletEvent{Wallet: wallet, Kind: accounts.WalletArrived})
+			wallets = append(wallets, wallet)
+			continue
+		}
+		// If the file is the same as the first wallet, keep it
+		wallets = append(wallets, hub.wallets[0])
+		hub.wallets = hub.wallets[1:]
+	}
+	// Drop any leftover wallets and set the new batch
+	for _, wallet := range hub.wallets {
+		events = append(events, accounts.WalletEvent{Wallet: wallet, Kind: accounts.WalletDropped})
+	}
+	hub.refreshed = time.Now()
+	hub.wallets = wallets
+	hub.stateLock.Unlock()
+
+	// Fire all wallet events and return
+	for _, event := range events {
+		hub.updateFeed.Send(event)
+	}
+}
+
+// Subscribe implements accounts.Backend, creating an async subscription to
+// receive notifications on the addition or removal of HD wallets.
+func (hub *Hub) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	// We need the mutex to reliably start/stop the update loop
+	hub.stateLock.Lock()
+	defer hub.stateLock.Unlock()
+
+	// Subscribe the caller and track the subscriber count
+	sub := hub.updateScope.Track(hub.updateFeed.Subscribe(sink))
+
+	// Subscribers require an active notification loop, start it
+	if !hub.updating {
+		hub.updating = true
+		go hub.updater()
+	}
+	return sub
+}
+
+// updater is responsible for maintaining an up-to-date list of wallets stored
+// in the key directory, and for firing wallet addition/removal events.
+func (hub *Hub) updater() {
+	for {
+		time.Sleep(refreshCycle)
+
+		// Run the wallet refresher
+		hub.refreshWallets()
+
+		// If all our subscribers left, stop the updater
+		hub.stateLock.Lock()
+		if hub.updateScope.Count() == 0 {
+			hub.updating = false
+			hub.stateLock.Unlock()
+			return
+		}
+		hub.stateLock.Unlock()
+	}
+}
+
+// NewWallet creates a wallet from a freshly generated mnemonic, encrypting its
+// seed with the passphrase. The mnemonic is returned for the user to back up,
+// it is not stored anywhere.
+func (hub *Hub) NewWallet(passphrase string) (string, accounts.Wallet, error) {
+	entropy, err := NewEntropy(mnemonicBits)
+	if err != nil {
+		return "", nil, err
+	}
+	mnemonic, err := NewMnemonic(entropy)
+	if err != nil {
+		return "", nil, err
+	}
+	wallet, err := hub.ImportMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return "", nil, err
+	}
+	return mnemonic, wallet, nil
+}
+
+// ImportMnemonic restores the wallet of an existing mnemonic, encrypting its
+// seed with the passphrase.
+func (hub *Hub) ImportMnemonic(mnemonic string, passphrase string) (accounts.Wallet, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	seed := NewSeed(mnemonic, "")
+	defer zeroBytes(seed)
+
+	walletjson, err := EncryptSeed(seed, passphrase, hub.scryptN, hub.scryptP)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(hub.keydir, WalletFileName())
+	if err := writeWalletFile(path, walletjson); err != nil {
+		return nil, err
+	}
+	// Pick the new file up right away, regardless of the throttling
+	hub.scanWallets()
+
+	url := accounts.URL{Scheme: Scheme, Path: path}
+	hub.stateLock.RLock()
+	defer hub.stateLock.RUnlock()
+
+	for _, wallet := range hub.wallets {
+		if wallet.URL() == url {
+			return wallet, nil
+		}
+	}
+	return nil, accounts.ErrUnknownWallet
+}
+
+// zeroBytes wipes a secret from memory.
+func zeroBytes(bytes []byte) {
+	for i := range bytes {
+		bytes[i] = 0
+	}
+}
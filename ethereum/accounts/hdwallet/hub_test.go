@@ -0,0 +1,151 @@
+package hdwallet
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func tmpHub(t *testing.T) (string, *Hub) {
+	dir, err := ioutil.TempDir("", "hdwallet-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, NewHub(dir, keystore.LightScryptN, keystore.LightScryptP)
+}
+
+// Tests that new wallets are stored in the key directory and show up in the
+// wallet list of the hub.
+func TestHubNewWallet(t *testing.T) {
+	dir, hub := tmpHub(t)
+	defer os.RemoveAll(dir)
+
+	mnemonic, wallet, err := hub.NewWallet("foo")
+	if err != nil {
+		t.Fatalf("failed to create wallet: %v", err)
+	}
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		t.Fatalf("invalid mnemonic returned: %v", err)
+	}
+	if url := wallet.URL(); url.Scheme != Scheme || filepath.Dir(url.Path) != dir {
+		t.Errorf("wallet url mismatch: have %s, want in %s", url, dir)
+	}
+	if _, err := os.Stat(wallet.URL().Path); err != nil {
+		t.Fatalf("wallet file missing: %v", err)
+	}
+	if wallets := hub.Wallets(); len(wallets) != 1 || wallets[0] != wallet {
+		t.Fatalf("wallet list mismatch: have %v, want [%v]", wallets, wallet)
+	}
+	// Make sure the keystore ignores the wallet file
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	if accs := ks.Accounts(); len(accs) != 0 {
+		t.Errorf("keystore picked up wallet file: %v", accs)
+	}
+	// Removing the file should drop the wallet
+	os.Remove(wallet.URL().Path)
+	hub.scanWallets()
+	if wallets := hub.Wallets(); len(wallets) != 0 {
+		t.Errorf("wallet list mismatch after removal: have %v, want []", wallets)
+	}
+}
+
+// Tests the life cycle of an imported wallet: opening, deriving and signing
+// with the derived accounts and closing.
+func TestWalletLifecycle(t *testing.T) {
+	dir, hub := tmpHub(t)
+	defer os.RemoveAll(dir)
+
+	wallet, err := hub.ImportMnemonic(testMnemonic, "foo")
+	if err != nil {
+		t.Fatalf("failed to import mnemonic: %v", err)
+	}
+	if _, err := wallet.Derive(accounts.DefaultBaseDerivationPath, false); err != accounts.ErrWalletClosed {
+		t.Fatalf("closed wallet derivation error mismatch: have %v, want %v", err, accounts.ErrWalletClosed)
+	}
+	if err := wallet.Open("bar"); err != keystore.ErrDecrypt {
+		t.Fatalf("wrong passphrase error mismatch: have %v, want %v", err, keystore.ErrDecrypt)
+	}
+	if err := wallet.Open("foo"); err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	if err := wallet.Open("foo"); err != accounts.ErrWalletAlreadyOpen {
+		t.Fatalf("reopen error mismatch: have %v, want %v", err, accounts.ErrWalletAlreadyOpen)
+	}
+	if status, _ := wallet.Status(); status != "Open" {
+		t.Errorf("status mismatch: have %s, want Open", status)
+	}
+	// Derive the default account and pin it to the wallet
+	account, err := wallet.Derive(accounts.DefaultBaseDerivationPath, true)
+	if err != nil {
+		t.Fatalf("failed to derive account: %v", err)
+	}
+	want := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	if !bytes.Equal(account.Address[:], want[:]) {
+		t.Errorf("address mismatch: have %x, want %x", account.Address, want)
+	}
+	if accs := wallet.Accounts(); len(accs) != 1 || !wallet.Contains(account) {
+		t.Errorf("pinned account missing: %v", accs)
+	}
+	// Sign with both the pinned and an unpinned account
+	path := accounts.DefaultBaseDerivationPath
+	path = append(path[:len(path)-1:len(path)-1], 1000)
+	unpinned, err := wallet.Derive(path, false)
+	if err != nil {
+		t.Fatalf("failed to derive account: %v", err)
+	}
+	if wallet.Contains(unpinned) {
+		t.Errorf("unpinned account contained in wallet")
+	}
+	hash := crypto.Keccak256([]byte("phoenix"))
+	for _, acc := range []accounts.Account{account, unpinned} {
+		sig, err := wallet.SignHash(acc, hash)
+		if err != nil {
+			t.Fatalf("failed to sign hash: %v", err)
+		}
+		pub, err := crypto.SigToPub(hash, sig)
+		if err != nil {
+			t.Fatalf("failed to recover signer: %v", err)
+		}
+		if signer := crypto.PubkeyToAddress(*pub); !bytes.Equal(signer[:], acc.Address[:]) {
+			t.Errorf("signer mismatch: have %x, want %x", signer, acc.Address)
+		}
+	}
+	chainID := big.NewInt(1)
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	signed, err := wallet.SignTx(account, tx, chainID)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if sender, err := types.Sender(types.NewEIP155Signer(chainID), signed); err != nil || !bytes.Equal(sender[:], account.Address[:]) {
+		t.Errorf("sender mismatch: have %x (%v), want %x", sender, err, account.Address)
+	}
+	// Foreign accounts must be rejected
+	foreign := accounts.Account{Address: common.Address{1}, URL: unpinned.URL}
+	if _, err := wallet.SignHash(foreign, hash); err != accounts.ErrUnknownAccount {
+		t.Errorf("foreign account error mismatch: have %v, want %v", err, accounts.ErrUnknownAccount)
+	}
+	// Close the wallet and sign with the passphrase instead
+	if err := wallet.Close(); err != nil {
+		t.Fatalf("failed to close wallet: %v", err)
+	}
+	if _, err := wallet.SignHash(account, hash); err != accounts.ErrWalletClosed {
+		t.Errorf("closed wallet signing error mismatch: have %v, want %v", err, accounts.ErrWalletClosed)
+	}
+	if _, err := wallet.SignHashWithPassphrase(account, "bar", hash); err != keystore.ErrDecrypt {
+		t.Errorf("wrong passphrase error mismatch: have %v, want %v", err, keystore.ErrDecrypt)
+	}
+	if _, err := wallet.SignTxWithPassphrase(account, "foo", tx, chainID); err != nil {
+		t.Errorf("failed to sign transaction with passphrase: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package hdwallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pborman/uuid"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
+)
+
+const (
+	// walletVersion is the version of the encrypted wallet file format.
+	walletVersion = 1
+
+	// walletFilePrefix is the file name prefix telling wallet files apart from
+	// the key files in the keystore directory.
+	walletFilePrefix = "hd--"
+)
+
+// encryptedWalletJSON is the on-disk format of a wallet, the BIP-39 seed being
+// encrypted the same way as the keys of the keystore. There is deliberately no
+// address field, so the keystore doesn't pick the file up as a key.
+type encryptedWalletJSON struct {
+	Id      string              `json:"id"`
+	Version int                 `json:"version"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+}
+
+// EncryptSeed encrypts a wallet seed using the specified scrypt parameters into
+// a json blob that can be decrypted later on.
+func EncryptSeed(seed []byte, auth string, scryptN, scryptP int) ([]byte, error) {
+	cryptoStruct, err := keystore.EncryptDataV3(seed, []byte(auth), scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encryptedWalletJSON{
+		Id:      uuid.NewRandom().String(),
+		Version: walletVersion,
+		Crypto:  cryptoStruct,
+	})
+}
+
+// DecryptSeed decrypts a wallet seed from a json blob.
+func DecryptSeed(walletjson []byte, auth string) ([]byte, error) {
+	var wallet encryptedWalletJSON
+	if err := json.Unmarshal(walletjson, &wallet); err != nil {
+		return nil, err
+	}
+	if wallet.Version != walletVersion {
+		return nil, fmt.Errorf("wallet version not supported: %v", wallet.Version)
+	}
+	return keystore.DecryptDataV3(wallet.Crypto, auth)
+}
+
+// WalletFileName returns the canonical file name of a new wallet, which is how
+// wallets are recognized within the keystore directory.
+func WalletFileName() string {
+	ts := time.Now().UTC()
+	return fmt.Sprintf("%sUTC--%s", walletFilePrefix, strings.Replace(ts.Format("2006-01-02T15:04:05.999999999Z07:00"), ":", "-", -1))
+}
+
+// isWalletFile reports whether a file name denotes a wallet file.
+func isWalletFile(name string) bool {
+	return strings.HasPrefix(name, walletFilePrefix)
+}
+
+// writeWalletFile atomically writes a wallet file readable only by the user. The
+// temporary file is hidden, so it's never mistaken for a wallet.
+func writeWalletFile(file string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	f.Close()
+	return os.Rename(f.Name(), file)
+}
@@ -0,0 +1,136 @@
+// Package hdwallet implements a software hierarchical deterministic wallet,
+// deriving secp256k1 accounts from a BIP-39 mnemonic according to BIP-32/44.
+package hdwallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var (
+	// ErrInvalidEntropy is returned if the size of mnemonic entropy is not a
+	// multiple of 32 bits in the range [128, 256].
+	ErrInvalidEntropy = errors.New("entropy size must be a multiple of 32 bits in [128, 256]")
+
+	// ErrInvalidMnemonic is returned if a mnemonic contains an unknown word or
+	// has an unsupported number of words.
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+	// ErrChecksumMismatch is returned if the checksum embedded in a mnemonic
+	// does not match the entropy it encodes.
+	ErrChecksumMismatch = errors.New("mnemonic checksum mismatch")
+)
+
+// wordIndex maps the words of the wordlist to the 11 bit values they encode.
+var wordIndex = func() map[string]int {
+	index := make(map[string]int, len(english))
+	for i, word := range english {
+		index[word] = i
+	}
+	return index
+}()
+
+// NewEntropy generates cryptographically secure random entropy for a mnemonic
+// of the given bit size.
+func NewEntropy(bits int) ([]byte, error) {
+	if bits%32 != 0 || bits < 128 || bits > 256 {
+		return nil, ErrInvalidEntropy
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return nil, err
+	}
+	return entropy, nil
+}
+
+// NewMnemonic encodes the given entropy into a BIP-39 mnemonic sentence, the
+// entropy being followed by a checksum of one bit per 32 bits of entropy.
+func NewMnemonic(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if bits%32 != 0 || bits < 128 || bits > 256 {
+		return "", ErrInvalidEntropy
+	}
+	// The checksum is at most 8 bits, so the first hash byte holds all of it
+	checksum := sha256.Sum256(entropy)
+	data := append(append([]byte{}, entropy...), checksum[0])
+
+	words := make([]string, (bits+bits/32)/11)
+	for i := range words {
+		words[i] = english[readBits(data, i*11, 11)]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// EntropyFromMnemonic decodes a BIP-39 mnemonic sentence back into the entropy
+// it was generated from, verifying its checksum.
+func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return nil, fmt.Errorf("%w: %d words", ErrInvalidMnemonic, len(words))
+	}
+	var (
+		bits = len(words) * 11
+		data = make([]byte, (bits+7)/8)
+	)
+	for i, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word %q", ErrInvalidMnemonic, word)
+		}
+		writeBits(data, i*11, 11, index)
+	}
+	entropy := data[:bits*32/33/8]
+
+	checksum := sha256.Sum256(entropy)
+	if size := uint(len(entropy) / 4); readBits(data, len(entropy)*8, int(size)) != int(checksum[0]>>(8-size)) {
+		return nil, ErrChecksumMismatch
+	}
+	return entropy, nil
+}
+
+// ValidateMnemonic checks whether a mnemonic sentence is made of known words
+// and carries a valid checksum.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := EntropyFromMnemonic(mnemonic)
+	return err
+}
+
+// NewSeed derives the 64 byte BIP-39 seed of a mnemonic sentence, protected by
+// an optional password. The mnemonic is not validated, call ValidateMnemonic
+// beforehand if needed.
+//
+// Note, the specification requires both inputs to be NFKD normalized, which is
+// a noop for the English wordlist and ASCII passwords.
+func NewSeed(mnemonic string, password string) []byte {
+	sentence := strings.Join(strings.Fields(mnemonic), " ")
+	return pbkdf2.Key([]byte(sentence), []byte("mnemonic"+password), 2048, 64, sha512.New)
+}
+
+// readBits reads size bits starting at the given bit offset of a big endian
+// bit stream.
+func readBits(data []byte, offset int, size int) int {
+	value := 0
+	for i := offset; i < offset+size; i++ {
+		value <<= 1
+		if data[i/8]&(0x80>>uint(i%8)) != 0 {
+			value |= 1
+		}
+	}
+	return value
+}
+
+// writeBits writes the lowest size bits of value starting at the given bit
+// offset of a big endian bit stream.
+func writeBits(data []byte, offset int, size int, value int) {
+	for i := 0; i < size; i++ {
+		if value&(1<<uint(size-1-i)) != 0 {
+			data[(offset+i)/8] |= 0x80 >> uint((offset+i)%8)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package hdwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Tests the mnemonic encoding and seed derivation against the official BIP-39
+// test vectors, all of them protected by the "TREZOR" password.
+func TestMnemonicVectors(t *testing.T) {
+	tests := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			"80808080808080808080808080808080",
+			"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+			"d71de856f81a8acc65e6fc851a38d4d7ec216fd0796d0a6827a3ad6ed5511a30fa280f12eb2e47ed2ac03b5c462a0358d18d69fe4f985ec81778c1b370b652a8",
+		},
+		{
+			"ffffffffffffffffffffffffffffffff",
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+			"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+		},
+	}
+	for i, tt := range tests {
+		entropy, _ := hex.DecodeString(tt.entropy)
+
+		mnemonic, err := NewMnemonic(entropy)
+		if err != nil {
+			t.Fatalf("test %d: failed to create mnemonic: %v", i, err)
+		}
+		if mnemonic != tt.mnemonic {
+			t.Errorf("test %d: mnemonic mismatch: have %q, want %q", i, mnemonic, tt.mnemonic)
+		}
+		decoded, err := EntropyFromMnemonic(mnemonic)
+		if err != nil {
+			t.Fatalf("test %d: failed to decode mnemonic: %v", i, err)
+		}
+		if !bytes.Equal(decoded, entropy) {
+			t.Errorf("test %d: entropy mismatch: have %x, want %x", i, decoded, entropy)
+		}
+		if seed := hex.EncodeToString(NewSeed(mnemonic, "TREZOR")); seed != tt.seed {
+			t.Errorf("test %d: seed mismatch: have %s, want %s", i, seed, tt.seed)
+		}
+	}
+}
+
+// Tests that mnemonics of all supported sizes round trip through the encoding.
+func TestMnemonicRoundtrip(t *testing.T) {
+	for bits := 128; bits <= 256; bits += 32 {
+		entropy, err := NewEntropy(bits)
+		if err != nil {
+			t.Fatalf("%d bits: failed to generate entropy: %v", bits, err)
+		}
+		mnemonic, err := NewMnemonic(entropy)
+		if err != nil {
+			t.Fatalf("%d bits: failed to create mnemonic: %v", bits, err)
+		}
+		if words := len(strings.Fields(mnemonic)); words != (bits+bits/32)/11 {
+			t.Errorf("%d bits: word count mismatch: have %d, want %d", bits, words, (bits+bits/32)/11)
+		}
+		decoded, err := EntropyFromMnemonic(mnemonic)
+		if err != nil {
+			t.Fatalf("%d bits: failed to decode mnemonic: %v", bits, err)
+		}
+		if !bytes.Equal(decoded, entropy) {
+			t.Errorf("%d bits: entropy mismatch: have %x, want %x", bits, decoded, entropy)
+		}
+	}
+	if _, err := NewEntropy(160 + 8); err != ErrInvalidEntropy {
+		t.Errorf("odd entropy size error mismatch: have %v, want %v", err, ErrInvalidEntropy)
+	}
+}
+
+// Tests that malformed mnemonics are rejected.
+func TestInvalidMnemonics(t *testing.T) {
+	tests := []struct {
+		mnemonic string
+		err      error
+	}{
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", ErrInvalidMnemonic},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon phoenix", ErrInvalidMnemonic},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", ErrChecksumMismatch},
+		{"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo", ErrChecksumMismatch},
+	}
+	for i, tt := range tests {
+		if err := ValidateMnemonic(tt.mnemonic); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,472 @@
+package hdwallet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	ethereum "github.com/PhoenixGlobal/Phoenix-Chain-SDK"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+)
+
+// Minimum time to wait between self derivation attempts, even it the user is
+// requesting accounts like crazy.
+const selfDeriveThrottling = time.Second
+
+// wallet is a mnemonic backed HD wallet stored in an encrypted wallet file. The
+// seed is only decrypted while the wallet is open, all accounts being derived
+// from its master key on demand.
+type wallet struct {
+	hub *Hub         // HD wallet hub tracking the wallet file
+	url accounts.URL // Textual URL uniquely identifying this wallet
+
+	master *ExtendedKey // Master key of the derivation tree, nil if closed
+
+	accounts []accounts.Account                         // List of derived accounts pinned on the wallet
+	paths    map[common.Address]accounts.DerivationPath // Known derivation paths for signing operations
+
+	deriveNextPath accounts.DerivationPath   // Next derivation path for account auto-discovery
+	deriveNextAddr common.Address            // Next derived account address for auto-discovery
+	deriveChain    ethereum.ChainStateReader // Blockchain state reader to discover used account with
+	deriveReq      chan chan struct{}        // Channel to request a self-derivation on
+	deriveQuit     chan chan error           // Channel to terminate the self-deriver with
+
+	stateLock sync.RWMutex // Protects read and write access to the wallet struct fields
+
+	log log.Logger // Contextual logger to tag the wallet file with its path
+}
+
+// URL implements accounts.Wallet, returning the URL of the wallet file.
+func (w *wallet) URL() accounts.URL {
+	return w.url // Immutable, no need for a lock
+}
+
+// Status implements accounts.Wallet, returning whether the seed of the wallet is
+// currently decrypted.
+func (w *wallet) Status() (string, error) {
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	if w.master == nil {
+		return "Closed", nil
+	}
+	return "Open", nil
+}
+
+// Open implements accounts.Wallet, decrypting the seed of the wallet file with
+// the passphrase and deriving the master key from it.
+func (w *wallet) Open(passphrase string) error {
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	// If the wallet was already opened, refuse to try again
+	if w.master != nil {
+		return accounts.ErrWalletAlreadyOpen
+	}
+	master, err := w.decrypt(passphrase)
+	if err != nil {
+		return err
+	}
+	// Decryption successful, start life-cycle management
+	w.master = master
+	w.paths = make(map[common.Address]accounts.DerivationPath)
+
+	w.deriveReq = make(chan chan struct{})
+	w.deriveQuit = make(chan chan error)
+
+	go w.selfDerive()
+
+	// Notify anyone listening for wallet events that a new wallet is accessible
+	go w.hub.updateFeed.Send(accounts.WalletEvent{Wallet: w, Kind: accounts.WalletOpened})
+
+	return nil
+}
+
+// decrypt reads the wallet file and derives the master key from the seed in it.
+func (w *wallet) decrypt(passphrase string) (*ExtendedKey, error) {
+	walletjson, err := ioutil.ReadFile(w.url.Path)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := DecryptSeed(walletjson, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(seed)
+
+	return NewMasterKey(seed)
+}
+
+// Close implements accounts.Wallet, stopping the self-derivation and wiping the
+// master key from memory.
+func (w *wallet) Close() error {
+	// Ensure the wallet was opened
+	w.stateLock.RLock()
+	dQuit := w.deriveQuit
+	w.stateLock.RUnlock()
+
+	// Terminate the self-derivations
+	var derr error
+	if dQuit != nil {
+		errc := make(chan error)
+		dQuit <- errc
+		derr = <-errc // Save for later, we *must* wipe the key
+	}
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	w.deriveQuit = nil
+	w.deriveReq = nil
+
+	if w.master != nil {
+		w.master.zero()
+		w.master = nil
+	}
+	w.accounts, w.paths = nil, nil
+
+	return derr
+}
+
+// Accounts implements accounts.Wallet, returning the list of accounts pinned to
+// the wallet. If self-derivation was enabled, the account list is periodically
+// expanded based on current chain state.
+func (w *wallet) Accounts() []accounts.Account {
+	// Attempt self-derivation if it's running
+	reqc := make(chan struct{}, 1)
+	select {
+	case w.deriveReq <- reqc:
+		// Self-derivation request accepted, wait for it
+		<-reqc
+	default:
+		// Self-derivation offline, throttled or busy, skip
+	}
+	// Return whatever account list we ended up with
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	cpy := make([]accounts.Account, len(w.accounts))
+	copy(cpy, w.accounts)
+	return cpy
+}
+
+// selfDerive is an account derivation loop that upon request attempts to find
+// new non-zero accounts.
+func (w *wallet) selfDerive() {
+	w.log.Debug("HD wallet self-derivation started")
+	defer w.log.Debug("HD wallet self-derivation stopped")
+
+	// Execute self-derivations until termination or error
+	var (
+		reqc chan struct{}
+		errc chan error
+		err  error
+	)
+	for errc == nil && err == nil {
+		// Wait until either derivation or termination is requested
+		select {
+		case errc = <-w.deriveQuit:
+			// Termination requested
+			continue
+		case reqc = <-w.deriveReq:
+			// Account discovery requested
+		}
+		// Derivation needs a chain and the master key, skip if either unavailable
+		w.stateLock.RLock()
+		if w.master == nil || w.deriveChain == nil {
+			w.stateLock.RUnlock()
+			reqc <- struct{}{}
+			continue
+		}
+		var (
+			accs  []accounts.Account
+			paths []accounts.DerivationPath
+
+			nextAddr = w.deriveNextAddr
+			nextPath = w.deriveNextPath
+
+			context = context.Background()
+		)
+		for empty := false; !empty; {
+			// Retrieve the next derived account
+			if nextAddr == (common.Address{}) {
+				if nextAddr, err = w.derive(nextPath); err != nil {
+					w.log.Warn("HD wallet account derivation failed", "err", err)
+					break
+				}
+			}
+			// Check the account's status against the current chain state
+			var (
+				balance *big.Int
+				nonce   uint64
+			)
+			balance, err = w.deriveChain.BalanceAt(context, nextAddr, nil)
+			if err != nil {
+				w.log.Warn("HD wallet balance retrieval failed", "err", err)
+				break
+			}
+			nonce, err = w.deriveChain.NonceAt(context, nextAddr, nil)
+			if err != nil {
+				w.log.Warn("HD wallet nonce retrieval failed", "err", err)
+				break
+			}
+			// If the next account is empty, stop self-derivation, but add it nonetheless
+			if balance.Sign() == 0 && nonce == 0 {
+				empty = true
+			}
+			// We've just self-derived a new account, start tracking it locally
+			path := make(accounts.DerivationPath, len(nextPath))
+			copy(path[:], nextPath[:])
+			paths = append(paths, path)
+
+			accs = append(accs, w.account(nextAddr, path))
+
+			// Display a log message to the user for new (or previously empty accounts)
+			if _, known := w.paths[nextAddr]; !known || (!empty && nextAddr == w.deriveNextAddr) {
+				w.log.Info("HD wallet discovered new account", "address", nextAddr, "path", path, "balance", balance, "nonce", nonce)
+			}
+			// Fetch the next potential account
+			if !empty {
+				nextAddr = common.Address{}
+				nextPath[len(nextPath)-1]++
+			}
+		}
+		w.stateLock.RUnlock()
+
+		// Insert any accounts successfully derived
+		w.stateLock.Lock()
+		for i := 0; i < len(accs); i++ {
+			if _, ok := w.paths[accs[i].Address]; !ok {
+				w.accounts = append(w.accounts, accs[i])
+				w.paths[accs[i].Address] = paths[i]
+			}
+		}
+		// Shift the self-derivation forward
+		w.deriveNextAddr = nextAddr
+		w.deriveNextPath = nextPath
+		w.stateLock.Unlock()
+
+		// Notify the user of termination and loop after a bit of time (to avoid trashing)
+		reqc <- struct{}{}
+		if err == nil {
+			select {
+			case errc = <-w.deriveQuit:
+				// Termination requested, abort
+			case <-time.After(selfDeriveThrottling):
+				// Waited enough, willing to self-derive again
+			}
+		}
+	}
+	// In case of error, wait for termination
+	if err != nil {
+		w.log.Debug("HD wallet self-derivation failed", "err", err)
+		errc = <-w.deriveQuit
+	}
+	errc <- err
+}
+
+// derive derives the address at the given path from the master key.
+//
+// Note, derive assumes the state lock is held!
+func (w *wallet) derive(path accounts.DerivationPath) (common.Address, error) {
+	key, err := w.privateKey(w.master, path)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(key.PublicKey), nil
+}
+
+// privateKey derives the private key at the given path from a master key.
+func (w *wallet) privateKey(master *ExtendedKey, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	child, err := master.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+	return child.PrivateKey()
+}
+
+// account assembles the account of an address derived at the given path, its
+// URL being the wallet URL suffixed with the path.
+func (w *wallet) account(address common.Address, path accounts.DerivationPath) accounts.Account {
+	return accounts.Account{
+		Address: address,
+		URL:     accounts.URL{Scheme: w.url.Scheme, Path: fmt.Sprintf("%s/%s", w.url.Path, path)},
+	}
+}
+
+// Contains implements accounts.Wallet, returning whether a particular account is
+// or is not pinned into this wallet instance.
+func (w *wallet) Contains(account accounts.Account) bool {
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	_, exists := w.paths[account.Address]
+	return exists
+}
+
+// Derive implements accounts.Wallet, deriving a new account at the specific
+// derivation path. If pin is set to true, the account will be added to the list
+// of tracked accounts.
+func (w *wallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	w.stateLock.RLock()
+	if w.master == nil {
+		w.stateLock.RUnlock()
+		return accounts.Account{}, accounts.ErrWalletClosed
+	}
+	address, err := w.derive(path)
+	w.stateLock.RUnlock()
+
+	// If an error occurred or no pinning was requested, return
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	account := w.account(address, path)
+	if !pin {
+		return account, nil
+	}
+	// Pinning needs to modify the state
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	if w.paths == nil {
+		return accounts.Account{}, accounts.ErrWalletClosed
+	}
+	if _, ok := w.paths[address]; !ok {
+		w.accounts = append(w.accounts, account)
+		w.paths[address] = path
+	}
+	return account, nil
+}
+
+// SelfDerive implements accounts.Wallet, trying to discover accounts that the
+// user used previously (based on the chain state), but ones that he/she did not
+// explicitly pin to the wallet manually. To avoid chain head monitoring, self
+// derivation only runs during account listing (and even then throttled).
+func (w *wallet) SelfDerive(base accounts.DerivationPath, chain ethereum.ChainStateReader) {
+	w.stateLock.Lock()
+	defer w.stateLock.Unlock()
+
+	w.deriveNextPath = make(accounts.DerivationPath, len(base))
+	copy(w.deriveNextPath[:], base[:])
+
+	w.deriveNextAddr = common.Address{}
+	w.deriveChain = chain
+}
+
+// accountPath resolves the derivation path of an account, either from the list
+// of pinned accounts or from the account URL if derived but not pinned.
+//
+// Note, accountPath assumes the state lock is held!
+func (w *wallet) accountPath(account accounts.Account) (accounts.DerivationPath, error) {
+	if path, ok := w.paths[account.Address]; ok {
+		return path, nil
+	}
+	prefix := w.url.Path + "/"
+	if account.URL.Scheme != w.url.Scheme || !strings.HasPrefix(account.URL.Path, prefix) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	path, err := accounts.ParseDerivationPath(strings.TrimPrefix(account.URL.Path, prefix))
+	if err != nil {
+		return nil, accounts.ErrUnknownAccount
+	}
+	return path, nil
+}
+
+// signingKey derives the private key of an account from a master key, making
+// sure it actually belongs to the account.
+//
+// Note, signingKey assumes the state lock is held!
+func (w *wallet) signingKey(master *ExtendedKey, account accounts.Account) (*ecdsa.PrivateKey, error) {
+	path, err := w.accountPath(account)
+	if err != nil {
+		return nil, err
+	}
+	key, err := w.privateKey(master, path)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.PubkeyToAddress(key.PublicKey) != account.Address {
+		return nil, accounts.ErrUnknownAccount
+	}
+	return key, nil
+}
+
+// SignHash implements accounts.Wallet, signing the given hash with the key of
+// the account derived from the master key.
+func (w *wallet) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	if w.master == nil {
+		return nil, accounts.ErrWalletClosed
+	}
+	key, err := w.signingKey(w.master, account)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(hash, key)
+}
+
+// SignTx implements accounts.Wallet, signing the given transaction with the key
+// of the account derived from the master key.
+func (w *wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	if w.master == nil {
+		return nil, accounts.ErrWalletClosed
+	}
+	key, err := w.signingKey(w.master, account)
+	if err != nil {
+		return nil, err
+	}
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), key)
+}
+
+// SignHashWithPassphrase implements accounts.Wallet, signing the given hash with
+// the key of the account, decrypting the wallet file with the passphrase without
+// opening the wallet.
+func (w *wallet) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	master, err := w.decrypt(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer master.zero()
+
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	key, err := w.signingKey(master, account)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(hash, key)
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, signing the given transaction
+// with the key of the account, decrypting the wallet file with the passphrase
+// without opening the wallet.
+func (w *wallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	master, err := w.decrypt(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer master.zero()
+
+	w.stateLock.RLock()
+	defer w.stateLock.RUnlock()
+
+	key, err := w.signingKey(master, account)
+	if err != nil {
+		return nil, err
+	}
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), key)
+}
@@ -0,0 +1,166 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pborman/uuid"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+)
+
+const (
+	// KeyTypeNode is the type of encrypted p2p node key files.
+	KeyTypeNode = "nodekey"
+
+	// KeyTypeBLS is the type of encrypted BLS secret key files.
+	KeyTypeBLS = "bls"
+)
+
+// encryptedNodeKeyJSON is the on-disk format of encrypted node and BLS keys. The
+// secret is encrypted the same way as the account keys, but there is no address
+// field, so such files are never mistaken for accounts. The public key is kept in
+// plain text to identify the key without decrypting it.
+type encryptedNodeKeyJSON struct {
+	Type      string     `json:"type"`
+	PublicKey string     `json:"publickey"`
+	Crypto    CryptoJSON `json:"crypto"`
+	Id        string     `json:"id"`
+	Version   int        `json:"version"`
+}
+
+// IsEncryptedNodeKey reports whether the content of a key file is an encrypted
+// node or BLS key, as opposed to a plain hex one.
+func IsEncryptedNodeKey(content []byte) bool {
+	content = bytes.TrimSpace(content)
+	if len(content) == 0 || content[0] != '{' {
+		return false
+	}
+	var keyJSON encryptedNodeKeyJSON
+	if err := json.Unmarshal(content, &keyJSON); err != nil {
+		return false
+	}
+	return keyJSON.Type != "" && keyJSON.Crypto.CipherText != ""
+}
+
+// encryptNodeKey encrypts the raw secret of a key of the given type.
+func encryptNodeKey(keyType string, secret []byte, pubkey []byte, auth string, scryptN, scryptP int) ([]byte, error) {
+	cryptoStruct, err := EncryptDataV3(secret, []byte(auth), scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(encryptedNodeKeyJSON{
+		Type:      keyType,
+		PublicKey: hex.EncodeToString(pubkey),
+		Crypto:    cryptoStruct,
+		Id:        uuid.NewRandom().String(),
+		Version:   version,
+	})
+}
+
+// decryptNodeKey decrypts the raw secret of a key, making sure it's of the
+// expected type.
+func decryptNodeKey(keyType string, keyjson []byte, auth string) ([]byte, error) {
+	var keyJSON encryptedNodeKeyJSON
+	if err := json.Unmarshal(keyjson, &keyJSON); err != nil {
+		return nil, err
+	}
+	if keyJSON.Type != keyType {
+		return nil, fmt.Errorf("key type mismatch: have %q, want %q", keyJSON.Type, keyType)
+	}
+	if keyJSON.Version != version {
+		return nil, fmt.Errorf("version not supported: %v", keyJSON.Version)
+	}
+	return DecryptDataV3(keyJSON.Crypto, auth)
+}
+
+// EncryptNodeKey encrypts a p2p node key using the specified scrypt parameters
+// into a json blob that can be decrypted later on.
+func EncryptNodeKey(key *ecdsa.PrivateKey, auth string, scryptN, scryptP int) ([]byte, error) {
+	return encryptNodeKey(KeyTypeNode, crypto.FromECDSA(key), crypto.FromECDSAPub(&key.PublicKey)[1:], auth, scryptN, scryptP)
+}
+
+// DecryptNodeKey decrypts a p2p node key from a json blob.
+func DecryptNodeKey(keyjson []byte, auth string) (*ecdsa.PrivateKey, error) {
+	secret, err := decryptNodeKey(KeyTypeNode, keyjson, auth)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.ToECDSA(secret)
+}
+
+// EncryptBLSKey encrypts a BLS secret key using the specified scrypt parameters
+// into a json blob that can be decrypted later on.
+func EncryptBLSKey(key *bls.SecretKey, auth string, scryptN, scryptP int) ([]byte, error) {
+	return encryptNodeKey(KeyTypeBLS, key.GetLittleEndian(), key.GetPublicKey().Serialize(), auth, scryptN, scryptP)
+}
+
+// DecryptBLSKey decrypts a BLS secret key from a json blob.
+func DecryptBLSKey(keyjson []byte, auth string) (*bls.SecretKey, error) {
+	secret, err := decryptNodeKey(KeyTypeBLS, keyjson, auth)
+	if err != nil {
+		return nil, err
+	}
+	var key bls.SecretKey
+	if err := key.SetLittleEndian(secret); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// LoadNodeKey loads a p2p node key from a file, which is either encrypted with
+// the given passphrase or a plain hex key as written by crypto.SaveECDSA.
+func LoadNodeKey(file string, auth string) (*ecdsa.PrivateKey, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if IsEncryptedNodeKey(content) {
+		return DecryptNodeKey(content, auth)
+	}
+	return crypto.LoadECDSA(file)
+}
+
+// LoadBLSKey loads a BLS secret key from a file, which is either encrypted with
+// the given passphrase or a plain hex key as written by bls.SaveBLS.
+func LoadBLSKey(file string, auth string) (*bls.SecretKey, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if IsEncryptedNodeKey(content) {
+		return DecryptBLSKey(content, auth)
+	}
+	return bls.LoadBLS(file)
+}
+
+// StoreNodeKey writes a p2p node key to a file, encrypted with the passphrase.
+// An empty passphrase stores the key in plain hex, as crypto.SaveECDSA does.
+func StoreNodeKey(file string, key *ecdsa.PrivateKey, auth string, scryptN, scryptP int) error {
+	if auth == "" {
+		return crypto.SaveECDSA(file, key)
+	}
+	keyjson, err := EncryptNodeKey(key, auth, scryptN, scryptP)
+	if err != nil {
+		return err
+	}
+	return writeKeyFile(file, keyjson)
+}
+
+// StoreBLSKey writes a BLS secret key to a file, encrypted with the passphrase.
+// An empty passphrase stores the key in plain hex, as bls.SaveBLS does.
+func StoreBLSKey(file string, key *bls.SecretKey, auth string, scryptN, scryptP int) error {
+	if auth == "" {
+		return bls.SaveBLS(file, key)
+	}
+	keyjson, err := EncryptBLSKey(key, auth, scryptN, scryptP)
+	if err != nil {
+		return err
+	}
+	return writeKeyFile(file, keyjson)
+}
@@ -0,0 +1,54 @@
+package keystore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+func TestNodeKeyEncryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nodekey-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Plain keys are still loaded as they used to be
+	plainfile := filepath.Join(dir, "plain")
+	if err := StoreNodeKey(plainfile, key, "", veryLightScryptN, veryLightScryptP); err != nil {
+		t.Fatalf("failed to store plain key: %v", err)
+	}
+	content, _ := ioutil.ReadFile(plainfile)
+	if IsEncryptedNodeKey(content) {
+		t.Fatalf("plain key reported as encrypted")
+	}
+	if loaded, err := LoadNodeKey(plainfile, "foo"); err != nil || loaded.D.Cmp(key.D) != 0 {
+		t.Fatalf("plain key mismatch: %v", err)
+	}
+	// Encrypted keys need the right passphrase
+	keyfile := filepath.Join(dir, "encrypted")
+	if err := StoreNodeKey(keyfile, key, "foo", veryLightScryptN, veryLightScryptP); err != nil {
+		t.Fatalf("failed to store encrypted key: %v", err)
+	}
+	content, _ = ioutil.ReadFile(keyfile)
+	if !IsEncryptedNodeKey(content) {
+		t.Fatalf("encrypted key reported as plain")
+	}
+	if _, err := LoadNodeKey(keyfile, "bar"); err != ErrDecrypt {
+		t.Fatalf("wrong passphrase error mismatch: have %v, want %v", err, ErrDecrypt)
+	}
+	if loaded, err := LoadNodeKey(keyfile, "foo"); err != nil || loaded.D.Cmp(key.D) != 0 {
+		t.Fatalf("encrypted key mismatch: %v", err)
+	}
+	// Keys of the wrong type must be rejected
+	if _, err := LoadBLSKey(keyfile, "foo"); err == nil {
+		t.Fatalf("node key loaded as bls key")
+	}
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/gov"
@@ -53,6 +54,11 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	if hash := types.DeriveSha(block.Transactions()); hash != header.TxHash {
 		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, header.TxHash)
 	}
+	// Blocks executed by the consensus engine may not have their parent in the
+	// chain yet, ProcessDirectly verifies their base fee instead.
+	if parent := v.bc.GetHeader(block.ParentHash(), block.NumberU64()-1); parent != nil {
+		return VerifyBaseFee(v.config, parent, header)
+	}
 	return nil
 }
 
@@ -132,3 +138,80 @@ func CalcGasLimit(parent *types.Block, gasFloor /*, gasCeil*/ uint64) uint64 {
 	log.Info("Call CalcGasLimit", "blockNumber", parent.Number().Uint64()+1, "gasFloor", gasFloor, "gasCeil", gasCeil, "parentLimit", parent.GasLimit(), "limit", limit)
 	return limit
 }
+
+// VerifyBaseFee verifies the base fee of header, which must only be set from
+// the dynamic fee fork on and then follow the one of parent.
+func VerifyBaseFee(config *configs.ChainConfig, parent, header *types.Header) error {
+	if !config.IsDynamicFee(header.Number) {
+		if header.BaseFee != nil {
+			return fmt.Errorf("invalid baseFee before fork: have %v, want <nil>", header.BaseFee)
+		}
+		return nil
+	}
+	if header.BaseFee == nil {
+		return fmt.Errorf("header is missing baseFee")
+	}
+	if expected := CalcBaseFee(config, parent); header.BaseFee.Cmp(expected) != 0 {
+		return fmt.Errorf("invalid baseFee: have %v, want %v, parentBaseFee %v, parentGasUsed %d",
+			header.BaseFee, expected, parent.BaseFee, parent.GasUsed)
+	}
+	return nil
+}
+
+// CalcBaseFee computes the base fee of the block after parent. The first block
+// of the dynamic fee fork starts at the initial base fee. The next ones move it
+// towards the gas target, the gas limit divided by the elasticity multiplier,
+// by at most a change denominator fraction of the parent base fee per block.
+func CalcBaseFee(config *configs.ChainConfig, parent *types.Header) *big.Int {
+	// The first block of the fork has no base fee to follow
+	if !config.IsDynamicFee(parent.Number) || parent.BaseFee == nil {
+		return new(big.Int).SetUint64(configs.InitialBaseFee)
+	}
+	number, hash := parent.Number.Uint64()+1, parent.Hash()
+
+	// The parameters are only governed in dpos mode, otherwise the defaults apply
+	denominator, err := gov.GovernBaseFeeChangeDenominator(number, hash)
+	if nil != err || denominator == 0 {
+		log.Debug("cannot find BaseFeeChangeDenominator from govern", "err", err)
+		denominator = configs.BaseFeeChangeDenominator
+	}
+	elasticity, err := gov.GovernElasticityMultiplier(number, hash)
+	if nil != err || elasticity == 0 {
+		log.Debug("cannot find ElasticityMultiplier from govern", "err", err)
+		elasticity = configs.ElasticityMultiplier
+	}
+	minBaseFee, err := gov.GovernMinBaseFee(number, hash)
+	if nil != err {
+		log.Debug("cannot find MinBaseFee from govern", "err", err)
+		minBaseFee = new(big.Int)
+	}
+
+	var (
+		baseFee         = new(big.Int).Set(parent.BaseFee)
+		parentGasTarget = parent.GasLimit / elasticity
+	)
+	switch {
+	case parentGasTarget == 0 || parent.GasUsed == parentGasTarget:
+	case parent.GasUsed > parentGasTarget:
+		// The parent used more gas than its target, the base fee increases
+		delta := new(big.Int).SetUint64(parent.GasUsed - parentGasTarget)
+		delta.Mul(delta, parent.BaseFee)
+		delta.Div(delta, new(big.Int).SetUint64(parentGasTarget))
+		delta.Div(delta, new(big.Int).SetUint64(denominator))
+		if delta.Sign() == 0 {
+			delta.SetUint64(1)
+		}
+		baseFee.Add(baseFee, delta)
+	default:
+		// The parent used less gas than its target, the base fee decreases
+		delta := new(big.Int).SetUint64(parentGasTarget - parent.GasUsed)
+		delta.Mul(delta, parent.BaseFee)
+		delta.Div(delta, new(big.Int).SetUint64(parentGasTarget))
+		delta.Div(delta, new(big.Int).SetUint64(denominator))
+		baseFee.Sub(baseFee, delta)
+	}
+	if baseFee.Cmp(minBaseFee) < 0 {
+		baseFee.Set(minBaseFee)
+	}
+	return baseFee
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state/snapshot"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
@@ -74,6 +75,7 @@ type CacheConfig struct {
 	MaxFutureBlocks int
 	BadBlockLimit   int
 	TriesInMemory   int
+	SnapshotLimit   int // Memory allowance (MB) to use for caching snapshot entries in memory
 
 	DBDisabledGC common.AtomicBool // Whether to disable database garbage collection
 	DBGCInterval uint64            // Block interval for database garbage collection
@@ -166,6 +168,7 @@ type BlockChain struct {
 	currentFastBlock atomic.Value // Current head of the fast-sync chain (may be above the block chain!)
 
 	stateCache    state.Database // State database to reuse between imports (contains state cache)
+	snaps         *snapshot.Tree // Snapshot tree for fast trie leaf access
 	bodyCache     *lru.Cache     // Cache for the most recent block bodies
 	bodyRLPCache  *lru.Cache     // Cache for the most recent block bodies in RLP encoded format
 	receiptsCache *lru.Cache     // Cache for the most recent receipts per block
@@ -204,6 +207,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *con
 			MaxFutureBlocks: 256,
 			BadBlockLimit:   10,
 			TriesInMemory:   128,
+			SnapshotLimit:   256,
 			DBGCInterval:    86400,
 			DBGCTimeout:     time.Minute,
 		}
@@ -311,6 +315,11 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *con
 		}
 	}
 
+	// Load any existing snapshot, regenerating it in the background if loading failed
+	if bc.cacheConfig.SnapshotLimit > 0 {
+		bc.snaps = snapshot.New(bc.db, bc.stateCache.TrieDB(), bc.cacheConfig.SnapshotLimit, bc.CurrentBlock().Root(), true)
+	}
+
 	log.Debug("DB config", "DBDisabledGC", bc.cacheConfig.DBDisabledGC, "DBGCInterval", bc.cacheConfig.DBGCInterval, "DBGCTimeout", bc.cacheConfig.DBGCTimeout, "DBGCMpt", bc.cacheConfig.DBGCMpt)
 	bc.cleaner = NewCleaner(bc, bc.cacheConfig.DBGCInterval, bc.cacheConfig.DBGCTimeout, bc.cacheConfig.DBGCMpt)
 
@@ -475,6 +484,11 @@ func (bc *BlockChain) FastSyncCommitHead(hash common.Hash) error {
 	headBlockGauge.Update(int64(block.NumberU64()))
 	bc.chainmu.Unlock()
 
+	// The synced state replaced the local one, regenerate the snapshot from it
+	if bc.snaps != nil {
+		bc.snaps.Rebuild(block.Root())
+	}
+
 	log.Info("Committed new head block", "number", block.Number(), "hash", hash)
 	bc.engine.Pause()
 	defer bc.engine.Resume()
@@ -533,7 +547,7 @@ func (bc *BlockChain) State() (*state.StateDB, error) {
 
 // StateAt returns a new mutable state based on a particular point in time.
 func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
-	return state.New(root, bc.stateCache)
+	return state.NewWithSnapshot(root, bc.stateCache, bc.snaps)
 }
 
 // StateCache returns the caching database underpinning the blockchain instance.
@@ -823,6 +837,11 @@ func (bc *BlockChain) Stop() {
 
 	bc.wg.Wait()
 
+	// Stop the snapshot generation, persisting its progress. The disk layer is
+	// already up to date with the head, diffs being flattened as blocks commit.
+	if bc.snaps != nil {
+		bc.snaps.Release()
+	}
 	// Ensure the state of a recent block is also stored to disk before exiting.
 	// We're writing three different states to catch different restart scenarios:
 	//  - HEAD:     So we don't need to reprocess any blocks in the general case
@@ -1267,6 +1286,7 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 		log.Error("check block is EIP158 error", "hash", block.Hash(), "number", block.NumberU64())
 		return NonStatTy, err
 	}
+	bc.capSnapshot(block, root, state)
 
 	// If we're running an archive node, always flush
 	if bc.cacheConfig.Disabled {
@@ -1435,6 +1455,26 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	return status, nil
 }
 
+// capSnapshot flattens the snapshot layers of a written block into the disk
+// layer, the block being final once PBFT committed it. Competing blocks that
+// were executed on other parents are dropped. If the block could not be layered
+// on the snapshot, it's regenerated from the state trie of the block instead.
+func (bc *BlockChain) capSnapshot(block *types.Block, root common.Hash, state *state.StateDB) {
+	if bc.snaps == nil {
+		return
+	}
+	if err := state.UpdateSnapshot(root); err != nil {
+		log.Warn("Failed to update state snapshot", "number", block.NumberU64(), "hash", block.Hash(), "root", root, "err", err)
+	}
+	if bc.snaps.Snapshot(root) == nil {
+		bc.snaps.Rebuild(root)
+		return
+	}
+	if err := bc.snaps.Cap(root, 0); err != nil {
+		log.Warn("Failed to cap state snapshot", "number", block.NumberU64(), "hash", block.Hash(), "root", root, "err", err)
+	}
+}
+
 // InsertChain attempts to insert the given batch of blocks in to the canonical
 // chain or, otherwise, create a fork. If an error is returned it will return
 // the index number of the failing block as well an error describing what went
@@ -1550,6 +1590,10 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, []
 
 //joey.lyu
 func (bc *BlockChain) ProcessDirectly(block *types.Block, state *state.StateDB, parent *types.Block) (types.Receipts, error) {
+	if err := VerifyBaseFee(bc.chainConfig, parent.Header(), block.Header()); err != nil {
+		log.Error("Failed to verify base fee", "blockNumber", block.Number(), "blockHash", block.Hash(), "err", err)
+		return nil, err
+	}
 	// Process block using the parent state as reference point.
 	start := time.Now()
 	receipts, _, usedGas, err := bc.processor.Process(block, state, bc.vmConfig)
@@ -150,6 +150,11 @@ func (bcc *BlockChainCache) WriteStateDB(sealHash common.Hash, stateDB *state.St
 	log.Info("Write a StateDB instance to the cache", "sealHash", sealHash, "blockNum", blockNum)
 	if _, exist := bcc.stateDBCache[sealHash]; !exist {
 		bcc.stateDBCache[sealHash] = &stateDBCache{stateDB: stateDB, blockNum: blockNum}
+		// Layer the changes of the block on the snapshot, so the blocks built on
+		// top of it can read from the snapshot before it's committed
+		if err := stateDB.UpdateSnapshot(stateDB.Root()); err != nil {
+			log.Debug("Failed to update state snapshot", "sealHash", sealHash, "blockNum", blockNum, "err", err)
+		}
 	}
 }
 
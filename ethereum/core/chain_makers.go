@@ -167,7 +167,7 @@ func GenerateChain(config *configs.ChainConfig, parent *types.Block, engine cons
 		defer blockchain.Stop()
 
 		b := &BlockGen{i: i, parent: parent, chain: blocks, chainReader: blockchain, statedb: statedb, config: config, engine: engine}
-		b.header = makeHeader(b.chainReader, parent, statedb, b.engine)
+		b.header = makeHeader(config, b.chainReader, parent, statedb, b.engine)
 
 		// Execute any user modifications to the block
 		if gen != nil {
@@ -222,7 +222,7 @@ func GenerateBlockChain2(config *configs.ChainConfig, parent *types.Block, engin
 	blocks, receipts := make(types.Blocks, n), make([]types.Receipts, n)
 	genblock := func(i int, parent *types.Block, statedb *state.StateDB) (*types.Block, types.Receipts) {
 		b := &BlockGen{i: i, parent: parent, chain: blocks, chainReader: blockchain, statedb: statedb, config: config, engine: engine}
-		b.header = makeHeader(b.chainReader, parent, statedb, b.engine)
+		b.header = makeHeader(config, b.chainReader, parent, statedb, b.engine)
 
 		// Execute any user modifications to the block
 		if gen != nil {
@@ -276,7 +276,7 @@ func GenerateBlockChain(config *configs.ChainConfig, parent *types.Block, engine
 	blocks, receipts := make(types.Blocks, n), make([]types.Receipts, n)
 	genblock := func(i int, parent *types.Block, statedb *state.StateDB) (*types.Block, types.Receipts) {
 		b := &BlockGen{i: i, parent: parent, chain: blocks, chainReader: blockchain, statedb: statedb, config: config, engine: engine}
-		b.header = makeHeader(b.chainReader, parent, statedb, b.engine)
+		b.header = makeHeader(config, b.chainReader, parent, statedb, b.engine)
 
 		// Execute any user modifications to the block
 		if gen != nil {
@@ -306,7 +306,7 @@ func GenerateBlockChain(config *configs.ChainConfig, parent *types.Block, engine
 	}
 	return blockchain
 }
-func makeHeader(chain consensus.ChainReader, parent *types.Block, state *state.StateDB, engine consensus.Engine) *types.Header {
+func makeHeader(config *configs.ChainConfig, chain consensus.ChainReader, parent *types.Block, state *state.StateDB, engine consensus.Engine) *types.Header {
 	var time uint64
 	if parent.Time() == 0 {
 		time = 10
@@ -314,7 +314,7 @@ func makeHeader(chain consensus.ChainReader, parent *types.Block, state *state.S
 		time = parent.Time() + 10 // block time is fixed at 10 seconds
 	}
 
-	return &types.Header{
+	header := &types.Header{
 		Root:       state.IntermediateRoot(true),
 		ParentHash: parent.Hash(),
 		Coinbase:   parent.Coinbase(),
@@ -323,6 +323,10 @@ func makeHeader(chain consensus.ChainReader, parent *types.Block, state *state.S
 		Time:       time,
 		Extra:      make([]byte, 65),
 	}
+	if config.IsDynamicFee(header.Number) {
+		header.BaseFee = CalcBaseFee(config, parent.Header())
+	}
+	return header
 }
 
 // makeHeaderChain creates a deterministic chain of headers rooted at parent.
@@ -428,6 +428,9 @@ func (g *Genesis) ToBlock(db ethdb.Database, sdb snapshotdb.BaseDB) *types.Block
 	if g.GasLimit == 0 {
 		head.GasLimit = configs.GenesisGasLimit
 	}
+	if g.Config != nil && g.Config.IsDynamicFee(head.Number) {
+		head.BaseFee = new(big.Int).SetUint64(configs.InitialBaseFee)
+	}
 
 	if _, err := statedb.Commit(false); nil != err {
 		panic("Failed to commit genesis stateDB: " + err.Error())
@@ -20,6 +20,7 @@ type Result struct {
 	toStateObject     *state.ParallelStateObject
 	receipt           *types.Receipt
 	minerEarnings     *big.Int
+	baseFees          *big.Int
 	err               error
 	needRefundGasPool bool
 }
@@ -37,6 +38,7 @@ type ParallelContext struct {
 
 	blockGasUsedHolder *uint64
 	earnings           *big.Int
+	baseFees           *big.Int
 	blockDeadline      time.Time
 	packNewBlock       bool
 	wg                 sync.WaitGroup
@@ -52,6 +54,7 @@ func NewParallelContext(state *state.StateDB, header *types.Header, blockHash co
 		gp:                gp,
 		poppedAddresses:   make(map[common.Address]struct{}),
 		earnings:          big.NewInt(0),
+		baseFees:          big.NewInt(0),
 		packNewBlock:      packNewBlock,
 		signer:            signer,
 		tempContractCache: tempContractCache,
@@ -149,6 +152,14 @@ func (ctx *ParallelContext) AddEarnings(earning *big.Int) {
 	ctx.earnings = new(big.Int).Add(ctx.earnings, earning)
 }
 
+func (ctx *ParallelContext) GetBaseFees() *big.Int {
+	return ctx.baseFees
+}
+
+func (ctx *ParallelContext) AddBaseFees(baseFees *big.Int) {
+	ctx.baseFees = new(big.Int).Add(ctx.baseFees, baseFees)
+}
+
 func (ctx *ParallelContext) SetBlockDeadline(blockDeadline time.Time) {
 	ctx.blockDeadline = blockDeadline
 }
@@ -178,7 +189,7 @@ func (ctx *ParallelContext) buildTransferFailedResult(idx int, err error, needRe
 		"txValue", tx.Value().Uint64(), "needRefundGasPool", needRefundGasPool, "error", err.Error())
 }
 
-func (ctx *ParallelContext) buildTransferSuccessResult(idx int, fromStateObject, toStateObject *state.ParallelStateObject, txGasUsed uint64, minerEarnings *big.Int) {
+func (ctx *ParallelContext) buildTransferSuccessResult(idx int, fromStateObject, toStateObject *state.ParallelStateObject, txGasUsed uint64, minerEarnings, baseFees *big.Int) {
 	tx := ctx.GetTx(idx)
 	var root []byte
 	receipt := types.NewReceipt(root, false, txGasUsed)
@@ -195,6 +206,7 @@ func (ctx *ParallelContext) buildTransferSuccessResult(idx int, fromStateObject,
 		toStateObject:   toStateObject,
 		receipt:         receipt,
 		minerEarnings:   minerEarnings,
+		baseFees:        baseFees,
 		err:             nil,
 	}
 	ctx.SetResult(idx, result)
@@ -229,24 +241,29 @@ func (ctx *ParallelContext) batchMerge(originIdxList []int) {
 
 				// Cumulate the miner's earnings
 				ctx.AddEarnings(resultList[idx].minerEarnings)
+				ctx.AddBaseFees(resultList[idx].baseFees)
 
 			} else {
 				if resultList[idx].needRefundGasPool {
 					tx := ctx.GetTx(idx)
 					ctx.AddGasPool(tx.GetIntrinsicGas())
 				}
-				switch resultList[idx].err {
-				case ErrGasLimitReached, ErrNonceTooHigh, vm.ErrAbort:
-					// pop error
-					ctx.SetPoppedAddress(ctx.GetTx(idx).FromAddr(ctx.signer))
-				default:
-					//shift
-				}
+				ctx.popOnError(idx, resultList[idx].err)
 			}
 		}
 	}
 }
 
+// popOnError pops the sender of a transaction if the error means its following
+// transactions cannot be executed in the block either, otherwise only the
+// transaction itself is shifted.
+func (ctx *ParallelContext) popOnError(idx int, err error) {
+	switch err {
+	case ErrGasLimitReached, ErrNonceTooHigh, vm.ErrAbort:
+		ctx.SetPoppedAddress(ctx.GetTx(idx).FromAddr(ctx.signer))
+	}
+}
+
 func (ctx *ParallelContext) txListInfo() string {
 	var buffer bytes.Buffer
 	if len(ctx.txList) > 0 {
@@ -32,8 +32,9 @@ type Executor struct {
 	vmCfg        vm.Config
 	signer       types.Signer
 
-	workerPool *ants.PoolWithFunc
-	txpool     *TxPool
+	workerPool      *ants.PoolWithFunc
+	speculativePool *ants.PoolWithFunc
+	txpool          *TxPool
 }
 
 type TaskArgs struct {
@@ -54,6 +55,11 @@ func NewExecutor(chainConfig *configs.ChainConfig, chainContext ChainContext, vm
 			executor.executeParallelTx(ctx, idx, intrinsicGas)
 			ctx.wg.Done()
 		})
+		executor.speculativePool, _ = ants.NewPoolWithFunc(runtime.NumCPU(), func(i interface{}) {
+			task := i.(*speculativeTask)
+			executor.executeSpeculativeTx(task)
+			task.wg.Done()
+		})
 		executor.chainConfig = chainConfig
 		executor.chainContext = chainContext
 		executor.signer = types.NewEIP155Signer(chainConfig.ChainID)
@@ -91,6 +97,8 @@ func (exe *Executor) ExecuteTransactions(ctx *ParallelContext) error {
 
 			if len(parallelTxIdxs) == 1 && txDag.IsContract(parallelTxIdxs[0]) {
 				exe.executeContractTransaction(ctx, parallelTxIdxs[0])
+			} else if txDag.IsSpeculativeGroup(parallelTxIdxs) {
+				exe.executeContractGroup(ctx, parallelTxIdxs)
 			} else {
 				for _, originIdx := range parallelTxIdxs {
 					tx := ctx.GetTx(originIdx)
@@ -107,7 +115,7 @@ func (exe *Executor) ExecuteTransactions(ctx *ParallelContext) error {
 						}
 					}
 
-					intrinsicGas, err := IntrinsicGas(tx.Data(), false, nil)
+					intrinsicGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), false, nil)
 					if err != nil {
 						ctx.buildTransferFailedResult(originIdx, err, false)
 						continue
@@ -134,6 +142,7 @@ func (exe *Executor) ExecuteTransactions(ctx *ParallelContext) error {
 		if ctx.GetEarnings().Cmp(big.NewInt(0)) > 0 {
 			ctx.state.AddMinerEarnings(ctx.header.Coinbase, ctx.GetEarnings())
 		}
+		settleBaseFee(ctx.state, ctx.header.Number, ctx.GetBlockHash(), ctx.GetBaseFees())
 		start = time.Now()
 		ctx.state.Finalise(true)
 		log.Trace("Finalise stateDB cost", "number", ctx.header.Number, "time", time.Since(start))
@@ -155,7 +164,7 @@ func (exe *Executor) executeParallelTx(ctx *ParallelContext, idx int, intrinsicG
 	}
 	tx := ctx.GetTx(idx)
 
-	msg, err := tx.AsMessage(exe.signer)
+	msg, err := tx.AsMessage(exe.signer, ctx.header.BaseFee)
 	if err != nil {
 		//gas pool is subbed
 		ctx.buildTransferFailedResult(idx, err, true)
@@ -173,7 +182,22 @@ func (exe *Executor) executeParallelTx(ctx *ParallelContext, idx int, intrinsicG
 		log.Debug("Get state object overtime", "address", msg.From().String(), "duration", time.Since(start))
 	}
 
-	mgval := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasPrice())
+	if !txTypeSupported(exe.chainConfig, tx, ctx.header.Number) {
+		ctx.buildTransferFailedResult(idx, ErrTxTypeNotSupported, true)
+		return
+	}
+	if baseFee := ctx.header.BaseFee; baseFee != nil {
+		if tx.GasFeeCapIntCmp(tx.GasTipCap()) < 0 {
+			ctx.buildTransferFailedResult(idx, ErrTipAboveFeeCap, true)
+			return
+		}
+		if tx.GasFeeCapIntCmp(baseFee) < 0 {
+			ctx.buildTransferFailedResult(idx, ErrFeeCapTooLow, true)
+			return
+		}
+	}
+
+	mgval := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
 	if fromObj.GetBalance().Cmp(mgval) < 0 {
 		ctx.buildTransferFailedResult(idx, errInsufficientBalanceForGas, true)
 		return
@@ -187,8 +211,8 @@ func (exe *Executor) executeParallelTx(ctx *ParallelContext, idx int, intrinsicG
 		return
 	}
 
-	minerEarnings := new(big.Int).Mul(new(big.Int).SetUint64(intrinsicGas), msg.GasPrice())
-	subTotal := new(big.Int).Add(msg.Value(), minerEarnings)
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(intrinsicGas), msg.GasPrice())
+	subTotal := new(big.Int).Add(msg.Value(), fee)
 	if fromObj.GetBalance().Cmp(subTotal) < 0 {
 		ctx.buildTransferFailedResult(idx, errInsufficientBalanceForGas, true)
 		return
@@ -205,13 +229,19 @@ func (exe *Executor) executeParallelTx(ctx *ParallelContext, idx int, intrinsicG
 	}
 	toObj.AddBalance(msg.Value())
 
-	ctx.buildTransferSuccessResult(idx, fromObj, toObj, intrinsicGas, minerEarnings)
+	// The block producer only earns the tip of the fee after the dynamic fee fork
+	minerEarnings, baseFees := fee, new(big.Int)
+	if ctx.header.BaseFee != nil {
+		baseFees.Mul(new(big.Int).SetUint64(intrinsicGas), ctx.header.BaseFee)
+		minerEarnings = new(big.Int).Sub(fee, baseFees)
+	}
+	ctx.buildTransferSuccessResult(idx, fromObj, toObj, intrinsicGas, minerEarnings, baseFees)
 	return
 }
 
-func (exe *Executor) executeContractTransaction(ctx *ParallelContext, idx int) {
+func (exe *Executor) executeContractTransaction(ctx *ParallelContext, idx int) error {
 	if ctx.IsTimeout() {
-		return
+		return nil
 	}
 	snap := ctx.GetState().Snapshot()
 	tx := ctx.GetTx(idx)
@@ -222,12 +252,18 @@ func (exe *Executor) executeContractTransaction(ctx *ParallelContext, idx int) {
 	if err != nil {
 		log.Warn("Execute contract transaction failed", "blockNumber", ctx.GetHeader().Number.Uint64(), "txHash", tx.Hash(), "gasPool", ctx.GetGasPool().Gas(), "txGasLimit", tx.Gas(), "err", err.Error())
 		ctx.GetState().RevertToSnapshot(snap)
-		return
+		return err
 	}
 	ctx.AddPackedTx(tx)
 	ctx.GetState().IncreaseTxIdx()
 	ctx.AddReceipt(receipt)
 	log.Debug("Execute contract transaction success", "blockNumber", ctx.GetHeader().Number.Uint64(), "txHash", tx.Hash().Hex(), "gasPool", ctx.gp.Gas(), "txGasLimit", tx.Gas(), "gasUsed", receipt.GasUsed)
+	return nil
+}
+
+// isSpeculative returns whether the contract transaction can be executed on a copy of the state.
+func (exe *Executor) isSpeculative(tx *types.Transaction) bool {
+	return tx.To() == nil || !vm.IsPhoenixChainPrecompiledContract(*tx.To())
 }
 
 func (exe *Executor) isContract(tx *types.Transaction, state *state.StateDB, ctx *ParallelContext) bool {
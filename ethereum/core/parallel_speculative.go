@@ -0,0 +1,172 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+)
+
+// speculativeTask executes a contract transaction on a private copy of the
+// state, in parallel with the other members of its group.
+type speculativeTask struct {
+	ctx    *ParallelContext
+	idx    int
+	state  *state.StateDB
+	result *speculativeResult
+	wg     *sync.WaitGroup
+}
+
+// speculativeResult is the outcome of a speculative execution along with the
+// accesses it made to the state.
+type speculativeResult struct {
+	msg    types.Message
+	result *ExecutionResult
+	rwSet  *state.TxRWSet
+	logs   []*types.Log
+	err    error
+}
+
+// executeContractGroup executes a group of contract transactions optimistically.
+// Every transaction runs in parallel on a copy of the state before the group and
+// records its read and write sets. The results are then committed in transaction
+// order; a transaction whose reads were written by an earlier one is executed
+// again on the current state. Transactions that cannot be executed on a copy
+// fall back to serial execution, so the result is the same as executing the
+// group one transaction after the other. When packing a block, the group stops
+// at the deadline and skips the senders popped from the block like the batches
+// of transfers.
+func (exe *Executor) executeContractGroup(ctx *ParallelContext, idxs []int) {
+	if ctx.IsTimeout() {
+		return
+	}
+	start := time.Now()
+	txIdxs := make([]int, 0, len(idxs))
+	for _, idx := range idxs {
+		if ctx.packNewBlock {
+			from := ctx.GetTx(idx).FromAddr(exe.signer)
+			if _, popped := ctx.poppedAddresses[from]; popped {
+				log.Debug("Address popped", "from", from.String())
+				continue
+			}
+		}
+		txIdxs = append(txIdxs, idx)
+	}
+	sort.Ints(txIdxs)
+
+	var wg sync.WaitGroup
+	tasks := make([]*speculativeTask, len(txIdxs))
+	for i, idx := range txIdxs {
+		tasks[i] = &speculativeTask{ctx: ctx, idx: idx, state: ctx.GetState().Copy(), wg: &wg}
+	}
+	for _, task := range tasks {
+		wg.Add(1)
+		_ = exe.speculativePool.Invoke(task)
+	}
+	wg.Wait()
+
+	for _, task := range tasks {
+		task.state.ClearParentReference()
+	}
+
+	written := state.NewStateWrites()
+	reExecuted, serial := 0, 0
+	for _, task := range tasks {
+		if ctx.IsTimeout() {
+			log.Warn("Parallel executor is timeout,interrupt current tx-executing")
+			break
+		}
+		res := task.result
+		if res.err == nil && ctx.GetGasPool().Gas() >= ctx.GetTx(task.idx).Gas() {
+			if res.rwSet.Conflicts(written) {
+				// The state read by the transaction has changed, execute it again on
+				// a copy of the current state which nothing can conflict with.
+				retry := &speculativeTask{ctx: ctx, idx: task.idx, state: ctx.GetState().Copy()}
+				exe.executeSpeculativeTx(retry)
+				retry.state.ClearParentReference()
+				res = retry.result
+				reExecuted++
+			}
+			if res.err == nil {
+				exe.commitSpeculativeTx(ctx, task.idx, res, written)
+				continue
+			}
+		}
+		// The transaction failed, does not fit in the gas pool or called a PhoenixChain
+		// precompiled contract. Its changes are not known, so after the serial execution
+		// no speculative result can be trusted anymore.
+		if err := exe.executeContractTransaction(ctx, task.idx); err != nil {
+			ctx.popOnError(task.idx, err)
+		}
+		written.Invalidate()
+		serial++
+	}
+	log.Debug("Execute contract group", "blockNumber", ctx.GetHeader().Number.Uint64(), "txs", len(tasks),
+		"reExecuted", reExecuted, "serial", serial, "time", time.Since(start))
+}
+
+// executeSpeculativeTx applies the transaction of the task to the state of the
+// task, recording the accesses it makes.
+func (exe *Executor) executeSpeculativeTx(task *speculativeTask) {
+	res := &speculativeResult{}
+	task.result = res
+	if task.ctx.IsTimeout() {
+		res.err = vm.ErrAbort
+		return
+	}
+
+	tx := task.ctx.GetTx(task.idx)
+	msg, err := tx.AsMessage(exe.signer, task.ctx.GetHeader().BaseFee)
+	if err != nil {
+		res.err = err
+		return
+	}
+	statedb := task.state
+	statedb.Prepare(tx.Hash(), task.ctx.GetBlockHash(), task.idx)
+	statedb.StartRWSet()
+
+	cfg := exe.vmCfg
+	cfg.Speculative = true
+	vmenv := vm.NewEVM(NewEVMContext(msg, task.ctx.GetHeader(), exe.chainContext), nil, statedb, exe.chainConfig, cfg)
+	result, err := ApplyMessage(vmenv, msg, new(GasPool).AddGas(msg.Gas()))
+	res.rwSet = statedb.RWSet()
+	if err != nil {
+		res.err = err
+		return
+	}
+	if vmenv.Cancelled() {
+		res.err = vm.ErrAbort
+		return
+	}
+	res.msg, res.result, res.logs = msg, result, statedb.GetLogs(tx.Hash())
+}
+
+// commitSpeculativeTx applies the result of a speculative execution to the
+// state of the context, the transaction must fit in the gas pool.
+func (exe *Executor) commitSpeculativeTx(ctx *ParallelContext, idx int, res *speculativeResult, written *state.StateWrites) {
+	tx := ctx.GetTx(idx)
+	statedb := ctx.GetState()
+	statedb.Prepare(tx.Hash(), ctx.GetBlockHash(), int(statedb.TxIdx()))
+	statedb.ApplyRWSet(res.rwSet, written)
+	for _, l := range res.logs {
+		statedb.AddLog(l)
+	}
+	statedb.Finalise(true)
+	_ = ctx.GetGasPool().SubGas(res.result.UsedGas)
+	ctx.CumulateBlockGasUsed(res.result.UsedGas)
+
+	receipt, err := newReceipt(statedb, ctx.GetHeader(), tx, res.msg, res.result, ctx.GetBlockGasUsed())
+	if err != nil {
+		log.Error("Failed to create the receipt of a speculative transaction", "blockNumber", ctx.GetHeader().Number.Uint64(), "txHash", tx.Hash(), "err", err)
+		return
+	}
+	ctx.AddPackedTx(tx)
+	statedb.IncreaseTxIdx()
+	ctx.AddReceipt(receipt)
+	log.Debug("Execute speculative contract transaction success", "blockNumber", ctx.GetHeader().Number.Uint64(), "txHash", tx.Hash().Hex(),
+		"gasPool", ctx.GetGasPool().Gas(), "txGasLimit", tx.Gas(), "gasUsed", receipt.GasUsed, "reads", res.rwSet.ReadAccounts(), "writes", res.rwSet.WriteAccounts())
+}
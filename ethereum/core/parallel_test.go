@@ -18,12 +18,14 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus"
 	state2 "github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
 	cvm "github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/xcom"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -330,6 +332,146 @@ func parallelMode(t testing.TB, testTxList types.Transactions, blockchain *Block
 	return block
 }
 
+func TestParallel_ContractGroupTimeoutAndPop(t *testing.T) {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+	fromAccountList, toAccountList, contractAccountList := initAccount()
+	blockchain, stateDb, header := initChain(fromAccountList, toAccountList, contractAccountList)
+	NewExecutor(chainConfig, blockchain, blockchain.vmConfig, nil)
+
+	// Contract calls of three senders, the last one with a nonce too high.
+	txs := make(types.Transactions, 3)
+	for i := range txs {
+		nonce := uint64(0)
+		if i == 2 {
+			nonce = 1
+		}
+		txs[i], _ = types.SignTx(types.NewTransaction(nonce, contractAccountList[i].address, big.NewInt(0), 100000, gasPrice, nil), signer, fromAccountList[i].priKey)
+	}
+	newCtx := func(deadline time.Time) *ParallelContext {
+		ctx := NewParallelContext(stateDb.Copy(), header, common.Hash{}, new(GasPool).AddGas(header.GasLimit), true, signer, make(map[common.Address]struct{}))
+		ctx.SetBlockDeadline(deadline)
+		ctx.SetBlockGasUsedHolder(new(uint64))
+		ctx.SetTxList(txs)
+		return ctx
+	}
+
+	// Nothing is packed after the deadline of the block.
+	ctx := newCtx(time.Now().Add(-time.Second))
+	GetExecutor().executeContractGroup(ctx, []int{0, 1, 2})
+	assert.Len(t, ctx.GetPackedTxList(), 0)
+	task := &speculativeTask{ctx: ctx, idx: 1, state: ctx.GetState().Copy()}
+	GetExecutor().executeSpeculativeTx(task)
+	assert.Equal(t, cvm.ErrAbort, task.result.err)
+
+	// Popped senders are skipped, and a nonce too high pops the sender.
+	ctx = newCtx(time.Now().Add(time.Minute))
+	ctx.SetPoppedAddress(fromAccountList[0].address)
+	GetExecutor().executeContractGroup(ctx, []int{0, 1, 2})
+	if assert.Len(t, ctx.GetPackedTxList(), 1) {
+		assert.Equal(t, txs[1].Hash(), ctx.GetPackedTxList()[0].Hash())
+	}
+	_, popped := ctx.poppedAddresses[fromAccountList[2].address]
+	assert.True(t, popped)
+}
+
+var (
+	// counterCode increments the first storage slot and logs the new value.
+	counterCode = "6000546001018060005560005260206000a000"
+	// precompileCallerCode calls the reward pool, a PhoenixChain precompiled contract, then counts like counterCode.
+	precompileCallerCode = "6000600060006000600073" + hex.EncodeToString(vm.RewardManagerPoolAddr.Bytes()) + "5af150" + counterCode
+)
+
+func TestParallel_SpeculativeGroupMatchesSerial(t *testing.T) {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+	fromAccountList, toAccountList, contractAccountList := initAccount()
+	blockchain, stateDb, header := initChain(fromAccountList, toAccountList, contractAccountList)
+	NewExecutor(chainConfig, blockchain, blockchain.vmConfig, nil)
+
+	counterA, counterB, counterC, caller := common.Address{0xa}, common.Address{0xb}, common.Address{0xc}, common.Address{0xd}
+	for _, addr := range []common.Address{counterA, counterB, counterC} {
+		stateDb.SetCode(addr, common.Hex2Bytes(counterCode))
+	}
+	stateDb.SetCode(caller, common.Hex2Bytes(precompileCallerCode))
+	stateDb.Finalise(false)
+
+	newTx := func(from int, to common.Address, value int64) *types.Transaction {
+		sender := fromAccountList[from]
+		tx, _ := types.SignTx(types.NewTransaction(sender.nonce, to, big.NewInt(value), 200000, gasPrice, nil), signer, sender.priKey)
+		types.Sender(signer, tx)
+		sender.nonce++
+		return tx
+	}
+	txs := types.Transactions{
+		// a group where the second and the last call conflict with the first one, and
+		// the fourth aborts on the precompiled contract and is executed serially
+		newTx(0, counterA, 0),
+		newTx(1, counterA, 0),
+		newTx(2, counterB, 0),
+		newTx(3, caller, 0),
+		newTx(4, counterA, 0),
+		// transfers between the groups, one of them from a sender of the first group
+		newTx(5, toAccountList[0].address, 1000),
+		newTx(0, toAccountList[1].address, 1000),
+		// a group without conflicts
+		newTx(6, counterB, 0),
+		newTx(7, counterC, 0),
+		newTx(8, caller, 0),
+	}
+	block := types.NewBlock(header, txs, nil)
+
+	txDag := NewTxDag(signer)
+	ctx := NewParallelContext(stateDb.Copy(), header, block.Hash(), new(GasPool).AddGas(header.GasLimit), false, signer, make(map[common.Address]struct{}))
+	ctx.SetTxList(txs)
+	if err := txDag.MakeDagGraph(ctx, GetExecutor()); err != nil {
+		t.Fatal("Make dag graph failed", "err", err)
+	}
+	group := txDag.Next()
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, group)
+	assert.True(t, txDag.IsSpeculativeGroup(group))
+	task := &speculativeTask{ctx: ctx, idx: 3, state: ctx.GetState().Copy()}
+	GetExecutor().executeSpeculativeTx(task)
+	assert.Equal(t, cvm.ErrAbort, task.result.err)
+
+	serialState, parallelState := stateDb.Copy(), stateDb.Copy()
+
+	var serialGasUsed uint64
+	gp := new(GasPool).AddGas(header.GasLimit)
+	serialReceipts := make(types.Receipts, 0, len(txs))
+	for idx, tx := range txs {
+		serialState.Prepare(tx.Hash(), block.Hash(), idx)
+		receipt, _, err := ApplyTransaction(chainConfig, blockchain, gp, serialState, header, tx, &serialGasUsed, blockchain.vmConfig)
+		if err != nil {
+			t.Fatal("Apply transaction failed", "idx", idx, "err", err)
+		}
+		serialReceipts = append(serialReceipts, receipt)
+	}
+
+	var parallelGasUsed uint64
+	ctx = NewParallelContext(parallelState, header, block.Hash(), new(GasPool).AddGas(header.GasLimit), false, signer, make(map[common.Address]struct{}))
+	ctx.SetBlockGasUsedHolder(&parallelGasUsed)
+	ctx.SetTxList(txs)
+	if err := GetExecutor().ExecuteTransactions(ctx); err != nil {
+		t.Fatal("Execute transactions failed", "err", err)
+	}
+	parallelReceipts := sortReceipts(txs, ctx.GetReceipts())
+
+	assert.Equal(t, big.NewInt(3), new(big.Int).SetBytes(serialState.GetState(counterA, common.Hash{}.Bytes())))
+	assert.Equal(t, serialGasUsed, parallelGasUsed)
+	assert.Equal(t, types.DeriveSha(serialReceipts), types.DeriveSha(parallelReceipts))
+	if assert.Len(t, parallelReceipts, len(serialReceipts)) {
+		for i, expect := range serialReceipts {
+			actual := parallelReceipts[i]
+			assert.Equal(t, expect.TxHash, actual.TxHash)
+			assert.Equal(t, expect.Status, actual.Status, "status of transaction %d", i)
+			assert.Equal(t, expect.GasUsed, actual.GasUsed, "gas used by transaction %d", i)
+			assert.Equal(t, expect.CumulativeGasUsed, actual.CumulativeGasUsed, "cumulative gas used by transaction %d", i)
+			assert.Equal(t, expect.Bloom, actual.Bloom, "bloom of transaction %d", i)
+			assert.Equal(t, expect.Logs, actual.Logs, "logs of transaction %d", i)
+		}
+	}
+	assert.Equal(t, serialState.IntermediateRoot(true), parallelState.IntermediateRoot(true))
+}
+
 func TestParallel_PackSerial_VerifyParallel(t *testing.T) {
 	fromAccountList, toAccountList, contractAccountList := initAccount()
 	blockchain, stateDb, header := initChain(fromAccountList, toAccountList, contractAccountList)
@@ -8,15 +8,17 @@ import (
 )
 
 type TxDag struct {
-	dag       *dag3.Dag
-	signer    types.Signer
-	contracts map[int]struct{}
+	dag         *dag3.Dag
+	signer      types.Signer
+	contracts   map[int]struct{}
+	speculative map[int]struct{}
 }
 
 func NewTxDag(signer types.Signer) *TxDag {
 	txDag := &TxDag{
-		signer:    signer,
-		contracts: make(map[int]struct{}),
+		signer:      signer,
+		contracts:   make(map[int]struct{}),
+		speculative: make(map[int]struct{}),
 	}
 	return txDag
 }
@@ -28,6 +30,11 @@ func (txDag *TxDag) MakeDagGraph(ctx *ParallelContext, exe *Executor) error {
 	//save all transfer addresses between two contracts(precompiled and user defined)
 	transferAddressMap := make(map[common.Address]int, 0)
 	latestPrecompiledIndex := -1
+	//consecutive contract transactions that can be executed speculatively share their dependencies
+	//and form a group, the transactions after the group depend on all its members
+	var latestContracts []int
+	//storage slots declared by the access lists of the group members
+	groupSlots := make(declaredSlots)
 	for index, tx := range txs {
 		if tx.FromAddr(txDag.signer) == (common.Address{}) {
 			log.Error("The from of the transaction cannot be resolved", "number", blockNumber, "index", index)
@@ -36,14 +43,32 @@ func (txDag *TxDag) MakeDagGraph(ctx *ParallelContext, exe *Executor) error {
 
 		if exe.isContract(tx, state, ctx) {
 			txDag.contracts[index] = struct{}{}
-			if index > 0 {
-				if index-latestPrecompiledIndex > 1 {
-					for begin := latestPrecompiledIndex + 1; begin < index; begin++ {
-						txDag.dag.AddEdge(begin, index)
+			speculative := exe.isSpeculative(tx)
+			if speculative && latestPrecompiledIndex >= 0 && index-latestPrecompiledIndex == 1 && txDag.IsSpeculative(latestPrecompiledIndex) &&
+				!groupSlots.overlaps(tx.AccessList()) {
+				for _, dependIdx := range txDag.dag.GetInEdges(latestPrecompiledIndex) {
+					txDag.dag.AddEdge(dependIdx, index)
+				}
+				latestContracts = append(latestContracts, index)
+				groupSlots.add(tx.AccessList())
+			} else {
+				if index > 0 {
+					if index-latestPrecompiledIndex > 1 {
+						for begin := latestPrecompiledIndex + 1; begin < index; begin++ {
+							txDag.dag.AddEdge(begin, index)
+						}
+					} else if index-latestPrecompiledIndex == 1 {
+						for _, dependIdx := range latestContracts {
+							txDag.dag.AddEdge(dependIdx, index)
+						}
 					}
-				} else if index-latestPrecompiledIndex == 1 {
-					txDag.dag.AddEdge(latestPrecompiledIndex, index)
 				}
+				latestContracts = []int{index}
+				groupSlots = make(declaredSlots)
+				groupSlots.add(tx.AccessList())
+			}
+			if speculative {
+				txDag.speculative[index] = struct{}{}
 			}
 			latestPrecompiledIndex = index
 			//reset transferAddressMap
@@ -63,7 +88,9 @@ func (txDag *TxDag) MakeDagGraph(ctx *ParallelContext, exe *Executor) error {
 				dependFound++
 			}
 			if dependFound == 0 && latestPrecompiledIndex >= 0 {
-				txDag.dag.AddEdge(latestPrecompiledIndex, index)
+				for _, dependIdx := range latestContracts {
+					txDag.dag.AddEdge(dependIdx, index)
+				}
 			}
 
 			transferAddressMap[tx.FromAddr(txDag.signer)] = index
@@ -85,6 +112,40 @@ func (txDag *TxDag) MakeDagGraph(ctx *ParallelContext, exe *Executor) error {
 	return nil
 }
 
+// declaredSlots holds the storage slots declared by the access lists of a group
+// of transactions. A transaction declaring a slot of the group most likely
+// conflicts with one of its members, so it is executed after the group rather
+// than speculatively along with it.
+type declaredSlots map[common.Address]map[common.Hash]struct{}
+
+func (d declaredSlots) add(list types.AccessList) {
+	for _, tuple := range list {
+		slots, ok := d[tuple.Address]
+		if !ok {
+			slots = make(map[common.Hash]struct{}, len(tuple.StorageKeys))
+			d[tuple.Address] = slots
+		}
+		for _, key := range tuple.StorageKeys {
+			slots[key] = struct{}{}
+		}
+	}
+}
+
+func (d declaredSlots) overlaps(list types.AccessList) bool {
+	for _, tuple := range list {
+		slots, ok := d[tuple.Address]
+		if !ok {
+			continue
+		}
+		for _, key := range tuple.StorageKeys {
+			if _, ok := slots[key]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (txDag *TxDag) HasNext() bool {
 	return txDag.dag.HasNext()
 }
@@ -99,3 +160,24 @@ func (txDag *TxDag) IsContract(idx int) bool {
 	}
 	return false
 }
+
+// IsSpeculative returns whether the contract transaction can be executed on a
+// copy of the state, i.e. it is not sent to a PhoenixChain precompiled contract.
+func (txDag *TxDag) IsSpeculative(idx int) bool {
+	_, ok := txDag.speculative[idx]
+	return ok
+}
+
+// IsSpeculativeGroup returns whether the transactions are a group of contract
+// transactions that can be executed speculatively in parallel.
+func (txDag *TxDag) IsSpeculativeGroup(idxs []int) bool {
+	if len(idxs) < 2 {
+		return false
+	}
+	for _, idx := range idxs {
+		if !txDag.IsSpeculative(idx) {
+			return false
+		}
+	}
+	return true
+}
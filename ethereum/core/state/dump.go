@@ -24,11 +24,19 @@ type Dump struct {
 }
 
 func (self *StateDB) RawDump() Dump {
+	root := self.trie.Hash()
 	dump := Dump{
-		Root:     fmt.Sprintf("%x", self.trie.Hash()),
+		Root:     fmt.Sprintf("%x", root),
 		Accounts: make(map[string]DumpAccount),
 	}
-
+	// Iterate the flat snapshot if it holds the unmodified state, which is
+	// a lot faster than walking the tries
+	if self.snaps != nil && root == self.snapRoot {
+		if accounts, err := self.dumpSnapshot(); err == nil {
+			dump.Accounts = accounts
+			return dump
+		}
+	}
 	it := trie.NewIterator(self.trie.NodeIterator(nil))
 	for it.Next() {
 		addr := self.trie.GetKey(it.Key)
@@ -38,14 +46,7 @@ func (self *StateDB) RawDump() Dump {
 		}
 
 		obj := self.getStateObject(common.BytesToAddress(addr))
-		account := DumpAccount{
-			Balance:  data.Balance.String(),
-			Nonce:    data.Nonce,
-			Root:     common.Bytes2Hex(data.Root[:]),
-			CodeHash: common.Bytes2Hex(data.CodeHash),
-			Code:     common.Bytes2Hex(obj.Code(self.db)),
-			Storage:  make(map[string]string),
-		}
+		account := self.dumpAccount(obj, data)
 		storageIt := trie.NewIterator(obj.getTrie(self.db).NodeIterator(nil))
 		for storageIt.Next() {
 			account.Storage[common.Bytes2Hex(self.trie.GetKey(storageIt.Key))] = common.Bytes2Hex(storageIt.Value)
@@ -55,6 +56,56 @@ func (self *StateDB) RawDump() Dump {
 	return dump
 }
 
+// dumpSnapshot collects the accounts of the state from the snapshot layer it
+// is based on. An error is returned if the snapshot can't be iterated.
+func (self *StateDB) dumpSnapshot() (map[string]DumpAccount, error) {
+	accIt, err := self.snaps.AccountIterator(self.snapRoot, common.Hash{})
+	if err != nil {
+		return nil, err
+	}
+	defer accIt.Release()
+
+	accounts := make(map[string]DumpAccount)
+	for accIt.Next() {
+		addr := self.trie.GetKey(accIt.Hash().Bytes())
+		var data Account
+		if err := rlp.DecodeBytes(accIt.Account(), &data); err != nil {
+			panic(err)
+		}
+
+		obj := self.getStateObject(common.BytesToAddress(addr))
+		account := self.dumpAccount(obj, data)
+		storageIt, err := self.snaps.StorageIterator(self.snapRoot, accIt.Hash(), common.Hash{})
+		if err != nil {
+			return nil, err
+		}
+		for storageIt.Next() {
+			account.Storage[common.Bytes2Hex(self.trie.GetKey(storageIt.Hash().Bytes()))] = common.Bytes2Hex(storageIt.Slot())
+		}
+		storageIt.Release()
+		if err := storageIt.Error(); err != nil {
+			return nil, err
+		}
+		accounts[common.Bytes2Hex(addr)] = account
+	}
+	if err := accIt.Error(); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// dumpAccount creates the dump of an account, without its storage.
+func (self *StateDB) dumpAccount(obj *stateObject, data Account) DumpAccount {
+	return DumpAccount{
+		Balance:  data.Balance.String(),
+		Nonce:    data.Nonce,
+		Root:     common.Bytes2Hex(data.Root[:]),
+		CodeHash: common.Bytes2Hex(data.CodeHash),
+		Code:     common.Bytes2Hex(obj.Code(self.db)),
+		Storage:  make(map[string]string),
+	}
+}
+
 func (self *StateDB) Dump() []byte {
 	json, err := json.MarshalIndent(self.RawDump(), "", "    ")
 	if err != nil {
@@ -0,0 +1,249 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+)
+
+// TxRWSet is the read and write set of a transaction executed speculatively on
+// a copy of the state. Reads are recorded while the transaction accesses the
+// state, writes are collected from the journal once the execution finished.
+type TxRWSet struct {
+	readAccounts   map[common.Address]struct{}
+	readStorage    map[common.Address]map[string]struct{}
+	readAllStorage map[common.Address]struct{}
+	migrated       map[common.Address]struct{}
+
+	writes    map[common.Address]*accountWrite
+	preimages map[common.Hash][]byte
+}
+
+// accountWrite holds the changes a transaction made to one account.
+type accountWrite struct {
+	// replaced is set if the account fields other than the balance changed or
+	// the account was reset, touched or removed. Such an account is copied
+	// as a whole, which is only valid if no earlier transaction wrote it.
+	replaced bool
+
+	initialBalance *big.Int
+	storage        map[string][]byte
+	object         *stateObject
+}
+
+func newTxRWSet() *TxRWSet {
+	return &TxRWSet{
+		readAccounts:   make(map[common.Address]struct{}),
+		readStorage:    make(map[common.Address]map[string]struct{}),
+		readAllStorage: make(map[common.Address]struct{}),
+		migrated:       make(map[common.Address]struct{}),
+		writes:         make(map[common.Address]*accountWrite),
+		preimages:      make(map[common.Hash][]byte),
+	}
+}
+
+func (rw *TxRWSet) write(addr common.Address) *accountWrite {
+	w, ok := rw.writes[addr]
+	if !ok {
+		w = &accountWrite{storage: make(map[string][]byte)}
+		rw.writes[addr] = w
+	}
+	return w
+}
+
+// ReadAccounts returns the number of accounts read by the transaction.
+func (rw *TxRWSet) ReadAccounts() int {
+	return len(rw.readAccounts)
+}
+
+// WriteAccounts returns the number of accounts written by the transaction.
+func (rw *TxRWSet) WriteAccounts() int {
+	return len(rw.writes)
+}
+
+// Conflicts reports whether the transaction read or replaced anything written
+// by the transactions committed since the state it was executed on.
+func (rw *TxRWSet) Conflicts(written *StateWrites) bool {
+	if written.invalid {
+		return true
+	}
+	for addr := range rw.readAccounts {
+		if w := written.accounts[addr]; w != nil && (w.account || w.replaced) {
+			return true
+		}
+	}
+	for addr, keys := range rw.readStorage {
+		w := written.accounts[addr]
+		if w == nil {
+			continue
+		}
+		if w.replaced {
+			return true
+		}
+		for key := range keys {
+			if _, ok := w.storage[key]; ok {
+				return true
+			}
+		}
+	}
+	for addr := range rw.readAllStorage {
+		if w := written.accounts[addr]; w != nil && (w.replaced || len(w.storage) > 0) {
+			return true
+		}
+	}
+	for addr, write := range rw.writes {
+		if w := written.accounts[addr]; w != nil && (write.replaced || w.replaced) {
+			return true
+		}
+	}
+	return false
+}
+
+// StateWrites accumulates the writes of the transactions committed to a state
+// since the copies used for speculative execution were taken.
+type StateWrites struct {
+	invalid  bool
+	accounts map[common.Address]*accountWritten
+}
+
+type accountWritten struct {
+	account  bool
+	replaced bool
+	storage  map[string]struct{}
+}
+
+func NewStateWrites() *StateWrites {
+	return &StateWrites{accounts: make(map[common.Address]*accountWritten)}
+}
+
+// Invalidate marks the state as changed in an unknown way, every transaction
+// checked against it afterwards conflicts.
+func (w *StateWrites) Invalidate() {
+	w.invalid = true
+}
+
+func (w *StateWrites) account(addr common.Address) *accountWritten {
+	a, ok := w.accounts[addr]
+	if !ok {
+		a = &accountWritten{storage: make(map[string]struct{})}
+		w.accounts[addr] = a
+	}
+	return a
+}
+
+// StartRWSet makes the state record the accesses of the next transaction.
+func (self *StateDB) StartRWSet() {
+	self.rwSet = newTxRWSet()
+}
+
+// RWSet returns the read and write set of the transaction executed since
+// StartRWSet. It must be called before the state is finalised.
+func (self *StateDB) RWSet() *TxRWSet {
+	rw := self.rwSet
+	if rw == nil {
+		return nil
+	}
+	self.rwSet = nil
+
+	for _, entry := range self.journal.entries {
+		switch ch := entry.(type) {
+		case createObjectChange:
+			if w := rw.write(*ch.account); w.initialBalance == nil {
+				w.initialBalance = new(big.Int)
+			}
+		case resetObjectChange:
+			rw.write(ch.prev.address).replaced = true
+		case suicideChange:
+			rw.write(*ch.account).replaced = true
+		case touchChange:
+			rw.write(*ch.account).replaced = true
+		case nonceChange:
+			rw.write(*ch.account).replaced = true
+		case codeChange:
+			rw.write(*ch.account).replaced = true
+		case balanceChange:
+			if w := rw.write(*ch.account); w.initialBalance == nil {
+				w.initialBalance = new(big.Int).Set(ch.prev)
+			}
+		case storageChange:
+			rw.write(*ch.account).storage[string(ch.key)] = nil
+		case addPreimageChange:
+			rw.preimages[ch.hash] = self.preimages[ch.hash]
+		}
+	}
+	for addr := range rw.migrated {
+		rw.write(addr).replaced = true
+	}
+	for addr, w := range rw.writes {
+		obj := self.stateObjects[addr]
+		if obj == nil {
+			// Only the ripemd touch can leave a dirty address without object.
+			delete(rw.writes, addr)
+			continue
+		}
+		w.object = obj
+		for key := range w.storage {
+			w.storage[key] = obj.dirtyStorage[key]
+		}
+	}
+	return rw
+}
+
+// ApplyRWSet applies the writes of a speculatively executed transaction to the
+// state and adds them to written. The caller must have checked that the
+// transaction does not conflict with written.
+func (self *StateDB) ApplyRWSet(rw *TxRWSet, written *StateWrites) {
+	for addr, w := range rw.writes {
+		a := written.account(addr)
+		if w.replaced {
+			self.stateObjects[addr] = w.object.deepCopy(self)
+			self.journal.dirty(addr)
+			a.account, a.replaced = true, true
+			continue
+		}
+		obj := self.GetOrNewStateObject(addr)
+		if w.initialBalance != nil {
+			obj.AddBalance(new(big.Int).Sub(w.object.Balance(), w.initialBalance))
+			a.account = true
+		}
+		for key, value := range w.storage {
+			obj.SetState(self.db, []byte(key), value)
+			a.storage[key] = struct{}{}
+		}
+	}
+	for hash, preimage := range rw.preimages {
+		self.AddPreimage(hash, preimage)
+	}
+}
+
+func (self *StateDB) recordAccountRead(addr common.Address) {
+	if self.rwSet != nil {
+		self.rwSet.readAccounts[addr] = struct{}{}
+	}
+}
+
+func (self *StateDB) recordStorageRead(addr common.Address, key []byte) {
+	if self.rwSet != nil {
+		keys, ok := self.rwSet.readStorage[addr]
+		if !ok {
+			keys = make(map[string]struct{})
+			self.rwSet.readStorage[addr] = keys
+		}
+		keys[string(key)] = struct{}{}
+	}
+}
+
+func (self *StateDB) recordAllStorageRead(addr common.Address) {
+	if self.rwSet != nil {
+		self.rwSet.readAllStorage[addr] = struct{}{}
+	}
+}
+
+func (self *StateDB) recordMigrate(from, to common.Address) {
+	if self.rwSet != nil {
+		self.rwSet.readAccounts[from] = struct{}{}
+		self.rwSet.readAccounts[to] = struct{}{}
+		self.rwSet.readAllStorage[from] = struct{}{}
+		self.rwSet.migrated[to] = struct{}{}
+	}
+}
@@ -0,0 +1,70 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+)
+
+func TestTxRWSet_Conflicts(t *testing.T) {
+	var (
+		contract = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		coinbase = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		sender   = common.HexToAddress("0x1000000000000000000000000000000000000003")
+	)
+	statedb, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()))
+	statedb.SetBalance(coinbase, big.NewInt(100))
+	statedb.SetBalance(sender, big.NewInt(100))
+	statedb.SetState(contract, []byte("a"), []byte("1"))
+	statedb.Finalise(true)
+
+	// Both transactions pay a fee to the coinbase, the first one writes "a".
+	first := statedb.Copy()
+	first.StartRWSet()
+	first.GetState(contract, []byte("a"))
+	first.SetState(contract, []byte("a"), []byte("2"))
+	first.AddBalance(coinbase, big.NewInt(10))
+	firstRW := first.RWSet()
+
+	second := statedb.Copy()
+	second.StartRWSet()
+	second.GetState(contract, []byte("b"))
+	second.AddBalance(coinbase, big.NewInt(5))
+	secondRW := second.RWSet()
+
+	written := NewStateWrites()
+	assert.False(t, firstRW.Conflicts(written))
+	statedb.ApplyRWSet(firstRW, written)
+	assert.False(t, secondRW.Conflicts(written))
+	statedb.ApplyRWSet(secondRW, written)
+
+	assert.Equal(t, []byte("2"), statedb.GetState(contract, []byte("a")))
+	assert.Equal(t, int64(115), statedb.GetBalance(coinbase).Int64())
+
+	// A transaction that read "a" before the first one wrote it must be executed again.
+	third := statedb.Copy()
+	third.StartRWSet()
+	third.GetState(contract, []byte("a"))
+	thirdRW := third.RWSet()
+	written = NewStateWrites()
+	statedb.ApplyRWSet(firstRW, written)
+	assert.True(t, thirdRW.Conflicts(written))
+
+	// Changing the nonce replaces the account, which conflicts with any other write to it.
+	fourth := statedb.Copy()
+	fourth.StartRWSet()
+	fourth.SetNonce(sender, 1)
+	fourthRW := fourth.RWSet()
+	written = NewStateWrites()
+	assert.False(t, fourthRW.Conflicts(written))
+	written.account(sender).account = true
+	assert.True(t, fourthRW.Conflicts(written))
+
+	written = NewStateWrites()
+	written.Invalidate()
+	assert.True(t, secondRW.Conflicts(written))
+}
@@ -30,20 +30,28 @@ func (self *StateDB) justGetStateObject(addr common.Address) (stateObject *state
 		}
 		return obj
 	}
-	// Load the object from the database.
-	start := time.Now()
-	parallelLocker.Lock()
-	if start.Add(20 * time.Millisecond).Before(time.Now()) {
-		log.Trace("Get parallelLocker overtime", "address", addr.String(), "duration", time.Since(start))
-	}
-	start = time.Now()
-	enc, err := self.trie.TryGet(addr[:])
-	if start.Add(20 * time.Millisecond).Before(time.Now()) {
-		log.Trace("Trie tryGet overtime", "address", addr.String(), "duration", time.Since(start))
+	// Load the object from the snapshot, which is safe for concurrent use.
+	enc, ok := self.snapAccount(addr)
+	if !ok {
+		// Load the object from the database.
+		start := time.Now()
+		parallelLocker.Lock()
+		if start.Add(20 * time.Millisecond).Before(time.Now()) {
+			log.Trace("Get parallelLocker overtime", "address", addr.String(), "duration", time.Since(start))
+		}
+		start = time.Now()
+		var err error
+		enc, err = self.trie.TryGet(addr[:])
+		if start.Add(20 * time.Millisecond).Before(time.Now()) {
+			log.Trace("Trie tryGet overtime", "address", addr.String(), "duration", time.Since(start))
+		}
+		parallelLocker.Unlock()
+		if len(enc) == 0 {
+			self.setError(err)
+			return nil
+		}
 	}
-	parallelLocker.Unlock()
 	if len(enc) == 0 {
-		self.setError(err)
 		return nil
 	}
 	var data Account
@@ -100,6 +108,7 @@ func (self *StateDB) justCreateObject(addr common.Address) *ParallelStateObject
 	//newobj := newObject(self, addr, Account{})
 	newobj := newObject(self, addr, Account{StorageKeyPrefix: addr.Bytes()})
 	//self.journal.append(createObjectChange{account: &addr})
+	newobj.snapReset = true
 	newobj.setNonce(0)
 	return &ParallelStateObject{
 		stateObject: newobj,
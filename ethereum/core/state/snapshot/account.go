@@ -0,0 +1,17 @@
+package snapshot
+
+import (
+	"math/big"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+)
+
+// Account is the consensus representation of an account as stored in the leaves
+// of the account trie. It mirrors state.Account, which cannot be imported here.
+type Account struct {
+	Nonce            uint64
+	Balance          *big.Int
+	Root             common.Hash
+	CodeHash         []byte
+	StorageKeyPrefix []byte
+}
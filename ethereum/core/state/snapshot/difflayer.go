@@ -0,0 +1,246 @@
+package snapshot
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// diffLayer represents a collection of modifications made to a state snapshot
+// after running a block on top. It contains one sorted list for the account trie
+// and one-one list for each storage tries.
+//
+// The goal of a diff layer is to act as a journal, tracking recent modifications
+// made to the state, that have not yet graduated into a semi-immutable state.
+type diffLayer struct {
+	parent snapshot    // Parent snapshot modified by this one, never nil
+	root   common.Hash // Root hash to which this snapshot diff belongs to
+	stale  bool        // Signals that the layer became stale (state progressed)
+
+	destructSet map[common.Hash]struct{}               // Keyed markers for deleted (and potentially) recreated accounts
+	accountList []common.Hash                          // List of account for iteration, sorted
+	accountData map[common.Hash][]byte                 // Keyed accounts for direct retrieval (nil means deleted)
+	storageList map[common.Hash][]common.Hash          // List of storage slots for iterated retrievals, one per account, sorted
+	storageData map[common.Hash]map[common.Hash][]byte // Keyed storage slots for direct retrieval. one per account (nil means deleted)
+
+	lock sync.RWMutex
+}
+
+// hashes is a helper to implement sort.Interface.
+type hashes []common.Hash
+
+func (hs hashes) Len() int           { return len(hs) }
+func (hs hashes) Less(i, j int) bool { return string(hs[i][:]) < string(hs[j][:]) }
+func (hs hashes) Swap(i, j int)      { hs[i], hs[j] = hs[j], hs[i] }
+
+// newDiffLayer creates a new diff on top of an existing snapshot, whether that's
+// a low level persistent database or a hierarchical diff already.
+func newDiffLayer(parent snapshot, root common.Hash, destructs map[common.Hash]struct{}, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) *diffLayer {
+	dl := &diffLayer{
+		parent:      parent,
+		root:        root,
+		destructSet: destructs,
+		accountData: accounts,
+		storageList: make(map[common.Hash][]common.Hash, len(storage)),
+		storageData: storage,
+	}
+	if dl.destructSet == nil {
+		dl.destructSet = make(map[common.Hash]struct{})
+	}
+	if dl.accountData == nil {
+		dl.accountData = make(map[common.Hash][]byte)
+	}
+	if dl.storageData == nil {
+		dl.storageData = make(map[common.Hash]map[common.Hash][]byte)
+	}
+	// Sort the touched accounts and slots for iteration, a destructed account
+	// shows up as a deletion unless it was recreated in the same block.
+	for hash := range dl.accountData {
+		dl.accountList = append(dl.accountList, hash)
+	}
+	for hash := range dl.destructSet {
+		if _, ok := dl.accountData[hash]; !ok {
+			dl.accountList = append(dl.accountList, hash)
+		}
+	}
+	sort.Sort(hashes(dl.accountList))
+
+	for accountHash, slots := range dl.storageData {
+		list := make([]common.Hash, 0, len(slots))
+		for storageHash := range slots {
+			list = append(list, storageHash)
+		}
+		sort.Sort(hashes(list))
+		dl.storageList[accountHash] = list
+	}
+	return dl
+}
+
+// Root returns the root hash for which this snapshot was made.
+func (dl *diffLayer) Root() common.Hash {
+	return dl.root
+}
+
+// Parent returns the subsequent layer of a diff layer.
+func (dl *diffLayer) Parent() snapshot {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	return dl.parent
+}
+
+// Stale return whether this layer has become stale (was flattened across) or if
+// it's still live.
+func (dl *diffLayer) Stale() bool {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	return dl.stale
+}
+
+// origin returns the disk layer this diff layer is built upon.
+func (dl *diffLayer) origin() *diskLayer {
+	var layer snapshot = dl
+	for {
+		switch l := layer.(type) {
+		case *diskLayer:
+			return l
+		case *diffLayer:
+			layer = l.Parent()
+		}
+	}
+}
+
+// Account directly retrieves the account associated with a particular hash in
+// the snapshot.
+func (dl *diffLayer) Account(hash common.Hash) (*Account, error) {
+	data, err := dl.AccountRLP(hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 { // can be both nil and []byte{}
+		return nil, nil
+	}
+	account := new(Account)
+	if err := rlp.DecodeBytes(data, account); err != nil {
+		panic(err)
+	}
+	return account, nil
+}
+
+// AccountRLP directly retrieves the account RLP associated with a particular
+// hash in the snapshot, walking down the parent layers if it was not modified
+// by this one.
+func (dl *diffLayer) AccountRLP(hash common.Hash) ([]byte, error) {
+	dl.lock.RLock()
+	// If the layer was flattened into, consider it invalid (any live reference to
+	// the original should be marked as unusable).
+	if dl.stale {
+		dl.lock.RUnlock()
+		return nil, ErrSnapshotStale
+	}
+	// If the account is known locally, return it
+	if data, ok := dl.accountData[hash]; ok {
+		dl.lock.RUnlock()
+		snapshotDirtyAccountHitMeter.Mark(1)
+		return data, nil
+	}
+	// If the account is known locally, but deleted, return it
+	if _, ok := dl.destructSet[hash]; ok {
+		dl.lock.RUnlock()
+		snapshotDirtyAccountHitMeter.Mark(1)
+		return nil, nil
+	}
+	parent := dl.parent
+	dl.lock.RUnlock()
+
+	// Account unknown to this diff, resolve from parent
+	return parent.AccountRLP(hash)
+}
+
+// Storage directly retrieves the storage data associated with a particular hash,
+// within a particular account, walking down the parent layers if it was not
+// modified by this one.
+func (dl *diffLayer) Storage(accountHash, storageHash common.Hash) ([]byte, error) {
+	dl.lock.RLock()
+	if dl.stale {
+		dl.lock.RUnlock()
+		return nil, ErrSnapshotStale
+	}
+	// If the account is known locally, try to resolve the slot locally
+	if storage, ok := dl.storageData[accountHash]; ok {
+		if data, ok := storage[storageHash]; ok {
+			dl.lock.RUnlock()
+			snapshotDirtyStorageHitMeter.Mark(1)
+			return data, nil
+		}
+	}
+	// If the account is known locally, but deleted, return an empty slot
+	if _, ok := dl.destructSet[accountHash]; ok {
+		dl.lock.RUnlock()
+		snapshotDirtyStorageHitMeter.Mark(1)
+		return nil, nil
+	}
+	parent := dl.parent
+	dl.lock.RUnlock()
+
+	// Storage slot unknown to this diff, resolve from parent
+	return parent.Storage(accountHash, storageHash)
+}
+
+// Update creates a new layer on top of the existing snapshot diff tree with
+// the specified data items.
+func (dl *diffLayer) Update(blockRoot common.Hash, destructs map[common.Hash]struct{}, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) *diffLayer {
+	return newDiffLayer(dl, blockRoot, destructs, accounts, storage)
+}
+
+// AccountIterator creates an account iterator over the merged view of this
+// layer and all the layers below it.
+func (dl *diffLayer) AccountIterator(seek common.Hash) AccountIterator {
+	dl.lock.RLock()
+	parent := dl.parent
+	dl.lock.RUnlock()
+
+	return newBinaryIterator(dl.newDiffAccountIterator(seek), parent.AccountIterator(seek))
+}
+
+// StorageIterator creates a storage iterator over the merged view of this
+// layer and all the layers below it. The storage of a destructed account is
+// not inherited from the parent layers.
+func (dl *diffLayer) StorageIterator(account common.Hash, seek common.Hash) StorageIterator {
+	dl.lock.RLock()
+	parent := dl.parent
+	_, destructed := dl.destructSet[account]
+	dl.lock.RUnlock()
+
+	var below StorageIterator = emptyIterator{}
+	if !destructed {
+		below = parent.StorageIterator(account, seek)
+	}
+	return newBinaryIterator(dl.newDiffStorageIterator(account, seek), below)
+}
+
+// newDiffAccountIterator creates an iterator over the accounts touched by this
+// layer alone, deleted ones included.
+func (dl *diffLayer) newDiffAccountIterator(seek common.Hash) *diffIterator {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	return newDiffIterator(dl, dl.accountList, seek, func(hash common.Hash) []byte {
+		return dl.accountData[hash]
+	})
+}
+
+// newDiffStorageIterator creates an iterator over the slots of an account
+// touched by this layer alone, deleted ones included.
+func (dl *diffLayer) newDiffStorageIterator(account common.Hash, seek common.Hash) *diffIterator {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	storage := dl.storageData[account]
+	return newDiffIterator(dl, dl.storageList[account], seek, func(hash common.Hash) []byte {
+		return storage[hash]
+	})
+}
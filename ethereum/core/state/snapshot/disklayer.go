@@ -0,0 +1,275 @@
+package snapshot
+
+import (
+	"sync"
+
+	"github.com/VictoriaMetrics/fastcache"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/trie"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// diskLayer is a low level persistent snapshot built on top of a key-value store.
+type diskLayer struct {
+	diskdb ethdb.KeyValueStore // Key-value store containing the base snapshot
+	triedb *trie.Database      // Trie node cache for reconstruction purposes
+	cache  *fastcache.Cache    // Cache to avoid hitting the disk for direct access
+
+	root  common.Hash // Root hash of the base snapshot
+	stale bool        // Signals that the layer became stale (state progressed)
+
+	genMarker  []byte             // Marker for the state that's indexed during initial layer generation
+	genWiping  bool               // Whether the leftovers of a previous snapshot are still being wiped
+	genPending chan struct{}      // Notification channel when generation is done (test synchronicity)
+	genAbort   chan chan struct{} // Notification channel to abort generating the snapshot in this layer
+
+	lock sync.RWMutex
+}
+
+// Root returns  root hash for which this snapshot was made.
+func (dl *diskLayer) Root() common.Hash {
+	return dl.root
+}
+
+// Parent always returns nil as there's no layer below the disk.
+func (dl *diskLayer) Parent() snapshot {
+	return nil
+}
+
+// Stale return whether this layer has become stale (was flattened across) or if
+// it's still live.
+func (dl *diskLayer) Stale() bool {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	return dl.stale
+}
+
+// Account directly retrieves the account associated with a particular hash in
+// the snapshot.
+func (dl *diskLayer) Account(hash common.Hash) (*Account, error) {
+	data, err := dl.AccountRLP(hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 { // can be both nil and []byte{}
+		return nil, nil
+	}
+	account := new(Account)
+	if err := rlp.DecodeBytes(data, account); err != nil {
+		panic(err)
+	}
+	return account, nil
+}
+
+// AccountRLP directly retrieves the account RLP associated with a particular
+// hash in the snapshot.
+func (dl *diskLayer) AccountRLP(hash common.Hash) ([]byte, error) {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	// If the layer was flattened into, consider it invalid (any live reference to
+	// the original should be marked as unusable).
+	if dl.stale {
+		return nil, ErrSnapshotStale
+	}
+	// If the layer is being generated, ensure the requested hash has already been
+	// covered by the generator.
+	if !covered(hash[:], dl.genMarker) {
+		return nil, ErrNotCoveredYet
+	}
+	// Try to retrieve the account from the memory cache
+	if blob, found := dl.cache.HasGet(nil, hash[:]); found {
+		snapshotCleanAccountHitMeter.Mark(1)
+		snapshotCleanAccountReadMeter.Mark(int64(len(blob)))
+		return blob, nil
+	}
+	// Cache doesn't contain account, pull from disk and cache for later
+	blob := rawdb.ReadAccountSnapshot(dl.diskdb, hash)
+	dl.cache.Set(hash[:], blob)
+
+	snapshotCleanAccountMissMeter.Mark(1)
+	snapshotCleanAccountReadMeter.Mark(int64(len(blob)))
+	return blob, nil
+}
+
+// Storage directly retrieves the storage data associated with a particular hash,
+// within a particular account.
+func (dl *diskLayer) Storage(accountHash, storageHash common.Hash) ([]byte, error) {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	// If the layer was flattened into, consider it invalid (any live reference to
+	// the original should be marked as unusable).
+	if dl.stale {
+		return nil, ErrSnapshotStale
+	}
+	key := append(accountHash[:], storageHash[:]...)
+
+	// If the layer is being generated, ensure the requested hash has already been
+	// covered by the generator.
+	if !covered(key, dl.genMarker) {
+		return nil, ErrNotCoveredYet
+	}
+	// Try to retrieve the storage slot from the memory cache
+	if blob, found := dl.cache.HasGet(nil, key); found {
+		snapshotCleanStorageHitMeter.Mark(1)
+		snapshotCleanStorageReadMeter.Mark(int64(len(blob)))
+		return blob, nil
+	}
+	// Cache doesn't contain storage slot, pull from disk and cache for later
+	blob := rawdb.ReadStorageSnapshot(dl.diskdb, accountHash, storageHash)
+	dl.cache.Set(key, blob)
+
+	snapshotCleanStorageMissMeter.Mark(1)
+	snapshotCleanStorageReadMeter.Mark(int64(len(blob)))
+	return blob, nil
+}
+
+// Update creates a new layer on top of the existing snapshot diff tree with
+// the specified data items. Note, the maps are retained by the method to avoid
+// copying everything.
+func (dl *diskLayer) Update(blockRoot common.Hash, destructs map[common.Hash]struct{}, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) *diffLayer {
+	return newDiffLayer(dl, blockRoot, destructs, accounts, storage)
+}
+
+// AccountIterator creates an account iterator over the persisted accounts.
+func (dl *diskLayer) AccountIterator(seek common.Hash) AccountIterator {
+	return newDiskIterator(dl, rawdb.SnapshotAccountPrefix, seek)
+}
+
+// StorageIterator creates a storage iterator over the persisted slots of an
+// account.
+func (dl *diskLayer) StorageIterator(account common.Hash, seek common.Hash) StorageIterator {
+	return newDiskIterator(dl, append(rawdb.SnapshotStoragePrefix, account[:]...), seek)
+}
+
+// abortGeneration stops the background generator of the layer, if any, and
+// waits for it to persist its progress.
+func (dl *diskLayer) abortGeneration() {
+	if dl.genAbort == nil {
+		return
+	}
+	stop := make(chan struct{})
+	dl.genAbort <- stop
+	<-stop
+	dl.genAbort = nil
+}
+
+// diffToDisk merges a bottom-most diff into the persistent disk layer underneath
+// it. The method will panic if called onto a non-bottom-most diff layer.
+func diffToDisk(bottom *diffLayer) *diskLayer {
+	var (
+		base  = bottom.parent.(*diskLayer)
+		batch = base.diskdb.NewBatch()
+	)
+	// Stop the generator while the disk is updated, it's resumed on the new root
+	base.abortGeneration()
+
+	// Mark the original base as stale as we're going to create a new wrapper
+	base.lock.Lock()
+	if base.stale {
+		panic("parent disk layer is stale") // we've committed into the same base from two children, boo
+	}
+	base.stale = true
+	marker, wiping := base.genMarker, base.genWiping
+	base.lock.Unlock()
+
+	// Destroy all the destructed accounts from the database, only the range
+	// already covered by the generator is updated, the rest is picked up when
+	// generation resumes.
+	rawdb.DeleteSnapshotRoot(batch)
+	for hash := range bottom.destructSet {
+		if !covered(hash[:], marker) {
+			continue
+		}
+		rawdb.DeleteAccountSnapshot(batch, hash)
+		base.cache.Set(hash[:], nil)
+
+		it := rawdb.IterateStorageSnapshots(base.diskdb, hash)
+		for it.Next() {
+			key := it.Key()
+			if len(key) != len(rawdb.SnapshotStoragePrefix)+2*common.HashLength {
+				continue
+			}
+			if !covered(key[len(rawdb.SnapshotStoragePrefix):], marker) {
+				break
+			}
+			batch.Delete(common.CopyBytes(key))
+			base.cache.Del(key[len(rawdb.SnapshotStoragePrefix):])
+			snapshotFlushStorageItemMeter.Mark(1)
+
+			if batch.ValueSize() > ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					log.Crit("Failed to write storage deletions", "err", err)
+				}
+				batch.Reset()
+			}
+		}
+		it.Release()
+	}
+	// Push all updated accounts into the database
+	for hash, data := range bottom.accountData {
+		if !covered(hash[:], marker) {
+			continue
+		}
+		if len(data) > 0 {
+			rawdb.WriteAccountSnapshot(batch, hash, data)
+		} else {
+			rawdb.DeleteAccountSnapshot(batch, hash)
+		}
+		base.cache.Set(hash[:], data)
+		snapshotFlushAccountItemMeter.Mark(1)
+	}
+	// Push all the storage slots into the database
+	for accountHash, storage := range bottom.storageData {
+		if !covered(accountHash[:], marker) {
+			continue
+		}
+		for storageHash, data := range storage {
+			key := append(accountHash[:], storageHash[:]...)
+			if !covered(key, marker) {
+				continue
+			}
+			if len(data) > 0 {
+				rawdb.WriteStorageSnapshot(batch, accountHash, storageHash, data)
+			} else {
+				rawdb.DeleteStorageSnapshot(batch, accountHash, storageHash)
+			}
+			base.cache.Set(key, data)
+			snapshotFlushStorageItemMeter.Mark(1)
+		}
+	}
+	// Update the snapshot block marker and write any remainder data
+	rawdb.WriteSnapshotRoot(batch, bottom.root)
+	if marker != nil {
+		journalProgress(batch, marker, wiping)
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write leftover snapshot", "err", err)
+	}
+	res := &diskLayer{
+		root:       bottom.root,
+		cache:      base.cache,
+		diskdb:     base.diskdb,
+		triedb:     base.triedb,
+		genMarker:  marker,
+		genWiping:  wiping,
+		genPending: base.genPending,
+	}
+	bottom.lock.Lock()
+	bottom.stale = true
+	bottom.lock.Unlock()
+
+	// If snapshot generation hasn't finished yet, continue where the previous
+	// round left off.
+	if marker != nil {
+		res.genAbort = make(chan chan struct{})
+		go res.generate(res.genAbort)
+	}
+	return res
+}
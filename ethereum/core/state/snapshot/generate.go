@@ -0,0 +1,282 @@
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/VictoriaMetrics/fastcache"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/trie"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+var (
+	// emptyRoot is the known root hash of an empty trie.
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+	// errMissingGenerator is returned if the progress of the generation was not
+	// persisted along with the snapshot.
+	errMissingGenerator = errors.New("missing snapshot generator")
+)
+
+// generatorStatus is the progress of the snapshot generation, persisted along
+// with the disk layer so an interrupted generation can be resumed.
+type generatorStatus struct {
+	Done   bool   // Whether the generator finished creating the snapshot
+	Wiping bool   // Whether the leftovers of a previous snapshot are being wiped
+	Marker []byte // Last key covered by the generator
+}
+
+// loadGenerator retrieves the persisted progress of the snapshot generation.
+func loadGenerator(db ethdb.KeyValueReader) (*generatorStatus, error) {
+	blob := rawdb.ReadSnapshotGenerator(db)
+	if len(blob) == 0 {
+		return nil, errMissingGenerator
+	}
+	var generator generatorStatus
+	if err := rlp.DecodeBytes(blob, &generator); err != nil {
+		return nil, err
+	}
+	return &generator, nil
+}
+
+// journalProgress persists the generator stats into the database to resume later.
+func journalProgress(db ethdb.KeyValueWriter, marker []byte, wiping bool) {
+	entry := generatorStatus{
+		Done:   marker == nil,
+		Wiping: wiping,
+		Marker: marker,
+	}
+	blob, err := rlp.EncodeToBytes(entry)
+	if err != nil {
+		panic(err) // Cannot happen, here to catch dev errors
+	}
+	rawdb.WriteSnapshotGenerator(db, blob)
+}
+
+// generateSnapshot regenerates a brand new snapshot based on an existing state
+// database and head block asynchronously. The snapshot is returned immediately
+// and generation is continued in the background until done.
+func generateSnapshot(diskdb ethdb.KeyValueStore, triedb *trie.Database, cache int, root common.Hash) *diskLayer {
+	// Create a new disk layer with an initialized state marker at zero
+	batch := diskdb.NewBatch()
+	rawdb.WriteSnapshotRoot(batch, root)
+	journalProgress(batch, []byte{}, true)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write initialized state marker", "err", err)
+	}
+	base := &diskLayer{
+		diskdb:     diskdb,
+		triedb:     triedb,
+		root:       root,
+		cache:      fastcache.New(cache * 1024 * 1024),
+		genMarker:  []byte{}, // Initialized but empty!
+		genWiping:  true,
+		genPending: make(chan struct{}),
+		genAbort:   make(chan chan struct{}),
+	}
+	go base.generate(base.genAbort)
+	return base
+}
+
+// generate is a background thread that iterates over the state and storage tries
+// and constructs a state snapshot. The method surfs the blocks as they arrive,
+// being aborted and restarted on the new root whenever a diff layer is flattened
+// into the disk layer.
+func (dl *diskLayer) generate(abort chan chan struct{}) {
+	snapshotGenerationRunningGauge.Update(1)
+	defer snapshotGenerationRunningGauge.Update(0)
+
+	dl.lock.RLock()
+	marker, wiping := dl.genMarker, dl.genWiping
+	dl.lock.RUnlock()
+
+	// Delete the leftovers of any previous snapshot first, nothing is covered
+	// until they are gone.
+	if wiping {
+		if stop := dl.wipe(abort); stop != nil {
+			close(stop)
+			return
+		}
+		dl.lock.Lock()
+		dl.genWiping = false
+		dl.lock.Unlock()
+	}
+	log.Info("Generating state snapshot", "root", dl.root, "at", common.BytesToHash(marker))
+
+	var (
+		accounts uint64
+		slots    uint64
+		start    = time.Now()
+		logged   = time.Now()
+		batch    = dl.diskdb.NewBatch()
+	)
+	// checkAndFlush persists the batch once it grows large or an abort was
+	// requested, advancing the marker of the covered range. True is returned if
+	// the generator needs to stop.
+	checkAndFlush := func(current []byte) bool {
+		var stop chan struct{}
+		select {
+		case stop = <-abort:
+		default:
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize || stop != nil {
+			journalProgress(batch, current, false)
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to write snapshot generation batch", "err", err)
+			}
+			batch.Reset()
+
+			dl.lock.Lock()
+			dl.genMarker = current
+			dl.lock.Unlock()
+
+			if stop != nil {
+				log.Debug("Aborting state snapshot generation", "root", dl.root, "at", common.BytesToHash(current), "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+				close(stop)
+				return true
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Generating state snapshot", "root", dl.root, "at", common.BytesToHash(current), "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		return false
+	}
+	// Create an account and state iterator pointing to the current generator marker
+	accTrie, err := trie.New(dl.root, dl.triedb)
+	if err != nil {
+		// The account trie is missing (GC), surf the chain until one becomes available
+		log.Warn("Generator failed to access account trie", "root", dl.root, "err", err)
+		dl.waitAbort(abort)
+		return
+	}
+	var accMarker []byte
+	if len(marker) > 0 { // []byte{} is the start, use nil for that
+		accMarker = marker[:common.HashLength]
+	}
+	accIt := trie.NewIterator(accTrie.NodeIterator(accMarker))
+	for accIt.Next() {
+		// Retrieve the current account and flatten it into the internal format
+		accountHash := common.BytesToHash(accIt.Key)
+
+		var acc Account
+		if err := rlp.DecodeBytes(accIt.Value, &acc); err != nil {
+			log.Crit("Invalid account encountered during snapshot creation", "err", err)
+		}
+		// If the account is not yet in-progress, write it out
+		if accMarker == nil || !bytes.Equal(accountHash[:], accMarker) {
+			rawdb.WriteAccountSnapshot(batch, accountHash, accIt.Value)
+			snapshotGeneratedAccountMeter.Mark(1)
+			accounts++
+
+			if checkAndFlush(accountHash[:]) {
+				return
+			}
+		}
+		// If the iterated account is a contract, iterate through corresponding
+		// storage trie, resuming from the slot the marker points to
+		if !isEmptyRoot(acc.Root) {
+			var storeMarker []byte
+			if accMarker != nil && bytes.Equal(accountHash[:], accMarker) && len(marker) > common.HashLength {
+				storeMarker = marker[common.HashLength:]
+			}
+			storeTrie, err := trie.New(acc.Root, dl.triedb)
+			if err != nil {
+				log.Warn("Generator failed to access storage trie", "root", dl.root, "account", accountHash, "stroot", acc.Root, "err", err)
+				dl.waitAbort(abort)
+				return
+			}
+			storeIt := trie.NewIterator(storeTrie.NodeIterator(storeMarker))
+			for storeIt.Next() {
+				rawdb.WriteStorageSnapshot(batch, accountHash, common.BytesToHash(storeIt.Key), storeIt.Value)
+				snapshotGeneratedStorageMeter.Mark(1)
+				slots++
+
+				if checkAndFlush(append(accountHash[:], storeIt.Key...)) {
+					return
+				}
+			}
+			if storeIt.Err != nil {
+				log.Warn("Generator failed to iterate storage trie", "root", dl.root, "account", accountHash, "stroot", acc.Root, "err", storeIt.Err)
+				dl.waitAbort(abort)
+				return
+			}
+		}
+		accMarker = nil
+	}
+	if accIt.Err != nil {
+		log.Warn("Generator failed to iterate account trie", "root", dl.root, "err", accIt.Err)
+		dl.waitAbort(abort)
+		return
+	}
+	// Snapshot fully generated, set the marker to nil
+	journalProgress(batch, nil, false)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write snapshot generation batch", "err", err)
+	}
+	log.Info("Generated state snapshot", "root", dl.root, "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	dl.lock.Lock()
+	dl.genMarker = nil
+	close(dl.genPending)
+	dl.lock.Unlock()
+
+	// Someone will be looking for us, wait it out
+	dl.waitAbort(abort)
+}
+
+// waitAbort blocks until the generator is asked to stop, acknowledging it.
+func (dl *diskLayer) waitAbort(abort chan chan struct{}) {
+	stop := <-abort
+	close(stop)
+}
+
+// wipe deletes all the snapshot entries left on disk by a previous snapshot.
+// If the wiping is interrupted, the abort request is returned unacknowledged.
+func (dl *diskLayer) wipe(abort chan chan struct{}) chan struct{} {
+	start := time.Now()
+	for _, kind := range []struct {
+		prefix []byte
+		keylen int
+	}{
+		{rawdb.SnapshotAccountPrefix, len(rawdb.SnapshotAccountPrefix) + common.HashLength},
+		{rawdb.SnapshotStoragePrefix, len(rawdb.SnapshotStoragePrefix) + 2*common.HashLength},
+	} {
+		batch := dl.diskdb.NewBatch()
+		it := dl.diskdb.NewIteratorWithPrefix(kind.prefix)
+		for it.Next() {
+			// Skip any keys with the correct prefix but wrong length (trie nodes)
+			key := it.Key()
+			if len(key) != kind.keylen {
+				continue
+			}
+			batch.Delete(common.CopyBytes(key))
+			if batch.ValueSize() > ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					log.Crit("Failed to wipe state snapshot", "err", err)
+				}
+				batch.Reset()
+
+				select {
+				case stop := <-abort:
+					it.Release()
+					return stop
+				default:
+				}
+			}
+		}
+		it.Release()
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed to wipe state snapshot", "err", err)
+		}
+	}
+	journalProgress(dl.diskdb, []byte{}, false)
+	log.Info("Wiped previous state snapshot", "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
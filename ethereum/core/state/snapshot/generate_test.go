@@ -0,0 +1,199 @@
+package snapshot
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/VictoriaMetrics/fastcache"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/trie"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// testState is a small account trie with a few contracts, along with the
+// flat entries a snapshot of it is expected to hold.
+type testState struct {
+	db       ethdb.KeyValueStore
+	triedb   *trie.Database
+	root     common.Hash
+	accounts map[common.Hash][]byte
+	storage  map[common.Hash]map[common.Hash][]byte
+}
+
+// newTestState creates an account trie where every other account owns a
+// storage trie with a handful of slots.
+func newTestState(t *testing.T, n int) *testState {
+	db := rawdb.NewMemoryDatabase()
+	s := &testState{
+		db:       db,
+		triedb:   trie.NewDatabase(db),
+		accounts: make(map[common.Hash][]byte),
+		storage:  make(map[common.Hash]map[common.Hash][]byte),
+	}
+	accTrie, _ := trie.NewSecure(common.Hash{}, s.triedb)
+	for i := 0; i < n; i++ {
+		addr := common.BigToAddress(big.NewInt(int64(i + 1)))
+		addrHash := crypto.Keccak256Hash(addr[:])
+
+		acc := Account{
+			Nonce:            uint64(i),
+			Balance:          big.NewInt(int64(i) * 1000),
+			Root:             emptyRoot,
+			CodeHash:         emptyCode[:],
+			StorageKeyPrefix: addr[:],
+		}
+		if i%2 == 1 {
+			stTrie, _ := trie.NewSecure(common.Hash{}, s.triedb)
+			slots := make(map[common.Hash][]byte)
+			for j := 0; j < 5; j++ {
+				key := []byte{byte(i), byte(j)}
+				value, _ := rlp.EncodeToBytes([]byte{byte(j + 1)})
+				stTrie.Update(key, value)
+				slots[crypto.Keccak256Hash(key)] = value
+			}
+			root, err := stTrie.Commit(nil)
+			if err != nil {
+				t.Fatalf("failed to commit storage trie: %v", err)
+			}
+			acc.Root = root
+			s.storage[addrHash] = slots
+		}
+		data, _ := rlp.EncodeToBytes(acc)
+		accTrie.Update(addr[:], data)
+		s.accounts[addrHash] = data
+	}
+	root, err := accTrie.Commit(nil)
+	if err != nil {
+		t.Fatalf("failed to commit account trie: %v", err)
+	}
+	s.root = root
+	return s
+}
+
+// verify checks that the disk holds exactly the flat entries of the state.
+func (s *testState) verify(t *testing.T) {
+	if root := rawdb.ReadSnapshotRoot(s.db); root != s.root {
+		t.Errorf("snapshot root mismatch: have %x, want %x", root, s.root)
+	}
+	generator, err := loadGenerator(s.db)
+	if err != nil || !generator.Done {
+		t.Errorf("snapshot generation not finished: %v", err)
+	}
+	for hash, want := range s.accounts {
+		if blob := rawdb.ReadAccountSnapshot(s.db, hash); string(blob) != string(want) {
+			t.Errorf("account %x: blob mismatch: have %x, want %x", hash, blob, want)
+		}
+		slots := 0
+		it := rawdb.IterateStorageSnapshots(s.db, hash)
+		for it.Next() {
+			slots++
+		}
+		it.Release()
+		if slots != len(s.storage[hash]) {
+			t.Errorf("account %x: slot count mismatch: have %d, want %d", hash, slots, len(s.storage[hash]))
+		}
+		for storageHash, want := range s.storage[hash] {
+			if blob := rawdb.ReadStorageSnapshot(s.db, hash, storageHash); string(blob) != string(want) {
+				t.Errorf("slot %x/%x: blob mismatch: have %x, want %x", hash, storageHash, blob, want)
+			}
+		}
+	}
+}
+
+// Tests that a snapshot generated from scratch matches the state trie, leftovers
+// of a previous snapshot included.
+func TestGenerateSnapshot(t *testing.T) {
+	s := newTestState(t, 16)
+
+	stale := randomHash()
+	rawdb.WriteAccountSnapshot(s.db, stale, randomAccount())
+	rawdb.WriteStorageSnapshot(s.db, stale, randomHash(), []byte{0x01})
+
+	snaps := New(s.db, s.triedb, 16, s.root, false)
+	defer snaps.Release()
+
+	if snaps.Generating() {
+		t.Fatalf("snapshot still generating")
+	}
+	s.verify(t)
+	if blob := rawdb.ReadAccountSnapshot(s.db, stale); len(blob) != 0 {
+		t.Errorf("stale account retained: %x", blob)
+	}
+	it := rawdb.IterateStorageSnapshots(s.db, stale)
+	defer it.Release()
+	if it.Next() {
+		t.Errorf("stale storage retained: %x", it.Key())
+	}
+}
+
+// Tests that an interrupted generation is resumed from the persisted marker on
+// the next start, instead of starting over.
+func TestGenerateSnapshotResume(t *testing.T) {
+	s := newTestState(t, 16)
+
+	// Fake an interrupted generation that got halfway through a contract
+	var contract common.Hash
+	for hash := range s.storage {
+		contract = hash
+		break
+	}
+	var storageHash common.Hash
+	for hash := range s.storage[contract] {
+		storageHash = hash
+		break
+	}
+	for hash, data := range s.accounts {
+		if string(hash[:]) <= string(contract[:]) {
+			rawdb.WriteAccountSnapshot(s.db, hash, data)
+		}
+		for key, value := range s.storage[hash] {
+			if string(hash[:]) < string(contract[:]) || (hash == contract && string(key[:]) < string(storageHash[:])) {
+				rawdb.WriteStorageSnapshot(s.db, hash, key, value)
+			}
+		}
+	}
+	rawdb.WriteSnapshotRoot(s.db, s.root)
+	journalProgress(s.db, append(contract[:], storageHash[:]...), false)
+
+	snaps := New(s.db, s.triedb, 16, s.root, false)
+	defer snaps.Release()
+
+	s.verify(t)
+}
+
+// Tests that reads beyond the range covered by the generator are rejected, so
+// they can be served by the trie instead.
+func TestGeneratingReads(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		marker  = common.HexToHash("0x80")
+		covered = common.HexToHash("0x10")
+		pending = common.HexToHash("0xf0")
+	)
+	rawdb.WriteAccountSnapshot(db, covered, []byte{0x01})
+	rawdb.WriteStorageSnapshot(db, covered, marker, []byte{0x02})
+
+	dl := &diskLayer{
+		diskdb:    db,
+		triedb:    trie.NewDatabase(db),
+		cache:     fastcache.New(1024 * 1024),
+		root:      randomHash(),
+		genMarker: marker[:],
+	}
+	if blob, err := dl.AccountRLP(covered); err != nil || string(blob) != string([]byte{0x01}) {
+		t.Errorf("covered account: have %x (%v), want %x", blob, err, []byte{0x01})
+	}
+	if blob, err := dl.Storage(covered, marker); err != nil || string(blob) != string([]byte{0x02}) {
+		t.Errorf("covered slot: have %x (%v), want %x", blob, err, []byte{0x02})
+	}
+	if _, err := dl.AccountRLP(pending); err != ErrNotCoveredYet {
+		t.Errorf("pending account: have %v, want %v", err, ErrNotCoveredYet)
+	}
+	if _, err := dl.Storage(pending, covered); err != ErrNotCoveredYet {
+		t.Errorf("pending slot: have %v, want %v", err, ErrNotCoveredYet)
+	}
+}
@@ -0,0 +1,344 @@
+package snapshot
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
+)
+
+// Iterator is an iterator to step over all the accounts or the specific
+// storage in a snapshot which may or may not be composed of multiple layers.
+type Iterator interface {
+	// Next steps the iterator forward one element, returning false if exhausted,
+	// or an error if iteration failed for some reason (e.g. root being iterated
+	// becomes stale and garbage collected).
+	Next() bool
+
+	// Error returns any failure that occurred during iteration, which might have
+	// caused a premature iteration exit (e.g. snapshot stack becoming stale).
+	Error() error
+
+	// Hash returns the hash of the account or storage slot the iterator is
+	// currently at.
+	Hash() common.Hash
+
+	// Release releases associated resources. Release should always succeed and
+	// can be called multiple times without causing error.
+	Release()
+}
+
+// AccountIterator is an iterator to step over all the accounts in a snapshot,
+// which may or may not be composed of multiple layers.
+type AccountIterator interface {
+	Iterator
+
+	// Account returns the RLP encoded account the iterator is currently at.
+	// An error will be returned if the iterator becomes invalid.
+	Account() []byte
+}
+
+// StorageIterator is an iterator to step over the specific storage in a snapshot,
+// which may or may not be composed of multiple layers.
+type StorageIterator interface {
+	Iterator
+
+	// Slot returns the storage slot the iterator is currently at. An error will
+	// be returned if the iterator becomes invalid.
+	Slot() []byte
+}
+
+// diffIterator is an account or storage iterator over the entries of a single
+// diff layer. Deleted entries are yielded with a nil value, so they can shadow
+// the layers below.
+type diffIterator struct {
+	layer *diffLayer
+	keys  []common.Hash
+	value func(hash common.Hash) []byte
+	curr  common.Hash
+	fail  error
+}
+
+// newDiffIterator creates an iterator over the sorted keys of a diff layer,
+// positioned right before the first key not smaller than seek.
+func newDiffIterator(layer *diffLayer, keys []common.Hash, seek common.Hash, value func(hash common.Hash) []byte) *diffIterator {
+	index := sort.Search(len(keys), func(i int) bool {
+		return bytes.Compare(seek[:], keys[i][:]) <= 0
+	})
+	return &diffIterator{
+		layer: layer,
+		keys:  keys[index:],
+		value: value,
+	}
+}
+
+// Next steps the iterator forward one element, returning false if exhausted.
+func (it *diffIterator) Next() bool {
+	// If the iterator was already stale, consider it a programmer error. Although
+	// we could just return false here, triggering this path would probably mean
+	// somebody forgot to check for Error, so lets blow up instead of undefined
+	// behavior that's hard to debug.
+	if it.fail != nil {
+		panic("snapshot iterator stale")
+	}
+	if len(it.keys) == 0 {
+		return false
+	}
+	if it.layer.Stale() {
+		it.fail, it.keys = ErrSnapshotStale, nil
+		return false
+	}
+	it.curr, it.keys = it.keys[0], it.keys[1:]
+	return true
+}
+
+// Error returns any failure that occurred during iteration.
+func (it *diffIterator) Error() error {
+	return it.fail
+}
+
+// Hash returns the hash of the entry the iterator is currently at.
+func (it *diffIterator) Hash() common.Hash {
+	return it.curr
+}
+
+// Account returns the RLP encoded account the iterator is currently at, nil
+// meaning the account was deleted in this layer.
+func (it *diffIterator) Account() []byte {
+	return it.entry()
+}
+
+// Slot returns the storage slot the iterator is currently at, nil meaning the
+// slot was deleted in this layer.
+func (it *diffIterator) Slot() []byte {
+	return it.entry()
+}
+
+// entry retrieves the value of the current key from the layer.
+func (it *diffIterator) entry() []byte {
+	it.layer.lock.RLock()
+	defer it.layer.lock.RUnlock()
+
+	if it.layer.stale {
+		it.fail, it.keys = ErrSnapshotStale, nil
+	}
+	return it.value(it.curr)
+}
+
+// Release is a noop for diff iterators as there are no held resources.
+func (it *diffIterator) Release() {}
+
+// diskIterator is an account or storage iterator over the persisted entries
+// of the disk layer sharing a key prefix.
+type diskIterator struct {
+	layer  *diskLayer
+	prefix []byte
+	it     ethdb.Iterator
+	fail   error
+}
+
+// newDiskIterator creates an iterator over the disk entries under the given
+// prefix, starting at the seek position.
+func newDiskIterator(layer *diskLayer, prefix []byte, seek common.Hash) *diskIterator {
+	return &diskIterator{
+		layer:  layer,
+		prefix: prefix,
+		it:     layer.diskdb.NewIteratorWithStart(append(common.CopyBytes(prefix), seek[:]...)),
+	}
+}
+
+// Next steps the iterator forward one element, returning false if exhausted.
+func (it *diskIterator) Next() bool {
+	// If the iterator was already exhausted, don't bother
+	if it.it == nil {
+		return false
+	}
+	// The disk content is rewritten when a diff is flattened into it
+	if it.layer.Stale() {
+		it.fail = ErrSnapshotStale
+		it.Release()
+		return false
+	}
+	// Try to advance the iterator and release it if we reached the end
+	for {
+		if !it.it.Next() {
+			it.it.Release()
+			it.it = nil
+			return false
+		}
+		key := it.it.Key()
+		if !bytes.HasPrefix(key, it.prefix) {
+			it.it.Release()
+			it.it = nil
+			return false
+		}
+		if len(key) == len(it.prefix)+common.HashLength {
+			break
+		}
+	}
+	return true
+}
+
+// Error returns any failure that occurred during iteration, which might have
+// caused a premature iteration exit (e.g. snapshot stack becoming stale).
+func (it *diskIterator) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	if it.it == nil {
+		return nil // Iterator is exhausted and released
+	}
+	return it.it.Error()
+}
+
+// Hash returns the hash of the entry the iterator is currently at.
+func (it *diskIterator) Hash() common.Hash {
+	return common.BytesToHash(it.it.Key()[len(it.prefix):])
+}
+
+// Account returns the RLP encoded account the iterator is currently at.
+func (it *diskIterator) Account() []byte {
+	return common.CopyBytes(it.it.Value())
+}
+
+// Slot returns the storage slot the iterator is currently at.
+func (it *diskIterator) Slot() []byte {
+	return common.CopyBytes(it.it.Value())
+}
+
+// Release releases the database snapshot held during iteration.
+func (it *diskIterator) Release() {
+	// The iterator is auto-released on exhaustion, so make sure it's still alive
+	if it.it != nil {
+		it.it.Release()
+		it.it = nil
+	}
+}
+
+// layerIterator is the union of the account and storage iterators, implemented
+// by all the layer level iterators.
+type layerIterator interface {
+	Iterator
+	Account() []byte
+	Slot() []byte
+}
+
+// binaryIterator steps over the accounts or storage of a snapshot composed of
+// multiple layers, by merging the iterator of the top layer with the merged
+// iterator of the layers below it.
+type binaryIterator struct {
+	a     layerIterator
+	b     layerIterator
+	aDone bool
+	bDone bool
+	k     common.Hash
+	v     []byte
+	fail  error
+}
+
+// newBinaryIterator creates an iterator merging the entries of a layer with
+// the merged entries of the layers below it, the top layer taking precedence.
+func newBinaryIterator(a *diffIterator, b Iterator) *binaryIterator {
+	l := &binaryIterator{
+		a: a,
+		b: b.(layerIterator),
+	}
+	l.aDone = !l.a.Next()
+	l.bDone = !l.b.Next()
+	return l
+}
+
+// Next steps the iterator forward one element, returning false if exhausted,
+// or an error if iteration failed for some reason (e.g. root being iterated
+// becomes stale and garbage collected).
+func (it *binaryIterator) Next() bool {
+	for {
+		if it.aDone && it.bDone {
+			it.fail = it.firstError()
+			return false
+		}
+		var (
+			hash  common.Hash
+			value []byte
+		)
+		switch {
+		case it.aDone:
+			hash, value = it.b.Hash(), it.b.Account()
+			it.bDone = !it.b.Next()
+		case it.bDone:
+			hash, value = it.a.Hash(), it.a.Account()
+			it.aDone = !it.a.Next()
+		default:
+			switch bytes.Compare(it.a.Hash().Bytes(), it.b.Hash().Bytes()) {
+			case -1:
+				hash, value = it.a.Hash(), it.a.Account()
+				it.aDone = !it.a.Next()
+			case 1:
+				hash, value = it.b.Hash(), it.b.Account()
+				it.bDone = !it.b.Next()
+			default:
+				// The upper layer shadows the entry of the lower one
+				hash, value = it.a.Hash(), it.a.Account()
+				it.aDone = !it.a.Next()
+				it.bDone = !it.b.Next()
+			}
+		}
+		if err := it.firstError(); err != nil {
+			it.fail = err
+			return false
+		}
+		// Deleted entries are not yielded, but they do shadow the lower layers
+		if len(value) == 0 {
+			continue
+		}
+		it.k, it.v = hash, value
+		return true
+	}
+}
+
+// firstError returns the failure of either merged iterator.
+func (it *binaryIterator) firstError() error {
+	if err := it.a.Error(); err != nil {
+		return err
+	}
+	return it.b.Error()
+}
+
+// Error returns any failure that occurred during iteration, which might have
+// caused a premature iteration exit (e.g. snapshot stack becoming stale).
+func (it *binaryIterator) Error() error {
+	return it.fail
+}
+
+// Hash returns the hash of the account or slot the iterator is currently at.
+func (it *binaryIterator) Hash() common.Hash {
+	return it.k
+}
+
+// Account returns the RLP encoded account the iterator is currently at.
+func (it *binaryIterator) Account() []byte {
+	return it.v
+}
+
+// Slot returns the storage slot the iterator is currently at.
+func (it *binaryIterator) Slot() []byte {
+	return it.v
+}
+
+// Release recursively releases all the iterators in the stack.
+func (it *binaryIterator) Release() {
+	it.a.Release()
+	it.b.Release()
+}
+
+// emptyIterator is an iterator without any entries, standing in for the
+// storage below a destructed account.
+type emptyIterator struct{}
+
+func (emptyIterator) Next() bool        { return false }
+func (emptyIterator) Error() error      { return nil }
+func (emptyIterator) Hash() common.Hash { return common.Hash{} }
+func (emptyIterator) Account() []byte   { return nil }
+func (emptyIterator) Slot() []byte      { return nil }
+func (emptyIterator) Release()          {}
@@ -0,0 +1,152 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+)
+
+// verifyIterator checks that an iterator yields exactly the expected entries,
+// in ascending hash order.
+func verifyIterator(t *testing.T, it Iterator, value func() []byte, want map[common.Hash][]byte) {
+	defer it.Release()
+
+	var (
+		count int
+		last  common.Hash
+	)
+	for it.Next() {
+		hash := it.Hash()
+		if count > 0 && string(hash[:]) <= string(last[:]) {
+			t.Errorf("wrong order: %x after %x", hash, last)
+		}
+		if blob, ok := want[hash]; !ok {
+			t.Errorf("unexpected entry %x", hash)
+		} else if have := value(); string(have) != string(blob) {
+			t.Errorf("entry %x: blob mismatch: have %x, want %x", hash, have, blob)
+		}
+		last = hash
+		count++
+	}
+	if err := it.Error(); err != nil {
+		t.Errorf("iteration failed: %v", err)
+	}
+	if count != len(want) {
+		t.Errorf("entry count mismatch: have %d, want %d", count, len(want))
+	}
+}
+
+// Tests that iterating the accounts of a layered snapshot merges all layers,
+// the upper ones taking precedence and deletions hiding the lower entries.
+func TestAccountIteratorMerge(t *testing.T) {
+	var (
+		base  = common.HexToHash("0x01")
+		acc1  = common.HexToHash("0x11")
+		acc2  = common.HexToHash("0x22")
+		acc3  = common.HexToHash("0x33")
+		acc4  = common.HexToHash("0x44")
+		acc5  = common.HexToHash("0x55")
+		data1 = randomAccount()
+		data2 = randomAccount()
+		data3 = randomAccount()
+		data4 = randomAccount()
+		data5 = randomAccount()
+		mod1  = randomAccount()
+	)
+	snaps, _ := newTestTree(t, base, map[common.Hash][]byte{acc1: data1, acc2: data2, acc3: data3}, nil)
+
+	root1, root2 := common.HexToHash("0x02"), common.HexToHash("0x03")
+	if err := snaps.Update(root1, base, map[common.Hash]struct{}{acc2: {}}, map[common.Hash][]byte{acc4: data4}, nil); err != nil {
+		t.Fatalf("failed to create layer 1: %v", err)
+	}
+	if err := snaps.Update(root2, root1, nil, map[common.Hash][]byte{acc1: mod1, acc5: data5}, nil); err != nil {
+		t.Fatalf("failed to create layer 2: %v", err)
+	}
+	it, err := snaps.AccountIterator(root2, common.Hash{})
+	if err != nil {
+		t.Fatalf("failed to create iterator: %v", err)
+	}
+	verifyIterator(t, it, it.Account, map[common.Hash][]byte{acc1: mod1, acc3: data3, acc4: data4, acc5: data5})
+
+	// Seeking skips everything before the given position
+	it, err = snaps.AccountIterator(root2, common.HexToHash("0x30"))
+	if err != nil {
+		t.Fatalf("failed to create iterator: %v", err)
+	}
+	verifyIterator(t, it, it.Account, map[common.Hash][]byte{acc3: data3, acc4: data4, acc5: data5})
+
+	// The disk layer on its own is unaffected
+	it, err = snaps.AccountIterator(base, common.Hash{})
+	if err != nil {
+		t.Fatalf("failed to create iterator: %v", err)
+	}
+	verifyIterator(t, it, it.Account, map[common.Hash][]byte{acc1: data1, acc2: data2, acc3: data3})
+}
+
+// Tests that iterating the storage of a destructed and recreated account
+// doesn't yield the slots of the old account.
+func TestStorageIteratorDestruct(t *testing.T) {
+	var (
+		base  = common.HexToHash("0x01")
+		acc   = common.HexToHash("0xa1")
+		slot1 = common.HexToHash("0x11")
+		slot2 = common.HexToHash("0x22")
+		slot3 = common.HexToHash("0x33")
+	)
+	snaps, _ := newTestTree(t, base, map[common.Hash][]byte{acc: randomAccount()},
+		map[common.Hash]map[common.Hash][]byte{acc: {slot1: {0x01}, slot2: {0x02}}})
+
+	root1, root2 := common.HexToHash("0x02"), common.HexToHash("0x03")
+	if err := snaps.Update(root1, base, nil, nil,
+		map[common.Hash]map[common.Hash][]byte{acc: {slot1: nil, slot3: {0x03}}}); err != nil {
+		t.Fatalf("failed to create layer 1: %v", err)
+	}
+	it, err := snaps.StorageIterator(root1, acc, common.Hash{})
+	if err != nil {
+		t.Fatalf("failed to create iterator: %v", err)
+	}
+	verifyIterator(t, it, it.Slot, map[common.Hash][]byte{slot2: {0x02}, slot3: {0x03}})
+
+	// Recreate the account with a single slot
+	if err := snaps.Update(root2, root1, map[common.Hash]struct{}{acc: {}}, map[common.Hash][]byte{acc: randomAccount()},
+		map[common.Hash]map[common.Hash][]byte{acc: {slot1: {0x04}}}); err != nil {
+		t.Fatalf("failed to create layer 2: %v", err)
+	}
+	it, err = snaps.StorageIterator(root2, acc, common.Hash{})
+	if err != nil {
+		t.Fatalf("failed to create iterator: %v", err)
+	}
+	verifyIterator(t, it, it.Slot, map[common.Hash][]byte{slot1: {0x04}})
+}
+
+// Tests that iterators over layers flattened into the disk layer fail instead
+// of yielding outdated entries.
+func TestIteratorStale(t *testing.T) {
+	var (
+		base = common.HexToHash("0x01")
+		root = common.HexToHash("0x02")
+		acc  = common.HexToHash("0x11")
+	)
+	snaps, _ := newTestTree(t, base, map[common.Hash][]byte{acc: randomAccount()}, nil)
+	if err := snaps.Update(root, base, nil, map[common.Hash][]byte{acc: randomAccount()}, nil); err != nil {
+		t.Fatalf("failed to create diff layer: %v", err)
+	}
+	it, err := snaps.AccountIterator(base, common.Hash{})
+	if err != nil {
+		t.Fatalf("failed to create iterator: %v", err)
+	}
+	defer it.Release()
+
+	if err := snaps.Cap(root, 0); err != nil {
+		t.Fatalf("failed to cap diff layer: %v", err)
+	}
+	if it.Next() {
+		t.Errorf("stale iterator advanced")
+	}
+	if err := it.Error(); err != ErrSnapshotStale {
+		t.Errorf("stale iterator error: have %v, want %v", err, ErrSnapshotStale)
+	}
+	if _, err := snaps.AccountIterator(base, common.Hash{}); err == nil {
+		t.Errorf("iterator created on dropped layer")
+	}
+}
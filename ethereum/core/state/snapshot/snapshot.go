@@ -0,0 +1,433 @@
+// Package snapshot implements a flat, layered view of the state trie, serving
+// account and storage reads without walking the trie.
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/VictoriaMetrics/fastcache"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/trie"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/metrics"
+)
+
+var (
+	snapshotCleanAccountHitMeter   = metrics.NewRegisteredMeter("state/snapshot/clean/account/hit", nil)
+	snapshotCleanAccountMissMeter  = metrics.NewRegisteredMeter("state/snapshot/clean/account/miss", nil)
+	snapshotCleanAccountReadMeter  = metrics.NewRegisteredMeter("state/snapshot/clean/account/read", nil)
+	snapshotCleanStorageHitMeter   = metrics.NewRegisteredMeter("state/snapshot/clean/storage/hit", nil)
+	snapshotCleanStorageMissMeter  = metrics.NewRegisteredMeter("state/snapshot/clean/storage/miss", nil)
+	snapshotCleanStorageReadMeter  = metrics.NewRegisteredMeter("state/snapshot/clean/storage/read", nil)
+	snapshotDirtyAccountHitMeter   = metrics.NewRegisteredMeter("state/snapshot/dirty/account/hit", nil)
+	snapshotDirtyStorageHitMeter   = metrics.NewRegisteredMeter("state/snapshot/dirty/storage/hit", nil)
+	snapshotFlushAccountItemMeter  = metrics.NewRegisteredMeter("state/snapshot/flush/account/item", nil)
+	snapshotFlushStorageItemMeter  = metrics.NewRegisteredMeter("state/snapshot/flush/storage/item", nil)
+	snapshotGeneratedAccountMeter  = metrics.NewRegisteredMeter("state/snapshot/generation/account", nil)
+	snapshotGeneratedStorageMeter  = metrics.NewRegisteredMeter("state/snapshot/generation/storage", nil)
+	snapshotDiffLayersGauge        = metrics.NewRegisteredGauge("state/snapshot/difflayers", nil)
+	snapshotGenerationRunningGauge = metrics.NewRegisteredGauge("state/snapshot/generation/running", nil)
+)
+
+var (
+	// ErrSnapshotStale is returned from data accessors if the underlying snapshot
+	// layer had been invalidated due to the chain progressing forward far enough
+	// to not maintain the layer's original state.
+	ErrSnapshotStale = errors.New("snapshot stale")
+
+	// ErrNotCoveredYet is returned from data accessors if the underlying snapshot
+	// is being generated currently and the requested data item is not yet in the
+	// range of accounts covered.
+	ErrNotCoveredYet = errors.New("not covered yet")
+
+	// ErrNotConstructed is returned if the callers want to iterate the snapshot
+	// while the generation is not finished yet.
+	ErrNotConstructed = errors.New("snapshot is not constructed")
+
+	// errSnapshotCycle is returned if a snapshot is attempted to be inserted
+	// that forms a cycle in the snapshot tree.
+	errSnapshotCycle = errors.New("snapshot cycle")
+)
+
+// Snapshot represents the functionality supported by a snapshot storage layer.
+type Snapshot interface {
+	// Root returns the root hash for which this snapshot was made.
+	Root() common.Hash
+
+	// Account directly retrieves the account associated with a particular hash in
+	// the snapshot.
+	Account(hash common.Hash) (*Account, error)
+
+	// AccountRLP directly retrieves the account RLP associated with a particular
+	// hash in the snapshot. A nil blob means the account does not exist.
+	AccountRLP(hash common.Hash) ([]byte, error)
+
+	// Storage directly retrieves the storage trie value associated with a
+	// particular hash within a particular account. A nil blob means the slot
+	// does not exist.
+	Storage(accountHash, storageHash common.Hash) ([]byte, error)
+}
+
+// snapshot is the internal version of the snapshot data layer that supports some
+// additional methods compared to the public API.
+type snapshot interface {
+	Snapshot
+
+	// Parent returns the subsequent layer of a snapshot, or nil if the base was
+	// reached.
+	Parent() snapshot
+
+	// Update creates a new layer on top of the existing snapshot diff tree with
+	// the specified data items.
+	Update(blockRoot common.Hash, destructs map[common.Hash]struct{}, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) *diffLayer
+
+	// Stale return whether this layer has become stale (was flattened across) or
+	// if it's still live.
+	Stale() bool
+
+	// AccountIterator creates an account iterator over an arbitrary layer.
+	AccountIterator(seek common.Hash) AccountIterator
+
+	// StorageIterator creates a storage iterator over an arbitrary layer.
+	StorageIterator(account common.Hash, seek common.Hash) StorageIterator
+}
+
+// Tree is a state snapshot tree. It consists of one persistent base layer backed
+// by a key-value store, on top of which arbitrarily many in-memory diff layers
+// are topped. The memory diffs can form a tree with branching, but the disk
+// layer is singleton and common to all.
+//
+// Diff layers are created for every executed block, including the competing
+// ones proposed before PBFT reaches a decision. Once a block is committed, the
+// layers below it are flattened into the disk layer and the abandoned branches
+// are dropped.
+type Tree struct {
+	diskdb ethdb.KeyValueStore      // Persistent database to store the snapshot
+	triedb *trie.Database           // In-memory cache to access the trie through
+	cache  int                      // Megabytes permitted to use for read caches
+	layers map[common.Hash]snapshot // Collection of all known layers
+	lock   sync.RWMutex
+}
+
+// New attempts to load an already existing snapshot from a persistent key-value
+// store, ensuring that the head of the snapshot matches the expected one.
+//
+// If the snapshot is missing or the disk layer is broken, the entire snapshot is
+// deleted and regenerated in a background thread. If async is false, New blocks
+// until the generation finished.
+func New(diskdb ethdb.KeyValueStore, triedb *trie.Database, cache int, root common.Hash, async bool) *Tree {
+	snap := &Tree{
+		diskdb: diskdb,
+		triedb: triedb,
+		cache:  cache,
+		layers: make(map[common.Hash]snapshot),
+	}
+	if head := loadSnapshot(diskdb, triedb, cache, root); head != nil {
+		snap.layers[head.root] = head
+	} else {
+		log.Warn("Failed to load snapshot, regenerating", "root", root)
+		snap.Rebuild(root)
+	}
+	if !async {
+		snap.waitGeneration()
+	}
+	return snap
+}
+
+// waitGeneration blocks until the disk layer finished its background generation.
+func (t *Tree) waitGeneration() {
+	if disk := t.disklayer(); disk != nil {
+		<-disk.genPending
+	}
+}
+
+// Snapshot retrieves a snapshot belonging to the given block root, or nil if no
+// snapshot is maintained for that block.
+func (t *Tree) Snapshot(blockRoot common.Hash) Snapshot {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if layer, ok := t.layers[blockRoot]; ok {
+		return layer
+	}
+	return nil
+}
+
+// Update adds a new snapshot into the tree, if that can be linked to an existing
+// old parent. It is disallowed to insert a disk layer (the origin of all).
+func (t *Tree) Update(blockRoot common.Hash, parentRoot common.Hash, destructs map[common.Hash]struct{}, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) error {
+	// Reject noop updates to avoid self-loops in the snapshot tree. An empty
+	// block leaves the state untouched, so its layer is the parent's.
+	if blockRoot == parentRoot {
+		return errSnapshotCycle
+	}
+	// Hold the lock across the whole insertion, so the parent cannot be
+	// flattened away in between
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.layers[blockRoot]; ok {
+		return nil
+	}
+	// Generate a new snapshot on top of the parent
+	parent, ok := t.layers[parentRoot]
+	if !ok {
+		return fmt.Errorf("parent [%#x] snapshot missing", parentRoot)
+	}
+	snap := parent.Update(blockRoot, destructs, accounts, storage)
+	t.layers[snap.root] = snap
+	snapshotDiffLayersGauge.Update(int64(len(t.layers) - 1))
+	return nil
+}
+
+// Cap traverses downwards the snapshot tree from a head block hash until the
+// number of allowed layers are crossed. All layers beyond the permitted number
+// are flattened downwards into the disk layer. Layers built on top of the
+// flattened head are kept, every other branch is dropped.
+func (t *Tree) Cap(root common.Hash, layers int) error {
+	// Retrieve the head snapshot to cap from
+	snap := t.Snapshot(root)
+	if snap == nil {
+		return fmt.Errorf("snapshot [%#x] missing", root)
+	}
+	diff, ok := snap.(*diffLayer)
+	if !ok {
+		return nil // Already flattened into the disk layer
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// Collect the retained diff layers, the rest needs to be flattened
+	var chain []*diffLayer
+	for {
+		chain = append(chain, diff)
+		parent, ok := diff.parent.(*diffLayer)
+		if !ok {
+			break
+		}
+		diff = parent
+	}
+	if len(chain) <= layers {
+		return nil
+	}
+	// Flatten the surplus layers into disk one by one, bottom-most first
+	var base *diskLayer
+	for i := len(chain) - 1; i >= layers; i-- {
+		if i < len(chain)-1 {
+			chain[i].lock.Lock()
+			chain[i].parent = base
+			chain[i].lock.Unlock()
+		}
+		base = diffToDisk(chain[i])
+	}
+	t.layers[base.root] = base
+
+	// Relink the children of the flattened head onto the new disk layer, which
+	// holds the very same state, and drop every layer left dangling.
+	top := chain[layers]
+	for _, layer := range t.layers {
+		if diff, ok := layer.(*diffLayer); ok {
+			diff.lock.Lock()
+			if diff.parent == snapshot(top) {
+				diff.parent = base
+			}
+			diff.lock.Unlock()
+		}
+	}
+	for root, layer := range t.layers {
+		if !descends(layer) {
+			delete(t.layers, root)
+		}
+	}
+	snapshotDiffLayersGauge.Update(int64(len(t.layers) - 1))
+	return nil
+}
+
+// descends reports whether a layer is built, without any stale link, on top of
+// the live disk layer.
+func descends(layer snapshot) bool {
+	for layer != nil {
+		if layer.Stale() {
+			return false
+		}
+		layer = layer.Parent()
+	}
+	return true
+}
+
+// disklayer is an internal helper function to return the disk layer.
+// The lock of snapTree is assumed to be held already.
+func (t *Tree) disklayer() *diskLayer {
+	var snap snapshot
+	for _, s := range t.layers {
+		snap = s
+		break
+	}
+	if snap == nil {
+		return nil
+	}
+	switch layer := snap.(type) {
+	case *diskLayer:
+		return layer
+	case *diffLayer:
+		return layer.origin()
+	default:
+		panic(fmt.Sprintf("%T: undefined layer", snap))
+	}
+}
+
+// Rebuild wipes all available snapshot data from the persistent database and
+// discard all caches and diff layers. Afterwards, it starts a new snapshot
+// generator with the given root hash.
+func (t *Tree) Rebuild(root common.Hash) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.release()
+
+	// Start generating a new snapshot from scratch on a background thread. The
+	// generator will run a wiper first if there's not one running right now.
+	log.Info("Rebuilding state snapshot", "root", root)
+	t.layers = map[common.Hash]snapshot{
+		root: generateSnapshot(t.diskdb, t.triedb, t.cache, root),
+	}
+	snapshotDiffLayersGauge.Update(0)
+}
+
+// Release stops any background generation and marks every layer stale. The
+// tree must not be used afterwards, except for a Rebuild.
+func (t *Tree) Release() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.release()
+	t.layers = map[common.Hash]snapshot{}
+}
+
+// release aborts the generator of the disk layer and invalidates all layers.
+// The lock of snapTree is assumed to be held already.
+func (t *Tree) release() {
+	for _, layer := range t.layers {
+		switch layer := layer.(type) {
+		case *diskLayer:
+			layer.abortGeneration()
+			layer.lock.Lock()
+			layer.stale = true
+			layer.lock.Unlock()
+		case *diffLayer:
+			layer.lock.Lock()
+			layer.stale = true
+			layer.lock.Unlock()
+		}
+	}
+}
+
+// Generating reports whether the disk layer is still being built.
+func (t *Tree) Generating() bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	disk := t.disklayer()
+	if disk == nil {
+		return false
+	}
+	disk.lock.RLock()
+	defer disk.lock.RUnlock()
+	return disk.genMarker != nil
+}
+
+// AccountIterator creates a new account iterator for the specified root hash and
+// seeks to a starting account hash.
+func (t *Tree) AccountIterator(root common.Hash, seek common.Hash) (AccountIterator, error) {
+	snap, err := t.iterable(root)
+	if err != nil {
+		return nil, err
+	}
+	return snap.AccountIterator(seek), nil
+}
+
+// StorageIterator creates a new storage iterator for the specified root hash and
+// account. The iterator will be move to the specific start position.
+func (t *Tree) StorageIterator(root common.Hash, account common.Hash, seek common.Hash) (StorageIterator, error) {
+	snap, err := t.iterable(root)
+	if err != nil {
+		return nil, err
+	}
+	return snap.StorageIterator(account, seek), nil
+}
+
+// iterable returns the layer of the given root if its entire state can be
+// iterated, which requires a fully generated disk layer.
+func (t *Tree) iterable(root common.Hash) (snapshot, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	snap, ok := t.layers[root]
+	if !ok {
+		return nil, fmt.Errorf("snapshot [%#x] missing", root)
+	}
+	disk := t.disklayer()
+	if disk == nil {
+		return nil, fmt.Errorf("snapshot [%#x] missing", root)
+	}
+	disk.lock.RLock()
+	defer disk.lock.RUnlock()
+	if disk.genMarker != nil {
+		return nil, ErrNotConstructed
+	}
+	return snap, nil
+}
+
+// loadSnapshot loads the disk layer persisted at the last run, resuming its
+// generation if it was interrupted. Nil is returned if the persisted snapshot
+// does not belong to the given root.
+func loadSnapshot(diskdb ethdb.KeyValueStore, triedb *trie.Database, cache int, root common.Hash) *diskLayer {
+	baseRoot := rawdb.ReadSnapshotRoot(diskdb)
+	if baseRoot == (common.Hash{}) || baseRoot != root {
+		return nil
+	}
+	generator, err := loadGenerator(diskdb)
+	if err != nil {
+		log.Warn("Failed to load snapshot generator", "err", err)
+		return nil
+	}
+	base := &diskLayer{
+		diskdb:     diskdb,
+		triedb:     triedb,
+		cache:      fastcache.New(cache * 1024 * 1024),
+		root:       baseRoot,
+		genPending: make(chan struct{}),
+	}
+	if generator.Done {
+		close(base.genPending)
+		log.Info("Loaded state snapshot", "root", baseRoot)
+		return base
+	}
+	// The generation was interrupted, resume it from the persisted marker
+	base.genMarker = generator.Marker
+	if base.genMarker == nil {
+		base.genMarker = []byte{}
+	}
+	base.genWiping = generator.Wiping
+	base.genAbort = make(chan chan struct{})
+	log.Info("Resuming state snapshot generation", "root", baseRoot, "at", common.BytesToHash(base.genMarker), "wiping", generator.Wiping)
+	go base.generate(base.genAbort)
+	return base
+}
+
+// isEmptyRoot reports whether a storage root denotes an empty storage trie,
+// which is either the hash of the empty trie or the zero hash of a fresh account.
+func isEmptyRoot(root common.Hash) bool {
+	return root == emptyRoot || root == (common.Hash{})
+}
+
+// covered reports whether a key is within the range of a generation marker.
+func covered(key []byte, marker []byte) bool {
+	return marker == nil || bytes.Compare(key, marker) <= 0
+}
@@ -0,0 +1,221 @@
+package snapshot
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/trie"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// randomHash generates a random blob of data and returns it as a hash.
+func randomHash() common.Hash {
+	var hash common.Hash
+	if n, err := rand.Read(hash[:]); n != common.HashLength || err != nil {
+		panic(err)
+	}
+	return hash
+}
+
+// randomAccount generates a random account and returns it RLP encoded.
+func randomAccount() []byte {
+	root := randomHash()
+	a := Account{
+		Balance:          big.NewInt(rand.Int63()),
+		Nonce:            rand.Uint64(),
+		Root:             root,
+		CodeHash:         emptyCode[:],
+		StorageKeyPrefix: root[:20],
+	}
+	data, _ := rlp.EncodeToBytes(a)
+	return data
+}
+
+var emptyCode = common.HexToHash("c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470")
+
+// newTestTree creates a snapshot tree whose fully generated disk layer holds
+// the given accounts and storage slots.
+func newTestTree(t *testing.T, root common.Hash, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) (*Tree, ethdb.KeyValueStore) {
+	db := rawdb.NewMemoryDatabase()
+	for hash, data := range accounts {
+		rawdb.WriteAccountSnapshot(db, hash, data)
+	}
+	for accountHash, slots := range storage {
+		for storageHash, data := range slots {
+			rawdb.WriteStorageSnapshot(db, accountHash, storageHash, data)
+		}
+	}
+	rawdb.WriteSnapshotRoot(db, root)
+	journalProgress(db, nil, false)
+
+	snaps := New(db, trie.NewDatabase(db), 16, root, false)
+	if _, ok := snaps.Snapshot(root).(*diskLayer); !ok {
+		t.Fatalf("persisted snapshot not loaded")
+	}
+	return snaps, db
+}
+
+// Tests that diff layers resolve the accounts and slots they modified, hide the
+// ones they deleted and fall through to their parents for everything else.
+func TestDiffLayerReads(t *testing.T) {
+	var (
+		base    = common.HexToHash("0x01")
+		acc1    = common.HexToHash("0xa1")
+		acc2    = common.HexToHash("0xa2")
+		acc3    = common.HexToHash("0xa3")
+		slot    = common.HexToHash("0x51")
+		data1   = randomAccount()
+		data2   = randomAccount()
+		data3   = randomAccount()
+		updated = randomAccount()
+	)
+	snaps, _ := newTestTree(t, base, map[common.Hash][]byte{acc1: data1, acc2: data2},
+		map[common.Hash]map[common.Hash][]byte{acc2: {slot: {0x01}}})
+
+	// Modify the first account, destruct the second and create a third one
+	root := common.HexToHash("0x02")
+	err := snaps.Update(root, base, map[common.Hash]struct{}{acc2: {}},
+		map[common.Hash][]byte{acc1: updated, acc3: data3}, nil)
+	if err != nil {
+		t.Fatalf("failed to create diff layer: %v", err)
+	}
+	snap := snaps.Snapshot(root)
+	for hash, want := range map[common.Hash][]byte{acc1: updated, acc2: nil, acc3: data3} {
+		if blob, err := snap.AccountRLP(hash); err != nil {
+			t.Errorf("account %x: failed to retrieve: %v", hash, err)
+		} else if string(blob) != string(want) {
+			t.Errorf("account %x: blob mismatch: have %x, want %x", hash, blob, want)
+		}
+	}
+	if blob, err := snap.Storage(acc2, slot); err != nil || blob != nil {
+		t.Errorf("destructed storage: have %x (%v), want nil", blob, err)
+	}
+	// The disk layer is left untouched
+	if blob, _ := snaps.Snapshot(base).Storage(acc2, slot); string(blob) != string([]byte{0x01}) {
+		t.Errorf("disk storage: have %x, want %x", blob, []byte{0x01})
+	}
+}
+
+// Tests that the snapshot tree rejects layers that would loop onto themselves
+// or that don't link up to a known parent.
+func TestUpdateRejectsInvalidLayers(t *testing.T) {
+	base := common.HexToHash("0x01")
+	snaps, _ := newTestTree(t, base, nil, nil)
+
+	if err := snaps.Update(base, base, nil, nil, nil); err != errSnapshotCycle {
+		t.Errorf("self-referencing layer: have %v, want %v", err, errSnapshotCycle)
+	}
+	if err := snaps.Update(common.HexToHash("0x03"), common.HexToHash("0x02"), nil, nil, nil); err == nil {
+		t.Errorf("orphan layer accepted")
+	}
+	if snap := snaps.Snapshot(common.HexToHash("0x03")); snap != nil {
+		t.Errorf("orphan layer retained")
+	}
+}
+
+// Tests that committing a block flattens its layers into the disk layer, keeps
+// the layers built on top of it and drops the competing branches.
+func TestCapFlattensCommittedBranch(t *testing.T) {
+	var (
+		base  = common.HexToHash("0x01")
+		acc   = common.HexToHash("0xa1")
+		slot  = common.HexToHash("0x51")
+		dataB = randomAccount()
+		dataC = randomAccount()
+		dataX = randomAccount()
+	)
+	snaps, db := newTestTree(t, base, map[common.Hash][]byte{acc: randomAccount()}, nil)
+
+	// Two competing blocks B and X on the base, C on top of B
+	var (
+		rootB = common.HexToHash("0x0b")
+		rootC = common.HexToHash("0x0c")
+		rootX = common.HexToHash("0x0f")
+	)
+	if err := snaps.Update(rootB, base, nil, map[common.Hash][]byte{acc: dataB},
+		map[common.Hash]map[common.Hash][]byte{acc: {slot: {0x0b}}}); err != nil {
+		t.Fatalf("failed to create layer B: %v", err)
+	}
+	if err := snaps.Update(rootX, base, nil, map[common.Hash][]byte{acc: dataX}, nil); err != nil {
+		t.Fatalf("failed to create layer X: %v", err)
+	}
+	if err := snaps.Update(rootC, rootB, nil, map[common.Hash][]byte{acc: dataC}, nil); err != nil {
+		t.Fatalf("failed to create layer C: %v", err)
+	}
+	old := snaps.Snapshot(base)
+
+	// Commit B, it must end up on disk with C still readable on top
+	if err := snaps.Cap(rootB, 0); err != nil {
+		t.Fatalf("failed to cap layer B: %v", err)
+	}
+	if root := rawdb.ReadSnapshotRoot(db); root != rootB {
+		t.Errorf("disk root mismatch: have %x, want %x", root, rootB)
+	}
+	if blob := rawdb.ReadAccountSnapshot(db, acc); string(blob) != string(dataB) {
+		t.Errorf("flattened account mismatch: have %x, want %x", blob, dataB)
+	}
+	if blob := rawdb.ReadStorageSnapshot(db, acc, slot); string(blob) != string([]byte{0x0b}) {
+		t.Errorf("flattened slot mismatch: have %x, want %x", blob, []byte{0x0b})
+	}
+	if _, ok := snaps.Snapshot(rootB).(*diskLayer); !ok {
+		t.Errorf("committed layer is not the disk layer")
+	}
+	if blob, err := snaps.Snapshot(rootC).AccountRLP(acc); err != nil || string(blob) != string(dataC) {
+		t.Errorf("child layer read: have %x (%v), want %x", blob, err, dataC)
+	}
+	if blob, err := snaps.Snapshot(rootC).Storage(acc, slot); err != nil || string(blob) != string([]byte{0x0b}) {
+		t.Errorf("child layer fallthrough read: have %x (%v), want %x", blob, err, []byte{0x0b})
+	}
+	if snap := snaps.Snapshot(rootX); snap != nil {
+		t.Errorf("competing layer retained")
+	}
+	if _, err := old.AccountRLP(acc); err != ErrSnapshotStale {
+		t.Errorf("old disk layer read: have %v, want %v", err, ErrSnapshotStale)
+	}
+	// Committing C as well leaves a single layer
+	if err := snaps.Cap(rootC, 0); err != nil {
+		t.Fatalf("failed to cap layer C: %v", err)
+	}
+	if n := len(snaps.layers); n != 1 {
+		t.Errorf("layer count mismatch: have %d, want 1", n)
+	}
+	if blob := rawdb.ReadAccountSnapshot(db, acc); string(blob) != string(dataC) {
+		t.Errorf("flattened account mismatch: have %x, want %x", blob, dataC)
+	}
+}
+
+// Tests that flattening a destructed account removes its storage from disk.
+func TestCapDestructsStorage(t *testing.T) {
+	var (
+		base = common.HexToHash("0x01")
+		acc  = common.HexToHash("0xa1")
+		root = common.HexToHash("0x02")
+	)
+	storage := map[common.Hash][]byte{randomHash(): {0x01}, randomHash(): {0x02}}
+	snaps, db := newTestTree(t, base, map[common.Hash][]byte{acc: randomAccount()},
+		map[common.Hash]map[common.Hash][]byte{acc: storage})
+
+	if err := snaps.Update(root, base, map[common.Hash]struct{}{acc: {}}, nil, nil); err != nil {
+		t.Fatalf("failed to create diff layer: %v", err)
+	}
+	if err := snaps.Cap(root, 0); err != nil {
+		t.Fatalf("failed to cap diff layer: %v", err)
+	}
+	if blob := rawdb.ReadAccountSnapshot(db, acc); len(blob) != 0 {
+		t.Errorf("destructed account retained: %x", blob)
+	}
+	it := rawdb.IterateStorageSnapshots(db, acc)
+	defer it.Release()
+	if it.Next() {
+		t.Errorf("destructed storage retained: %x", it.Key())
+	}
+	for hash := range storage {
+		if blob, err := snaps.Snapshot(root).Storage(acc, hash); err != nil || blob != nil {
+			t.Errorf("destructed slot %x: have %x (%v), want nil", hash, blob, err)
+		}
+	}
+}
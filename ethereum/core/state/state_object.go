@@ -80,6 +80,12 @@ type stateObject struct {
 	dirtyCode bool // true if the code was updated
 	suicided  bool
 	deleted   bool
+
+	// Snapshot flags.
+	// An object whose storage was created or replaced within the state can't
+	// read it from the snapshot, which still holds the storage of the origin.
+	snapReset bool // true if storage reads bypass the snapshot
+	snapWipe  bool // true if the replaced storage is yet to be recorded as destructed
 }
 
 // empty returns whether the account is considered empty.
@@ -234,11 +240,22 @@ func (self *stateObject) GetCommittedState(db Database, key []byte) []byte {
 	if metrics.EnabledExpensive {
 		defer func(start time.Time) { self.db.StorageReads += time.Since(start) }(time.Now())
 	}
-	// Otherwise load the valueKey from trie
-	enc, err := self.getTrie(db).TryGet(key[:])
-	if err != nil {
-		self.setError(err)
-		return []byte{}
+	// Otherwise load the valueKey from the snapshot, unless the value was already
+	// resolved or the snapshot doesn't hold the storage of this object, falling
+	// back to the trie
+	var (
+		enc []byte
+		ok  bool
+	)
+	if _, cached := self.originStorage[string(key)]; !cached && !self.snapReset {
+		enc, ok = self.db.snapSlot(self.addrHash, key)
+	}
+	if !ok {
+		var err error
+		if enc, err = self.getTrie(db).TryGet(key[:]); err != nil {
+			self.setError(err)
+			return []byte{}
+		}
 	}
 	value := make([]byte, 0)
 	if len(enc) > 0 {
@@ -309,6 +326,10 @@ func (self *stateObject) updateTrie(db Database) Trie {
 	if metrics.EnabledExpensive {
 		defer func(start time.Time) { self.db.StorageUpdates += time.Since(start) }(time.Now())
 	}
+	var slots map[common.Hash][]byte
+	if self.db.snaps != nil {
+		slots = make(map[common.Hash][]byte, len(self.dirtyStorage))
+	}
 	for key, value := range self.dirtyStorage {
 		delete(self.dirtyStorage, key)
 
@@ -322,13 +343,20 @@ func (self *stateObject) updateTrie(db Database) Trie {
 
 		if len(value) == 0 {
 			self.setError(tr.TryDelete([]byte(key)))
+			if slots != nil {
+				slots[crypto.Keccak256Hash([]byte(key))] = nil
+			}
 			continue
 		}
 
 		// Encoding []byte cannot fail, ok to ignore the error.
 		v, _ := rlp.EncodeToBytes(value)
 		self.setError(tr.TryUpdate([]byte(key), v))
+		if slots != nil {
+			slots[crypto.Keccak256Hash([]byte(key))] = v
+		}
 	}
+	self.db.recordSnapStorage(self, tr, slots)
 
 	return tr
 }
@@ -415,6 +443,8 @@ func (self *stateObject) deepCopy(db *StateDB) *stateObject {
 	stateObject.suicided = self.suicided
 	stateObject.dirtyCode = self.dirtyCode
 	stateObject.deleted = self.deleted
+	stateObject.snapReset = self.snapReset
+	stateObject.snapWipe = self.snapWipe
 	return stateObject
 }
 
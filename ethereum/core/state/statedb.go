@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state/snapshot"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/trie"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
@@ -66,6 +67,9 @@ type StateDB struct {
 
 	preimages map[common.Hash][]byte
 
+	// Per-transaction access list
+	accessList *accessList
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        *journal
@@ -88,6 +92,18 @@ type StateDB struct {
 	// statedb is created based on this root
 	originRoot common.Hash
 
+	// The snapshot tree serving flat reads, and the changes made on top of the
+	// snapRoot layer that make up the layer of this state.
+	snaps         *snapshot.Tree
+	snapRoot      common.Hash
+	snapLock      sync.Mutex
+	snapDestructs map[common.Hash]struct{}
+	snapAccounts  map[common.Hash][]byte
+	snapStorage   map[common.Hash]map[common.Hash][]byte
+
+	// The accesses of a speculatively executed transaction, nil otherwise
+	rwSet *TxRWSet
+
 	// Measurements gathered during execution for debugging purposes
 	AccountReads   time.Duration
 	AccountHashes  time.Duration
@@ -101,6 +117,12 @@ type StateDB struct {
 
 // Create a new state from a given trie.
 func New(root common.Hash, db Database) (*StateDB, error) {
+	return NewWithSnapshot(root, db, nil)
+}
+
+// NewWithSnapshot creates a new state from a given trie, reading accounts and
+// storage from the snapshot tree whenever it holds a layer for the root.
+func NewWithSnapshot(root common.Hash, db Database, snaps *snapshot.Tree) (*StateDB, error) {
 	tr, err := db.OpenTrie(root)
 	if err != nil {
 		return nil, err
@@ -112,10 +134,12 @@ func New(root common.Hash, db Database) (*StateDB, error) {
 		stateObjectsDirty:  make(map[common.Address]struct{}),
 		logs:               make(map[common.Hash][]*types.Log),
 		preimages:          make(map[common.Hash][]byte),
+		accessList:         newAccessList(),
 		journal:            newJournal(),
 		clearReferenceFunc: make([]func(), 0),
 		originRoot:         root,
 	}
+	state.setSnapshot(snaps, root)
 	return state, nil
 }
 
@@ -128,11 +152,13 @@ func (self *StateDB) NewStateDB() *StateDB {
 		stateObjectsDirty:  make(map[common.Address]struct{}),
 		logs:               make(map[common.Hash][]*types.Log),
 		preimages:          make(map[common.Hash][]byte),
+		accessList:         newAccessList(),
 		journal:            newJournal(),
 		parent:             self,
 		clearReferenceFunc: make([]func(), 0),
 		originRoot:         self.Root(),
 	}
+	stateDB.setSnapshot(self.snaps, stateDB.originRoot)
 
 	index := self.AddReferenceFunc(stateDB.clearParentRef)
 	stateDB.referenceFuncIndex = index
@@ -221,7 +247,9 @@ func (self *StateDB) Reset(root common.Hash) error {
 	self.logs = make(map[common.Hash][]*types.Log)
 	self.logSize = 0
 	self.preimages = make(map[common.Hash][]byte)
+	self.accessList = newAccessList()
 	self.clearJournalAndRefund()
+	self.setSnapshot(self.snaps, root)
 	return nil
 }
 
@@ -282,18 +310,21 @@ func (self *StateDB) SubRefund(gas uint64) {
 // Exist reports whether the given account address exists in the state.
 // Notably this also returns true for suicided accounts.
 func (self *StateDB) Exist(addr common.Address) bool {
+	self.recordAccountRead(addr)
 	return self.getStateObject(addr) != nil
 }
 
 // Empty returns whether the state object is either non-existent
 // or empty according to the EIP161 specification (balance = nonce = code = 0)
 func (self *StateDB) Empty(addr common.Address) bool {
+	self.recordAccountRead(addr)
 	so := self.getStateObject(addr)
 	return so == nil || so.empty()
 }
 
 // Retrieve the balance from the given address or 0 if object not found
 func (self *StateDB) GetBalance(addr common.Address) *big.Int {
+	self.recordAccountRead(addr)
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Balance()
@@ -302,6 +333,7 @@ func (self *StateDB) GetBalance(addr common.Address) *big.Int {
 }
 
 func (self *StateDB) GetNonce(addr common.Address) uint64 {
+	self.recordAccountRead(addr)
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Nonce()
@@ -311,6 +343,7 @@ func (self *StateDB) GetNonce(addr common.Address) uint64 {
 }
 
 func (self *StateDB) GetCode(addr common.Address) []byte {
+	self.recordAccountRead(addr)
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Code(self.db)
@@ -319,6 +352,7 @@ func (self *StateDB) GetCode(addr common.Address) []byte {
 }
 
 func (self *StateDB) GetCodeSize(addr common.Address) int {
+	self.recordAccountRead(addr)
 	stateObject := self.getStateObject(addr)
 	if stateObject == nil {
 		return 0
@@ -334,6 +368,7 @@ func (self *StateDB) GetCodeSize(addr common.Address) int {
 }
 
 func (self *StateDB) GetCodeHash(addr common.Address) common.Hash {
+	self.recordAccountRead(addr)
 	stateObject := self.getStateObject(addr)
 	if stateObject == nil {
 		return common.Hash{}
@@ -345,6 +380,7 @@ func (self *StateDB) GetCodeHash(addr common.Address) common.Hash {
 func (self *StateDB) GetState(addr common.Address, key []byte) []byte {
 	self.lock.Lock()
 	defer self.lock.Unlock()
+	self.recordStorageRead(addr, key)
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.removePrefixValue(stateObject.GetState(self.db, key))
@@ -383,6 +419,7 @@ func (s *StateDB) GetStorageProof(a common.Address, key common.Hash) ([][]byte,
 
 // GetCommittedState retrieves a value from the given account's committed storage trie.
 func (self *StateDB) GetCommittedState(addr common.Address, key []byte) []byte {
+	self.recordStorageRead(addr, key)
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.removePrefixValue(stateObject.GetCommittedState(self.db, key))
@@ -407,6 +444,7 @@ func (self *StateDB) StorageTrie(addr common.Address) Trie {
 }
 
 func (self *StateDB) HasSuicided(addr common.Address) bool {
+	self.recordAccountRead(addr)
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.suicided
@@ -490,6 +528,7 @@ func (self *StateDB) SetState(address common.Address, key, value []byte) {
 // The account's state object is still available until the state is committed,
 // getStateObject will return a non-nil account after Suicide.
 func (self *StateDB) Suicide(addr common.Address) bool {
+	self.recordAccountRead(addr)
 	stateObject := self.getStateObject(addr)
 	if stateObject == nil {
 		return false
@@ -521,6 +560,7 @@ func (self *StateDB) updateStateObject(stateObject *stateObject) {
 		panic(fmt.Errorf("can't encode object at %x: %v", addr[:], err))
 	}
 	self.setError(self.trie.TryUpdate(addr[:], data))
+	self.recordSnapAccount(stateObject.addrHash, data)
 }
 
 // deleteStateObject removes the given object from the state trie.
@@ -532,6 +572,7 @@ func (self *StateDB) deleteStateObject(stateObject *stateObject) {
 	stateObject.deleted = true
 	addr := stateObject.Address()
 	self.setError(self.trie.TryDelete(addr[:]))
+	self.recordSnapDestruct(stateObject.addrHash)
 }
 
 // Get the current StateDB cache and the parent StateDB cache
@@ -691,10 +732,17 @@ func (self *StateDB) getStateObject(addr common.Address) (stateObject *stateObje
 	if metrics.EnabledExpensive {
 		defer func(start time.Time) { self.AccountReads += time.Since(start) }(time.Now())
 	}
-	// Load the object from the database.
-	enc, err := self.trie.TryGet(addr[:])
+	// Load the object from the snapshot, or from the database if the snapshot
+	// can't serve it.
+	enc, ok := self.snapAccount(addr)
+	if !ok {
+		var err error
+		if enc, err = self.trie.TryGet(addr[:]); len(enc) == 0 {
+			self.setError(err)
+			return nil
+		}
+	}
 	if len(enc) == 0 {
-		self.setError(err)
 		return nil
 	}
 	var data Account
@@ -739,8 +787,10 @@ func (self *StateDB) createObject(addr common.Address) (newobj, prev *stateObjec
 		prefix := make([]byte, len(prev.data.StorageKeyPrefix))
 		copy(prefix, prev.data.StorageKeyPrefix)
 		newobj = newObject(self, addr, Account{StorageKeyPrefix: prefix})
+		newobj.snapWipe = true
 		self.journal.append(resetObjectChange{prev: prev})
 	}
+	newobj.snapReset = true
 	newobj.setNonce(0) // sets the object to dirty
 	self.setStateObject(newobj)
 	return newobj, prev
@@ -757,6 +807,7 @@ func (self *StateDB) createObject(addr common.Address) (newobj, prev *stateObjec
 //
 // Carrying over the balance ensures that Ether doesn't disappear.
 func (self *StateDB) CreateAccount(addr common.Address) {
+	self.recordAccountRead(addr)
 	newObj, prev := self.createObject(addr)
 	if prev != nil {
 		newObj.setBalance(prev.data.Balance)
@@ -772,6 +823,7 @@ func (self *StateDB) TxIdx() uint32 {
 }
 
 func (db *StateDB) ForEachStorage(addr common.Address, cb func(key, value []byte) bool) {
+	db.recordAllStorageRead(addr)
 	so := db.getStateObject(addr)
 	if so == nil {
 		return
@@ -790,6 +842,7 @@ func (db *StateDB) ForEachStorage(addr common.Address, cb func(key, value []byte
 }
 
 func (db *StateDB) MigrateStorage(from, to common.Address) {
+	db.recordMigrate(from, to)
 
 	fromObj := db.getStateObject(from)
 	toObj := db.getStateObject(to)
@@ -806,6 +859,9 @@ func (db *StateDB) MigrateStorage(from, to common.Address) {
 		// replace storage
 		toObj.dirtyStorage = fromObj.dirtyStorage.Copy()
 		toObj.originStorage = fromObj.originStorage.Copy()
+		// the snapshot still holds the replaced storage
+		toObj.snapReset = true
+		toObj.snapWipe = true
 	}
 }
 
@@ -829,6 +885,7 @@ func (self *StateDB) Copy() *StateDB {
 		clearReferenceFunc: make([]func(), 0),
 		originRoot:         self.originRoot,
 	}
+	self.copySnapshot(state)
 
 	// Copy the dirty states, logs, and preimages
 	for addr := range self.journal.dirties {
@@ -861,12 +918,16 @@ func (self *StateDB) Copy() *StateDB {
 	for hash, preimage := range self.preimages {
 		state.preimages[hash] = preimage
 	}
+	// The access list is only valid within a transaction, but copies are also
+	// made in the middle of one, e.g. by the tracers.
+	state.accessList = self.accessList.Copy()
+
 	// Copy parent state
 	self.refLock.Lock()
 	if self.parent != nil {
 		if !self.parentCommitted {
 			state.parent = self.parent
-			state.parent.AddReferenceFunc(state.clearParentRef)
+			state.referenceFuncIndex = state.parent.AddReferenceFunc(state.clearParentRef)
 		} else {
 			self.parent = nil
 		}
@@ -966,11 +1027,72 @@ func (s *StateDB) Root() common.Hash {
 }
 
 // Prepare sets the current transaction hash and index and block hash which is
-// used when the EVM emits new state logs.
+// used when the EVM emits new state logs. It also resets the access list of
+// the previous transaction.
 func (self *StateDB) Prepare(thash, bhash common.Hash, ti int) {
 	self.thash = thash
 	self.bhash = bhash
 	self.txIndex = ti
+	self.accessList = newAccessList()
+}
+
+// PrepareAccessList warms the accounts and storage slots known before the
+// execution of a transaction after the access list fork:
+// - the sender and the destination, if any
+// - the precompiled contracts
+// - the content of the access list of the transaction
+//
+// This method should only be called if the access list fork is active.
+func (self *StateDB) PrepareAccessList(sender common.Address, dst *common.Address, precompiles []common.Address, list types.AccessList) {
+	self.AddAddressToAccessList(sender)
+	if dst != nil {
+		self.AddAddressToAccessList(*dst)
+		// If it's a create-tx, the destination will be added inside evm.create
+	}
+	for _, addr := range precompiles {
+		self.AddAddressToAccessList(addr)
+	}
+	for _, el := range list {
+		self.AddAddressToAccessList(el.Address)
+		for _, key := range el.StorageKeys {
+			self.AddSlotToAccessList(el.Address, key)
+		}
+	}
+}
+
+// AddAddressToAccessList adds the given address to the access list
+func (self *StateDB) AddAddressToAccessList(addr common.Address) {
+	if self.accessList.AddAddress(addr) {
+		self.journal.append(accessListAddAccountChange{&addr})
+	}
+}
+
+// AddSlotToAccessList adds the given (address, slot)-tuple to the access list
+func (self *StateDB) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	addrMod, slotMod := self.accessList.AddSlot(addr, slot)
+	if addrMod {
+		// In practice, this should not happen, since there is no way to enter the
+		// scope of 'address' without having the 'address' become already added
+		// to the access list (via call-variant, create, etc).
+		// Better safe than sorry, though
+		self.journal.append(accessListAddAccountChange{&addr})
+	}
+	if slotMod {
+		self.journal.append(accessListAddSlotChange{
+			address: &addr,
+			slot:    &slot,
+		})
+	}
+}
+
+// AddressInAccessList returns true if the given address is in the access list.
+func (self *StateDB) AddressInAccessList(addr common.Address) bool {
+	return self.accessList.ContainsAddress(addr)
+}
+
+// SlotInAccessList returns true if the given (address, slot)-tuple is in the access list.
+func (self *StateDB) SlotInAccessList(addr common.Address, slot common.Hash) (addressPresent bool, slotPresent bool) {
+	return self.accessList.Contains(addr, slot)
 }
 
 func (s *StateDB) clearJournalAndRefund() {
@@ -1127,6 +1249,7 @@ func (self *StateDB) IncreaseTxIdx() {
 func (self *StateDB) ListActiveVersion() ([]gov.ActiveVersionValue, error) {
 	//avListBytes := self.GetState(vm.GovContractAddr, gov.KeyActiveVersions())
 	var avListBytes []byte
+	self.recordStorageRead(vm.GovContractAddr, gov.KeyActiveVersions())
 	stateObject := self.getStateObject(vm.GovContractAddr)
 	if stateObject != nil {
 		avListBytes = stateObject.removePrefixValue(stateObject.GetState(self.db, gov.KeyActiveVersions()))
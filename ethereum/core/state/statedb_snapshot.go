@@ -0,0 +1,178 @@
+package state
+
+import (
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state/snapshot"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/trie"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+// setSnapshot bases the flat reads of the state on the snapshot layer of the
+// given root, dropping any changes recorded so far.
+func (self *StateDB) setSnapshot(snaps *snapshot.Tree, root common.Hash) {
+	self.snapLock.Lock()
+	defer self.snapLock.Unlock()
+
+	self.snaps, self.snapRoot = snaps, root
+	if snaps != nil {
+		self.snapDestructs = make(map[common.Hash]struct{})
+		self.snapAccounts = make(map[common.Hash][]byte)
+		self.snapStorage = make(map[common.Hash]map[common.Hash][]byte)
+	}
+}
+
+// snapLayer returns the snapshot layer the state is based on, or nil if the
+// snapshot tree holds none for it (yet).
+func (self *StateDB) snapLayer() snapshot.Snapshot {
+	if self.snaps == nil {
+		return nil
+	}
+	return self.snaps.Snapshot(self.snapRoot)
+}
+
+// snapAccount retrieves the encoded account of an address from the snapshot.
+// False is returned if the snapshot can't serve the read, in which case it
+// needs to be done on the trie.
+func (self *StateDB) snapAccount(addr common.Address) ([]byte, bool) {
+	snap := self.snapLayer()
+	if snap == nil {
+		return nil, false
+	}
+	enc, err := snap.AccountRLP(crypto.Keccak256Hash(addr[:]))
+	if err != nil {
+		return nil, false
+	}
+	return enc, true
+}
+
+// snapSlot retrieves the encoded storage slot of an account from the snapshot.
+// False is returned if the snapshot can't serve the read.
+func (self *StateDB) snapSlot(addrHash common.Hash, key []byte) ([]byte, bool) {
+	snap := self.snapLayer()
+	if snap == nil {
+		return nil, false
+	}
+	enc, err := snap.Storage(addrHash, crypto.Keccak256Hash(key))
+	if err != nil {
+		return nil, false
+	}
+	return enc, true
+}
+
+// recordSnapAccount records an account written into the account trie.
+func (self *StateDB) recordSnapAccount(addrHash common.Hash, data []byte) {
+	if self.snaps == nil {
+		return
+	}
+	self.snapLock.Lock()
+	defer self.snapLock.Unlock()
+
+	self.snapAccounts[addrHash] = data
+}
+
+// recordSnapDestruct records an account deleted from the account trie, along
+// with all its storage.
+func (self *StateDB) recordSnapDestruct(addrHash common.Hash) {
+	if self.snaps == nil {
+		return
+	}
+	self.snapLock.Lock()
+	defer self.snapLock.Unlock()
+
+	self.snapDestructs[addrHash] = struct{}{}
+	delete(self.snapAccounts, addrHash)
+	delete(self.snapStorage, addrHash)
+}
+
+// recordSnapStorage records the slots written into the storage trie of an
+// object, nil values being deletions. If the storage of the object replaced
+// the previous one as a whole, the old storage is destructed and the full
+// content of the trie is recorded instead.
+func (self *StateDB) recordSnapStorage(obj *stateObject, tr Trie, slots map[common.Hash][]byte) {
+	if self.snaps == nil {
+		return
+	}
+	if obj.snapWipe {
+		slots = make(map[common.Hash][]byte)
+		it := trie.NewIterator(tr.NodeIterator(nil))
+		for it.Next() {
+			slots[common.BytesToHash(it.Key)] = common.CopyBytes(it.Value)
+		}
+		obj.setError(it.Err)
+	}
+	self.snapLock.Lock()
+	defer self.snapLock.Unlock()
+
+	if obj.snapWipe {
+		self.snapDestructs[obj.addrHash] = struct{}{}
+		self.snapStorage[obj.addrHash] = slots
+		obj.snapWipe = false
+		return
+	}
+	if len(slots) == 0 {
+		return
+	}
+	storage := self.snapStorage[obj.addrHash]
+	if storage == nil {
+		storage = make(map[common.Hash][]byte, len(slots))
+		self.snapStorage[obj.addrHash] = storage
+	}
+	for hash, value := range slots {
+		storage[hash] = value
+	}
+}
+
+// UpdateSnapshot adds the changes made by the state to the snapshot tree, as
+// the layer of the given root on top of the one the state is based on. States
+// built on top of this one can then read from the snapshot before it's
+// committed.
+func (self *StateDB) UpdateSnapshot(root common.Hash) error {
+	if self.snaps == nil || root == self.snapRoot {
+		return nil
+	}
+	self.snapLock.Lock()
+	destructs := make(map[common.Hash]struct{}, len(self.snapDestructs))
+	for hash := range self.snapDestructs {
+		destructs[hash] = struct{}{}
+	}
+	accounts := make(map[common.Hash][]byte, len(self.snapAccounts))
+	for hash, data := range self.snapAccounts {
+		accounts[hash] = data
+	}
+	storage := make(map[common.Hash]map[common.Hash][]byte, len(self.snapStorage))
+	for hash, slots := range self.snapStorage {
+		cpy := make(map[common.Hash][]byte, len(slots))
+		for key, value := range slots {
+			cpy[key] = value
+		}
+		storage[hash] = cpy
+	}
+	parent := self.snapRoot
+	self.snapLock.Unlock()
+
+	return self.snaps.Update(root, parent, destructs, accounts, storage)
+}
+
+// copySnapshot copies the snapshot base and recorded changes of the state.
+func (self *StateDB) copySnapshot(state *StateDB) {
+	state.setSnapshot(self.snaps, self.snapRoot)
+	if self.snaps == nil {
+		return
+	}
+	self.snapLock.Lock()
+	defer self.snapLock.Unlock()
+
+	for hash := range self.snapDestructs {
+		state.snapDestructs[hash] = struct{}{}
+	}
+	for hash, data := range self.snapAccounts {
+		state.snapAccounts[hash] = data
+	}
+	for hash, slots := range self.snapStorage {
+		cpy := make(map[common.Hash][]byte, len(slots))
+		for key, value := range slots {
+			cpy[key] = value
+		}
+		state.snapStorage[hash] = cpy
+	}
+}
@@ -0,0 +1,78 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state/snapshot"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+// Tests that the changes of a committed state end up in the snapshot tree, and
+// that states based on it read the same values from the snapshot as from the trie.
+func TestStateSnapshotReads(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	sdb := NewDatabase(db)
+	snaps := snapshot.New(db, sdb.TrieDB(), 16, common.Hash{}, false)
+	defer snaps.Release()
+
+	var (
+		addr1 = common.Address{byte(1)}
+		addr2 = common.Address{byte(2)}
+		key1  = []byte("key1")
+		key2  = []byte("key2")
+	)
+	s1, _ := NewWithSnapshot(common.Hash{}, sdb, snaps)
+	s1.AddBalance(addr1, big.NewInt(100))
+	s1.SetState(addr1, key1, []byte("value1"))
+	s1.SetState(addr1, key2, []byte("value2"))
+	s1.AddBalance(addr2, big.NewInt(200))
+	s1.SetState(addr2, key1, []byte("value3"))
+
+	root1, err := s1.Commit(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s1.UpdateSnapshot(root1); err != nil {
+		t.Fatalf("failed to update snapshot: %v", err)
+	}
+	snap := snaps.Snapshot(root1)
+	if snap == nil {
+		t.Fatalf("snapshot layer missing")
+	}
+	if blob, err := snap.AccountRLP(crypto.Keccak256Hash(addr1[:])); err != nil || len(blob) == 0 {
+		t.Errorf("account missing from snapshot: %x (%v)", blob, err)
+	}
+
+	// Modify and delete on top of the first block
+	s2, _ := NewWithSnapshot(root1, sdb, snaps)
+	assert.Equal(t, big.NewInt(100), s2.GetBalance(addr1))
+	assert.Equal(t, []byte("value1"), s2.GetState(addr1, key1))
+	assert.Equal(t, []byte("value3"), s2.GetState(addr2, key1))
+
+	s2.SetState(addr1, key1, []byte("value4"))
+	s2.SetState(addr1, key2, []byte{})
+	s2.Suicide(addr2)
+
+	root2, err := s2.Commit(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s2.UpdateSnapshot(root2); err != nil {
+		t.Fatalf("failed to update snapshot: %v", err)
+	}
+	if err := snaps.Cap(root2, 0); err != nil {
+		t.Fatalf("failed to cap snapshot: %v", err)
+	}
+	withSnap, _ := NewWithSnapshot(root2, sdb, snaps)
+	withTrie, _ := New(root2, sdb)
+	for _, state := range []*StateDB{withSnap, withTrie} {
+		assert.Equal(t, []byte("value4"), state.GetState(addr1, key1))
+		assert.Equal(t, 0, len(state.GetState(addr1, key2)))
+		assert.False(t, state.Exist(addr2))
+	}
+}
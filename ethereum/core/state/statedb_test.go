@@ -799,3 +799,39 @@ func TestGetAfterDelete(t *testing.T) {
 	assert.Equal(t, buf, []byte("value"))
 	assert.Equal(t, buf1, []byte("value1"))
 }
+
+func TestStateDBAccessList(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()))
+	var (
+		addrA = common.HexToAddress("aa")
+		addrB = common.HexToAddress("bb")
+		slot1 = common.HexToHash("01")
+		slot2 = common.HexToHash("02")
+	)
+	state.PrepareAccessList(addrA, nil, nil, types.AccessList{{Address: addrB, StorageKeys: []common.Hash{slot1}}})
+	if !state.AddressInAccessList(addrA) || !state.AddressInAccessList(addrB) {
+		t.Fatal("prepared addresses are not warm")
+	}
+	if _, slotOk := state.SlotInAccessList(addrB, slot1); !slotOk {
+		t.Fatal("prepared slot is not warm")
+	}
+
+	snap := state.Snapshot()
+	state.AddSlotToAccessList(addrA, slot2)
+	if _, slotOk := state.SlotInAccessList(addrA, slot2); !slotOk {
+		t.Fatal("added slot is not warm")
+	}
+	cpy := state.Copy()
+	state.RevertToSnapshot(snap)
+	if addrOk, slotOk := state.SlotInAccessList(addrA, slot2); !addrOk || slotOk {
+		t.Fatalf("revert left the access list inconsistent: address %v, slot %v", addrOk, slotOk)
+	}
+	if _, slotOk := cpy.SlotInAccessList(addrA, slot2); !slotOk {
+		t.Fatal("the copy lost its access list")
+	}
+
+	state.Prepare(common.Hash{1}, common.Hash{}, 1)
+	if state.AddressInAccessList(addrA) {
+		t.Fatal("access list is not reset for the next transaction")
+	}
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"math/big"
 	"strconv"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
@@ -98,8 +99,11 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 func ApplyTransaction(config *configs.ChainConfig, bc ChainContext, gp *GasPool,
 	statedb *state.StateDB, header *types.Header, tx *types.Transaction,
 	usedGas *uint64, cfg vm.Config) (*types.Receipt, uint64, error) {
+	if !txTypeSupported(config, tx, header.Number) {
+		return nil, 0, ErrTxTypeNotSupported
+	}
 
-	msg, err := tx.AsMessage(types.NewEIP155Signer(config.ChainID))
+	msg, err := tx.AsMessage(types.NewEIP155Signer(config.ChainID), header.BaseFee)
 
 	if err != nil {
 		return nil, 0, err
@@ -120,17 +124,29 @@ func ApplyTransaction(config *configs.ChainConfig, bc ChainContext, gp *GasPool,
 	// Update the state with pending changes
 	statedb.Finalise(true)
 
-	var root []byte
 	*usedGas += result.UsedGas
 
+	receipt, err := newReceipt(statedb, header, tx, msg, result, *usedGas)
+	if err != nil {
+		return nil, 0, err
+	}
+	return receipt, result.UsedGas, nil
+}
+
+// newReceipt creates the receipt of a transaction applied to statedb, the
+// cumulative gas used includes the transaction itself.
+func newReceipt(statedb *state.StateDB, header *types.Header, tx *types.Transaction, msg types.Message,
+	result *ExecutionResult, cumulativeGasUsed uint64) (*types.Receipt, error) {
+	var root []byte
+
 	// Create a new receipt for the transaction, storing the intermediate root and gas used by the tx
 	// based on the eip phase, we're passing whether the root touch-delete accounts.
-	receipt := types.NewReceipt(root, result.Failed(), *usedGas)
+	receipt := types.NewReceipt(root, result.Failed(), cumulativeGasUsed)
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = result.UsedGas
 	// if the transaction created a contract, store the creation address in the receipt.
 	if msg.To() == nil {
-		receipt.ContractAddress = crypto.CreateAddress(vmenv.Context.Origin, tx.Nonce())
+		receipt.ContractAddress = crypto.CreateAddress(msg.From(), tx.Nonce())
 	}
 	// Set the receipt logs
 	if result.Failed() {
@@ -139,7 +155,7 @@ func ApplyTransaction(config *configs.ChainConfig, bc ChainContext, gp *GasPool,
 			res := strconv.Itoa(int(bizError.Code))
 			if err := rlp.Encode(buf, [][]byte{[]byte(res)}); nil != err {
 				log.Error("Cannot RlpEncode the log data", "data", bizError.Code, "err", err)
-				return nil, 0, err
+				return nil, err
 			}
 			receipt.Logs = []*types.Log{
 				&types.Log{
@@ -160,5 +176,21 @@ func ApplyTransaction(config *configs.ChainConfig, bc ChainContext, gp *GasPool,
 	receipt.BlockHash = statedb.BlockHash()
 	receipt.BlockNumber = header.Number
 	receipt.TransactionIndex = uint(statedb.TxIndex())
-	return receipt, result.UsedGas, err
+	return receipt, nil
+}
+
+// txTypeSupported returns whether the type of the given transaction is accepted
+// in the block with the given number. Dynamic fee transactions carrying an
+// access list additionally need the access list fork.
+func txTypeSupported(config *configs.ChainConfig, tx *types.Transaction, number *big.Int) bool {
+	switch tx.Type() {
+	case types.LegacyTxType:
+		return true
+	case types.DynamicFeeTxType:
+		return config.IsDynamicFee(number) && (len(tx.AccessList()) == 0 || config.IsAccessList(number))
+	case types.AccessListTxType:
+		return config.IsAccessList(number)
+	default:
+		return false
+	}
 }
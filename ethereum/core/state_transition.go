@@ -3,14 +3,19 @@ package core
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"time"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	cmath "github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/math"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/gov"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/xcom"
 )
 
 var (
@@ -36,6 +41,8 @@ type StateTransition struct {
 	msg        Message
 	gas        uint64
 	gasPrice   *big.Int
+	gasFeeCap  *big.Int
+	gasTipCap  *big.Int
 	initialGas uint64
 	value      *big.Int
 	data       []byte
@@ -50,12 +57,15 @@ type Message interface {
 	To() *common.Address
 
 	GasPrice() *big.Int
+	GasFeeCap() *big.Int
+	GasTipCap() *big.Int
 	Gas() uint64
 	Value() *big.Int
 
 	Nonce() uint64
 	CheckNonce() bool
 	Data() []byte
+	AccessList() types.AccessList
 }
 
 // ExecutionResult includes all output after executing given evm
@@ -93,8 +103,9 @@ func (result *ExecutionResult) Revert() []byte {
 	return common.CopyBytes(result.ReturnData)
 }
 
-// IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(data []byte, contractCreation bool, state vm.StateDB) (uint64, error) {
+// IntrinsicGas computes the 'intrinsic gas' for a message with the given data
+// and access list.
+func IntrinsicGas(data []byte, accessList types.AccessList, contractCreation bool, state vm.StateDB) (uint64, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if contractCreation {
@@ -133,19 +144,25 @@ func IntrinsicGas(data []byte, contractCreation bool, state vm.StateDB) (uint64,
 		}
 		gas += z * zeroGas
 	}
+	if accessList != nil {
+		gas += uint64(len(accessList)) * configs.TxAccessListAddressGas
+		gas += uint64(accessList.StorageKeys()) * configs.TxAccessListStorageKeyGas
+	}
 	return gas, nil
 }
 
 // NewStateTransition initialises and returns a new state transition object.
 func NewStateTransition(evm *vm.EVM, msg Message, gp *GasPool) *StateTransition {
 	return &StateTransition{
-		gp:       gp,
-		evm:      evm,
-		msg:      msg,
-		gasPrice: msg.GasPrice(),
-		value:    msg.Value(),
-		data:     msg.Data(),
-		state:    evm.StateDB,
+		gp:        gp,
+		evm:       evm,
+		msg:       msg,
+		gasPrice:  msg.GasPrice(),
+		gasFeeCap: msg.GasFeeCap(),
+		gasTipCap: msg.GasTipCap(),
+		value:     msg.Value(),
+		data:      msg.Data(),
+		state:     evm.StateDB,
 	}
 }
 
@@ -170,7 +187,13 @@ func (st *StateTransition) to() common.Address {
 
 func (st *StateTransition) buyGas() error {
 	mgval := new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), st.gasPrice)
-	if st.state.GetBalance(st.msg.From()).Cmp(mgval) < 0 {
+	// The balance must cover the fee cap, even though only the effective
+	// gas price is charged.
+	balanceCheck := mgval
+	if st.gasFeeCap != nil {
+		balanceCheck = new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), st.gasFeeCap)
+	}
+	if st.state.GetBalance(st.msg.From()).Cmp(balanceCheck) < 0 {
 		return errInsufficientBalanceForGas
 	}
 	if err := st.gp.SubGas(st.msg.Gas()); err != nil {
@@ -193,6 +216,21 @@ func (st *StateTransition) preCheck() error {
 			return ErrNonceTooLow
 		}
 	}
+	// Make sure that transaction gasFeeCap is greater than the baseFee (post dynamic fee fork)
+	if st.evm.BaseFee != nil && st.gasFeeCap != nil && st.gasTipCap != nil {
+		// Skip the checks if gas fields are zero in calls that do not check the
+		// nonce either (eth_call, eth_estimateGas)
+		if st.msg.CheckNonce() || st.gasFeeCap.BitLen() > 0 || st.gasTipCap.BitLen() > 0 {
+			if st.gasFeeCap.Cmp(st.gasTipCap) < 0 {
+				return fmt.Errorf("%w: address %v, maxPriorityFeePerGas: %s, maxFeePerGas: %s", ErrTipAboveFeeCap,
+					st.msg.From().Hex(), st.gasTipCap, st.gasFeeCap)
+			}
+			if st.gasFeeCap.Cmp(st.evm.BaseFee) < 0 {
+				return fmt.Errorf("%w: address %v, maxFeePerGas: %s baseFee: %s", ErrFeeCapTooLow,
+					st.msg.From().Hex(), st.gasFeeCap, st.evm.BaseFee)
+			}
+		}
+	}
 	return st.buyGas()
 }
 
@@ -214,7 +252,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	contractCreation := msg.To() == nil
 
 	// Pay intrinsic gas
-	gas, err := IntrinsicGas(st.data, contractCreation, st.state)
+	gas, err := IntrinsicGas(st.data, msg.AccessList(), contractCreation, st.state)
 	if err != nil {
 		return nil, err
 	}
@@ -224,6 +262,11 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	}
 	st.gas -= gas
 
+	// Warm the accounts and storage slots known before the execution.
+	if st.evm.ChainConfig().IsAccessList(st.evm.BlockNumber) {
+		st.state.PrepareAccessList(msg.From(), msg.To(), vm.ActivePrecompiles(), msg.AccessList())
+	}
+
 	// Limit the time it takes for a virtual machine to execute the smart contract,
 	// Except precompiled contracts.
 	ctx := context.Background()
@@ -274,7 +317,15 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 
 	st.refundGas()
 
-	st.state.AddBalance(st.evm.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice))
+	// After the dynamic fee fork the block producer only earns the tip, the
+	// base fee is burnt or paid to the community developer foundation.
+	effectiveTip := st.gasPrice
+	if st.evm.BaseFee != nil {
+		baseFee := cmath.BigMin(st.gasPrice, st.evm.BaseFee)
+		effectiveTip = new(big.Int).Sub(st.gasPrice, baseFee)
+		settleBaseFee(st.state, st.evm.BlockNumber, st.evm.BlockHash, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), baseFee))
+	}
+	st.state.AddBalance(st.evm.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), effectiveTip))
 
 	return &ExecutionResult{
 		UsedGas:    st.gasUsed(),
@@ -300,6 +351,25 @@ func (st *StateTransition) refundGas() {
 	st.gp.AddGas(st.gas)
 }
 
+// settleBaseFee burns the governed share of the base fee paid in block number
+// and credits the rest to the account of the community developer foundation,
+// not to the community treasury.
+func settleBaseFee(state vm.StateDB, number *big.Int, blockHash common.Hash, fee *big.Int) {
+	if fee.Sign() == 0 {
+		return
+	}
+	ratio, err := gov.GovernBaseFeeBurnRatio(number.Uint64(), blockHash)
+	if nil != err {
+		log.Warn("cannot find BaseFeeBurnRatio from govern", "blockNumber", number, "err", err)
+		ratio = gov.DefaultBaseFeeBurnRatio
+	}
+	burnt := new(big.Int).Mul(fee, big.NewInt(int64(ratio)))
+	burnt.Div(burnt, big.NewInt(100))
+	if foundationShare := new(big.Int).Sub(fee, burnt); foundationShare.Sign() > 0 {
+		state.AddBalance(xcom.CDFAccount(), foundationShare)
+	}
+}
+
 // gasUsed returns the amount of gas used up by the state transition.
 func (st *StateTransition) gasUsed() uint64 {
 	return st.initialGas - st.gas
@@ -266,6 +266,8 @@ type TxPool struct {
 	currentState  *state.StateDB // Current state in the blockchain head
 	pendingNonces *txNoncer      // Pending state tracking virtual nonces
 	currentMaxGas uint64         // Current gas limit for transaction caps
+	dynamicFee    bool           // Fork indicator whether we are using dynamic fee transactions
+	accessList    bool           // Fork indicator whether we are using access list transactions
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
@@ -481,6 +483,7 @@ func (pool *TxPool) ForkedReset(newHeader *types.Header, rollback []*types.Block
 	pool.currentState = statedb
 	pool.pendingNonces = newTxNoncer(statedb)
 	pool.currentMaxGas = newHeader.GasLimit
+	pool.updateBaseFee(newHeader)
 
 	// Inject any transactions discarded due to reorgs
 	t := time.Now()
@@ -667,6 +670,13 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if tx.Size() > 1024*1024 {
 		return ErrOversizedData
 	}
+	// Reject typed transactions until their fork activates.
+	if !pool.dynamicFee && tx.Type() == types.DynamicFeeTxType {
+		return ErrTxTypeNotSupported
+	}
+	if !pool.accessList && (tx.Type() == types.AccessListTxType || len(tx.AccessList()) > 0) {
+		return ErrTxTypeNotSupported
+	}
 	// Transactions can't be negative. This may never happen using RLP decoded
 	// transactions but may occur if you create a transaction using the RPC.
 	if tx.Value().Sign() < 0 {
@@ -676,14 +686,18 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if pool.currentMaxGas < tx.Gas() {
 		return ErrGasLimit
 	}
+	// Ensure gasTipCap is less than or equal to gasFeeCap.
+	if tx.GasFeeCapIntCmp(tx.GasTipCap()) < 0 {
+		return ErrTipAboveFeeCap
+	}
 	// Make sure the transaction is signed properly
 	from, err := types.Sender(pool.signer, tx)
 	if err != nil {
 		return ErrInvalidSender
 	}
-	// Drop non-local transactions under our own minimal accepted gas price
+	// Drop non-local transactions under our own minimal accepted gas price or tip
 	local = local || pool.locals.contains(from) // account may be local even if the transaction arrived from the network
-	if !local && tx.GasPriceIntCmp(pool.gasPrice) < 0 {
+	if !local && tx.GasTipCapIntCmp(pool.gasPrice) < 0 {
 		return ErrUnderpriced
 	}
 	// Ensure the transaction adheres to nonce ordering
@@ -691,11 +705,11 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		return ErrNonceTooLow
 	}
 	// Transactor should have enough funds to cover the costs
-	// cost == V + GP * GL
+	// cost == V + GP * GL, the fee cap being GP of dynamic fee transactions
 	if pool.currentState.GetBalance(from).Cmp(tx.Cost()) < 0 {
 		return ErrInsufficientFunds
 	}
-	intrGas, err := IntrinsicGas(tx.Data(), tx.To() == nil, pool.currentState)
+	intrGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, pool.currentState)
 	if err != nil {
 		return err
 	}
@@ -1385,6 +1399,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.currentState = statedb
 	pool.pendingNonces = newTxNoncer(statedb)
 	pool.currentMaxGas = newHead.GasLimit
+	pool.updateBaseFee(newHead)
 	// Inject any transactions discarded due to reorgs
 	t := time.Now()
 	SenderCacher.recover(pool.signer, reinject)
@@ -1393,6 +1408,17 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 
 }
 
+// updateBaseFee updates the fork indicators of the typed transactions and the
+// base fee the pending transactions are sorted by for the block after head.
+func (pool *TxPool) updateBaseFee(head *types.Header) {
+	next := new(big.Int).Add(head.Number, big.NewInt(1))
+	pool.accessList = pool.chainconfig.IsAccessList(next)
+	pool.dynamicFee = pool.chainconfig.IsDynamicFee(next)
+	if pool.dynamicFee {
+		pool.priced.SetBaseFee(CalcBaseFee(pool.chainconfig, head))
+	}
+}
+
 // promoteExecutables moves transactions that have become processable from the
 // future queue to the set of pending transactions. During this process, all
 // invalidated transactions (low nonce, low balance) are deleted.
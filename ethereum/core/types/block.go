@@ -73,6 +73,9 @@ type Header struct {
 	Extra       []byte         `json:"extraData"        gencodec:"required"`
 	Nonce       BlockNonce     `json:"nonce"            gencodec:"required"`
 
+	// BaseFee was added by the dynamic fee fork and is ignored in legacy headers.
+	BaseFee *big.Int `json:"baseFeePerGas" rlp:"optional"`
+
 	// caches
 	sealHash  atomic.Value `json:"-" rlp:"-"`
 	hash      atomic.Value `json:"-" rlp:"-"`
@@ -94,6 +97,7 @@ func (h Header) MarshalJSON2() ([]byte, error) {
 		Time        hexutil.Uint64 `json:"timestamp"        gencodec:"required"`
 		Extra       hexutil.Bytes  `json:"extraData"        gencodec:"required"`
 		Nonce       BlockNonce     `json:"nonce"            gencodec:"required"`
+		BaseFee     *hexutil.Big   `json:"baseFeePerGas" rlp:"optional"`
 		Hash        common.Hash    `json:"hash"`
 	}
 	var enc Header
@@ -109,6 +113,7 @@ func (h Header) MarshalJSON2() ([]byte, error) {
 	enc.Time = hexutil.Uint64(h.Time)
 	enc.Extra = h.Extra
 	enc.Nonce = h.Nonce
+	enc.BaseFee = (*hexutil.Big)(h.BaseFee)
 	enc.Hash = h.Hash()
 	return json2.Marshal(&enc)
 }
@@ -120,6 +125,7 @@ type headerMarshaling struct {
 	GasUsed  hexutil.Uint64
 	Time     hexutil.Uint64
 	Extra    hexutil.Bytes
+	BaseFee  *hexutil.Big
 	Hash     common.Hash `json:"hash"` // adds call to Hash() in MarshalJSON
 }
 
@@ -173,7 +179,7 @@ func (h *Header) _sealHash() (hash common.Hash) {
 	if len(h.Extra) > 32 {
 		extra = h.Extra[0:32]
 	}
-	rlp.Encode(hasher, []interface{}{
+	enc := []interface{}{
 		h.ParentHash,
 		h.Coinbase,
 		h.Root,
@@ -186,7 +192,11 @@ func (h *Header) _sealHash() (hash common.Hash) {
 		h.Time,
 		extra,
 		h.Nonce,
-	})
+	}
+	if h.BaseFee != nil {
+		enc = append(enc, h.BaseFee)
+	}
+	rlp.Encode(hasher, enc)
 
 	hasher.Sum(hash[:0])
 	return hash
@@ -234,6 +244,18 @@ func rlpHash(x interface{}) (h common.Hash) {
 	return h
 }
 
+// prefixedRlpHash writes the prefix into the hasher before rlp-encoding x.
+// It's used for typed transactions.
+func prefixedRlpHash(prefix byte, x interface{}) (h common.Hash) {
+	sha := hasherPool.Get().(crypto.KeccakState)
+	defer hasherPool.Put(sha)
+	sha.Reset()
+	sha.Write([]byte{prefix})
+	rlp.Encode(sha, x)
+	sha.Read(h[:])
+	return h
+}
+
 // Body is a simple (mutable, non-safe) data container for storing and moving
 // a block's data contents (transactions) together.
 type Body struct {
@@ -336,6 +358,9 @@ func CopyHeader(h *Header) *Header {
 		cpy.Extra = make([]byte, len(h.Extra))
 		copy(cpy.Extra, h.Extra)
 	}
+	if h.BaseFee != nil {
+		cpy.BaseFee = new(big.Int).Set(h.BaseFee)
+	}
 	return &cpy
 }
 
@@ -399,6 +424,15 @@ func (b *Block) TxHash() common.Hash      { return b.header.TxHash }
 func (b *Block) ReceiptHash() common.Hash { return b.header.ReceiptHash }
 func (b *Block) Extra() []byte            { return common.CopyBytes(b.header.Extra) }
 
+// BaseFee returns the base fee per gas of the block, nil before the dynamic
+// fee fork.
+func (b *Block) BaseFee() *big.Int {
+	if b.header.BaseFee == nil {
+		return nil
+	}
+	return new(big.Int).Set(b.header.BaseFee)
+}
+
 func (b *Block) Header() *Header { return CopyHeader(b.header) }
 
 // Body returns the non-header content of the block.
@@ -39,7 +39,7 @@ func TestBlockEncoding(t *testing.T) {
 
 	tx1, _ = tx1.WithSignature(NewEIP155Signer(new(big.Int)), common.Hex2Bytes("9bea4c4daac7c7c52e093e6a4c35dbbcf8856f1af7b059ba20253e70848d094f8a8fae537ce25ed8cb5af9adac3f141af69bd515bd2ba031522df09b97dd72b100"))
 	fmt.Println(block.Transactions()[0].Hash())
-	fmt.Println(tx1.data)
+	fmt.Println(tx1.inner)
 	fmt.Println(tx1.Hash())
 	check("len(Transactions)", len(block.Transactions()), 1)
 	check("Transactions[0].Hash", block.Transactions()[0].Hash(), tx1.Hash())
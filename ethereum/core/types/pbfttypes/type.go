@@ -86,6 +86,7 @@ type ValidateNode struct {
 	PubKey    *ecdsa.PublicKey   `json:"-"`
 	NodeID    discover.NodeID    `json:"nodeID"`
 	BlsPubKey *bls.PublicKey     `json:"blsPubKey"`
+	Weight    *big.Int           `json:"weight,omitempty"` // Stake of the validator, nil if the validators are weighted equally
 }
 
 type ValidateNodeMap map[discover.NodeID]*ValidateNode
@@ -103,6 +104,18 @@ type Validators struct {
 	sortedNodes SortedValidatorNode
 }
 
+// NewValidators creates the validator set of nodes and sorts it by index
+// up front, so that the lookups by index never write to the set and it can
+// be shared between goroutines.
+func NewValidators(nodes ValidateNodeMap, validBlockNumber uint64) *Validators {
+	vs := &Validators{
+		Nodes:            nodes,
+		ValidBlockNumber: validBlockNumber,
+	}
+	vs.sort()
+	return vs
+}
+
 func (vn *ValidateNode) String() string {
 	b, _ := json.Marshal(vn)
 	return string(b)
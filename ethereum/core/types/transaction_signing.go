@@ -22,7 +22,6 @@ type sigCache struct {
 	from   common.Address
 }
 
-// todo we may  support EIP2930 soon
 // MakeSigner returns a Signer based on the given chain config and block number.
 func MakeSigner(config *configs.ChainConfig, blockNumber *big.Int) Signer {
 	var signer Signer
@@ -80,13 +79,18 @@ type Signer interface {
 	SignatureValues(tx *Transaction, sig []byte) (r, s, v *big.Int, err error)
 	// Hash returns the hash to be signed.
 	Hash(tx *Transaction) common.Hash
+	// ChainID returns the chain id the signer signs for.
+	ChainID() *big.Int
 	// Equal returns true if the given signer is the same as the receiver.
 	Equal(Signer) bool
 	// Signature return the sig info of the transaction
 	//	SignatureAndSender(tx *Transaction) (common.Address, []byte, error)
 }
 
-// EIP155Transaction implements Signer using the EIP155 rules.
+// EIP155Signer implements Signer using the EIP155 rules. It also accepts the
+// dynamic fee transactions, which carry their chain id and sign the typed
+// envelope with a plain 0/1 recovery id. Whether such transactions are valid
+// at a given height is up to the fork rules of the chain config.
 type EIP155Signer struct {
 	chainId, chainIdMul *big.Int
 }
@@ -101,6 +105,10 @@ func NewEIP155Signer(chainId *big.Int) EIP155Signer {
 	}
 }
 
+func (s EIP155Signer) ChainID() *big.Int {
+	return s.chainId
+}
+
 func (s EIP155Signer) Equal(s2 Signer) bool {
 	eip155, ok := s2.(EIP155Signer)
 	return ok && eip155.chainId.Cmp(s.chainId) == 0
@@ -109,13 +117,31 @@ func (s EIP155Signer) Equal(s2 Signer) bool {
 var big8 = big.NewInt(8)
 
 func (s EIP155Signer) Sender(tx *Transaction) (common.Address, error) {
+	V, R, S, err := s.recoveryValues(tx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return recoverPlain(s.Hash(tx), R, S, V, true)
+}
 
+// recoveryValues returns the signature values of tx with V shifted to the
+// 27/28 range expected by recoverPlain.
+func (s EIP155Signer) recoveryValues(tx *Transaction) (V, R, S *big.Int, err error) {
 	if tx.ChainId().Cmp(s.chainId) != 0 {
-		return common.Address{}, ErrInvalidChainId
+		return nil, nil, nil, ErrInvalidChainId
 	}
-	V := new(big.Int).Sub(tx.data.V, s.chainIdMul)
-	V.Sub(V, big8)
-	return recoverPlain(s.Hash(tx), tx.data.R, tx.data.S, V, true)
+	V, R, S = tx.RawSignatureValues()
+	switch tx.Type() {
+	case LegacyTxType:
+		V = new(big.Int).Sub(V, s.chainIdMul)
+		V.Sub(V, big8)
+	case DynamicFeeTxType, AccessListTxType:
+		// Typed transactions use 0 and 1 as recovery id.
+		V = new(big.Int).Add(V, big.NewInt(27))
+	default:
+		return nil, nil, nil, ErrTxTypeNotSupported
+	}
+	return V, R, S, nil
 }
 
 // WithSignature returns a new transaction with the given signature. This signature
@@ -126,34 +152,73 @@ func (s EIP155Signer) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big
 	}
 	R = new(big.Int).SetBytes(sig[:32])
 	S = new(big.Int).SetBytes(sig[32:64])
-	V = new(big.Int).SetBytes([]byte{sig[64] + 35})
-	V.Add(V, s.chainIdMul)
-
+	switch tx.Type() {
+	case LegacyTxType:
+		V = new(big.Int).SetBytes([]byte{sig[64] + 35})
+		V.Add(V, s.chainIdMul)
+	case DynamicFeeTxType, AccessListTxType:
+		// Check that chain ID of tx matches the signer. We also accept ID zero here,
+		// because it indicates that the chain ID was not specified in the tx.
+		if txChainID := tx.ChainId(); txChainID.Sign() != 0 && txChainID.Cmp(s.chainId) != 0 {
+			return nil, nil, nil, ErrInvalidChainId
+		}
+		V = new(big.Int).SetBytes([]byte{sig[64]})
+	default:
+		return nil, nil, nil, ErrTxTypeNotSupported
+	}
 	return R, S, V, nil
 }
 
 // Hash returns the hash to be signed by the sender.
 // It does not uniquely identify the transaction.
 func (s EIP155Signer) Hash(tx *Transaction) common.Hash {
-	return rlpHash([]interface{}{
-		tx.data.AccountNonce,
-		tx.data.Price,
-		tx.data.GasLimit,
-		tx.data.Recipient,
-		tx.data.Amount,
-		tx.data.Payload,
-		s.chainId, uint(0), uint(0),
-	})
+	switch tx.Type() {
+	case DynamicFeeTxType:
+		return prefixedRlpHash(
+			tx.Type(),
+			[]interface{}{
+				s.chainId,
+				tx.Nonce(),
+				tx.GasTipCap(),
+				tx.GasFeeCap(),
+				tx.Gas(),
+				tx.To(),
+				tx.Value(),
+				tx.Data(),
+				tx.AccessList(),
+			})
+	case AccessListTxType:
+		return prefixedRlpHash(
+			tx.Type(),
+			[]interface{}{
+				s.chainId,
+				tx.Nonce(),
+				tx.GasPrice(),
+				tx.Gas(),
+				tx.To(),
+				tx.Value(),
+				tx.Data(),
+				tx.AccessList(),
+			})
+	default:
+		return rlpHash([]interface{}{
+			tx.Nonce(),
+			tx.GasPrice(),
+			tx.Gas(),
+			tx.To(),
+			tx.Value(),
+			tx.Data(),
+			s.chainId, uint(0), uint(0),
+		})
+	}
 }
 
 func (s EIP155Signer) SignatureAndSender(tx *Transaction) (common.Address, []byte, error) {
-
-	if tx.ChainId().Cmp(s.chainId) != 0 {
-		return common.Address{}, []byte{}, ErrInvalidChainId
+	V, R, S, err := s.recoveryValues(tx)
+	if err != nil {
+		return common.Address{}, []byte{}, err
 	}
-	V := new(big.Int).Sub(tx.data.V, s.chainIdMul)
-	V.Sub(V, big8)
-	return recoverPubKeyAndSender(s.Hash(tx), tx.data.R, tx.data.S, V, true)
+	return recoverPubKeyAndSender(s.Hash(tx), R, S, V, true)
 }
 
 func recoverPlain(sighash common.Hash, R, S, Vb *big.Int, homestead bool) (common.Address, error) {
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
@@ -81,7 +82,7 @@ func EncodeRecipientEmpty() (string, error) {
 		return "", err
 	}
 	buffer := new(bytes.Buffer)
-	err = rlp.Encode(buffer, tx.data)
+	err = rlp.Encode(buffer, tx.inner)
 	if err != nil {
 		fmt.Println("geninput fail.", err)
 		return "", err
@@ -137,7 +138,7 @@ func TestRecipientNormal(t *testing.T) {
 		t.FailNow()
 	}
 	buffer := new(bytes.Buffer)
-	err := rlp.Encode(buffer, txtmp.data)
+	err := rlp.Encode(buffer, txtmp.inner)
 	if err != nil {
 		fmt.Println("geninput fail.", err)
 	}
@@ -183,7 +184,7 @@ func TestTransactionPriceNonceSort(t *testing.T) {
 		}
 	}
 	// Sort the transactions and cross check the nonce ordering
-	txset := NewTransactionsByPriceAndNonce(signer, groups)
+	txset := NewTransactionsByPriceAndNonce(signer, groups, nil)
 
 	txs := Transactions{}
 	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
@@ -216,6 +217,118 @@ func TestTransactionPriceNonceSort(t *testing.T) {
 	}
 }
 
+// Tests that transactions are sorted by their effective tip once a base fee
+// applies, and that senders unable to pay the base fee are skipped.
+func TestTransactionTipSort(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 10)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	signer := NewEIP155Signer(common.Big1)
+	baseFee := big.NewInt(5)
+
+	groups := map[common.Address]Transactions{}
+	for start, key := range keys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		tx, _ := SignTx(NewTx(&DynamicFeeTx{
+			ChainID:   common.Big1,
+			Nonce:     0,
+			GasTipCap: big.NewInt(int64(start)),
+			GasFeeCap: big.NewInt(int64(start * 2)),
+			Gas:       21000,
+			To:        &common.Address{},
+			Value:     big.NewInt(100),
+		}), signer, key)
+		groups[addr] = append(groups[addr], tx)
+	}
+	txset := NewTransactionsByPriceAndNonce(signer, groups, baseFee)
+
+	txs := Transactions{}
+	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+		txs = append(txs, tx)
+		txset.Shift()
+	}
+	// Fee caps below the base fee are those of start 0, 1 and 2
+	if len(txs) != len(keys)-3 {
+		t.Fatalf("expected %d transactions, found %d", len(keys)-3, len(txs))
+	}
+	for i := 1; i < len(txs); i++ {
+		if txs[i-1].EffectiveGasTipCmp(txs[i], baseFee) < 0 {
+			t.Errorf("invalid tip ordering: tx #%d (T=%v) < tx #%d (T=%v)", i-1,
+				txs[i-1].EffectiveGasTipValue(baseFee), i, txs[i].EffectiveGasTipValue(baseFee))
+		}
+	}
+}
+
+// Tests that dynamic fee transactions survive the binary and RLP encodings
+// and recover their sender.
+func TestDynamicFeeTxEncoding(t *testing.T) {
+	key, addr := defaultTestKey()
+	signer := NewEIP155Signer(big.NewInt(100))
+
+	accesses := AccessList{{Address: common.Address{2}, StorageKeys: []common.Hash{{1}}}}
+	tx, err := SignTx(NewTx(&DynamicFeeTx{
+		Nonce:      3,
+		GasTipCap:  big.NewInt(2),
+		GasFeeCap:  big.NewInt(10),
+		Gas:        21000,
+		To:         &common.Address{1},
+		Value:      big.NewInt(10),
+		Data:       []byte("abcdef"),
+		AccessList: accesses,
+	}), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	if tx.Type() != DynamicFeeTxType || tx.ChainId().Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("unexpected type %d or chain id %v", tx.Type(), tx.ChainId())
+	}
+
+	bin, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("binary encoding failed: %v", err)
+	}
+	if bin[0] != 0x02 {
+		t.Fatalf("binary encoding is missing the type prefix: %x", bin)
+	}
+	parsed := new(Transaction)
+	if err := parsed.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("binary decoding failed: %v", err)
+	}
+	if parsed.Hash() != tx.Hash() {
+		t.Errorf("binary round trip changed the hash: have %v, want %v", parsed.Hash(), tx.Hash())
+	}
+	if !reflect.DeepEqual(parsed.AccessList(), accesses) {
+		t.Errorf("access list mismatch: have %v, want %v", parsed.AccessList(), accesses)
+	}
+
+	enc, err := rlp.EncodeToBytes(Transactions{tx})
+	if err != nil {
+		t.Fatalf("rlp encoding failed: %v", err)
+	}
+	var decoded Transactions
+	if err := rlp.DecodeBytes(enc, &decoded); err != nil {
+		t.Fatalf("rlp decoding failed: %v", err)
+	}
+	if decoded[0].Hash() != tx.Hash() {
+		t.Errorf("rlp round trip changed the hash: have %v, want %v", decoded[0].Hash(), tx.Hash())
+	}
+
+	from, err := Sender(signer, decoded[0])
+	if err != nil {
+		t.Fatalf("could not recover sender: %v", err)
+	}
+	if from != addr {
+		t.Errorf("derived address doesn't match: have %v, want %v", from, addr)
+	}
+	if _, err := Sender(NewEIP155Signer(big.NewInt(1)), decoded[0]); err != ErrInvalidChainId {
+		t.Errorf("expected %v for a foreign chain, got %v", ErrInvalidChainId, err)
+	}
+	if price := tx.EffectiveGasPrice(big.NewInt(9)); price.Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("effective gas price is not capped: have %v, want 10", price)
+	}
+}
+
 // TestTransactionJSON tests serializing/de-serializing to/from JSON.
 func TestTransactionJSON(t *testing.T) {
 	key, err := crypto.GenerateKey()
@@ -226,11 +339,22 @@ func TestTransactionJSON(t *testing.T) {
 
 	for i := uint64(0); i < 25; i++ {
 		var tx *Transaction
-		switch i % 2 {
+		switch i % 3 {
 		case 0:
 			tx = NewTransaction(i, common.Address{1}, common.Big0, 1, common.Big2, []byte("abcdef"))
 		case 1:
 			tx = NewContractCreation(i, common.Big0, 1, common.Big2, []byte("abcdef"))
+		case 2:
+			tx = NewTx(&DynamicFeeTx{
+				ChainID:   common.Big1,
+				Nonce:     i,
+				GasTipCap: common.Big1,
+				GasFeeCap: common.Big3,
+				Gas:       1,
+				To:        &common.Address{1},
+				Value:     common.Big0,
+				Data:      []byte("abcdef"),
+			})
 		}
 
 		tx, err := SignTx(tx, signer, key)
@@ -257,3 +381,102 @@ func TestTransactionJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestAccessListTxEncoding(t *testing.T) {
+	key, addr := defaultTestKey()
+	signer := NewEIP155Signer(big.NewInt(100))
+
+	accesses := AccessList{{Address: common.Address{2}, StorageKeys: []common.Hash{{1}, {2}}}}
+	tx, err := SignTx(NewTx(&AccessListTx{
+		Nonce:      1,
+		GasPrice:   big.NewInt(5),
+		Gas:        50000,
+		To:         &common.Address{1},
+		Value:      big.NewInt(10),
+		AccessList: accesses,
+	}), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	if tx.Type() != AccessListTxType || tx.AccessList().StorageKeys() != 2 {
+		t.Fatalf("unexpected type %d or access list %v", tx.Type(), tx.AccessList())
+	}
+
+	bin, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("binary encoding failed: %v", err)
+	}
+	parsed := new(Transaction)
+	if err := parsed.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("binary decoding failed: %v", err)
+	}
+	if parsed.Hash() != tx.Hash() {
+		t.Errorf("binary round trip changed the hash: have %v, want %v", parsed.Hash(), tx.Hash())
+	}
+	if !reflect.DeepEqual(parsed.AccessList(), accesses) {
+		t.Errorf("access list mismatch: have %v, want %v", parsed.AccessList(), accesses)
+	}
+
+	data, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("json encoding failed: %v", err)
+	}
+	var decoded Transaction
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json decoding failed: %v", err)
+	}
+	if decoded.Hash() != tx.Hash() {
+		t.Errorf("json round trip changed the hash: have %v, want %v", decoded.Hash(), tx.Hash())
+	}
+
+	from, err := Sender(signer, &decoded)
+	if err != nil {
+		t.Fatalf("could not recover sender: %v", err)
+	}
+	if from != addr {
+		t.Errorf("derived address doesn't match: have %v, want %v", from, addr)
+	}
+}
+
+// Tests that an access list transaction encoded by other EIP-2930 clients
+// decodes, hashes for signing and re-encodes to the very same bytes. The
+// vector is the one used by the go-ethereum transaction tests.
+func TestAccessListTxVector(t *testing.T) {
+	raw := common.FromHex("01f8630103018261a894b94f5374fce5edbc8e2a8697c15331677e6ebf0b0a825544c001a0c9519f4f2b30335884581971573fadf60c6204f59a911df35ee8a540456b2660a032f1e8e2c5dd761f9e4f88f41c8310aeaba26a8bfcdacfedfa12ec3862d37521")
+
+	tx := new(Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		t.Fatalf("binary decoding failed: %v", err)
+	}
+	if tx.Type() != AccessListTxType || AccessListTxType != 0x01 {
+		t.Fatalf("decoded as type %d, want access list type 0x01", tx.Type())
+	}
+	if tx.ChainId().Cmp(common.Big1) != 0 || tx.Nonce() != 3 || tx.Gas() != 25000 || tx.Value().Cmp(big.NewInt(10)) != 0 {
+		t.Fatalf("unexpected fields: chain id %v, nonce %d, gas %d, value %v", tx.ChainId(), tx.Nonce(), tx.Gas(), tx.Value())
+	}
+	if want := common.HexToAddress("b94f5374fce5edbc8e2a8697c15331677e6ebf0b"); *tx.To() != want {
+		t.Errorf("recipient mismatch: have %v, want %v", tx.To(), want)
+	}
+	if !bytes.Equal(tx.Data(), common.FromHex("5544")) {
+		t.Errorf("payload mismatch: have %x, want 5544", tx.Data())
+	}
+
+	signer := NewEIP155Signer(common.Big1)
+	if h, want := signer.Hash(tx), common.HexToHash("49b486f0ec0a60dfbbca2d30cb07c9e8ffb2a2ff41f29a1ab6737475f6ff69f3"); h != want {
+		t.Errorf("signing hash mismatch: have %v, want %v", h, want)
+	}
+	if h, want := tx.Hash(), crypto.Keccak256Hash(raw); h != want {
+		t.Errorf("transaction hash mismatch: have %v, want %v", h, want)
+	}
+	if _, err := Sender(signer, tx); err != nil {
+		t.Errorf("could not recover sender: %v", err)
+	}
+
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("binary encoding failed: %v", err)
+	}
+	if !bytes.Equal(enc, raw) {
+		t.Errorf("re-encoding mismatch:\nhave %x\nwant %x", enc, raw)
+	}
+}
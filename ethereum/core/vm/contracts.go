@@ -122,6 +122,19 @@ func IsPrecompiledContract(addr common.Address) bool {
 	}
 }
 
+// ActivePrecompiles returns the addresses of the precompiled contracts, which
+// are warm from the start of a transaction after the access list fork.
+func ActivePrecompiles() []common.Address {
+	addrs := make([]common.Address, 0, len(PrecompiledContractsByzantium)+len(PhoenixChainPrecompiledContracts))
+	for addr := range PrecompiledContractsByzantium {
+		addrs = append(addrs, addr)
+	}
+	for addr := range PhoenixChainPrecompiledContracts {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
 type PrecompiledContractCheck struct{}
 
 func (pcc *PrecompiledContractCheck) IsPhoenixChainPrecompiledContract(address common.Address) bool {
@@ -75,3 +75,31 @@ func enable2315(jt *JumpTable) {
 		jumps:       true,
 	}
 }
+
+// enableAccessList applies the access list pricing to the given jump table.
+// The first access to an account or a storage slot in a transaction keeps its
+// price, the following ones cost WarmStorageReadCost. The accounts and slots
+// declared by the access list of the transaction are warm from the start.
+func enableAccessList(jt *JumpTable) {
+	jt[SLOAD].constantGas = configs.WarmStorageReadCost
+	jt[SLOAD].dynamicGas = gasSLoadAccessList
+	jt[SSTORE].dynamicGas = makeGasSStoreAccessList(jt[SSTORE].dynamicGas)
+
+	jt[BALANCE].constantGas = configs.WarmStorageReadCost
+	jt[BALANCE].dynamicGas = gasAccountAccessList
+	jt[EXTCODESIZE].constantGas = configs.WarmStorageReadCost
+	jt[EXTCODESIZE].dynamicGas = gasAccountAccessList
+	jt[EXTCODEHASH].constantGas = configs.WarmStorageReadCost
+	jt[EXTCODEHASH].dynamicGas = gasAccountAccessList
+	jt[EXTCODECOPY].constantGas = configs.WarmStorageReadCost
+	jt[EXTCODECOPY].dynamicGas = gasExtCodeCopyAccessList
+
+	jt[CALL].constantGas = configs.WarmStorageReadCost
+	jt[CALL].dynamicGas = makeCallVariantGasCallAccessList(gasCall)
+	jt[CALLCODE].constantGas = configs.WarmStorageReadCost
+	jt[CALLCODE].dynamicGas = makeCallVariantGasCallAccessList(gasCallCode)
+	jt[DELEGATECALL].constantGas = configs.WarmStorageReadCost
+	jt[DELEGATECALL].dynamicGas = makeCallVariantGasCallAccessList(gasDelegateCall)
+	jt[STATICCALL].constantGas = configs.WarmStorageReadCost
+	jt[STATICCALL].dynamicGas = makeCallVariantGasCallAccessList(gasStaticCall)
+}
@@ -40,6 +40,10 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 		}
 
 		if p := PhoenixChainPrecompiledContracts[*contract.CodeAddr]; p != nil {
+			if evm.vmConfig.Speculative {
+				evm.Cancel()
+				return nil, ErrAbort
+			}
 			switch p.(type) {
 
 			case *validatorInnerContract:
@@ -131,6 +135,7 @@ type Context struct {
 	BlockNumber *big.Int       // Provides information for NUMBER
 	Time        *big.Int       // Provides information for TIME
 	Difficulty  *big.Int       // Provides information for DIFFICULTY  (This one must not be deleted, otherwise the solidity contract will be failed)
+	BaseFee     *big.Int       // Base fee per gas of the block, nil before the dynamic fee fork
 
 	BlockHash common.Hash // Only, the value will be available after the current block has been sealed.
 
@@ -433,6 +438,10 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	}
 	nonce := evm.StateDB.GetNonce(caller.Address())
 	evm.StateDB.SetNonce(caller.Address(), nonce+1)
+	// The created address is warm from now on, even if the creation fails.
+	if evm.chainConfig.IsAccessList(evm.BlockNumber) {
+		evm.StateDB.AddAddressToAccessList(address)
+	}
 
 	// Ensure there's no existing contract already at the designated address
 	contractHash := evm.StateDB.GetCodeHash(address)
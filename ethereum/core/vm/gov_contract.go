@@ -8,10 +8,12 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/gov"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/plugin"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/restricting"
 )
 
 const (
@@ -21,6 +23,7 @@ const (
 	Vote                  = uint16(2003)
 	Declare               = uint16(2004)
 	SubmitCancel          = uint16(2005)
+	SubmitTreasury        = uint16(2006)
 	GetProposal           = uint16(2100)
 	GetResult             = uint16(2101)
 	ListProposal          = uint16(2102)
@@ -28,6 +31,7 @@ const (
 	GetGovernParamValue   = uint16(2104)
 	GetAccuVerifiersCount = uint16(2105)
 	ListGovernParam       = uint16(2106)
+	GetTreasuryBalance    = uint16(2107)
 )
 
 var (
@@ -57,12 +61,13 @@ func (gc *GovContract) Run(input []byte) ([]byte, error) {
 func (gc *GovContract) FnSigns() map[uint16]interface{} {
 	return map[uint16]interface{}{
 		// Set
-		SubmitText:    gc.submitText,
-		SubmitVersion: gc.submitVersion,
-		Vote:          gc.vote,
-		Declare:       gc.declareVersion,
-		SubmitCancel:  gc.submitCancel,
-		SubmitParam:   gc.submitParam,
+		SubmitText:     gc.submitText,
+		SubmitVersion:  gc.submitVersion,
+		Vote:           gc.vote,
+		Declare:        gc.declareVersion,
+		SubmitCancel:   gc.submitCancel,
+		SubmitParam:    gc.submitParam,
+		SubmitTreasury: gc.submitTreasury,
 
 		// Get
 		GetProposal:           gc.getProposal,
@@ -72,6 +77,7 @@ func (gc *GovContract) FnSigns() map[uint16]interface{} {
 		GetGovernParamValue:   gc.getGovernParamValue,
 		GetAccuVerifiersCount: gc.getAccuVerifiersCount,
 		ListGovernParam:       gc.listGovernParam,
+		GetTreasuryBalance:    gc.getTreasuryBalance,
 	}
 }
 
@@ -93,6 +99,10 @@ func (gc *GovContract) CheckGasPrice(gasPrice *big.Int, fcode uint16) error {
 		if gasPrice.Cmp(configs.SubmitParamProposalGasPrice) < 0 {
 			return common.InvalidParameter.Wrap(ErrUnderPrice.Error())
 		}
+	case SubmitTreasury:
+		if gasPrice.Cmp(configs.SubmitTreasuryProposalGasPrice) < 0 {
+			return common.InvalidParameter.Wrap(ErrUnderPrice.Error())
+		}
 	}
 
 	return nil
@@ -258,6 +268,52 @@ func (gc *GovContract) submitParam(verifier discover.NodeID, pipID string, modul
 	return gc.nonCallHandler("submitParam", SubmitParam, err)
 }
 
+// submitTreasury submits a proposal spending the community treasury. The
+// function doesn't exist before the treasury fork.
+func (gc *GovContract) submitTreasury(verifier discover.NodeID, pipID string, recipient common.Address, tranches []restricting.RestrictingPlan) ([]byte, error) {
+	from := gc.Contract.CallerAddress
+	blockNumber := gc.Evm.BlockNumber.Uint64()
+	blockHash := gc.Evm.BlockHash
+	txHash := gc.Evm.StateDB.TxHash()
+
+	log.Debug("call submitTreasury of GovContract",
+		"from", from,
+		"txHash", txHash,
+		"blockNumber", blockNumber,
+		"PIPID", pipID,
+		"verifierID", verifier.TerminalString(),
+		"recipient", recipient,
+		"tranches", tranches)
+
+	if !gc.Evm.chainConfig.IsTreasury(gc.Evm.BlockNumber) {
+		return nil, plugin.FuncNotExistErr
+	}
+
+	if !gc.Contract.UseGas(configs.SubmitTreasuryProposalGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	if gc.Evm.GasPrice.Cmp(configs.SubmitTreasuryProposalGasPrice) < 0 {
+		return nil, ErrUnderPrice
+	}
+
+	p := &gov.TreasuryProposal{
+		PIPID:        pipID,
+		ProposalType: gov.Treasury,
+		SubmitBlock:  blockNumber,
+		ProposalID:   txHash,
+		Proposer:     verifier,
+		Recipient:    recipient,
+		Tranches:     tranches,
+	}
+	err := gov.Submit(from, p, blockHash, blockNumber, plugin.StakingInstance(), gc.Evm.StateDB, gc.Evm.chainConfig.ChainID)
+	return gc.nonCallHandler("submitTreasury", SubmitTreasury, err)
+}
+
 func (gc *GovContract) vote(verifier discover.NodeID, proposalID common.Hash, op uint8, programVersion uint32, programVersionSign common.VersionSign) ([]byte, error) {
 	from := gc.Contract.CallerAddress
 	blockNumber := gc.Evm.BlockNumber.Uint64()
@@ -453,6 +509,24 @@ func (gc *GovContract) listGovernParam(module string) ([]byte, error) {
 	return gc.callHandler("listGovernParam", paramList, err)
 }
 
+// getTreasuryBalance returns the balance of the community treasury.
+func (gc *GovContract) getTreasuryBalance() ([]byte, error) {
+	from := gc.Contract.CallerAddress
+	blockNumber := gc.Evm.BlockNumber.Uint64()
+	txHash := gc.Evm.StateDB.TxHash()
+	log.Debug("call getTreasuryBalance of GovContract",
+		"from", from,
+		"txHash", txHash,
+		"blockNumber", blockNumber)
+
+	if !gc.Evm.chainConfig.IsTreasury(gc.Evm.BlockNumber) {
+		return nil, plugin.FuncNotExistErr
+	}
+
+	balance := gc.Evm.StateDB.GetBalance(vm.TreasuryAddr)
+	return gc.callHandler("getTreasuryBalance", (*hexutil.Big)(balance), nil)
+}
+
 func (gc *GovContract) nonCallHandler(funcName string, fcode uint16, err error) ([]byte, error) {
 	if err != nil {
 		if bizErr, ok := err.(*common.BizError); ok {
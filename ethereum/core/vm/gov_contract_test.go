@@ -18,9 +18,11 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/gov"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	commonvm "github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/plugin"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/restricting"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/xcom"
 )
 
@@ -157,6 +159,17 @@ func buildSubmitCancel(nodeID discover.NodeID, pipID string, endVotingRounds uin
 	return common.MustRlpEncode(input)
 }
 
+func buildSubmitTreasury(nodeID discover.NodeID, pipID string, recipient common.Address, tranches []restricting.RestrictingPlan) []byte {
+	var input [][]byte
+	input = make([][]byte, 0)
+	input = append(input, common.MustRlpEncode(uint16(2006))) // func type code
+	input = append(input, common.MustRlpEncode(nodeID))       // param 1 ..
+	input = append(input, common.MustRlpEncode(pipID))
+	input = append(input, common.MustRlpEncode(recipient))
+	input = append(input, common.MustRlpEncode(tranches))
+	return common.MustRlpEncode(input)
+}
+
 func buildVoteInput(nodeIdx int, proposalID common.Hash) []byte {
 	var input [][]byte
 	input = make([][]byte, 0)
@@ -551,6 +564,137 @@ func TestGovContract_SubmitParam_Pass(t *testing.T) {
 	}
 }
 
+// treasuryTranches returns two tranches of the minimum restricting amount,
+// released at the first and the second epoch.
+func treasuryTranches() []restricting.RestrictingPlan {
+	return []restricting.RestrictingPlan{
+		{Epoch: 1, Amount: new(big.Int).Set(xcom.FloorMinimumRelease)},
+		{Epoch: 2, Amount: new(big.Int).Set(xcom.FloorMinimumRelease)},
+	}
+}
+
+// enableTreasury activates the treasury fork for the current block and funds
+// the treasury with the given amount.
+func enableTreasury(chain *mock.Chain, balance *big.Int) {
+	gc.Evm.chainConfig = &configs.ChainConfig{TreasuryBlock: common.Big0}
+	chain.StateDB.AddBalance(commonvm.TreasuryAddr, balance)
+}
+
+// passTreasuryProposal lets all the validators vote for the treasury proposal
+// and tallies it at its end-voting block.
+func passTreasuryProposal(chain *mock.Chain, t *testing.T) {
+	commit_sndb(chain)
+
+	prepair_sndb(chain, txHashArr[2])
+	allVote(chain, t, defaultProposalID, gov.Yes)
+	commit_sndb(chain)
+
+	p, err := gov.GetProposal(defaultProposalID, chain.StateDB)
+	if err != nil {
+		t.Fatal("find proposal error", "err", err)
+	}
+	skip_emptyBlock(chain, p.GetEndVotingBlock()-1)
+
+	build_staking_data_more(chain)
+	endBlock(chain, t)
+	commit_sndb(chain)
+}
+
+func TestGovContract_SubmitTreasury_BeforeFork(t *testing.T) {
+	chain := setup(t)
+	defer clear(chain, t)
+
+	chain.StateDB.AddBalance(commonvm.TreasuryAddr, new(big.Int).Mul(xcom.FloorMinimumRelease, common.Big2))
+	_, err := gc.Run(buildSubmitTreasury(nodeIdArr[1], "pipid3", addrArr[0], treasuryTranches()))
+	assert.Equal(t, plugin.FuncNotExistErr, err)
+}
+
+func TestGovContract_SubmitTreasury_Invalid(t *testing.T) {
+	chain := setup(t)
+	defer clear(chain, t)
+
+	enableTreasury(chain, new(big.Int).Mul(xcom.FloorMinimumRelease, common.Big2))
+
+	runGovContract(false, gc, buildSubmitTreasury(nodeIdArr[1], "pipid3", common.Address{}, treasuryTranches()), t, gov.TreasuryRecipientInvalid)
+	runGovContract(false, gc, buildSubmitTreasury(nodeIdArr[1], "pipid3", commonvm.TreasuryAddr, treasuryTranches()), t, gov.TreasuryRecipientInvalid)
+
+	runGovContract(false, gc, buildSubmitTreasury(nodeIdArr[1], "pipid3", addrArr[0], nil), t, gov.TreasuryTranchesInvalid)
+	zeroEpoch := treasuryTranches()
+	zeroEpoch[0].Epoch = 0
+	runGovContract(false, gc, buildSubmitTreasury(nodeIdArr[1], "pipid3", addrArr[0], zeroEpoch), t, gov.TreasuryTranchesInvalid)
+	belowMinimum := treasuryTranches()
+	belowMinimum[1].Amount = new(big.Int).Sub(xcom.FloorMinimumRelease, common.Big1)
+	runGovContract(false, gc, buildSubmitTreasury(nodeIdArr[1], "pipid3", addrArr[0], belowMinimum), t, gov.TreasuryTranchesInvalid)
+
+	aboveBalance := append(treasuryTranches(), restricting.RestrictingPlan{Epoch: 3, Amount: new(big.Int).Set(xcom.FloorMinimumRelease)})
+	runGovContract(false, gc, buildSubmitTreasury(nodeIdArr[1], "pipid3", addrArr[0], aboveBalance), t, gov.TreasuryBalanceNotEnough)
+
+	p, err := gov.GetProposal(defaultProposalID, chain.StateDB)
+	assert.Nil(t, err)
+	assert.Nil(t, p)
+}
+
+func TestGovContract_SubmitTreasury_Pass(t *testing.T) {
+	chain := setup(t)
+	defer clear(chain, t)
+
+	total := new(big.Int).Mul(xcom.FloorMinimumRelease, common.Big2)
+	enableTreasury(chain, total)
+	recipient := addrArr[0]
+
+	runGovContract(false, gc, buildSubmitTreasury(nodeIdArr[1], "pipid3", recipient, treasuryTranches()), t)
+	passTreasuryProposal(chain, t)
+
+	result, err := gov.GetTallyResult(defaultProposalID, chain.StateDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result == nil {
+		t.Fatal("cannot find the tally result")
+	}
+	assert.Equal(t, gov.Pass, result.Status)
+
+	// the tranches are moved from the treasury to the recipient as restricting plans
+	assert.Equal(t, 0, chain.StateDB.GetBalance(commonvm.TreasuryAddr).Sign())
+	plans, bizErr := plugin.RestrictingInstance().GetRestrictingInfo(recipient, chain.StateDB)
+	if bizErr != nil {
+		t.Fatal(bizErr)
+	}
+	assert.Equal(t, total, plans.Balance.ToInt())
+	assert.Equal(t, 2, len(plans.Entry))
+	for _, entry := range plans.Entry {
+		assert.Equal(t, xcom.FloorMinimumRelease, entry.Amount.ToInt())
+	}
+}
+
+func TestGovContract_SubmitTreasury_PassedButUnpaid(t *testing.T) {
+	chain := setup(t)
+	defer clear(chain, t)
+
+	total := new(big.Int).Mul(xcom.FloorMinimumRelease, common.Big2)
+	enableTreasury(chain, total)
+	recipient := addrArr[0]
+
+	runGovContract(false, gc, buildSubmitTreasury(nodeIdArr[1], "pipid3", recipient, treasuryTranches()), t)
+
+	// the treasury is spent elsewhere while the proposal is voted
+	chain.StateDB.SubBalance(commonvm.TreasuryAddr, common.Big1)
+	passTreasuryProposal(chain, t)
+
+	result, err := gov.GetTallyResult(defaultProposalID, chain.StateDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result == nil {
+		t.Fatal("cannot find the tally result")
+	}
+	assert.Equal(t, gov.Failed, result.Status)
+
+	assert.Equal(t, new(big.Int).Sub(total, common.Big1), chain.StateDB.GetBalance(commonvm.TreasuryAddr))
+	_, bizErr := plugin.RestrictingInstance().GetRestrictingInfo(recipient, chain.StateDB)
+	assert.NotNil(t, bizErr)
+}
+
 func TestGovContract_SubmitVersion(t *testing.T) {
 	chain := setup(t)
 	defer clear(chain, t)
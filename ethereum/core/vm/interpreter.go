@@ -31,6 +31,11 @@ type Config struct {
 
 	// VM execution timeout duration (unit: ms)
 	VmTimeoutDuration uint64
+
+	// Speculative marks an execution whose state changes may be thrown away.
+	// The PhoenixChain precompiled contracts write to the snapshotdb, which
+	// cannot be discarded, so calling them cancels a speculative execution.
+	Speculative bool
 }
 
 // Interpreter is used to run Ethereum based contracts and will utilise the
@@ -90,7 +95,11 @@ func NewEVMInterpreter(evm *EVM, cfg Config) *EVMInterpreter {
 	// the jump table was initialised. If it was not
 	// we'll set the default jump table.
 	if cfg.JumpTable[STOP] == nil {
-		cfg.JumpTable = istanbulInstructionSet
+		if evm.chainConfig.IsAccessList(evm.BlockNumber) {
+			cfg.JumpTable = accessListInstructionSet
+		} else {
+			cfg.JumpTable = istanbulInstructionSet
+		}
 	}
 
 	return &EVMInterpreter{
@@ -41,11 +41,20 @@ var (
 	byzantiumInstructionSet      = newByzantiumInstructionSet()
 	constantinopleInstructionSet = newConstantinopleInstructionSet()
 	istanbulInstructionSet       = newIstanbulInstructionSet()
+	accessListInstructionSet     = newAccessListInstructionSet()
 )
 
 // JumpTable contains the EVM opcodes supported at a given fork.
 type JumpTable [256]*operation
 
+// newAccessListInstructionSet returns the istanbul instructions priced
+// according to the access list of the transaction.
+func newAccessListInstructionSet() JumpTable {
+	instructionSet := newIstanbulInstructionSet()
+	enableAccessList(&instructionSet)
+	return instructionSet
+}
+
 // newIstanbulInstructionSet returns the frontier, homestead
 // byzantium, contantinople and petersburg instructions.
 func newIstanbulInstructionSet() JumpTable {
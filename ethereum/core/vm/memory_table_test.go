@@ -7,8 +7,8 @@ import (
 
 func TestMemorySha3(t *testing.T) {
 	stack := newstack()
-	stack.push(uint256.NewInt().SetBytes([]byte{0x01}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x02}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x01}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x02}))
 	r, _ := memorySha3(stack)
 	if r != 3 {
 		t.Errorf("Expected: 3, got %d", r)
@@ -17,9 +17,9 @@ func TestMemorySha3(t *testing.T) {
 
 func TestMemoryCallDataCopy(t *testing.T) {
 	stack := newstack()
-	stack.push(uint256.NewInt().SetBytes([]byte{0x01}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x02}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x03}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x01}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x02}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x03}))
 	r, _ := memoryCallDataCopy(stack)
 	if r != 4 {
 		t.Errorf("Expected: 4, got %d", r)
@@ -28,10 +28,10 @@ func TestMemoryCallDataCopy(t *testing.T) {
 
 func TestMemoryReturnDataCopy(t *testing.T) {
 	stack := newstack()
-	stack.push(uint256.NewInt().SetBytes([]byte{0x01}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x02}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x03}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x05}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x01}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x02}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x03}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x05}))
 	r, _ := memoryReturnDataCopy(stack)
 	if r != 7 {
 		t.Errorf("Expected: 7, got %d", r)
@@ -40,13 +40,13 @@ func TestMemoryReturnDataCopy(t *testing.T) {
 
 func TestMemoryCodeCopy(t *testing.T) {
 	stack := newstack()
-	stack.push(uint256.NewInt().SetBytes([]byte{0x01}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x02}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x03}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x05}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x06}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x07}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x01}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x02}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x03}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x05}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x06}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x07}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
 	r, _ := memoryCodeCopy(stack)
 	if r != 14 {
 		t.Errorf("Expected: 14, got %d", r)
@@ -55,13 +55,13 @@ func TestMemoryCodeCopy(t *testing.T) {
 
 func TestMemoryExtCodeCopy(t *testing.T) {
 	stack := newstack()
-	stack.push(uint256.NewInt().SetBytes([]byte{0x01}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x02}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x03}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x05}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x06}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x07}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x01}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x02}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x03}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x05}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x06}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x07}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
 	r, _ := memoryExtCodeCopy(stack)
 	if r != 12 {
 		t.Errorf("Expected: 12, got %d", r)
@@ -70,7 +70,7 @@ func TestMemoryExtCodeCopy(t *testing.T) {
 
 func TestMemoryMLoad(t *testing.T) {
 	stack := newstack()
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
 	r, _ := memoryMLoad(stack)
 	if r != 40 {
 		t.Errorf("Expected: 40, got %d", r)
@@ -79,7 +79,7 @@ func TestMemoryMLoad(t *testing.T) {
 
 func TestMemoryMStore8(t *testing.T) {
 	stack := newstack()
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
 	r, _ := memoryMStore8(stack)
 	if r != 9 {
 		t.Errorf("Expected: 9, got %d", r)
@@ -88,7 +88,7 @@ func TestMemoryMStore8(t *testing.T) {
 
 func TestMemoryMStore(t *testing.T) {
 	stack := newstack()
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
 	r, _ := memoryMStore(stack)
 	if r != 40 {
 		t.Errorf("Expected: 40, got %d", r)
@@ -97,9 +97,9 @@ func TestMemoryMStore(t *testing.T) {
 
 func TestMemoryCreate(t *testing.T) {
 	stack := newstack()
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x03}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x03}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
 	r, _ := memoryCreate(stack)
 	if r != 11 {
 		t.Errorf("Expected: 11, got %d", r)
@@ -112,15 +112,15 @@ func TestMemoryCreate(t *testing.T) {
 
 func TestMemoryCall(t *testing.T) {
 	stack := newstack()
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x06}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x03}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x06}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x03}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
 	r, _ := memoryCall(stack)
 	if r != 16 {
 		t.Errorf("Expected: 16, got %d", r)
@@ -141,9 +141,9 @@ func TestMemoryCall(t *testing.T) {
 
 func TestMemoryReturn(t *testing.T) {
 	stack := newstack()
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x03}))
-	stack.push(uint256.NewInt().SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x03}))
+	stack.push(new(uint256.Int).SetBytes([]byte{0x08}))
 	r, _ := memoryReturn(stack)
 	if r != 11 {
 		t.Errorf("Expected: 11, got %d", r)
@@ -27,7 +27,7 @@ func TestSlashingContract_ReportMutiSign(t *testing.T) {
 	if nil != err {
 		t.Fatal(err)
 	}
-	addr := common.MustStringToAddress("0xr9tx0n00etv5c5smmlctlpg8jas7p78n8x3n9x")
+	addr := common.MustStringToAddress("0x3Ba2a1C3c2F1E3d6e0E2cA5b4D3e8a8A8f0B9d6c")
 	nodeId, err := discover.HexID("51c0559c065400151377d71acd7a17282a7c8abcfefdb11992dcecafde15e100b8e31e1a5e74834a04792d016f166c80b9923423fe280570e8131debf591d483")
 	if nil != err {
 		t.Fatal(err)
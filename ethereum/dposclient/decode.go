@@ -0,0 +1,334 @@
+package dposclient
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/restricting"
+)
+
+// Param is a decoded argument of a built-in contract call.
+type Param struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Call is a decoded transaction to a built-in contract. Parameter values are
+// converted to plain JSON types: integers which may exceed 53 bits, such as
+// amounts, are decimal strings and binary values are hex strings.
+type Call struct {
+	Contract string  `json:"contract"`
+	FuncType uint16  `json:"funcType"`
+	Method   string  `json:"method"`
+	Params   []Param `json:"params"`
+}
+
+// Args returns the parameter values of the call by name.
+func (c *Call) Args() map[string]interface{} {
+	args := make(map[string]interface{}, len(c.Params))
+	for _, p := range c.Params {
+		args[p.Name] = p.Value
+	}
+	return args
+}
+
+// String returns the call in the form Contract.method(name: value, ...).
+func (c *Call) String() string {
+	params := make([]string, len(c.Params))
+	for i, p := range c.Params {
+		value := p.Value
+		if value == nil {
+			value = "<unchanged>"
+		}
+		params[i] = fmt.Sprintf("%s: %v", p.Name, value)
+	}
+	return fmt.Sprintf("%s.%s(%s)", c.Contract, c.Method, strings.Join(params, ", "))
+}
+
+// paramSpec describes an argument of a built-in contract function.
+type paramSpec struct {
+	name string
+	typ  reflect.Type
+}
+
+// funcSpec describes a built-in contract function.
+type funcSpec struct {
+	method string
+	params []paramSpec
+}
+
+var (
+	uint8Type           = reflect.TypeOf(uint8(0))
+	uint16Type          = reflect.TypeOf(uint16(0))
+	uint32Type          = reflect.TypeOf(uint32(0))
+	uint64Type          = reflect.TypeOf(uint64(0))
+	stringType          = reflect.TypeOf("")
+	bigIntType          = reflect.TypeOf(new(big.Int))
+	addressType         = reflect.TypeOf(common.Address{})
+	hashType            = reflect.TypeOf(common.Hash{})
+	nodeIDType          = reflect.TypeOf(discover.NodeID{})
+	versionSignType     = reflect.TypeOf(common.VersionSign{})
+	blsPubKeyType       = reflect.TypeOf(bls.PublicKeyHex{})
+	blsProofType        = reflect.TypeOf(bls.SchnorrProofHex{})
+	restrictingPlanType = reflect.TypeOf([]restricting.RestrictingPlan{})
+)
+
+// optional marks a parameter which may be left empty, meaning "not set".
+func optional(typ reflect.Type) reflect.Type {
+	return reflect.PtrTo(typ)
+}
+
+// funcSpecs lists the transactions accepted by the built-in contracts, with
+// the arguments in the order the contracts decode them.
+var funcSpecs = map[uint16]funcSpec{
+	TxCreateStaking: {"createStaking", []paramSpec{
+		{"typ", uint16Type},
+		{"benefitAddress", addressType},
+		{"nodeId", nodeIDType},
+		{"externalId", stringType},
+		{"nodeName", stringType},
+		{"website", stringType},
+		{"details", stringType},
+		{"amount", bigIntType},
+		{"rewardPer", uint16Type},
+		{"programVersion", uint32Type},
+		{"programVersionSign", versionSignType},
+		{"blsPubKey", blsPubKeyType},
+		{"blsProof", blsProofType},
+	}},
+	TxEditCandidate: {"editCandidate", []paramSpec{
+		{"benefitAddress", optional(addressType)},
+		{"nodeId", nodeIDType},
+		{"rewardPer", optional(uint16Type)},
+		{"externalId", optional(stringType)},
+		{"nodeName", optional(stringType)},
+		{"website", optional(stringType)},
+		{"details", optional(stringType)},
+	}},
+	TxIncreaseStaking: {"increaseStaking", []paramSpec{
+		{"nodeId", nodeIDType},
+		{"typ", uint16Type},
+		{"amount", bigIntType},
+	}},
+	TxWithdrewStaking: {"withdrewStaking", []paramSpec{
+		{"nodeId", nodeIDType},
+	}},
+	TxDelegate: {"delegate", []paramSpec{
+		{"typ", uint16Type},
+		{"nodeId", nodeIDType},
+		{"amount", bigIntType},
+	}},
+	TxWithdrewDelegation: {"withdrewDelegation", []paramSpec{
+		{"stakingBlockNum", uint64Type},
+		{"nodeId", nodeIDType},
+		{"amount", bigIntType},
+	}},
+
+	TxSubmitText: {"submitText", []paramSpec{
+		{"verifier", nodeIDType},
+		{"pipID", stringType},
+	}},
+	TxSubmitVersion: {"submitVersion", []paramSpec{
+		{"verifier", nodeIDType},
+		{"pipID", stringType},
+		{"newVersion", uint32Type},
+		{"endVotingRounds", uint64Type},
+	}},
+	TxSubmitParam: {"submitParam", []paramSpec{
+		{"verifier", nodeIDType},
+		{"pipID", stringType},
+		{"module", stringType},
+		{"name", stringType},
+		{"newValue", stringType},
+	}},
+	TxVote: {"vote", []paramSpec{
+		{"verifier", nodeIDType},
+		{"proposalID", hashType},
+		{"option", uint8Type},
+		{"programVersion", uint32Type},
+		{"programVersionSign", versionSignType},
+	}},
+	TxDeclareVersion: {"declareVersion", []paramSpec{
+		{"activeNode", nodeIDType},
+		{"programVersion", uint32Type},
+		{"programVersionSign", versionSignType},
+	}},
+	TxSubmitCancel: {"submitCancel", []paramSpec{
+		{"verifier", nodeIDType},
+		{"pipID", stringType},
+		{"endVotingRounds", uint64Type},
+		{"tobeCanceledProposalID", hashType},
+	}},
+	TxSubmitTreasury: {"submitTreasury", []paramSpec{
+		{"verifier", nodeIDType},
+		{"pipID", stringType},
+		{"recipient", addressType},
+		{"tranches", restrictingPlanType},
+	}},
+
+	TxReportDuplicateSign: {"reportDuplicateSign", []paramSpec{
+		{"dupType", uint8Type},
+		{"data", stringType},
+	}},
+
+	TxCreateRestrictingPlan: {"createRestrictingPlan", []paramSpec{
+		{"account", addressType},
+		{"plans", restrictingPlanType},
+	}},
+
+	TxWithdrawDelegateReward: {"withdrawDelegateReward", nil},
+}
+
+// ContractName returns the name of the built-in contract at the given address,
+// or the empty string if there is none.
+func ContractName(addr common.Address) string {
+	switch addr {
+	case vm.StakingContractAddr:
+		return "StakingContract"
+	case vm.GovContractAddr:
+		return "GovContract"
+	case vm.SlashingContractAddr:
+		return "SlashingContract"
+	case vm.RestrictingContractAddr:
+		return "RestrictingContract"
+	case vm.DelegateRewardPoolAddr:
+		return "DelegateRewardContract"
+	}
+	return ""
+}
+
+// DecodeFunc decodes the input data of a transaction to the built-in contract
+// at address to. It is the inverse of EncodeFunc and fails for data the
+// contract would reject as malformed.
+func DecodeFunc(to common.Address, data []byte) (*Call, error) {
+	contract := ContractName(to)
+	if contract == "" {
+		return nil, fmt.Errorf("%x is not a built-in contract", to)
+	}
+	var args [][]byte
+	if err := rlp.Decode(bytes.NewReader(data), &args); err != nil {
+		return nil, fmt.Errorf("invalid input data: %v", err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("input data has no function type")
+	}
+	var funcType uint16
+	if err := rlp.DecodeBytes(args[0], &funcType); err != nil {
+		return nil, fmt.Errorf("invalid function type: %v", err)
+	}
+	spec, ok := funcSpecs[funcType]
+	if !ok || ContractAddress(funcType) != to {
+		return nil, fmt.Errorf("unknown function type %d of %s", funcType, contract)
+	}
+	if len(args)-1 != len(spec.params) {
+		return nil, fmt.Errorf("%s takes %d parameters, have %d", spec.method, len(spec.params), len(args)-1)
+	}
+	call := &Call{
+		Contract: contract,
+		FuncType: funcType,
+		Method:   spec.method,
+		Params:   make([]Param, len(spec.params)),
+	}
+	for i, param := range spec.params {
+		value, err := decodeParam(param.typ, args[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter %s of %s: %v", param.name, spec.method, err)
+		}
+		call.Params[i] = Param{Name: param.name, Type: typeName(param.typ), Value: value}
+	}
+	return call, nil
+}
+
+// decodeParam decodes a single RLP encoded argument and converts it for
+// display. Empty optional arguments decode to nil.
+func decodeParam(typ reflect.Type, data []byte) (interface{}, error) {
+	if typ.Kind() == reflect.Ptr && typ != bigIntType {
+		if len(data) == 0 {
+			return nil, nil
+		}
+		typ = typ.Elem()
+	}
+	ptr := reflect.New(typ)
+	if err := rlp.DecodeBytes(data, ptr.Interface()); err != nil {
+		return nil, err
+	}
+	return displayValue(ptr.Elem()), nil
+}
+
+// displayValue converts a decoded value into a plain JSON type.
+func displayValue(v reflect.Value) interface{} {
+	if v.Type() == bigIntType {
+		if v.IsNil() {
+			return "0"
+		}
+		return v.Interface().(*big.Int).String()
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return fmt.Sprint(v.Interface())
+		}
+		return string(text)
+	}
+	switch v.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.String:
+		return v.String()
+	case reflect.Slice:
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = displayValue(v.Index(i))
+		}
+		return list
+	case reflect.Struct:
+		fields := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			name := v.Type().Field(i).Name
+			if tag := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]; tag != "" {
+				name = tag
+			}
+			fields[name] = displayValue(v.Field(i))
+		}
+		return fields
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// typeName returns the type of an argument as shown to the user.
+func typeName(typ reflect.Type) string {
+	switch typ {
+	case bigIntType:
+		return "uint256"
+	case addressType:
+		return "address"
+	case optional(addressType):
+		return "address?"
+	case hashType:
+		return "bytes32"
+	case nodeIDType:
+		return "nodeId"
+	case versionSignType:
+		return "versionSign"
+	case blsPubKeyType:
+		return "blsPubKey"
+	case blsProofType:
+		return "blsProof"
+	case restrictingPlanType:
+		return "restrictingPlan[]"
+	}
+	if typ.Kind() == reflect.Ptr {
+		return typ.Elem().String() + "?"
+	}
+	return typ.String()
+}
@@ -0,0 +1,292 @@
+// Package dposclient provides typed access to the built-in PoS contracts of
+// PhoenixChain: staking, governance, slashing, restricting and delegate reward.
+//
+// Calls to these contracts are RLP encoded as a list of byte strings, the first
+// being the function type and the rest the RLP encoding of each argument.
+// Queries answer with a JSON document {"Code":..,"Ret":..}, transactions leave
+// their result code in a log attached to the receipt.
+package dposclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sync"
+
+	phoenixchain "github.com/PhoenixGlobal/Phoenix-Chain-SDK"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/bind"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/ethclient"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// Function types of the built-in contracts.
+const (
+	TxCreateStaking      uint16 = 1000
+	TxEditCandidate      uint16 = 1001
+	TxIncreaseStaking    uint16 = 1002
+	TxWithdrewStaking    uint16 = 1003
+	TxDelegate           uint16 = 1004
+	TxWithdrewDelegation uint16 = 1005
+	QueryVerifierList    uint16 = 1100
+	QueryValidatorList   uint16 = 1101
+	QueryCandidateList   uint16 = 1102
+	QueryRelateList      uint16 = 1103
+	QueryDelegateInfo    uint16 = 1104
+	QueryCandidateInfo   uint16 = 1105
+	QueryPackageReward   uint16 = 1200
+	QueryStakingReward   uint16 = 1201
+	QueryAvgPackTime     uint16 = 1202
+
+	TxSubmitText            uint16 = 2000
+	TxSubmitVersion         uint16 = 2001
+	TxSubmitParam           uint16 = 2002
+	TxVote                  uint16 = 2003
+	TxDeclareVersion        uint16 = 2004
+	TxSubmitCancel          uint16 = 2005
+	TxSubmitTreasury        uint16 = 2006
+	QueryProposal           uint16 = 2100
+	QueryTallyResult        uint16 = 2101
+	QueryProposalList       uint16 = 2102
+	QueryActiveVersion      uint16 = 2103
+	QueryGovernParamValue   uint16 = 2104
+	QueryAccuVerifiersCount uint16 = 2105
+	QueryGovernParamList    uint16 = 2106
+	QueryTreasuryBalance    uint16 = 2107
+
+	TxReportDuplicateSign uint16 = 3000
+	QueryDuplicateSign    uint16 = 3001
+
+	TxCreateRestrictingPlan uint16 = 4000
+	QueryRestrictingInfo    uint16 = 4100
+
+	TxWithdrawDelegateReward uint16 = 5000
+	QueryDelegateReward      uint16 = 5100
+)
+
+var (
+	// ErrNoResult is returned by DecodeTxResult if the receipt carries no log
+	// written by one of the built-in contracts.
+	ErrNoResult = errors.New("no PoS contract result in receipt")
+	// ErrNoSigner is returned when a transaction is requested without a signer.
+	ErrNoSigner = errors.New("no signer to authorize the transaction with")
+)
+
+// Client wraps an ethclient.Client with typed methods for the built-in PoS
+// contracts.
+type Client struct {
+	ec *ethclient.Client
+
+	mu      sync.Mutex
+	chainID *big.Int
+}
+
+// Dial connects a client to the given URL.
+func Dial(rawurl string) (*Client, error) {
+	return DialContext(context.Background(), rawurl)
+}
+
+// DialContext connects a client to the given URL with the given context.
+func DialContext(ctx context.Context, rawurl string) (*Client, error) {
+	ec, err := ethclient.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(ec), nil
+}
+
+// NewClient creates a client that uses the given ethclient.
+func NewClient(ec *ethclient.Client) *Client {
+	return &Client{ec: ec}
+}
+
+// Close closes the underlying RPC connection.
+func (dc *Client) Close() {
+	dc.ec.Close()
+}
+
+// EthClient returns the underlying ethclient.
+func (dc *Client) EthClient() *ethclient.Client {
+	return dc.ec
+}
+
+// ContractAddress returns the address of the built-in contract serving the
+// given function type, or the zero address if the type is unknown.
+func ContractAddress(funcType uint16) common.Address {
+	switch {
+	case 0 < funcType && funcType < 2000:
+		return vm.StakingContractAddr
+	case funcType >= 2000 && funcType < 3000:
+		return vm.GovContractAddr
+	case funcType >= 3000 && funcType < 4000:
+		return vm.SlashingContractAddr
+	case funcType >= 4000 && funcType < 5000:
+		return vm.RestrictingContractAddr
+	case funcType >= 5000 && funcType < 6000:
+		return vm.DelegateRewardPoolAddr
+	}
+	return common.ZeroAddr
+}
+
+// EncodeFunc builds the input data of a built-in contract call. A nil pointer
+// parameter is encoded as an empty string, which the contracts read as "not set".
+func EncodeFunc(funcType uint16, params ...interface{}) ([]byte, error) {
+	args := make([][]byte, 0, len(params)+1)
+	fnType, err := rlp.EncodeToBytes(funcType)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, fnType)
+	for i, param := range params {
+		if v := reflect.ValueOf(param); v.Kind() == reflect.Ptr && v.IsNil() {
+			args = append(args, []byte{})
+			continue
+		}
+		arg, err := rlp.EncodeToBytes(param)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode parameter %d of function %d: %v", i, funcType, err)
+		}
+		args = append(args, arg)
+	}
+	buf := new(bytes.Buffer)
+	if err := rlp.Encode(buf, args); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// callResult is the JSON envelope returned by the contract queries.
+type callResult struct {
+	Code uint32
+	Ret  json.RawMessage
+}
+
+// decodeCallResult unpacks a query answer into result. A non-zero code is
+// turned into a *common.BizError carrying the contract's message.
+func decodeCallResult(data []byte, result interface{}) error {
+	var res callResult
+	if err := json.Unmarshal(data, &res); err != nil {
+		return fmt.Errorf("invalid contract result %q: %v", data, err)
+	}
+	if res.Code != common.OkCode {
+		var msg string
+		if err := json.Unmarshal(res.Ret, &msg); err != nil {
+			msg = string(res.Ret)
+		}
+		return common.NewBizError(res.Code, msg)
+	}
+	if result == nil || len(res.Ret) == 0 {
+		return nil
+	}
+	return json.Unmarshal(res.Ret, result)
+}
+
+// call executes a query of a built-in contract on the latest block and decodes
+// its result.
+func (dc *Client) call(ctx context.Context, result interface{}, funcType uint16, params ...interface{}) error {
+	return dc.callAt(ctx, nil, result, funcType, params...)
+}
+
+// callAt executes a query of a built-in contract on the given block, the latest
+// one if blockNumber is nil, and decodes its result.
+func (dc *Client) callAt(ctx context.Context, blockNumber *big.Int, result interface{}, funcType uint16, params ...interface{}) error {
+	data, err := EncodeFunc(funcType, params...)
+	if err != nil {
+		return err
+	}
+	to := ContractAddress(funcType)
+	res, err := dc.ec.CallContract(ctx, phoenixchain.CallMsg{To: &to, Data: data}, blockNumber)
+	if err != nil {
+		return err
+	}
+	return decodeCallResult(res, result)
+}
+
+func (dc *Client) getChainID(ctx context.Context) (*big.Int, error) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	if dc.chainID == nil {
+		id, err := dc.ec.ChainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		dc.chainID = id
+	}
+	return dc.chainID, nil
+}
+
+// transact signs and sends a transaction to a built-in contract, deriving the
+// nonce, gas price and gas limit from the node when opts leaves them unset.
+func (dc *Client) transact(opts *bind.TransactOpts, funcType uint16, params ...interface{}) (*types.Transaction, error) {
+	if opts.Signer == nil {
+		return nil, ErrNoSigner
+	}
+	input, err := EncodeFunc(funcType, params...)
+	if err != nil {
+		return nil, err
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	to := ContractAddress(funcType)
+
+	value := opts.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	var nonce uint64
+	if opts.Nonce == nil {
+		nonce, err = dc.ec.PendingNonceAt(ctx, opts.From)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve account nonce: %v", err)
+		}
+	} else {
+		nonce = opts.Nonce.Uint64()
+	}
+	gasPrice := opts.GasPrice
+	if gasPrice == nil {
+		gasPrice, err = dc.ec.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest gas price: %v", err)
+		}
+	}
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		msg := phoenixchain.CallMsg{From: opts.From, To: &to, GasPrice: gasPrice, Value: value, Data: input}
+		gasLimit, err = dc.ec.EstimateGas(ctx, msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate gas needed: %v", err)
+		}
+	}
+	chainID, err := dc.getChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rawTx := types.NewTransaction(nonce, to, value, gasLimit, gasPrice, input)
+	signedTx, err := opts.Signer(types.NewEIP155Signer(chainID), opts.From, rawTx)
+	if err != nil {
+		return nil, err
+	}
+	if err := dc.ec.SendTransaction(ctx, signedTx); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
+// WaitTxResult waits until tx is mined and returns the contract result
+// recorded in its receipt.
+func (dc *Client) WaitTxResult(ctx context.Context, tx *types.Transaction) (*TxResult, error) {
+	receipt, err := bind.WaitMined(ctx, dc.ec, tx)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeTxResult(receipt)
+}
@@ -0,0 +1,227 @@
+package dposclient
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	corevm "github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/gov"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/plugin"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/restricting"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/reward"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/staking"
+)
+
+var testNodeID = discover.MustHexID("0x362003c50ed3a523cdede37a001803b8f0fed27cb402b3d6127a1a96661ec202318f68f4c76d9b0bfbabfd551a178d4335eaeaa9b7981a4df30dfc8c0bfe3384")
+
+func TestEncodeFunc(t *testing.T) {
+	var (
+		gotAmount  *big.Int
+		gotNode    discover.NodeID
+		gotBenefit *common.Address
+		gotReward  *uint16
+		gotName    *string
+	)
+	command := map[uint16]interface{}{
+		TxIncreaseStaking: func(nodeId discover.NodeID, typ uint16, amount *big.Int) ([]byte, error) {
+			gotNode, gotAmount = nodeId, amount
+			return nil, nil
+		},
+		TxEditCandidate: func(benefitAddress *common.Address, nodeId discover.NodeID, rewardPer *uint16,
+			externalId, nodeName, website, details *string) ([]byte, error) {
+			gotBenefit, gotReward, gotName = benefitAddress, rewardPer, nodeName
+			return nil, nil
+		},
+	}
+
+	input, err := EncodeFunc(TxIncreaseStaking, testNodeID, uint16(RestrictingAmount), big.NewInt(1000))
+	assert.Nil(t, err)
+	fnCode, fn, params, err := plugin.VerifyTxData(input, command)
+	assert.Nil(t, err)
+	assert.Equal(t, TxIncreaseStaking, fnCode)
+	callFn(fn, params)
+	assert.Equal(t, testNodeID, gotNode)
+	assert.Equal(t, int64(1000), gotAmount.Int64())
+
+	// Nil pointers must reach the contract as nil, not as zero values.
+	name := "node-1"
+	edit := &EditCandidateParams{NodeId: testNodeID, NodeName: &name}
+	input, err = EncodeFunc(TxEditCandidate, edit.BenefitAddress, edit.NodeId, edit.RewardPer,
+		edit.ExternalId, edit.NodeName, edit.Website, edit.Details)
+	assert.Nil(t, err)
+	_, fn, params, err = plugin.VerifyTxData(input, command)
+	assert.Nil(t, err)
+	callFn(fn, params)
+	assert.Nil(t, gotBenefit)
+	assert.Nil(t, gotReward)
+	if assert.NotNil(t, gotName) {
+		assert.Equal(t, name, *gotName)
+	}
+}
+
+func TestContractAddress(t *testing.T) {
+	assert.Equal(t, vm.StakingContractAddr, ContractAddress(TxDelegate))
+	assert.Equal(t, vm.GovContractAddr, ContractAddress(TxVote))
+	assert.Equal(t, vm.SlashingContractAddr, ContractAddress(TxReportDuplicateSign))
+	assert.Equal(t, vm.RestrictingContractAddr, ContractAddress(TxCreateRestrictingPlan))
+	assert.Equal(t, vm.DelegateRewardPoolAddr, ContractAddress(TxWithdrawDelegateReward))
+	assert.Equal(t, common.ZeroAddr, ContractAddress(6000))
+}
+
+func TestDecodeCallResult(t *testing.T) {
+	var can *staking.CandidateHex
+	err := decodeCallResult([]byte(`{"Code":0,"Ret":{"NodeId":"`+testNodeID.String()+`","StakingBlockNum":12,"Shares":"0x10"}}`), &can)
+	assert.Nil(t, err)
+	assert.Equal(t, testNodeID, can.NodeId)
+	assert.Equal(t, uint64(12), can.StakingBlockNum)
+	assert.Equal(t, int64(16), can.Shares.ToInt().Int64())
+
+	err = decodeCallResult([]byte(`{"Code":301204,"Ret":"Query candidate info failed:Candidate info is not found"}`), &can)
+	bizErr, ok := err.(*common.BizError)
+	if assert.True(t, ok) {
+		assert.Equal(t, uint32(301204), bizErr.Code)
+		assert.Equal(t, "Query candidate info failed:Candidate info is not found", bizErr.Msg)
+	}
+}
+
+func TestDecodeProposal(t *testing.T) {
+	p, err := DecodeProposal([]byte(`{"ProposalID":"0x0000000000000000000000000000000000000000000000000000000000000001","ProposalType":2,"PIPID":"7","NewVersion":65536}`))
+	assert.Nil(t, err)
+	vp, ok := p.(*gov.VersionProposal)
+	if assert.True(t, ok) {
+		assert.Equal(t, "7", vp.PIPID)
+		assert.Equal(t, uint32(65536), vp.NewVersion)
+	}
+	p, err = DecodeProposal([]byte(`{"ProposalID":"0x0000000000000000000000000000000000000000000000000000000000000002","ProposalType":5,"PIPID":"8","Recipient":"0x1000000000000000000000000000000000001337","Tranches":[{"epoch":2,"amount":100}]}`))
+	assert.Nil(t, err)
+	tp, ok := p.(*gov.TreasuryProposal)
+	if assert.True(t, ok) {
+		assert.Equal(t, common.HexToAddress("0x1000000000000000000000000000000000001337"), tp.Recipient)
+		assert.Equal(t, []restricting.RestrictingPlan{{Epoch: 2, Amount: big.NewInt(100)}}, tp.Tranches)
+	}
+	_, err = DecodeProposal([]byte(`{"ProposalType":9}`))
+	assert.NotNil(t, err)
+}
+
+func TestDecodeTxResult(t *testing.T) {
+	rewards := []reward.NodeDelegateReward{{NodeID: testNodeID, StakingNum: 100, Reward: big.NewInt(7)}}
+	resBytes, _ := rlp.EncodeToBytes(rewards)
+	data, _ := rlp.EncodeToBytes([][]byte{[]byte("0"), resBytes})
+	receipt := &types.Receipt{
+		TxHash: common.HexToHash("0x01"),
+		Logs:   []*types.Log{{Address: vm.DelegateRewardPoolAddr, Data: data}},
+	}
+	res, err := DecodeTxResult(receipt)
+	assert.Nil(t, err)
+	assert.Nil(t, res.Err())
+	got, err := DecodeWithdrawDelegateReward(res)
+	assert.Nil(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, testNodeID, got[0].NodeID)
+		assert.Equal(t, int64(7), got[0].Reward.Int64())
+	}
+
+	data, _ = rlp.EncodeToBytes([][]byte{[]byte("301101")})
+	receipt.Logs = []*types.Log{{Address: vm.StakingContractAddr, Data: data}}
+	res, err = DecodeTxResult(receipt)
+	assert.Nil(t, err)
+	assert.Equal(t, staking.ErrCanAlreadyExist, res.Err())
+
+	receipt.Logs = nil
+	_, err = DecodeTxResult(receipt)
+	assert.Equal(t, ErrNoResult, err)
+}
+
+func callFn(fn interface{}, params []reflect.Value) {
+	reflect.ValueOf(fn).Call(params)
+}
+
+func TestDecodeFunc(t *testing.T) {
+	input, err := EncodeFunc(TxDelegate, uint16(FreeAmount), testNodeID, new(big.Int).Mul(big.NewInt(1e18), big.NewInt(1e6)))
+	assert.Nil(t, err)
+	call, err := DecodeFunc(vm.StakingContractAddr, input)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "StakingContract", call.Contract)
+		assert.Equal(t, "delegate", call.Method)
+		assert.Equal(t, map[string]interface{}{
+			"typ":    uint64(0),
+			"nodeId": testNodeID.String(),
+			"amount": "1000000000000000000000000",
+		}, call.Args())
+		assert.Equal(t, "StakingContract.delegate(typ: 0, nodeId: "+testNodeID.String()+", amount: 1000000000000000000000000)", call.String())
+	}
+
+	// Unset optional parameters decode to nil.
+	name := "node-1"
+	input, err = EncodeFunc(TxEditCandidate, (*common.Address)(nil), testNodeID, (*uint16)(nil),
+		(*string)(nil), &name, (*string)(nil), (*string)(nil))
+	assert.Nil(t, err)
+	call, err = DecodeFunc(vm.StakingContractAddr, input)
+	if assert.Nil(t, err) {
+		args := call.Args()
+		assert.Nil(t, args["benefitAddress"])
+		assert.Nil(t, args["rewardPer"])
+		assert.Equal(t, name, args["nodeName"])
+	}
+
+	plans := []restricting.RestrictingPlan{{Epoch: 1, Amount: big.NewInt(100)}}
+	account := common.HexToAddress("0x1000000000000000000000000000000000001337")
+	input, err = EncodeFunc(TxCreateRestrictingPlan, account, plans)
+	assert.Nil(t, err)
+	call, err = DecodeFunc(vm.RestrictingContractAddr, input)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []interface{}{map[string]interface{}{"epoch": uint64(1), "amount": "100"}}, call.Args()["plans"])
+	}
+
+	// Calls sent to the wrong contract, with missing parameters or garbage are rejected.
+	input, _ = EncodeFunc(TxWithdrewStaking, testNodeID)
+	_, err = DecodeFunc(vm.GovContractAddr, input)
+	assert.NotNil(t, err)
+	input, _ = EncodeFunc(TxDelegate, uint16(FreeAmount), testNodeID)
+	_, err = DecodeFunc(vm.StakingContractAddr, input)
+	assert.NotNil(t, err)
+	_, err = DecodeFunc(vm.StakingContractAddr, []byte{0xa9, 0x05, 0x9c, 0xbb})
+	assert.NotNil(t, err)
+	_, err = DecodeFunc(account, input)
+	assert.NotNil(t, err)
+}
+
+func TestFuncSpecsMatchContracts(t *testing.T) {
+	contracts := []map[uint16]interface{}{
+		(&corevm.StakingContract{}).FnSigns(),
+		(&corevm.GovContract{}).FnSigns(),
+		(&corevm.SlashingContract{}).FnSigns(),
+		(&corevm.RestrictingContract{}).FnSigns(),
+		(&corevm.DelegateRewardContract{}).FnSigns(),
+	}
+	for code, spec := range funcSpecs {
+		var fn interface{}
+		for _, fnSigns := range contracts {
+			if f, ok := fnSigns[code]; ok {
+				fn = f
+			}
+		}
+		if fn == nil {
+			t.Errorf("function type %d (%s) is not served by any contract", code, spec.method)
+			continue
+		}
+		fnType := reflect.TypeOf(fn)
+		if fnType.NumIn() != len(spec.params) {
+			t.Errorf("%s: have %d parameters, contract takes %d", spec.method, len(spec.params), fnType.NumIn())
+			continue
+		}
+		for i, param := range spec.params {
+			if param.typ != fnType.In(i) {
+				t.Errorf("%s: parameter %s has type %v, contract takes %v", spec.method, param.name, param.typ, fnType.In(i))
+			}
+		}
+	}
+}
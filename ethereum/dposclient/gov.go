@@ -0,0 +1,166 @@
+package dposclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/bind"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/gov"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/restricting"
+)
+
+// SubmitText submits a text proposal on behalf of the verifier.
+func (dc *Client) SubmitText(opts *bind.TransactOpts, verifier discover.NodeID, pipID string) (*types.Transaction, error) {
+	return dc.transact(opts, TxSubmitText, verifier, pipID)
+}
+
+// SubmitVersion submits a version upgrade proposal on behalf of the verifier.
+func (dc *Client) SubmitVersion(opts *bind.TransactOpts, verifier discover.NodeID, pipID string, newVersion uint32, endVotingRounds uint64) (*types.Transaction, error) {
+	return dc.transact(opts, TxSubmitVersion, verifier, pipID, newVersion, endVotingRounds)
+}
+
+// SubmitParam submits a governance parameter proposal on behalf of the verifier.
+func (dc *Client) SubmitParam(opts *bind.TransactOpts, verifier discover.NodeID, pipID string, module, name, newValue string) (*types.Transaction, error) {
+	return dc.transact(opts, TxSubmitParam, verifier, pipID, module, name, newValue)
+}
+
+// SubmitCancel submits a proposal cancelling the version proposal tobeCanceled.
+func (dc *Client) SubmitCancel(opts *bind.TransactOpts, verifier discover.NodeID, pipID string, endVotingRounds uint64, tobeCanceled common.Hash) (*types.Transaction, error) {
+	return dc.transact(opts, TxSubmitCancel, verifier, pipID, endVotingRounds, tobeCanceled)
+}
+
+// SubmitTreasury submits a proposal paying the recipient from the community
+// treasury, released in the epochs given by tranches once it passes.
+func (dc *Client) SubmitTreasury(opts *bind.TransactOpts, verifier discover.NodeID, pipID string, recipient common.Address, tranches []restricting.RestrictingPlan) (*types.Transaction, error) {
+	return dc.transact(opts, TxSubmitTreasury, verifier, pipID, recipient, tranches)
+}
+
+// Vote casts the verifier's vote on a proposal. The program version and its
+// signature by the node key are checked against the running node.
+func (dc *Client) Vote(opts *bind.TransactOpts, verifier discover.NodeID, proposalID common.Hash, option gov.VoteOption, programVersion uint32, versionSign common.VersionSign) (*types.Transaction, error) {
+	return dc.transact(opts, TxVote, verifier, proposalID, uint8(option), programVersion, versionSign)
+}
+
+// DeclareVersion declares the program version a node is running.
+func (dc *Client) DeclareVersion(opts *bind.TransactOpts, activeNode discover.NodeID, programVersion uint32, versionSign common.VersionSign) (*types.Transaction, error) {
+	return dc.transact(opts, TxDeclareVersion, activeNode, programVersion, versionSign)
+}
+
+// GetProposal returns the proposal with the given ID.
+func (dc *Client) GetProposal(ctx context.Context, proposalID common.Hash) (gov.Proposal, error) {
+	var raw json.RawMessage
+	if err := dc.call(ctx, &raw, QueryProposal, proposalID); err != nil {
+		return nil, err
+	}
+	return DecodeProposal(raw)
+}
+
+// GetTallyResult returns the tally result of a proposal.
+func (dc *Client) GetTallyResult(ctx context.Context, proposalID common.Hash) (*gov.TallyResult, error) {
+	var tally *gov.TallyResult
+	err := dc.call(ctx, &tally, QueryTallyResult, proposalID)
+	return tally, err
+}
+
+// ListProposal returns all the proposals.
+func (dc *Client) ListProposal(ctx context.Context) ([]gov.Proposal, error) {
+	var raws []json.RawMessage
+	if err := dc.call(ctx, &raws, QueryProposalList); err != nil {
+		return nil, err
+	}
+	proposals := make([]gov.Proposal, 0, len(raws))
+	for _, raw := range raws {
+		p, err := DecodeProposal(raw)
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, p)
+	}
+	return proposals, nil
+}
+
+// GetActiveVersion returns the version currently active on chain.
+func (dc *Client) GetActiveVersion(ctx context.Context) (uint32, error) {
+	var version uint32
+	err := dc.call(ctx, &version, QueryActiveVersion)
+	return version, err
+}
+
+// GetGovernParamValue returns the current value of a governance parameter.
+func (dc *Client) GetGovernParamValue(ctx context.Context, module, name string) (string, error) {
+	var value string
+	err := dc.call(ctx, &value, QueryGovernParamValue, module, name)
+	return value, err
+}
+
+// AccuVerifiers is the vote summary of a proposal.
+type AccuVerifiers struct {
+	Verifiers   uint64 // verifiers allowed to vote
+	Yeas        uint64
+	Nays        uint64
+	Abstentions uint64
+}
+
+// GetAccuVerifiersCount returns the vote summary of a proposal at the given block.
+func (dc *Client) GetAccuVerifiersCount(ctx context.Context, proposalID, blockHash common.Hash) (*AccuVerifiers, error) {
+	var counts []uint64
+	if err := dc.call(ctx, &counts, QueryAccuVerifiersCount, proposalID, blockHash); err != nil {
+		return nil, err
+	}
+	if len(counts) != 4 {
+		return nil, fmt.Errorf("unexpected accumulated verifiers result: %v", counts)
+	}
+	return &AccuVerifiers{counts[0], counts[1], counts[2], counts[3]}, nil
+}
+
+// ListGovernParam returns the governance parameters of a module, or of all
+// modules if module is empty.
+func (dc *Client) ListGovernParam(ctx context.Context, module string) ([]*gov.GovernParam, error) {
+	var params []*gov.GovernParam
+	err := dc.call(ctx, &params, QueryGovernParamList, module)
+	return params, err
+}
+
+// GetTreasuryBalance returns the balance of the community treasury.
+func (dc *Client) GetTreasuryBalance(ctx context.Context) (*big.Int, error) {
+	var balance hexutil.Big
+	if err := dc.call(ctx, &balance, QueryTreasuryBalance); err != nil {
+		return nil, err
+	}
+	return balance.ToInt(), nil
+}
+
+// DecodeProposal decodes the JSON form of a proposal into its concrete type.
+func DecodeProposal(data []byte) (gov.Proposal, error) {
+	var head struct {
+		ProposalType gov.ProposalType
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+	var p gov.Proposal
+	switch head.ProposalType {
+	case gov.Text:
+		p = new(gov.TextProposal)
+	case gov.Version:
+		p = new(gov.VersionProposal)
+	case gov.Param:
+		p = new(gov.ParamProposal)
+	case gov.Cancel:
+		p = new(gov.CancelProposal)
+	case gov.Treasury:
+		p = new(gov.TreasuryProposal)
+	default:
+		return nil, fmt.Errorf("unknown proposal type %d", head.ProposalType)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
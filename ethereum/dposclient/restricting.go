@@ -0,0 +1,23 @@
+package dposclient
+
+import (
+	"context"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/bind"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/restricting"
+)
+
+// CreateRestrictingPlan locks funds of the sender for account, released in the
+// epochs given by plans.
+func (dc *Client) CreateRestrictingPlan(opts *bind.TransactOpts, account common.Address, plans []restricting.RestrictingPlan) (*types.Transaction, error) {
+	return dc.transact(opts, TxCreateRestrictingPlan, account, plans)
+}
+
+// GetRestrictingInfo returns the restricting plans of an account.
+func (dc *Client) GetRestrictingInfo(ctx context.Context, account common.Address) (*restricting.Result, error) {
+	var res *restricting.Result
+	err := dc.call(ctx, &res, QueryRestrictingInfo, account)
+	return res, err
+}
@@ -0,0 +1,208 @@
+package dposclient
+
+import (
+	"strconv"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/gov"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/restricting"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/reward"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/slashing"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/staking"
+)
+
+// TxResult is the outcome of a transaction sent to a built-in contract, as
+// recorded in the log the contract attaches to the receipt.
+type TxResult struct {
+	TxHash   common.Hash
+	Contract common.Address
+	Code     uint32
+	// Data holds the RLP encoded value some functions return along with the
+	// code, e.g. the rewards paid out by withdrawDelegateReward.
+	Data []byte
+}
+
+// Err returns the business error corresponding to the result code, or nil if
+// the transaction succeeded.
+func (r *TxResult) Err() error {
+	if r.Code == common.OkCode {
+		return nil
+	}
+	return LookupError(r.Code)
+}
+
+// DecodeData decodes the RLP encoded return value of the transaction into v.
+func (r *TxResult) DecodeData(v interface{}) error {
+	return rlp.DecodeBytes(r.Data, v)
+}
+
+// DecodeTxResult extracts the contract result from the receipt of a
+// transaction sent to one of the built-in contracts.
+func DecodeTxResult(receipt *types.Receipt) (*TxResult, error) {
+	for _, log := range receipt.Logs {
+		if !isContractAddress(log.Address) {
+			continue
+		}
+		var fields [][]byte
+		if err := rlp.DecodeBytes(log.Data, &fields); err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			return nil, ErrNoResult
+		}
+		code, err := strconv.ParseUint(string(fields[0]), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		res := &TxResult{TxHash: receipt.TxHash, Contract: log.Address, Code: uint32(code)}
+		if len(fields) > 1 {
+			res.Data = fields[1]
+		}
+		return res, nil
+	}
+	return nil, ErrNoResult
+}
+
+func isContractAddress(addr common.Address) bool {
+	switch addr {
+	case vm.StakingContractAddr, vm.GovContractAddr, vm.SlashingContractAddr,
+		vm.RestrictingContractAddr, vm.DelegateRewardPoolAddr:
+		return true
+	}
+	return false
+}
+
+// LookupError returns the business error registered for code. Unknown codes
+// yield a generic error carrying the code.
+func LookupError(code uint32) *common.BizError {
+	if err, ok := bizErrors[code]; ok {
+		return err
+	}
+	return common.NewBizError(code, "unknown error code "+strconv.FormatUint(uint64(code), 10))
+}
+
+var bizErrors = make(map[uint32]*common.BizError)
+
+func init() {
+	for _, err := range knownErrors {
+		bizErrors[err.Code] = err
+	}
+}
+
+var knownErrors = []*common.BizError{
+	common.NoErr,
+	common.InternalError,
+	common.NotFound,
+	common.InvalidParameter,
+
+	// staking
+	staking.ErrWrongBlsPubKey,
+	staking.ErrWrongBlsPubKeyProof,
+	staking.ErrDescriptionLen,
+	staking.ErrWrongProgramVersionSign,
+	staking.ErrProgramVersionTooLow,
+	staking.ErrDeclVsFialedCreateCan,
+	staking.ErrNoSameStakingAddr,
+	staking.ErrInvalidRewardPer,
+	staking.ErrRewardPerInterval,
+	staking.ErrRewardPerChangeRange,
+	staking.ErrStakeVonTooLow,
+	staking.ErrCanAlreadyExist,
+	staking.ErrCanNoExist,
+	staking.ErrCanStatusInvalid,
+	staking.ErrIncreaseStakeVonTooLow,
+	staking.ErrDelegateVonTooLow,
+	staking.ErrAccountNoAllowToDelegate,
+	staking.ErrCanNoAllowDelegate,
+	staking.ErrWithdrewDelegationVonTooLow,
+	staking.ErrDelegateNoExist,
+	staking.ErrWrongVonOptType,
+	staking.ErrAccountVonNoEnough,
+	staking.ErrBlockNumberDisordered,
+	staking.ErrDelegateVonNoEnough,
+	staking.ErrWrongWithdrewDelVonCalc,
+	staking.ErrValidatorNoExist,
+	staking.ErrWrongFuncParams,
+	staking.ErrWrongSlashType,
+	staking.ErrSlashVonOverflow,
+	staking.ErrWrongSlashVonCalc,
+	staking.ErrGetVerifierList,
+	staking.ErrGetValidatorList,
+	staking.ErrGetCandidateList,
+	staking.ErrGetDelegateRelated,
+	staking.ErrQueryCandidateInfo,
+	staking.ErrQueryDelegateInfo,
+	staking.ErrNodeID2Addr,
+
+	// gov
+	gov.ActiveVersionError,
+	gov.VoteOptionError,
+	gov.ProposalTypeError,
+	gov.ProposalIDEmpty,
+	gov.ProposalIDExist,
+	gov.ProposalNotFound,
+	gov.PIPIDEmpty,
+	gov.PIPIDExist,
+	gov.EndVotingRoundsTooSmall,
+	gov.EndVotingRoundsTooLarge,
+	gov.NewVersionError,
+	gov.VotingVersionProposalExist,
+	gov.PreActiveVersionProposalExist,
+	gov.VotingCancelProposalExist,
+	gov.TobeCanceledProposalNotFound,
+	gov.TobeCanceledProposalTypeError,
+	gov.TobeCanceledProposalNotAtVoting,
+	gov.ProposerEmpty,
+	gov.VerifierInfoNotFound,
+	gov.VerifierStatusInvalid,
+	gov.TxSenderDifferFromStaking,
+	gov.TxSenderIsNotVerifier,
+	gov.TxSenderIsNotCandidate,
+	gov.VersionSignError,
+	gov.VerifierNotUpgraded,
+	gov.ProposalNotAtVoting,
+	gov.VoteDuplicated,
+	gov.DeclareVersionError,
+	gov.NotifyStakingDeclaredVersionError,
+	gov.TallyResultNotFound,
+	gov.UnsupportedGovernParam,
+	gov.VotingParamProposalExist,
+	gov.GovernParamValueError,
+	gov.ParamProposalIsSameValue,
+
+	// slashing
+	slashing.ErrDuplicateSignVerify,
+	slashing.ErrSlashingExist,
+	slashing.ErrBlockNumberTooHigh,
+	slashing.ErrIntervalTooLong,
+	slashing.ErrGetCandidate,
+	slashing.ErrAddrMismatch,
+	slashing.ErrNodeIdMismatch,
+	slashing.ErrBlsPubKeyMismatch,
+	slashing.ErrSlashingFail,
+	slashing.ErrNotValidator,
+	slashing.ErrSameAddr,
+
+	// restricting
+	restricting.ErrParamEpochInvalid,
+	restricting.ErrCountRestrictPlansInvalid,
+	restricting.ErrLockedAmountTooLess,
+	restricting.ErrBalanceNotEnough,
+	restricting.ErrAccountNotFound,
+	restricting.ErrSlashingTooMuch,
+	restricting.ErrStakingAmountEmpty,
+	restricting.ErrAdvanceLockedFundsAmountLessThanZero,
+	restricting.ErrReturnLockFundsAmountLessThanZero,
+	restricting.ErrSlashingAmountLessThanZero,
+	restricting.ErrCreatePlanAmountLessThanZero,
+	restricting.ErrStakingAmountInvalid,
+	restricting.ErrRestrictBalanceNotEnough,
+	restricting.ErrCreatePlanAmountLessThanMiniAmount,
+	restricting.ErrRestrictBalanceAndFreeNotEnough,
+
+	// reward
+	reward.ErrDelegationNotFound,
+}
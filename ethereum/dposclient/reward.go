@@ -0,0 +1,45 @@
+package dposclient
+
+import (
+	"context"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/bind"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/reward"
+)
+
+// WithdrawDelegateReward withdraws the delegate rewards of the sender from all
+// the nodes it delegated to. The rewards paid out can be read from the
+// transaction result with DecodeWithdrawDelegateReward.
+func (dc *Client) WithdrawDelegateReward(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return dc.transact(opts, TxWithdrawDelegateReward)
+}
+
+// GetDelegateReward returns the rewards not yet withdrawn by account at each of
+// the given nodes, or at every node it delegated to if nodeIDs is empty.
+func (dc *Client) GetDelegateReward(ctx context.Context, account common.Address, nodeIDs []discover.NodeID) ([]reward.NodeDelegateRewardPresenter, error) {
+	if nodeIDs == nil {
+		nodeIDs = []discover.NodeID{}
+	}
+	var rewards []reward.NodeDelegateRewardPresenter
+	err := dc.call(ctx, &rewards, QueryDelegateReward, account, nodeIDs)
+	return rewards, err
+}
+
+// DecodeWithdrawDelegateReward returns the rewards paid out by a successful
+// withdrawDelegateReward transaction.
+func DecodeWithdrawDelegateReward(res *TxResult) ([]reward.NodeDelegateReward, error) {
+	if err := res.Err(); err != nil {
+		return nil, err
+	}
+	var rewards []reward.NodeDelegateReward
+	if len(res.Data) == 0 {
+		return rewards, nil
+	}
+	if err := res.DecodeData(&rewards); err != nil {
+		return nil, err
+	}
+	return rewards, nil
+}
@@ -0,0 +1,32 @@
+package dposclient
+
+import (
+	"context"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/bind"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/consensus"
+)
+
+// ReportDuplicateSign reports a validator signing two conflicting consensus
+// messages. evidence is the JSON encoding of the evidence as produced by the
+// PBFT evidence pool.
+func (dc *Client) ReportDuplicateSign(opts *bind.TransactOpts, typ consensus.EvidenceType, evidence string) (*types.Transaction, error) {
+	return dc.transact(opts, TxReportDuplicateSign, uint8(typ), evidence)
+}
+
+// CheckDuplicateSign returns the hash of the transaction that reported the
+// node for duplicate signing at blockNumber, or the zero hash if it has not
+// been reported.
+func (dc *Client) CheckDuplicateSign(ctx context.Context, typ consensus.EvidenceType, nodeId discover.NodeID, blockNumber uint64) (common.Hash, error) {
+	var txHash string
+	if err := dc.call(ctx, &txHash, QueryDuplicateSign, uint8(typ), nodeId, blockNumber); err != nil {
+		return common.Hash{}, err
+	}
+	if txHash == "" {
+		return common.Hash{}, nil
+	}
+	return common.HexToHash(txHash), nil
+}
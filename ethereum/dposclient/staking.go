@@ -0,0 +1,161 @@
+package dposclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/abi/bind"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/staking"
+)
+
+// AmountType selects the account balance a staking or delegation is paid from.
+type AmountType uint16
+
+const (
+	FreeAmount        AmountType = 0 // the free balance of the sender
+	RestrictingAmount AmountType = 1 // the locked balance of the sender's restricting plan
+)
+
+// CreateStakingParams are the arguments of the createStaking (1000) function.
+type CreateStakingParams struct {
+	Typ                AmountType
+	BenefitAddress     common.Address
+	NodeId             discover.NodeID
+	ExternalId         string
+	NodeName           string
+	Website            string
+	Details            string
+	Amount             *big.Int
+	RewardPer          uint16
+	ProgramVersion     uint32
+	ProgramVersionSign common.VersionSign
+	BlsPubKey          bls.PublicKeyHex
+	BlsProof           bls.SchnorrProofHex
+}
+
+// EditCandidateParams are the arguments of the editCandidate (1001) function.
+// Nil fields are left unchanged.
+type EditCandidateParams struct {
+	BenefitAddress *common.Address
+	NodeId         discover.NodeID
+	RewardPer      *uint16
+	ExternalId     *string
+	NodeName       *string
+	Website        *string
+	Details        *string
+}
+
+// CreateStaking stakes a new candidate node.
+func (dc *Client) CreateStaking(opts *bind.TransactOpts, p *CreateStakingParams) (*types.Transaction, error) {
+	return dc.transact(opts, TxCreateStaking, uint16(p.Typ), p.BenefitAddress, p.NodeId,
+		p.ExternalId, p.NodeName, p.Website, p.Details, p.Amount, p.RewardPer, p.ProgramVersion,
+		p.ProgramVersionSign, p.BlsPubKey, p.BlsProof)
+}
+
+// EditCandidate modifies the description, benefit address or reward ratio of a candidate.
+func (dc *Client) EditCandidate(opts *bind.TransactOpts, p *EditCandidateParams) (*types.Transaction, error) {
+	return dc.transact(opts, TxEditCandidate, p.BenefitAddress, p.NodeId, p.RewardPer,
+		p.ExternalId, p.NodeName, p.Website, p.Details)
+}
+
+// IncreaseStaking adds amount to the staking of a candidate.
+func (dc *Client) IncreaseStaking(opts *bind.TransactOpts, nodeId discover.NodeID, typ AmountType, amount *big.Int) (*types.Transaction, error) {
+	return dc.transact(opts, TxIncreaseStaking, nodeId, uint16(typ), amount)
+}
+
+// WithdrewStaking withdraws the staking of a candidate and all its delegations.
+func (dc *Client) WithdrewStaking(opts *bind.TransactOpts, nodeId discover.NodeID) (*types.Transaction, error) {
+	return dc.transact(opts, TxWithdrewStaking, nodeId)
+}
+
+// Delegate delegates amount to a candidate.
+func (dc *Client) Delegate(opts *bind.TransactOpts, typ AmountType, nodeId discover.NodeID, amount *big.Int) (*types.Transaction, error) {
+	return dc.transact(opts, TxDelegate, uint16(typ), nodeId, amount)
+}
+
+// WithdrewDelegation withdraws amount from the delegation made to the staking
+// of nodeId started at stakingBlockNum.
+func (dc *Client) WithdrewDelegation(opts *bind.TransactOpts, stakingBlockNum uint64, nodeId discover.NodeID, amount *big.Int) (*types.Transaction, error) {
+	return dc.transact(opts, TxWithdrewDelegation, stakingBlockNum, nodeId, amount)
+}
+
+// GetVerifierList returns the verifiers of the current settlement epoch.
+func (dc *Client) GetVerifierList(ctx context.Context) (staking.ValidatorExQueue, error) {
+	var list staking.ValidatorExQueue
+	err := dc.call(ctx, &list, QueryVerifierList)
+	return list, err
+}
+
+// GetValidatorList returns the validators of the current consensus round.
+func (dc *Client) GetValidatorList(ctx context.Context) (staking.ValidatorExQueue, error) {
+	var list staking.ValidatorExQueue
+	err := dc.call(ctx, &list, QueryValidatorList)
+	return list, err
+}
+
+// GetValidatorListAt returns the validators of the consensus round that
+// contains the given block.
+func (dc *Client) GetValidatorListAt(ctx context.Context, blockNumber *big.Int) (staking.ValidatorExQueue, error) {
+	var list staking.ValidatorExQueue
+	err := dc.callAt(ctx, blockNumber, &list, QueryValidatorList)
+	return list, err
+}
+
+// GetCandidateList returns all the current candidates.
+func (dc *Client) GetCandidateList(ctx context.Context) (staking.CandidateHexQueue, error) {
+	var list staking.CandidateHexQueue
+	err := dc.call(ctx, &list, QueryCandidateList)
+	return list, err
+}
+
+// GetRelatedListByDelAddr returns the stakings the given account delegated to.
+func (dc *Client) GetRelatedListByDelAddr(ctx context.Context, delAddr common.Address) (staking.DelRelatedQueue, error) {
+	var list staking.DelRelatedQueue
+	err := dc.call(ctx, &list, QueryRelateList, delAddr)
+	return list, err
+}
+
+// GetDelegateInfo returns the delegation of delAddr to the staking of nodeId
+// started at stakingBlockNum.
+func (dc *Client) GetDelegateInfo(ctx context.Context, stakingBlockNum uint64, delAddr common.Address, nodeId discover.NodeID) (*staking.DelegationEx, error) {
+	var del *staking.DelegationEx
+	err := dc.call(ctx, &del, QueryDelegateInfo, stakingBlockNum, delAddr, nodeId)
+	return del, err
+}
+
+// GetCandidateInfo returns the staking information of a candidate.
+func (dc *Client) GetCandidateInfo(ctx context.Context, nodeId discover.NodeID) (*staking.CandidateHex, error) {
+	var can *staking.CandidateHex
+	err := dc.call(ctx, &can, QueryCandidateInfo, nodeId)
+	return can, err
+}
+
+// GetPackageReward returns the block reward of the current settlement epoch.
+func (dc *Client) GetPackageReward(ctx context.Context) (*big.Int, error) {
+	var reward hexutil.Big
+	if err := dc.call(ctx, &reward, QueryPackageReward); err != nil {
+		return nil, err
+	}
+	return reward.ToInt(), nil
+}
+
+// GetStakingReward returns the staking reward of the current settlement epoch.
+func (dc *Client) GetStakingReward(ctx context.Context) (*big.Int, error) {
+	var reward hexutil.Big
+	if err := dc.call(ctx, &reward, QueryStakingReward); err != nil {
+		return nil, err
+	}
+	return reward.ToInt(), nil
+}
+
+// GetAvgPackTime returns the average block interval in milliseconds.
+func (dc *Client) GetAvgPackTime(ctx context.Context) (uint64, error) {
+	var avg uint64
+	err := dc.call(ctx, &avg, QueryAvgPackTime)
+	return avg, err
+}
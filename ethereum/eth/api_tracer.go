@@ -190,7 +190,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 				signer := types.NewEIP155Signer(api.eth.blockchain.Config().ChainID)
 				// Trace all the transactions contained within
 				for i, tx := range task.block.Transactions() {
-					msg, _ := tx.AsMessage(signer)
+					msg, _ := tx.AsMessage(signer, task.block.BaseFee())
 					vmctx := core.NewEVMContext(msg, task.block.Header(), api.eth.blockchain)
 
 					res, err := api.traceTx(ctx, msg, vmctx, task.statedb, config)
@@ -463,7 +463,7 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 
 			// Fetch and execute the next transaction trace tasks
 			for task := range jobs {
-				msg, _ := txs[task.index].AsMessage(signer)
+				msg, _ := txs[task.index].AsMessage(signer, block.BaseFee())
 				vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain)
 
 				res, err := api.traceTx(ctx, msg, vmctx, task.statedb, config)
@@ -482,7 +482,7 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 		jobs <- &txTraceTask{statedb: statedb.Copy(), index: i}
 
 		// Generate the next state snapshot fast without tracing
-		msg, _ := tx.AsMessage(signer)
+		msg, _ := tx.AsMessage(signer, block.BaseFee())
 		vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain)
 
 		vmenv := vm.NewEVM(vmctx, snapshotdb.Instance(), statedb, api.eth.blockchain.Config(), vm.Config{})
@@ -550,7 +550,7 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 	for i, tx := range block.Transactions() {
 		// Prepare the trasaction for un-traced execution
 		var (
-			msg, _ = tx.AsMessage(signer)
+			msg, _ = tx.AsMessage(signer, block.BaseFee())
 			vmctx  = core.NewEVMContext(msg, block.Header(), api.eth.blockchain)
 
 			vmConf vm.Config
@@ -780,7 +780,7 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, ree
 	signer := types.NewEIP155Signer(api.eth.blockchain.Config().ChainID)
 	for idx, tx := range block.Transactions() {
 		// Assemble the transaction call message and return if the requested offset
-		msg, _ := tx.AsMessage(signer)
+		msg, _ := tx.AsMessage(signer, block.BaseFee())
 		context := core.NewEVMContext(msg, block.Header(), api.eth.blockchain)
 		if idx == txIndex {
 			return msg, context, statedb, nil
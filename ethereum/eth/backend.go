@@ -80,10 +80,11 @@ type Ethereum struct {
 
 	APIBackend *EthAPIBackend
 
-	miner         *miner.Miner
-	gasPrice      *big.Int
-	networkID     uint64
-	netRPCService *ethapi2.PublicNetAPI
+	miner            *miner.Miner
+	evidenceReporter *EvidenceReporter
+	gasPrice         *big.Int
+	networkID        uint64
+	netRPCService    *ethapi2.PublicNetAPI
 
 	lock sync.RWMutex // Protects the variadic fields (e.g. gas price and etherbase)
 }
@@ -241,6 +242,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 			BodyCacheLimit: config.BodyCacheLimit, BlockCacheLimit: config.BlockCacheLimit,
 			MaxFutureBlocks: config.MaxFutureBlocks, BadBlockLimit: config.BadBlockLimit,
 			TriesInMemory: config.TriesInMemory, TrieCleanLimit: config.TrieDBCache,
+			SnapshotLimit: config.SnapshotCache,
 			DBGCInterval: config.DBGCInterval, DBGCTimeout: config.DBGCTimeout,
 			DBGCMpt: config.DBGCMpt, DBGCBlock: config.DBGCBlock,
 		}
@@ -320,7 +322,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 			reactor.SetVRFhandler(handler.NewVrfHandler(eth.blockchain.Genesis().Nonce()))
 			reactor.SetPluginEventMux()
 			reactor.SetPrivateKey(ctx.NodePriKey())
-			handlePlugin(reactor)
+			handlePlugin(reactor, chainConfig)
 			agency = reactor
 
 			//register Govern parameter verifiers
@@ -351,6 +353,11 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	}
 	eth.APIBackend.gpo = gasprice.NewOracle(eth.APIBackend, gpoParams)
 
+	if reporter := config.PbftConfig.EvidenceReporter; reporter != (common.Address{}) {
+		if engine, ok := eth.engine.(*pbft.Pbft); ok {
+			eth.evidenceReporter = NewEvidenceReporter(eth, engine.EvidencePool(), reporter)
+		}
+	}
 	return eth, nil
 }
 
@@ -585,6 +592,9 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 	}
 	srvr.StartWatching(s.eventMux)
 
+	if s.evidenceReporter != nil {
+		s.evidenceReporter.Start()
+	}
 	if s.lesServer != nil {
 		s.lesServer.Start(srvr)
 	}
@@ -600,6 +610,9 @@ func (s *Ethereum) Stop() error {
 	}
 
 	// Then stop everything else.
+	if s.evidenceReporter != nil {
+		s.evidenceReporter.Stop()
+	}
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
 	s.txPool.Stop()
@@ -613,16 +626,18 @@ func (s *Ethereum) Stop() error {
 }
 
 // RegisterPlugin one by one
-func handlePlugin(reactor *core.BlockChainReactor) {
+func handlePlugin(reactor *core.BlockChainReactor, chainConfig *configs.ChainConfig) {
 	xplugin.RewardMgrInstance().SetCurrentNodeID(reactor.NodeId)
 
 	reactor.RegisterPlugin(xcom.SlashingRule, xplugin.SlashInstance())
 	xplugin.SlashInstance().SetDecodeEvidenceFun(evidence.NewEvidence)
 	reactor.RegisterPlugin(xcom.StakingRule, xplugin.StakingInstance())
 	reactor.RegisterPlugin(xcom.RestrictingRule, xplugin.RestrictingInstance())
+	xplugin.RewardMgrInstance().SetChainConfig(chainConfig)
 	reactor.RegisterPlugin(xcom.RewardRule, xplugin.RewardMgrInstance())
 
 	xplugin.GovPluginInstance().SetChainID(reactor.GetChainID())
+	xplugin.GovPluginInstance().SetChainConfig(chainConfig)
 	reactor.RegisterPlugin(xcom.GovernanceRule, xplugin.GovPluginInstance())
 
 	// set rule order
@@ -18,11 +18,14 @@ var DefaultConfig = Config{
 	SyncMode: downloader.FullSync,
 	PbftConfig: types.OptionsConfig{
 		WalMode:           true,
+		ProtectionFile:    "signerprotection.json",
 		PeerMsgQueueSize:  1024,
 		EvidenceDir:       "evidence",
 		MaxPingLatency:    5000,
 		MaxQueuesLimit:    4096,
 		BlacklistDeadline: 60,
+		HealthDir:         "consensushealth",
+		HealthWindow:      1000,
 		Period:            2000,
 		Amount:            1,
 	},
@@ -32,6 +35,7 @@ var DefaultConfig = Config{
 	TrieCache:     32,
 	TrieTimeout:   60 * time.Minute,
 	TrieDBCache:   512,
+	SnapshotCache: 256,
 	DBDisabledGC:      false,
 	DBGCInterval:      86400,
 	DBGCTimeout:       time.Minute,
@@ -102,6 +106,7 @@ type Config struct {
 	TrieCache    int
 	TrieTimeout  time.Duration
 	TrieDBCache  int
+	SnapshotCache int // Megabytes of memory allocated to state snapshot caching, 0 disables it
 	DBDisabledGC bool
 	DBGCInterval uint64
 	DBGCTimeout  time.Duration
@@ -130,6 +130,9 @@ type Downloader struct {
 	dposStorageCh     chan dataPack        // [eth/63] Channel receiving inbound dpos storage
 	dposStorageDoneCh chan struct{}        // Channel to signal termination completion
 	originAndPivotCh  chan dataPack        // [eth/63] Channel receiving origin and pivot block
+	accountRangeCh    chan dataPack        // [eth/66] Channel receiving inbound account ranges
+	storageRangesCh   chan dataPack        // [eth/66] Channel receiving inbound storage ranges
+	byteCodesCh       chan dataPack        // [eth/66] Channel receiving inbound contract codes
 
 	// for stateFetcher
 	stateSyncStart chan *stateSync
@@ -222,6 +225,9 @@ func New(stateDb ethdb.Database, snapshotDB snapshotdb.DB, stateBloom *trie.Sync
 		dposStorageCh:    make(chan dataPack, 1),
 		dposInfoCh:       make(chan dataPack, 1),
 		originAndPivotCh: make(chan dataPack, 1),
+		accountRangeCh:   make(chan dataPack, 1),
+		storageRangesCh:  make(chan dataPack, 1),
+		byteCodesCh:      make(chan dataPack, 1),
 		quitCh:           make(chan struct{}),
 		stateCh:          make(chan dataPack),
 		stateSyncStart:   make(chan *stateSync),
@@ -252,7 +258,7 @@ func (d *Downloader) Progress() ethereum.SyncProgress {
 	switch {
 	case d.blockchain != nil && d.mode == FullSync:
 		current = d.blockchain.CurrentBlock().NumberU64()
-	case d.blockchain != nil && d.mode == FastSync:
+	case d.blockchain != nil && (d.mode == FastSync || d.mode == SnapSync):
 		current = d.blockchain.CurrentFastBlock().NumberU64()
 	case d.lightchain != nil:
 		current = d.lightchain.CurrentHeader().Number.Uint64()
@@ -440,7 +446,7 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, bn *big.I
 	log.Info("synchronising findOrigin", "peer", p.id, "origin", origin, "pivot", pivoth.Number)
 	// Ensure our origin point is below any fast sync pivot point
 	d.committed = 1
-	if d.mode == FastSync {
+	if d.mode == FastSync || d.mode == SnapSync {
 		if pivoth.Number.Uint64() > origin {
 			// fetch latest dpos storage cache from remote peer
 			latest, pivoth, err = d.fetchDPOSInfo(p)
@@ -475,7 +481,7 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, bn *big.I
 	d.syncStatsChainHeight = height
 	d.syncStatsLock.Unlock()
 
-	if d.mode == FastSync {
+	if d.mode == FastSync || d.mode == SnapSync {
 		// Set the ancient data limitation.
 		// If we are running fast sync, all block data older than ancientLimit will be
 		// written to the ancient store. More recent data will be written to the active
@@ -526,7 +532,7 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, bn *big.I
 		func() error { return d.fetchReceipts(origin + 1) },                         // Receipts are retrieved during fast sync
 		func() error { return d.processHeaders(origin+1, pivoth.Number.Uint64(), bn) },
 	}
-	if d.mode == FastSync {
+	if d.mode == FastSync || d.mode == SnapSync {
 		if err := d.snapshotDB.SetEmpty(); err != nil {
 			p.log.Error("set  snapshotDB empty fail")
 			return errors.New("set  snapshotDB empty fail:" + err.Error())
@@ -535,7 +541,7 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, bn *big.I
 			p.log.Error("set snapshotdb current fail", "err", err)
 			return errors.New("set current fail")
 		}
-		fetchers = append(fetchers, func() error { return d.processFastSyncContent(latest, pivoth.Number.Uint64()) })
+		fetchers = append(fetchers, func() error { return d.processFastSyncContent(p, latest, pivoth) })
 		fetchers = append(fetchers, func() error { return d.fetchDPOSStorage(p, pivoth) })
 	} else if d.mode == FullSync {
 		fetchers = append(fetchers, d.processFullSyncContent)
@@ -552,7 +558,7 @@ func (d *Downloader) findOrigin(p *peerConnection) (*types.Header, *types.Header
 	var current *types.Header
 	if d.mode == FullSync {
 		current = d.blockchain.CurrentBlock().Header()
-	} else if d.mode == FastSync {
+	} else if d.mode == FastSync || d.mode == SnapSync {
 		current = d.blockchain.CurrentFastBlock().Header()
 	} else {
 		current = d.lightchain.CurrentHeader()
@@ -777,7 +783,7 @@ func (d *Downloader) spawnSync(fetchers []func() error) error {
 		}
 	}
 
-	if d.mode == FastSync {
+	if d.mode == FastSync || d.mode == SnapSync {
 		if failed {
 			if err := d.setFastSyncStatus(FastSyncFail); err != nil {
 				return err
@@ -1371,7 +1377,7 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, bn *big.Int) er
 				// This check cannot be executed "as is" for full imports, since blocks may still be
 				// queued for processing when the header download completes. However, as long as the
 				// peer gave us something useful, we're already happy/progressed (above check).
-				if d.mode == FastSync || d.mode == LightSync {
+				if d.mode == FastSync || d.mode == SnapSync || d.mode == LightSync {
 					head := d.lightchain.CurrentHeader()
 					if bn.Cmp(head.Number) > 0 {
 						return errStallingPeer
@@ -1397,7 +1403,7 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, bn *big.Int) er
 				}
 				chunk := headers[:limit]
 				// In case of header only syncing, validate the chunk immediately
-				if d.mode == FastSync || d.mode == LightSync {
+				if d.mode == FastSync || d.mode == SnapSync || d.mode == LightSync {
 					// Collect the yet unknown headers to mark them as uncertain
 					unknown := make([]*types.Header, 0, len(chunk))
 					for _, header := range chunk {
@@ -1425,7 +1431,7 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, bn *big.Int) er
 					}
 				}
 				// Unless we're doing light chains, schedule the headers for associated content retrieval
-				if d.mode == FullSync || d.mode == FastSync {
+				if d.mode == FullSync || d.mode == FastSync || d.mode == SnapSync {
 					// If we've reached the allowed number of pending headers, stall a bit
 					for d.queue.PendingBlocks() >= maxQueuedHeaders || d.queue.PendingReceipts() >= maxQueuedHeaders {
 						select {
@@ -1515,10 +1521,16 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 
 // processFastSyncContent takes fetch results from the queue and writes them to the
 // database. It also controls the synchronisation of state nodes of the pivot block.
-func (d *Downloader) processFastSyncContent(latest *types.Header, pivot uint64) error {
+func (d *Downloader) processFastSyncContent(p *peerConnection, latest *types.Header, pivoth *types.Header) error {
 	// Start syncing state of the reported head block. This should get us most of
-	// the state of the pivot block.
-	stateSync := d.syncState(latest.Root)
+	// the state of the pivot block. Snap sync needs the exact state the ranges are
+	// proven against, so it starts at the pivot right away.
+	root := latest.Root
+	if d.mode == SnapSync {
+		root = pivoth.Root
+	}
+	pivot := pivoth.Number.Uint64()
+	stateSync := d.startStateSync(p, root)
 	defer stateSync.Cancel()
 	go func() {
 		if err := stateSync.Wait(); err != nil && err != errCancelStateFetch && err != errCanceled {
@@ -1562,15 +1574,17 @@ func (d *Downloader) processFastSyncContent(latest *types.Header, pivot uint64)
 		if P != nil {
 			// If new pivot block found, cancel old state retrieval and restart
 			if oldPivot != P {
-				stateSync.Cancel()
-
-				stateSync = d.syncState(P.Header.Root)
-				defer stateSync.Cancel()
-				go func() {
-					if err := stateSync.Wait(); err != nil && err != errCancelStateFetch && err != errCanceled {
-						d.queue.Close() // wake up Results
-					}
-				}()
+				if stateSync.root != P.Header.Root {
+					stateSync.Cancel()
+
+					stateSync = d.startStateSync(p, P.Header.Root)
+					defer stateSync.Cancel()
+					go func() {
+						if err := stateSync.Wait(); err != nil && err != errCancelStateFetch && err != errCanceled {
+							d.queue.Close() // wake up Results
+						}
+					}()
+				}
 				oldPivot = P
 			}
 			// Wait for completion, occasionally checking for pivot staleness
@@ -1706,6 +1720,21 @@ func (d *Downloader) DeliverReceipts(id string, receipts [][]*types.Receipt) (er
 	return d.deliver(id, d.receiptCh, &receiptPack{id, receipts}, receiptInMeter, receiptDropMeter)
 }
 
+// DeliverAccountRange injects a new range of accounts received from a remote node.
+func (d *Downloader) DeliverAccountRange(id string, hashes []common.Hash, accounts [][]byte, proof [][]byte) (err error) {
+	return d.deliver(id, d.accountRangeCh, &accountRangePack{id, hashes, accounts, proof}, snapInMeter, snapDropMeter)
+}
+
+// DeliverStorageRanges injects a new batch of storage ranges received from a remote node.
+func (d *Downloader) DeliverStorageRanges(id string, hashes [][]common.Hash, slots [][][]byte, proof [][]byte) (err error) {
+	return d.deliver(id, d.storageRangesCh, &storageRangesPack{id, hashes, slots, proof}, snapInMeter, snapDropMeter)
+}
+
+// DeliverByteCodes injects a new batch of contract codes received from a remote node.
+func (d *Downloader) DeliverByteCodes(id string, codes [][]byte) (err error) {
+	return d.deliver(id, d.byteCodesCh, &byteCodesPack{id, codes}, snapInMeter, snapDropMeter)
+}
+
 // DeliverNodeData injects a new batch of node state data received from a remote node.
 func (d *Downloader) DeliverNodeData(id string, data [][]byte) (err error) {
 	return d.deliver(id, d.stateCh, &statePack{id, data}, stateInMeter, stateDropMeter)
@@ -398,6 +398,7 @@ type downloadTesterPeer struct {
 	lock          sync.RWMutex
 	chain         *testChain
 	missingStates map[common.Hash]bool // State entries that fast sync should not return
+	rangeRequests int32                // Number of state range queries received
 }
 
 // setDelay is a thread safe setter for the network delay value.
@@ -550,6 +551,30 @@ func (dlp *downloadTesterPeer) RequestOriginAndPivotByCurrent(c uint64) error {
 	return nil
 }
 
+// RequestAccountRange constructs a getAccountRange method associated with a
+// particular peer in the download tester. The tester peers serve no state ranges,
+// so snap sync heals the whole state.
+func (dlp *downloadTesterPeer) RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error {
+	atomic.AddInt32(&dlp.rangeRequests, 1)
+	go dlp.dl.downloader.DeliverAccountRange(dlp.id, nil, nil, nil)
+	return nil
+}
+
+// RequestStorageRanges constructs a getStorageRanges method associated with a
+// particular peer in the download tester.
+func (dlp *downloadTesterPeer) RequestStorageRanges(root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error {
+	atomic.AddInt32(&dlp.rangeRequests, 1)
+	go dlp.dl.downloader.DeliverStorageRanges(dlp.id, nil, nil, nil)
+	return nil
+}
+
+// RequestByteCodes constructs a getByteCodes method associated with a particular
+// peer in the download tester.
+func (dlp *downloadTesterPeer) RequestByteCodes(hashes []common.Hash, bytes uint64) error {
+	go dlp.dl.downloader.DeliverByteCodes(dlp.id, nil)
+	return nil
+}
+
 // assertOwnChain checks if the local chain contains the correct number of items
 // of the various chain components.
 func assertOwnChain(t *testing.T, tester *downloadTester, length int, base int64) {
@@ -589,7 +614,7 @@ func assertOwnForkedChain(t *testing.T, tester *downloadTester, common int, leng
 		t.Fatalf("synchronised receipts mismatch: have %v, want %v", rs, receipts)
 	}
 	// test dpos
-	if tester.downloader.mode == FastSync {
+	if tester.downloader.mode == FastSync || tester.downloader.mode == SnapSync {
 		baseNum, err := tester.snapshotdb.BaseNum()
 		if err != nil {
 			t.Error(err)
@@ -609,6 +634,7 @@ func TestCanonicalSynchronisation63Fast(t *testing.T) { testCanonicalSynchronisa
 
 func TestCanonicalSynchronisation64Full(t *testing.T) { testCanonicalSynchronisation(t, 64, FullSync) }
 func TestCanonicalSynchronisation64Fast(t *testing.T) { testCanonicalSynchronisation(t, 64, FastSync) }
+func TestCanonicalSynchronisation66Snap(t *testing.T) { testCanonicalSynchronisation(t, 66, SnapSync) }
 
 func TestCanonicalSynchronisation64Light(t *testing.T) {
 	testCanonicalSynchronisation(t, 64, LightSync)
@@ -630,6 +656,24 @@ func testCanonicalSynchronisation(t *testing.T, protocol int, mode SyncMode) {
 	assertOwnChain(t, tester, blockSyncItems, snapshotDBBaseNum)
 }
 
+// Tests that snap sync never queries state ranges from a peer which didn't
+// negotiate them, healing the whole state from its trie nodes instead.
+func TestSnapSyncLegacyPeer(t *testing.T) {
+	t.Parallel()
+	tester := newTester()
+	defer tester.terminate()
+
+	tester.newPeer("peer", 65, testChainBase)
+	if err := tester.sync("peer", nil, SnapSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertOwnChain(t, tester, blockSyncItems, snapshotDBBaseNum)
+
+	if n := atomic.LoadInt32(&tester.peers["peer"].rangeRequests); n != 0 {
+		t.Fatalf("eth/65 peer received %d state range queries", n)
+	}
+}
+
 // Tests that if a large batch of blocks are being downloaded, it is throttled
 // until the cached blocks are retrieved.
 func TestThrottling62(t *testing.T) { testThrottling(t, 62, FullSync) }
@@ -1667,6 +1711,18 @@ func (ftp *floodingTestPeer) RequestOriginAndPivotByCurrent(d uint64) error {
 	return ftp.peer.RequestOriginAndPivotByCurrent(d)
 }
 
+func (ftp *floodingTestPeer) RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error {
+	return ftp.peer.RequestAccountRange(root, origin, limit, bytes)
+}
+
+func (ftp *floodingTestPeer) RequestStorageRanges(root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error {
+	return ftp.peer.RequestStorageRanges(root, accounts, origin, limit, bytes)
+}
+
+func (ftp *floodingTestPeer) RequestByteCodes(hashes []common.Hash, bytes uint64) error {
+	return ftp.peer.RequestByteCodes(hashes, bytes)
+}
+
 func (ftp *floodingTestPeer) RequestHeadersByNumber(from uint64, count, skip int, reverse bool) error {
 	deliveriesDone := make(chan struct{}, 500)
 	for i := 0; i < cap(deliveriesDone); i++ {
@@ -234,3 +234,30 @@ func (p *FakePeer) RequestOriginAndPivotByCurrent(m uint64) error {
 	}
 	return nil
 }
+
+// RequestAccountRange implements downloader.Peer, answering that no state ranges
+// are available, which makes snap sync heal the whole state trie.
+func (p *FakePeer) RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error {
+	p.dl.DeliverAccountRange(p.id, nil, nil, nil)
+	return nil
+}
+
+// RequestStorageRanges implements downloader.Peer, answering that no storage
+// ranges are available.
+func (p *FakePeer) RequestStorageRanges(root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error {
+	p.dl.DeliverStorageRanges(p.id, nil, nil, nil)
+	return nil
+}
+
+// RequestByteCodes implements downloader.Peer, returning a batch of contract
+// codes corresponding to the specified hashes.
+func (p *FakePeer) RequestByteCodes(hashes []common.Hash, bytes uint64) error {
+	var codes [][]byte
+	for _, hash := range hashes {
+		if code, err := p.db.Get(hash.Bytes()); err == nil {
+			codes = append(codes, code)
+		}
+	}
+	p.dl.DeliverByteCodes(p.id, codes)
+	return nil
+}
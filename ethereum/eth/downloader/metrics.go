@@ -27,4 +27,7 @@ var (
 
 	dposStorageInMeter   = metrics.NewRegisteredMeter("eth/downloader/dposStorage/in", nil)
 	dposStorageDropMeter = metrics.NewRegisteredMeter("eth/downloader/dposStorage/drop", nil)
+
+	snapInMeter   = metrics.NewRegisteredMeter("eth/downloader/snap/in", nil)
+	snapDropMeter = metrics.NewRegisteredMeter("eth/downloader/snap/drop", nil)
 )
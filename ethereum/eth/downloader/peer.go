@@ -75,6 +75,9 @@ type Peer interface {
 	RequestNodeData([]common.Hash) error
 	RequestDPOSStorage() error
 	RequestOriginAndPivotByCurrent(uint64) error
+	RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error
+	RequestStorageRanges(root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error
+	RequestByteCodes(hashes []common.Hash, bytes uint64) error
 }
 
 // lightPeerWrapper wraps a LightPeer struct, stubbing out the Peer-only methods.
@@ -107,6 +110,18 @@ func (w *lightPeerWrapper) RequestOriginAndPivotByCurrent(uint64) error {
 	panic("RequestOriginAndPivotByCurrent not supported in light client mode sync")
 }
 
+func (w *lightPeerWrapper) RequestAccountRange(common.Hash, common.Hash, common.Hash, uint64) error {
+	panic("RequestAccountRange not supported in light client mode sync")
+}
+
+func (w *lightPeerWrapper) RequestStorageRanges(common.Hash, []common.Hash, []byte, []byte, uint64) error {
+	panic("RequestStorageRanges not supported in light client mode sync")
+}
+
+func (w *lightPeerWrapper) RequestByteCodes([]common.Hash, uint64) error {
+	panic("RequestByteCodes not supported in light client mode sync")
+}
+
 // newPeerConnection creates a new downloader peer.
 func newPeerConnection(id string, version int, peer Peer, logger log.Logger) *peerConnection {
 	return &peerConnection{
@@ -476,7 +491,7 @@ func (ps *peerSet) HeaderIdlePeers() ([]*peerConnection, int) {
 		defer p.lock.RUnlock()
 		return p.headerThroughput
 	}
-	return ps.idlePeers(62, 65, idle, throughput)
+	return ps.idlePeers(62, 66, idle, throughput)
 }
 
 // BodyIdlePeers retrieves a flat list of all the currently body-idle peers within
@@ -490,7 +505,7 @@ func (ps *peerSet) BodyIdlePeers() ([]*peerConnection, int) {
 		defer p.lock.RUnlock()
 		return p.blockThroughput
 	}
-	return ps.idlePeers(62, 65, idle, throughput)
+	return ps.idlePeers(62, 66, idle, throughput)
 }
 
 // ReceiptIdlePeers retrieves a flat list of all the currently receipt-idle peers
@@ -504,7 +519,7 @@ func (ps *peerSet) ReceiptIdlePeers() ([]*peerConnection, int) {
 		defer p.lock.RUnlock()
 		return p.receiptThroughput
 	}
-	return ps.idlePeers(63, 65, idle, throughput)
+	return ps.idlePeers(63, 66, idle, throughput)
 }
 
 // NodeDataIdlePeers retrieves a flat list of all the currently node-data-idle
@@ -518,7 +533,7 @@ func (ps *peerSet) NodeDataIdlePeers() ([]*peerConnection, int) {
 		defer p.lock.RUnlock()
 		return p.stateThroughput
 	}
-	return ps.idlePeers(63, 65, idle, throughput)
+	return ps.idlePeers(63, 66, idle, throughput)
 }
 
 // idlePeers retrieves a flat list of all currently idle peers satisfying the
@@ -0,0 +1,438 @@
+package downloader
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/trie"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb/memorydb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+var (
+	snapRangeBytes      uint64 = 512 * 1024 // Soft size limit of range responses requested from peers
+	maxSnapStorageFetch        = 16         // Amount of accounts to request storage ranges for at once
+	maxSnapCodeFetch           = 64         // Amount of contract codes to request at once
+	snapFlushLeaves            = 8192       // Number of leaves inserted into a trie before flushing it
+)
+
+var (
+	// errSnapUnavailable is returned by the range phase of snap sync if the sync
+	// peer can't serve the ranges, either because it runs a protocol version
+	// without them or because it lacks the state. The remaining state is then
+	// healed.
+	errSnapUnavailable = errors.New("state ranges unavailable")
+
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	emptyCode = crypto.Keccak256Hash(nil)
+	maxHash   = common.HexToHash("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+)
+
+// syncSnapState starts downloading the state with the given root as proven
+// ranges of accounts and storage slots from the sync peer, filling in whatever
+// the ranges didn't cover with a regular trie node sync afterwards.
+//
+// Every trie node is written only once all the data below it is present, so
+// the healing trie sync never descends into the ranges already downloaded.
+func (d *Downloader) syncSnapState(p *peerConnection, root common.Hash) *stateSync {
+	s := newStateSync(d, root)
+	go func() {
+		start := time.Now()
+		switch err := d.fetchSnapRanges(p, s); err {
+		case nil:
+			log.Info("Downloaded state ranges", "root", root, "elapsed", common.PrettyDuration(time.Since(start)))
+		case errSnapUnavailable:
+			log.Info("State ranges unavailable, healing trie", "peer", p.id, "root", root)
+		default:
+			s.err = err
+			close(s.done)
+			return
+		}
+		// Schedule the healing only now, so that it sees the downloaded ranges.
+		s.sched = state.NewStateSync(root, d.stateDB, d.stateBloom)
+		select {
+		case d.stateSyncStart <- s:
+		case <-d.quitCh:
+			s.err = errCancelStateFetch
+			close(s.done)
+		}
+	}()
+	return s
+}
+
+// startStateSync starts downloading the state with the given root, the way the
+// current sync mode does it.
+func (d *Downloader) startStateSync(p *peerConnection, root common.Hash) *stateSync {
+	if d.mode == SnapSync {
+		return d.syncSnapState(p, root)
+	}
+	return d.syncState(root)
+}
+
+// fetchSnapRanges walks the account keyspace of the state in ascending order.
+// For each verified chunk of accounts the storage tries and contract codes are
+// downloaded first, then the accounts are inserted into the account trie.
+func (d *Downloader) fetchSnapRanges(p *peerConnection, s *stateSync) error {
+	// Only peers which negotiated eth/66 serve the ranges, never query others
+	if p.version < 66 {
+		return errSnapUnavailable
+	}
+	var (
+		triedb  = trie.NewDatabase(&bloomStore{d.stateDB, d.stateBloom})
+		accTrie = newSnapTrie(triedb)
+		origin  common.Hash
+
+		accounts, slots, codes int
+	)
+	for {
+		go p.peer.RequestAccountRange(s.root, origin, maxHash, snapRangeBytes)
+		packet, err := d.waitSnapPacket(p, s, d.accountRangeCh)
+		if err != nil {
+			return err
+		}
+		pack := packet.(*accountRangePack)
+		if len(pack.proof) == 0 {
+			return errSnapUnavailable
+		}
+		if len(pack.hashes) != len(pack.accounts) {
+			p.log.Warn("Inconsistent account range", "hashes", len(pack.hashes), "accounts", len(pack.accounts))
+			return errBadPeer
+		}
+		keys := make([][]byte, len(pack.hashes))
+		for i, hash := range pack.hashes {
+			keys[i] = hash[:]
+		}
+		var last []byte
+		if len(keys) > 0 {
+			last = keys[len(keys)-1]
+		}
+		more, err := trie.VerifyRangeProof(s.root, origin[:], last, keys, pack.accounts, proofDB(pack.proof))
+		if err != nil {
+			p.log.Warn("Invalid account range", "origin", origin, "err", err)
+			return errBadPeer
+		}
+		// Download everything the accounts refer to before inserting them
+		var (
+			storageHashes []common.Hash
+			storageRoots  []common.Hash
+			codeHashes    = make(map[common.Hash]struct{})
+		)
+		for i, blob := range pack.accounts {
+			var acc state.Account
+			if err := rlp.DecodeBytes(blob, &acc); err != nil {
+				return err
+			}
+			if acc.Root != emptyRoot {
+				if ok, _ := d.stateDB.Has(acc.Root[:]); !ok {
+					storageHashes = append(storageHashes, pack.hashes[i])
+					storageRoots = append(storageRoots, acc.Root)
+				}
+			}
+			if hash := common.BytesToHash(acc.CodeHash); hash != emptyCode {
+				if ok, _ := d.stateDB.Has(hash[:]); !ok {
+					codeHashes[hash] = struct{}{}
+				}
+			}
+		}
+		n, err := d.fetchSnapStorage(p, s, triedb, storageHashes, storageRoots)
+		if err != nil {
+			return err
+		}
+		slots += n
+		if err := d.fetchSnapCodes(p, s, codeHashes); err != nil {
+			return err
+		}
+		codes += len(codeHashes)
+
+		for i, key := range keys {
+			if err := accTrie.update(key, pack.accounts[i]); err != nil {
+				return err
+			}
+		}
+		accounts += len(keys)
+		log.Debug("Imported state range", "accounts", accounts, "slots", slots, "codes", codes, "origin", origin)
+
+		if !more || bytes.Equal(last, maxHash[:]) {
+			break
+		}
+		origin = incHash(common.BytesToHash(last))
+	}
+	root, err := accTrie.flush()
+	if err != nil {
+		return err
+	}
+	log.Info("Imported state ranges", "accounts", accounts, "slots", slots, "codes", codes)
+	if root != s.root {
+		// Possible only if the state changed under the peer, heal the rest.
+		return errSnapUnavailable
+	}
+	return nil
+}
+
+// fetchSnapStorage downloads the storage tries with the given roots of the
+// accounts, returning the number of slots written.
+func (d *Downloader) fetchSnapStorage(p *peerConnection, s *stateSync, triedb *trie.Database, accounts []common.Hash, roots []common.Hash) (int, error) {
+	var slots int
+	for len(accounts) > 0 {
+		batch := accounts
+		if len(batch) > maxSnapStorageFetch {
+			batch = batch[:maxSnapStorageFetch]
+		}
+		go p.peer.RequestStorageRanges(s.root, batch, nil, nil, snapRangeBytes)
+		packet, err := d.waitSnapPacket(p, s, d.storageRangesCh)
+		if err != nil {
+			return slots, err
+		}
+		pack := packet.(*storageRangesPack)
+		if len(pack.slots) == 0 || len(pack.slots) > len(batch) || len(pack.hashes) != len(pack.slots) {
+			return slots, errSnapUnavailable
+		}
+		for i := range pack.slots {
+			tr := newSnapTrie(triedb)
+			if err := tr.updateRange(pack.hashes[i], pack.slots[i]); err != nil {
+				return slots, err
+			}
+			slots += len(pack.slots[i])
+
+			// The last account of the response may be cut short, fetch the rest
+			if i == len(pack.slots)-1 && len(pack.proof) > 0 {
+				n, err := d.fetchSnapLargeStorage(p, s, tr, accounts[i], roots[i], pack)
+				slots += n
+				if err != nil {
+					return slots, err
+				}
+			}
+			root, err := tr.flush()
+			if err != nil {
+				return slots, err
+			}
+			if root != roots[i] {
+				p.log.Warn("Invalid storage range", "account", accounts[i], "root", root, "want", roots[i])
+				return slots, errBadPeer
+			}
+		}
+		accounts, roots = accounts[len(pack.slots):], roots[len(pack.slots):]
+	}
+	return slots, nil
+}
+
+// fetchSnapLargeStorage continues downloading a storage trie which didn't fit
+// into a single response, verifying every chunk by its range proof.
+func (d *Downloader) fetchSnapLargeStorage(p *peerConnection, s *stateSync, tr *snapTrie, account, root common.Hash, pack *storageRangesPack) (int, error) {
+	var (
+		slots  int
+		origin common.Hash
+		hashes = pack.hashes[len(pack.hashes)-1]
+		values = pack.slots[len(pack.slots)-1]
+		proof  = pack.proof
+	)
+	for {
+		keys := make([][]byte, len(hashes))
+		for i, hash := range hashes {
+			keys[i] = hash[:]
+		}
+		var last []byte
+		if len(keys) > 0 {
+			last = keys[len(keys)-1]
+		}
+		more, err := trie.VerifyRangeProof(root, origin[:], last, keys, values, proofDB(proof))
+		if err != nil {
+			p.log.Warn("Invalid storage range", "account", account, "origin", origin, "err", err)
+			return slots, errBadPeer
+		}
+		if !more || bytes.Equal(last, maxHash[:]) {
+			return slots, nil
+		}
+		origin = incHash(common.BytesToHash(last))
+
+		go p.peer.RequestStorageRanges(s.root, []common.Hash{account}, origin[:], nil, snapRangeBytes)
+		packet, err := d.waitSnapPacket(p, s, d.storageRangesCh)
+		if err != nil {
+			return slots, err
+		}
+		next := packet.(*storageRangesPack)
+		if len(next.slots) != 1 || len(next.hashes) != 1 || len(next.proof) == 0 {
+			return slots, errSnapUnavailable
+		}
+		hashes, values, proof = next.hashes[0], next.slots[0], next.proof
+		if err := tr.updateRange(hashes, values); err != nil {
+			return slots, err
+		}
+		slots += len(values)
+	}
+}
+
+// fetchSnapCodes downloads the contract codes with the given hashes.
+func (d *Downloader) fetchSnapCodes(p *peerConnection, s *stateSync, hashes map[common.Hash]struct{}) error {
+	pending := make(map[common.Hash]struct{}, len(hashes))
+	for hash := range hashes {
+		pending[hash] = struct{}{}
+	}
+	for len(pending) > 0 {
+		var request []common.Hash
+		for hash := range pending {
+			if request = append(request, hash); len(request) >= maxSnapCodeFetch {
+				break
+			}
+		}
+		go p.peer.RequestByteCodes(request, snapRangeBytes)
+		packet, err := d.waitSnapPacket(p, s, d.byteCodesCh)
+		if err != nil {
+			return err
+		}
+		batch := d.stateDB.NewBatch()
+		for _, code := range packet.(*byteCodesPack).codes {
+			hash := crypto.Keccak256Hash(code)
+			if _, ok := pending[hash]; !ok {
+				continue
+			}
+			if err := batch.Put(hash[:], code); err != nil {
+				return err
+			}
+			if d.stateBloom != nil {
+				d.stateBloom.Add(hash[:])
+			}
+			delete(pending, hash)
+		}
+		if batch.ValueSize() == 0 {
+			return errSnapUnavailable
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitSnapPacket waits for the response of the sync peer to a range request.
+func (d *Downloader) waitSnapPacket(p *peerConnection, s *stateSync, ch chan dataPack) (dataPack, error) {
+	ttl := d.requestTTL()
+	timeout := time.NewTimer(ttl)
+	defer timeout.Stop()
+	for {
+		select {
+		case <-s.cancel:
+			return nil, errCancelStateFetch
+		case <-d.cancelCh:
+			return nil, errCanceled
+		case <-timeout.C:
+			p.log.Debug("Waiting for state range timed out", "elapsed", ttl)
+			return nil, errSnapUnavailable
+		case packet := <-ch:
+			// Discard anything not from the sync peer
+			if packet.PeerId() != p.id {
+				log.Debug("Received state range from incorrect peer", "peer", packet.PeerId())
+				continue
+			}
+			return packet, nil
+		}
+	}
+}
+
+// snapTrie assembles a trie from leaves inserted in ascending key order. The
+// trie is flushed to the database every so often, which keeps only the right
+// edge of it in memory. The nodes on the edge are flushed before they're final
+// but, being unreferenced by the complete trie, they're merely dead weight.
+type snapTrie struct {
+	triedb  *trie.Database
+	trie    *trie.Trie
+	pending int
+}
+
+func newSnapTrie(triedb *trie.Database) *snapTrie {
+	tr, _ := trie.New(common.Hash{}, triedb)
+	return &snapTrie{triedb: triedb, trie: tr}
+}
+
+// update inserts a leaf into the trie.
+func (t *snapTrie) update(key, value []byte) error {
+	if err := t.trie.TryUpdate(key, value); err != nil {
+		return err
+	}
+	if t.pending++; t.pending >= snapFlushLeaves {
+		if _, err := t.flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateRange inserts a range of leaves into the trie.
+func (t *snapTrie) updateRange(keys []common.Hash, values [][]byte) error {
+	if len(keys) != len(values) {
+		return errBadPeer
+	}
+	for i, key := range keys {
+		if err := t.update(key[:], values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flush writes the trie to the database, returning its current root.
+func (t *snapTrie) flush() (common.Hash, error) {
+	root, err := t.trie.Commit(nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := t.triedb.Commit(root, false, true); err != nil {
+		return common.Hash{}, err
+	}
+	if t.trie, err = trie.New(root, t.triedb); err != nil {
+		return common.Hash{}, err
+	}
+	t.pending = 0
+	return root, nil
+}
+
+// bloomStore is the database the tries of snap sync are written to. It marks
+// every written node in the state bloom, so that the healing trie sync doesn't
+// download it again.
+type bloomStore struct {
+	ethdb.KeyValueStore
+	bloom *trie.SyncBloom
+}
+
+func (s *bloomStore) NewBatch() ethdb.Batch {
+	return &bloomBatch{s.KeyValueStore.NewBatch(), s.bloom}
+}
+
+// bloomBatch is a write batch of a bloomStore.
+type bloomBatch struct {
+	ethdb.Batch
+	bloom *trie.SyncBloom
+}
+
+func (b *bloomBatch) Put(key, value []byte) error {
+	if b.bloom != nil {
+		b.bloom.Add(key)
+	}
+	return b.Batch.Put(key, value)
+}
+
+// proofDB collects the nodes of a range proof for verification.
+func proofDB(proof [][]byte) ethdb.KeyValueReader {
+	db := memorydb.New()
+	for _, node := range proof {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	return db
+}
+
+// incHash returns the hash following h in the keyspace.
+func incHash(h common.Hash) common.Hash {
+	for i := len(h) - 1; i >= 0; i-- {
+		h[i]++
+		if h[i] != 0 {
+			break
+		}
+	}
+	return h
+}
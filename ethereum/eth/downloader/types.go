@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 )
 
 // peerDropFn is a callback type for dropping a peer detected as malicious.
@@ -90,3 +91,37 @@ type dposInfoPack struct {
 func (p *dposInfoPack) PeerId() string { return p.peerID }
 func (p *dposInfoPack) Items() int     { return 1 }
 func (p *dposInfoPack) Stats() string  { return fmt.Sprint(1) }
+
+// accountRangePack is a range of accounts returned by a peer.
+type accountRangePack struct {
+	peerID   string
+	hashes   []common.Hash
+	accounts [][]byte
+	proof    [][]byte
+}
+
+func (p *accountRangePack) PeerId() string { return p.peerID }
+func (p *accountRangePack) Items() int     { return len(p.accounts) }
+func (p *accountRangePack) Stats() string  { return fmt.Sprintf("%d", len(p.accounts)) }
+
+// storageRangesPack is a batch of storage slot ranges returned by a peer.
+type storageRangesPack struct {
+	peerID string
+	hashes [][]common.Hash
+	slots  [][][]byte
+	proof  [][]byte
+}
+
+func (p *storageRangesPack) PeerId() string { return p.peerID }
+func (p *storageRangesPack) Items() int     { return len(p.slots) }
+func (p *storageRangesPack) Stats() string  { return fmt.Sprintf("%d", len(p.slots)) }
+
+// byteCodesPack is a batch of contract codes returned by a peer.
+type byteCodesPack struct {
+	peerID string
+	codes  [][]byte
+}
+
+func (p *byteCodesPack) PeerId() string { return p.peerID }
+func (p *byteCodesPack) Items() int     { return len(p.codes) }
+func (p *byteCodesPack) Stats() string  { return fmt.Sprintf("%d", len(p.codes)) }
@@ -0,0 +1,392 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/evidence"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/dposclient"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/consensus"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/gov"
+	xplugin "github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/plugin"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/slashing"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/xutil"
+)
+
+const (
+	// evidenceReportInterval is the interval at which the evidence pool is
+	// checked for evidence to report.
+	evidenceReportInterval = 5 * time.Second
+	// evidenceReportTimeout is the time a report transaction may stay pending
+	// before it is considered lost and the evidence is reported again.
+	evidenceReportTimeout = 2 * time.Minute
+	// minReportBackoff and maxReportBackoff bound the delay between two
+	// failed attempts to report the same evidence.
+	minReportBackoff = 10 * time.Second
+	maxReportBackoff = 10 * time.Minute
+)
+
+var evidenceReportPrefix = []byte("evidence-report-") // evidenceReportPrefix + evidence hash -> EvidenceReport
+
+// ReportStatus is the state of the report of a duplicate-sign evidence.
+type ReportStatus uint8
+
+const (
+	// ReportPending means the report transaction was sent and waits to be included.
+	ReportPending ReportStatus = iota
+	// ReportFailed means the last attempt failed, it is retried after a backoff.
+	ReportFailed
+	// ReportAccepted means the slashing contract accepted the evidence.
+	ReportAccepted
+	// ReportRejected means the slashing contract rejected the evidence.
+	ReportRejected
+	// ReportHandled means the duplicate sign was already slashed by another report.
+	ReportHandled
+	// ReportExpired means the evidence is older than the MaxEvidenceAge governance parameter.
+	ReportExpired
+)
+
+func (s ReportStatus) String() string {
+	switch s {
+	case ReportPending:
+		return "pending"
+	case ReportFailed:
+		return "failed"
+	case ReportAccepted:
+		return "accepted"
+	case ReportRejected:
+		return "rejected"
+	case ReportHandled:
+		return "handled"
+	case ReportExpired:
+		return "expired"
+	}
+	return "unknown"
+}
+
+// Final reports whether the evidence needs no further report.
+func (s ReportStatus) Final() bool {
+	return s != ReportPending && s != ReportFailed
+}
+
+// EvidenceReport records the submission of a duplicate-sign evidence to the
+// slashing contract.
+type EvidenceReport struct {
+	Hash        common.Hash
+	Type        consensus.EvidenceType
+	NodeID      discover.NodeID
+	BlockNumber uint64
+	Status      ReportStatus
+	Attempts    uint32
+	TxHash      common.Hash // Hash of the last report transaction
+	Code        uint32      // Result code of the slashing contract
+	Error       string      // Reason of the last failure
+	Time        uint64      // Unix time of the last attempt
+}
+
+// EvidenceReporter submits the duplicate-sign evidence collected by the
+// consensus engine to the slashing contract, signing the reports with a
+// configured account.
+type EvidenceReporter struct {
+	eth     *Ethereum
+	pool    consensus.EvidencePool
+	account accounts.Account
+	db      ethdb.Database
+
+	mu      sync.Mutex
+	reports map[common.Hash]*EvidenceReport
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewEvidenceReporter creates a reporter of the evidence in pool, the account
+// must be unlocked in the account manager of eth.
+func NewEvidenceReporter(eth *Ethereum, pool consensus.EvidencePool, reporter common.Address) *EvidenceReporter {
+	return &EvidenceReporter{
+		eth:     eth,
+		pool:    pool,
+		account: accounts.Account{Address: reporter},
+		db:      eth.ChainDb(),
+		reports: make(map[common.Hash]*EvidenceReport),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start starts reporting evidence in the background.
+func (r *EvidenceReporter) Start() {
+	log.Info("Starting evidence reporter", "account", r.account.Address)
+	r.wg.Add(1)
+	go r.loop()
+}
+
+// Stop stops the reporter and waits for the current round to finish.
+func (r *EvidenceReporter) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+	log.Info("Evidence reporter stopped")
+}
+
+// Reports returns the records of all the evidence handled by the reporter
+// since it started.
+func (r *EvidenceReporter) Reports() []EvidenceReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reports := make([]EvidenceReport, 0, len(r.reports))
+	for _, report := range r.reports {
+		reports = append(reports, *report)
+	}
+	return reports
+}
+
+func (r *EvidenceReporter) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(evidenceReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.reportAll()
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// reportAll walks through the evidence in the pool and reports the ones that
+// still need to be reported.
+func (r *EvidenceReporter) reportAll() {
+	evs := r.pool.Evidences()
+	if len(evs) == 0 {
+		return
+	}
+	head := r.eth.BlockChain().CurrentBlock()
+	maxAge, err := gov.GovernMaxEvidenceAge(head.NumberU64(), head.Hash())
+	if err != nil {
+		log.Error("Failed to query MaxEvidenceAge", "blockNumber", head.NumberU64(), "err", err)
+		return
+	}
+	statedb, err := r.eth.BlockChain().StateAt(head.Root())
+	if err != nil {
+		log.Error("Failed to open the state of the chain head", "blockNumber", head.NumberU64(), "err", err)
+		return
+	}
+	now := time.Now()
+	for _, ev := range evs {
+		if !evidenceReportable(ev.Type()) {
+			continue
+		}
+		report := r.report(ev)
+		if report.Status.Final() {
+			continue
+		}
+		if report.Status == ReportPending && !r.checkPending(report, now) {
+			continue
+		}
+		if evidenceExpired(ev.BlockNumber(), head.NumberU64(), maxAge) {
+			r.finish(report, ReportExpired, 0)
+			continue
+		}
+		if txHash, err := xplugin.SlashInstance().CheckDuplicateSign(ev.NodeID(), ev.BlockNumber(), ev.Type(), statedb); err == nil && len(txHash) > 0 {
+			report.TxHash = common.BytesToHash(txHash)
+			r.finish(report, ReportHandled, slashing.ErrSlashingExist.Code)
+			continue
+		}
+		if report.Status == ReportFailed && now.Before(time.Unix(int64(report.Time), 0).Add(reportBackoff(report.Attempts))) {
+			continue
+		}
+		r.submit(ev, report, now)
+	}
+}
+
+// report returns the record of ev, loading it from the database if the
+// reporter has not seen it since it started.
+func (r *EvidenceReporter) report(ev consensus.Evidence) *EvidenceReport {
+	hash := common.BytesToHash(ev.Hash())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if report, ok := r.reports[hash]; ok {
+		return report
+	}
+	report := new(EvidenceReport)
+	if data, err := r.db.Get(append(evidenceReportPrefix, hash.Bytes()...)); err == nil {
+		if err := rlp.DecodeBytes(data, report); err != nil {
+			log.Warn("Invalid evidence report record", "hash", hash, "err", err)
+			report = new(EvidenceReport)
+		}
+	}
+	if report.Hash == (common.Hash{}) {
+		report.Hash = hash
+		report.Type = ev.Type()
+		report.NodeID = ev.NodeID()
+		report.BlockNumber = ev.BlockNumber()
+		report.Status = ReportFailed
+	}
+	r.reports[hash] = report
+	return report
+}
+
+// checkPending looks up the result of the pending report transaction. It
+// returns true if the evidence has to be reported again.
+func (r *EvidenceReporter) checkPending(report *EvidenceReport, now time.Time) bool {
+	receipt, _, _, _ := rawdb.ReadReceipt(r.db, report.TxHash, r.eth.BlockChain().Config())
+	if receipt == nil {
+		if r.eth.TxPool().Get(report.TxHash) == nil && now.After(time.Unix(int64(report.Time), 0).Add(evidenceReportTimeout)) {
+			r.fail(report, "report transaction dropped")
+			return true
+		}
+		return false
+	}
+	res, err := dposclient.DecodeTxResult(receipt)
+	if err != nil {
+		r.fail(report, err.Error())
+		return true
+	}
+	switch {
+	case res.Code == common.OkCode:
+		r.finish(report, ReportAccepted, res.Code)
+	case res.Code == slashing.ErrSlashingExist.Code:
+		r.finish(report, ReportHandled, res.Code)
+	default:
+		report.Error = res.Err().Error()
+		r.finish(report, ReportRejected, res.Code)
+	}
+	return false
+}
+
+// submit sends a reportDuplicateSign transaction for ev.
+func (r *EvidenceReporter) submit(ev consensus.Evidence, report *EvidenceReport, now time.Time) {
+	r.mu.Lock()
+	report.Attempts++
+	report.Time = uint64(now.Unix())
+	r.mu.Unlock()
+
+	tx, err := r.newReportTx(ev)
+	if err != nil {
+		r.fail(report, err.Error())
+		return
+	}
+	if err := r.eth.TxPool().AddLocal(tx); err != nil {
+		r.fail(report, err.Error())
+		return
+	}
+	r.mu.Lock()
+	report.Status = ReportPending
+	report.TxHash = tx.Hash()
+	report.Error = ""
+	r.mu.Unlock()
+	r.store(report)
+	log.Info("Reported duplicate sign evidence", "type", ev.Type(), "nodeId", ev.NodeID().TerminalString(),
+		"blockNumber", ev.BlockNumber(), "txHash", tx.Hash(), "attempts", report.Attempts)
+}
+
+// newReportTx builds and signs the transaction reporting ev.
+func (r *EvidenceReporter) newReportTx(ev consensus.Evidence) (*types.Transaction, error) {
+	input, err := reportTxInput(ev)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := core.IntrinsicGas(input, nil, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	gas += configs.SlashingGas + configs.ReportDuplicateSignGas + configs.DuplicateEvidencesGas
+
+	gasPrice, err := r.eth.APIBackend.SuggestPrice(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := r.eth.AccountManager().Find(r.account)
+	if err != nil {
+		return nil, err
+	}
+	nonce := r.eth.TxPool().Nonce(r.account.Address)
+	tx := types.NewTransaction(nonce, vm.SlashingContractAddr, new(big.Int), gas, gasPrice, input)
+	return wallet.SignTx(r.account, tx, r.eth.BlockChain().Config().ChainID)
+}
+
+// reportTxInput encodes the reportDuplicateSign call of ev.
+func reportTxInput(ev consensus.Evidence) ([]byte, error) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	return dposclient.EncodeFunc(dposclient.TxReportDuplicateSign, uint8(ev.Type()), string(data))
+}
+
+func (r *EvidenceReporter) fail(report *EvidenceReport, reason string) {
+	r.mu.Lock()
+	report.Status = ReportFailed
+	report.Error = reason
+	r.mu.Unlock()
+	r.store(report)
+	log.Warn("Failed to report duplicate sign evidence", "hash", report.Hash, "nodeId", report.NodeID.TerminalString(),
+		"blockNumber", report.BlockNumber, "attempts", report.Attempts, "retryIn", reportBackoff(report.Attempts), "err", reason)
+}
+
+func (r *EvidenceReporter) finish(report *EvidenceReport, status ReportStatus, code uint32) {
+	r.mu.Lock()
+	report.Status = status
+	report.Code = code
+	r.mu.Unlock()
+	r.store(report)
+	log.Info("Duplicate sign evidence report finished", "hash", report.Hash, "nodeId", report.NodeID.TerminalString(),
+		"blockNumber", report.BlockNumber, "status", status, "code", code, "txHash", report.TxHash)
+}
+
+func (r *EvidenceReporter) store(report *EvidenceReport) {
+	r.mu.Lock()
+	data, err := rlp.EncodeToBytes(report)
+	r.mu.Unlock()
+	if err != nil {
+		log.Error("Failed to encode evidence report", "hash", report.Hash, "err", err)
+		return
+	}
+	if err := r.db.Put(append(evidenceReportPrefix, report.Hash.Bytes()...), data); err != nil {
+		log.Error("Failed to store evidence report", "hash", report.Hash, "err", err)
+	}
+}
+
+// evidenceExpired reports whether evidence of the given block can no longer be
+// reported at blockNumber, applying the same rule as the slashing plugin.
+func evidenceExpired(evidenceNumber, blockNumber uint64, maxAge uint32) bool {
+	blocksOfEpoch := xutil.CalcBlocksEachEpoch()
+	invalidNum := xutil.CalculateEpoch(evidenceNumber) * blocksOfEpoch
+	return invalidNum < blockNumber && blockNumber-invalidNum > blocksOfEpoch*uint64(maxAge)
+}
+
+// evidenceReportable reports whether the slashing contract accepts evidence of
+// the given type. Duplicate PreCommits are kept in the pool for queries only.
+func evidenceReportable(dupType consensus.EvidenceType) bool {
+	return dupType != evidence.DuplicatePreCommitType
+}
+
+// reportBackoff returns the delay before the next attempt after the given
+// number of failed attempts.
+func reportBackoff(attempts uint32) time.Duration {
+	backoff := minReportBackoff
+	for i := uint32(1); i < attempts && backoff < maxReportBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxReportBackoff {
+		backoff = maxReportBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,105 @@
+package eth
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/evidence"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/protocols"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/xcom"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/xutil"
+)
+
+func TestEvidenceExpired(t *testing.T) {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+	blocks := xutil.CalcBlocksEachEpoch()
+
+	// Evidence stays valid for MaxEvidenceAge epochs after the end of its epoch.
+	assert.False(t, evidenceExpired(1, blocks, 1))
+	assert.False(t, evidenceExpired(1, 2*blocks, 1))
+	assert.True(t, evidenceExpired(1, 2*blocks+1, 1))
+	assert.False(t, evidenceExpired(1, 2*blocks+1, 2))
+}
+
+func TestReportBackoff(t *testing.T) {
+	assert.Equal(t, minReportBackoff, reportBackoff(0))
+	assert.Equal(t, minReportBackoff, reportBackoff(1))
+	assert.Equal(t, 2*minReportBackoff, reportBackoff(2))
+	assert.Equal(t, 8*minReportBackoff, reportBackoff(4))
+	assert.Equal(t, maxReportBackoff, reportBackoff(100))
+}
+
+func TestEvidenceReportRLP(t *testing.T) {
+	report := &EvidenceReport{
+		Hash:        common.HexToHash("0x01"),
+		Type:        2,
+		BlockNumber: 10,
+		Status:      ReportRejected,
+		Attempts:    3,
+		TxHash:      common.HexToHash("0x02"),
+		Code:        303004,
+		Error:       "not a validator",
+		Time:        uint64(time.Now().Unix()),
+	}
+	data, err := rlp.EncodeToBytes(report)
+	assert.Nil(t, err)
+	var decoded EvidenceReport
+	assert.Nil(t, rlp.DecodeBytes(data, &decoded))
+	assert.Equal(t, *report, decoded)
+	assert.True(t, decoded.Status.Final())
+	assert.False(t, ReportFailed.Final())
+}
+
+func TestSkipPreCommitEvidence(t *testing.T) {
+	path, err := ioutil.TempDir("", "evidence")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+	pool, err := evidence.NewBaseEvidencePool(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	var sk bls.SecretKey
+	sk.SetByCSPRNG()
+	key, _ := crypto.GenerateKey()
+	node := &pbfttypes.ValidateNode{
+		Index:     0,
+		Address:   crypto.PubkeyToNodeAddress(key.PublicKey),
+		PubKey:    &key.PublicKey,
+		NodeID:    discover.PubkeyID(&key.PublicKey),
+		BlsPubKey: sk.GetPublicKey(),
+	}
+	for i := 0; i < 2; i++ {
+		pc := &protocols.PreCommit{Epoch: 1, ViewNumber: 1, BlockHash: common.BytesToHash([]byte{byte(i + 1)}), BlockNumber: 5}
+		buf, err := pc.CannibalizeBytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pc.Signature.SetBytes(sk.Sign(string(buf)).Serialize())
+		pool.AddPreCommit(pc, node)
+	}
+	evs := pool.Evidences()
+	if !assert.Len(t, evs, 1) {
+		return
+	}
+	ev := evs[0]
+	assert.Equal(t, evidence.DuplicatePreCommitType, ev.Type())
+
+	// The slashing contract doesn't accept duplicate PreCommits, so they are
+	// never reported.
+	assert.False(t, evidenceReportable(ev.Type()))
+	assert.True(t, evidenceReportable(evidence.DuplicatePrepareVoteType))
+}
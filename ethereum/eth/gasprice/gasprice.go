@@ -57,8 +57,24 @@ func NewOracle(backend ethapi.Backend, params Config) *Oracle {
 	}
 }
 
-// SuggestPrice returns the recommended gas price.
+// SuggestPrice returns the recommended gas price, which is the suggested tip
+// on top of the base fee of the latest block once dynamic fees are enabled.
 func (gpo *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
+	tip, err := gpo.SuggestTipCap(ctx)
+	if err != nil {
+		return tip, err
+	}
+	head, _ := gpo.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil || head.BaseFee == nil {
+		return tip, nil
+	}
+	return new(big.Int).Add(tip, head.BaseFee), nil
+}
+
+// SuggestTipCap returns the recommended gas tip cap for dynamic fee
+// transactions. Before dynamic fees are enabled the tip of a transaction is
+// its gas price, so this equals the legacy gas price suggestion.
+func (gpo *Oracle) SuggestTipCap(ctx context.Context) (*big.Int, error) {
 	gpo.cacheLock.RLock()
 	lastHead := gpo.lastHead
 	lastPrice := gpo.lastPrice
@@ -136,14 +152,19 @@ type getBlockPricesResult struct {
 	err   error
 }
 
-type transactionsByGasPrice []*types.Transaction
+type transactionsByGasTip struct {
+	txs     []*types.Transaction
+	baseFee *big.Int
+}
 
-func (t transactionsByGasPrice) Len() int           { return len(t) }
-func (t transactionsByGasPrice) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
-func (t transactionsByGasPrice) Less(i, j int) bool { return t[i].GasPriceCmp(t[j]) < 0 }
+func (t transactionsByGasTip) Len() int      { return len(t.txs) }
+func (t transactionsByGasTip) Swap(i, j int) { t.txs[i], t.txs[j] = t.txs[j], t.txs[i] }
+func (t transactionsByGasTip) Less(i, j int) bool {
+	return t.txs[i].EffectiveGasTipCmp(t.txs[j], t.baseFee) < 0
+}
 
-// getBlockPrices calculates the lowest transaction gas price in a given block
-// and sends it to the result channel. If the block is empty, price is nil.
+// getBlockPrices calculates the lowest effective transaction gas tip in a given
+// block and sends it to the result channel. If the block is empty, price is nil.
 func (gpo *Oracle) getBlockPrices(ctx context.Context, signer types.Signer, blockNum uint64, ch chan getBlockPricesResult) {
 	block, err := gpo.backend.BlockByNumber(ctx, rpc.BlockNumber(blockNum))
 	if block == nil {
@@ -154,12 +175,12 @@ func (gpo *Oracle) getBlockPrices(ctx context.Context, signer types.Signer, bloc
 	blockTxs := block.Transactions()
 	txs := make([]*types.Transaction, len(blockTxs))
 	copy(txs, blockTxs)
-	sort.Sort(transactionsByGasPrice(txs))
+	sort.Sort(transactionsByGasTip{txs, block.BaseFee()})
 
 	for _, tx := range txs {
 		sender, err := types.Sender(signer, tx)
 		if err == nil && sender != block.Coinbase() {
-			ch <- getBlockPricesResult{tx.GasPrice(), nil}
+			ch <- getBlockPricesResult{tx.EffectiveGasTipValue(block.BaseFee()), nil}
 			return
 		}
 	}
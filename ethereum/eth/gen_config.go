@@ -30,6 +30,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TrieCache                int
 		TrieTimeout              time.Duration
 		TrieDBCache              int
+		SnapshotCache            int
 		DBDisabledGC             bool
 		DBGCInterval             uint64
 		DBGCTimeout              time.Duration
@@ -79,6 +80,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TrieCache = c.TrieCache
 	enc.TrieTimeout = c.TrieTimeout
 	enc.TrieDBCache = c.TrieDBCache
+	enc.SnapshotCache = c.SnapshotCache
 	enc.DBDisabledGC = c.DBDisabledGC
 	enc.DBGCInterval = c.DBGCInterval
 	enc.DBGCTimeout = c.DBGCTimeout
@@ -132,6 +134,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TrieCache                *int
 		TrieTimeout              *time.Duration
 		TrieDBCache              *int
+		SnapshotCache            *int
 		DBDisabledGC             *bool
 		DBGCInterval             *uint64
 		DBGCTimeout              *time.Duration
@@ -212,6 +215,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TrieDBCache != nil {
 		c.TrieDBCache = *dec.TrieDBCache
 	}
+	if dec.SnapshotCache != nil {
+		c.SnapshotCache = *dec.SnapshotCache
+	}
 	if dec.DBDisabledGC != nil {
 		c.DBDisabledGC = *dec.DBDisabledGC
 	}
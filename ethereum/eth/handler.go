@@ -77,6 +77,7 @@ type ProtocolManager struct {
 	networkID uint64
 
 	fastSync        uint32 // Flag whether fast sync is enabled (gets disabled if we already have blocks)
+	snapSync        uint32 // Flag whether fast sync should download the state as ranges
 	acceptTxs       uint32 // Flag whether we're considered synchronised (enables transaction processing)
 	acceptRemoteTxs uint32 // Flag whether we're accept remote txs
 
@@ -127,9 +128,12 @@ func NewProtocolManager(config *configs.ChainConfig, mode downloader2.SyncMode,
 		quitSync:    make(chan struct{}),
 		engine:      engine,
 	}
-	// If fast sync was requested and our database is empty, grant it
-	if mode == downloader2.FastSync && blockchain.CurrentBlock().NumberU64() == 0 {
+	// If fast or snap sync was requested and our database is empty, grant it
+	if (mode == downloader2.FastSync || mode == downloader2.SnapSync) && blockchain.CurrentBlock().NumberU64() == 0 {
 		manager.fastSync = uint32(1)
+		if mode == downloader2.SnapSync {
+			manager.snapSync = uint32(1)
+		}
 	}
 	// Initiate a sub-protocol for every implemented version we can handle
 	manager.SubProtocols = make([]p2p.Protocol, 0, len(ProtocolVersions))
@@ -861,6 +865,57 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		log.Trace("Handler Receive PooledTransactions", "peer", p.id, "txs", len(txs))
 		return pm.txFetcher.Enqueue(p.id, txs, true)
 
+	case p.version >= eth66 && msg.Code == GetAccountRangeMsg:
+		var req getAccountRangeData
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		return p.SendAccountRange(pm.serveAccountRange(&req))
+
+	case p.version >= eth66 && msg.Code == AccountRangeMsg:
+		var res accountRangeData
+		if err := msg.Decode(&res); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if err := pm.downloader.DeliverAccountRange(p.id, res.Hashes, res.Accounts, res.Proof); err != nil {
+			log.Debug("Failed to deliver account range", "err", err)
+		}
+
+	case p.version >= eth66 && msg.Code == GetStorageRangesMsg:
+		var req getStorageRangesData
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		return p.SendStorageRanges(pm.serveStorageRanges(&req))
+
+	case p.version >= eth66 && msg.Code == StorageRangesMsg:
+		var res storageRangesData
+		if err := msg.Decode(&res); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if len(res.Hashes) != len(res.Slots) {
+			return errResp(ErrDecode, "msg %v: storage hash and slot count mismatch", msg)
+		}
+		if err := pm.downloader.DeliverStorageRanges(p.id, res.Hashes, res.Slots, res.Proof); err != nil {
+			log.Debug("Failed to deliver storage ranges", "err", err)
+		}
+
+	case p.version >= eth66 && msg.Code == GetByteCodesMsg:
+		var req getByteCodesData
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		return p.SendByteCodes(pm.serveByteCodes(&req))
+
+	case p.version >= eth66 && msg.Code == ByteCodesMsg:
+		var codes [][]byte
+		if err := msg.Decode(&codes); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if err := pm.downloader.DeliverByteCodes(p.id, codes); err != nil {
+			log.Debug("Failed to deliver byte codes", "err", err)
+		}
+
 	default:
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
 	}
@@ -7,6 +7,7 @@ import (
 	"math/big"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
 
@@ -532,3 +533,38 @@ func testGetReceipt(t *testing.T, protocol int) {
 		t.Errorf("receipts mismatch: %v", err)
 	}
 }
+
+// Tests that the snap sync queries are only served to peers which negotiated
+// eth/66, and treated as invalid messages on the older versions.
+func TestGetAccountRange(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, downloader2.FullSync, 4, nil, nil)
+	defer pm.Stop()
+
+	req := &getAccountRangeData{
+		Root:  pm.blockchain.CurrentBlock().Root(),
+		Limit: common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+		Bytes: 512 * 1024,
+	}
+	res := pm.serveAccountRange(req)
+	if len(res.Accounts) == 0 {
+		t.Fatal("no accounts served from the head state")
+	}
+	peer, _ := newTestPeer("peer", eth66, pm, true)
+	p2p.Send(peer.app, GetAccountRangeMsg, req)
+	if err := p2p.ExpectMsg(peer.app, AccountRangeMsg, res); err != nil {
+		t.Errorf("account range mismatch: %v", err)
+	}
+	peer.close()
+
+	legacy, errc := newTestPeer("legacy", eth65, pm, true)
+	defer legacy.close()
+	go p2p.Send(legacy.app, GetAccountRangeMsg, req)
+	select {
+	case err := <-errc:
+		if want := errResp(ErrInvalidMsgCode, "%v", GetAccountRangeMsg); err == nil || err.Error() != want.Error() {
+			t.Errorf("wrong error: got %v, want %q", err, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Errorf("protocol did not shut down within 2 seconds")
+	}
+}
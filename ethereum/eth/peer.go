@@ -392,6 +392,22 @@ func (p *peer) SendReceiptsRLP(receipts []rlp.RawValue) error {
 	return p2p.Send(p.rw, ReceiptsMsg, receipts)
 }
 
+// SendAccountRange sends an account range of the state trie, answering a snap
+// sync query.
+func (p *peer) SendAccountRange(data *accountRangeData) error {
+	return p2p.Send(p.rw, AccountRangeMsg, data)
+}
+
+// SendStorageRanges sends storage slot ranges, answering a snap sync query.
+func (p *peer) SendStorageRanges(data *storageRangesData) error {
+	return p2p.Send(p.rw, StorageRangesMsg, data)
+}
+
+// SendByteCodes sends a batch of contract codes, answering a snap sync query.
+func (p *peer) SendByteCodes(codes [][]byte) error {
+	return p2p.Send(p.rw, ByteCodesMsg, codes)
+}
+
 // RequestOneHeader is a wrapper around the header query functions to fetch a
 // single header. It is used solely by the fetcher.
 func (p *peer) RequestOneHeader(hash common.Hash) error {
@@ -451,6 +467,26 @@ func (p *peer) RequestOriginAndPivotByCurrent(current uint64) error {
 	return nil
 }
 
+// RequestAccountRange fetches a range of accounts of the state trie with the
+// given root, along with the proofs of the range boundaries.
+func (p *peer) RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error {
+	p.Log().Debug("Fetching range of accounts", "root", root, "origin", origin, "limit", limit, "bytes", common.StorageSize(bytes))
+	return p2p.Send(p.rw, GetAccountRangeMsg, &getAccountRangeData{Root: root, Origin: origin, Limit: limit, Bytes: bytes})
+}
+
+// RequestStorageRanges fetches the storage slots of a batch of accounts,
+// starting at the given origin of the first account.
+func (p *peer) RequestStorageRanges(root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error {
+	p.Log().Debug("Fetching ranges of storage slots", "root", root, "accounts", len(accounts), "origin", common.BytesToHash(origin), "bytes", common.StorageSize(bytes))
+	return p2p.Send(p.rw, GetStorageRangesMsg, &getStorageRangesData{Root: root, Accounts: accounts, Origin: origin, Limit: limit, Bytes: bytes})
+}
+
+// RequestByteCodes fetches a batch of contract codes by their hashes.
+func (p *peer) RequestByteCodes(hashes []common.Hash, bytes uint64) error {
+	p.Log().Debug("Fetching batch of byte codes", "count", len(hashes), "bytes", common.StorageSize(bytes))
+	return p2p.Send(p.rw, GetByteCodesMsg, &getByteCodesData{Hashes: hashes, Bytes: bytes})
+}
+
 // Handshake executes the eth protocol handshake, negotiating version number,
 // network IDs, difficulties, head and genesis blocks.
 func (p *peer) Handshake(network uint64, bn *big.Int, head common.Hash, genesis common.Hash, pm *ProtocolManager) error {
@@ -17,16 +17,17 @@ const (
 	eth62 = 62
 	eth63 = 63
 	eth65 = 65
+	eth66 = 66
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 var ProtocolName = "phoenixchain"
 
 // ProtocolVersions are the upported versions of the eth protocol (first is primary).
-var ProtocolVersions = []uint{eth65, eth63, eth62}
+var ProtocolVersions = []uint{eth66, eth65, eth63, eth62}
 
 // ProtocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []uint64{40, 23, 8}
+var ProtocolLengths = []uint64{40, 40, 23, 8}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -61,6 +62,14 @@ const (
 	NewPooledTransactionHashesMsg = 0x16
 	GetPooledTransactionsMsg      = 0x17
 	PooledTransactionsMsg         = 0x18
+
+	// Protocol messages belonging to eth/66, serving snap sync
+	GetAccountRangeMsg  = 0x19
+	AccountRangeMsg     = 0x1a
+	GetStorageRangesMsg = 0x1b
+	StorageRangesMsg    = 0x1c
+	GetByteCodesMsg     = 0x1d
+	ByteCodesMsg        = 0x1e
 )
 
 type errCode int
@@ -104,6 +113,43 @@ type GetPooledTransactionsPacket []common.Hash
 // PooledTransactionsPacket is the network packet for transaction distribution.
 type PooledTransactionsPacket []*types.Transaction
 
+// getAccountRangeData represents an account range query of snap sync.
+type getAccountRangeData struct {
+	Root   common.Hash // Root of the account trie to serve the range from
+	Origin common.Hash // Hash of the first account to retrieve
+	Limit  common.Hash // Hash of the last account to retrieve
+	Bytes  uint64      // Soft limit at which to stop returning data
+}
+
+// accountRangeData is the network packet answering an account range query.
+type accountRangeData struct {
+	Hashes   []common.Hash // Hashes of the accounts in the range
+	Accounts [][]byte      // RLP encoded accounts in the range
+	Proof    [][]byte      // Merkle proofs of the range boundaries
+}
+
+// getStorageRangesData represents a storage slot range query of snap sync.
+type getStorageRangesData struct {
+	Root     common.Hash   // Root of the account trie holding the accounts
+	Accounts []common.Hash // Hashes of the accounts whose storage to retrieve
+	Origin   []byte        // Hash of the first slot of the first account to retrieve
+	Limit    []byte        // Hash of the last slot of the last account to retrieve
+	Bytes    uint64        // Soft limit at which to stop returning data
+}
+
+// storageRangesData is the network packet answering a storage range query.
+type storageRangesData struct {
+	Hashes [][]common.Hash // Hashes of the slots, per account
+	Slots  [][][]byte      // RLP encoded slot values, per account
+	Proof  [][]byte        // Merkle proofs of the last, possibly partial, account
+}
+
+// getByteCodesData represents a contract code query of snap sync.
+type getByteCodesData struct {
+	Hashes []common.Hash // Code hashes to retrieve
+	Bytes  uint64        // Soft limit at which to stop returning data
+}
+
 type txPool interface {
 	// Has returns an indicator whether txpool has a transaction
 	// cached with the given hash.
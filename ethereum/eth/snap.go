@@ -0,0 +1,184 @@
+package eth
+
+import (
+	"bytes"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/trie"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb/memorydb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// responseLimit caps the soft size limit requested by a snap sync query.
+func responseLimit(bytes uint64) int {
+	if bytes == 0 || bytes > softResponseLimit {
+		return softResponseLimit
+	}
+	return int(bytes)
+}
+
+// proofList flattens the nodes of a merkle proof for sending.
+func proofList(proof *memorydb.Database) [][]byte {
+	var nodes [][]byte
+	it := proof.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		nodes = append(nodes, common.CopyBytes(it.Value()))
+	}
+	return nodes
+}
+
+// serveAccountRange retrieves the accounts of the state trie with the given
+// root between the origin and limit hashes, along with the proofs of the range
+// boundaries. If the state is not available, an empty unproven range is
+// returned, telling the requester to fall back to downloading trie nodes.
+func (pm *ProtocolManager) serveAccountRange(req *getAccountRangeData) *accountRangeData {
+	res := new(accountRangeData)
+	tr, err := trie.New(req.Root, pm.blockchain.StateCache().TrieDB())
+	if err != nil {
+		log.Debug("Account range of unavailable state requested", "root", req.Root)
+		return res
+	}
+	var (
+		limit = responseLimit(req.Bytes)
+		size  int
+	)
+	it := trie.NewIterator(tr.NodeIterator(req.Origin[:]))
+	for size < limit && it.Next() {
+		if bytes.Compare(it.Key, req.Limit[:]) > 0 {
+			break
+		}
+		res.Hashes = append(res.Hashes, common.BytesToHash(it.Key))
+		res.Accounts = append(res.Accounts, common.CopyBytes(it.Value))
+		size += common.HashLength + len(it.Value)
+	}
+	if it.Err != nil {
+		log.Debug("Failed to iterate account range", "root", req.Root, "err", it.Err)
+		return new(accountRangeData)
+	}
+	// Prove the origin and the last returned account, which proves that there
+	// are no gaps in the range.
+	proof := memorydb.New()
+	if err := tr.Prove(req.Origin[:], 0, proof); err != nil {
+		return new(accountRangeData)
+	}
+	if len(res.Hashes) > 0 {
+		if err := tr.Prove(res.Hashes[len(res.Hashes)-1][:], 0, proof); err != nil {
+			return new(accountRangeData)
+		}
+	}
+	res.Proof = proofList(proof)
+	return res
+}
+
+// serveStorageRanges retrieves the storage slots of the requested accounts.
+// Accounts are served in full until the size limit is reached; only the last
+// served account may be cut short, in which case its range is proven.
+func (pm *ProtocolManager) serveStorageRanges(req *getStorageRangesData) *storageRangesData {
+	res := new(storageRangesData)
+	triedb := pm.blockchain.StateCache().TrieDB()
+	accTrie, err := trie.New(req.Root, triedb)
+	if err != nil {
+		log.Debug("Storage ranges of unavailable state requested", "root", req.Root)
+		return res
+	}
+	var (
+		limit = responseLimit(req.Bytes)
+		size  int
+	)
+	for i, account := range req.Accounts {
+		if size >= limit {
+			break
+		}
+		blob, err := accTrie.TryGet(account[:])
+		if err != nil || len(blob) == 0 {
+			return new(storageRangesData)
+		}
+		var acc state.Account
+		if err := rlp.DecodeBytes(blob, &acc); err != nil {
+			return new(storageRangesData)
+		}
+		stTrie, err := trie.New(acc.Root, triedb)
+		if err != nil {
+			return new(storageRangesData)
+		}
+		// Only the first account may start at an origin and only the last one
+		// may end at a limit.
+		var origin, last []byte
+		if i == 0 && len(req.Origin) > 0 {
+			origin = req.Origin
+		}
+		if i == len(req.Accounts)-1 && len(req.Limit) > 0 {
+			last = req.Limit
+		}
+		var (
+			hashes []common.Hash
+			slots  [][]byte
+			abort  bool // range cut short by the size limit
+			capped bool // range cut short by the requested limit
+		)
+		it := trie.NewIterator(stTrie.NodeIterator(origin))
+		for it.Next() {
+			if last != nil && bytes.Compare(it.Key, last) > 0 {
+				capped = true
+				break
+			}
+			if size >= limit {
+				abort = true
+				break
+			}
+			hashes = append(hashes, common.BytesToHash(it.Key))
+			slots = append(slots, common.CopyBytes(it.Value))
+			size += common.HashLength + len(it.Value)
+		}
+		if it.Err != nil {
+			return new(storageRangesData)
+		}
+		if abort && len(hashes) == 0 {
+			break
+		}
+		res.Hashes = append(res.Hashes, hashes)
+		res.Slots = append(res.Slots, slots)
+
+		// A partial range needs to be proven, a full one can be checked against
+		// the storage root of the account.
+		if origin != nil || abort || capped {
+			proof := memorydb.New()
+			if origin == nil {
+				origin = make([]byte, common.HashLength)
+			}
+			if err := stTrie.Prove(origin, 0, proof); err != nil {
+				return new(storageRangesData)
+			}
+			if len(hashes) > 0 {
+				if err := stTrie.Prove(hashes[len(hashes)-1][:], 0, proof); err != nil {
+					return new(storageRangesData)
+				}
+			}
+			res.Proof = proofList(proof)
+			break
+		}
+	}
+	return res
+}
+
+// serveByteCodes retrieves the requested contract codes, skipping unknown ones.
+func (pm *ProtocolManager) serveByteCodes(req *getByteCodesData) [][]byte {
+	var (
+		limit = responseLimit(req.Bytes)
+		size  int
+		codes [][]byte
+	)
+	for _, hash := range req.Hashes {
+		if size >= limit {
+			break
+		}
+		if code, err := pm.blockchain.TrieNode(hash); err == nil {
+			codes = append(codes, code)
+			size += len(code)
+		}
+	}
+	return codes
+}
@@ -234,6 +234,9 @@ func (cs *chainSyncer) nextSyncOp() *chainSyncOp {
 	if atomic.LoadUint32(&cs.pm.fastSync) == 1 {
 		// Fast sync was explicitly requested, and explicitly granted
 		mode = downloader.FastSync
+		if atomic.LoadUint32(&cs.pm.snapSync) == 1 {
+			mode = downloader.SnapSync
+		}
 	} else if currentBlock.NumberU64() == 0 && cs.pm.blockchain.CurrentFastBlock().NumberU64() > 0 {
 		// The database seems empty as the current block is the genesis. Yet the fast
 		// block is ahead, so fast sync was enabled for this node at a certain point.
@@ -244,7 +247,7 @@ func (cs *chainSyncer) nextSyncOp() *chainSyncOp {
 		mode = downloader.FastSync
 	}
 
-	if mode == downloader.FastSync && cs.pm.blockchain.CurrentFastBlock().Number().Cmp(pBn) >= 0 {
+	if (mode == downloader.FastSync || mode == downloader.SnapSync) && cs.pm.blockchain.CurrentFastBlock().Number().Cmp(pBn) >= 0 {
 		return nil
 	}
 
@@ -11,10 +11,12 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
 
 	phoenixchain "github.com/PhoenixGlobal/Phoenix-Chain-SDK"
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/signer/eip712"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/consensus"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
-	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
-	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rpc"
 )
 
@@ -446,6 +448,16 @@ func (ec *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
 	return (*big.Int)(&hex), nil
 }
 
+// SuggestGasTipCap retrieves the currently suggested gas tip cap to allow a
+// timely execution of a dynamic fee transaction.
+func (ec *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	var hex hexutil.Big
+	if err := ec.c.CallContext(ctx, &hex, "phoenixchain_maxPriorityFeePerGas"); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&hex), nil
+}
+
 // EstimateGas tries to estimate the gas needed to execute a specific transaction based on
 // the current pending state of the backend blockchain. There is no guarantee that this is
 // the true gas limit requirement as other transactions may be added or removed by miners,
@@ -459,18 +471,46 @@ func (ec *Client) EstimateGas(ctx context.Context, msg phoenixchain.CallMsg) (ui
 	return uint64(hex), nil
 }
 
+// CreateAccessList simulates a transaction against the pending state and
+// returns the accounts and storage slots it accesses, the gas it uses once
+// they are declared and the error message of the execution, if it failed.
+func (ec *Client) CreateAccessList(ctx context.Context, msg phoenixchain.CallMsg) (*types.AccessList, uint64, string, error) {
+	type accessListResult struct {
+		Accesslist *types.AccessList `json:"accessList"`
+		Error      string            `json:"error,omitempty"`
+		GasUsed    hexutil.Uint64    `json:"gasUsed"`
+	}
+	var result accessListResult
+	if err := ec.c.CallContext(ctx, &result, "phoenixchain_createAccessList", toCallArg(msg)); err != nil {
+		return nil, 0, "", err
+	}
+	return result.Accesslist, uint64(result.GasUsed), result.Error, nil
+}
+
 // SendTransaction injects a signed transaction into the pending pool for execution.
 //
 // If the transaction was a contract creation use the TransactionReceipt method to get the
 // contract address after the transaction has been mined.
 func (ec *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
-	data, err := rlp.EncodeToBytes(tx)
+	data, err := tx.MarshalBinary()
 	if err != nil {
 		return err
 	}
 	return ec.c.CallContext(ctx, nil, "phoenixchain_sendRawTransaction", common.ToHex(data))
 }
 
+// SignTypedData signs EIP-712 typed data with an account of the node, which must
+// be unlocked. The V value of the returned signature is 27 or 28.
+//
+// Use eip712.SignTypedData to sign with a local key instead.
+func (ec *Client) SignTypedData(ctx context.Context, account common.Address, typedData eip712.TypedData) ([]byte, error) {
+	var signature hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &signature, "phoenixchain_signTypedData", account, typedData); err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
+
 func (ec *Client) GetSchnorrNIZKProve(ctx context.Context) (string, error) {
 	var res string
 	err := ec.c.CallContext(ctx, &res, "phoenixchain_getSchnorrNIZKProve", nil)
@@ -489,6 +529,75 @@ func (ec *Client) GetProgramVersion(ctx context.Context) (*configs.ProgramVersio
 	return res, nil
 }
 
+// Evidences returns the duplicate-sign evidences recorded by the node that match
+// the filter, all of them if filter is nil.
+func (ec *Client) Evidences(ctx context.Context, filter *consensus.EvidenceFilter) ([]*consensus.EvidenceInfo, error) {
+	var res []*consensus.EvidenceInfo
+	err := ec.c.CallContext(ctx, &res, "phoenixchain_getEvidences", filter)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ConsensusHealth returns the participation of the validators in the last
+// blocks committed by the node.
+func (ec *Client) ConsensusHealth(ctx context.Context) (*consensus.HealthReport, error) {
+	var res *consensus.HealthReport
+	err := ec.c.CallContext(ctx, &res, "phoenixchain_consensusHealth")
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SubscribeNewEvidence subscribes to notifications about the duplicate-sign
+// evidences matching the filter as the node records them.
+func (ec *Client) SubscribeNewEvidence(ctx context.Context, filter *consensus.EvidenceFilter, ch chan<- *consensus.EvidenceInfo) (phoenixchain.Subscription, error) {
+	return ec.c.EthSubscribe(ctx, ch, "newEvidences", filter)
+}
+
+// PrepareQC returns the QuorumCert with which the validators confirmed the block
+// with the given number.
+func (ec *Client) PrepareQC(ctx context.Context, number uint64) (*ctypes.QuorumCert, error) {
+	var qc *ctypes.QuorumCert
+	err := ec.c.CallContext(ctx, &qc, "debug_getPrepareQC", number)
+	if err == nil && (qc == nil || qc.BlockHash == (common.Hash{})) {
+		return nil, phoenixchain.NotFound
+	}
+	return qc, err
+}
+
+// AccountResult is the Merkle proof of an account and of some of its storage
+// slots, as returned by phoenixchain_getProof.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []string        `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+// StorageResult is the Merkle proof of a storage slot.
+type StorageResult struct {
+	Key   string       `json:"key"`
+	Value *hexutil.Big `json:"value"`
+	Proof []string     `json:"proof"`
+}
+
+// GetProof returns the Merkle proof of the given account and storage keys.
+// The block number can be nil, in which case the proof is taken from the latest known block.
+func (ec *Client) GetProof(ctx context.Context, account common.Address, keys []string, blockNumber *big.Int) (*AccountResult, error) {
+	var res *AccountResult
+	err := ec.c.CallContext(ctx, &res, "phoenixchain_getProof", account, keys, toBlockNumArg(blockNumber))
+	if err == nil && res == nil {
+		return nil, phoenixchain.NotFound
+	}
+	return res, err
+}
+
 func toCallArg(msg phoenixchain.CallMsg) interface{} {
 	arg := map[string]interface{}{
 		"from": msg.From,
@@ -506,5 +615,14 @@ func toCallArg(msg phoenixchain.CallMsg) interface{} {
 	if msg.GasPrice != nil {
 		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
 	}
+	if msg.GasFeeCap != nil {
+		arg["maxFeePerGas"] = (*hexutil.Big)(msg.GasFeeCap)
+	}
+	if msg.GasTipCap != nil {
+		arg["maxPriorityFeePerGas"] = (*hexutil.Big)(msg.GasTipCap)
+	}
+	if msg.AccessList != nil {
+		arg["accessList"] = msg.AccessList
+	}
 	return arg
 }
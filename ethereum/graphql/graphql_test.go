@@ -0,0 +1,282 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/snapshotdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/internal/ethapi"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rpc"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/gov"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/restricting"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/staking"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/xcom"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/xutil"
+)
+
+func TestBuildSchema(t *testing.T) {
+	// Make sure the schema can be parsed and matched up to the object model.
+	if _, err := newHandler(nil); err != nil {
+		t.Errorf("Could not construct GraphQL handler: %v", err)
+	}
+}
+
+// testBackend serves a single block, its receipts and its state to the
+// resolvers. The methods the tests don't reach panic on the nil Backend.
+type testBackend struct {
+	ethapi.Backend
+	block    *types.Block
+	receipts types.Receipts
+	state    *state.StateDB
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if number == rpc.LatestBlockNumber || uint64(number) == b.block.NumberU64() {
+		return b.block, nil
+	}
+	return nil, nil
+}
+
+func (b *testBackend) GetBlock(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	if hash == b.block.Hash() {
+		return b.block, nil
+	}
+	return nil, nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	if hash == b.block.Hash() {
+		return b.receipts, nil
+	}
+	return nil, nil
+}
+
+func (b *testBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	if uint64(number) == b.block.NumberU64() {
+		return b.state, b.block.Header(), nil
+	}
+	return nil, nil, nil
+}
+
+func newTestBackend(t *testing.T) *testBackend {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+
+	dir, err := ioutil.TempDir("", "graphql-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	snapshotdb.SetDBPathWithNode(dir)
+	sndb := snapshotdb.Instance()
+	t.Cleanup(func() { sndb.Clear() })
+
+	tx := types.NewTransaction(0, common.HexToAddress("0x1000000000000000000000000000000000001337"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		TxHash:            tx.Hash(),
+		GasUsed:           21000,
+		Logs:              []*types.Log{{Data: []byte{0x01, 0x02}}},
+	}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, []*types.Receipt{receipt})
+
+	// Stake a candidate with restricted funds as of the block.
+	if err := sndb.NewBlock(block.Number(), block.ParentHash(), block.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	nodeId := discover.MustHexID("0x362003c50ed3a523cdede37a001803b8f0fed27cb402b3d6127a1a96661ec202318f68f4c76d9b0bfbabfd551a178d4335eaeaa9b7981a4df30dfc8c0bfe3384")
+	nodeAddr, err := xutil.NodeId2Addr(nodeId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	can := &staking.Candidate{
+		CandidateBase: &staking.CandidateBase{
+			NodeId:          nodeId,
+			StakingBlockNum: 1,
+			Description:     staking.Description{NodeName: "node-1"},
+		},
+		CandidateMutable: &staking.CandidateMutable{
+			Status:             staking.Valided,
+			StakingEpoch:       1,
+			Shares:             big.NewInt(300),
+			Released:           big.NewInt(100),
+			ReleasedHes:        new(big.Int),
+			RestrictingPlan:    big.NewInt(200),
+			RestrictingPlanHes: new(big.Int),
+		},
+	}
+	stakingDB := staking.NewStakingDB()
+	if err := stakingDB.SetCandidateStore(block.Hash(), nodeAddr, can); err != nil {
+		t.Fatal(err)
+	}
+	if err := stakingDB.SetCanPowerStore(block.Hash(), nodeAddr, can); err != nil {
+		t.Fatal(err)
+	}
+
+	// Put a treasury proposal to the vote.
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposal := &gov.TreasuryProposal{
+		ProposalID:     common.HexToHash("0x01"),
+		ProposalType:   gov.Treasury,
+		PIPID:          "7",
+		SubmitBlock:    1,
+		EndVotingBlock: 100,
+		Proposer:       nodeId,
+		Recipient:      common.HexToAddress("0x1000000000000000000000000000000000001337"),
+		Tranches:       []restricting.RestrictingPlan{{Epoch: 2, Amount: big.NewInt(1000)}},
+	}
+	if err := gov.SetProposal(proposal, statedb); err != nil {
+		t.Fatal(err)
+	}
+	if err := gov.SetTallyResult(gov.TallyResult{ProposalID: proposal.ProposalID, Yeas: 3, Status: gov.Voting}, statedb); err != nil {
+		t.Fatal(err)
+	}
+	if err := gov.AddVotingProposalID(block.Hash(), proposal.ProposalID); err != nil {
+		t.Fatal(err)
+	}
+	return &testBackend{block: block, receipts: types.Receipts{receipt}, state: statedb}
+}
+
+// query runs a GraphQL query against the backend and returns the data of the
+// response.
+func query(t *testing.T, backend ethapi.Backend, q string) map[string]interface{} {
+	data, errs := exec(t, backend, q)
+	if len(errs) > 0 {
+		t.Fatalf("query failed: %v", errs)
+	}
+	return data
+}
+
+// exec runs a GraphQL query against the backend and returns the data and the
+// errors of the response.
+func exec(t *testing.T, backend ethapi.Backend, q string) (map[string]interface{}, []interface{}) {
+	handler, err := newHandler(backend)
+	if err != nil {
+		t.Fatalf("could not construct GraphQL handler: %v", err)
+	}
+	body, _ := json.Marshal(map[string]string{"query": q})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/graphql", strings.NewReader(string(body))))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status mismatch: have %d, want %d", rec.Code, http.StatusOK)
+	}
+	var resp struct {
+		Data   map[string]interface{}
+		Errors []interface{}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	return resp.Data, resp.Errors
+}
+
+func TestQueryBlock(t *testing.T) {
+	backend := newTestBackend(t)
+
+	data := query(t, backend, `{ block(number: 1) { hash transactions { hash status gasUsed logs { data } } } }`)
+	block := data["block"].(map[string]interface{})
+	if have, want := block["hash"], backend.block.Hash().Hex(); have != want {
+		t.Errorf("block hash mismatch: have %v, want %v", have, want)
+	}
+	txs := block["transactions"].([]interface{})
+	if len(txs) != 1 {
+		t.Fatalf("have %d transactions, want 1", len(txs))
+	}
+	tx := txs[0].(map[string]interface{})
+	if have, want := tx["hash"], backend.block.Transactions()[0].Hash().Hex(); have != want {
+		t.Errorf("transaction hash mismatch: have %v, want %v", have, want)
+	}
+	if tx["status"] != "0x1" || tx["gasUsed"] != "0x5208" {
+		t.Errorf("receipt mismatch: have status %v and gas used %v", tx["status"], tx["gasUsed"])
+	}
+	logs := tx["logs"].([]interface{})
+	if len(logs) != 1 || logs[0].(map[string]interface{})["data"] != "0x0102" {
+		t.Errorf("logs mismatch: have %v", logs)
+	}
+
+	if data := query(t, backend, `{ block(number: 2) { hash } }`); data["block"] != nil {
+		t.Errorf("unknown block resolved to %v", data["block"])
+	}
+}
+
+func TestQueryLimits(t *testing.T) {
+	backend := newTestBackend(t)
+
+	data := query(t, backend, `{ blocks(from: 1, to: 1) { hash } }`)
+	if blocks := data["blocks"].([]interface{}); len(blocks) != 1 {
+		t.Errorf("have %d blocks, want 1", len(blocks))
+	}
+	if _, errs := exec(t, backend, `{ blocks(from: 0, to: 1000000000) { hash } }`); len(errs) == 0 {
+		t.Error("oversized block range not rejected")
+	}
+	deep := `{ block(number: 1) { parent { parent { parent { parent { parent { parent { parent { parent { hash } } } } } } } } } }`
+	if _, errs := exec(t, backend, deep); len(errs) == 0 {
+		t.Error("overly nested query not rejected")
+	}
+}
+
+func TestQueryCandidates(t *testing.T) {
+	backend := newTestBackend(t)
+
+	data := query(t, backend, `{ block(number: 1) { candidates { nodeName shares released restrictingPlan } } }`)
+	candidates := data["block"].(map[string]interface{})["candidates"].([]interface{})
+	if len(candidates) != 1 {
+		t.Fatalf("have %d candidates, want 1", len(candidates))
+	}
+	want := map[string]interface{}{
+		"nodeName":        "node-1",
+		"shares":          "0x12c",
+		"released":        "0x64",
+		"restrictingPlan": "0xc8",
+	}
+	for field, value := range want {
+		if have := candidates[0].(map[string]interface{})[field]; have != value {
+			t.Errorf("%s mismatch: have %v, want %v", field, have, value)
+		}
+	}
+}
+
+func TestQueryProposals(t *testing.T) {
+	backend := newTestBackend(t)
+
+	data := query(t, backend, `{ block(number: 1) { proposals { id type pipId recipient newVersion tranches { epoch amount } tally { yeas status } } } }`)
+	proposals := data["block"].(map[string]interface{})["proposals"].([]interface{})
+	if len(proposals) != 1 {
+		t.Fatalf("have %d proposals, want 1", len(proposals))
+	}
+	p := proposals[0].(map[string]interface{})
+	if p["id"] != common.HexToHash("0x01").Hex() || p["type"] != float64(gov.Treasury) || p["pipId"] != "7" {
+		t.Errorf("proposal mismatch: have %v", p)
+	}
+	if p["recipient"] != "0x1000000000000000000000000000000000001337" {
+		t.Errorf("recipient mismatch: have %v", p["recipient"])
+	}
+	if p["newVersion"] != nil {
+		t.Errorf("treasury proposal has a new version: %v", p["newVersion"])
+	}
+	tranches := p["tranches"].([]interface{})
+	if len(tranches) != 1 {
+		t.Fatalf("have %d tranches, want 1", len(tranches))
+	}
+	if tranche := tranches[0].(map[string]interface{}); tranche["epoch"] != "0x2" || tranche["amount"] != "0x3e8" {
+		t.Errorf("tranche mismatch: have %v", tranche)
+	}
+	if tally := p["tally"].(map[string]interface{}); tally["yeas"] != "0x3" || tally["status"] != float64(gov.Voting) {
+		t.Errorf("tally mismatch: have %v", tally)
+	}
+}
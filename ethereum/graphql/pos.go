@@ -0,0 +1,385 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/snapshotdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rpc"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/gov"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/plugin"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/restricting"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/staking"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/xutil"
+)
+
+// hexBig dereferences an amount of the staking views, which is nil when the
+// amount was never set.
+func hexBig(b *hexutil.Big) hexutil.Big {
+	if b == nil {
+		return hexutil.Big{}
+	}
+	return *b
+}
+
+// Candidate represents the staking information of a node.
+type Candidate struct {
+	candidate *staking.CandidateHex
+}
+
+func (c *Candidate) NodeId() hexutil.Bytes          { return c.candidate.NodeId.Bytes() }
+func (c *Candidate) BlsPubKey() hexutil.Bytes       { return c.candidate.BlsPubKey.Bytes() }
+func (c *Candidate) StakingAddress() common.Address { return c.candidate.StakingAddress }
+func (c *Candidate) BenefitAddress() common.Address { return c.candidate.BenefitAddress }
+func (c *Candidate) RewardPer() int32               { return int32(c.candidate.RewardPer) }
+func (c *Candidate) NextRewardPer() int32           { return int32(c.candidate.NextRewardPer) }
+func (c *Candidate) Status() hexutil.Uint64         { return hexutil.Uint64(c.candidate.Status) }
+func (c *Candidate) StakingEpoch() hexutil.Uint64   { return hexutil.Uint64(c.candidate.StakingEpoch) }
+func (c *Candidate) StakingBlockNum() hexutil.Uint64 {
+	return hexutil.Uint64(c.candidate.StakingBlockNum)
+}
+func (c *Candidate) StakingTxIndex() hexutil.Uint64 {
+	return hexutil.Uint64(c.candidate.StakingTxIndex)
+}
+func (c *Candidate) ProgramVersion() hexutil.Uint64 {
+	return hexutil.Uint64(c.candidate.ProgramVersion)
+}
+func (c *Candidate) Shares() hexutil.Big          { return hexBig(c.candidate.Shares) }
+func (c *Candidate) Released() hexutil.Big        { return hexBig(c.candidate.Released) }
+func (c *Candidate) ReleasedHes() hexutil.Big     { return hexBig(c.candidate.ReleasedHes) }
+func (c *Candidate) RestrictingPlan() hexutil.Big { return hexBig(c.candidate.RestrictingPlan) }
+func (c *Candidate) RestrictingPlanHes() hexutil.Big {
+	return hexBig(c.candidate.RestrictingPlanHes)
+}
+func (c *Candidate) DelegateEpoch() hexutil.Uint64 { return hexutil.Uint64(c.candidate.DelegateEpoch) }
+func (c *Candidate) DelegateTotal() hexutil.Big    { return hexBig(c.candidate.DelegateTotal) }
+func (c *Candidate) DelegateTotalHes() hexutil.Big { return hexBig(c.candidate.DelegateTotalHes) }
+func (c *Candidate) DelegateRewardTotal() hexutil.Big {
+	return hexBig(c.candidate.DelegateRewardTotal)
+}
+func (c *Candidate) NodeName() string   { return c.candidate.NodeName }
+func (c *Candidate) ExternalId() string { return c.candidate.ExternalId }
+func (c *Candidate) Website() string    { return c.candidate.Website }
+func (c *Candidate) Details() string    { return c.candidate.Details }
+
+// candidate looks up the staking information of a node as of the given block,
+// nil if the node has no staking.
+func candidate(blockHash common.Hash, blockNumber uint64, nodeId discover.NodeID) (*Candidate, error) {
+	nodeAddr, err := xutil.NodeId2Addr(nodeId)
+	if err != nil {
+		return nil, err
+	}
+	can, err := plugin.StakingInstance().GetCandidateCompactInfo(blockHash, blockNumber, nodeAddr)
+	if err != nil {
+		if snapshotdb.IsDbNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &Candidate{can}, nil
+}
+
+// Validator represents a node of the verifier list of a settlement epoch or
+// of the validator list of a consensus round.
+type Validator struct {
+	blockHash   common.Hash
+	blockNumber uint64
+	validator   *staking.ValidatorEx
+}
+
+func (v *Validator) NodeId() hexutil.Bytes          { return v.validator.NodeId.Bytes() }
+func (v *Validator) BlsPubKey() hexutil.Bytes       { return v.validator.BlsPubKey.Bytes() }
+func (v *Validator) StakingAddress() common.Address { return v.validator.StakingAddress }
+func (v *Validator) BenefitAddress() common.Address { return v.validator.BenefitAddress }
+func (v *Validator) RewardPer() int32               { return int32(v.validator.RewardPer) }
+func (v *Validator) NextRewardPer() int32           { return int32(v.validator.NextRewardPer) }
+func (v *Validator) StakingBlockNum() hexutil.Uint64 {
+	return hexutil.Uint64(v.validator.StakingBlockNum)
+}
+func (v *Validator) ProgramVersion() hexutil.Uint64 {
+	return hexutil.Uint64(v.validator.ProgramVersion)
+}
+func (v *Validator) Shares() hexutil.Big        { return hexBig(v.validator.Shares) }
+func (v *Validator) DelegateTotal() hexutil.Big { return hexBig(v.validator.DelegateTotal) }
+func (v *Validator) DelegateRewardTotal() hexutil.Big {
+	return hexBig(v.validator.DelegateRewardTotal)
+}
+func (v *Validator) ValidatorTerm() hexutil.Uint64 { return hexutil.Uint64(v.validator.ValidatorTerm) }
+func (v *Validator) NodeName() string              { return v.validator.NodeName }
+
+func (v *Validator) Candidate(ctx context.Context) (*Candidate, error) {
+	return candidate(v.blockHash, v.blockNumber, v.validator.NodeId)
+}
+
+// Delegation represents the stake an account delegated to a candidate.
+type Delegation struct {
+	blockHash   common.Hash
+	blockNumber uint64
+	delegation  *staking.DelegationEx
+}
+
+func (d *Delegation) Delegator() common.Address { return d.delegation.Addr }
+func (d *Delegation) NodeId() hexutil.Bytes     { return d.delegation.NodeId.Bytes() }
+func (d *Delegation) StakingBlockNum() hexutil.Uint64 {
+	return hexutil.Uint64(d.delegation.StakingBlockNum)
+}
+func (d *Delegation) DelegateEpoch() hexutil.Uint64 {
+	return hexutil.Uint64(d.delegation.DelegateEpoch)
+}
+func (d *Delegation) Released() hexutil.Big        { return hexBig(d.delegation.Released) }
+func (d *Delegation) ReleasedHes() hexutil.Big     { return hexBig(d.delegation.ReleasedHes) }
+func (d *Delegation) RestrictingPlan() hexutil.Big { return hexBig(d.delegation.RestrictingPlan) }
+func (d *Delegation) RestrictingPlanHes() hexutil.Big {
+	return hexBig(d.delegation.RestrictingPlanHes)
+}
+func (d *Delegation) CumulativeIncome() hexutil.Big { return hexBig(d.delegation.CumulativeIncome) }
+
+func (d *Delegation) Candidate(ctx context.Context) (*Candidate, error) {
+	return candidate(d.blockHash, d.blockNumber, d.delegation.NodeId)
+}
+
+// delegations lists the delegations of an account as of the given block.
+func delegations(blockHash common.Hash, blockNumber uint64, delAddr common.Address) ([]*Delegation, error) {
+	related, err := plugin.StakingInstance().GetRelatedListByDelAddr(blockHash, delAddr)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Delegation, 0, len(related))
+	for _, rel := range related {
+		del, err := plugin.StakingInstance().GetDelegateExCompactInfo(blockHash, blockNumber, delAddr, rel.NodeId, rel.StakingBlockNum)
+		if err != nil {
+			if snapshotdb.IsDbNotFoundErr(err) {
+				continue
+			}
+			return nil, err
+		}
+		ret = append(ret, &Delegation{
+			blockHash:   blockHash,
+			blockNumber: blockNumber,
+			delegation:  del,
+		})
+	}
+	return ret, nil
+}
+
+// TallyResult represents the outcome of the voting on a proposal.
+type TallyResult struct {
+	result *gov.TallyResult
+}
+
+func (t *TallyResult) Yeas() hexutil.Uint64          { return hexutil.Uint64(t.result.Yeas) }
+func (t *TallyResult) Nays() hexutil.Uint64          { return hexutil.Uint64(t.result.Nays) }
+func (t *TallyResult) Abstentions() hexutil.Uint64   { return hexutil.Uint64(t.result.Abstentions) }
+func (t *TallyResult) AccuVerifiers() hexutil.Uint64 { return hexutil.Uint64(t.result.AccuVerifiers) }
+func (t *TallyResult) Status() int32                 { return int32(t.result.Status) }
+func (t *TallyResult) CanceledBy() common.Hash       { return t.result.CanceledBy }
+
+// Proposal represents a governance proposal. The accessors of the fields
+// specific to a proposal type return nil for the other types.
+type Proposal struct {
+	proposal gov.Proposal
+	tally    *gov.TallyResult
+}
+
+func (p *Proposal) Id() common.Hash             { return p.proposal.GetProposalID() }
+func (p *Proposal) Type() int32                 { return int32(p.proposal.GetProposalType()) }
+func (p *Proposal) PipId() string               { return p.proposal.GetPIPID() }
+func (p *Proposal) SubmitBlock() hexutil.Uint64 { return hexutil.Uint64(p.proposal.GetSubmitBlock()) }
+func (p *Proposal) EndVotingBlock() hexutil.Uint64 {
+	return hexutil.Uint64(p.proposal.GetEndVotingBlock())
+}
+func (p *Proposal) Proposer() hexutil.Bytes { return p.proposal.GetProposer().Bytes() }
+
+func (p *Proposal) Tally() *TallyResult {
+	if p.tally == nil {
+		return nil
+	}
+	return &TallyResult{p.tally}
+}
+
+func (p *Proposal) NewVersion() *hexutil.Uint64 {
+	vp, ok := p.proposal.(*gov.VersionProposal)
+	if !ok {
+		return nil
+	}
+	ret := hexutil.Uint64(vp.NewVersion)
+	return &ret
+}
+
+func (p *Proposal) ActiveBlock() *hexutil.Uint64 {
+	vp, ok := p.proposal.(*gov.VersionProposal)
+	if !ok {
+		return nil
+	}
+	ret := hexutil.Uint64(vp.ActiveBlock)
+	return &ret
+}
+
+func (p *Proposal) TobeCanceled() *common.Hash {
+	cp, ok := p.proposal.(*gov.CancelProposal)
+	if !ok {
+		return nil
+	}
+	return &cp.TobeCanceled
+}
+
+func (p *Proposal) Module() *string {
+	pp, ok := p.proposal.(*gov.ParamProposal)
+	if !ok {
+		return nil
+	}
+	return &pp.Module
+}
+
+func (p *Proposal) Name() *string {
+	pp, ok := p.proposal.(*gov.ParamProposal)
+	if !ok {
+		return nil
+	}
+	return &pp.Name
+}
+
+func (p *Proposal) NewValue() *string {
+	pp, ok := p.proposal.(*gov.ParamProposal)
+	if !ok {
+		return nil
+	}
+	return &pp.NewValue
+}
+
+func (p *Proposal) Recipient() *common.Address {
+	tp, ok := p.proposal.(*gov.TreasuryProposal)
+	if !ok {
+		return nil
+	}
+	return &tp.Recipient
+}
+
+func (p *Proposal) Tranches() *[]*Tranche {
+	tp, ok := p.proposal.(*gov.TreasuryProposal)
+	if !ok {
+		return nil
+	}
+	ret := make([]*Tranche, 0, len(tp.Tranches))
+	for _, plan := range tp.Tranches {
+		ret = append(ret, &Tranche{plan})
+	}
+	return &ret
+}
+
+// Tranche represents an amount paid by a treasury proposal.
+type Tranche struct {
+	plan restricting.RestrictingPlan
+}
+
+func (t *Tranche) Epoch() hexutil.Uint64 { return hexutil.Uint64(t.plan.Epoch) }
+func (t *Tranche) Amount() hexutil.Big   { return hexBig((*hexutil.Big)(t.plan.Amount)) }
+
+// resolveHead returns the hash and number of the block, which the PoS views
+// are keyed by.
+func (b *Block) resolveHead(ctx context.Context) (common.Hash, uint64, error) {
+	block, err := b.resolve(ctx)
+	if err != nil {
+		return common.Hash{}, 0, err
+	}
+	if block == nil {
+		return common.Hash{}, 0, errors.New("block not found")
+	}
+	return block.Hash(), block.NumberU64(), nil
+}
+
+func (b *Block) Candidates(ctx context.Context) ([]*Candidate, error) {
+	hash, number, err := b.resolveHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	queue, err := plugin.StakingInstance().GetCandidateList(hash, number)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Candidate, 0, len(queue))
+	for _, can := range queue {
+		ret = append(ret, &Candidate{can})
+	}
+	return ret, nil
+}
+
+// validators wraps a verifier or validator list for the resolvers.
+func validators(hash common.Hash, number uint64, queue staking.ValidatorExQueue) []*Validator {
+	ret := make([]*Validator, 0, len(queue))
+	for _, v := range queue {
+		ret = append(ret, &Validator{
+			blockHash:   hash,
+			blockNumber: number,
+			validator:   v,
+		})
+	}
+	return ret
+}
+
+func (b *Block) Verifiers(ctx context.Context) ([]*Validator, error) {
+	hash, number, err := b.resolveHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	queue, err := plugin.StakingInstance().GetVerifierList(hash, number, plugin.QueryStartNotIrr)
+	if err != nil {
+		return nil, err
+	}
+	return validators(hash, number, queue), nil
+}
+
+func (b *Block) Validators(ctx context.Context) ([]*Validator, error) {
+	hash, number, err := b.resolveHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	queue, err := plugin.StakingInstance().GetValidatorList(hash, number, plugin.CurrentRound, plugin.QueryStartNotIrr)
+	if err != nil {
+		return nil, err
+	}
+	return validators(hash, number, queue), nil
+}
+
+func (b *Block) Delegations(ctx context.Context, args struct{ Address common.Address }) ([]*Delegation, error) {
+	hash, number, err := b.resolveHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return delegations(hash, number, args.Address)
+}
+
+func (b *Block) Proposals(ctx context.Context) ([]*Proposal, error) {
+	hash, number, err := b.resolveHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	state, _, err := b.backend.StateAndHeaderByNumber(ctx, rpc.BlockNumber(number))
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, errors.New("state not found")
+	}
+	defer state.ClearParentReference()
+
+	proposals, err := gov.ListProposal(hash, state)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Proposal, 0, len(proposals))
+	for _, proposal := range proposals {
+		tally, err := gov.GetTallyResult(proposal.GetProposalID(), state)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &Proposal{
+			proposal: proposal,
+			tally:    tally,
+		})
+	}
+	return ret, nil
+}
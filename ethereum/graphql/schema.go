@@ -0,0 +1,432 @@
+package graphql
+
+const schema string = `
+    # Bytes32 is a 32 byte binary string, represented as 0x-prefixed hexadecimal.
+    scalar Bytes32
+    # Address is a 20 byte address, represented as 0x-prefixed hexadecimal.
+    scalar Address
+    # Bytes is an arbitrary length binary string, represented as 0x-prefixed hexadecimal.
+    # An empty byte string is represented as '0x'. Byte strings must have an even number of hexadecimal nybbles.
+    scalar Bytes
+    # BigInt is a large integer. Input is accepted as either a JSON number or as a string.
+    # Strings may be either decimal or 0x-prefixed hexadecimal. Output values are all
+    # 0x-prefixed hexadecimal.
+    scalar BigInt
+    # Long is a 64 bit unsigned integer, represented as 0x-prefixed hexadecimal.
+    scalar Long
+
+    schema {
+        query: Query
+        mutation: Mutation
+    }
+
+    # Account is an account at a particular block.
+    type Account {
+        # Address is the address owning the account.
+        address: Address!
+        # Balance is the balance of the account, in von.
+        balance: BigInt!
+        # TransactionCount is the number of transactions sent from this account,
+        # or in the case of a contract, the number of contracts created. Otherwise
+        # known as the nonce.
+        transactionCount: Long!
+        # Code contains the smart contract code for this account, if the account
+        # is a (non-self-destructed) contract.
+        code: Bytes!
+        # Storage provides access to the storage of a contract account, indexed
+        # by its 32 byte slot identifier.
+        storage(slot: Bytes32!): Bytes32!
+        # Delegations lists the stakes this account delegated to candidates.
+        delegations: [Delegation!]!
+    }
+
+    # Log is an event log emitted by a contract.
+    type Log {
+        # Index is the index of this log in the block.
+        index: Int!
+        # Account is the account which generated this log - this will always
+        # be a contract account.
+        account(block: Long): Account!
+        # Topics is a list of 0-4 indexed topics for the log.
+        topics: [Bytes32!]!
+        # Data is unindexed data for this log.
+        data: Bytes!
+        # Transaction is the transaction that generated this log entry.
+        transaction: Transaction!
+    }
+
+    # AccessTuple is an account and the storage slots of it a transaction
+    # declared in its access list.
+    type AccessTuple {
+        address: Address!
+        storageKeys: [Bytes32!]!
+    }
+
+    # Transaction is a transaction.
+    type Transaction {
+        # Hash is the hash of this transaction.
+        hash: Bytes32!
+        # Nonce is the nonce of the account this transaction was generated with.
+        nonce: Long!
+        # Index is the index of this transaction in the parent block. This will
+        # be null if the transaction has not yet been mined.
+        index: Int
+        # From is the account that sent this transaction - this will always be
+        # an externally owned account.
+        from(block: Long): Account!
+        # To is the account the transaction was sent to. This is null for
+        # contract-creating transactions.
+        to(block: Long): Account
+        # Value is the value, in von, sent along with this transaction.
+        value: BigInt!
+        # GasPrice is the price offered to miners for gas, in von per unit. For
+        # dynamic fee transactions it is the effective gas price once mined.
+        gasPrice: BigInt!
+        # MaxFeePerGas is the fee cap of a dynamic fee transaction.
+        maxFeePerGas: BigInt
+        # MaxPriorityFeePerGas is the tip cap of a dynamic fee transaction.
+        maxPriorityFeePerGas: BigInt
+        # EffectiveGasPrice is the price paid per unit of gas once mined.
+        effectiveGasPrice: BigInt
+        # Gas is the maximum amount of gas this transaction can consume.
+        gas: Long!
+        # InputData is the data supplied to the target of the transaction.
+        inputData: Bytes!
+        # Block is the block this transaction was mined in. This will be null if
+        # the transaction has not yet been mined.
+        block: Block
+
+        # Status is the return status of the transaction. This will be 1 if the
+        # transaction succeeded, or 0 if it failed (due to a revert, or due to
+        # running out of gas). If the transaction has not yet been mined, this
+        # field will be null.
+        status: Long
+        # GasUsed is the amount of gas that was used processing this transaction.
+        # If the transaction has not yet been mined, this field will be null.
+        gasUsed: Long
+        # CumulativeGasUsed is the total gas used in the block up to and including
+        # this transaction. If the transaction has not yet been mined, this field
+        # will be null.
+        cumulativeGasUsed: Long
+        # CreatedContract is the account that was created by a contract creation
+        # transaction. If the transaction was not a contract creation transaction,
+        # or it has not yet been mined, this field will be null.
+        createdContract(block: Long): Account
+        # Logs is a list of log entries emitted by this transaction. If the
+        # transaction has not yet been mined, this field will be null.
+        logs: [Log!]
+        r: BigInt!
+        s: BigInt!
+        v: BigInt!
+        # Type is the type of the transaction envelope.
+        type: Int!
+        # AccessList is the access list of an access list or dynamic fee transaction.
+        accessList: [AccessTuple!]
+        # Raw is the canonical encoding of the transaction.
+        raw: Bytes!
+    }
+
+    # BlockFilterCriteria encapsulates log filter criteria for a filter applied
+    # to a single block.
+    input BlockFilterCriteria {
+        # Addresses is list of addresses that are of interest. If this list is
+        # empty, results will not be filtered by address.
+        addresses: [Address!]
+        # Topics list restricts matches to particular event topics. Each event has a list
+        # of topics. Topics matches a prefix of that list. An empty element array matches any
+        # topic. Non-empty elements represent an alternative that matches any of the
+        # contained topics.
+        #
+        # Examples:
+        #  - [] or nil          matches any topic list
+        #  - [[A]]              matches topic A in first position
+        #  - [[], [B]]          matches any topic in first position, B in second position
+        #  - [[A], [B]]         matches topic A in first position, B in second position
+        #  - [[A, C], [B, D]]   matches topic (A OR C) in first position, (B OR D) in second position
+        topics: [[Bytes32!]!]
+    }
+
+    # Block is a block.
+    type Block {
+        # Number is the number of this block, starting at 0 for the genesis block.
+        number: Long!
+        # Hash is the block hash of this block.
+        hash: Bytes32!
+        # Parent is the parent block of this block.
+        parent: Block
+        # Nonce is the block nonce, carrying the VRF proof of the proposer.
+        nonce: Bytes!
+        # TransactionsRoot is the keccak256 hash of the root of the trie of transactions in this block.
+        transactionsRoot: Bytes32!
+        # TransactionCount is the number of transactions in this block. if
+        # transactions are not available for this block, this field will be null.
+        transactionCount: Int
+        # StateRoot is the keccak256 hash of the state trie after this block was processed.
+        stateRoot: Bytes32!
+        # ReceiptsRoot is the keccak256 hash of the trie of transaction receipts in this block.
+        receiptsRoot: Bytes32!
+        # Miner is the account that proposed this block.
+        miner(block: Long): Account!
+        # ExtraData is an arbitrary data field supplied by the proposer.
+        extraData: Bytes!
+        # GasLimit is the maximum amount of gas that was available to transactions in this block.
+        gasLimit: Long!
+        # GasUsed is the amount of gas that was used executing transactions in this block.
+        gasUsed: Long!
+        # BaseFeePerGas is the base fee of this block, null before the dynamic fee fork.
+        baseFeePerGas: BigInt
+        # Timestamp is the unix timestamp at which this block was proposed, in milliseconds.
+        timestamp: Long!
+        # LogsBloom is a bloom filter that can be used to check if a block may
+        # contain log entries matching a filter.
+        logsBloom: Bytes!
+        # Transactions is a list of transactions associated with this block. If
+        # transactions are unavailable for this block, this field will be null.
+        transactions: [Transaction!]
+        # TransactionAt returns the transaction at the specified index. If
+        # transactions are unavailable for this block, or if the index is out of
+        # bounds, this field will be null.
+        transactionAt(index: Int!): Transaction
+        # Logs returns a filtered set of logs from this block.
+        logs(filter: BlockFilterCriteria!): [Log!]!
+        # Account fetches an account at the current block's state.
+        account(address: Address!): Account!
+        # Call executes a local call operation at the current block's state.
+        call(data: CallData!): CallResult
+        # EstimateGas estimates the amount of gas that will be required for
+        # successful execution of a transaction at the current block's state.
+        estimateGas(data: CallData!): Long!
+
+        # Candidates lists the staking candidates as of this block.
+        candidates: [Candidate!]!
+        # Verifiers lists the verifiers of the settlement epoch of this block.
+        verifiers: [Validator!]!
+        # Validators lists the validators of the consensus round of this block.
+        validators: [Validator!]!
+        # Delegations lists the stakes the given account delegated as of this block.
+        delegations(address: Address!): [Delegation!]!
+        # Proposals lists the governance proposals as of this block.
+        proposals: [Proposal!]!
+    }
+
+    # CallData represents the data associated with a local contract call.
+    # All fields are optional.
+    input CallData {
+        # From is the address making the call.
+        from: Address
+        # To is the address the call is sent to.
+        to: Address
+        # Gas is the amount of gas sent with the call.
+        gas: Long
+        # GasPrice is the price, in von, offered for each unit of gas.
+        gasPrice: BigInt
+        # MaxFeePerGas is the fee cap of a dynamic fee call.
+        maxFeePerGas: BigInt
+        # MaxPriorityFeePerGas is the tip cap of a dynamic fee call.
+        maxPriorityFeePerGas: BigInt
+        # Value is the value, in von, sent along with the call.
+        value: BigInt
+        # Data is the data sent to the callee.
+        data: Bytes
+    }
+
+    # CallResult is the result of a local call operation.
+    type CallResult {
+        # Data is the return data of the called contract.
+        data: Bytes!
+        # GasUsed is the amount of gas used by the call, after any refunds.
+        gasUsed: Long!
+        # Status is the result of the call - 1 for success or 0 for failure.
+        status: Long!
+    }
+
+    # FilterCriteria encapsulates log filter criteria for searching log entries.
+    input FilterCriteria {
+        # FromBlock is the block at which to start searching, inclusive. Defaults
+        # to the latest block if not supplied.
+        fromBlock: Long
+        # ToBlock is the block at which to stop searching, inclusive. Defaults
+        # to the latest block if not supplied.
+        toBlock: Long
+        # Addresses is a list of addresses that are of interest. If this list is
+        # empty, results will not be filtered by address.
+        addresses: [Address!]
+        # Topics list restricts matches to particular event topics. Each event has a list
+        # of topics. Topics matches a prefix of that list. An empty element array matches any
+        # topic. Non-empty elements represent an alternative that matches any of the
+        # contained topics.
+        topics: [[Bytes32!]!]
+    }
+
+    # SyncState contains the current synchronisation state of the client.
+    type SyncState{
+        # StartingBlock is the block number at which synchronisation started.
+        startingBlock: Long!
+        # CurrentBlock is the point at which synchronisation has presently reached.
+        currentBlock: Long!
+        # HighestBlock is the latest known block number.
+        highestBlock: Long!
+        # PulledStates is the number of state entries fetched so far, or null
+        # if this is not known or not relevant.
+        pulledStates: Long
+        # KnownStates is the number of states the node knows of so far, or null
+        # if this is not known or not relevant.
+        knownStates: Long
+    }
+
+    # Pending represents the current pending state.
+    type Pending {
+        # TransactionCount is the number of transactions in the pending state.
+        transactionCount: Int!
+        # Transactions is a list of transactions in the current pending state.
+        transactions: [Transaction!]
+        # Account fetches an account at the current pending state.
+        account(address: Address!): Account!
+        # Call executes a local call operation for the pending state.
+        call(data: CallData!): CallResult
+        # EstimateGas estimates the amount of gas that will be required for
+        # successful execution of a transaction for the pending state.
+        estimateGas(data: CallData!): Long!
+    }
+
+    # Candidate is a node which staked to take part in the consensus.
+    type Candidate {
+        nodeId: Bytes!
+        blsPubKey: Bytes!
+        # StakingAddress is the account which staked the node.
+        stakingAddress: Address!
+        # BenefitAddress is the account receiving the block and staking rewards.
+        benefitAddress: Address!
+        # RewardPer is the share of the rewards paid to the delegators, in basis points.
+        rewardPer: Int!
+        nextRewardPer: Int!
+        status: Long!
+        stakingEpoch: Long!
+        stakingBlockNum: Long!
+        stakingTxIndex: Long!
+        programVersion: Long!
+        shares: BigInt!
+        released: BigInt!
+        releasedHes: BigInt!
+        restrictingPlan: BigInt!
+        restrictingPlanHes: BigInt!
+        delegateEpoch: Long!
+        delegateTotal: BigInt!
+        delegateTotalHes: BigInt!
+        delegateRewardTotal: BigInt!
+        nodeName: String!
+        externalId: String!
+        website: String!
+        details: String!
+    }
+
+    # Validator is a node of the verifier list of an epoch or of the
+    # validator list of a consensus round.
+    type Validator {
+        nodeId: Bytes!
+        blsPubKey: Bytes!
+        stakingAddress: Address!
+        benefitAddress: Address!
+        rewardPer: Int!
+        nextRewardPer: Int!
+        stakingBlockNum: Long!
+        programVersion: Long!
+        shares: BigInt!
+        delegateTotal: BigInt!
+        delegateRewardTotal: BigInt!
+        # ValidatorTerm is the number of consecutive rounds the node validates.
+        validatorTerm: Long!
+        nodeName: String!
+        # Candidate is the staking information of the node.
+        candidate: Candidate
+    }
+
+    # Delegation is a stake an account delegated to a candidate.
+    type Delegation {
+        delegator: Address!
+        nodeId: Bytes!
+        # StakingBlockNum identifies the staking of the candidate the stake is delegated to.
+        stakingBlockNum: Long!
+        delegateEpoch: Long!
+        released: BigInt!
+        releasedHes: BigInt!
+        restrictingPlan: BigInt!
+        restrictingPlanHes: BigInt!
+        # CumulativeIncome is the delegation reward waiting for withdrawal.
+        cumulativeIncome: BigInt!
+        # Candidate is the candidate the stake is delegated to.
+        candidate: Candidate
+    }
+
+    # TallyResult is the outcome of the voting on a proposal.
+    type TallyResult {
+        yeas: Long!
+        nays: Long!
+        abstentions: Long!
+        accuVerifiers: Long!
+        status: Int!
+        canceledBy: Bytes32!
+    }
+
+    # Proposal is a governance proposal. The fields specific to a proposal
+    # type are null for the other types.
+    type Proposal {
+        id: Bytes32!
+        # Type is 1 for text, 2 for version, 3 for parameter, 4 for cancel
+        # and 5 for treasury proposals.
+        type: Int!
+        pipId: String!
+        submitBlock: Long!
+        endVotingBlock: Long!
+        proposer: Bytes!
+        # Tally is the voting result, null while it is not known.
+        tally: TallyResult
+        newVersion: Long
+        activeBlock: Long
+        tobeCanceled: Bytes32
+        module: String
+        name: String
+        newValue: String
+        recipient: Address
+        tranches: [Tranche!]
+    }
+
+    # Tranche is an amount paid by a treasury proposal, released at the end
+    # of the epoch counted from the tally.
+    type Tranche {
+        epoch: Long!
+        amount: BigInt!
+    }
+
+    type Query {
+        # Block fetches a block by number or by hash. If neither is
+        # supplied, the most recent known block is returned.
+        block(number: Long, hash: Bytes32): Block
+        # Blocks returns all the blocks between two numbers, inclusive. If
+        # to is not supplied, it defaults to the most recent known block.
+        blocks(from: Long!, to: Long): [Block!]!
+        # Pending returns the current pending state.
+        pending: Pending!
+        # Transaction returns a transaction specified by its hash.
+        transaction(hash: Bytes32!): Transaction
+        # Logs returns log entries matching the provided filter.
+        logs(filter: FilterCriteria!): [Log!]!
+        # GasPrice returns the node's estimate of a gas price sufficient to
+        # ensure a transaction is mined in a timely fashion.
+        gasPrice: BigInt!
+        # MaxPriorityFeePerGas returns the node's estimate of a tip sufficient
+        # to ensure a dynamic fee transaction is mined in a timely fashion.
+        maxPriorityFeePerGas: BigInt!
+        # ProtocolVersion is the current wire protocol version number.
+        protocolVersion: Int!
+        # ChainID is the chain identifier used for transaction signing.
+        chainID: BigInt!
+        # Syncing returns information on the current synchronisation state.
+        syncing: SyncState
+    }
+
+    type Mutation {
+        # SendRawTransaction sends an RLP-encoded transaction to the network.
+        sendRawTransaction(data: Bytes!): Bytes32!
+    }
+`
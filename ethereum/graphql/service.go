@@ -0,0 +1,65 @@
+package graphql
+
+import (
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/node"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/internal/ethapi"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rpc"
+)
+
+// Service encapsulates a GraphQL service. Its queries are served on the
+// /graphql path of the node's HTTP endpoint.
+type Service struct {
+	backend ethapi.Backend // The backend that queries will operate on.
+}
+
+// New constructs a new GraphQL service instance and mounts its handler on the
+// HTTP endpoint of stack, behind the token authentication and the request
+// quotas of the RPC API.
+func New(stack *node.Node, backend ethapi.Backend, cors, vhosts []string) (*Service, error) {
+	handler, err := newHandler(backend)
+	if err != nil {
+		return nil, err
+	}
+	if err := stack.RegisterHandler("graphql", "/graphql", node.NewHTTPHandlerStack(handler, cors, vhosts)); err != nil {
+		return nil, err
+	}
+	return &Service{backend: backend}, nil
+}
+
+// Protocols returns the list of protocols exported by this service.
+func (s *Service) Protocols() []p2p.Protocol { return nil }
+
+// APIs returns the list of APIs exported by this service.
+func (s *Service) APIs() []rpc.API { return nil }
+
+// Start implements node.Service, the queries are served by the node.
+func (s *Service) Start(server *p2p.Server) error { return nil }
+
+// Stop implements node.Service.
+func (s *Service) Stop() error { return nil }
+
+const (
+	// maxQueryDepth bounds the nesting of the fields selected by a query.
+	maxQueryDepth = 8
+	// maxQueryParallelism bounds the resolvers a query runs concurrently.
+	maxQueryParallelism = 4
+)
+
+// newHandler returns a new `http.Handler` that will answer GraphQL queries.
+// The queries are limited in depth and parallelism, as the node quotas charge
+// each of them as a single call.
+func newHandler(backend ethapi.Backend) (http.Handler, error) {
+	q := Resolver{backend}
+
+	s, err := graphql.ParseSchema(schema, &q, graphql.MaxDepth(maxQueryDepth), graphql.MaxParallelism(maxQueryParallelism))
+	if err != nil {
+		return nil, err
+	}
+	return &relay.Handler{Schema: s}, nil
+}
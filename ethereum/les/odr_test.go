@@ -116,7 +116,7 @@ func odrContractCall(ctx context.Context, db ethdb.Database, config *configs.Cha
 				from := statedb.GetOrNewStateObject(testBankAddress)
 				from.SetBalance(math.MaxBig256)
 
-				msg := callmsg{types.NewMessage(from.Address(), &testContractAddr, 0, new(big.Int), 100000, new(big.Int), data, false)}
+				msg := callmsg{types.NewMessage(from.Address(), &testContractAddr, 0, new(big.Int), 100000, new(big.Int), new(big.Int), new(big.Int), data, nil, false)}
 
 				context := core.NewEVMContext(msg, header, bc)
 				vmenv := vm.NewEVM(context, nil, statedb, config, vm.Config{})
@@ -130,7 +130,7 @@ func odrContractCall(ctx context.Context, db ethdb.Database, config *configs.Cha
 			header := lc.GetHeaderByHash(bhash)
 			state := light.NewState(ctx, header, lc.Odr())
 			state.SetBalance(testBankAddress, math.MaxBig256)
-			msg := callmsg{types.NewMessage(testBankAddress, &testContractAddr, 0, new(big.Int), 100000, new(big.Int), data, false)}
+			msg := callmsg{types.NewMessage(testBankAddress, &testContractAddr, 0, new(big.Int), 100000, new(big.Int), new(big.Int), new(big.Int), data, nil, false)}
 			context := core.NewEVMContext(msg, header, lc)
 			vmenv := vm.NewEVM(context, nil, state, config, vm.Config{})
 			gp := new(core.GasPool).AddGas(math.MaxUint64)
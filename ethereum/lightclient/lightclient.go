@@ -0,0 +1,281 @@
+// Package lightclient provides a client for the PhoenixChain RPC API that
+// verifies what the node serves instead of trusting it.
+//
+// A header is only accepted together with the QuorumCert by which the
+// validators of its consensus round confirmed it: the QC must carry the
+// signatures of at least 2/3 of the validators and its aggregated BLS
+// signature must verify against their public keys. Account and storage reads
+// are answered with Merkle proofs checked against the state root of such a
+// verified header.
+//
+// The validator set is followed from a checkpoint trusted out of band. The set
+// of a new round is queried from the node, since the round's validators are
+// kept by the staking plugin outside of the state trie and cannot be proved.
+// The set is accepted only if the first block of the round is confirmed under
+// it and at least (n-1)/3+1 of the signers were validators of the previous
+// round already. PhoenixChain replaces at most (n-1)/3 validators per round,
+// so an honest handover always passes this check, while a set made up by the
+// serving node would need one of the previous validators to sign for it. A
+// handover replacing more validators, after a change of the validator count
+// for instance, cannot be followed and requires a new checkpoint.
+package lightclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/dposclient"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/ethclient"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+var (
+	// ErrBeforeCheckpoint is returned for blocks older than the trusted checkpoint.
+	ErrBeforeCheckpoint = errors.New("block is older than the trusted checkpoint")
+	// ErrCheckpointMismatch is returned if the node serves another block than
+	// the trusted one at the checkpoint height.
+	ErrCheckpointMismatch = errors.New("block does not match the trusted checkpoint")
+)
+
+// Config contains the trust anchor of a light client.
+type Config struct {
+	// CheckpointNumber and CheckpointHash identify the block, trusted out of
+	// band, from which the validator set is followed.
+	CheckpointNumber uint64
+	CheckpointHash   common.Hash
+
+	// RoundBlocks is the length of a consensus round in blocks, the blocks
+	// produced by each validator in a round times the number of validators.
+	RoundBlocks uint64
+
+	// Validators is the validator set of the checkpoint's round in consensus
+	// order. If nil it is queried from the node, which is then trusted for it.
+	Validators *pbfttypes.Validators
+}
+
+// Client wraps an ethclient.Client and verifies the headers and state it
+// returns.
+type Client struct {
+	ec     *ethclient.Client
+	dc     *dposclient.Client
+	config Config
+
+	mu     sync.Mutex
+	sets   map[uint64]*pbfttypes.Validators // trusted validator sets by round
+	latest uint64                           // latest round with a trusted set
+}
+
+// DialContext connects a light client to the given URL. The node must serve
+// the debug API, which exposes the QuorumCerts of the blocks.
+func DialContext(ctx context.Context, rawurl string, config Config) (*Client, error) {
+	ec, err := ethclient.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewClient(ctx, ec, config)
+	if err != nil {
+		ec.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewClient creates a light client that uses the given ethclient. It checks
+// the checkpoint against the node and sets up the validator set of its round.
+func NewClient(ctx context.Context, ec *ethclient.Client, config Config) (*Client, error) {
+	if config.RoundBlocks == 0 {
+		return nil, errors.New("consensus round length not configured")
+	}
+	c := &Client{
+		ec:     ec,
+		dc:     dposclient.NewClient(ec),
+		config: config,
+		sets:   make(map[uint64]*pbfttypes.Validators),
+	}
+	header, err := ec.HeaderByNumber(ctx, new(big.Int).SetUint64(config.CheckpointNumber))
+	if err != nil {
+		return nil, err
+	}
+	if header.Hash() != config.CheckpointHash {
+		return nil, ErrCheckpointMismatch
+	}
+
+	round := c.round(config.CheckpointNumber)
+	var vs *pbfttypes.Validators
+	if config.Validators != nil {
+		if config.Validators.Len() == 0 {
+			return nil, errors.New("empty checkpoint validator set")
+		}
+		vs = pbfttypes.NewValidators(config.Validators.Nodes, config.Validators.ValidBlockNumber)
+	} else if vs, err = c.fetchValidators(ctx, round); err != nil {
+		return nil, err
+	}
+	// The genesis block has no QC, any later checkpoint must be confirmed by
+	// the validators it is trusted with.
+	if config.CheckpointNumber > 0 {
+		qc, err := ec.PrepareQC(ctx, config.CheckpointNumber)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyQuorumCert(header, qc, vs); err != nil {
+			return nil, err
+		}
+	}
+	c.sets[round] = vs
+	c.latest = round
+	return c, nil
+}
+
+// Close closes the underlying RPC connection.
+func (c *Client) Close() {
+	c.ec.Close()
+}
+
+// EthClient returns the underlying, unverified, ethclient.
+func (c *Client) EthClient() *ethclient.Client {
+	return c.ec
+}
+
+// HeaderByNumber returns a verified block header from the current canonical
+// chain. If number is nil, the latest known header is returned.
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := c.ec.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if number != nil && header.Number.Cmp(number) != 0 {
+		return nil, fmt.Errorf("header number mismatch: have %v, want %v", header.Number, number)
+	}
+	if err := c.VerifyHeader(ctx, header); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// HeaderByHash returns the verified block header with the given hash.
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	header, err := c.ec.HeaderByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if header.Hash() != hash {
+		return nil, fmt.Errorf("header hash mismatch: have %x, want %x", header.Hash(), hash)
+	}
+	if err := c.VerifyHeader(ctx, header); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// VerifyHeader checks that the header was confirmed by the validators of its
+// consensus round.
+func (c *Client) VerifyHeader(ctx context.Context, header *types.Header) error {
+	number := header.Number.Uint64()
+	switch {
+	case number < c.config.CheckpointNumber:
+		return ErrBeforeCheckpoint
+	case number == c.config.CheckpointNumber:
+		if header.Hash() != c.config.CheckpointHash {
+			return ErrCheckpointMismatch
+		}
+		return nil
+	}
+	vs, err := c.validators(ctx, c.round(number))
+	if err != nil {
+		return err
+	}
+	qc, err := c.ec.PrepareQC(ctx, number)
+	if err != nil {
+		return err
+	}
+	return verifyQuorumCert(header, qc, vs)
+}
+
+// GetProof returns the account and storage values at the given block, the
+// latest one if number is nil, after checking their Merkle proof against the
+// state root of the verified header.
+func (c *Client) GetProof(ctx context.Context, account common.Address, keys []string, number *big.Int) (*ethclient.AccountResult, error) {
+	_, res, err := c.getProof(ctx, account, keys, number)
+	return res, err
+}
+
+func (c *Client) getProof(ctx context.Context, account common.Address, keys []string, number *big.Int) (*types.Header, *ethclient.AccountResult, error) {
+	header, err := c.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := c.ec.GetProof(ctx, account, keys, header.Number)
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.Address != account {
+		return nil, nil, fmt.Errorf("proof of account %x returned for %x", res.Address, account)
+	}
+	if len(res.StorageProof) != len(keys) {
+		return nil, nil, fmt.Errorf("%d storage proofs returned for %d keys", len(res.StorageProof), len(keys))
+	}
+	for i, key := range keys {
+		if res.StorageProof[i].Key != key {
+			return nil, nil, fmt.Errorf("proof of storage key %s returned for %s", res.StorageProof[i].Key, key)
+		}
+	}
+	if err := VerifyAccountProof(header.Root, res); err != nil {
+		return nil, nil, err
+	}
+	return header, res, nil
+}
+
+// BalanceAt returns the verified balance of the given account.
+// The block number can be nil, in which case the balance is taken from the latest known block.
+func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	res, err := c.GetProof(ctx, account, nil, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return res.Balance.ToInt(), nil
+}
+
+// NonceAt returns the verified nonce of the given account.
+// The block number can be nil, in which case the nonce is taken from the latest known block.
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	res, err := c.GetProof(ctx, account, nil, blockNumber)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(res.Nonce), nil
+}
+
+// StorageAt returns the verified value of key in the contract storage of the
+// given account. The block number can be nil, in which case the value is taken
+// from the latest known block.
+func (c *Client) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) (common.Hash, error) {
+	res, err := c.GetProof(ctx, account, []string{key.Hex()}, blockNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BigToHash(res.StorageProof[0].Value.ToInt()), nil
+}
+
+// CodeAt returns the contract code of the given account, checked against the
+// verified code hash. The block number can be nil, in which case the code is
+// taken from the latest known block.
+func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	header, res, err := c.getProof(ctx, account, nil, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	code, err := c.ec.CodeAt(ctx, account, header.Number)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.Keccak256Hash(code) != res.CodeHash {
+		return nil, fmt.Errorf("code of %x does not match its hash %x", account, res.CodeHash)
+	}
+	return code, nil
+}
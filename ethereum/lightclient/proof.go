@@ -0,0 +1,106 @@
+package lightclient
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/ethclient"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/trie"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb/memorydb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// VerifyAccountProof checks the account and storage values of res against
+// their Merkle proofs in the state trie with the given root.
+func VerifyAccountProof(root common.Hash, res *ethclient.AccountResult) error {
+	db, err := proofDB(res.AccountProof)
+	if err != nil {
+		return err
+	}
+	value, _, err := trie.VerifyProof(root, crypto.Keccak256(res.Address.Bytes()), db)
+	if err != nil {
+		return fmt.Errorf("invalid proof of account %x: %v", res.Address, err)
+	}
+	// A missing account is served as an empty one.
+	account := state.Account{
+		Balance:  new(big.Int),
+		Root:     types.EmptyRootHash,
+		CodeHash: crypto.Keccak256(nil),
+	}
+	if value != nil {
+		if err := rlp.DecodeBytes(value, &account); err != nil {
+			return fmt.Errorf("invalid account %x: %v", res.Address, err)
+		}
+	}
+	switch {
+	case res.Balance == nil || res.Balance.ToInt().Cmp(account.Balance) != 0:
+		return fmt.Errorf("balance of %x does not match the proof", res.Address)
+	case uint64(res.Nonce) != account.Nonce:
+		return fmt.Errorf("nonce of %x does not match the proof", res.Address)
+	case res.CodeHash != common.BytesToHash(account.CodeHash):
+		return fmt.Errorf("code hash of %x does not match the proof", res.Address)
+	case res.StorageHash != account.Root:
+		return fmt.Errorf("storage hash of %x does not match the proof", res.Address)
+	}
+	for _, sp := range res.StorageProof {
+		if err := VerifyStorageProof(account.Root, sp); err != nil {
+			return fmt.Errorf("account %x: %v", res.Address, err)
+		}
+	}
+	return nil
+}
+
+// VerifyStorageProof checks the value of a storage slot against its Merkle
+// proof in the storage trie with the given root.
+func VerifyStorageProof(root common.Hash, sp ethclient.StorageResult) error {
+	if sp.Value == nil {
+		return fmt.Errorf("no value for storage key %s", sp.Key)
+	}
+	have := new(big.Int)
+	if root != types.EmptyRootHash {
+		db, err := proofDB(sp.Proof)
+		if err != nil {
+			return err
+		}
+		key := crypto.Keccak256(common.HexToHash(sp.Key).Bytes())
+		value, _, err := trie.VerifyProof(root, key, db)
+		if err != nil {
+			return fmt.Errorf("invalid proof of storage key %s: %v", sp.Key, err)
+		}
+		if value != nil {
+			var content []byte
+			if err := rlp.DecodeBytes(value, &content); err != nil {
+				return fmt.Errorf("invalid value of storage key %s: %v", sp.Key, err)
+			}
+			// Values are stored behind the hash of the account's key prefix and the key.
+			if len(content) > common.HashLength {
+				have = common.BytesToHash(content[common.HashLength:]).Big()
+			}
+		}
+	}
+	if sp.Value.ToInt().Cmp(have) != 0 {
+		return fmt.Errorf("value of storage key %s does not match the proof", sp.Key)
+	}
+	return nil
+}
+
+// proofDB loads the hex encoded trie nodes of a proof into a database keyed by
+// node hash.
+func proofDB(proof []string) (*memorydb.Database, error) {
+	db := memorydb.New()
+	for i, enc := range proof {
+		node, err := hexutil.Decode(enc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof node %d: %v", i, err)
+		}
+		if err := db.Put(crypto.Keccak256(node), node); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
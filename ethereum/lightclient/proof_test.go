@@ -0,0 +1,82 @@
+package lightclient
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/db/rawdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	_ "github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/vm" // registers the precompiled contracts checked by the state
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/ethclient"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+// accountResult builds the answer of phoenixchain_getProof the same way the node does.
+func accountResult(t *testing.T, statedb *state.StateDB, addr common.Address, keys []string) *ethclient.AccountResult {
+	storageHash, codeHash := types.EmptyRootHash, crypto.Keccak256Hash(nil)
+	storageProof := make([]ethclient.StorageResult, len(keys))
+	if tr := statedb.StorageTrie(addr); tr != nil {
+		storageHash, codeHash = tr.Hash(), statedb.GetCodeHash(addr)
+		for i, key := range keys {
+			proof, err := statedb.GetStorageProof(addr, common.HexToHash(key))
+			assert.Nil(t, err)
+			value := common.BytesToHash(statedb.GetState(addr, common.FromHex(key))).Big()
+			storageProof[i] = ethclient.StorageResult{Key: key, Value: (*hexutil.Big)(value), Proof: common.ToHexArray(proof)}
+		}
+	} else {
+		for i, key := range keys {
+			storageProof[i] = ethclient.StorageResult{Key: key, Value: &hexutil.Big{}, Proof: []string{}}
+		}
+	}
+	accountProof, err := statedb.GetProof(addr)
+	assert.Nil(t, err)
+	return &ethclient.AccountResult{
+		Address:      addr,
+		AccountProof: common.ToHexArray(accountProof),
+		Balance:      (*hexutil.Big)(statedb.GetBalance(addr)),
+		CodeHash:     codeHash,
+		Nonce:        hexutil.Uint64(statedb.GetNonce(addr)),
+		StorageHash:  storageHash,
+		StorageProof: storageProof,
+	}
+}
+
+func TestVerifyAccountProof(t *testing.T) {
+	var (
+		contract = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		user     = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		missing  = common.HexToAddress("0x1000000000000000000000000000000000000003")
+		slot     = common.HexToHash("0x01")
+		empty    = common.HexToHash("0x02")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	statedb.SetBalance(user, big.NewInt(100))
+	statedb.SetNonce(user, 3)
+	statedb.SetCode(contract, []byte{0x60, 0x00})
+	statedb.SetState(contract, slot.Bytes(), []byte{0x2a})
+	root, err := statedb.Commit(true)
+	assert.Nil(t, err)
+	statedb, _ = state.New(root, statedb.Database())
+
+	res := accountResult(t, statedb, user, nil)
+	assert.Nil(t, VerifyAccountProof(root, res))
+	res.Balance = (*hexutil.Big)(big.NewInt(1000))
+	assert.NotNil(t, VerifyAccountProof(root, res))
+
+	keys := []string{slot.Hex(), empty.Hex()}
+	res = accountResult(t, statedb, contract, keys)
+	assert.Equal(t, int64(0x2a), res.StorageProof[0].Value.ToInt().Int64())
+	assert.Nil(t, VerifyAccountProof(root, res))
+	res.StorageProof[1].Value = (*hexutil.Big)(big.NewInt(1))
+	assert.NotNil(t, VerifyAccountProof(root, res))
+
+	res = accountResult(t, statedb, missing, keys)
+	assert.Nil(t, VerifyAccountProof(root, res))
+	res.Nonce = 1
+	assert.NotNil(t, VerifyAccountProof(root, res))
+}
@@ -0,0 +1,176 @@
+package lightclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	ctypes "github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+)
+
+// ErrInvalidQC is returned if a QuorumCert is not signed by enough validators
+// or its aggregated signature does not verify.
+var ErrInvalidQC = errors.New("invalid quorum certificate")
+
+// round returns the consensus round of the block, the first round spanning
+// blocks 1 to RoundBlocks.
+func (c *Client) round(number uint64) uint64 {
+	if number == 0 {
+		return 0
+	}
+	return (number - 1) / c.config.RoundBlocks
+}
+
+// roundStart returns the first block of the consensus round.
+func (c *Client) roundStart(round uint64) uint64 {
+	return round*c.config.RoundBlocks + 1
+}
+
+// validators returns the trusted validator set of the round, following the
+// handovers from the latest trusted round if needed.
+func (c *Client) validators(ctx context.Context, round uint64) (*pbfttypes.Validators, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if vs, ok := c.sets[round]; ok {
+		return vs, nil
+	}
+	if round < c.latest {
+		return nil, ErrBeforeCheckpoint
+	}
+	for r := c.latest + 1; r <= round; r++ {
+		vs, err := c.handover(ctx, c.sets[r-1], r)
+		if err != nil {
+			return nil, err
+		}
+		c.sets[r] = vs
+		c.latest = r
+	}
+	return c.sets[round], nil
+}
+
+// handover queries the validator set of the round and checks it against the
+// QC of the round's first block and the previous trusted set.
+func (c *Client) handover(ctx context.Context, prev *pbfttypes.Validators, round uint64) (*pbfttypes.Validators, error) {
+	next, err := c.fetchValidators(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+	start := c.roundStart(round)
+	header, err := c.ec.HeaderByNumber(ctx, new(big.Int).SetUint64(start))
+	if err != nil {
+		return nil, err
+	}
+	qc, err := c.ec.PrepareQC(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyQuorumCert(header, qc, next); err != nil {
+		return nil, fmt.Errorf("validators of round %d: %v", round, err)
+	}
+	if err := verifyHandover(prev, next, qc); err != nil {
+		return nil, fmt.Errorf("validators of round %d: %v", round, err)
+	}
+	return next, nil
+}
+
+// fetchValidators queries the validator set of the round from the node.
+func (c *Client) fetchValidators(ctx context.Context, round uint64) (*pbfttypes.Validators, error) {
+	start := c.roundStart(round)
+	list, err := c.dc.GetValidatorListAt(ctx, new(big.Int).SetUint64(start))
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(pbfttypes.ValidateNodeMap, len(list))
+	for i, v := range list {
+		pubKey, err := v.NodeId.Pubkey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid node id of validator %d: %v", i, err)
+		}
+		blsPubKey, err := v.BlsPubKey.ParseBlsPubKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid BLS public key of validator %d: %v", i, err)
+		}
+		if _, ok := nodes[v.NodeId]; ok {
+			return nil, fmt.Errorf("duplicate validator %s", v.NodeId.TerminalString())
+		}
+		nodes[v.NodeId] = &pbfttypes.ValidateNode{
+			Index:     uint32(i),
+			Address:   crypto.PubkeyToNodeAddress(*pubKey),
+			PubKey:    pubKey,
+			NodeID:    v.NodeId,
+			BlsPubKey: blsPubKey,
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no validators in round %d", round)
+	}
+	return pbfttypes.NewValidators(nodes, start), nil
+}
+
+// threshold returns the number of signatures confirming a block, the same as
+// the consensus engine requires.
+func threshold(num int) int {
+	return num - (num-1)/3
+}
+
+// verifyQuorumCert checks that qc confirms the header with the signatures of
+// enough validators of vs.
+func verifyQuorumCert(header *types.Header, qc *ctypes.QuorumCert, vs *pbfttypes.Validators) error {
+	if qc.BlockNumber != header.Number.Uint64() || qc.BlockHash != header.Hash() {
+		return fmt.Errorf("%w: confirms block %d %x instead of %d %x", ErrInvalidQC,
+			qc.BlockNumber, qc.BlockHash, header.Number.Uint64(), header.Hash())
+	}
+	if qc.ValidatorSet == nil {
+		return fmt.Errorf("%w: no signers", ErrInvalidQC)
+	}
+	if signs, want := qc.Len(), threshold(vs.Len()); signs < want {
+		return fmt.Errorf("%w: %d signatures, want %d", ErrInvalidQC, signs, want)
+	}
+	nodes, err := vs.NodeListByBitArray(qc.ValidatorSet)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidQC, err)
+	}
+	msg, err := qc.CannibalizeBytes()
+	if err != nil {
+		return err
+	}
+	pub := *nodes[0].BlsPubKey
+	for _, node := range nodes[1:] {
+		pub.Add(node.BlsPubKey)
+	}
+	var sig bls.Sign
+	if err := sig.Deserialize(qc.Signature.Bytes()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidQC, err)
+	}
+	if !sig.Verify(&pub, string(msg)) {
+		return fmt.Errorf("%w: bad aggregated signature", ErrInvalidQC)
+	}
+	return nil
+}
+
+// verifyHandover checks that enough signers of qc, verified under next, were
+// validators of the previous round with the same BLS key. As at most (n-1)/3
+// validators are replaced per round, (n-1)/3+1 of them always sign an honest
+// QC, and at least one of those is honest if the previous set was.
+func verifyHandover(prev, next *pbfttypes.Validators, qc *ctypes.QuorumCert) error {
+	nodes, err := next.NodeListByBitArray(qc.ValidatorSet)
+	if err != nil {
+		return err
+	}
+	known := 0
+	for _, node := range nodes {
+		if old, err := prev.FindNodeByID(node.NodeID); err == nil && old.BlsPubKey.IsEqual(node.BlsPubKey) {
+			known++
+		}
+	}
+	if want := (prev.Len()-1)/3 + 1; known < want {
+		return fmt.Errorf("only %d signers were validators before, want %d", known, want)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package lightclient
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus/pbft/utils"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types/pbfttypes"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+)
+
+func TestValidatorsConcurrentLookup(t *testing.T) {
+	nodes := make(pbfttypes.ValidateNodeMap)
+	for i := 0; i < 7; i++ {
+		id := discover.NodeID{byte(i + 1)}
+		nodes[id] = &pbfttypes.ValidateNode{Index: uint32(i), NodeID: id}
+	}
+	vs := pbfttypes.NewValidators(nodes, 1)
+
+	signers := utils.NewBitArray(7)
+	for i := uint32(0); i < 7; i += 2 {
+		signers.SetIndex(i, true)
+	}
+	// The set is shared by the verifications of concurrent requests.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			list, err := vs.NodeListByBitArray(signers)
+			if assert.Nil(t, err) && assert.Len(t, list, 4) {
+				for j, node := range list {
+					assert.Equal(t, uint32(2*j), node.Index)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -968,6 +968,9 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 		GasLimit:   core.CalcGasLimit(parent, w.config.GasFloor),
 		Time:       uint64(timestamp),
 	}
+	if w.chainConfig.IsDynamicFee(header.Number) {
+		header.BaseFee = core.CalcBaseFee(w.chainConfig, parent.Header())
+	}
 
 	log.Info("Pbft begin to consensus for new block", "number", header.Number, "nonce", hexutil.Encode(header.Nonce[:]), "gasLimit", header.GasLimit, "parentHash", parent.Hash(), "parentNumber", parent.NumberU64(), "parentStateRoot", parent.Root(), "timestamp", common.MillisToString(timestamp))
 	// Initialize the header extra in Prepare function of engine
@@ -1074,7 +1077,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 	var localTimeout = false
 	tempContractCache := make(map[common.Address]struct{})
 	if len(localTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs)
+		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs, header.BaseFee)
 		if failed, timeout := w.committer.CommitTransactions(header, txs, interrupt, timestamp, blockDeadline, tempContractCache); failed {
 			return fmt.Errorf("commit transactions error")
 		} else {
@@ -1087,7 +1090,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 
 	startTime = time.Now()
 	if !localTimeout && len(remoteTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs)
+		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs, header.BaseFee)
 
 		if failed, _ := w.committer.CommitTransactions(header, txs, interrupt, timestamp, blockDeadline, tempContractCache); failed {
 			return fmt.Errorf("commit transactions error")
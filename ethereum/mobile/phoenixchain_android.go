@@ -1,4 +1,6 @@
-// +build androidpackage phoenixchain
+// +build android
+
+package phoenixchain
 
 // clientIdentifier is a hard coded identifier to report into the network.
 var clientIdentifier = "PhoenixChainDroid"
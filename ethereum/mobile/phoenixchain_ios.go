@@ -1,4 +1,6 @@
-// +build iospackage phoenixchain
+// +build ios
+
+package phoenixchain
 
 // clientIdentifier is a hard coded identifier to report into the network.
 var clientIdentifier = "iPhoenixChain"
@@ -13,6 +13,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/hdwallet"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/wallet"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
@@ -78,6 +79,10 @@ type Config struct {
 	// NoUSB disables hardware wallet monitoring and connectivity.
 	NoUSB bool `toml:",omitempty"`
 
+	// KeyPassphrase decrypts the node and BLS keys of the data directory if they
+	// are stored encrypted. Newly generated keys are encrypted with it if set.
+	KeyPassphrase string `toml:"-"`
+
 	// IPCPath is the requested location to place the IPC endpoint. If the path is
 	// a simple file name, it is placed inside the data directory (or on the root
 	// pipe path on Windows), whereas if it's a resolvable path name (absolute or
@@ -142,6 +147,20 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// JWTSecret is the path to a file holding the hex encoded secret shared with
+	// the clients, which sign HS256 tokens with it. When it or JWTPublicKey is set,
+	// HTTP and WebSocket RPC requests must carry a valid bearer token.
+	JWTSecret string `toml:",omitempty"`
+
+	// JWTPublicKey is the path to a PEM encoded RSA or ECDSA public key verifying
+	// RS256 or ES256 tokens issued by an external service.
+	JWTPublicKey string `toml:",omitempty"`
+
+	// RPCLimits are the request quotas enforced per client on the HTTP and
+	// WebSocket RPC endpoints. Clients are told apart by their token subject
+	// if authenticated, by their IP address otherwise.
+	RPCLimits rpc.RateLimits `toml:",omitempty"`
+
 	// GraphQLCors is the Cross-Origin Resource Sharing header to send to requesting
 	// clients. Please be aware that CORS is a browser enforced security, it's fully
 	// useless for custom HTTP clients.
@@ -320,7 +339,8 @@ func (c *Config) instanceDir() string {
 
 // NodeKey retrieves the currently configured private key of the node, checking
 // first any manually set key, falling back to the one found in the configured
-// data folder. If no key can be found, a new one is generated.
+// data folder. If no key can be found, a new one is generated. A key file which
+// can't be loaded is fatal, it is never replaced.
 func (c *Config) NodeKey() *ecdsa.PrivateKey {
 	// Use any specifically configured key.
 	if c.P2P.PrivateKey != nil {
@@ -336,8 +356,11 @@ func (c *Config) NodeKey() *ecdsa.PrivateKey {
 	}
 
 	keyfile := c.ResolvePath(datadirPrivateKey)
-	if key, err := crypto.LoadECDSA(keyfile); err == nil {
+	if key, err := keystore.LoadNodeKey(keyfile, c.KeyPassphrase); err == nil {
 		return key
+	} else if !os.IsNotExist(err) {
+		// Never replace an existing key file, whatever keeps it from loading
+		log.Crit(fmt.Sprintf("Failed to load node key %s: %v", keyfile, err))
 	}
 	// No persistent key found, generate and store a new one.
 	key, err := crypto.GenerateKey()
@@ -350,7 +373,8 @@ func (c *Config) NodeKey() *ecdsa.PrivateKey {
 		return key
 	}
 	keyfile = filepath.Join(instanceDir, datadirPrivateKey)
-	if err := crypto.SaveECDSA(keyfile, key); err != nil {
+	scryptN, scryptP, _, _ := c.AccountConfig()
+	if err := keystore.StoreNodeKey(keyfile, key, c.KeyPassphrase, scryptN, scryptP); err != nil {
 		log.Error(fmt.Sprintf("Failed to persist node key: %v", err))
 	}
 	return key
@@ -358,7 +382,8 @@ func (c *Config) NodeKey() *ecdsa.PrivateKey {
 
 // BlsKey retrieves the currently configured private key of the node,
 // falling back to the one found in the configured
-// data folder. If no key can be found, a new one is generated.
+// data folder. If no key can be found, a new one is generated. A key file which
+// can't be loaded is fatal, it is never replaced.
 func (c *Config) BlsKey() *bls.SecretKey {
 	// Generate ephemeral key if no datadir is being used.
 	if c.DataDir == "" {
@@ -366,8 +391,11 @@ func (c *Config) BlsKey() *bls.SecretKey {
 	}
 
 	keyfile := c.ResolvePath(datadirBlsKey)
-	if key, err := bls.LoadBLS(keyfile); err == nil {
+	if key, err := keystore.LoadBLSKey(keyfile, c.KeyPassphrase); err == nil {
 		return key
+	} else if !os.IsNotExist(err) {
+		// Never replace an existing key file, whatever keeps it from loading
+		log.Crit(fmt.Sprintf("Failed to load bls key %s: %v", keyfile, err))
 	}
 
 	privateKey := bls.GenerateKey()
@@ -378,7 +406,8 @@ func (c *Config) BlsKey() *bls.SecretKey {
 		return privateKey
 	}
 	keyfile = filepath.Join(instanceDir, datadirBlsKey)
-	if err := bls.SaveBLS(keyfile, privateKey); err != nil {
+	scryptN, scryptP, _, _ := c.AccountConfig()
+	if err := keystore.StoreBLSKey(keyfile, privateKey, c.KeyPassphrase, scryptN, scryptP); err != nil {
 		log.Error(fmt.Sprintf("Failed to persist bls key: %v", err))
 	}
 	return privateKey
@@ -474,6 +503,7 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 	// Assemble the account manager and supported backends
 	backends := []accounts.Backend{
 		keystore.NewKeyStore(keydir, scryptN, scryptP),
+		hdwallet.NewHub(keydir, scryptN, scryptP),
 	}
 	if !conf.NoUSB {
 		// Start a USB hub for Ledger hardware wallets
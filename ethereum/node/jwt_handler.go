@@ -0,0 +1,193 @@
+package node
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rpc"
+)
+
+// minJWTSecretLength is the minimum length of the HS256 shared secret.
+const minJWTSecretLength = 32
+
+var errMissingToken = errors.New("missing bearer token")
+
+// rpcClaims are the claims of the tokens authenticating RPC requests.
+type rpcClaims struct {
+	jwt.RegisteredClaims
+
+	// Allow lists the namespaces (e.g. "eth") and methods (e.g. "admin_peers")
+	// the token grants access to. A token without it may call every method of
+	// the modules enabled on the endpoint.
+	Allow []string `json:"allow,omitempty"`
+}
+
+// jwtAuth verifies the bearer tokens of HTTP and WebSocket RPC requests.
+type jwtAuth struct {
+	secret    []byte           // HS256 shared secret, nil if not configured
+	publicKey crypto.PublicKey // RS256 or ES256 verification key, nil if not configured
+	methods   []string         // accepted signing algorithms
+}
+
+// newJWTAuth loads the token verification keys configured for the node. It
+// returns nil if none is configured, leaving the endpoints unauthenticated.
+func newJWTAuth(conf *Config) (*jwtAuth, error) {
+	if conf.JWTSecret == "" && conf.JWTPublicKey == "" {
+		return nil, nil
+	}
+	auth := new(jwtAuth)
+	if conf.JWTSecret != "" {
+		secretFile := conf.ResolvePath(conf.JWTSecret)
+		blob, err := ioutil.ReadFile(secretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT secret: %v", err)
+		}
+		secret := common.FromHex(strings.TrimSpace(string(blob)))
+		if len(secret) < minJWTSecretLength {
+			return nil, fmt.Errorf("JWT secret in %s too short, need at least %d hex encoded bytes", secretFile, minJWTSecretLength)
+		}
+		auth.secret = secret
+		auth.methods = append(auth.methods, jwt.SigningMethodHS256.Alg())
+	}
+	if conf.JWTPublicKey != "" {
+		publicKeyFile := conf.ResolvePath(conf.JWTPublicKey)
+		blob, err := ioutil.ReadFile(publicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT public key: %v", err)
+		}
+		block, _ := pem.Decode(blob)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM data in %s", publicKeyFile)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT public key: %v", err)
+		}
+		switch key.(type) {
+		case *rsa.PublicKey:
+			auth.methods = append(auth.methods, jwt.SigningMethodRS256.Alg())
+		case *ecdsa.PublicKey:
+			auth.methods = append(auth.methods, jwt.SigningMethodES256.Alg())
+		default:
+			return nil, fmt.Errorf("unsupported JWT public key type %T", key)
+		}
+		auth.publicKey = key
+	}
+	return auth, nil
+}
+
+// keyFunc returns the key verifying the token, depending on its algorithm.
+func (a *jwtAuth) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return a.secret, nil
+	default:
+		return a.publicKey, nil
+	}
+}
+
+// verify authenticates the request, returning the claims of its token.
+func (a *jwtAuth) verify(r *http.Request) (*rpcClaims, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil, errMissingToken
+	}
+	claims := new(rpcClaims)
+	parser := jwt.NewParser(jwt.WithValidMethods(a.methods))
+	if _, err := parser.ParseWithClaims(strings.TrimPrefix(header, "Bearer "), claims, a.keyFunc); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// jwtHandler is a handler which rejects requests lacking a valid token and
+// restricts the others to the methods their token grants.
+type jwtHandler struct {
+	auth *jwtAuth
+	next http.Handler
+}
+
+func newJWTHandler(auth *jwtAuth, next http.Handler) http.Handler {
+	return &jwtHandler{auth: auth, next: next}
+}
+
+// ServeHTTP implements http.Handler
+func (h *jwtHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	claims, err := h.auth.verify(r)
+	if err != nil {
+		log.Warn("RPC authentication failed", "remote", r.RemoteAddr, "err", err)
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+	if claims.Subject != "" {
+		r = r.WithContext(rpc.WithClientIdentity(r.Context(), "token:"+claims.Subject))
+	}
+	if len(claims.Allow) > 0 {
+		ac := newTokenAccess(claims.Subject, r.RemoteAddr, claims.Allow)
+		r = r.WithContext(rpc.WithAccessControl(r.Context(), ac))
+	}
+	h.next.ServeHTTP(w, r)
+}
+
+// tokenAccess restricts the calls of a request to the allowlist of its token.
+type tokenAccess struct {
+	subject    string
+	remote     string
+	namespaces map[string]struct{}
+	methods    map[string]struct{}
+}
+
+func newTokenAccess(subject, remote string, allow []string) *tokenAccess {
+	ta := &tokenAccess{
+		subject:    subject,
+		remote:     remote,
+		namespaces: make(map[string]struct{}),
+		methods:    make(map[string]struct{}),
+	}
+	for _, entry := range allow {
+		if strings.Contains(entry, "_") {
+			ta.methods[entry] = struct{}{}
+		} else {
+			ta.namespaces[entry] = struct{}{}
+		}
+	}
+	return ta
+}
+
+// Allowed implements rpc.AccessControl.
+func (ta *tokenAccess) Allowed(method string) bool {
+	if _, ok := ta.methods[method]; ok {
+		return true
+	}
+	namespace := strings.SplitN(method, "_", 2)[0]
+	if _, ok := ta.namespaces[namespace]; ok {
+		return true
+	}
+	log.Warn("RPC call denied", "subject", ta.subject, "remote", ta.remote, "method", method)
+	return false
+}
+
+// newAllowHandler rejects the requests whose token has an allowlist that does
+// not grant name, the way calls to methods outside of it are denied.
+func newAllowHandler(name string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ac, ok := rpc.AccessControlFromContext(r.Context()); ok && !ac.Allowed(name) {
+			http.Error(w, "access denied by token", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,114 @@
+package node
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rpc"
+)
+
+const testJWTSecret = "0x7365637265747365637265747365637265747365637265747365637265747365"
+
+func newTestJWTAuth(t *testing.T) (*jwtAuth, []byte) {
+	dir, err := ioutil.TempDir("", "jwt-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	file := filepath.Join(dir, "jwtsecret")
+	if err := ioutil.WriteFile(file, []byte(testJWTSecret), 0600); err != nil {
+		t.Fatal(err)
+	}
+	auth, err := newJWTAuth(&Config{JWTSecret: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth, auth.secret
+}
+
+func signTestToken(t *testing.T, secret []byte, claims *rpcClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestJWTHandler(t *testing.T) {
+	auth, secret := newTestJWTAuth(t)
+
+	var served rpc.AccessControl
+	handler := newJWTHandler(auth, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served, _ = rpc.AccessControlFromContext(r.Context())
+	}))
+	expired := jwt.NewNumericDate(time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		header string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer garbage", http.StatusUnauthorized},
+		{"Bearer " + signTestToken(t, []byte("wrongsecretwrongsecretwrongsecret"), new(rpcClaims)), http.StatusUnauthorized},
+		{"Bearer " + signTestToken(t, secret, &rpcClaims{RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: expired}}), http.StatusUnauthorized},
+		{"Bearer " + signTestToken(t, secret, new(rpcClaims)), http.StatusOK},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("POST", "/", strings.NewReader("{}"))
+		if test.header != "" {
+			req.Header.Set("Authorization", test.header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != test.status {
+			t.Errorf("test %d: status mismatch: have %d, want %d", i, rec.Code, test.status)
+		}
+	}
+	if served != nil {
+		t.Fatal("unrestricted token installed access control")
+	}
+
+	// A token with an allowlist restricts the methods of the request.
+	token := signTestToken(t, secret, &rpcClaims{Allow: []string{"phoenixchain", "admin_peers"}})
+	req := httptest.NewRequest("POST", "/", strings.NewReader("{}"))
+	req.Header.Set("Authorization", "Bearer "+token)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if served == nil {
+		t.Fatal("restricted token installed no access control")
+	}
+	for method, want := range map[string]bool{
+		"phoenixchain_blockNumber": true,
+		"admin_peers":              true,
+		"admin_addPeer":            false,
+		"personal_unlockAccount":   false,
+	} {
+		if have := served.Allowed(method); have != want {
+			t.Errorf("%s: allowed mismatch: have %v, want %v", method, have, want)
+		}
+	}
+}
+
+func TestJWTSecretTooShort(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwt-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "jwtsecret")
+	if err := ioutil.WriteFile(file, []byte("0x1234"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newJWTAuth(&Config{JWTSecret: file}); err == nil {
+		t.Fatal("expected error for short secret")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -26,6 +27,14 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rpc"
 )
 
+// pathHandler is a handler registered by a service for a path of the HTTP
+// endpoint, next to the RPC API.
+type pathHandler struct {
+	name    string
+	path    string
+	handler http.Handler
+}
+
 // Node is a container on which services can be registered.
 type Node struct {
 	eventmux *event.TypeMux // Event multiplexer used between the services of a stack
@@ -55,10 +64,15 @@ type Node struct {
 	httpListener  net.Listener // HTTP RPC listener socket to server API requests
 	httpHandler   *rpc.Server  // HTTP RPC request handler to process the API requests
 
+	handlers     []pathHandler // Handlers mounted by the services on the HTTP endpoint
+	handlersLock sync.Mutex
+
 	wsEndpoint string       // Websocket endpoint (interface + port) to listen at (empty = websocket disabled)
 	wsListener net.Listener // Websocket RPC listener socket to server API requests
 	wsHandler  *rpc.Server  // Websocket RPC request handler to process the API requests
 
+	rpcAuth *jwtAuth // Token verification of the HTTP and websocket endpoints, nil if disabled
+
 	stop chan struct{} // Channel to wait for termination notifications
 	lock sync.RWMutex
 
@@ -149,6 +163,24 @@ func (n *Node) Register(constructor ServiceConstructor) error {
 	return nil
 }
 
+// RegisterHandler mounts handler on path of the HTTP RPC endpoint, behind its
+// token authentication and the request quotas of its clients, each request
+// costing a call to a method called name. Tokens with an allowlist must grant
+// name to reach it. It is meant to be called by the service constructors, the
+// handler is not served if the HTTP endpoint is disabled.
+func (n *Node) RegisterHandler(name, path string, handler http.Handler) error {
+	n.handlersLock.Lock()
+	defer n.handlersLock.Unlock()
+
+	for _, h := range n.handlers {
+		if h.path == path {
+			return fmt.Errorf("path %s already registered by %s", path, h.name)
+		}
+	}
+	n.handlers = append(n.handlers, pathHandler{name: name, path: path, handler: handler})
+	return nil
+}
+
 // Start creates a live P2P node and starts running it.
 func (n *Node) Start() error {
 	n.lock.Lock()
@@ -188,6 +220,11 @@ func (n *Node) Start() error {
 	running := &p2p.Server{Config: n.serverConfig}
 	n.log.Info("Starting peer-to-peer node", "instance", n.serverConfig.Name)
 
+	// The service constructors register their handlers again
+	n.handlersLock.Lock()
+	n.handlers = nil
+	n.handlersLock.Unlock()
+
 	// Otherwise copy and specialize the P2P configuration
 	services := make(map[reflect.Type]Service)
 
@@ -290,6 +327,12 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 	for _, service := range services {
 		apis = append(apis, service.APIs()...)
 	}
+	auth, err := newJWTAuth(n.config)
+	if err != nil {
+		return err
+	}
+	n.rpcAuth = auth
+
 	// Start the various API endpoints, terminating all in case of errors
 	if err := n.startInProc(apis); err != nil {
 		return err
@@ -376,6 +419,7 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 	}
 	// register apis and create handler stack
 	srv := rpc.NewServer()
+	srv.SetRateLimits(n.config.RPCLimits)
 	err := RegisterApisFromWhitelist(apis, modules, srv, false)
 	if err != nil {
 		return err
@@ -385,6 +429,13 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 	if n.httpEndpoint == n.wsEndpoint {
 		handler = NewWebsocketUpgradeHandler(handler, srv.WebsocketHandler(wsOrigins))
 	}
+	n.handlersLock.Lock()
+	handlers := n.handlers
+	n.handlersLock.Unlock()
+	handler = mountHandlers(handler, srv, handlers)
+	if n.rpcAuth != nil {
+		handler = newJWTHandler(n.rpcAuth, handler)
+	}
 	listener, err := StartHTTPEndpoint(endpoint, timeouts, handler)
 	if err != nil {
 		return err
@@ -395,6 +446,9 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 	if n.httpEndpoint == n.wsEndpoint {
 		n.log.Info("WebSocket endpoint opened", "url", fmt.Sprintf("ws://%v", listener.Addr()))
 	}
+	for _, h := range handlers {
+		n.log.Info(fmt.Sprintf("%s endpoint opened", h.name), "url", fmt.Sprintf("http://%v%s", listener.Addr(), h.path))
+	}
 	// All listeners booted successfully
 	n.httpEndpoint = endpoint
 	n.httpListener = listener
@@ -403,6 +457,22 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 	return nil
 }
 
+// mountHandlers serves the handlers registered by the services next to the RPC
+// handler, charging their requests to the client quotas of srv.
+func mountHandlers(rpcHandler http.Handler, srv *rpc.Server, handlers []pathHandler) http.Handler {
+	if len(handlers) == 0 {
+		return rpcHandler
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", rpcHandler)
+	for _, h := range handlers {
+		handler := newAllowHandler(h.name, srv.LimitHandler(h.name, h.handler))
+		mux.Handle(h.path, handler)
+		mux.Handle(h.path+"/", handler)
+	}
+	return mux
+}
+
 // stopHTTP terminates the HTTP RPC endpoint.
 func (n *Node) stopHTTP() {
 	if n.httpListener != nil {
@@ -425,11 +495,15 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 	}
 
 	srv := rpc.NewServer()
+	srv.SetRateLimits(n.config.RPCLimits)
 	handler := srv.WebsocketHandler(wsOrigins)
 	err := RegisterApisFromWhitelist(apis, modules, srv, exposeAll)
 	if err != nil {
 		return err
 	}
+	if n.rpcAuth != nil {
+		handler = newJWTHandler(n.rpcAuth, handler)
+	}
 	listener, err := startWSEndpoint(endpoint, handler)
 	if err != nil {
 		return err
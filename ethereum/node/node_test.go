@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
 	"testing"
@@ -638,3 +639,53 @@ func doHTTPRequest(t *testing.T, req *http.Request) *http.Response {
 	}
 	return resp
 }
+
+// Tests that the handlers registered by services are served on the HTTP
+// endpoint behind its token authentication and request quotas.
+func TestRegisterHandler(t *testing.T) {
+	stack, err := New(testNodeConfig())
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	defer stack.Close()
+
+	served := 0
+	test := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { served++ })
+	if err := stack.RegisterHandler("test", "/test", test); err != nil {
+		t.Fatalf("failed to register handler: %v", err)
+	}
+	if err := stack.RegisterHandler("other", "/test", test); err == nil {
+		t.Fatal("registered two handlers on the same path")
+	}
+
+	auth, secret := newTestJWTAuth(t)
+	srv := rpc.NewServer()
+	srv.SetRateLimits(rpc.RateLimits{RequestRate: 0.001, RequestBurst: 2})
+	handler := newJWTHandler(auth, mountHandlers(srv, srv, stack.handlers))
+
+	tests := []struct {
+		path   string
+		token  *rpcClaims
+		status int
+	}{
+		{"/test", nil, http.StatusUnauthorized},
+		{"/test", &rpcClaims{Allow: []string{"phoenixchain"}}, http.StatusForbidden},
+		{"/test", &rpcClaims{Allow: []string{"test"}}, http.StatusOK},
+		{"/test/", new(rpcClaims), http.StatusOK},
+		{"/test", new(rpcClaims), http.StatusTooManyRequests},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest("GET", test.path, nil)
+		if test.token != nil {
+			req.Header.Set("Authorization", "Bearer "+signTestToken(t, secret, test.token))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != test.status {
+			t.Errorf("test %d: status mismatch: have %d, want %d", i, rec.Code, test.status)
+		}
+	}
+	if served != 2 {
+		t.Errorf("handler served %d requests, want 2", served)
+	}
+}
@@ -1,4 +1,6 @@
-// +build go1.4,nacl,faketime_simulationpackage discv5
+// +build go1.4,nacl,faketime_simulation
+
+package discv5
 
 import (
 	"os"
@@ -13,6 +13,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/wallet"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/signer/eip712"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
@@ -30,6 +31,8 @@ type ExternalAPI interface {
 	SignTransaction(ctx context.Context, args SendTxArgs, methodSelector *string) (*ethapi.SignTransactionResult, error)
 	// Sign - request to sign the given data (plus prefix)
 	Sign(ctx context.Context, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error)
+	// SignTypedData - request to sign the given EIP-712 typed data
+	SignTypedData(ctx context.Context, addr common.MixedcaseAddress, typedData eip712.TypedData) (hexutil.Bytes, error)
 	// EcRecover - request to perform ecrecover
 	EcRecover(ctx context.Context, data, sig hexutil.Bytes) (common.Address, error)
 	// Export - request to export an account
@@ -111,6 +114,7 @@ type (
 	SignTxRequest struct {
 		Transaction SendTxArgs       `json:"transaction"`
 		Callinfo    []ValidationInfo `json:"call_info"`
+		InnerCall   *InnerCall       `json:"inner_call,omitempty"`
 		Meta        Metadata         `json:"meta"`
 	}
 	// SignTxResponse result from SignTxRequest
@@ -144,6 +148,8 @@ type (
 		Message string                  `json:"message"`
 		Hash hexutil.Bytes `json:"hash"`
 		Meta Metadata      `json:"meta"`
+		// Messages holds the decoded fields of typed data
+		Messages []*eip712.NameValueType `json:"messages,omitempty"`
 	}
 	SignDataResponse struct {
 		Approved bool `json:"approved"`
@@ -310,6 +316,7 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args SendTxArgs, meth
 		Transaction: args,
 		Meta:        MetadataFromContext(ctx),
 		Callinfo:    msgs.Messages,
+		InnerCall:   DecodeInnerCall(&args),
 	}
 	// Process approval
 	result, err = api.UI.ApproveTx(&req)
@@ -364,6 +371,33 @@ func (api *SignerAPI) Sign(ctx context.Context, addr common.MixedcaseAddress, da
 	// We make the request prior to looking up if we actually have the account, to prevent
 	// account-enumeration via the API
 	req := &SignDataRequest{Address: addr, Rawdata: data, Message: msg, Hash: sighash, Meta: MetadataFromContext(ctx)}
+	return api.signData(req)
+}
+
+// SignTypedData signs the hash of EIP-712 typed data, which binds the message to
+// a signing domain and lets the user review its decoded fields before approving.
+//
+// Note, the produced signature conforms to the secp256k1 curve R, S and V values,
+// where the V value will be 27 or 28 for legacy reasons.
+//
+// The key used to calculate the signature is decrypted with the given password.
+func (api *SignerAPI) SignTypedData(ctx context.Context, addr common.MixedcaseAddress, typedData eip712.TypedData) (hexutil.Bytes, error) {
+	sighash, rawData, err := eip712.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := typedData.Format()
+	if err != nil {
+		return nil, err
+	}
+	req := &SignDataRequest{Address: addr, Rawdata: []byte(rawData), Messages: messages, Hash: sighash, Meta: MetadataFromContext(ctx)}
+	return api.signData(req)
+}
+
+// signData asks the user to approve signing the hash of a request, and signs it
+// with the key of the requested account.
+func (api *SignerAPI) signData(req *SignDataRequest) (hexutil.Bytes, error) {
+	addr := req.Address
 	res, err := api.UI.ApproveSignData(req)
 
 	if err != nil {
@@ -379,7 +413,7 @@ func (api *SignerAPI) Sign(ctx context.Context, addr common.MixedcaseAddress, da
 		return nil, err
 	}
 	// Assemble sign the data with the wallet
-	signature, err := wallet.SignHashWithPassphrase(account, res.Password, sighash)
+	signature, err := wallet.SignHashWithPassphrase(account, res.Password, req.Hash)
 	if err != nil {
 		api.UI.ShowError(err.Error())
 		return nil, err
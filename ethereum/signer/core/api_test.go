@@ -15,8 +15,10 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/commands/utils"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts/keystore"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/signer/eip712"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/math"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
 )
 
@@ -238,6 +240,52 @@ func TestSignData(t *testing.T) {
 		t.Errorf("Expected 65 byte signature (got %d bytes)", len(h))
 	}
 }
+
+func TestSignTypedData(t *testing.T) {
+
+	api, control := setup(t)
+	createAccount(control, api, t)
+	a := common.NewMixedcaseAddress(list(control, api, t)[0].Address)
+
+	typedData := eip712.TypedData{
+		Types: eip712.Types{
+			"EIP712Domain": {{Name: "name", Type: "string"}, {Name: "chainId", Type: "uint256"}},
+			"Permit":       {{Name: "spender", Type: "address"}, {Name: "value", Type: "uint256"}},
+		},
+		PrimaryType: "Permit",
+		Domain:      eip712.TypedDataDomain{Name: "Token", ChainId: (*math.HexOrDecimal256)(big.NewInt(1))},
+		Message:     eip712.TypedDataMessage{"spender": "0x0000000000000000000000000000000000001337", "value": "1000"},
+	}
+	control <- "No way"
+	h, err := api.SignTypedData(context.Background(), a, typedData)
+	if h != nil {
+		t.Errorf("Expected nil-data, got %x", h)
+	}
+	if err != ErrRequestDenied {
+		t.Errorf("Expected ErrRequestDenied! %v", err)
+	}
+
+	control <- "Y"
+	control <- "apassword"
+	h, err = api.SignTypedData(context.Background(), a, typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := eip712.RecoverTypedData(typedData, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(signer.Bytes(), a.Address().Bytes()) {
+		t.Errorf("Expected signature of %x, recovered %x", a.Address(), signer)
+	}
+
+	// Typed data which doesn't match its types is rejected before asking the user
+	typedData.Message["value"] = "-1"
+	if _, err = api.SignTypedData(context.Background(), a, typedData); err == nil {
+		t.Errorf("Expected error for invalid typed data")
+	}
+}
+
 func mkTestTx(from common.MixedcaseAddress) SendTxArgs {
 	to := common.NewMixedcaseAddress(common.HexToAddress("0x1337"))
 	gas := hexutil.Uint64(21000)
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/signer/eip712"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
@@ -56,6 +57,15 @@ func (l *AuditLogger) Sign(ctx context.Context, addr common.MixedcaseAddress, da
 	return b, e
 }
 
+func (l *AuditLogger) SignTypedData(ctx context.Context, addr common.MixedcaseAddress, typedData eip712.TypedData) (hexutil.Bytes, error) {
+	data, _ := json.Marshal(typedData)
+	l.log.Info("SignTypedData", "type", "request", "metadata", MetadataFromContext(ctx).String(),
+		"addr", addr.String(), "data", string(data))
+	b, e := l.api.SignTypedData(ctx, addr, typedData)
+	l.log.Info("SignTypedData", "type", "response", "data", common.Bytes2Hex(b), "error", e)
+	return b, e
+}
+
 func (l *AuditLogger) EcRecover(ctx context.Context, data, sig hexutil.Bytes) (common.Address, error) {
 	l.log.Info("EcRecover", "type", "request", "metadata", MetadataFromContext(ctx).String(),
 		"data", common.Bytes2Hex(data))
@@ -8,6 +8,7 @@ import (
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/dposclient"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
 )
@@ -44,6 +45,15 @@ type ValidationMessages struct {
 	Messages []ValidationInfo
 }
 
+// InnerCall is a decoded transaction to one of the built-in PoS contracts,
+// exposing its parameters by name to the rules.
+type InnerCall struct {
+	Contract string                 `json:"contract"`
+	FuncType uint16                 `json:"funcType"`
+	Method   string                 `json:"method"`
+	Params   map[string]interface{} `json:"params"`
+}
+
 // SendTxArgs represents the arguments to submit a transaction
 type SendTxArgs struct {
 	From     common.MixedcaseAddress  `json:"from"`
@@ -65,6 +75,24 @@ func (args SendTxArgs) String() string {
 	return err.Error()
 }
 
+// DecodeInnerCall decodes the data of a transaction to a built-in PoS contract,
+// returning nil for any other transaction or data that can't be decoded.
+func DecodeInnerCall(args *SendTxArgs) *InnerCall {
+	if args.To == nil || args.Data == nil {
+		return nil
+	}
+	call, err := dposclient.DecodeFunc(args.To.Address(), *args.Data)
+	if err != nil {
+		return nil
+	}
+	return &InnerCall{
+		Contract: call.Contract,
+		FuncType: call.FuncType,
+		Method:   call.Method,
+		Params:   call.Args(),
+	}
+}
+
 func (args *SendTxArgs) toTransaction() *types.Transaction {
 	var input []byte
 	if args.Data != nil {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/dposclient"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 )
 
@@ -86,6 +87,24 @@ func (v *Validator) validateCallData(msgs *ValidationMessages, data []byte, meth
 	}
 }
 
+// validateInnerCall checks that the data of a transaction to a built-in PoS contract
+// can be decoded, and shows the decoded call
+func (v *Validator) validateInnerCall(msgs *ValidationMessages, to common.Address, data []byte, methodSelector *string) {
+	if methodSelector != nil {
+		msgs.warn("Tx is sent to a built-in contract, but method selector supplied; the selector is ignored.")
+	}
+	if len(data) == 0 {
+		msgs.crit(fmt.Sprintf("Tx is sent to the %s without data", dposclient.ContractName(to)))
+		return
+	}
+	call, err := dposclient.DecodeFunc(to, data)
+	if err != nil {
+		msgs.crit(fmt.Sprintf("Tx contains data the %s can not decode: %v", dposclient.ContractName(to), err))
+		return
+	}
+	msgs.info(call.String())
+}
+
 // validateSemantics checks if the transactions 'makes sense', and generate warnings for a couple of typical scenarios
 func (v *Validator) validate(msgs *ValidationMessages, txargs *SendTxArgs, methodSelector *string) error {
 	// Prevent accidental erroneous usage of both 'input' and 'data'
@@ -133,8 +152,12 @@ func (v *Validator) validate(msgs *ValidationMessages, txargs *SendTxArgs, metho
 			// Sending to 0
 			msgs.crit("Tx destination is the zero address!")
 		}
-		// Validate calldata
-		v.validateCallData(msgs, data, methodSelector)
+		// Validate calldata, the built-in contracts take RLP instead of ABI encoded calls
+		if dposclient.ContractName(txargs.To.Address()) != "" {
+			v.validateInnerCall(msgs, txargs.To.Address(), data, methodSelector)
+		} else {
+			v.validateCallData(msgs, data, methodSelector)
+		}
 	}
 	return nil
 }
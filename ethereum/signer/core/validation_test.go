@@ -91,6 +91,15 @@ func TestValidator(t *testing.T) {
 		// Small payload for create
 		{from: "000000000000000000000000000000000000dead", to: "",
 			n: "0x01", g: "0x20", gp: "0x40", value: "0x01", d: "0x01", numMessages: 1},
+		// Built-in contract call (withdrawDelegateReward)
+		{from: "000000000000000000000000000000000000dead", to: "0x1000000000000000000000000000000000000006",
+			n: "0x01", g: "0x20", gp: "0x40", value: "0x00", d: "0xc483821388", numMessages: 1},
+		// Built-in contract call to the wrong contract
+		{from: "000000000000000000000000000000000000dead", to: "0x1000000000000000000000000000000000000002",
+			n: "0x01", g: "0x20", gp: "0x40", value: "0x00", d: "0xc483821388", numMessages: 1},
+		// Built-in contract without data
+		{from: "000000000000000000000000000000000000dead", to: "0x1000000000000000000000000000000000000002",
+			n: "0x01", g: "0x20", gp: "0x40", value: "0x00", numMessages: 1},
 	}
 	for i, test := range testcases {
 		msgs, err := v.ValidateTransaction(dummyTxArgs(test), nil)
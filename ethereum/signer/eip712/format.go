@@ -0,0 +1,133 @@
+package eip712
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NameValueType is a decoded field of typed data, displayed to the user when
+// approving a signature.
+type NameValueType struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+	Typ   string      `json:"type"`
+}
+
+// Pprint returns a pretty-printed version of the field and its nested fields.
+func (nvt *NameValueType) Pprint(depth int) string {
+	output := bytes.Buffer{}
+	output.WriteString(strings.Repeat(" ", depth*2))
+	output.WriteString(fmt.Sprintf("%s [%s]: ", nvt.Name, nvt.Typ))
+	if nvts, ok := nvt.Value.([]*NameValueType); ok {
+		output.WriteString("\n")
+		for _, next := range nvts {
+			output.WriteString(next.Pprint(depth + 1))
+		}
+	} else {
+		output.WriteString(fmt.Sprintf("%q\n", fmt.Sprint(nvt.Value)))
+	}
+	return output.String()
+}
+
+// Format returns the domain and the message of the typed data decoded into
+// their fields, for displaying them to the user.
+func (typedData *TypedData) Format() ([]*NameValueType, error) {
+	if err := typedData.validate(); err != nil {
+		return nil, err
+	}
+	domain, err := typedData.formatData(DomainType, typedData.Domain.Map(), 1)
+	if err != nil {
+		return nil, err
+	}
+	message, err := typedData.formatData(typedData.PrimaryType, typedData.Message, 1)
+	if err != nil {
+		return nil, err
+	}
+	return []*NameValueType{
+		{Name: DomainType, Value: domain, Typ: "domain"},
+		{Name: typedData.PrimaryType, Value: message, Typ: "primary type"},
+	}, nil
+}
+
+// formatData decodes a struct of the given type into its fields, in the order
+// of the type definition.
+func (typedData *TypedData) formatData(primaryType string, data TypedDataMessage, depth int) ([]*NameValueType, error) {
+	if depth > maxDepth {
+		return nil, errMaxDepth
+	}
+	fields, ok := typedData.Types[primaryType]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", primaryType)
+	}
+	var output []*NameValueType
+	for _, field := range fields {
+		value := data[field.Name]
+		item := &NameValueType{Name: field.Name, Typ: field.Type}
+		if field.isArray() {
+			items, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("field %q: expected array, got %T", field.Name, value)
+			}
+			var elems []*NameValueType
+			for i, elem := range items {
+				formatted, err := typedData.formatValue(field.typeName(), elem, depth)
+				if err != nil {
+					return nil, fmt.Errorf("field %q[%d]: %v", field.Name, i, err)
+				}
+				elems = append(elems, &NameValueType{Name: fmt.Sprintf("%d", i), Value: formatted, Typ: field.typeName()})
+			}
+			item.Value = elems
+		} else {
+			formatted, err := typedData.formatValue(field.Type, value, depth)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %v", field.Name, err)
+			}
+			item.Value = formatted
+		}
+		output = append(output, item)
+	}
+	if len(data) > len(fields) {
+		var unknown []string
+		for name := range data {
+			if !hasField(fields, name) {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("type %q has no fields %s", primaryType, strings.Join(unknown, ", "))
+	}
+	return output, nil
+}
+
+// formatValue decodes a single value of the given non-array type, validating it
+// the same way it is encoded for hashing.
+func (typedData *TypedData) formatValue(encType string, value interface{}, depth int) (interface{}, error) {
+	if _, ok := typedData.Types[encType]; ok {
+		mapValue, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected struct %s, got %T", encType, value)
+		}
+		return typedData.formatData(encType, mapValue, depth+1)
+	}
+	if _, err := encodePrimitiveValue(encType, value); err != nil {
+		return nil, err
+	}
+	// Show integers in decimal, however they were given.
+	if match := sizedTypeRegexp.FindStringSubmatch(encType); match != nil && match[1] != "bytes" {
+		integer, _ := parseInteger(value)
+		return integer.String(), nil
+	}
+	return value, nil
+}
+
+// hasField reports whether a field of the given name is defined.
+func hasField(fields []Type, name string) bool {
+	for _, field := range fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package eip712
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+// signatureLength is the length of a [R || S || V] signature.
+const signatureLength = 65
+
+// SignTypedData signs the typed data with the given key. The recovery id of the
+// returned signature is 27 or 28, as expected by ecrecover in contracts.
+func SignTypedData(key *ecdsa.PrivateKey, typedData TypedData) ([]byte, error) {
+	hash, _, err := TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := crypto.Sign(hash, key)
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+// RecoverTypedData returns the address which signed the typed data. Both 0/1 and
+// 27/28 recovery ids are accepted.
+func RecoverTypedData(typedData TypedData, signature []byte) (common.Address, error) {
+	if len(signature) != signatureLength {
+		return common.Address{}, fmt.Errorf("signature must be %d bytes long", signatureLength)
+	}
+	hash, _, err := TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Address{}, err
+	}
+	sig := make([]byte, len(signature))
+	copy(sig, signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	if sig[64] > 1 {
+		return common.Address{}, fmt.Errorf("invalid recovery id %d", signature[64])
+	}
+	pubkey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
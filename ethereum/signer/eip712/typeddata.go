@@ -0,0 +1,405 @@
+// Package eip712 implements the hashing of EIP-712 typed structured data, used
+// for signing off-chain messages such as permits and orders in a form users can
+// review field by field.
+package eip712
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/math"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+// DomainType is the name of the type describing the signing domain.
+const DomainType = "EIP712Domain"
+
+// maxDepth is the maximum nesting depth of structs, protecting against cyclic
+// type definitions.
+const maxDepth = 32
+
+var (
+	// typedDataPrefix is prepended to the domain separator and the message hash
+	// before hashing, so the signed hash can never be a valid transaction.
+	typedDataPrefix = []byte("\x19\x01")
+
+	// typeNameRegexp matches the valid names of struct types.
+	typeNameRegexp = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z_$0-9]*$`)
+
+	// sizedTypeRegexp splits the sized primitive types into name and size.
+	sizedTypeRegexp = regexp.MustCompile(`^(u?int|bytes)([0-9]+)$`)
+
+	// errMaxDepth is returned if the structs of a message are nested too deep.
+	errMaxDepth = errors.New("max depth exceeded")
+)
+
+// Type is a field of a struct type, its name and type.
+type Type struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// isArray reports whether the field is an array of its base type.
+func (t *Type) isArray() bool {
+	return strings.HasSuffix(t.Type, "[]")
+}
+
+// typeName returns the base type of the field, stripping the array suffix.
+func (t *Type) typeName() string {
+	return strings.TrimSuffix(t.Type, "[]")
+}
+
+// Types maps the names of struct types to their fields.
+type Types map[string][]Type
+
+// TypedDataMessage is the content of a struct, field names mapped to values.
+type TypedDataMessage = map[string]interface{}
+
+// TypedDataDomain is the signing domain, binding signatures to a particular
+// application, contract and chain.
+type TypedDataDomain struct {
+	Name              string                `json:"name"`
+	Version           string                `json:"version"`
+	ChainId           *math.HexOrDecimal256 `json:"chainId"`
+	VerifyingContract string                `json:"verifyingContract"`
+	Salt              string                `json:"salt"`
+}
+
+// Map returns the fields of the domain which are set, in the form hashed as the
+// domain separator.
+func (domain *TypedDataDomain) Map() TypedDataMessage {
+	dataMap := TypedDataMessage{}
+	if domain.Name != "" {
+		dataMap["name"] = domain.Name
+	}
+	if domain.Version != "" {
+		dataMap["version"] = domain.Version
+	}
+	if domain.ChainId != nil {
+		dataMap["chainId"] = (*big.Int)(domain.ChainId)
+	}
+	if domain.VerifyingContract != "" {
+		dataMap["verifyingContract"] = domain.VerifyingContract
+	}
+	if domain.Salt != "" {
+		dataMap["salt"] = domain.Salt
+	}
+	return dataMap
+}
+
+// TypedData is a complete EIP-712 signing request: the type definitions, the
+// signing domain and the message of the primary type.
+type TypedData struct {
+	Types       Types            `json:"types"`
+	PrimaryType string           `json:"primaryType"`
+	Domain      TypedDataDomain  `json:"domain"`
+	Message     TypedDataMessage `json:"message"`
+}
+
+// TypedDataAndHash returns the hash to sign for the typed data, along with the
+// raw data it is the hash of.
+func TypedDataAndHash(typedData TypedData) ([]byte, string, error) {
+	if err := typedData.validate(); err != nil {
+		return nil, "", err
+	}
+	domainSeparator, err := typedData.HashStruct(DomainType, typedData.Domain.Map())
+	if err != nil {
+		return nil, "", err
+	}
+	typedDataHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return nil, "", err
+	}
+	rawData := make([]byte, 0, len(typedDataPrefix)+2*common.HashLength)
+	rawData = append(rawData, typedDataPrefix...)
+	rawData = append(rawData, domainSeparator...)
+	rawData = append(rawData, typedDataHash...)
+
+	return crypto.Keccak256(rawData), string(rawData), nil
+}
+
+// HashStruct returns the hash of a struct of the given type: the hash of its
+// type followed by its encoded fields.
+func (typedData *TypedData) HashStruct(primaryType string, data TypedDataMessage) (hexutil.Bytes, error) {
+	encodedData, err := typedData.EncodeData(primaryType, data, 1)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(encodedData), nil
+}
+
+// Dependencies returns the struct types the given type references, directly or
+// indirectly, including itself.
+func (typedData *TypedData) Dependencies(primaryType string, found []string) []string {
+	for _, dep := range found {
+		if dep == primaryType {
+			return found
+		}
+	}
+	if typedData.Types[primaryType] == nil {
+		return found
+	}
+	found = append(found, primaryType)
+	for _, field := range typedData.Types[primaryType] {
+		found = typedData.Dependencies(field.typeName(), found)
+	}
+	return found
+}
+
+// EncodeType returns the canonical encoding of a struct type, its own
+// definition followed by the definitions of its dependencies sorted by name:
+//
+//	Mail(Person from,Person to,string contents)Person(string name,address wallet)
+func (typedData *TypedData) EncodeType(primaryType string) hexutil.Bytes {
+	deps := typedData.Dependencies(primaryType, nil)
+	if len(deps) > 0 {
+		sort.Strings(deps[1:])
+	}
+	var buffer bytes.Buffer
+	for _, dep := range deps {
+		buffer.WriteString(dep)
+		buffer.WriteString("(")
+		for i, field := range typedData.Types[dep] {
+			if i > 0 {
+				buffer.WriteString(",")
+			}
+			buffer.WriteString(field.Type)
+			buffer.WriteString(" ")
+			buffer.WriteString(field.Name)
+		}
+		buffer.WriteString(")")
+	}
+	return buffer.Bytes()
+}
+
+// TypeHash returns the hash of the canonical encoding of a struct type.
+func (typedData *TypedData) TypeHash(primaryType string) hexutil.Bytes {
+	return crypto.Keccak256(typedData.EncodeType(primaryType))
+}
+
+// EncodeData returns the encoding of a struct of the given type, its type hash
+// followed by each field encoded into 32 bytes. Structs and arrays are encoded
+// as the hash of their encoding.
+func (typedData *TypedData) EncodeData(primaryType string, data TypedDataMessage, depth int) (hexutil.Bytes, error) {
+	if depth > maxDepth {
+		return nil, errMaxDepth
+	}
+	fields, ok := typedData.Types[primaryType]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", primaryType)
+	}
+	if len(data) > len(fields) {
+		return nil, fmt.Errorf("type %q has %d fields, message has %d", primaryType, len(fields), len(data))
+	}
+	buffer := bytes.NewBuffer(typedData.TypeHash(primaryType))
+
+	for _, field := range fields {
+		value := data[field.Name]
+		if field.isArray() {
+			items, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("field %q: expected array, got %T", field.Name, value)
+			}
+			var arrayBuffer bytes.Buffer
+			for i, item := range items {
+				encoded, err := typedData.encodeValue(field.typeName(), item, depth)
+				if err != nil {
+					return nil, fmt.Errorf("field %q[%d]: %v", field.Name, i, err)
+				}
+				arrayBuffer.Write(encoded)
+			}
+			buffer.Write(crypto.Keccak256(arrayBuffer.Bytes()))
+			continue
+		}
+		encoded, err := typedData.encodeValue(field.Type, value, depth)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %v", field.Name, err)
+		}
+		buffer.Write(encoded)
+	}
+	return buffer.Bytes(), nil
+}
+
+// encodeValue encodes a single value of the given non-array type into 32 bytes.
+func (typedData *TypedData) encodeValue(encType string, value interface{}, depth int) ([]byte, error) {
+	if _, ok := typedData.Types[encType]; ok {
+		mapValue, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected struct %s, got %T", encType, value)
+		}
+		encoded, err := typedData.EncodeData(encType, mapValue, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(encoded), nil
+	}
+	return encodePrimitiveValue(encType, value)
+}
+
+// encodePrimitiveValue encodes an atomic or dynamic value into 32 bytes.
+func encodePrimitiveValue(encType string, value interface{}) ([]byte, error) {
+	switch encType {
+	case "address":
+		str, ok := value.(string)
+		if !ok || !common.IsHexAddress(str) {
+			return nil, fmt.Errorf("invalid address %v", value)
+		}
+		return common.LeftPadBytes(common.FromHex(str), 32), nil
+
+	case "bool":
+		boolValue, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid bool %v", value)
+		}
+		if boolValue {
+			return math.PaddedBigBytes(common.Big1, 32), nil
+		}
+		return math.PaddedBigBytes(common.Big0, 32), nil
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid string %v", value)
+		}
+		return crypto.Keccak256([]byte(str)), nil
+
+	case "bytes":
+		bytesValue, err := parseBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(bytesValue), nil
+	}
+	match := sizedTypeRegexp.FindStringSubmatch(encType)
+	if match == nil {
+		return nil, fmt.Errorf("unsupported type %q", encType)
+	}
+	size, _ := strconv.Atoi(match[2])
+
+	if match[1] == "bytes" {
+		if size < 1 || size > 32 {
+			return nil, fmt.Errorf("invalid size of %s", encType)
+		}
+		bytesValue, err := parseBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(bytesValue) != size {
+			return nil, fmt.Errorf("%s value has %d bytes", encType, len(bytesValue))
+		}
+		return common.RightPadBytes(bytesValue, 32), nil
+	}
+	if size < 8 || size > 256 || size%8 != 0 {
+		return nil, fmt.Errorf("invalid size of %s", encType)
+	}
+	integer, err := parseInteger(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIntegerRange(integer, match[1] == "int", size); err != nil {
+		return nil, fmt.Errorf("%s: %v", encType, err)
+	}
+	return math.U256Bytes(new(big.Int).Set(integer)), nil
+}
+
+// parseBytes converts a hex string or a byte slice into bytes.
+func parseBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case hexutil.Bytes:
+		return v, nil
+	case string:
+		return hexutil.Decode(v)
+	}
+	return nil, fmt.Errorf("invalid bytes %v", value)
+}
+
+// parseInteger converts the integer representations found in decoded JSON and
+// Go values into a big integer. Floating point numbers are only accepted if
+// they are exact integers.
+func parseInteger(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		return v, nil
+	case *math.HexOrDecimal256:
+		return (*big.Int)(v), nil
+	case string:
+		integer, ok := new(big.Int), false
+		if strings.HasPrefix(v, "-") {
+			integer, ok = math.ParseBig256(v[1:])
+			if ok {
+				integer.Neg(integer)
+			}
+		} else {
+			integer, ok = math.ParseBig256(v)
+		}
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", v)
+		}
+		return integer, nil
+	case float64:
+		if v != float64(int64(v)) || v > 1<<53 || v < -(1<<53) {
+			return nil, fmt.Errorf("integer %v is not exact, pass it as a string", v)
+		}
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	}
+	if number, ok := value.(interface{ String() string }); ok {
+		// Numbers decoded with json.Decoder.UseNumber
+		return parseInteger(number.String())
+	}
+	return nil, fmt.Errorf("invalid integer %v", value)
+}
+
+// checkIntegerRange makes sure an integer fits into the given integer type.
+func checkIntegerRange(integer *big.Int, signed bool, size int) error {
+	if !signed {
+		if integer.Sign() < 0 || integer.BitLen() > size {
+			return fmt.Errorf("integer %v out of range", integer)
+		}
+		return nil
+	}
+	limit := new(big.Int).Lsh(common.Big1, uint(size-1))
+	if integer.Cmp(limit) >= 0 || integer.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return fmt.Errorf("integer %v out of range", integer)
+	}
+	return nil
+}
+
+// validate checks the type definitions and that the primary type is defined.
+func (typedData *TypedData) validate() error {
+	if _, ok := typedData.Types[DomainType]; !ok {
+		return fmt.Errorf("type %q not defined", DomainType)
+	}
+	if _, ok := typedData.Types[typedData.PrimaryType]; !ok {
+		return fmt.Errorf("primary type %q not defined", typedData.PrimaryType)
+	}
+	for typeName, fields := range typedData.Types {
+		if !typeNameRegexp.MatchString(typeName) {
+			return fmt.Errorf("invalid type name %q", typeName)
+		}
+		for _, field := range fields {
+			if field.Name == "" {
+				return fmt.Errorf("type %q has an unnamed field", typeName)
+			}
+			if field.Type == "" {
+				return fmt.Errorf("field %q of type %q has no type", field.Name, typeName)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package eip712
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/math"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+)
+
+// mailJSON is the example message of the EIP-712 specification.
+const mailJSON = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func mailTypedData(t *testing.T) TypedData {
+	var typedData TypedData
+	if err := json.Unmarshal([]byte(mailJSON), &typedData); err != nil {
+		t.Fatalf("failed to decode typed data: %v", err)
+	}
+	return typedData
+}
+
+func TestEncodeType(t *testing.T) {
+	typedData := mailTypedData(t)
+
+	want := "Mail(Person from,Person to,string contents)Person(string name,address wallet)"
+	if have := string(typedData.EncodeType("Mail")); have != want {
+		t.Errorf("encoded type mismatch: have %q, want %q", have, want)
+	}
+	want = "0xa0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2"
+	if have := typedData.TypeHash("Mail").String(); have != want {
+		t.Errorf("type hash mismatch: have %s, want %s", have, want)
+	}
+}
+
+func TestTypedDataHash(t *testing.T) {
+	typedData := mailTypedData(t)
+
+	domainSeparator, err := typedData.HashStruct(DomainType, typedData.Domain.Map())
+	if err != nil {
+		t.Fatalf("failed to hash domain: %v", err)
+	}
+	if have, want := domainSeparator.String(), "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"; have != want {
+		t.Errorf("domain separator mismatch: have %s, want %s", have, want)
+	}
+	messageHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		t.Fatalf("failed to hash message: %v", err)
+	}
+	if have, want := messageHash.String(), "0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e"; have != want {
+		t.Errorf("message hash mismatch: have %s, want %s", have, want)
+	}
+	hash, rawData, err := TypedDataAndHash(typedData)
+	if err != nil {
+		t.Fatalf("failed to hash typed data: %v", err)
+	}
+	if have, want := common.ToHex(hash), "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"; have != want {
+		t.Errorf("hash mismatch: have %s, want %s", have, want)
+	}
+	if !strings.HasPrefix(rawData, "\x19\x01") || len(rawData) != 66 {
+		t.Errorf("invalid raw data %x", rawData)
+	}
+}
+
+func TestSignTypedData(t *testing.T) {
+	typedData := mailTypedData(t)
+
+	// The key of the "Cow" account in the EIP-712 specification.
+	key, _ := crypto.HexToECDSA(common.Bytes2Hex(crypto.Keccak256([]byte("cow"))))
+	signature, err := SignTypedData(key, typedData)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	if signature[64] != 27 && signature[64] != 28 {
+		t.Errorf("invalid recovery id %d", signature[64])
+	}
+	signer, err := RecoverTypedData(typedData, signature)
+	if err != nil {
+		t.Fatalf("failed to recover: %v", err)
+	}
+	want := common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+	if !bytes.Equal(signer.Bytes(), want.Bytes()) {
+		t.Errorf("signer mismatch: have %x, want %x", signer, want)
+	}
+
+	// Any change of the message must change the signer.
+	typedData.Message["contents"] = "Hello, Alice!"
+	if signer, err = RecoverTypedData(typedData, signature); err == nil && bytes.Equal(signer.Bytes(), want.Bytes()) {
+		t.Errorf("tampered message recovered to the signer")
+	}
+}
+
+func TestEncodeIntegers(t *testing.T) {
+	tests := []struct {
+		typ   string
+		value interface{}
+		want  *big.Int
+		err   bool
+	}{
+		{typ: "uint8", value: float64(255), want: big.NewInt(255)},
+		{typ: "uint8", value: float64(256), err: true},
+		{typ: "uint8", value: float64(-1), err: true},
+		{typ: "uint256", value: "0x10", want: big.NewInt(16)},
+		{typ: "uint256", value: json.Number("1000000000000000000000"), want: math.MustParseBig256("1000000000000000000000")},
+		{typ: "uint256", value: float64(1.5), err: true},
+		{typ: "int8", value: "-128", want: big.NewInt(-128)},
+		{typ: "int8", value: "128", err: true},
+		{typ: "int256", value: big.NewInt(-1), want: big.NewInt(-1)},
+		{typ: "uint7", value: "1", err: true},
+		{typ: "uint", value: "1", err: true},
+	}
+	for i, test := range tests {
+		encoded, err := encodePrimitiveValue(test.typ, test.value)
+		if test.err {
+			if err == nil {
+				t.Errorf("test %d: expected error encoding %v as %s", i, test.value, test.typ)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: failed to encode %v as %s: %v", i, test.value, test.typ, err)
+			continue
+		}
+		if want := math.U256Bytes(new(big.Int).Set(test.want)); !bytes.Equal(encoded, want) {
+			t.Errorf("test %d: encoding mismatch: have %x, want %x", i, encoded, want)
+		}
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*TypedData)
+		err    string
+	}{
+		{
+			name:   "undefined primary type",
+			modify: func(td *TypedData) { td.PrimaryType = "Letter" },
+			err:    `primary type "Letter" not defined`,
+		},
+		{
+			name:   "invalid address",
+			modify: func(td *TypedData) { td.Message["to"].(map[string]interface{})["wallet"] = "0x1234" },
+			err:    `invalid address`,
+		},
+		{
+			name:   "extra field",
+			modify: func(td *TypedData) { td.Message["cc"] = "Alice" },
+			err:    `has 3 fields, message has 4`,
+		},
+		{
+			name: "cyclic type",
+			modify: func(td *TypedData) {
+				td.Types["Person"] = append(td.Types["Person"], Type{Name: "friend", Type: "Person"})
+				person := map[string]interface{}{"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"}
+				person["friend"] = person
+				td.Message["from"] = person
+			},
+			err: errMaxDepth.Error(),
+		},
+	}
+	for _, test := range tests {
+		typedData := mailTypedData(t)
+		test.modify(&typedData)
+		_, _, err := TypedDataAndHash(typedData)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: error mismatch: have %v, want %q", test.name, err, test.err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	typedData := mailTypedData(t)
+
+	nvts, err := typedData.Format()
+	if err != nil {
+		t.Fatalf("failed to format: %v", err)
+	}
+	var output string
+	for _, nvt := range nvts {
+		output += nvt.Pprint(0)
+	}
+	for _, want := range []string{
+		"EIP712Domain [domain]:",
+		fmt.Sprintf("  chainId [uint256]: %q", "1"),
+		"Mail [primary type]:",
+		"  from [Person]:",
+		fmt.Sprintf("    name [string]: %q", "Cow"),
+		fmt.Sprintf("  contents [string]: %q", "Hello, Bob!"),
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("formatted output misses %q:\n%s", want, output)
+		}
+	}
+}
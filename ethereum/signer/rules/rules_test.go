@@ -11,8 +11,11 @@ import (
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/accounts"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/dposclient"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
 )
 
 const JS = `
@@ -613,3 +616,80 @@ function ApproveSignData(r){
 		t.Fatalf("Expected approved")
 	}
 }
+
+const ExampleStakingPolicy = `
+	// Node IDs the account may delegate to.
+	var allowlist = {
+		"362003c50ed3a523cdede37a001803b8f0fed27cb402b3d6127a1a96661ec202318f68f4c76d9b0bfbabfd551a178d4335eaeaa9b7981a4df30dfc8c0bfe3384": true,
+	};
+	// The largest delegation approved automatically: 1000 PHC.
+	var maxDelegation = new BigNumber("1000e18");
+
+	function ApproveTx(r){
+		var call = r.inner_call;
+		if (!call) {
+			return "Manual";
+		}
+		if (call.method == "withdrewStaking") {
+			return "Reject";
+		}
+		if (call.method == "delegate") {
+			if (!allowlist[call.params.nodeId]) {
+				return "Reject";
+			}
+			if (new BigNumber(call.params.amount).greaterThan(maxDelegation)) {
+				return "Reject";
+			}
+			return "Approve";
+		}
+	}
+`
+
+func innerCallTx(t *testing.T, to common.Address, funcType uint16, params ...interface{}) *core2.SignTxRequest {
+	data, err := dposclient.EncodeFunc(funcType, params...)
+	if err != nil {
+		t.Fatalf("Failed to encode call %d: %v", funcType, err)
+	}
+	req := dummyTx(hexutil.Big{})
+	toAddr := common.NewMixedcaseAddress(to)
+	req.Transaction.To = &toAddr
+	req.Transaction.Data = (*hexutil.Bytes)(&data)
+	req.InnerCall = core2.DecodeInnerCall(&req.Transaction)
+	if req.InnerCall == nil {
+		t.Fatalf("Failed to decode call %d", funcType)
+	}
+	return req
+}
+
+func TestStakingPolicy(t *testing.T) {
+	r, err := NewRuleEvaluator(&alwaysDenyUI{}, storage.NewEphemeralStorage(), storage.NewEphemeralStorage())
+	if err != nil {
+		t.Fatalf("Couldn't create evaluator %v", err)
+	}
+	r.Init(ExampleStakingPolicy)
+
+	var (
+		allowed = discover.MustHexID("362003c50ed3a523cdede37a001803b8f0fed27cb402b3d6127a1a96661ec202318f68f4c76d9b0bfbabfd551a178d4335eaeaa9b7981a4df30dfc8c0bfe3384")
+		other   = discover.MustHexID("a6ef31a2006f55f5039e23ccccef343e735d56699bde947cfe253d441f5f291561640a8e2bbaf8a85a8a367b939efcef6f80ae28d2bd3d0b21bcb0f7a8ab6b2f")
+		phc     = big.NewInt(1e18)
+	)
+	tests := []struct {
+		name    string
+		req     *core2.SignTxRequest
+		approve bool
+	}{
+		{"small delegation", innerCallTx(t, vm.StakingContractAddr, dposclient.TxDelegate, uint16(0), allowed, new(big.Int).Mul(big.NewInt(10), phc)), true},
+		{"large delegation", innerCallTx(t, vm.StakingContractAddr, dposclient.TxDelegate, uint16(0), allowed, new(big.Int).Mul(big.NewInt(1001), phc)), false},
+		{"unknown node", innerCallTx(t, vm.StakingContractAddr, dposclient.TxDelegate, uint16(0), other, phc), false},
+		{"withdraw staking", innerCallTx(t, vm.StakingContractAddr, dposclient.TxWithdrewStaking, allowed), false},
+	}
+	for _, test := range tests {
+		resp, err := r.ApproveTx(test.req)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		}
+		if resp.Approved != test.approve {
+			t.Errorf("%s: approved %v, want %v", test.name, resp.Approved, test.approve)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	crand "crypto/rand"
 	mrand "math/rand"
+	"sort"
 	"testing"
 	"time"
 
@@ -133,6 +134,211 @@ func TestMissingKeyProof(t *testing.T) {
 	}
 }
 
+type entrySlice []*kv
+
+func (p entrySlice) Len() int           { return len(p) }
+func (p entrySlice) Less(i, j int) bool { return bytes.Compare(p[i].k, p[j].k) < 0 }
+func (p entrySlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// sortedEntries returns the entries of a random trie in key order.
+func sortedEntries(vals map[string]*kv) entrySlice {
+	var entries entrySlice
+	for _, kv := range vals {
+		entries = append(entries, kv)
+	}
+	sort.Sort(entries)
+	return entries
+}
+
+// rangeProof proves both edges of entries[start:end] and returns them along
+// with the keys and values of the range.
+func rangeProof(t *testing.T, trie *Trie, entries entrySlice, start, end int) (*memorydb.Database, [][]byte, [][]byte) {
+	proof := memorydb.New()
+	if err := trie.Prove(entries[start].k, 0, proof); err != nil {
+		t.Fatalf("failed to prove the first node %v", err)
+	}
+	if err := trie.Prove(entries[end-1].k, 0, proof); err != nil {
+		t.Fatalf("failed to prove the last node %v", err)
+	}
+	var keys, vals [][]byte
+	for i := start; i < end; i++ {
+		keys = append(keys, entries[i].k)
+		vals = append(vals, entries[i].v)
+	}
+	return proof, keys, vals
+}
+
+// TestRangeProof tests normal range proofs with both edge proofs present.
+func TestRangeProof(t *testing.T) {
+	trie, vals := randomTrie(4096)
+	entries := sortedEntries(vals)
+	root := trie.Hash()
+
+	for i := 0; i < 500; i++ {
+		start := mrand.Intn(len(entries))
+		end := mrand.Intn(len(entries)-start) + start + 1
+
+		proof, keys, vals := rangeProof(t, trie, entries, start, end)
+		hasMore, err := VerifyRangeProof(root, keys[0], keys[len(keys)-1], keys, vals, proof)
+		if err != nil {
+			t.Fatalf("case %d(%d->%d): expected no error, got %v", i, start, end-1, err)
+		}
+		if want := end < len(entries); hasMore != want {
+			t.Fatalf("case %d(%d->%d): more mismatch: have %v, want %v", i, start, end-1, hasMore, want)
+		}
+	}
+}
+
+// TestRangeProofWithNonExistentEdges tests range proofs whose edge proofs
+// prove the absence of keys just outside the range.
+func TestRangeProofWithNonExistentEdges(t *testing.T) {
+	trie, vals := randomTrie(4096)
+	entries := sortedEntries(vals)
+	root := trie.Hash()
+
+	for i := 0; i < 500; i++ {
+		start := mrand.Intn(len(entries)-2) + 1
+		end := mrand.Intn(len(entries)-start-1) + start + 1
+
+		first := decreaseKey(common.CopyBytes(entries[start].k))
+		if bytes.Equal(first, entries[start-1].k) {
+			continue
+		}
+		last := increaseKey(common.CopyBytes(entries[end-1].k))
+		if bytes.Equal(last, entries[end].k) {
+			continue
+		}
+		proof := memorydb.New()
+		if err := trie.Prove(first, 0, proof); err != nil {
+			t.Fatalf("failed to prove the first node %v", err)
+		}
+		if err := trie.Prove(last, 0, proof); err != nil {
+			t.Fatalf("failed to prove the last node %v", err)
+		}
+		var keys, vals [][]byte
+		for i := start; i < end; i++ {
+			keys = append(keys, entries[i].k)
+			vals = append(vals, entries[i].v)
+		}
+		if _, err := VerifyRangeProof(root, first, last, keys, vals, proof); err != nil {
+			t.Fatalf("case %d(%d->%d): expected no error, got %v", i, start, end-1, err)
+		}
+	}
+}
+
+// TestRangeProofSpecialCases tests the one element, all elements and zero
+// element cases of range proofs.
+func TestRangeProofSpecialCases(t *testing.T) {
+	trie, vals := randomTrie(4096)
+	entries := sortedEntries(vals)
+	root := trie.Hash()
+
+	// One element.
+	start := 1000
+	proof, keys, values := rangeProof(t, trie, entries, start, start+1)
+	if _, err := VerifyRangeProof(root, keys[0], keys[0], keys, values, proof); err != nil {
+		t.Fatalf("one element: expected no error, got %v", err)
+	}
+	// All elements, without any proof.
+	var allKeys, allVals [][]byte
+	for _, entry := range entries {
+		allKeys = append(allKeys, entry.k)
+		allVals = append(allVals, entry.v)
+	}
+	hasMore, err := VerifyRangeProof(root, nil, nil, allKeys, allVals, nil)
+	if err != nil {
+		t.Fatalf("all elements: expected no error, got %v", err)
+	}
+	if hasMore {
+		t.Fatal("all elements: more entries reported")
+	}
+	if _, err := VerifyRangeProof(root, nil, nil, allKeys[1:], allVals[1:], nil); err == nil {
+		t.Fatal("all elements: expected error for missing element")
+	}
+	// Zero elements right of the last key.
+	last := increaseKey(common.CopyBytes(entries[len(entries)-1].k))
+	proof = memorydb.New()
+	if err := trie.Prove(last, 0, proof); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyRangeProof(root, last, nil, nil, nil, proof); err != nil {
+		t.Fatalf("zero elements: expected no error, got %v", err)
+	}
+	// Zero elements with more entries on the right.
+	first := decreaseKey(common.CopyBytes(entries[start].k))
+	proof = memorydb.New()
+	if err := trie.Prove(first, 0, proof); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyRangeProof(root, first, nil, nil, nil, proof); err == nil {
+		t.Fatal("zero elements: expected error for hidden entries")
+	}
+}
+
+// TestBadRangeProof tests that tampered ranges are rejected.
+func TestBadRangeProof(t *testing.T) {
+	trie, vals := randomTrie(4096)
+	entries := sortedEntries(vals)
+	root := trie.Hash()
+
+	for i := 0; i < 500; i++ {
+		start := mrand.Intn(len(entries))
+		end := mrand.Intn(len(entries)-start) + start + 1
+		proof, keys, vals := rangeProof(t, trie, entries, start, end)
+
+		var first, last = keys[0], keys[len(keys)-1]
+		switch mrand.Intn(4) {
+		case 0:
+			// Modified value
+			index := mrand.Intn(end - start)
+			vals[index] = randBytes(20)
+		case 1:
+			// Gapped entry slice
+			if end-start < 3 {
+				continue
+			}
+			index := mrand.Intn(end-start-2) + 1
+			keys = append(keys[:index], keys[index+1:]...)
+			vals = append(vals[:index], vals[index+1:]...)
+		case 2:
+			// Out of order
+			if end-start < 2 {
+				continue
+			}
+			keys[0], keys[1] = keys[1], keys[0]
+		case 3:
+			// Set an empty value
+			index := mrand.Intn(end - start)
+			vals[index] = nil
+		}
+		if _, err := VerifyRangeProof(root, first, last, keys, vals, proof); err == nil {
+			t.Fatalf("case %d(%d->%d): expected error for tampered range", i, start, end-1)
+		}
+	}
+}
+
+// increaseKey returns the key following key in the keyspace.
+func increaseKey(key []byte) []byte {
+	for i := len(key) - 1; i >= 0; i-- {
+		key[i]++
+		if key[i] != 0x0 {
+			break
+		}
+	}
+	return key
+}
+
+// decreaseKey returns the key preceding key in the keyspace.
+func decreaseKey(key []byte) []byte {
+	for i := len(key) - 1; i >= 0; i-- {
+		key[i]--
+		if key[i] != 0xff {
+			break
+		}
+	}
+	return key
+}
+
 // mutateByte changes one byte in b.
 func mutateByte(b []byte) {
 	for r := mrand.Intn(len(b)); ; {
@@ -0,0 +1,144 @@
+package txmgr
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/rlp"
+)
+
+// TxStatus is the state of a managed transaction.
+type TxStatus uint8
+
+const (
+	// StatusPending means the transaction waits to be included in a block.
+	StatusPending TxStatus = iota
+	// StatusMined means a version of the transaction is included in the chain
+	// but is not deep enough yet.
+	StatusMined
+	// StatusConfirmed means the transaction is buried under the configured
+	// number of blocks.
+	StatusConfirmed
+	// StatusDropped means the nonce was used by a transaction the manager did
+	// not send.
+	StatusDropped
+)
+
+func (s TxStatus) String() string {
+	switch s {
+	case StatusPending:
+		return "pending"
+	case StatusMined:
+		return "mined"
+	case StatusConfirmed:
+		return "confirmed"
+	case StatusDropped:
+		return "dropped"
+	}
+	return "unknown"
+}
+
+// Final reports whether the status does not change anymore.
+func (s TxStatus) Final() bool {
+	return s == StatusConfirmed || s == StatusDropped
+}
+
+// TxRecord is the persisted state of a managed transaction. A transaction is
+// identified by its sender and nonce as replacing it changes its hash.
+type TxRecord struct {
+	From   common.Address
+	Nonce  uint64
+	Status TxStatus
+
+	// Tx is the latest version of the transaction, Hashes those of all the
+	// versions sent, any of which may end up in the chain.
+	Tx     *types.Transaction
+	Hashes []common.Hash
+	// SentAt is the unix time the latest version was sent at.
+	SentAt uint64
+
+	// TxHash, BlockNumber and BlockHash locate the version in the chain once
+	// mined.
+	TxHash      common.Hash
+	BlockNumber uint64
+	BlockHash   common.Hash
+}
+
+// GasPrice returns the gas price of the latest version.
+func (r *TxRecord) GasPrice() *big.Int {
+	return r.Tx.GasPrice()
+}
+
+var (
+	txRecordPrefix  = []byte("txmgr-tx-")    // txRecordPrefix + from + nonce -> TxRecord
+	nextNoncePrefix = []byte("txmgr-nonce-") // nextNoncePrefix + from -> next nonce
+)
+
+func txRecordKey(from common.Address, nonce uint64) []byte {
+	key := append(append([]byte{}, txRecordPrefix...), from.Bytes()...)
+	return append(key, encodeNonce(nonce)...)
+}
+
+func nextNonceKey(from common.Address) []byte {
+	return append(append([]byte{}, nextNoncePrefix...), from.Bytes()...)
+}
+
+func encodeNonce(nonce uint64) []byte {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, nonce)
+	return enc
+}
+
+func writeTxRecord(db ethdb.KeyValueWriter, r *TxRecord) error {
+	enc, err := rlp.EncodeToBytes(r)
+	if err != nil {
+		return err
+	}
+	return db.Put(txRecordKey(r.From, r.Nonce), enc)
+}
+
+func readTxRecord(db ethdb.KeyValueReader, from common.Address, nonce uint64) (*TxRecord, error) {
+	enc, err := db.Get(txRecordKey(from, nonce))
+	if err != nil {
+		return nil, err
+	}
+	r := new(TxRecord)
+	if err := rlp.DecodeBytes(enc, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func writeNextNonce(db ethdb.KeyValueWriter, from common.Address, nonce uint64) error {
+	return db.Put(nextNonceKey(from), encodeNonce(nonce))
+}
+
+func readNextNonce(db ethdb.KeyValueReader, from common.Address) (uint64, bool) {
+	enc, err := db.Get(nextNonceKey(from))
+	if err != nil || len(enc) != 8 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(enc), true
+}
+
+// readOpenTxRecords returns the records of the sender whose status is not final.
+func readOpenTxRecords(db ethdb.Iteratee, from common.Address) ([]*TxRecord, error) {
+	prefix := append(append([]byte{}, txRecordPrefix...), from.Bytes()...)
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	var records []*TxRecord
+	for it.Next() {
+		r := new(TxRecord)
+		if err := rlp.DecodeBytes(it.Value(), r); err != nil {
+			return nil, err
+		}
+		if !r.Status.Final() {
+			records = append(records, r)
+		}
+	}
+	return records, it.Error()
+}
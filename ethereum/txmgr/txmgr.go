@@ -0,0 +1,556 @@
+// Package txmgr sends transactions on behalf of a set of local keys.
+//
+// The TxManager hands out the nonces of its accounts locally, replaces the
+// transactions that do not get mined with a higher gas price, sends them again
+// in case the node lost them and tracks them until they are buried under the
+// configured number of blocks. Its state is kept in a database so that a
+// restarted service resumes where it stopped without reusing or skipping a
+// nonce.
+package txmgr
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	phoenixchain "github.com/PhoenixGlobal/Phoenix-Chain-SDK"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/event"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+)
+
+var (
+	// ErrUnknownAccount is returned for a sender the manager has no key of.
+	ErrUnknownAccount = errors.New("unknown account")
+	// ErrNoChainID is returned if the configuration lacks the chain id to sign with.
+	ErrNoChainID = errors.New("chain id not configured")
+)
+
+// Backend is the part of the node API the manager works with.
+type Backend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call phoenixchain.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Config contains the settings of a TxManager.
+type Config struct {
+	// ChainID is the id of the chain transactions are signed for.
+	ChainID *big.Int
+
+	// Confirmations is the number of blocks on top of the including block
+	// after which a transaction is confirmed.
+	Confirmations uint64
+
+	// PriceBump is the percentage by which the gas price of a replacement
+	// exceeds the replaced one. It must be at least the price bump of the
+	// node's transaction pool.
+	PriceBump uint64
+
+	// MaxGasPrice caps the gas price of replacements, nil means no cap.
+	MaxGasPrice *big.Int
+
+	// ReplaceAfter is the time a transaction may wait in the pool before it is
+	// replaced with a higher gas price.
+	ReplaceAfter time.Duration
+
+	// ResendInterval is the interval at which pending transactions are sent
+	// again, in case the node dropped them when it was restarted.
+	ResendInterval time.Duration
+
+	// PollInterval is the interval at which the chain is checked for the
+	// pending transactions.
+	PollInterval time.Duration
+}
+
+// DefaultConfig contains the default settings, the chain id has to be set.
+var DefaultConfig = Config{
+	Confirmations:  3,
+	PriceBump:      10,
+	ReplaceAfter:   time.Minute,
+	ResendInterval: 30 * time.Second,
+	PollInterval:   2 * time.Second,
+}
+
+func (c *Config) sanitize() {
+	if c.PriceBump < 1 {
+		log.Warn("Sanitizing invalid txmgr price bump", "provided", c.PriceBump, "updated", DefaultConfig.PriceBump)
+		c.PriceBump = DefaultConfig.PriceBump
+	}
+	if c.ReplaceAfter <= 0 {
+		c.ReplaceAfter = DefaultConfig.ReplaceAfter
+	}
+	if c.ResendInterval <= 0 {
+		c.ResendInterval = DefaultConfig.ResendInterval
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = DefaultConfig.PollInterval
+	}
+}
+
+// TxRequest describes a transaction to send.
+type TxRequest struct {
+	From     common.Address
+	To       *common.Address // nil means contract creation
+	Value    *big.Int
+	Data     []byte
+	GasLimit uint64   // estimated if 0
+	GasPrice *big.Int // suggested by the node if nil
+}
+
+// TxEvent is posted when a managed transaction is sent, replaced or changes
+// its status.
+type TxEvent struct {
+	Record *TxRecord
+}
+
+// TxManager sends and tracks the transactions of a set of accounts.
+type TxManager struct {
+	backend Backend
+	db      ethdb.KeyValueStore
+	config  Config
+	signer  types.Signer
+	keys    map[common.Address]*ecdsa.PrivateKey
+
+	mu         sync.Mutex
+	next       map[common.Address]uint64               // next nonce to hand out
+	open       map[common.Address]map[uint64]*TxRecord // transactions not final yet
+	lastResend time.Time
+
+	feed  event.Feed
+	scope event.SubscriptionScope
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates a manager sending with the given keys. The nonces and pending
+// transactions of the accounts are restored from db.
+func New(ctx context.Context, backend Backend, db ethdb.KeyValueStore, config Config, keys ...*ecdsa.PrivateKey) (*TxManager, error) {
+	if config.ChainID == nil {
+		return nil, ErrNoChainID
+	}
+	config.sanitize()
+	m := &TxManager{
+		backend: backend,
+		db:      db,
+		config:  config,
+		signer:  types.NewEIP155Signer(config.ChainID),
+		keys:    make(map[common.Address]*ecdsa.PrivateKey, len(keys)),
+		next:    make(map[common.Address]uint64, len(keys)),
+		open:    make(map[common.Address]map[uint64]*TxRecord, len(keys)),
+		quit:    make(chan struct{}),
+	}
+	for _, key := range keys {
+		from := crypto.PubkeyToAddress(key.PublicKey)
+		m.keys[from] = key
+
+		records, err := readOpenTxRecords(db, from)
+		if err != nil {
+			return nil, err
+		}
+		m.open[from] = make(map[uint64]*TxRecord, len(records))
+		for _, r := range records {
+			m.open[from][r.Nonce] = r
+		}
+		// Transactions sent with the key by someone else move the nonce forward.
+		next, _ := readNextNonce(db, from)
+		pending, err := backend.PendingNonceAt(ctx, from)
+		if err != nil {
+			return nil, err
+		}
+		if pending > next {
+			next = pending
+		}
+		m.next[from] = next
+	}
+	return m, nil
+}
+
+// Start starts tracking the pending transactions. All of them are sent again
+// first, in case the node was restarted in the meantime.
+func (m *TxManager) Start() {
+	m.wg.Add(1)
+	go m.loop()
+}
+
+// Stop stops tracking the transactions.
+func (m *TxManager) Stop() {
+	close(m.quit)
+	m.wg.Wait()
+	m.scope.Close()
+}
+
+// Accounts returns the addresses of the managed keys.
+func (m *TxManager) Accounts() []common.Address {
+	accounts := make([]common.Address, 0, len(m.keys))
+	for from := range m.keys {
+		accounts = append(accounts, from)
+	}
+	return accounts
+}
+
+// SubscribeTxEvent registers a subscription of TxEvent.
+func (m *TxManager) SubscribeTxEvent(ch chan<- TxEvent) event.Subscription {
+	return m.scope.Track(m.feed.Subscribe(ch))
+}
+
+// Send assigns the next nonce of the sender to the transaction, signs it and
+// sends it. The returned record identifies the transaction by sender and
+// nonce, the hash changes if it gets replaced.
+func (m *TxManager) Send(ctx context.Context, req TxRequest) (*TxRecord, error) {
+	key, ok := m.keys[req.From]
+	if !ok {
+		return nil, ErrUnknownAccount
+	}
+	value := req.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	gasPrice := req.GasPrice
+	if gasPrice == nil {
+		var err error
+		if gasPrice, err = m.backend.SuggestGasPrice(ctx); err != nil {
+			return nil, fmt.Errorf("failed to suggest gas price: %v", err)
+		}
+	}
+	gasLimit := req.GasLimit
+	if gasLimit == 0 {
+		msg := phoenixchain.CallMsg{From: req.From, To: req.To, GasPrice: gasPrice, Value: value, Data: req.Data}
+		var err error
+		if gasLimit, err = m.backend.EstimateGas(ctx, msg); err != nil {
+			return nil, fmt.Errorf("failed to estimate gas needed: %v", err)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.sendNext(ctx, key, req.To, value, gasLimit, gasPrice, req.Data)
+	if err != nil && isNonceTooLow(err) {
+		// The nonce was used outside of the manager, retry with the node's one.
+		pending, perr := m.backend.PendingNonceAt(ctx, req.From)
+		if perr != nil {
+			return nil, err
+		}
+		m.next[req.From] = pending
+		r, err = m.sendNext(ctx, key, req.To, value, gasLimit, gasPrice, req.Data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.copy(), nil
+}
+
+// sendNext sends a new transaction with the next nonce of the account. The
+// nonce is only handed out for good if the node accepts the transaction.
+func (m *TxManager) sendNext(ctx context.Context, key *ecdsa.PrivateKey, to *common.Address, value *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) (*TxRecord, error) {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	nonce := m.next[from]
+	if err := writeNextNonce(m.db, from, nonce+1); err != nil {
+		return nil, err
+	}
+	r, err := m.send(ctx, key, nonce, to, value, gasLimit, gasPrice, data)
+	if err != nil {
+		writeNextNonce(m.db, from, nonce)
+		return nil, err
+	}
+	m.next[from] = nonce + 1
+	return r, nil
+}
+
+// send signs and sends a new transaction with the given nonce and starts
+// tracking it.
+func (m *TxManager) send(ctx context.Context, key *ecdsa.PrivateKey, nonce uint64, to *common.Address, value *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) (*TxRecord, error) {
+	var tx *types.Transaction
+	if to == nil {
+		tx = types.NewContractCreation(nonce, value, gasLimit, gasPrice, data)
+	} else {
+		tx = types.NewTransaction(nonce, *to, value, gasLimit, gasPrice, data)
+	}
+	signed, err := types.SignTx(tx, m.signer, key)
+	if err != nil {
+		return nil, err
+	}
+	r := &TxRecord{
+		From:   crypto.PubkeyToAddress(key.PublicKey),
+		Nonce:  nonce,
+		Status: StatusPending,
+		Tx:     signed,
+		Hashes: []common.Hash{signed.Hash()},
+		SentAt: uint64(time.Now().Unix()),
+	}
+	// Persist before sending, a restarted manager must know about the
+	// transaction even if it crashed right after the node accepted it.
+	if err := writeTxRecord(m.db, r); err != nil {
+		return nil, err
+	}
+	if err := m.backend.SendTransaction(ctx, signed); err != nil && !isKnownTx(err) {
+		m.db.Delete(txRecordKey(r.From, nonce))
+		return nil, err
+	}
+	m.open[r.From][nonce] = r
+	log.Debug("Sent managed transaction", "from", r.From, "nonce", nonce, "hash", signed.Hash(), "gasPrice", gasPrice)
+	m.feed.Send(TxEvent{Record: r.copy()})
+	return r, nil
+}
+
+// Status returns the record of the transaction of the sender with the given
+// nonce.
+func (m *TxManager) Status(from common.Address, nonce uint64) (*TxRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r, ok := m.open[from][nonce]; ok {
+		return r.copy(), nil
+	}
+	return readTxRecord(m.db, from, nonce)
+}
+
+// WaitConfirmed waits until the transaction of the sender with the given
+// nonce is confirmed or dropped. It stops waiting when ctx is canceled.
+func (m *TxManager) WaitConfirmed(ctx context.Context, from common.Address, nonce uint64) (*TxRecord, error) {
+	ticker := time.NewTicker(m.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r, err := m.Status(from, nonce)
+		if err != nil {
+			return nil, err
+		}
+		if r.Status.Final() {
+			return r, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *TxManager) loop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.config.PollInterval)
+	defer ticker.Stop()
+
+	m.update()
+	for {
+		select {
+		case <-ticker.C:
+			m.update()
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// update checks the pending transactions of all accounts against the chain.
+func (m *TxManager) update() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.config.PollInterval*5)
+	defer cancel()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	head, err := m.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		log.Warn("Failed to retrieve the chain head", "err", err)
+		return
+	}
+	resend := time.Since(m.lastResend) >= m.config.ResendInterval
+	for from := range m.keys {
+		if err := m.updateAccount(ctx, from, head.Number.Uint64(), resend); err != nil {
+			log.Warn("Failed to update managed transactions", "from", from, "err", err)
+		}
+	}
+	if resend {
+		m.lastResend = time.Now()
+	}
+}
+
+func (m *TxManager) updateAccount(ctx context.Context, from common.Address, head uint64, resend bool) error {
+	latest, err := m.backend.NonceAt(ctx, from, nil)
+	if err != nil {
+		return err
+	}
+	if latest > m.next[from] {
+		log.Warn("Account used outside of the transaction manager", "from", from, "nonce", latest, "next", m.next[from])
+		m.next[from] = latest
+		if err := writeNextNonce(m.db, from, latest); err != nil {
+			return err
+		}
+	}
+
+	nonces := make([]uint64, 0, len(m.open[from]))
+	for nonce := range m.open[from] {
+		nonces = append(nonces, nonce)
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+
+	for _, nonce := range nonces {
+		r := m.open[from][nonce]
+		changed, err := m.track(ctx, r, head, latest)
+		if err != nil {
+			return err
+		}
+		if r.Status == StatusPending {
+			switch {
+			case time.Since(time.Unix(int64(r.SentAt), 0)) >= m.config.ReplaceAfter:
+				if err := m.replace(ctx, r); err != nil {
+					log.Warn("Failed to replace managed transaction", "from", from, "nonce", nonce, "err", err)
+				}
+			case resend:
+				if err := m.backend.SendTransaction(ctx, r.Tx); err != nil && !isKnownTx(err) {
+					log.Debug("Failed to resend managed transaction", "from", from, "nonce", nonce, "err", err)
+				}
+			}
+		}
+		if changed {
+			if err := writeTxRecord(m.db, r); err != nil {
+				return err
+			}
+			if r.Status.Final() {
+				delete(m.open[from], nonce)
+			}
+			m.feed.Send(TxEvent{Record: r.copy()})
+		}
+	}
+	return m.fillGaps(ctx, from, latest)
+}
+
+// track updates the status of the record, it reports whether it changed.
+func (m *TxManager) track(ctx context.Context, r *TxRecord, head, latest uint64) (bool, error) {
+	var receipt *types.Receipt
+	for i := len(r.Hashes) - 1; i >= 0 && receipt == nil; i-- {
+		var err error
+		receipt, err = m.backend.TransactionReceipt(ctx, r.Hashes[i])
+		if err != nil && err != phoenixchain.NotFound {
+			return false, err
+		}
+	}
+	if receipt == nil {
+		switch {
+		case r.Status == StatusMined:
+			// The including block was reorganised away.
+			r.Status, r.TxHash, r.BlockNumber, r.BlockHash = StatusPending, common.Hash{}, 0, common.Hash{}
+			return true, nil
+		case latest > r.Nonce:
+			log.Warn("Nonce of managed transaction used by another one", "from", r.From, "nonce", r.Nonce)
+			r.Status = StatusDropped
+			return true, nil
+		}
+		return false, nil
+	}
+
+	changed := false
+	if r.Status == StatusPending || r.BlockHash != receipt.BlockHash {
+		r.Status = StatusMined
+		r.TxHash, r.BlockNumber, r.BlockHash = receipt.TxHash, receipt.BlockNumber.Uint64(), receipt.BlockHash
+		changed = true
+	}
+	if head >= r.BlockNumber+m.config.Confirmations {
+		r.Status = StatusConfirmed
+		changed = true
+	}
+	return changed, nil
+}
+
+// replace sends the transaction again with a gas price high enough for the
+// pool to accept it as replacement.
+func (m *TxManager) replace(ctx context.Context, r *TxRecord) error {
+	old := r.GasPrice()
+	if m.config.MaxGasPrice != nil && old.Cmp(m.config.MaxGasPrice) >= 0 {
+		// The price can't be raised anymore, keep the transaction in the pool.
+		r.SentAt = uint64(time.Now().Unix())
+		return m.backend.SendTransaction(ctx, r.Tx)
+	}
+	price := new(big.Int).Mul(old, big.NewInt(int64(100+m.config.PriceBump)))
+	price.Div(price, big.NewInt(100))
+	if price.Cmp(old) <= 0 {
+		price.Add(old, common.Big1)
+	}
+	if suggested, err := m.backend.SuggestGasPrice(ctx); err == nil && suggested.Cmp(price) > 0 {
+		price = suggested
+	}
+	if m.config.MaxGasPrice != nil && price.Cmp(m.config.MaxGasPrice) > 0 {
+		price = new(big.Int).Set(m.config.MaxGasPrice)
+	}
+
+	var tx *types.Transaction
+	if to := r.Tx.To(); to == nil {
+		tx = types.NewContractCreation(r.Nonce, r.Tx.Value(), r.Tx.Gas(), price, r.Tx.Data())
+	} else {
+		tx = types.NewTransaction(r.Nonce, *to, r.Tx.Value(), r.Tx.Gas(), price, r.Tx.Data())
+	}
+	signed, err := types.SignTx(tx, m.signer, m.keys[r.From])
+	if err != nil {
+		return err
+	}
+	if err := m.backend.SendTransaction(ctx, signed); err != nil && !isKnownTx(err) {
+		return err
+	}
+	r.Tx = signed
+	r.Hashes = append(r.Hashes, signed.Hash())
+	r.SentAt = uint64(time.Now().Unix())
+	log.Debug("Replaced managed transaction", "from", r.From, "nonce", r.Nonce, "hash", signed.Hash(), "gasPrice", price)
+	if err := writeTxRecord(m.db, r); err != nil {
+		return err
+	}
+	m.feed.Send(TxEvent{Record: r.copy()})
+	return nil
+}
+
+// fillGaps sends an empty transfer for every nonce below the next one that
+// neither the chain nor the manager have a transaction for, since the later
+// transactions could never be mined otherwise.
+func (m *TxManager) fillGaps(ctx context.Context, from common.Address, latest uint64) error {
+	for nonce := latest; nonce < m.next[from]; nonce++ {
+		if _, ok := m.open[from][nonce]; ok {
+			continue
+		}
+		price, err := m.backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return err
+		}
+		log.Warn("Filling nonce gap of managed account", "from", from, "nonce", nonce)
+		if _, err := m.send(ctx, m.keys[from], nonce, &from, new(big.Int), configs.TxGas, price, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TxRecord) copy() *TxRecord {
+	cpy := *r
+	cpy.Hashes = append([]common.Hash(nil), r.Hashes...)
+	return &cpy
+}
+
+// isKnownTx reports whether the node rejected a transaction because it has it
+// already. The error crosses RPC as a message only.
+func isKnownTx(err error) bool {
+	msg := err.Error()
+	return strings.HasPrefix(msg, "already known") || strings.HasPrefix(msg, "known transaction")
+}
+
+// isNonceTooLow reports whether the node rejected a transaction because its
+// nonce is used already.
+func isNonceTooLow(err error) bool {
+	return strings.HasPrefix(err.Error(), "nonce too low")
+}
@@ -0,0 +1,195 @@
+package txmgr
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	phoenixchain "github.com/PhoenixGlobal/Phoenix-Chain-SDK"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/ethdb/memorydb"
+)
+
+// testBackend is a chain whose blocks are mined by hand.
+type testBackend struct {
+	mu       sync.Mutex
+	head     uint64
+	nonce    uint64 // nonce of the account in the latest block
+	pool     map[uint64]*types.Transaction
+	receipts map[common.Hash]*types.Receipt
+}
+
+func newTestBackend() *testBackend {
+	return &testBackend{
+		pool:     make(map[uint64]*types.Transaction),
+		receipts: make(map[common.Hash]*types.Receipt),
+	}
+}
+
+func (b *testBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	nonce := b.nonce
+	for b.pool[nonce] != nil {
+		nonce++
+	}
+	return nonce, nil
+}
+
+func (b *testBackend) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nonce, nil
+}
+
+func (b *testBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(100), nil
+}
+
+func (b *testBackend) EstimateGas(ctx context.Context, call phoenixchain.CallMsg) (uint64, error) {
+	return 21000, nil
+}
+
+func (b *testBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pool[tx.Nonce()] = tx
+	return nil
+}
+
+func (b *testBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if r, ok := b.receipts[txHash]; ok {
+		return r, nil
+	}
+	return nil, phoenixchain.NotFound
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return &types.Header{Number: new(big.Int).SetUint64(b.head)}, nil
+}
+
+// mine includes the pooled transactions in a new block.
+func (b *testBackend) mine() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.head++
+	for tx := b.pool[b.nonce]; tx != nil; tx = b.pool[b.nonce] {
+		b.receipts[tx.Hash()] = &types.Receipt{
+			TxHash:      tx.Hash(),
+			BlockNumber: new(big.Int).SetUint64(b.head),
+			BlockHash:   common.BigToHash(new(big.Int).SetUint64(b.head)),
+		}
+		delete(b.pool, b.nonce)
+		b.nonce++
+	}
+}
+
+func newTestManager(t *testing.T, backend Backend, db *memorydb.Database) (*TxManager, common.Address) {
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	config := DefaultConfig
+	config.ChainID = big.NewInt(100)
+	m, err := New(context.Background(), backend, db, config, key)
+	assert.Nil(t, err)
+	return m, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func TestSendAndConfirm(t *testing.T) {
+	backend, db := newTestBackend(), memorydb.New()
+	m, from := newTestManager(t, backend, db)
+	to := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	for i := uint64(0); i < 2; i++ {
+		r, err := m.Send(context.Background(), TxRequest{From: from, To: &to, Value: big.NewInt(1)})
+		assert.Nil(t, err)
+		assert.Equal(t, i, r.Nonce)
+	}
+	_, err := m.Send(context.Background(), TxRequest{From: to, To: &to})
+	assert.Equal(t, ErrUnknownAccount, err)
+
+	backend.mine()
+	m.update()
+	r, _ := m.Status(from, 1)
+	assert.Equal(t, StatusMined, r.Status)
+	assert.Equal(t, uint64(1), r.BlockNumber)
+
+	for i := uint64(0); i < m.config.Confirmations; i++ {
+		backend.mine()
+	}
+	m.update()
+	r, _ = m.Status(from, 1)
+	assert.Equal(t, StatusConfirmed, r.Status)
+	assert.Len(t, m.open[from], 0)
+
+	// A restarted manager continues with the next nonce.
+	m, _ = newTestManager(t, backend, db)
+	r, err = m.Send(context.Background(), TxRequest{From: from, To: &to})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), r.Nonce)
+}
+
+func TestReplaceAndResume(t *testing.T) {
+	backend, db := newTestBackend(), memorydb.New()
+	m, from := newTestManager(t, backend, db)
+	to := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	r, err := m.Send(context.Background(), TxRequest{From: from, To: &to})
+	assert.Nil(t, err)
+	first := r.Tx.Hash()
+
+	// A transaction stuck in the pool is replaced with a bumped gas price.
+	m.open[from][0].SentAt = uint64(time.Now().Add(-2 * m.config.ReplaceAfter).Unix())
+	m.update()
+	r, _ = m.Status(from, 0)
+	assert.Len(t, r.Hashes, 2)
+	assert.Equal(t, int64(110), r.GasPrice().Int64())
+	assert.Equal(t, r.Tx.Hash(), backend.pool[0].Hash())
+
+	// After a restart the node lost the transaction, the manager sends it again.
+	delete(backend.pool, 0)
+	m, _ = newTestManager(t, backend, db)
+	assert.Len(t, m.open[from], 1)
+	m.update()
+	assert.NotNil(t, backend.pool[0])
+
+	// The replaced version may still be the one that gets mined.
+	backend.pool[0], _ = types.SignTx(
+		types.NewTransaction(0, to, new(big.Int), 21000, big.NewInt(100), nil),
+		m.signer, m.keys[from])
+	assert.Equal(t, first, backend.pool[0].Hash())
+	backend.mine()
+	m.update()
+	r, _ = m.Status(from, 0)
+	assert.Equal(t, StatusMined, r.Status)
+	assert.Equal(t, first, r.TxHash)
+}
+
+func TestFillNonceGap(t *testing.T) {
+	backend, db := newTestBackend(), memorydb.New()
+	_, from := newTestManager(t, backend, db)
+
+	// Nonces 0 and 1 were handed out but their transactions got lost.
+	assert.Nil(t, writeNextNonce(db, from, 2))
+	m, _ := newTestManager(t, backend, db)
+	m.update()
+	assert.Len(t, backend.pool, 2)
+	assert.Equal(t, from, *backend.pool[1].To())
+
+	backend.mine()
+	m.update()
+	next, _ := readNextNonce(db, from)
+	assert.Equal(t, uint64(2), next)
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		r, _ := m.Status(from, nonce)
+		assert.Equal(t, StatusMined, r.Status)
+	}
+}
@@ -103,6 +103,11 @@ type CallMsg struct {
 	GasPrice *big.Int        // wei <-> gas exchange ratio
 	Value    *big.Int        // amount of wei sent along with the call
 	Data     []byte          // input data, usually an ABI-encoded contract method invocation
+
+	GasFeeCap *big.Int // max fee per gas of a dynamic fee call, exclusive with GasPrice
+	GasTipCap *big.Int // max priority fee per gas of a dynamic fee call, exclusive with GasPrice
+
+	AccessList types.AccessList // addresses and storage slots the call is known to access
 }
 
 // A ContractCaller provides contract calls, essentially transactions that are executed by
@@ -9,6 +9,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/consensus"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/bloombits"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/state"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/vm"
@@ -27,6 +28,7 @@ type Backend interface {
 	Engine() consensus.Engine
 	ProtocolVersion() int
 	SuggestPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
 	ChainDb() ethdb.Database
 	EventMux() *event.TypeMux
 	AccountManager() *accounts.Manager
@@ -40,7 +42,7 @@ type Backend interface {
 	StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error)
 	GetBlock(ctx context.Context, blockHash common.Hash) (*types.Block, error)
 	GetReceipts(ctx context.Context, blockHash common.Hash) (types.Receipts, error)
-	GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header) (*vm.EVM, func() error, error)
+	GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config) (*vm.EVM, func() error, error)
 	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
 	SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription
@@ -55,6 +57,14 @@ type Backend interface {
 	TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
 
+	// Filter API
+	HeaderByHash(ctx context.Context, blockHash common.Hash) (*types.Header, error)
+	GetLogs(ctx context.Context, blockHash common.Hash) ([][]*types.Log, error)
+	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
+	SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription
+	BloomStatus() (uint64, uint64)
+	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
+
 	ChainConfig() *configs.ChainConfig
 	CurrentBlock() *types.Block
 
@@ -365,6 +365,12 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
 		}),
+		new web3._extend.Method({
+			name: 'signTypedData',
+			call: 'phoenixchain_signTypedData',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
 		new web3._extend.Method({
 			name: 'resend',
 			call: 'phoenixchain_resend',
@@ -401,6 +407,16 @@ web3._extend({
 			call: 'phoenixchain_getPrepareQC',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'consensusHealth',
+			call: 'phoenixchain_consensusHealth',
+		}),
+		new web3._extend.Method({
+			name: 'createAccessList',
+			call: 'phoenixchain_createAccessList',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter],
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -472,11 +488,11 @@ web3._extend({
 			call: 'personal_openWallet',
 			params: 2
 		}),
-		//new web3._extend.Method({
-		//	name: 'deriveAccount',
-		//	call: 'personal_deriveAccount',
-		//	params: 3
-		//}),
+		new web3._extend.Method({
+			name: 'deriveAccount',
+			call: 'personal_deriveAccount',
+			params: 3
+		}),
 		new web3._extend.Method({
 			name: 'signTransaction',
 			call: 'personal_signTransaction',
@@ -1,4 +1,6 @@
-// +build gofuzzpackage bitutil
+// +build gofuzz
+
+package bitutil
 
 import "bytes"
 
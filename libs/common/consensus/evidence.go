@@ -1,8 +1,11 @@
 package consensus
 
 import (
-	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
+	"encoding/json"
+
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/hexutil"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/crypto/bls"
 )
 
 type EvidenceType uint8
@@ -38,3 +41,49 @@ type EvidencePool interface {
 	Clear(epoch uint64, blockNumber uint64)
 	Close()
 }
+
+// EvidenceFilter selects duplicate evidence by type, node, epoch and block range.
+// Unset fields match any evidence.
+type EvidenceFilter struct {
+	Type      EvidenceType     `json:"type"`
+	NodeID    *discover.NodeID `json:"nodeId"`
+	Epoch     *uint64          `json:"epoch"`
+	FromBlock *uint64          `json:"fromBlock"`
+	ToBlock   *uint64          `json:"toBlock"`
+}
+
+// Match reports whether the evidence satisfies the filter.
+func (f *EvidenceFilter) Match(e Evidence) bool {
+	if f == nil {
+		return true
+	}
+	if f.Type != 0 && f.Type != e.Type() {
+		return false
+	}
+	if f.NodeID != nil && *f.NodeID != e.NodeID() {
+		return false
+	}
+	if f.Epoch != nil && *f.Epoch != e.Epoch() {
+		return false
+	}
+	if f.FromBlock != nil && e.BlockNumber() < *f.FromBlock {
+		return false
+	}
+	if f.ToBlock != nil && e.BlockNumber() > *f.ToBlock {
+		return false
+	}
+	return true
+}
+
+// EvidenceInfo is the representation of a duplicate evidence served over RPC.
+// Evidence holds the JSON encoding of the conflicting messages and the validator
+// that signed them, the pbft evidence package decodes it into its concrete type.
+type EvidenceInfo struct {
+	Type        EvidenceType    `json:"type"`
+	Hash        hexutil.Bytes   `json:"hash"`
+	Epoch       uint64          `json:"epoch"`
+	ViewNumber  uint64          `json:"viewNumber"`
+	BlockNumber uint64          `json:"blockNumber"`
+	NodeID      discover.NodeID `json:"nodeId"`
+	Evidence    json.RawMessage `json:"evidence"`
+}
@@ -0,0 +1,28 @@
+package consensus
+
+import (
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+)
+
+// ValidatorHealth summarizes the participation of a validator.
+type ValidatorHealth struct {
+	NodeID          discover.NodeID `json:"nodeId"`
+	Validator       bool            `json:"validator"` // Whether it is a validator of the current epoch
+	PrepareVotes    uint64          `json:"prepareVotes"`
+	PreCommits      uint64          `json:"preCommits"`
+	Proposals       uint64          `json:"proposals"`
+	MissedProposals uint64          `json:"missedProposals"`
+	ViewChanges     uint64          `json:"viewChanges"`
+	AvgTimeToQC     uint64          `json:"avgTimeToQC"` // Of the blocks proposed, in milliseconds
+}
+
+// HealthReport summarizes the participation of the validators in the window
+// of committed blocks tracked by the pbft health tracker.
+type HealthReport struct {
+	From        uint64             `json:"from"`
+	To          uint64             `json:"to"`
+	Blocks      uint64             `json:"blocks"`
+	ViewChanges uint64             `json:"viewChanges"`
+	AvgTimeToQC uint64             `json:"avgTimeToQC"` // In milliseconds
+	Validators  []*ValidatorHealth `json:"validators"`
+}
@@ -1,4 +1,6 @@
-// +build freebsdpackage fdlimit
+// +build freebsd
+
+package fdlimit
 
 import "syscall"
 
@@ -4,6 +4,7 @@
 
 //go:build gofuzz
 // +build gofuzz
+
 package json
 
 import (
@@ -218,6 +218,8 @@ type MockStateDB struct {
 	logSize      uint
 	Logs         map[common.Hash][]*types.Log
 	Journal      *journal
+
+	accessList map[common.Address]map[common.Hash]struct{}
 }
 
 func (s *MockStateDB) Prepare(thash, bhash common.Hash, ti int) {
@@ -445,6 +447,50 @@ func (s *MockStateDB) ForEachStorage(addr common.Address, fn func([]byte, []byte
 	}
 }
 
+func (s *MockStateDB) PrepareAccessList(sender common.Address, dest *common.Address, precompiles []common.Address, txAccesses types.AccessList) {
+	s.accessList = make(map[common.Address]map[common.Hash]struct{})
+	s.AddAddressToAccessList(sender)
+	if dest != nil {
+		s.AddAddressToAccessList(*dest)
+	}
+	for _, addr := range precompiles {
+		s.AddAddressToAccessList(addr)
+	}
+	for _, el := range txAccesses {
+		for _, key := range el.StorageKeys {
+			s.AddSlotToAccessList(el.Address, key)
+		}
+		s.AddAddressToAccessList(el.Address)
+	}
+}
+
+func (s *MockStateDB) AddressInAccessList(addr common.Address) bool {
+	_, ok := s.accessList[addr]
+	return ok
+}
+
+func (s *MockStateDB) SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool) {
+	slots, addressOk := s.accessList[addr]
+	if addressOk {
+		_, slotOk = slots[slot]
+	}
+	return addressOk, slotOk
+}
+
+func (s *MockStateDB) AddAddressToAccessList(addr common.Address) {
+	if s.accessList == nil {
+		s.accessList = make(map[common.Address]map[common.Hash]struct{})
+	}
+	if _, ok := s.accessList[addr]; !ok {
+		s.accessList[addr] = make(map[common.Hash]struct{})
+	}
+}
+
+func (s *MockStateDB) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	s.AddAddressToAccessList(addr)
+	s.accessList[addr][slot] = struct{}{}
+}
+
 func (s *MockStateDB) TxHash() common.Hash {
 	return s.Thash
 }
@@ -2,7 +2,9 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build !go1.7,amd64,!gccgo,!appenginepackage blake2b
+// +build !go1.7,amd64,!gccgo,!appengine
+
+package blake2b
 
 import "golang.org/x/sys/cpu"
 
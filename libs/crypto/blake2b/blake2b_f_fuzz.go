@@ -1,4 +1,6 @@
-// +build gofuzzpackage blake2b
+// +build gofuzz
+
+package blake2b
 
 import (
 	"encoding/binary"
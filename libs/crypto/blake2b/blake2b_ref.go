@@ -2,7 +2,9 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build !amd64 appengine gccgopackage blake2b
+// +build !amd64 appengine gccgo
+
+package blake2b
 
 func f(h *[8]uint64, m *[16]uint64, c0, c1 uint64, flag uint64, rounds uint64) {
 	fGeneric(h, m, c0, c1, flag, rounds)
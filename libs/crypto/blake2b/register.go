@@ -2,7 +2,9 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build go1.9package blake2b
+// +build go1.9
+
+package blake2b
 
 import (
 	"crypto"
@@ -2,7 +2,9 @@
 // Use of this source code is governed by a BSD-style license that can be found
 // in the LICENSE file.
 
-// +build gofuzzpackage bn256
+// +build gofuzz
+
+package bn256
 
 import (
 	"bytes"
@@ -1,4 +1,6 @@
-// +build !amd64,!arm64 genericpackage bn256
+// +build !amd64,!arm64 generic
+
+package bn256
 
 func gfpCarry(a *gfP, head uint64) {
 	b := &gfP{}
@@ -3,7 +3,9 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build appenginepackage term
+// +build appengine
+
+package term
 
 // IsTty always returns false on AppEngine.
 func IsTty(fd uintptr) bool {
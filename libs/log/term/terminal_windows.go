@@ -3,7 +3,9 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build windowspackage term
+// +build windows
+
+package term
 
 import (
 	"syscall"
@@ -1,4 +1,5 @@
 // +build ios
+
 package metrics
 
 // ReadCPUStats retrieves the current CPU stats. Internally this uses `gosigar`,
@@ -1,4 +1,5 @@
 // +build !cgo appengine
+
 package metrics
 
 //func numCgoCall() int64 {
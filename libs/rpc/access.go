@@ -0,0 +1,27 @@
+package rpc
+
+import "context"
+
+// AccessControl decides which methods the caller of a connection may invoke.
+// Implementations are installed by the transport, e.g. from the credentials
+// presented with an HTTP request, and are consulted before every call.
+type AccessControl interface {
+	// Allowed reports whether the method, in namespace_method form, may be
+	// called. The implementation is responsible for auditing denials.
+	Allowed(method string) bool
+}
+
+type accessControlKey struct{}
+
+// WithAccessControl returns a copy of the context restricting the methods the
+// RPC server serves to requests carrying it.
+func WithAccessControl(ctx context.Context, ac AccessControl) context.Context {
+	return context.WithValue(ctx, accessControlKey{}, ac)
+}
+
+// AccessControlFromContext retrieves the access control installed by
+// WithAccessControl, if any.
+func AccessControlFromContext(ctx context.Context) (AccessControl, bool) {
+	ac, ok := ctx.Value(accessControlKey{}).(AccessControl)
+	return ac, ok
+}
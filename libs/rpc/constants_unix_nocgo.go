@@ -1,5 +1,6 @@
 //go:build !cgo && !windows
 // +build !cgo,!windows
+
 package rpc
 
 var (
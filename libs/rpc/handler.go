@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/metrics"
 )
 
 // handler handles JSON-RPC messages. There is one handler per connection. Note that
@@ -45,6 +46,8 @@ type handler struct {
 	conn           jsonWriter                     // where responses will be sent
 	log            log.Logger
 	allowSubscribe bool
+	limiter        *rateLimiter // request quotas of the server, nil if unlimited
+	identity       string       // client the calls are accounted to
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
@@ -72,6 +75,10 @@ func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *
 	if conn.remoteAddr() != "" {
 		h.log = h.log.New("conn", conn.remoteAddr())
 	}
+	if limiter, ok := connCtx.Value(rateLimiterKey{}).(*rateLimiter); ok {
+		h.limiter = limiter
+		h.identity, _ = ClientIdentityFromContext(connCtx)
+	}
 	h.unsubscribeCb = newCallback(reflect.Value{}, reflect.ValueOf(h.unsubscribe))
 	return h
 }
@@ -85,6 +92,25 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 		})
 		return
 	}
+	// Reject oversized batches, answering each call so clients waiting on the
+	// individual responses are released:
+	if h.limiter != nil {
+		if err := h.limiter.checkBatch(len(msgs)); err != nil {
+			h.startCallProc(func(cp *callProc) {
+				answers := make([]*jsonrpcMessage, 0, len(msgs))
+				for _, msg := range msgs {
+					if msg.hasValidID() {
+						answers = append(answers, msg.errorResponse(err))
+					}
+				}
+				if len(answers) == 0 {
+					answers = append(answers, errorMessage(err))
+				}
+				h.conn.writeJSON(cp.ctx, answers)
+			})
+			return
+		}
+	}
 
 	// Handle non-call messages first:
 	calls := make([]*jsonrpcMessage, 0, len(msgs))
@@ -301,6 +327,16 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if ac, ok := AccessControlFromContext(cp.ctx); ok && !ac.Allowed(msg.Method) {
+		return msg.errorResponse(&accessDeniedError{method: msg.Method})
+	}
+	if h.limiter != nil {
+		release, err := h.limiter.acquire(h.identity, msg.Method, h.costCounter(msg))
+		if err != nil {
+			return msg.errorResponse(err)
+		}
+		defer release()
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
@@ -319,6 +355,11 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	}
 	start := time.Now()
 	answer := h.runMethod(cp.ctx, msg, callb, args)
+	if h.limiter != nil && answer.Error == nil {
+		if err := h.limiter.checkResponse(len(answer.Result)); err != nil {
+			answer = msg.errorResponse(err)
+		}
+	}
 
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
@@ -335,6 +376,22 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	return answer
 }
 
+// costCounter returns the counter the cost of msg is accounted to. The calls of
+// methods which don't resolve share a single counter, so that clients can't grow
+// the metrics registry with made up method names.
+func (h *handler) costCounter(msg *jsonrpcMessage) metrics.Counter {
+	switch {
+	case msg.isSubscribe():
+		name, err := parseSubscriptionName(msg.Params)
+		if err != nil || h.reg.subscription(msg.namespace(), name) == nil {
+			return rpcUnknownCostCounter
+		}
+	case !msg.isUnsubscribe() && h.reg.callback(msg.Method) == nil:
+		return rpcUnknownCostCounter
+	}
+	return newRPCCostCounter(msg.Method)
+}
+
 // handleSubscribe processes *_subscribe method calls.
 func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
 	if !h.allowSubscribe {
@@ -0,0 +1,218 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/mclock"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/metrics"
+)
+
+// maxTrackedClients is the number of client quotas kept before idle ones are
+// dropped from the limiter.
+const maxTrackedClients = 4096
+
+// RateLimits are the request quotas a Server enforces on each of its clients.
+// Clients are identified by the identity installed with WithClientIdentity, or
+// by their IP address. The zero value disables all limits.
+type RateLimits struct {
+	RequestRate     float64        `toml:",omitempty"` // Call cost refilled per second for each client, 0 for unlimited
+	RequestBurst    int            `toml:",omitempty"` // Call cost a client may spend at once, defaults to the rate
+	MethodWeights   map[string]int `toml:",omitempty"` // Cost of a call to a method, 1 if not listed
+	MaxConcurrent   int            `toml:",omitempty"` // Calls a client may have in flight, 0 for unlimited
+	MaxBatchSize    int            `toml:",omitempty"` // Calls in a batch request, 0 for unlimited
+	MaxResponseSize int            `toml:",omitempty"` // Encoded size of a call result in bytes, 0 for unlimited
+}
+
+// Enabled reports whether any limit is configured.
+func (l RateLimits) Enabled() bool {
+	return l.RequestRate > 0 || l.MaxConcurrent > 0 || l.MaxBatchSize > 0 || l.MaxResponseSize > 0
+}
+
+// weight returns the cost of a call to method.
+func (l RateLimits) weight(method string) int {
+	if w, ok := l.MethodWeights[method]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+type (
+	clientIdentityKey struct{}
+	rateLimiterKey    struct{}
+)
+
+// WithClientIdentity returns a copy of the context accounting the requests
+// carrying it to the given client, e.g. the subject of an auth token.
+func WithClientIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, clientIdentityKey{}, identity)
+}
+
+// ClientIdentityFromContext retrieves the identity installed by WithClientIdentity.
+func ClientIdentityFromContext(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(clientIdentityKey{}).(string)
+	return identity, ok
+}
+
+// requestIdentity returns the identity the requests of r are accounted to.
+func requestIdentity(r *http.Request) string {
+	if identity, ok := ClientIdentityFromContext(r.Context()); ok {
+		return identity
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// clientQuota is the token bucket and in-flight counter of a client.
+type clientQuota struct {
+	tokens   float64
+	updated  mclock.AbsTime
+	inflight int
+}
+
+// rateLimiter enforces RateLimits over all connections of a server.
+type rateLimiter struct {
+	limits RateLimits
+	burst  float64
+	clock  mclock.Clock
+
+	mu      sync.Mutex
+	clients map[string]*clientQuota
+}
+
+func newRateLimiter(limits RateLimits, clock mclock.Clock) *rateLimiter {
+	burst := float64(limits.RequestBurst)
+	if burst <= 0 {
+		burst = limits.RequestRate
+	}
+	return &rateLimiter{
+		limits:  limits,
+		burst:   burst,
+		clock:   clock,
+		clients: make(map[string]*clientQuota),
+	}
+}
+
+// acquire charges a call to method against the quota of the client and adds
+// its cost to counter, if not nil. On success the returned function must be
+// called once the call is done.
+func (l *rateLimiter) acquire(identity, method string, counter metrics.Counter) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	q := l.clients[identity]
+	if q == nil {
+		if len(l.clients) >= maxTrackedClients {
+			l.prune(now)
+		}
+		q = &clientQuota{tokens: l.burst, updated: now}
+		l.clients[identity] = q
+	}
+	l.refill(q, now)
+
+	if l.limits.MaxConcurrent > 0 && q.inflight >= l.limits.MaxConcurrent {
+		rpcLimitedConcurrencyMeter.Mark(1)
+		return nil, &limitExceededError{fmt.Sprintf("too many concurrent requests (max %d)", l.limits.MaxConcurrent)}
+	}
+	cost := l.limits.weight(method)
+	if l.limits.RequestRate > 0 {
+		if float64(cost) > l.burst {
+			rpcLimitedRateMeter.Mark(1)
+			return nil, &limitExceededError{fmt.Sprintf("request cost of %s exceeds burst (%d>%v)", method, cost, l.burst)}
+		}
+		if q.tokens < float64(cost) {
+			rpcLimitedRateMeter.Mark(1)
+			return nil, &limitExceededError{fmt.Sprintf("request rate exceeded, retry in %v", l.retryAfter(q, cost))}
+		}
+		q.tokens -= float64(cost)
+	}
+	q.inflight++
+	rpcInflightGauge.Inc(1)
+	rpcCostCounter.Inc(int64(cost))
+	if counter != nil {
+		counter.Inc(int64(cost))
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			q.inflight--
+			l.mu.Unlock()
+			rpcInflightGauge.Dec(1)
+		})
+	}, nil
+}
+
+// refill adds the tokens accrued since the last update of the quota.
+func (l *rateLimiter) refill(q *clientQuota, now mclock.AbsTime) {
+	elapsed := time.Duration(now - q.updated)
+	q.updated = now
+	if q.tokens += l.limits.RequestRate * elapsed.Seconds(); q.tokens > l.burst {
+		q.tokens = l.burst
+	}
+}
+
+// retryAfter estimates how long the client has to wait before a call of the
+// given cost is granted.
+func (l *rateLimiter) retryAfter(q *clientQuota, cost int) time.Duration {
+	missing := float64(cost) - q.tokens
+	wait := time.Duration(missing / l.limits.RequestRate * float64(time.Second))
+	return wait.Round(time.Millisecond)
+}
+
+// prune drops the quotas of idle clients whose bucket is full again, as they
+// carry no state a new quota wouldn't.
+func (l *rateLimiter) prune(now mclock.AbsTime) {
+	for identity, q := range l.clients {
+		l.refill(q, now)
+		if q.inflight == 0 && q.tokens >= l.burst {
+			delete(l.clients, identity)
+		}
+	}
+}
+
+// LimitHandler wraps h, a handler served next to the server on its HTTP
+// endpoint, with the request quotas of the server's clients. Each request is
+// charged as a call to method. Requests over quota get a 429 status.
+func (s *Server) LimitHandler(method string, h http.Handler) http.Handler {
+	if s.limiter == nil {
+		return h
+	}
+	counter := newRPCCostCounter(method)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		release, err := s.limiter.acquire(requestIdentity(r), method, counter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		defer release()
+		h.ServeHTTP(w, r)
+	})
+}
+
+// checkBatch returns an error if the batch holds more calls than allowed.
+func (l *rateLimiter) checkBatch(size int) error {
+	if l.limits.MaxBatchSize > 0 && size > l.limits.MaxBatchSize {
+		rpcLimitedBatchMeter.Mark(1)
+		return &limitExceededError{fmt.Sprintf("batch too large (%d>%d)", size, l.limits.MaxBatchSize)}
+	}
+	return nil
+}
+
+// checkResponse returns an error if the encoded result exceeds the size limit.
+func (l *rateLimiter) checkResponse(size int) error {
+	if l.limits.MaxResponseSize > 0 && size > l.limits.MaxResponseSize {
+		rpcLimitedResponseMeter.Mark(1)
+		return &limitExceededError{fmt.Sprintf("response too large (%d>%d)", size, l.limits.MaxResponseSize)}
+	}
+	return nil
+}
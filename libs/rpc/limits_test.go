@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/mclock"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/metrics"
+)
+
+func TestRateLimiterBucket(t *testing.T) {
+	clock := new(mclock.Simulated)
+	limiter := newRateLimiter(RateLimits{
+		RequestRate:   2,
+		RequestBurst:  4,
+		MethodWeights: map[string]int{"debug_traceBlock": 3, "debug_huge": 10},
+	}, clock)
+
+	// The burst is spent by the weighted call and one plain call.
+	for _, method := range []string{"debug_traceBlock", "eth_blockNumber"} {
+		release, err := limiter.acquire("a", method, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", method, err)
+		}
+		release()
+	}
+	if _, err := limiter.acquire("a", "eth_blockNumber", nil); err == nil {
+		t.Fatal("call beyond burst granted")
+	}
+	// Other clients have their own bucket.
+	if _, err := limiter.acquire("b", "debug_traceBlock", nil); err != nil {
+		t.Fatalf("other client limited: %v", err)
+	}
+	// Calls costing more than the burst are never granted.
+	if _, err := limiter.acquire("c", "debug_huge", nil); err == nil {
+		t.Fatal("call costing more than the burst granted")
+	}
+	// The bucket refills with the rate.
+	clock.Run(time.Second)
+	if _, err := limiter.acquire("a", "eth_blockNumber", nil); err != nil {
+		t.Fatalf("call after refill denied: %v", err)
+	}
+	if _, err := limiter.acquire("a", "eth_blockNumber", nil); err != nil {
+		t.Fatalf("call after refill denied: %v", err)
+	}
+	if _, err := limiter.acquire("a", "eth_blockNumber", nil); err == nil {
+		t.Fatal("call beyond refill granted")
+	}
+}
+
+func TestRateLimiterConcurrency(t *testing.T) {
+	limiter := newRateLimiter(RateLimits{MaxConcurrent: 2}, new(mclock.Simulated))
+
+	release1, err := limiter.acquire("a", "test_sleep", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.acquire("a", "test_sleep", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.acquire("a", "test_sleep", nil); err == nil {
+		t.Fatal("call beyond concurrency cap granted")
+	}
+	release1()
+	release1() // releasing twice must not free another slot
+	if _, err := limiter.acquire("a", "test_sleep", nil); err != nil {
+		t.Fatalf("call after release denied: %v", err)
+	}
+	if _, err := limiter.acquire("a", "test_sleep", nil); err == nil {
+		t.Fatal("double release freed a slot")
+	}
+}
+
+func TestServerRateLimits(t *testing.T) {
+	server := newTestServer()
+	server.SetRateLimits(RateLimits{MaxBatchSize: 2, MaxResponseSize: 64})
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	var res echoResult
+	if err := client.Call(&res, "test_echo", "x", 1, nil); err != nil {
+		t.Fatalf("small response denied: %v", err)
+	}
+	err := client.Call(&res, "test_echo", strings.Repeat("x", 64), 1, nil)
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != -32005 {
+		t.Fatalf("large response not limited, got error %v", err)
+	}
+
+	batch := make([]BatchElem, 3)
+	for i := range batch {
+		batch[i] = BatchElem{Method: "test_echo", Args: []interface{}{"x", i, nil}, Result: new(echoResult)}
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal(err)
+	}
+	for i, elem := range batch {
+		if elem.Error == nil {
+			t.Errorf("element %d of oversized batch served", i)
+		}
+	}
+	if err := client.BatchCall(batch[:2]); err != nil {
+		t.Fatal(err)
+	}
+	for i, elem := range batch[:2] {
+		if elem.Error != nil {
+			t.Errorf("batch element %d failed: %v", i, elem.Error)
+		}
+	}
+}
+
+func TestServerCostMetrics(t *testing.T) {
+	server := newTestServer()
+	server.SetRateLimits(RateLimits{RequestRate: 100})
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	var res echoResult
+	if err := client.Call(&res, "test_echo", "x", 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(&res, "test_madeUpMethod"); err == nil {
+		t.Fatal("unknown method served")
+	}
+	if metrics.DefaultRegistry.Get("rpc/cost/test_echo") == nil {
+		t.Error("cost of a known method not accounted")
+	}
+	if metrics.DefaultRegistry.Get("rpc/cost/test_madeUpMethod") != nil {
+		t.Error("unknown method registered a cost metric")
+	}
+}
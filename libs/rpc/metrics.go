@@ -11,6 +11,14 @@ var (
 	successfulRequestGauge = metrics.NewRegisteredGauge("rpc/success", nil)
 	failedReqeustGauge     = metrics.NewRegisteredGauge("rpc/failure", nil)
 	rpcServingTimer        = metrics.NewRegisteredTimer("rpc/duration/all", nil)
+
+	rpcCostCounter             = metrics.NewRegisteredCounter("rpc/cost/all", nil)
+	rpcUnknownCostCounter      = metrics.NewRegisteredCounter("rpc/cost/unknown", nil)
+	rpcInflightGauge           = metrics.NewRegisteredGauge("rpc/inflight", nil)
+	rpcLimitedRateMeter        = metrics.NewRegisteredMeter("rpc/limited/rate", nil)
+	rpcLimitedConcurrencyMeter = metrics.NewRegisteredMeter("rpc/limited/concurrency", nil)
+	rpcLimitedBatchMeter       = metrics.NewRegisteredMeter("rpc/limited/batch", nil)
+	rpcLimitedResponseMeter    = metrics.NewRegisteredMeter("rpc/limited/response", nil)
 )
 
 func newRPCServingTimer(method string, valid bool) metrics.Timer {
@@ -21,3 +29,7 @@ func newRPCServingTimer(method string, valid bool) metrics.Timer {
 	m := fmt.Sprintf("rpc/duration/%s/%s", method, flag)
 	return metrics.GetOrRegisterTimer(m, nil)
 }
+
+func newRPCCostCounter(method string) metrics.Counter {
+	return metrics.GetOrRegisterCounter("rpc/cost/"+method, nil)
+}
@@ -7,6 +7,7 @@ import (
 
 	mapset "github.com/deckarep/golang-set"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/mclock"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
 )
 
@@ -31,6 +32,7 @@ type Server struct {
 	idgen    func() ID
 	run      int32
 	codecs   mapset.Set
+	limiter  *rateLimiter
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -51,12 +53,28 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 	return s.services.registerName(name, receiver)
 }
 
+// SetRateLimits configures the request quotas enforced on the clients of the
+// server. It must be called before the server starts serving requests.
+func (s *Server) SetRateLimits(limits RateLimits) {
+	if !limits.Enabled() {
+		s.limiter = nil
+		return
+	}
+	s.limiter = newRateLimiter(limits, mclock.System{})
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
 // the response back using the given codec. It will block until the codec is closed or the
 // server is stopped. In either case the codec is closed.
 //
 // Note that codec options are no longer supported.
 func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
+	s.serveCodec(context.Background(), codec)
+}
+
+// serveCodec serves the codec like ServeCodec, deriving the context of the
+// calls from the given one.
+func (s *Server) serveCodec(ctx context.Context, codec ServerCodec) {
 	defer codec.close()
 
 	// Don't serve if server is stopped.
@@ -68,7 +86,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	s.codecs.Add(codec)
 	defer s.codecs.Remove(codec)
 
-	c := initClient(codec, s.idgen, &s.services)
+	c := initClient(s.limitContext(ctx), codec, s.idgen, &s.services)
 	<-codec.closed()
 	c.Close()
 }
@@ -82,7 +100,7 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 		return
 	}
 
-	h := newHandler(ctx, codec, s.idgen, &s.services)
+	h := newHandler(s.limitContext(ctx), codec, s.idgen, &s.services)
 	h.allowSubscribe = false
 	defer h.close(io.EOF, nil)
 
@@ -100,6 +118,15 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 	}
 }
 
+// limitContext installs the rate limiter of the server into the connection
+// context, for the handler to charge the calls of the connection against it.
+func (s *Server) limitContext(ctx context.Context) context.Context {
+	if s.limiter == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, rateLimiterKey{}, s.limiter)
+}
+
 // Stop stops reading new requests, waits for stopPendingRequestTimeout to allow pending
 // requests to finish, then closes all codecs which will cancel pending requests and
 // subscriptions.
@@ -42,8 +42,14 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			log.Debug("WebSocket upgrade failed", "err", err)
 			return
 		}
+		// The request context ends with the upgrade, carry over the access
+		// control the HTTP stack installed for the lifetime of the connection.
+		connCtx := WithClientIdentity(context.Background(), requestIdentity(r))
+		if ac, ok := AccessControlFromContext(r.Context()); ok {
+			connCtx = WithAccessControl(connCtx, ac)
+		}
 		codec := newWebsocketCodec(conn)
-		s.ServeCodec(codec, 0)
+		s.serveCodec(connCtx, codec)
 	})
 }
 
@@ -166,10 +172,30 @@ func parseOriginURL(origin string) (string, string, string, error) {
 // DialWebsocketWithDialer creates a new RPC client that communicates with a JSON-RPC server
 // that is listening on the given endpoint using the provided dialer.
 func DialWebsocketWithDialer(ctx context.Context, endpoint, origin string, dialer websocket.Dialer) (*Client, error) {
+	return dialWebsocket(ctx, endpoint, origin, nil, dialer)
+}
+
+// DialWebsocketWithHeader creates a new RPC client like DialWebsocket, sending the
+// given headers with the handshake, e.g. the bearer token of an authenticated endpoint.
+func DialWebsocketWithHeader(ctx context.Context, endpoint, origin string, header http.Header) (*Client, error) {
+	dialer := websocket.Dialer{
+		ReadBufferSize:  wsReadBuffer,
+		WriteBufferSize: wsWriteBuffer,
+		WriteBufferPool: wsBufferPool,
+	}
+	return dialWebsocket(ctx, endpoint, origin, header, dialer)
+}
+
+func dialWebsocket(ctx context.Context, endpoint, origin string, extra http.Header, dialer websocket.Dialer) (*Client, error) {
 	endpoint, header, err := wsClientHeaders(endpoint, origin)
 	if err != nil {
 		return nil, err
 	}
+	for key, values := range extra {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
 	return newClient(ctx, func(ctx context.Context) (ServerCodec, error) {
 		conn, resp, err := dialer.DialContext(ctx, endpoint, header)
 		if err != nil {
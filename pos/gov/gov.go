@@ -34,6 +34,7 @@ const (
 	ModuleTxPool      = "txPool"
 	ModuleReward      = "reward"
 	ModuleRestricting = "restricting"
+	ModuleFee         = "fee"
 )
 
 const (
@@ -54,6 +55,11 @@ const (
 	KeyIncreaseIssuanceRatio      = "increaseIssuanceRatio"
 	KeyZeroProduceFreezeDuration  = "zeroProduceFreezeDuration"
 	KeyRestrictingMinimumAmount   = "minimumRelease"
+	KeyBaseFeeChangeDenominator   = "baseFeeChangeDenominator"
+	KeyElasticityMultiplier       = "elasticityMultiplier"
+	KeyMinBaseFee                 = "minBaseFee"
+	KeyBaseFeeBurnRatio           = "baseFeeBurnRatio"
+	KeyTreasuryIssuanceRatio      = "treasuryIssuanceRatio"
 )
 
 func Gte110VersionState(state xcom.StateDB) bool {
@@ -887,3 +893,72 @@ func GovernRestrictingMinimumAmount(blockNumber uint64, blockHash common.Hash) (
 
 	return value, nil
 }
+
+func GovernBaseFeeChangeDenominator(blockNumber uint64, blockHash common.Hash) (uint64, error) {
+	valueStr, err := GetGovernParamValue(ModuleFee, KeyBaseFeeChangeDenominator, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if nil != err {
+		return 0, err
+	}
+
+	return uint64(value), nil
+}
+
+func GovernElasticityMultiplier(blockNumber uint64, blockHash common.Hash) (uint64, error) {
+	valueStr, err := GetGovernParamValue(ModuleFee, KeyElasticityMultiplier, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if nil != err {
+		return 0, err
+	}
+
+	return uint64(value), nil
+}
+
+func GovernMinBaseFee(blockNumber uint64, blockHash common.Hash) (*big.Int, error) {
+	valueStr, err := GetGovernParamValue(ModuleFee, KeyMinBaseFee, blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
+	value, ok := new(big.Int).SetString(valueStr, 10)
+	if !ok {
+		return nil, errors.New("set KeyMinBaseFee to big int fail")
+	}
+
+	return value, nil
+}
+
+func GovernBaseFeeBurnRatio(blockNumber uint64, blockHash common.Hash) (uint16, error) {
+	valueStr, err := GetGovernParamValue(ModuleFee, KeyBaseFeeBurnRatio, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if nil != err {
+		return 0, err
+	}
+
+	return uint16(value), nil
+}
+
+func GovernTreasuryIssuanceRatio(blockNumber uint64, blockHash common.Hash) (uint16, error) {
+	valueStr, err := GetGovernParamValue(ModuleReward, KeyTreasuryIssuanceRatio, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if nil != err {
+		return 0, err
+	}
+
+	return uint16(value), nil
+}
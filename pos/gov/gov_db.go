@@ -66,6 +66,13 @@ func GetProposal(proposalID common.Hash, state xcom.StateDB) (Proposal, error) {
 			return nil, e
 		}
 		return &proposal, nil
+	} else if pType == byte(Treasury) {
+		var proposal TreasuryProposal
+		if e := json.Unmarshal(pData, &proposal); e != nil {
+			log.Error("cannot parse data to treasury proposal")
+			return nil, e
+		}
+		return &proposal, nil
 	} else {
 		return nil, common.InternalError.Wrap("Incorrect proposal type.")
 	}
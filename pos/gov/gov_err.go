@@ -37,4 +37,7 @@ var (
 	VotingParamProposalExist          = common.NewBizError(302032, "Another parameter proposal already existed at voting stage")
 	GovernParamValueError             = common.NewBizError(302033, "Govern parameter value error")
 	ParamProposalIsSameValue          = common.NewBizError(302034, "The new value of the parameter proposal is the same as the old one")
+	TreasuryRecipientInvalid          = common.NewBizError(302035, "The recipient of the treasury proposal is invalid")
+	TreasuryTranchesInvalid           = common.NewBizError(302036, "The tranches of the treasury proposal are invalid")
+	TreasuryBalanceNotEnough          = common.NewBizError(302037, "The balance of the treasury is not enough for the treasury proposal")
 )
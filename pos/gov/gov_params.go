@@ -21,6 +21,18 @@ var (
 
 var governParam []*GovernParam
 
+const (
+	// MaxBaseFeeChangeDenominator and MaxElasticityMultiplier bound the fee
+	// market parameters, DefaultBaseFeeBurnRatio is the percentage of the
+	// base fee burnt unless governance changes it.
+	MaxBaseFeeChangeDenominator = 1024
+	MaxElasticityMultiplier     = 16
+	DefaultBaseFeeBurnRatio     = 100
+	// DefaultTreasuryIssuanceRatio is the percentage of the yearly issuance
+	// left over by the reward pool that goes to the community treasury.
+	DefaultTreasuryIssuanceRatio = 0
+)
+
 func queryInitParam() []*GovernParam {
 	initGovParam.Do(func() {
 		log.Info("Init Govern parameters ...")
@@ -366,6 +378,96 @@ func initParam() []*GovernParam {
 				return nil
 			},
 		},
+
+		/**
+		About Fee module
+		*/
+		{
+			ParamItem: &ParamItem{ModuleFee, KeyBaseFeeChangeDenominator,
+				fmt.Sprintf("bound divisor of the base fee change between blocks, range: [1, %d]", MaxBaseFeeChangeDenominator)},
+			ParamValue: &ParamValue{"", strconv.Itoa(configs.BaseFeeChangeDenominator), 0},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				denominator, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("parsed BaseFeeChangeDenominator is failed: %v", err)
+				}
+
+				if denominator < 1 || denominator > MaxBaseFeeChangeDenominator {
+					return common.InvalidParameter.Wrap(fmt.Sprintf("The BaseFeeChangeDenominator must be [1, %d]", MaxBaseFeeChangeDenominator))
+				}
+				return nil
+			},
+		},
+		{
+			ParamItem: &ParamItem{ModuleFee, KeyElasticityMultiplier,
+				fmt.Sprintf("ratio of the block gas limit to the gas target of the base fee, range: [1, %d]", MaxElasticityMultiplier)},
+			ParamValue: &ParamValue{"", strconv.Itoa(configs.ElasticityMultiplier), 0},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				multiplier, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("parsed ElasticityMultiplier is failed: %v", err)
+				}
+
+				if multiplier < 1 || multiplier > MaxElasticityMultiplier {
+					return common.InvalidParameter.Wrap(fmt.Sprintf("The ElasticityMultiplier must be [1, %d]", MaxElasticityMultiplier))
+				}
+				return nil
+			},
+		},
+		{
+			ParamItem: &ParamItem{ModuleFee, KeyMinBaseFee,
+				fmt.Sprintf("minimum base fee per gas of a block, range: [0, %d]", configs.InitialBaseFee)},
+			ParamValue: &ParamValue{"", "0", 0},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				minBaseFee, ok := new(big.Int).SetString(value, 10)
+				if !ok {
+					return fmt.Errorf("parsed MinBaseFee is failed")
+				}
+
+				if minBaseFee.Sign() < 0 || minBaseFee.Cmp(big.NewInt(configs.InitialBaseFee)) > 0 {
+					return common.InvalidParameter.Wrap(fmt.Sprintf("The MinBaseFee must be [0, %d]", configs.InitialBaseFee))
+				}
+				return nil
+			},
+		},
+		{
+			ParamItem: &ParamItem{ModuleFee, KeyBaseFeeBurnRatio,
+				"percentage of the base fee that is burnt, the rest goes to the community developer foundation, range: [0, 100]"},
+			ParamValue: &ParamValue{"", strconv.Itoa(DefaultBaseFeeBurnRatio), 0},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				ratio, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("parsed BaseFeeBurnRatio is failed: %v", err)
+				}
+
+				if ratio < 0 || ratio > 100 {
+					return common.InvalidParameter.Wrap("The BaseFeeBurnRatio must be [0, 100]")
+				}
+				return nil
+			},
+		},
+		{
+
+			ParamItem: &ParamItem{ModuleReward, KeyTreasuryIssuanceRatio,
+				"percentage of the yearly additional issuance not allocated to the reward pool that goes to the community treasury, range: [0, 100]"},
+			ParamValue: &ParamValue{"", strconv.Itoa(DefaultTreasuryIssuanceRatio), 0},
+			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
+
+				ratio, err := strconv.Atoi(value)
+				if nil != err {
+					return fmt.Errorf("parsed TreasuryIssuanceRatio is failed: %v", err)
+				}
+
+				if ratio < 0 || ratio > 100 {
+					return common.InvalidParameter.Wrap("The TreasuryIssuanceRatio must be [0, 100]")
+				}
+				return nil
+			},
+		},
 	}
 }
 
@@ -408,6 +510,31 @@ func InitGenesisGovernParam(prevHash common.Hash, snapDB snapshotdb.BaseDB, gene
 	return lastHash, nil
 }
 
+// AddMissingGovernParams stores the parameters introduced after the genesis
+// of the chain with their default values, so that param proposals can tune
+// them. It is called when a new version becomes active and at the blocks of
+// the forks introducing parameters.
+func AddMissingGovernParams(blockHash common.Hash) error {
+	itemList, err := listGovernParamItem("", blockHash)
+	if err != nil {
+		return err
+	}
+	exist := make(map[string]struct{}, len(itemList))
+	for _, item := range itemList {
+		exist[item.Module+"/"+item.Name] = struct{}{}
+	}
+	for _, param := range queryInitParam() {
+		if _, ok := exist[param.ParamItem.Module+"/"+param.ParamItem.Name]; ok {
+			continue
+		}
+		if err := SetGovernParam(param.ParamItem.Module, param.ParamItem.Name, param.ParamItem.Desc, param.ParamValue.Value, 0, blockHash); err != nil {
+			return err
+		}
+		log.Info("Add new govern parameter", "module", param.ParamItem.Module, "name", param.ParamItem.Name, "value", param.ParamValue.Value)
+	}
+	return nil
+}
+
 func RegisterGovernParamVerifiers() {
 	for _, param := range queryInitParam() {
 		RegGovernParamVerifier(param.ParamItem.Module, param.ParamItem.Name, param.ParamVerifier)
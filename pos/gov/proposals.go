@@ -5,8 +5,10 @@ import (
 	"math/big"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/restricting"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/xcom"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/xutil"
 )
@@ -18,10 +20,11 @@ const RateCoefficient = uint64(10000)
 type ProposalType uint8
 
 const (
-	Text    ProposalType = 0x01
-	Version ProposalType = 0x02
-	Param   ProposalType = 0x03
-	Cancel  ProposalType = 0x04
+	Text     ProposalType = 0x01
+	Version  ProposalType = 0x02
+	Param    ProposalType = 0x03
+	Cancel   ProposalType = 0x04
+	Treasury ProposalType = 0x05
 )
 
 type ProposalStatus uint8
@@ -342,7 +345,7 @@ func (cp *CancelProposal) Verify(submitBlock uint64, blockHash common.Hash, stat
 		return err
 	} else if tobeCanceled == nil {
 		return TobeCanceledProposalNotFound
-	} else if tobeCanceled.GetProposalType() != Version && tobeCanceled.GetProposalType() != Param {
+	} else if tobeCanceled.GetProposalType() != Version && tobeCanceled.GetProposalType() != Param && tobeCanceled.GetProposalType() != Treasury {
 		return TobeCanceledProposalTypeError
 	} else if votingList, err := ListVotingProposal(blockHash); err != nil {
 		log.Error("list voting proposal error", "err", err)
@@ -481,6 +484,115 @@ func (pp *ParamProposal) String() string {
 		pp.ProposalID, pp.ProposalType, pp.PIPID, pp.Proposer, pp.SubmitBlock, pp.EndVotingBlock, pp.Module, pp.Name, pp.NewValue)
 }
 
+// TreasuryProposal spends the community treasury: once it passes, the amounts
+// of the tranches are moved from the treasury to the recipient as restricting
+// plans, each released at the end of its epoch counted from the tally.
+type TreasuryProposal struct {
+	ProposalID     common.Hash
+	ProposalType   ProposalType
+	PIPID          string
+	SubmitBlock    uint64
+	EndVotingBlock uint64
+	Proposer       discover.NodeID
+	Result         TallyResult `json:"-"`
+	Recipient      common.Address
+	Tranches       []restricting.RestrictingPlan
+}
+
+func (tp *TreasuryProposal) GetProposalID() common.Hash {
+	return tp.ProposalID
+}
+
+func (tp *TreasuryProposal) GetProposalType() ProposalType {
+	return tp.ProposalType
+}
+
+func (tp *TreasuryProposal) GetPIPID() string {
+	return tp.PIPID
+}
+
+func (tp *TreasuryProposal) GetSubmitBlock() uint64 {
+	return tp.SubmitBlock
+}
+
+func (tp *TreasuryProposal) GetEndVotingBlock() uint64 {
+	return tp.EndVotingBlock
+}
+
+func (tp *TreasuryProposal) GetProposer() discover.NodeID {
+	return tp.Proposer
+}
+
+func (tp *TreasuryProposal) GetTallyResult() TallyResult {
+	return tp.Result
+}
+
+// TotalAmount returns the sum of the amounts of the tranches.
+func (tp *TreasuryProposal) TotalAmount() *big.Int {
+	total := new(big.Int)
+	for _, tranche := range tp.Tranches {
+		if tranche.Amount != nil {
+			total.Add(total, tranche.Amount)
+		}
+	}
+	return total
+}
+
+func (tp *TreasuryProposal) Verify(submitBlock uint64, blockHash common.Hash, state xcom.StateDB, chainID *big.Int) error {
+	if tp.ProposalType != Treasury {
+		return ProposalTypeError
+	}
+	if err := verifyBasic(tp, blockHash, state); err != nil {
+		return err
+	}
+
+	if tp.Recipient == (common.Address{}) || tp.Recipient == vm.TreasuryAddr {
+		return TreasuryRecipientInvalid
+	}
+
+	if len(tp.Tranches) == 0 || len(tp.Tranches) > restricting.RestrictTxPlanSize {
+		return TreasuryTranchesInvalid
+	}
+	minimumAmount, err := GovernRestrictingMinimumAmount(submitBlock, blockHash)
+	if err != nil {
+		log.Error("get minimum restricting amount error", "err", err)
+		return err
+	}
+	for _, tranche := range tp.Tranches {
+		if tranche.Epoch == 0 || tranche.Amount == nil || tranche.Amount.Sign() <= 0 || tranche.Amount.Cmp(minimumAmount) < 0 {
+			return TreasuryTranchesInvalid
+		}
+	}
+	if state.GetBalance(vm.TreasuryAddr).Cmp(tp.TotalAmount()) < 0 {
+		return TreasuryBalanceNotEnough
+	}
+
+	var voteDuration = xcom.ParamProposalVote_DurationSeconds()
+
+	endVotingBlock := xutil.EstimateEndVotingBlockForParaProposal(submitBlock, voteDuration)
+	if endVotingBlock <= submitBlock {
+		log.Error("the end-voting-block is lower than submit-block. Please check configuration")
+		return common.InternalError
+	}
+	tp.EndVotingBlock = endVotingBlock
+	log.Debug("verify Treasury Proposal", "PIPID", tp.PIPID, "voteDuration", voteDuration, "endVotingBlock", endVotingBlock, "blockNumber", submitBlock, "blockHash", blockHash)
+
+	return nil
+}
+
+func (tp *TreasuryProposal) String() string {
+	return fmt.Sprintf(`Proposal %x: 
+  Type:               	%x
+  PIPID:			    %s
+  Proposer:            	%x
+  SubmitBlock:        	%d
+  EndVotingBlock:   	%d
+  Recipient:   			%s
+  Tranches:   			%d
+  TotalAmount:   		%s`,
+		tp.ProposalID, tp.ProposalType, tp.PIPID, tp.Proposer, tp.SubmitBlock, tp.EndVotingBlock, tp.Recipient.String(), len(tp.Tranches), tp.TotalAmount())
+}
+
 func verifyBasic(p Proposal, blockHash common.Hash, state xcom.StateDB) error {
 	log.Debug("verify proposal basic parameters", "proposalID", p.GetProposalID(), "proposer", p.GetProposer(), "pipID", p.GetPIPID(), "endVotingBlock", p.GetEndVotingBlock(), "submitBlock", p.GetSubmitBlock())
 
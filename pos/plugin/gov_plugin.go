@@ -10,6 +10,7 @@ import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/p2p/discover"
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/log"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/gov"
@@ -22,7 +23,8 @@ var (
 )
 
 type GovPlugin struct {
-	chainID *big.Int
+	chainID     *big.Int
+	chainConfig *configs.ChainConfig
 }
 
 var govp *GovPlugin
@@ -38,6 +40,10 @@ func GovPluginInstance() *GovPlugin {
 func (govPlugin *GovPlugin) SetChainID(chainId *big.Int) {
 	govPlugin.chainID = chainId
 }
+
+func (govPlugin *GovPlugin) SetChainConfig(config *configs.ChainConfig) {
+	govPlugin.chainConfig = config
+}
 func (govPlugin *GovPlugin) Confirmed(nodeId discover.NodeID, block *types.Block) error {
 	return nil
 }
@@ -47,6 +53,15 @@ func (govPlugin *GovPlugin) BeginBlock(blockHash common.Hash, header *types.Head
 	var blockNumber = header.Number.Uint64()
 	//log.Debug("call BeginBlock()", "blockNumber", blockNumber, "blockHash", blockHash)
 
+	// the fee market and treasury parameters are followed from the block after their fork,
+	// chains created before it get them at the fork block
+	if govPlugin.isGovernParamForkBlock(header.Number) {
+		if err := gov.AddMissingGovernParams(blockHash); err != nil {
+			log.Error("add the govern parameters of the fork failed.", "blockNumber", blockNumber, "blockHash", blockHash, "err", err)
+			return err
+		}
+	}
+
 	if !xutil.IsBeginOfConsensus(blockNumber) {
 		return nil
 	}
@@ -110,19 +125,38 @@ func (govPlugin *GovPlugin) BeginBlock(blockHash common.Hash, header *types.Head
 				return err
 			}
 
+			if err = gov.AddMissingGovernParams(blockHash); err != nil {
+				log.Error("add new govern parameters failed.", "blockNumber", blockNumber, "blockHash", blockHash, "preActiveProposalID", preActiveVersionProposalID)
+				return err
+			}
+
 			log.Info("version proposal is active", "blockNumber", blockNumber, "proposalID", versionProposal.ProposalID, "newVersion", versionProposal.NewVersion, "newVersionString", xutil.ProgramVersion2Str(versionProposal.NewVersion))
 		}
 	}
 	return nil
 }
 
+// isGovernParamForkBlock reports whether number is the block of a fork which
+// introduces govern parameters.
+func (govPlugin *GovPlugin) isGovernParamForkBlock(number *big.Int) bool {
+	if govPlugin.chainConfig == nil {
+		return false
+	}
+	for _, fork := range []*big.Int{govPlugin.chainConfig.DynamicFeeBlock, govPlugin.chainConfig.TreasuryBlock} {
+		if fork != nil && fork.Cmp(number) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 //implement BasePlugin
 func (govPlugin *GovPlugin) EndBlock(blockHash common.Hash, header *types.Header, state xcom.StateDB) error {
 	var blockNumber = header.Number.Uint64()
 	//log.Debug("call EndBlock()", "blockNumber", blockNumber, "blockHash", blockHash)
 
 	//text/version/cancel proposal's end voting block is ElectionBlock
-	//param proposal's end voting block is end of Epoch
+	//param/treasury proposal's end voting block is end of Epoch
 	isEndOfEpoch := false
 	isElection := false
 	if xutil.IsElection(blockNumber) {
@@ -173,6 +207,11 @@ func (govPlugin *GovPlugin) EndBlock(blockHash common.Hash, header *types.Header
 				if err != nil {
 					return err
 				}
+			} else if votingProposal.GetProposalType() == gov.Treasury && isEndOfEpoch {
+				_, err := tallyTreasury(votingProposal.(*gov.TreasuryProposal), blockHash, blockNumber, state)
+				if err != nil {
+					return err
+				}
 			} else {
 				log.Error("invalid proposal type", "type", votingProposal.GetProposalType())
 				return gov.ProposalTypeError
@@ -320,7 +359,7 @@ func tallyCancel(cp *gov.CancelProposal, blockHash common.Hash, blockNumber uint
 	} else if pass {
 		if proposal, err := gov.GetExistProposal(cp.TobeCanceled, state); err != nil {
 			return false, err
-		} else if proposal.GetProposalType() != gov.Version && proposal.GetProposalType() != gov.Param {
+		} else if proposal.GetProposalType() != gov.Version && proposal.GetProposalType() != gov.Param && proposal.GetProposalType() != gov.Treasury {
 			return false, gov.TobeCanceledProposalTypeError
 		}
 		if votingProposalIDList, err := gov.ListVotingProposalID(blockHash); err != nil {
@@ -395,6 +434,38 @@ func tallyParam(pp *gov.ParamProposal, blockHash common.Hash, blockNumber uint64
 	return true, nil
 }
 
+// tallyTreasury tallies a treasury proposal and, if it passes, moves the
+// tranches from the treasury to the recipient as restricting plans starting
+// from the next epoch. A passed proposal the treasury can no longer pay for
+// is marked as failed, its PIPID stays used.
+func tallyTreasury(tp *gov.TreasuryProposal, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (pass bool, err error) {
+	if pass, err := tally(gov.Treasury, tp.ProposalID, tp.PIPID, blockHash, blockNumber, state); err != nil {
+		return false, err
+	} else if !pass {
+		return false, nil
+	}
+	err = RestrictingInstance().AddRestrictingRecord(vm.TreasuryAddr, tp.Recipient, blockNumber+1, blockHash, tp.Tranches, state, tp.ProposalID)
+	if err == nil {
+		log.Info("treasury proposal is executed", "blockNumber", blockNumber, "proposalID", tp.ProposalID, "recipient", tp.Recipient, "amount", tp.TotalAmount())
+		return true, nil
+	}
+	if _, ok := err.(*common.BizError); !ok {
+		return false, err
+	}
+	log.Warn("failed to execute the passed treasury proposal", "blockNumber", blockNumber, "proposalID", tp.ProposalID, "recipient", tp.Recipient, "amount", tp.TotalAmount(), "err", err)
+	tallyResult, err := gov.GetTallyResult(tp.ProposalID, state)
+	if err != nil || tallyResult == nil {
+		log.Error("find treasury proposal tally result failed", "blockNumber", blockNumber, "proposalID", tp.ProposalID)
+		return false, err
+	}
+	tallyResult.Status = gov.Failed
+	if err := gov.SetTallyResult(*tallyResult, state); err != nil {
+		log.Error("save tally result failed", "blockNumber", blockNumber, "proposalID", tp.ProposalID, "tallyResult", tallyResult)
+		return false, err
+	}
+	return false, nil
+}
+
 func tally(proposalType gov.ProposalType, proposalID common.Hash, pipID string, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (pass bool, err error) {
 	//log.Debug("proposal tally", "proposalID", proposalID, "blockHash", blockHash, "blockNumber", blockNumber, "proposalID", proposalID)
 
@@ -440,6 +511,13 @@ func tally(proposalType gov.ProposalType, proposalID common.Hash, pipID string,
 		} else {
 			status = gov.Failed
 		}
+	case gov.Treasury:
+		// treasury proposals are passed by the same votes as param proposals
+		if voteRate > xcom.ParamProposal_VoteRate() && supportRate >= xcom.ParamProposal_SupportRate() {
+			status = gov.Pass
+		} else {
+			status = gov.Failed
+		}
 	}
 	tallyResult := &gov.TallyResult{
 		ProposalID:    proposalID,
@@ -249,7 +249,7 @@ type TestRestrictingPlugin struct {
 func NewTestRestrictingPlugin() *TestRestrictingPlugin {
 	tp := new(TestRestrictingPlugin)
 	tp.log = log.Root()
-	tp.from, tp.to = common.MustStringToAddress("0xavltgjnqmy6alefayfry3cd9rpguduawcph8ja"), common.MustStringToAddress("0xrkdnqnnsl5shqm7e00897dpey33h3pcntluqar")
+	tp.from, tp.to = common.MustStringToAddress("0xEcBef3C5e6D41D6E0d9a1d7eE1D17Fa09b9eA5Ad"), common.MustStringToAddress("0x1Ae5dB3d46fAa32d1a0C2F0d48B4a65c5e0a7Fd9")
 	tp.mockDB = mock.NewChain().StateDB
 	tp.mockDB.AddBalance(tp.from, big.NewInt(9e18))
 	return tp
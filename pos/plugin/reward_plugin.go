@@ -23,6 +23,7 @@ import (
 
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/pos/staking"
 
+	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/configs"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common"
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/libs/common/vm"
@@ -37,6 +38,7 @@ type RewardMgrPlugin struct {
 	nodeID        discover.NodeID
 	nodeADD       common.NodeAddress
 	stakingPlugin *StakingPlugin
+	chainConfig   *configs.ChainConfig
 }
 
 const (
@@ -66,6 +68,11 @@ func RewardMgrInstance() *RewardMgrPlugin {
 	return rm
 }
 
+// SetChainConfig sets the chain configuration the forks of the rewards are read from.
+func (rmp *RewardMgrPlugin) SetChainConfig(config *configs.ChainConfig) {
+	rmp.chainConfig = config
+}
+
 // BeginBlock does something like check input params before execute transactions,
 // in RewardMgrPlugin it does nothing.
 func (rmp *RewardMgrPlugin) BeginBlock(blockHash common.Hash, head *types.Header, state xcom.StateDB) error {
@@ -187,6 +194,19 @@ func (rmp *RewardMgrPlugin) increaseIssuance(thisYear, lastYear uint32, state xc
 	rewardpoolIncr := percentageCalculation(currIssuance, uint64(RewardPoolIncreaseRate))
 	state.AddBalance(vm.RewardManagerPoolAddr, rewardpoolIncr)
 	lessBalance := new(big.Int).Sub(currIssuance, rewardpoolIncr)
+	// The issuance only goes to the treasury after its fork, whatever the ratio.
+	if rmp.chainConfig != nil && rmp.chainConfig.IsTreasury(new(big.Int).SetUint64(blockNumber)) {
+		treasuryIssuanceRatio, err := gov.GovernTreasuryIssuanceRatio(blockNumber, blockHash)
+		if nil != err {
+			log.Warn("Failed to get the treasury issuance ratio, nothing goes to the treasury", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
+			treasuryIssuanceRatio = gov.DefaultTreasuryIssuanceRatio
+		}
+		if treasuryIncr := percentageCalculation(lessBalance, uint64(treasuryIssuanceRatio)); treasuryIncr.Sign() > 0 {
+			log.Debug("Call EndBlock on reward_plugin: increase issuance to treasury", "thisYear", thisYear, "treasuryBalance", treasuryIncr)
+			state.AddBalance(vm.TreasuryAddr, treasuryIncr)
+			lessBalance.Sub(lessBalance, treasuryIncr)
+		}
+	}
 	if rmp.isLessThanFoundationYear(thisYear) {
 		log.Debug("Call EndBlock on reward_plugin: increase issuance to developer", "thisYear", thisYear, "developBalance", lessBalance)
 		rmp.addCommunityDeveloperFoundation(state, lessBalance, LessThanFoundationYearDeveloperRate)
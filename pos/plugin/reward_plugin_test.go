@@ -312,6 +312,36 @@ func TestIncreaseIssuance(t *testing.T) {
 
 }
 
+func TestIncreaseIssuanceTreasuryFork(t *testing.T) {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+
+	gov.InitGenesisGovernParam(common.ZeroHash, snapshotdb.Instance(), 2048)
+
+	blockHash := common.HexToHash("0x01")
+	if err := snapshotdb.Instance().NewBlock(big.NewInt(1), common.ZeroHash, blockHash); nil != err {
+		t.Fatal(err)
+	}
+	defer func() {
+		snapshotdb.Instance().Clear()
+	}()
+
+	if err := gov.SetGovernParam(gov.ModuleReward, gov.KeyTreasuryIssuanceRatio, "", "50", 0, blockHash); nil != err {
+		t.Fatal(err)
+	}
+	plugin := &RewardMgrPlugin{chainConfig: &configs.ChainConfig{TreasuryBlock: big.NewInt(10)}}
+
+	// The issuance only goes to the treasury from the fork block on.
+	for _, blockNumber := range []uint64{9, 10} {
+		mockDB := mock.NewMockStateDB()
+		SetYearEndCumulativeIssue(mockDB, 0, new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18)))
+		if err := plugin.increaseIssuance(1, 0, mockDB, blockNumber, blockHash); nil != err {
+			t.Fatal(err)
+		}
+		funded := mockDB.GetBalance(vm.TreasuryAddr).Sign() > 0
+		assert.Equal(t, blockNumber >= 10, funded, "block %d", blockNumber)
+	}
+}
+
 func TestZeroIncreaseIssuance(t *testing.T) {
 	var plugin = RewardMgrInstance()
 
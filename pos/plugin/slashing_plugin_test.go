@@ -56,7 +56,7 @@ func initInfo(t *testing.T) (*SlashingPlugin, xcom.StateDB) {
 func buildStakingData(blockNumber uint64, blockHash common.Hash, pri *ecdsa.PrivateKey, blsKey bls.SecretKey, t *testing.T, stateDb xcom.StateDB) {
 	stakingDB := staking.NewStakingDB()
 
-	sender := common.MustStringToAddress("0xpmhjxvfqeccm87kzpkkr08djgvpp55355nr8j7")
+	sender := common.MustStringToAddress("0x0eEf233120cE31b3FAc20DAC379db243021A5234")
 
 	buildDbRestrictingPlan(sender, t, stateDb)
 
@@ -474,7 +474,7 @@ func TestSlashingPlugin_Slash(t *testing.T) {
           }
          }`
 	blockNumber = new(big.Int).Add(blockNumber, common.Big1)
-	stakingAddr := common.MustStringToAddress("0xr9tx0n00etv5c5smmlctlpg8jas7p78n8x3n9x")
+	stakingAddr := common.MustStringToAddress("0x3Ba2a1C3c2F1E3d6e0E2cA5b4D3e8a8A8f0B9d6c")
 	stakingNodeId, err := discover.HexID("51c0559c065400151377d71acd7a17282a7c8abcfefdb11992dcecafde15e100b8e31e1a5e74834a04792d016f166c80b9923423fe280570e8131debf591d483")
 	if nil != err {
 		t.Fatal(err)
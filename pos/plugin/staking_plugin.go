@@ -2702,6 +2702,7 @@ func buildPbftValidators(start uint64, arr staking.ValidatorQueue) *pbfttypes.Va
 			PubKey:    pubKey,
 			NodeID:    v.NodeId,
 			BlsPubKey: blsPk,
+			Weight:    v.Shares,
 		}
 
 		valMap[v.NodeId] = vn
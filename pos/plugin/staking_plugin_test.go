@@ -3,7 +3,6 @@ package plugin
 import (
 	"github.com/PhoenixGlobal/Phoenix-Chain-SDK/ethereum/core/types/pbfttypes"
 	"crypto/ecdsa"
-	"crypto/elliptic"
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
@@ -1740,6 +1739,9 @@ func TestStakingPlugin_WithdrewDelegate(t *testing.T) {
 	/**
 	Start Withdrew Delegate
 	*/
+	// the contract withdraws the delegation as it is stored
+	del = getDelegate(blockHash, blockNumber.Uint64(), index, t)
+
 	amount := common.Big257
 	delegateTotalHes := can.DelegateTotalHes
 	_, err = StakingInstance().WithdrewDelegation(state, blockHash2, blockNumber2, amount, addrArr[index+1],
@@ -2630,7 +2632,7 @@ func TestStakingPlugin_SlashCandidates(t *testing.T) {
 
 	// Double sign penalty
 	sla := new(big.Int).Div(slash2.Released, big.NewInt(10))
-	caller := common.MustStringToAddress("0xuj3zd9yz00axz7ls88ynwsp3jprhjd9ldx9qpm")
+	caller := common.MustStringToAddress("0x6Fe7C4f7a1b3E9dA1f0c9B4C2d5E8F7a6B5c4D3e")
 	slashItem2 := &staking.SlashNodeItem{
 		NodeId:      slash2.NodeId,
 		Amount:      sla,
@@ -3571,7 +3573,7 @@ func TestStakingPlugin_ProbabilityElection(t *testing.T) {
 
 	newChainState()
 
-	curve := elliptic.P256()
+	curve := crypto.S256()
 	vqList := make(staking.ValidatorQueue, 0)
 	preNonces := make([][]byte, 0)
 	currentNonce := crypto.Keccak256([]byte(string("nonce")))
@@ -3621,7 +3623,7 @@ func TestStakingPlugin_ProbabilityElectionDifferentWeights(t *testing.T) {
 
 	newChainState()
 
-	curve := elliptic.P256()
+	curve := crypto.S256()
 
 	currentNonce := crypto.Keccak256([]byte("nonce"))
 
@@ -3705,7 +3707,7 @@ func TestStakingPlugin_RandomOrderValidatorQueue(t *testing.T) {
 		data = vrf.ProofToHash(vrfData)
 		dataList = append(dataList, data)
 
-		tempPrivateKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		tempPrivateKey, _ := ecdsa.GenerateKey(crypto.S256(), rand.Reader)
 		nodeId := discover.PubkeyID(&tempPrivateKey.PublicKey)
 		addr := crypto.PubkeyToNodeAddress(tempPrivateKey.PublicKey)
 		v := &staking.Validator{
@@ -1,4 +1,4 @@
-// +build testpackage xcom
+// +build test
 
 package xcom
 